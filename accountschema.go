@@ -0,0 +1,253 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"fmt"
+	"reflect"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// accountSpec describes the account an instruction expects at a position.
+type accountSpec struct {
+	Name     string
+	Writable bool
+	Signer   bool
+	// Multisig marks an authority that may be a multisig, which does not
+	// sign itself and is followed by the signing members. Only the last
+	// account of a schema may be one.
+	Multisig bool
+}
+
+// accountSchema describes the accounts of an instruction in the order the
+// program reads them.
+type accountSchema struct {
+	Accounts []accountSpec
+	// Rest describes the accounts following Accounts and the multisig
+	// signers, when the program constrains them.
+	Rest *accountSpec
+}
+
+func readonly(name string) accountSpec { return accountSpec{Name: name} }
+
+func writable(name string) accountSpec { return accountSpec{Name: name, Writable: true} }
+
+func payer(name string) accountSpec { return accountSpec{Name: name, Writable: true, Signer: true} }
+
+func authority(name string) accountSpec { return accountSpec{Name: name, Signer: true, Multisig: true} }
+
+// accountSchemas maps the discriminators of the Token-2022 builders to
+// the accounts the program expects, as the SPL instruction documentation
+// lists them.
+var accountSchemas = map[Discriminator]accountSchema{
+	NewDiscriminator(InstructionTransfer):                 {Accounts: []accountSpec{writable("Source"), writable("Destination"), authority("Owner")}},
+	NewDiscriminator(InstructionTransferChecked):          {Accounts: []accountSpec{writable("Source"), readonly("Mint"), writable("Destination"), authority("Owner")}},
+	NewDiscriminator(InstructionApprove):                  {Accounts: []accountSpec{writable("Source"), readonly("Delegate"), authority("Owner")}},
+	NewDiscriminator(InstructionApproveChecked):           {Accounts: []accountSpec{writable("Source"), readonly("Mint"), readonly("Delegate"), authority("Owner")}},
+	NewDiscriminator(InstructionRevoke):                   {Accounts: []accountSpec{writable("Source"), authority("Owner")}},
+	NewDiscriminator(InstructionSetAuthority):             {Accounts: []accountSpec{writable("Account"), authority("Authority")}},
+	NewDiscriminator(InstructionMintTo):                   {Accounts: []accountSpec{writable("Mint"), writable("Destination"), authority("MintAuthority")}},
+	NewDiscriminator(InstructionMintToChecked):            {Accounts: []accountSpec{writable("Mint"), writable("Destination"), authority("MintAuthority")}},
+	NewDiscriminator(InstructionBurn):                     {Accounts: []accountSpec{writable("Account"), writable("Mint"), authority("Owner")}},
+	NewDiscriminator(InstructionBurnChecked):              {Accounts: []accountSpec{writable("Account"), writable("Mint"), authority("Owner")}},
+	NewDiscriminator(InstructionCloseAccount):             {Accounts: []accountSpec{writable("Account"), writable("Destination"), authority("Owner")}},
+	NewDiscriminator(InstructionWithdrawExcessLamports):   {Accounts: []accountSpec{writable("Source"), writable("Destination"), authority("Authority")}},
+	NewDiscriminator(InstructionFreezeAccount):            {Accounts: []accountSpec{writable("Account"), readonly("Mint"), authority("FreezeAuthority")}},
+	NewDiscriminator(InstructionThawAccount):              {Accounts: []accountSpec{writable("Account"), readonly("Mint"), authority("FreezeAuthority")}},
+	NewDiscriminator(InstructionInitializeMint):           {Accounts: []accountSpec{writable("Mint"), readonly("Rent")}},
+	NewDiscriminator(InstructionInitializeAccount):        {Accounts: []accountSpec{writable("Account"), readonly("Mint"), readonly("Owner"), readonly("Rent")}},
+	NewDiscriminator(InstructionInitializeAccount2):       {Accounts: []accountSpec{writable("Account"), readonly("Mint"), readonly("Rent")}},
+	NewDiscriminator(InstructionInitializeMultisig):       {Accounts: []accountSpec{writable("Multisig"), readonly("Rent")}},
+	NewDiscriminator(InstructionInitializeMint2):          {Accounts: []accountSpec{writable("Mint")}},
+	NewDiscriminator(InstructionInitializeAccount3):       {Accounts: []accountSpec{writable("Account"), readonly("Mint")}},
+	NewDiscriminator(InstructionInitializeMultisig2):      {Accounts: []accountSpec{writable("Multisig")}},
+	NewDiscriminator(InstructionInitializeImmutableOwner): {Accounts: []accountSpec{writable("Account")}},
+	NewDiscriminator(InstructionSyncNative):               {Accounts: []accountSpec{writable("Account")}},
+	NewDiscriminator(InstructionCreateNativeMint):         {Accounts: []accountSpec{payer("Payer"), writable("NativeMint"), readonly("SystemProgram")}},
+	NewDiscriminator(InstructionGetAccountDataSize):       {Accounts: []accountSpec{readonly("Mint")}},
+	NewDiscriminator(InstructionAmountToUiAmount):         {Accounts: []accountSpec{readonly("Mint")}},
+	NewDiscriminator(InstructionUiAmountToAmount):         {Accounts: []accountSpec{readonly("Mint")}},
+	NewDiscriminator(InstructionReallocate): {
+		Accounts: []accountSpec{writable("Account"), payer("Payer"), readonly("SystemProgram"), authority("Owner")},
+	},
+	NewDiscriminator(InstructionInitializeMintCloseAuthority):  {Accounts: []accountSpec{writable("Mint")}},
+	NewDiscriminator(InstructionInitializeNonTransferableMint): {Accounts: []accountSpec{writable("Mint")}},
+	NewDiscriminator(InstructionInitializePermanentDelegate):   {Accounts: []accountSpec{writable("Mint")}},
+
+	NewExtensionDiscriminator(InstructionTransferFeeExtension, TransferFeeInstructionInitializeConfig): {
+		Accounts: []accountSpec{writable("Mint")},
+	},
+	NewExtensionDiscriminator(InstructionTransferFeeExtension, TransferFeeInstructionTransferCheckedWithFee): {
+		Accounts: []accountSpec{writable("Source"), readonly("Mint"), writable("Destination"), authority("Owner")},
+	},
+	NewExtensionDiscriminator(InstructionTransferFeeExtension, TransferFeeInstructionWithdrawWithheldTokensFromMint): {
+		Accounts: []accountSpec{writable("Mint"), writable("Destination"), authority("WithdrawWithheldAuthority")},
+	},
+	NewExtensionDiscriminator(InstructionTransferFeeExtension, TransferFeeInstructionWithdrawWithheldTokensFromAccounts): {
+		Accounts: []accountSpec{readonly("Mint"), writable("Destination"), authority("WithdrawWithheldAuthority")},
+		Rest:     &accountSpec{Name: "Sources", Writable: true},
+	},
+	NewExtensionDiscriminator(InstructionTransferFeeExtension, TransferFeeInstructionHarvestWithheldTokensToMint): {
+		Accounts: []accountSpec{writable("Mint")},
+		Rest:     &accountSpec{Name: "Sources", Writable: true},
+	},
+	NewExtensionDiscriminator(InstructionTransferFeeExtension, TransferFeeInstructionSetTransferFee): {
+		Accounts: []accountSpec{writable("Mint"), authority("TransferFeeConfigAuthority")},
+	},
+
+	NewExtensionDiscriminator(InstructionDefaultAccountStateExtension, DefaultAccountStateInstructionInitialize): {
+		Accounts: []accountSpec{writable("Mint")},
+	},
+	NewExtensionDiscriminator(InstructionDefaultAccountStateExtension, DefaultAccountStateInstructionUpdate): {
+		Accounts: []accountSpec{writable("Mint"), authority("FreezeAuthority")},
+	},
+	NewExtensionDiscriminator(InstructionMemoTransferExtension, ToggleInstructionEnable): {
+		Accounts: []accountSpec{writable("Account"), authority("Owner")},
+	},
+	NewExtensionDiscriminator(InstructionMemoTransferExtension, ToggleInstructionDisable): {
+		Accounts: []accountSpec{writable("Account"), authority("Owner")},
+	},
+	NewExtensionDiscriminator(InstructionCpiGuardExtension, ToggleInstructionEnable): {
+		Accounts: []accountSpec{writable("Account"), authority("Owner")},
+	},
+	NewExtensionDiscriminator(InstructionCpiGuardExtension, ToggleInstructionDisable): {
+		Accounts: []accountSpec{writable("Account"), authority("Owner")},
+	},
+	NewExtensionDiscriminator(InstructionInterestBearingMintExtension, ExtensionInstructionInitialize): {
+		Accounts: []accountSpec{writable("Mint")},
+	},
+	NewExtensionDiscriminator(InstructionInterestBearingMintExtension, ExtensionInstructionUpdate): {
+		Accounts: []accountSpec{writable("Mint"), authority("RateAuthority")},
+	},
+	NewExtensionDiscriminator(InstructionTransferHookExtension, ExtensionInstructionInitialize): {
+		Accounts: []accountSpec{writable("Mint")},
+	},
+	NewExtensionDiscriminator(InstructionTransferHookExtension, ExtensionInstructionUpdate): {
+		Accounts: []accountSpec{writable("Mint"), authority("Authority")},
+	},
+	NewExtensionDiscriminator(InstructionMetadataPointerExtension, ExtensionInstructionInitialize): {
+		Accounts: []accountSpec{writable("Mint")},
+	},
+	NewExtensionDiscriminator(InstructionMetadataPointerExtension, ExtensionInstructionUpdate): {
+		Accounts: []accountSpec{writable("Mint"), authority("Authority")},
+	},
+	NewExtensionDiscriminator(InstructionGroupPointerExtension, ExtensionInstructionInitialize): {
+		Accounts: []accountSpec{writable("Mint")},
+	},
+	NewExtensionDiscriminator(InstructionGroupPointerExtension, ExtensionInstructionUpdate): {
+		Accounts: []accountSpec{writable("Mint"), authority("Authority")},
+	},
+	NewExtensionDiscriminator(InstructionGroupMemberPointerExtension, ExtensionInstructionInitialize): {
+		Accounts: []accountSpec{writable("Mint")},
+	},
+	NewExtensionDiscriminator(InstructionGroupMemberPointerExtension, ExtensionInstructionUpdate): {
+		Accounts: []accountSpec{writable("Mint"), authority("Authority")},
+	},
+	NewExtensionDiscriminator(InstructionScaledUiAmountExtension, ExtensionInstructionInitialize): {
+		Accounts: []accountSpec{writable("Mint")},
+	},
+	NewExtensionDiscriminator(InstructionScaledUiAmountExtension, ExtensionInstructionUpdate): {
+		Accounts: []accountSpec{writable("Mint"), authority("Authority")},
+	},
+	NewExtensionDiscriminator(InstructionPausableExtension, PausableInstructionInitialize): {
+		Accounts: []accountSpec{writable("Mint")},
+	},
+	NewExtensionDiscriminator(InstructionPausableExtension, PausableInstructionPause): {
+		Accounts: []accountSpec{writable("Mint"), authority("Authority")},
+	},
+	NewExtensionDiscriminator(InstructionPausableExtension, PausableInstructionResume): {
+		Accounts: []accountSpec{writable("Mint"), authority("Authority")},
+	},
+}
+
+// createAccountSchema describes the accounts of the associated token
+// account program's Create and CreateIdempotent. The rent sysvar that
+// follows them is only read by older versions of the program.
+var createAccountSchema = accountSchema{
+	Accounts: []accountSpec{
+		payer("Payer"), writable("AssociatedTokenAccount"), readonly("Wallet"), readonly("TokenMint"),
+		readonly("SystemProgram"), readonly("TokenProgram"),
+	},
+}
+
+// checkAccounts checks the accounts of a Token-2022 builder against the
+// schema of its instruction. Builders only hold accounts after
+// SetAccounts, such as when decoded; Build derives them from the fields.
+func checkAccounts(impl interface{}, accounts []*solana.AccountMeta) error {
+	if len(accounts) == 0 {
+		return nil
+	}
+	name := reflect.Indirect(reflect.ValueOf(impl)).Type().Name()
+	discriminator, err := discriminatorOf(impl)
+	if err != nil {
+		return err
+	}
+	schema, ok := accountSchemas[discriminator]
+	if !ok {
+		return fmt.Errorf("%s: no account schema for discriminator %v", name, discriminator)
+	}
+	return schema.check(name, accounts)
+}
+
+// check checks that accounts has the accounts of the schema with at least
+// the privileges the program requires. No accounts pass, as builders only
+// hold them after SetAccounts. Extra privileges are accepted, as
+// the accounts of a compiled transaction carry the union of the
+// privileges of all its instructions.
+func (s accountSchema) check(name string, accounts []*solana.AccountMeta) error {
+	if len(accounts) == 0 {
+		return nil
+	}
+	if err := checkAccountCount(name, accounts, len(s.Accounts)); err != nil {
+		return err
+	}
+	for i, spec := range s.Accounts {
+		account := accounts[i]
+		if spec.Writable && !account.IsWritable {
+			return errInvalidAccountMeta(name, i, spec.Name, "must be writable")
+		}
+		if spec.Multisig {
+			n := i + 1
+			for n < len(accounts) && accounts[n].IsSigner {
+				n++
+			}
+			if !account.IsSigner && n == i+1 {
+				return errInvalidAccountMeta(name, i, spec.Name, "must sign or be followed by its multisig signers")
+			}
+			return s.checkRest(name, accounts, n)
+		}
+		if spec.Signer && !account.IsSigner {
+			return errInvalidAccountMeta(name, i, spec.Name, "must sign")
+		}
+	}
+	return s.checkRest(name, accounts, len(s.Accounts))
+}
+
+// checkRest checks the accounts from index from on against Rest.
+func (s accountSchema) checkRest(name string, accounts []*solana.AccountMeta, from int) error {
+	if s.Rest == nil {
+		return nil
+	}
+	for i := from; i < len(accounts); i++ {
+		if s.Rest.Writable && !accounts[i].IsWritable {
+			return errInvalidAccountMeta(name, i, s.Rest.Name, "must be writable")
+		}
+		if s.Rest.Signer && !accounts[i].IsSigner {
+			return errInvalidAccountMeta(name, i, s.Rest.Name, "must sign")
+		}
+	}
+	return nil
+}
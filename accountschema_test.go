@@ -0,0 +1,173 @@
+package token2022
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+func TestAccountSchemasCoverRegistry(t *testing.T) {
+
+	check := func(newInstruction func() TypedInstruction) {
+		inst := newInstruction()
+		discriminator, err := discriminatorOf(inst)
+		if err != nil {
+			t.Fatalf("%T: %v", inst, err)
+		}
+		schema, ok := accountSchemas[discriminator]
+		if !ok {
+			t.Errorf("%T: no account schema", inst)
+			return
+		}
+		for i, spec := range schema.Accounts {
+			if spec.Multisig && i != len(schema.Accounts)-1 {
+				t.Errorf("%T: multisig authority %s is not the last account", inst, spec.Name)
+			}
+		}
+	}
+	for _, newInstruction := range instructionRegistry {
+		check(newInstruction)
+	}
+	for _, subRegistry := range extensionInstructionRegistry {
+		for _, newInstruction := range subRegistry {
+			check(newInstruction)
+		}
+	}
+}
+
+// TestGoldenAccountSchemas checks the schemas against the accounts of the
+// golden instruction vectors: the privileges must be exactly those of the
+// schema, and dropping any of them must fail the check.
+func TestGoldenAccountSchemas(t *testing.T) {
+	var vectors []goldenInstruction
+	loadGolden(t, "instructions.json", &vectors)
+	for i, vector := range vectors {
+		label := fmt.Sprintf("%d %s", i, vector.Name)
+		data, err := hex.DecodeString(vector.Data)
+		if err != nil {
+			t.Fatalf("%s: %v", label, err)
+		}
+		discriminator, err := ParseDiscriminator(data)
+		if err != nil {
+			t.Fatalf("%s: %v", label, err)
+		}
+		schema := accountSchemas[discriminator]
+		accounts := make([]*solana.AccountMeta, len(vector.Accounts))
+		for j, meta := range vector.Accounts {
+			accounts[j] = &solana.AccountMeta{PublicKey: meta.PublicKey, IsSigner: meta.IsSigner, IsWritable: meta.IsWritable}
+		}
+
+		for j, spec := range schema.Accounts {
+			if j >= len(accounts) {
+				t.Errorf("%s: missing account %s", label, spec.Name)
+				break
+			}
+			if accounts[j].IsWritable != spec.Writable {
+				t.Errorf("%s: %s is writable %v in the schema, %v in the vector", label, spec.Name, spec.Writable, accounts[j].IsWritable)
+			}
+			if !spec.Multisig && accounts[j].IsSigner != spec.Signer {
+				t.Errorf("%s: %s is signer %v in the schema, %v in the vector", label, spec.Name, spec.Signer, accounts[j].IsSigner)
+			}
+		}
+		if err := checkDecodedAccounts(accounts, data); err != nil {
+			t.Errorf("%s: %v", label, err)
+		}
+
+		for j := range schema.Accounts {
+			for _, flag := range []string{"writable", "signer"} {
+				demoted := make([]*solana.AccountMeta, len(accounts))
+				for k, meta := range accounts {
+					copied := *meta
+					demoted[k] = &copied
+				}
+				switch {
+				case flag == "writable" && demoted[j].IsWritable:
+					demoted[j].IsWritable = false
+				case flag == "signer" && demoted[j].IsSigner:
+					demoted[j].IsSigner = false
+				default:
+					continue
+				}
+				if err := checkDecodedAccounts(demoted, data); !errors.Is(err, ErrInvalidAccountMeta) {
+					t.Errorf("%s: %s not %s: expected ErrInvalidAccountMeta, got %v", label, schema.Accounts[j].Name, flag, err)
+				}
+			}
+		}
+	}
+}
+
+// checkDecodedAccounts checks the accounts of decoded data against the
+// schema, skipping the checks of Validate on the fields.
+func checkDecodedAccounts(accounts []*solana.AccountMeta, data []byte) error {
+	decoded, err := DecodeInstruction(accounts, data)
+	if err != nil {
+		return err
+	}
+	return checkAccounts(decoded, decoded.GetAccounts())
+}
+
+func validateDecoded(accounts []*solana.AccountMeta, data []byte) error {
+	decoded, err := DecodeInstruction(accounts, data)
+	if err != nil {
+		return err
+	}
+	return decoded.(interface{ Validate() error }).Validate()
+}
+
+func TestValidateDecodedMultisigAuthority(t *testing.T) {
+
+	var (
+		source      = solana.NewWallet().PublicKey()
+		destination = solana.NewWallet().PublicKey()
+		multisig    = solana.NewWallet().PublicKey()
+		signer      = solana.NewWallet().PublicKey()
+	)
+	inst := NewTransfer2022Instruction(1, source, destination, multisig, signer).Build()
+	data, err := inst.Data()
+	if err != nil {
+		t.Fatalf("Error getting data: %v", err)
+	}
+	if err := validateDecoded(inst.Accounts(), data); err != nil {
+		t.Errorf("Expected the multisig transfer to validate, got %v", err)
+	}
+
+	accounts := inst.Accounts()
+	accounts[3] = &solana.AccountMeta{PublicKey: signer}
+	err = validateDecoded(accounts, data)
+	var metaErr *AccountMetaError
+	if !errors.As(err, &metaErr) {
+		t.Fatalf("Expected an AccountMetaError, got %v", err)
+	}
+	if metaErr.Index != 2 || metaErr.Account != "Owner" {
+		t.Errorf("Expected account 2 (Owner), got %d (%s)", metaErr.Index, metaErr.Account)
+	}
+
+	// A fee payer that also owns the source is writable: extra privileges
+	// are accepted.
+	accounts = NewTransfer2022Instruction(1, source, destination, signer).Build().Accounts()
+	accounts[2].IsWritable = true
+	if err := validateDecoded(accounts, data); err != nil {
+		t.Errorf("Expected extra privileges to validate, got %v", err)
+	}
+}
+
+func TestValidateDecodedCreate(t *testing.T) {
+
+	inst := NewCreate2022Instruction(solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()).Build()
+	data, err := inst.Data()
+	if err != nil {
+		t.Fatalf("Error getting data: %v", err)
+	}
+	accounts := inst.Accounts()
+	accounts[1].IsWritable = false
+	decoded, err := DecodeAssociatedTokenInstruction(accounts, data)
+	if err != nil {
+		t.Fatalf("Error decoding: %v", err)
+	}
+	if err := decoded.(*Create2022).Validate(); !errors.Is(err, ErrInvalidAccountMeta) {
+		t.Errorf("Expected ErrInvalidAccountMeta, got %v", err)
+	}
+}
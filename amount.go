@@ -0,0 +1,256 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+	"strings"
+)
+
+// ParseAmount parses a decimal amount such as "1.5" into raw units of a
+// mint with decimals, without going through floating point. It rejects
+// signs, exponents, digit separators and surrounding spaces, amounts with
+// more than decimals decimal places, and amounts that overflow a u64.
+func ParseAmount(value string, decimals uint8) (uint64, error) {
+	if strings.HasPrefix(value, "-") {
+		return 0, fmt.Errorf("negative amount %q", value)
+	}
+	whole, fraction, hasPoint := strings.Cut(value, ".")
+	if whole == "" && fraction == "" || hasPoint && fraction == "" {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	if len(fraction) > int(decimals) {
+		return 0, fmt.Errorf("amount %q has more than %d decimal places", value, decimals)
+	}
+	var amount uint64
+	for _, c := range whole + fraction + strings.Repeat("0", int(decimals)-len(fraction)) {
+		if c < '0' || c > '9' {
+			return 0, fmt.Errorf("invalid amount %q", value)
+		}
+		if amount > (math.MaxUint64-uint64(c-'0'))/10 {
+			return 0, fmt.Errorf("amount %q overflows u64: %w", value, ErrOverflow)
+		}
+		amount = amount*10 + uint64(c-'0')
+	}
+	return amount, nil
+}
+
+// FormatAmount formats a raw amount of a mint with decimals as a decimal
+// string without trailing zeros, such as "1.5". It is the inverse of
+// ParseAmount.
+func FormatAmount(amount uint64, decimals uint8) string {
+	s := fmt.Sprintf("%0*d", int(decimals)+1, amount)
+	if decimals == 0 {
+		return s
+	}
+	whole, frac := s[:len(s)-int(decimals)], strings.TrimRight(s[len(s)-int(decimals):], "0")
+	if frac == "" {
+		return whole
+	}
+	return whole + "." + frac
+}
+
+// AmountFormat renders raw amounts for display, such as "1,250.50 USDC".
+// The output depends only on its fields, never on the process locale.
+type AmountFormat struct {
+	// GroupSeparator separates groups of three digits in the whole part.
+	// Grouping is disabled when empty.
+	GroupSeparator string
+	// DecimalSeparator defaults to ".".
+	DecimalSeparator string
+	// MinDecimals pads the fraction with zeros to at least this many
+	// places, up to the mint's decimals.
+	MinDecimals int
+	// MaxDecimals, when positive, truncates the fraction to this many
+	// places, rounding toward zero so that a balance is never overstated.
+	MaxDecimals int
+	// SymbolFirst puts the symbol before the amount.
+	SymbolFirst bool
+	// SymbolSeparator goes between the amount and the symbol. It defaults
+	// to a space.
+	SymbolSeparator *string
+}
+
+// DefaultAmountFormat groups thousands with commas and shows the symbol
+// after the amount, as in "1,250.5 USDC".
+var DefaultAmountFormat = AmountFormat{GroupSeparator: ","}
+
+// Format formats a raw amount of a mint with decimals, followed by symbol
+// unless it is empty.
+func (f AmountFormat) Format(amount uint64, decimals uint8, symbol string) string {
+	formatted := FormatAmount(amount, decimals)
+	whole, fraction, _ := strings.Cut(formatted, ".")
+	if f.MaxDecimals > 0 && len(fraction) > f.MaxDecimals {
+		fraction = strings.TrimRight(fraction[:f.MaxDecimals], "0")
+	}
+	if minDecimals := min(f.MinDecimals, int(decimals)); len(fraction) < minDecimals {
+		fraction += strings.Repeat("0", minDecimals-len(fraction))
+	}
+
+	var b strings.Builder
+	if symbol != "" && f.SymbolFirst {
+		b.WriteString(symbol)
+		b.WriteString(f.symbolSeparator())
+	}
+	for i, c := range whole {
+		if i > 0 && f.GroupSeparator != "" && (len(whole)-i)%3 == 0 {
+			b.WriteString(f.GroupSeparator)
+		}
+		b.WriteRune(c)
+	}
+	if fraction != "" {
+		if f.DecimalSeparator == "" {
+			b.WriteString(".")
+		} else {
+			b.WriteString(f.DecimalSeparator)
+		}
+		b.WriteString(fraction)
+	}
+	if symbol != "" && !f.SymbolFirst {
+		b.WriteString(f.symbolSeparator())
+		b.WriteString(symbol)
+	}
+	return b.String()
+}
+
+func (f AmountFormat) symbolSeparator() string {
+	if f.SymbolSeparator == nil {
+		return " "
+	}
+	return *f.SymbolSeparator
+}
+
+// FormatMint formats a raw amount of mint with its decimals and the symbol
+// of its TokenMetadata extension. Mints without the extension, such as
+// mints described by Metaplex metadata, are formatted without a symbol;
+// Format takes the symbol from another source.
+func (f AmountFormat) FormatMint(amount uint64, mint *Mint) (string, error) {
+	metadata, _, err := mint.TokenMetadata()
+	if err != nil {
+		return "", fmt.Errorf("error while decoding token metadata: %w", err)
+	}
+	var symbol string
+	if metadata != nil {
+		symbol = metadata.Symbol
+	}
+	return f.Format(amount, mint.Decimals, symbol), nil
+}
+
+// CheckedAdd returns a + b. Like the other checked helpers, it returns
+// ErrOverflow, the program's error for out-of-range arithmetic, when the
+// result does not fit in a u64.
+func CheckedAdd(a, b uint64) (uint64, error) {
+	sum, carry := bits.Add64(a, b, 0)
+	if carry != 0 {
+		return 0, ErrOverflow
+	}
+	return sum, nil
+}
+
+// CheckedSub returns a - b, or ErrOverflow when b is larger than a.
+func CheckedSub(a, b uint64) (uint64, error) {
+	difference, borrow := bits.Sub64(a, b, 0)
+	if borrow != 0 {
+		return 0, ErrOverflow
+	}
+	return difference, nil
+}
+
+// CheckedMul returns a * b, or ErrOverflow.
+func CheckedMul(a, b uint64) (uint64, error) {
+	hi, lo := bits.Mul64(a, b)
+	if hi != 0 {
+		return 0, ErrOverflow
+	}
+	return lo, nil
+}
+
+// AmountToBig returns amount as a big.Int.
+func AmountToBig(amount uint64) *big.Int {
+	return new(big.Int).SetUint64(amount)
+}
+
+// AmountFromBig converts v to a raw amount. It returns ErrOverflow for
+// negative values and values above the u64 range.
+func AmountFromBig(v *big.Int) (uint64, error) {
+	if !v.IsUint64() {
+		return 0, ErrOverflow
+	}
+	return v.Uint64(), nil
+}
+
+// AmountToRat returns the UI value of a raw amount of a mint with decimals,
+// amount / 10^decimals, exactly.
+func AmountToRat(amount uint64, decimals uint8) *big.Rat {
+	return new(big.Rat).SetFrac(AmountToBig(amount), pow10(int(decimals)))
+}
+
+// AmountFromRat converts a UI value back to raw units of a mint with
+// decimals. It fails when the value has more precision than the mint or
+// is out of range.
+func AmountFromRat(v *big.Rat, decimals uint8) (uint64, error) {
+	raw := new(big.Rat).Mul(v, new(big.Rat).SetInt(pow10(int(decimals))))
+	if !raw.IsInt() {
+		return 0, fmt.Errorf("amount %s has more than %d decimal places", v.RatString(), decimals)
+	}
+	return AmountFromBig(raw.Num())
+}
+
+// AmountToDecimal returns the UI value of a raw amount of a mint with
+// decimals as a coefficient and a base-10 exponent, the representation of
+// decimal libraries such as github.com/shopspring/decimal:
+//
+//	value := decimal.NewFromBigInt(token2022.AmountToDecimal(amount, 6))
+func AmountToDecimal(amount uint64, decimals uint8) (*big.Int, int32) {
+	return AmountToBig(amount), -int32(decimals)
+}
+
+// AmountFromDecimal converts a value given as coefficient * 10^exponent,
+// such as decimal.Decimal's Coefficient and Exponent, to raw units of a
+// mint with decimals. It fails when the value has more precision than the
+// mint or is out of range.
+func AmountFromDecimal(coefficient *big.Int, exponent int32, decimals uint8) (uint64, error) {
+	shift := int(exponent) + int(decimals)
+	if coefficient.Sign() == 0 {
+		return 0, nil
+	}
+	if shift > maxAmountDigits {
+		// Any non-zero coefficient times 10^20 is out of range, which is
+		// cheaper to tell than to compute for a large exponent.
+		return 0, ErrOverflow
+	}
+	if shift >= 0 {
+		return AmountFromBig(new(big.Int).Mul(coefficient, pow10(shift)))
+	}
+	if -shift > len(new(big.Int).Abs(coefficient).Text(10)) {
+		// 10^-shift exceeds the coefficient, which is then all remainder.
+		return 0, fmt.Errorf("amount %se%d has more than %d decimal places", coefficient, exponent, decimals)
+	}
+	raw, remainder := new(big.Int).QuoRem(coefficient, pow10(-shift), new(big.Int))
+	if remainder.Sign() != 0 {
+		return 0, fmt.Errorf("amount %se%d has more than %d decimal places", coefficient, exponent, decimals)
+	}
+	return AmountFromBig(raw)
+}
+
+// maxAmountDigits is the largest power of ten that fits in a u64.
+const maxAmountDigits = 19
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
@@ -0,0 +1,413 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	solana "github.com/gagliardetto/solana-go"
+	format "github.com/gagliardetto/solana-go/text/format"
+	treeout "github.com/gagliardetto/treeout"
+)
+
+// GetAccountDataSize2022 returns, as return data, the size of a token account for the
+// mint with the given extensions.
+type GetAccountDataSize2022 struct {
+	// The account extensions to size the account for.
+	ExtensionTypes []ExtensionType
+
+	Mint solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [] Mint
+	// ··········· The token mint
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewGetAccountDataSize2022InstructionBuilder creates a new `GetAccountDataSize2022` instruction builder.
+func NewGetAccountDataSize2022InstructionBuilder() *GetAccountDataSize2022 {
+	nd := &GetAccountDataSize2022{}
+	return nd
+}
+
+func (inst *GetAccountDataSize2022) SetExtensionTypes(extensionTypes []ExtensionType) *GetAccountDataSize2022 {
+	inst.ExtensionTypes = extensionTypes
+	return inst
+}
+
+func (inst *GetAccountDataSize2022) SetMint(mint solana.PublicKey) *GetAccountDataSize2022 {
+	inst.Mint = mint
+	return inst
+}
+
+func (inst GetAccountDataSize2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Mint,
+			IsSigner:   false,
+			IsWritable: false,
+		},
+	}
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst GetAccountDataSize2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *GetAccountDataSize2022) Validate() error {
+	if inst.Mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+func (inst *GetAccountDataSize2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("GetAccountDataSize2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=1]").ParentFunc(func(paramsBranch treeout.Branches) {
+						paramsBranch.Child(format.Param("ExtensionTypes", inst.ExtensionTypes))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("mint", inst.AccountMetaSlice.Get(0)))
+					})
+				})
+		})
+}
+
+func (inst GetAccountDataSize2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	return writeExtensionTypes(encoder, inst.ExtensionTypes)
+}
+
+func (inst *GetAccountDataSize2022) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	if inst.ExtensionTypes, err = readExtensionTypes(decoder); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst GetAccountDataSize2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *GetAccountDataSize2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("GetAccountDataSize2022", accounts, 1); err != nil {
+		return err
+	}
+	inst.Mint = accounts[0].PublicKey
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst GetAccountDataSize2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *GetAccountDataSize2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewGetAccountDataSize2022Instruction creates a new `GetAccountDataSize2022` instruction.
+func NewGetAccountDataSize2022Instruction(
+	extensionTypes []ExtensionType,
+	mint solana.PublicKey,
+) *GetAccountDataSize2022 {
+	return NewGetAccountDataSize2022InstructionBuilder().
+		SetExtensionTypes(extensionTypes).
+		SetMint(mint)
+}
+
+// AmountToUiAmount2022 returns, as return data, the UI representation of an amount
+// for the mint.
+type AmountToUiAmount2022 struct {
+	// The amount of tokens to convert.
+	Amount uint64
+
+	Mint solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [] Mint
+	// ··········· The token mint
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewAmountToUiAmount2022InstructionBuilder creates a new `AmountToUiAmount2022` instruction builder.
+func NewAmountToUiAmount2022InstructionBuilder() *AmountToUiAmount2022 {
+	nd := &AmountToUiAmount2022{}
+	return nd
+}
+
+func (inst *AmountToUiAmount2022) SetAmount(amount uint64) *AmountToUiAmount2022 {
+	inst.Amount = amount
+	return inst
+}
+
+func (inst *AmountToUiAmount2022) SetMint(mint solana.PublicKey) *AmountToUiAmount2022 {
+	inst.Mint = mint
+	return inst
+}
+
+func (inst AmountToUiAmount2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Mint,
+			IsSigner:   false,
+			IsWritable: false,
+		},
+	}
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst AmountToUiAmount2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *AmountToUiAmount2022) Validate() error {
+	if inst.Mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+func (inst *AmountToUiAmount2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("AmountToUiAmount2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=1]").ParentFunc(func(paramsBranch treeout.Branches) {
+						paramsBranch.Child(format.Param("Amount", inst.Amount))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("mint", inst.AccountMetaSlice.Get(0)))
+					})
+				})
+		})
+}
+
+func (inst AmountToUiAmount2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	return encoder.WriteUint64(inst.Amount, bin.LE)
+}
+
+func (inst *AmountToUiAmount2022) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	if inst.Amount, err = decoder.ReadUint64(bin.LE); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst AmountToUiAmount2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *AmountToUiAmount2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("AmountToUiAmount2022", accounts, 1); err != nil {
+		return err
+	}
+	inst.Mint = accounts[0].PublicKey
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst AmountToUiAmount2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *AmountToUiAmount2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewAmountToUiAmount2022Instruction creates a new `AmountToUiAmount2022` instruction.
+func NewAmountToUiAmount2022Instruction(
+	amount uint64,
+	mint solana.PublicKey,
+) *AmountToUiAmount2022 {
+	return NewAmountToUiAmount2022InstructionBuilder().
+		SetAmount(amount).
+		SetMint(mint)
+}
+
+// UiAmountToAmount2022 returns, as return data, the raw amount of a UI amount for the
+// mint.
+type UiAmountToAmount2022 struct {
+	// The UI amount to convert, such as "12.5".
+	UiAmount string
+
+	Mint solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [] Mint
+	// ··········· The token mint
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewUiAmountToAmount2022InstructionBuilder creates a new `UiAmountToAmount2022` instruction builder.
+func NewUiAmountToAmount2022InstructionBuilder() *UiAmountToAmount2022 {
+	nd := &UiAmountToAmount2022{}
+	return nd
+}
+
+func (inst *UiAmountToAmount2022) SetUiAmount(uiAmount string) *UiAmountToAmount2022 {
+	inst.UiAmount = uiAmount
+	return inst
+}
+
+func (inst *UiAmountToAmount2022) SetMint(mint solana.PublicKey) *UiAmountToAmount2022 {
+	inst.Mint = mint
+	return inst
+}
+
+func (inst UiAmountToAmount2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Mint,
+			IsSigner:   false,
+			IsWritable: false,
+		},
+	}
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst UiAmountToAmount2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *UiAmountToAmount2022) Validate() error {
+	if inst.Mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+func (inst *UiAmountToAmount2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("UiAmountToAmount2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=1]").ParentFunc(func(paramsBranch treeout.Branches) {
+						paramsBranch.Child(format.Param("UiAmount", inst.UiAmount))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("mint", inst.AccountMetaSlice.Get(0)))
+					})
+				})
+		})
+}
+
+func (inst UiAmountToAmount2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	return encoder.WriteBytes([]byte(inst.UiAmount), false)
+}
+
+func (inst *UiAmountToAmount2022) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	value, err := decoder.ReadNBytes(decoder.Remaining())
+	if err != nil {
+		return err
+	}
+	inst.UiAmount = string(value)
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst UiAmountToAmount2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *UiAmountToAmount2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("UiAmountToAmount2022", accounts, 1); err != nil {
+		return err
+	}
+	inst.Mint = accounts[0].PublicKey
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst UiAmountToAmount2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *UiAmountToAmount2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewUiAmountToAmount2022Instruction creates a new `UiAmountToAmount2022` instruction.
+func NewUiAmountToAmount2022Instruction(
+	uiAmount string,
+	mint solana.PublicKey,
+) *UiAmountToAmount2022 {
+	return NewUiAmountToAmount2022InstructionBuilder().
+		SetUiAmount(uiAmount).
+		SetMint(mint)
+}
@@ -0,0 +1,215 @@
+package token2022
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		value    string
+		decimals uint8
+		want     uint64
+		wantErr  string
+	}{
+		{value: "1.5", decimals: 6, want: 1_500_000},
+		{value: "2", decimals: 0, want: 2},
+		{value: ".25", decimals: 2, want: 25},
+		{value: "007.10", decimals: 2, want: 710},
+		{value: "0.001", decimals: 2, wantErr: "more than 2 decimal places"},
+		{value: "-1", decimals: 6, wantErr: "negative"},
+		{value: "+1", decimals: 6, wantErr: "invalid"},
+		{value: "1,5", decimals: 6, wantErr: "invalid"},
+		{value: "1e3", decimals: 6, wantErr: "invalid"},
+		{value: " 1", decimals: 6, wantErr: "invalid"},
+		{value: "1.", decimals: 6, wantErr: "invalid"},
+		{value: ".", decimals: 6, wantErr: "invalid"},
+		{value: "", decimals: 6, wantErr: "invalid"},
+		{value: "18446744073709551615", decimals: 0, want: 1<<64 - 1},
+		{value: "18446744073.709551615", decimals: 9, want: 1<<64 - 1},
+		{value: "18446744073709551616", decimals: 0, wantErr: "overflows"},
+		{value: "18446744073709551.616", decimals: 3, wantErr: "overflows"},
+	}
+	for _, tt := range tests {
+		got, err := ParseAmount(tt.value, tt.decimals)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseAmount(%q, %d): expected error containing %q, got %v", tt.value, tt.decimals, tt.wantErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseAmount(%q, %d): unexpected error %v", tt.value, tt.decimals, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseAmount(%q, %d): expected %d, got %d", tt.value, tt.decimals, tt.want, got)
+		}
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	cases := []struct {
+		amount   uint64
+		decimals uint8
+		want     string
+	}{
+		{0, 0, "0"},
+		{0, 6, "0"},
+		{1, 6, "0.000001"},
+		{12_500_000, 6, "12.5"},
+		{100, 2, "1"},
+		{18_446_744_073_709_551_615, 9, "18446744073.709551615"},
+	}
+	for _, c := range cases {
+		got := FormatAmount(c.amount, c.decimals)
+		if got != c.want {
+			t.Errorf("FormatAmount(%d, %d) = %q, want %q", c.amount, c.decimals, got, c.want)
+		}
+		if parsed, err := ParseAmount(got, c.decimals); err != nil || parsed != c.amount {
+			t.Errorf("ParseAmount(%q, %d) = %d, %v, want %d", got, c.decimals, parsed, err, c.amount)
+		}
+	}
+}
+
+// tokenMetadataExtension encodes a TokenMetadata extension without an
+// update authority or additional metadata.
+func tokenMetadataExtension(mint solana.PublicKey, name, symbol, uri string) Extension {
+	data := append(make([]byte, 32), mint.Bytes()...)
+	for _, s := range []string{name, symbol, uri} {
+		data = binary.LittleEndian.AppendUint32(data, uint32(len(s)))
+		data = append(data, s...)
+	}
+	data = binary.LittleEndian.AppendUint32(data, 0)
+	return Extension{Type: ExtensionTokenMetadata, Data: data}
+}
+
+func TestAmountFormat(t *testing.T) {
+	space, none := " ", ""
+	cases := []struct {
+		format   AmountFormat
+		amount   uint64
+		decimals uint8
+		symbol   string
+		want     string
+	}{
+		{DefaultAmountFormat, 1_250_500_000, 6, "USDC", "1,250.5 USDC"},
+		{AmountFormat{GroupSeparator: ",", MinDecimals: 2, MaxDecimals: 2}, 1_250_500_000, 6, "USDC", "1,250.50 USDC"},
+		{AmountFormat{GroupSeparator: ",", MinDecimals: 2, MaxDecimals: 2}, 1_250_509_999, 6, "USDC", "1,250.50 USDC"},
+		{AmountFormat{MaxDecimals: 2}, 1_000_001, 6, "", "1"},
+		{AmountFormat{GroupSeparator: ".", DecimalSeparator: ","}, 1_234_567_891, 3, "EUR", "1.234.567,891 EUR"},
+		{AmountFormat{GroupSeparator: space, SymbolFirst: true, SymbolSeparator: &none}, 100_000_000, 2, "$", "$1 000 000"},
+		{AmountFormat{MinDecimals: 4}, 5, 2, "", "0.05"},
+		{DefaultAmountFormat, 999, 0, "", "999"},
+		{DefaultAmountFormat, 18_446_744_073_709_551_615, 0, "", "18,446,744,073,709,551,615"},
+	}
+	for _, c := range cases {
+		if got := c.format.Format(c.amount, c.decimals, c.symbol); got != c.want {
+			t.Errorf("Format(%d, %d, %q) = %q, want %q", c.amount, c.decimals, c.symbol, got, c.want)
+		}
+	}
+
+	mint := solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	decoded, err := DecodeMint(EncodeMint(&Mint{
+		Decimals:      6,
+		IsInitialized: true,
+		Extensions:    []Extension{tokenMetadataExtension(mint, "USD Coin", "USDC", "https://example.com")},
+	}))
+	if err != nil {
+		t.Fatalf("DecodeMint: %v", err)
+	}
+	if got, err := DefaultAmountFormat.FormatMint(2_500_000, decoded); err != nil || got != "2.5 USDC" {
+		t.Errorf("FormatMint = %q, %v, want \"2.5 USDC\"", got, err)
+	}
+	if got, err := DefaultAmountFormat.FormatMint(2_500_000, &Mint{Decimals: 6}); err != nil || got != "2.5" {
+		t.Errorf("FormatMint without metadata = %q, %v, want \"2.5\"", got, err)
+	}
+}
+
+func TestCheckedMath(t *testing.T) {
+	const max = math.MaxUint64
+	if sum, err := CheckedAdd(max-1, 1); err != nil || sum != max {
+		t.Errorf("CheckedAdd(max-1, 1) = %d, %v", sum, err)
+	}
+	if _, err := CheckedAdd(max, 1); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Expected ErrOverflow, got %v", err)
+	}
+	if difference, err := CheckedSub(5, 5); err != nil || difference != 0 {
+		t.Errorf("CheckedSub(5, 5) = %d, %v", difference, err)
+	}
+	if _, err := CheckedSub(4, 5); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Expected ErrOverflow, got %v", err)
+	}
+	if product, err := CheckedMul(1<<32, 1<<31); err != nil || product != 1<<63 {
+		t.Errorf("CheckedMul(2^32, 2^31) = %d, %v", product, err)
+	}
+	if _, err := CheckedMul(1<<32, 1<<32); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Expected ErrOverflow, got %v", err)
+	}
+	if _, err := ParseAmount("18446744073709551616", 0); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Expected ParseAmount to return ErrOverflow, got %v", err)
+	}
+}
+
+func TestAmountBigConversions(t *testing.T) {
+	if got := AmountToBig(math.MaxUint64).String(); got != "18446744073709551615" {
+		t.Errorf("AmountToBig = %s", got)
+	}
+	for _, v := range []*big.Int{big.NewInt(-1), new(big.Int).Lsh(big.NewInt(1), 64)} {
+		if _, err := AmountFromBig(v); !errors.Is(err, ErrOverflow) {
+			t.Errorf("AmountFromBig(%s): expected ErrOverflow, got %v", v, err)
+		}
+	}
+
+	rat := AmountToRat(1_250_500_000, 6)
+	if rat.FloatString(2) != "1250.50" {
+		t.Errorf("AmountToRat = %s", rat.FloatString(6))
+	}
+	if amount, err := AmountFromRat(rat, 6); err != nil || amount != 1_250_500_000 {
+		t.Errorf("AmountFromRat = %d, %v", amount, err)
+	}
+	if amount, err := AmountFromRat(big.NewRat(1, 4), 2); err != nil || amount != 25 {
+		t.Errorf("AmountFromRat(1/4, 2) = %d, %v", amount, err)
+	}
+	if _, err := AmountFromRat(big.NewRat(1, 3), 9); err == nil {
+		t.Error("Expected an error for 1/3")
+	}
+
+	coefficient, exponent := AmountToDecimal(1_500_000, 6)
+	if coefficient.Int64() != 1_500_000 || exponent != -6 {
+		t.Errorf("AmountToDecimal = %s, %d", coefficient, exponent)
+	}
+	for _, c := range []struct {
+		coefficient int64
+		exponent    int32
+		want        uint64
+		wantErr     bool
+	}{
+		{15, -1, 1_500_000, false},
+		{1_500_000, -6, 1_500_000, false},
+		{150_000_000, -8, 1_500_000, false},
+		{3, 2, 300_000_000, false},
+		{1_500_001, -7, 0, true},
+		{-1, 0, 0, true},
+		{0, math.MaxInt32, 0, false},
+		{0, math.MinInt32, 0, false},
+		{1, 13, 10_000_000_000_000_000_000, false},
+		{1, 14, 0, true},
+		{1, math.MaxInt32, 0, true},
+		{1, math.MinInt32, 0, true},
+	} {
+		got, err := AmountFromDecimal(big.NewInt(c.coefficient), c.exponent, 6)
+		if (err != nil) != c.wantErr || got != c.want {
+			t.Errorf("AmountFromDecimal(%d, %d) = %d, %v, want %d", c.coefficient, c.exponent, got, err, c.want)
+		}
+	}
+	if _, err := AmountFromDecimal(big.NewInt(1), math.MaxInt32, 6); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Expected ErrOverflow for a large exponent, got %v", err)
+	}
+}
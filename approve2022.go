@@ -0,0 +1,590 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	solana "github.com/gagliardetto/solana-go"
+	format "github.com/gagliardetto/solana-go/text/format"
+	treeout "github.com/gagliardetto/treeout"
+)
+
+// Approve2022 approves a delegate to transfer up to a maximum number of tokens
+// from the source account.
+type Approve2022 struct {
+	// The amount of tokens the delegate is approved for.
+	Amount uint64
+
+	Source   solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Delegate solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Owner    solana.PublicKey `bin:"-" borsh_skip:"true"`
+	// Signers are the signers of a multisig authority.
+	Signers []solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Source
+	// ··········· The source account
+	//
+	// [1] = [] Delegate
+	// ··········· The delegate
+	//
+	// [2] = [SIGNER] Owner
+	// ··········· The source account's owner, or a multisig
+	//
+	// [3...] = [SIGNER] Signers
+	// ··········· M signer accounts when the authority is a multisig
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewApprove2022InstructionBuilder creates a new `Approve2022` instruction builder.
+func NewApprove2022InstructionBuilder() *Approve2022 {
+	nd := &Approve2022{}
+	return nd
+}
+
+func (inst *Approve2022) SetAmount(amount uint64) *Approve2022 {
+	inst.Amount = amount
+	return inst
+}
+
+func (inst *Approve2022) SetSource(source solana.PublicKey) *Approve2022 {
+	inst.Source = source
+	return inst
+}
+
+func (inst *Approve2022) SetDelegate(delegate solana.PublicKey) *Approve2022 {
+	inst.Delegate = delegate
+	return inst
+}
+
+// SetOwner sets the authority. Pass the signers when the authority is a multisig.
+func (inst *Approve2022) SetOwner(owner solana.PublicKey, multisigSigners ...solana.PublicKey) *Approve2022 {
+	inst.Owner = owner
+	inst.Signers = multisigSigners
+	return inst
+}
+
+func (inst Approve2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Source,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+		{
+			PublicKey:  inst.Delegate,
+			IsSigner:   false,
+			IsWritable: false,
+		},
+	}
+	keys = appendAuthority(keys, inst.Owner, inst.Signers)
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst Approve2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *Approve2022) Validate() error {
+	if inst.Source.IsZero() {
+		return errNotSet("Source")
+	}
+	if inst.Delegate.IsZero() {
+		return errNotSet("Delegate")
+	}
+	if inst.Owner.IsZero() {
+		return errNotSet("Owner")
+	}
+	if err := validateMultisigSigners(inst.Signers); err != nil {
+		return err
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+// ValidateOnChain validates the instruction and checks its accounts on
+// chain; see the package function ValidateOnChain.
+func (inst *Approve2022) ValidateOnChain(ctx context.Context, client RPCClient) error {
+	return ValidateOnChain(ctx, client, inst)
+}
+
+// ValidateStrict validates the instruction and checks its values against
+// each other; see the package function ValidateStrict.
+func (inst *Approve2022) ValidateStrict() error {
+	return ValidateStrict(inst)
+}
+
+func (inst *Approve2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("Approve2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=1]").ParentFunc(func(paramsBranch treeout.Branches) {
+						paramsBranch.Child(format.Param("Amount", inst.Amount))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("  source", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(format.Meta("delegate", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(format.Meta("   owner", inst.AccountMetaSlice.Get(2)))
+						encodeRemainingAccounts(accountsBranch, "signer", inst.AccountMetaSlice, 3)
+					})
+				})
+		})
+}
+
+func (inst Approve2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	return encoder.WriteUint64(inst.Amount, bin.LE)
+}
+
+func (inst *Approve2022) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	if inst.Amount, err = decoder.ReadUint64(bin.LE); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst Approve2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *Approve2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("Approve2022", accounts, 3); err != nil {
+		return err
+	}
+	inst.Source = accounts[0].PublicKey
+	inst.Delegate = accounts[1].PublicKey
+	inst.Owner = accounts[2].PublicKey
+	inst.Signers = pubkeysOf(accounts[3:])
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst Approve2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *Approve2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewApprove2022Instruction creates a new `Approve2022` instruction.
+func NewApprove2022Instruction(
+	amount uint64,
+	source solana.PublicKey,
+	delegate solana.PublicKey,
+	owner solana.PublicKey,
+	multisigSigners ...solana.PublicKey,
+) *Approve2022 {
+	return NewApprove2022InstructionBuilder().
+		SetAmount(amount).
+		SetSource(source).
+		SetDelegate(delegate).
+		SetOwner(owner, multisigSigners...)
+}
+
+// ApproveChecked2022 approves a delegate, checking the mint and the number of
+// decimals.
+type ApproveChecked2022 struct {
+	// The amount of tokens the delegate is approved for.
+	Amount uint64
+
+	// Expected number of base 10 digits to the right of the decimal place.
+	Decimals uint8
+
+	Source   solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Mint     solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Delegate solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Owner    solana.PublicKey `bin:"-" borsh_skip:"true"`
+	// Signers are the signers of a multisig authority.
+	Signers []solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Source
+	// ··········· The source account
+	//
+	// [1] = [] Mint
+	// ··········· The token mint
+	//
+	// [2] = [] Delegate
+	// ··········· The delegate
+	//
+	// [3] = [SIGNER] Owner
+	// ··········· The source account's owner, or a multisig
+	//
+	// [4...] = [SIGNER] Signers
+	// ··········· M signer accounts when the authority is a multisig
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewApproveChecked2022InstructionBuilder creates a new `ApproveChecked2022` instruction builder.
+func NewApproveChecked2022InstructionBuilder() *ApproveChecked2022 {
+	nd := &ApproveChecked2022{}
+	return nd
+}
+
+func (inst *ApproveChecked2022) SetAmount(amount uint64) *ApproveChecked2022 {
+	inst.Amount = amount
+	return inst
+}
+
+func (inst *ApproveChecked2022) SetDecimals(decimals uint8) *ApproveChecked2022 {
+	inst.Decimals = decimals
+	return inst
+}
+
+func (inst *ApproveChecked2022) SetSource(source solana.PublicKey) *ApproveChecked2022 {
+	inst.Source = source
+	return inst
+}
+
+func (inst *ApproveChecked2022) SetMint(mint solana.PublicKey) *ApproveChecked2022 {
+	inst.Mint = mint
+	return inst
+}
+
+func (inst *ApproveChecked2022) SetDelegate(delegate solana.PublicKey) *ApproveChecked2022 {
+	inst.Delegate = delegate
+	return inst
+}
+
+// SetOwner sets the authority. Pass the signers when the authority is a multisig.
+func (inst *ApproveChecked2022) SetOwner(owner solana.PublicKey, multisigSigners ...solana.PublicKey) *ApproveChecked2022 {
+	inst.Owner = owner
+	inst.Signers = multisigSigners
+	return inst
+}
+
+func (inst ApproveChecked2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Source,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+		{
+			PublicKey:  inst.Mint,
+			IsSigner:   false,
+			IsWritable: false,
+		},
+		{
+			PublicKey:  inst.Delegate,
+			IsSigner:   false,
+			IsWritable: false,
+		},
+	}
+	keys = appendAuthority(keys, inst.Owner, inst.Signers)
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst ApproveChecked2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *ApproveChecked2022) Validate() error {
+	if inst.Source.IsZero() {
+		return errNotSet("Source")
+	}
+	if inst.Mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	if inst.Delegate.IsZero() {
+		return errNotSet("Delegate")
+	}
+	if inst.Owner.IsZero() {
+		return errNotSet("Owner")
+	}
+	if err := validateMultisigSigners(inst.Signers); err != nil {
+		return err
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+// ValidateOnChain validates the instruction and checks its accounts on
+// chain; see the package function ValidateOnChain.
+func (inst *ApproveChecked2022) ValidateOnChain(ctx context.Context, client RPCClient) error {
+	return ValidateOnChain(ctx, client, inst)
+}
+
+// ValidateStrict validates the instruction and checks its values against
+// each other; see the package function ValidateStrict.
+func (inst *ApproveChecked2022) ValidateStrict() error {
+	return ValidateStrict(inst)
+}
+
+func (inst *ApproveChecked2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("ApproveChecked2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=2]").ParentFunc(func(paramsBranch treeout.Branches) {
+						paramsBranch.Child(format.Param("  Amount", inst.Amount))
+						paramsBranch.Child(format.Param("Decimals", inst.Decimals))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("  source", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(format.Meta("    mint", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(format.Meta("delegate", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(format.Meta("   owner", inst.AccountMetaSlice.Get(3)))
+						encodeRemainingAccounts(accountsBranch, "signer", inst.AccountMetaSlice, 4)
+					})
+				})
+		})
+}
+
+func (inst ApproveChecked2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	if err := encoder.WriteUint64(inst.Amount, bin.LE); err != nil {
+		return err
+	}
+	return encoder.WriteUint8(inst.Decimals)
+}
+
+func (inst *ApproveChecked2022) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	if inst.Amount, err = decoder.ReadUint64(bin.LE); err != nil {
+		return err
+	}
+	if inst.Decimals, err = decoder.ReadUint8(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst ApproveChecked2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *ApproveChecked2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("ApproveChecked2022", accounts, 4); err != nil {
+		return err
+	}
+	inst.Source = accounts[0].PublicKey
+	inst.Mint = accounts[1].PublicKey
+	inst.Delegate = accounts[2].PublicKey
+	inst.Owner = accounts[3].PublicKey
+	inst.Signers = pubkeysOf(accounts[4:])
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst ApproveChecked2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *ApproveChecked2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewApproveChecked2022Instruction creates a new `ApproveChecked2022` instruction.
+func NewApproveChecked2022Instruction(
+	amount uint64,
+	decimals uint8,
+	source solana.PublicKey,
+	mint solana.PublicKey,
+	delegate solana.PublicKey,
+	owner solana.PublicKey,
+	multisigSigners ...solana.PublicKey,
+) *ApproveChecked2022 {
+	return NewApproveChecked2022InstructionBuilder().
+		SetAmount(amount).
+		SetDecimals(decimals).
+		SetSource(source).
+		SetMint(mint).
+		SetDelegate(delegate).
+		SetOwner(owner, multisigSigners...)
+}
+
+// Revoke2022 revokes the delegate's authority over the source account.
+type Revoke2022 struct {
+	Source solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Owner  solana.PublicKey `bin:"-" borsh_skip:"true"`
+	// Signers are the signers of a multisig authority.
+	Signers []solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Source
+	// ··········· The source account
+	//
+	// [1] = [SIGNER] Owner
+	// ··········· The source account's owner, or a multisig
+	//
+	// [2...] = [SIGNER] Signers
+	// ··········· M signer accounts when the authority is a multisig
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewRevoke2022InstructionBuilder creates a new `Revoke2022` instruction builder.
+func NewRevoke2022InstructionBuilder() *Revoke2022 {
+	nd := &Revoke2022{}
+	return nd
+}
+
+func (inst *Revoke2022) SetSource(source solana.PublicKey) *Revoke2022 {
+	inst.Source = source
+	return inst
+}
+
+// SetOwner sets the authority. Pass the signers when the authority is a multisig.
+func (inst *Revoke2022) SetOwner(owner solana.PublicKey, multisigSigners ...solana.PublicKey) *Revoke2022 {
+	inst.Owner = owner
+	inst.Signers = multisigSigners
+	return inst
+}
+
+func (inst Revoke2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Source,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+	}
+	keys = appendAuthority(keys, inst.Owner, inst.Signers)
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst Revoke2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *Revoke2022) Validate() error {
+	if inst.Source.IsZero() {
+		return errNotSet("Source")
+	}
+	if inst.Owner.IsZero() {
+		return errNotSet("Owner")
+	}
+	if err := validateMultisigSigners(inst.Signers); err != nil {
+		return err
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+func (inst *Revoke2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("Revoke2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=0]").ParentFunc(func(paramsBranch treeout.Branches) {})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("source", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(format.Meta(" owner", inst.AccountMetaSlice.Get(1)))
+						encodeRemainingAccounts(accountsBranch, "signer", inst.AccountMetaSlice, 2)
+					})
+				})
+		})
+}
+
+func (inst Revoke2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	return nil
+}
+
+func (inst *Revoke2022) UnmarshalWithDecoder(decoder *bin.Decoder) error {
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst Revoke2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *Revoke2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("Revoke2022", accounts, 2); err != nil {
+		return err
+	}
+	inst.Source = accounts[0].PublicKey
+	inst.Owner = accounts[1].PublicKey
+	inst.Signers = pubkeysOf(accounts[2:])
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst Revoke2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *Revoke2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewRevoke2022Instruction creates a new `Revoke2022` instruction.
+func NewRevoke2022Instruction(
+	source solana.PublicKey,
+	owner solana.PublicKey,
+	multisigSigners ...solana.PublicKey,
+) *Revoke2022 {
+	return NewRevoke2022InstructionBuilder().
+		SetSource(source).
+		SetOwner(owner, multisigSigners...)
+}
@@ -1,18 +1,107 @@
 package token2022
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/gagliardetto/solana-go"
 )
 
+// FindAssociatedTokenAddress2022 derives the Token-2022 associated token
+// account of wallet for mint. It matches solana.FindProgramAddress over the
+// wallet, program and mint seeds but does not allocate.
 func FindAssociatedTokenAddress2022(
 	wallet solana.PublicKey,
 	mint solana.PublicKey,
 ) (solana.PublicKey, uint8, error) {
-	return solana.FindProgramAddress([][]byte{
-		wallet[:],
-		solana.Token2022ProgramID[:],
-		mint[:],
-	},
-		solana.SPLAssociatedTokenAccountProgramID,
-	)
+	return FindAssociatedTokenAddress(wallet, mint, solana.Token2022ProgramID)
+}
+
+// FindAssociatedTokenAddress derives the associated token account of
+// wallet for mint under tokenProgram, solana.TokenProgramID for mints of
+// the original SPL Token program.
+func FindAssociatedTokenAddress(
+	wallet solana.PublicKey,
+	mint solana.PublicKey,
+	tokenProgram solana.PublicKey,
+) (solana.PublicKey, uint8, error) {
+	address, bump, ok := findAssociatedTokenAddress(wallet, tokenProgram, mint)
+	if !ok {
+		return solana.PublicKey{}, 0, errors.New("unable to find a valid program address")
+	}
+	return address, bump, nil
+}
+
+// FindAssociatedTokenAddress2022Checked is FindAssociatedTokenAddress2022
+// that first rejects, with ErrOwnerOffCurve, an owner off the ed25519 curve
+// unless allowOwnerOffCurve is set. Use it wherever the owner comes from
+// user input.
+func FindAssociatedTokenAddress2022Checked(
+	owner solana.PublicKey,
+	mint solana.PublicKey,
+	allowOwnerOffCurve bool,
+) (solana.PublicKey, uint8, error) {
+	if err := ValidateOwner(owner, allowOwnerOffCurve); err != nil {
+		return solana.PublicKey{}, 0, err
+	}
+	return FindAssociatedTokenAddress2022(owner, mint)
+}
+
+// AssociatedAccountPlan is the result of PlanAssociatedTokenAccount.
+type AssociatedAccountPlan struct {
+	Address      solana.PublicKey
+	Instructions []solana.Instruction
+	// Frozen reports that the mint's DefaultAccountState extension makes
+	// new accounts start frozen.
+	Frozen bool
+	// Thawed reports that Instructions thaw the account after creating
+	// it.
+	Thawed bool
+	// NeedsThawBy is the freeze authority that must thaw a frozen account
+	// when Instructions do not. It is nil when the account is not frozen or
+	// is thawed, and also when the mint has no freeze authority left, in
+	// which case the account stays frozen.
+	NeedsThawBy *solana.PublicKey
+}
+
+// PlanAssociatedTokenAccount returns the instructions creating the
+// associated token account of owner for mint, paid by payer. The account
+// is created idempotently. When the mint's DefaultAccountState is frozen
+// and its freeze authority is among signers, the keys the caller will sign
+// with, a ThawAccount instruction follows; otherwise NeedsThawBy names the
+// authority the owner must ask. An owner off the ed25519 curve is rejected
+// with ErrOwnerOffCurve unless allowOwnerOffCurve is set.
+func PlanAssociatedTokenAccount(mint solana.PublicKey, decoded *Mint, payer, owner solana.PublicKey, allowOwnerOffCurve bool, signers ...solana.PublicKey) (*AssociatedAccountPlan, error) {
+	if decoded == nil {
+		return nil, errNotSet("mint")
+	}
+	address, _, err := FindAssociatedTokenAddress2022Checked(owner, mint, allowOwnerOffCurve)
+	if err != nil {
+		return nil, err
+	}
+	plan := &AssociatedAccountPlan{
+		Address:      address,
+		Instructions: []solana.Instruction{NewCreate2022Instruction(payer, owner, mint).SetIdempotent(true).SetAllowOwnerOffCurve(allowOwnerOffCurve).Build()},
+	}
+	state, _, err := decoded.DefaultAccountState()
+	if err != nil {
+		return nil, fmt.Errorf("error while reading default account state: %w", err)
+	}
+	if state != AccountStateFrozen {
+		return plan, nil
+	}
+	plan.Frozen = true
+	if decoded.FreezeAuthority == nil {
+		return plan, nil
+	}
+	for _, signer := range signers {
+		if signer == *decoded.FreezeAuthority {
+			plan.Instructions = append(plan.Instructions, NewThawAccount2022Instruction(address, mint, signer).Build())
+			plan.Thawed = true
+			return plan, nil
+		}
+	}
+	authority := *decoded.FreezeAuthority
+	plan.NeedsThawBy = &authority
+	return plan, nil
 }
@@ -0,0 +1,419 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// AlertKind classifies a SecurityAlert.
+type AlertKind int
+
+const (
+	// AlertAuthorityChange is a SetAuthority other than the freeze
+	// authority, such as a new mint authority or account owner.
+	AlertAuthorityChange AlertKind = iota
+	// AlertFreezeAuthorityChange is a SetAuthority of the freeze
+	// authority of a mint.
+	AlertFreezeAuthorityChange
+	// AlertDelegateApproval is an Approve or ApproveChecked.
+	AlertDelegateApproval
+	// AlertTransferHookChange is an update of the transfer hook program
+	// of a mint.
+	AlertTransferHookChange
+)
+
+var alertKindNames = []string{
+	"AuthorityChange",
+	"FreezeAuthorityChange",
+	"DelegateApproval",
+	"TransferHookChange",
+}
+
+func (k AlertKind) String() string {
+	if k >= 0 && int(k) < len(alertKindNames) {
+		return alertKindNames[k]
+	}
+	return fmt.Sprintf("AlertKind(%d)", int(k))
+}
+
+// SecurityAlert is an instruction changing who controls a watched mint or
+// token account.
+type SecurityAlert struct {
+	Kind AlertKind
+	// Address is the mint or token account changed by the instruction.
+	Address   solana.PublicKey
+	Signature solana.Signature
+	Slot      uint64
+	BlockTime *solana.UnixTimeSeconds
+	// Index and InnerIndex locate the instruction as in ParsedInstruction.
+	Index      int
+	InnerIndex int
+	// Instruction is the instruction name, such as "SetAuthority".
+	Instruction string
+	// Authority is the signing authority, or the owner for approvals.
+	Authority solana.PublicKey
+	// AuthorityType and NewAuthority are set for authority changes.
+	// NewAuthority is nil when the authority was removed.
+	AuthorityType AuthorityType
+	NewAuthority  *solana.PublicKey
+	// Delegate and Amount are set for delegate approvals.
+	Delegate solana.PublicKey
+	Amount   uint64
+	// HookProgramID is the new transfer hook program, or nil when the
+	// hook was removed.
+	HookProgramID *solana.PublicKey
+}
+
+// ID returns a key unique to the alert, made of the signature and the
+// instruction position, for deduplicating alerts that are delivered again
+// after a restart.
+func (a *SecurityAlert) ID() string {
+	return fmt.Sprintf("%s:%d:%d", a.Signature, a.Index, a.InnerIndex)
+}
+
+// SecurityAlerts returns the authority changes, delegate approvals and
+// transfer hook updates of a parsed transaction whose changed mint or
+// account is watched, in execution order. Failed transactions have no
+// alerts. Slot and BlockTime are left for the caller to fill in.
+func SecurityAlerts(parsed *ParsedTransaction, watched func(solana.PublicKey) bool) []*SecurityAlert {
+	if parsed.Err != nil {
+		return nil
+	}
+	var alerts []*SecurityAlert
+	for _, inst := range parsed.Instructions {
+		alert := securityAlert(inst)
+		if alert == nil || !watched(alert.Address) {
+			continue
+		}
+		alert.Signature = parsed.Signature
+		alerts = append(alerts, alert)
+	}
+	return alerts
+}
+
+// securityAlert classifies one instruction, returning nil for
+// instructions that do not raise alerts.
+func securityAlert(parsed *ParsedInstruction) *SecurityAlert {
+	alert := &SecurityAlert{
+		Index:       parsed.Index,
+		InnerIndex:  parsed.InnerIndex,
+		Instruction: parsed.Name,
+	}
+	switch inst := parsed.Instruction.(type) {
+	case *SetAuthority2022:
+		alert.Kind = AlertAuthorityChange
+		if inst.AuthorityType == AuthorityFreezeAccount {
+			alert.Kind = AlertFreezeAuthorityChange
+		}
+		alert.Address, alert.Authority = inst.Account, inst.Authority
+		alert.AuthorityType, alert.NewAuthority = inst.AuthorityType, inst.NewAuthority
+	case *Approve2022:
+		alert.Kind = AlertDelegateApproval
+		alert.Address, alert.Authority, alert.Delegate, alert.Amount = inst.Source, inst.Owner, inst.Delegate, inst.Amount
+	case *ApproveChecked2022:
+		alert.Kind = AlertDelegateApproval
+		alert.Address, alert.Authority, alert.Delegate, alert.Amount = inst.Source, inst.Owner, inst.Delegate, inst.Amount
+	case *UpdateTransferHook2022:
+		alert.Kind = AlertTransferHookChange
+		alert.Address, alert.Authority, alert.HookProgramID = inst.Mint, inst.Authority, inst.HookProgramID
+	default:
+		return nil
+	}
+	return alert
+}
+
+// AlertSink receives the alerts of an AuthorityWatcher, for example to
+// page an operator or post to a chat channel.
+type AlertSink interface {
+	Notify(ctx context.Context, alert *SecurityAlert) error
+}
+
+// AlertSinkFunc adapts a function to AlertSink.
+type AlertSinkFunc func(ctx context.Context, alert *SecurityAlert) error
+
+func (f AlertSinkFunc) Notify(ctx context.Context, alert *SecurityAlert) error {
+	return f(ctx, alert)
+}
+
+// NewLogAlertSink returns a sink logging every alert at warn level.
+func NewLogAlertSink(logger Logger) AlertSink {
+	return AlertSinkFunc(func(ctx context.Context, alert *SecurityAlert) error {
+		args := []any{"kind", alert.Kind, "address", alert.Address, "signature", alert.Signature, "slot", alert.Slot, "instruction", alert.Instruction, "authority", alert.Authority}
+		switch alert.Kind {
+		case AlertAuthorityChange, AlertFreezeAuthorityChange:
+			args = append(args, "authority_type", alert.AuthorityType, "new_authority", optionalKeyString(alert.NewAuthority))
+		case AlertDelegateApproval:
+			args = append(args, "delegate", alert.Delegate, "amount", alert.Amount)
+		case AlertTransferHookChange:
+			args = append(args, "hook_program", optionalKeyString(alert.HookProgramID))
+		}
+		logger.Log(ctx, slog.LevelWarn, "token2022 security alert", args...)
+		return nil
+	})
+}
+
+func optionalKeyString(key *solana.PublicKey) string {
+	if key == nil {
+		return "none"
+	}
+	return key.String()
+}
+
+// AuthorityWatcher monitors mints and token accounts for SetAuthority,
+// Approve and transfer hook updates, and notifies its sinks of every one.
+// It polls the signatures of each watched address and parses the new
+// successful transactions.
+//
+// Progress is a signature cursor per address. The first poll of an
+// address without a cursor only records its latest signature, so alerts
+// start with the transactions after the watcher started; SetCursor
+// resumes from a stored cursor instead. A cursor only advances after all
+// sinks accepted the alerts before it, and SecurityAlert.ID deduplicates
+// alerts that are delivered again after a failure.
+//
+// AuthorityWatcher is safe for concurrent use.
+type AuthorityWatcher struct {
+	reader       *HistoryReader
+	commitment   rpc.CommitmentType
+	pollInterval time.Duration
+	retry        RetryPolicy
+	logger       Logger
+	metrics      Metrics
+	sinks        []AlertSink
+
+	mu      sync.Mutex
+	cursors map[solana.PublicKey]solana.Signature
+}
+
+// NewAuthorityWatcher creates a watcher reading at confirmed commitment
+// and polling every five seconds.
+func NewAuthorityWatcher(client HistoryClient, sinks ...AlertSink) *AuthorityWatcher {
+	return &AuthorityWatcher{
+		reader:       NewHistoryReader(client),
+		commitment:   rpc.CommitmentConfirmed,
+		pollInterval: 5 * time.Second,
+		retry:        DefaultRetryPolicy(),
+		sinks:        sinks,
+		cursors:      map[solana.PublicKey]solana.Signature{},
+	}
+}
+
+// SetLogger logs every retried poll.
+func (w *AuthorityWatcher) SetLogger(logger Logger) *AuthorityWatcher {
+	w.logger = logger
+	return w
+}
+
+// SetMetrics counts retried polls and transactions that could not be
+// decoded.
+func (w *AuthorityWatcher) SetMetrics(metrics Metrics) *AuthorityWatcher {
+	w.metrics = metrics
+	return w
+}
+
+func (w *AuthorityWatcher) SetCommitment(commitment rpc.CommitmentType) *AuthorityWatcher {
+	w.commitment = commitment
+	return w
+}
+
+func (w *AuthorityWatcher) SetPollInterval(interval time.Duration) *AuthorityWatcher {
+	w.pollInterval = interval
+	return w
+}
+
+// SetRetryPolicy sets how Run retries failed polls.
+func (w *AuthorityWatcher) SetRetryPolicy(policy RetryPolicy) *AuthorityWatcher {
+	w.retry = policy
+	return w
+}
+
+// AddSink adds a sink notified after those already set.
+func (w *AuthorityWatcher) AddSink(sink AlertSink) *AuthorityWatcher {
+	w.sinks = append(w.sinks, sink)
+	return w
+}
+
+// Watch adds mints or token accounts to watch. Addresses already watched
+// keep their cursor.
+func (w *AuthorityWatcher) Watch(addresses ...solana.PublicKey) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, address := range addresses {
+		if _, ok := w.cursors[address]; !ok {
+			w.cursors[address] = solana.Signature{}
+		}
+	}
+}
+
+func (w *AuthorityWatcher) Unwatch(addresses ...solana.PublicKey) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, address := range addresses {
+		delete(w.cursors, address)
+	}
+}
+
+// SetCursor watches address from the transactions after sig, as
+// returned by Cursors.
+func (w *AuthorityWatcher) SetCursor(address solana.PublicKey, sig solana.Signature) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.cursors[address] = sig
+}
+
+// Cursors returns the latest signature processed for every watched
+// address, for storing and passing to SetCursor after a restart. The
+// zero signature marks an address that was not polled yet.
+func (w *AuthorityWatcher) Cursors() map[solana.PublicKey]solana.Signature {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	out := make(map[solana.PublicKey]solana.Signature, len(w.cursors))
+	for address, sig := range w.cursors {
+		out[address] = sig
+	}
+	return out
+}
+
+// Poll reads the new transactions of every watched address, notifies the
+// sinks and returns the alerts, ordered by slot. On error, the addresses
+// polled before the failure keep their progress.
+func (w *AuthorityWatcher) Poll(ctx context.Context) ([]*SecurityAlert, error) {
+	cursors := w.Cursors()
+	addresses := make([]solana.PublicKey, 0, len(cursors))
+	for address := range cursors {
+		addresses = append(addresses, address)
+	}
+	sort.Slice(addresses, func(i, j int) bool { return addresses[i].String() < addresses[j].String() })
+
+	var alerts []*SecurityAlert
+	for _, address := range addresses {
+		found, err := w.poll(ctx, address, cursors[address])
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, found...)
+	}
+	sort.SliceStable(alerts, func(i, j int) bool { return alerts[i].Slot < alerts[j].Slot })
+	return alerts, nil
+}
+
+func (w *AuthorityWatcher) poll(ctx context.Context, address solana.PublicKey, cursor solana.Signature) ([]*SecurityAlert, error) {
+	limit := math.MaxInt
+	if cursor.IsZero() {
+		limit = 1
+	}
+	sigs, _, err := w.reader.signatures(ctx, address, &HistoryOpts{Until: cursor, Commitment: w.commitment}, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(sigs) == 0 {
+		return nil, nil
+	}
+	if cursor.IsZero() {
+		w.advance(address, cursor, sigs[0].Signature)
+		return nil, nil
+	}
+
+	succeeded := make([]*rpc.TransactionSignature, 0, len(sigs))
+	for _, sig := range sigs {
+		if sig.Err == nil {
+			succeeded = append(succeeded, sig)
+		}
+	}
+	txs, err := w.reader.transactions(ctx, succeeded, w.commitment)
+	if err != nil {
+		return nil, err
+	}
+	var alerts []*SecurityAlert
+	// Signatures are listed newest first.
+	for i := len(txs) - 1; i >= 0; i-- {
+		tx, err := txs[i].Transaction.GetTransaction()
+		if err != nil {
+			addMetric(w.metrics, MetricDecodeErrors, 1, "authority_watch")
+			return nil, fmt.Errorf("error while decoding transaction %s: %w", succeeded[i].Signature, err)
+		}
+		parsed, err := ParseTransaction(tx, txs[i].Meta)
+		if err != nil {
+			addMetric(w.metrics, MetricDecodeErrors, 1, "authority_watch")
+			return nil, fmt.Errorf("error while parsing transaction %s: %w", succeeded[i].Signature, err)
+		}
+		for _, alert := range SecurityAlerts(parsed, address.Equals) {
+			alert.Slot, alert.BlockTime = txs[i].Slot, txs[i].BlockTime
+			alerts = append(alerts, alert)
+		}
+	}
+	for _, alert := range alerts {
+		for _, sink := range w.sinks {
+			if err := sink.Notify(ctx, alert); err != nil {
+				return nil, fmt.Errorf("error while notifying alert %s: %w", alert.ID(), err)
+			}
+		}
+	}
+	w.advance(address, cursor, sigs[0].Signature)
+	return alerts, nil
+}
+
+// advance moves the cursor of address unless it was unwatched or reset
+// during the poll.
+func (w *AuthorityWatcher) advance(address solana.PublicKey, from, to solana.Signature) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if current, ok := w.cursors[address]; ok && current == from {
+		w.cursors[address] = to
+	}
+}
+
+// Run polls until ctx is done. Failed polls are retried according to the
+// retry policy; Run returns the error of a poll that failed after its
+// retries, including sink errors that are not retryable.
+func (w *AuthorityWatcher) Run(ctx context.Context) error {
+	failures := 0
+	for {
+		_, err := w.Poll(ctx)
+		wait := w.pollInterval
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			failures++
+			if failures >= w.retry.MaxAttempts || !IsRetryable(err) {
+				return err
+			}
+			wait = w.retry.Backoff(failures, err)
+			logEvent(ctx, w.logger, slog.LevelWarn, "authority watch retried", "attempt", failures, "backoff", wait, "error", err)
+			addMetric(w.metrics, MetricRetries, 1, "authority_watch")
+		} else {
+			failures = 0
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
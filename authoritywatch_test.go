@@ -0,0 +1,130 @@
+package token2022
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// securityTransaction wraps instructions signed by depositWallet.
+func securityTransaction(t *testing.T, sig solana.Signature, instructions ...solana.Instruction) (*solana.Transaction, *rpc.TransactionMeta) {
+	tx, err := solana.NewTransaction(instructions, solana.Hash{1}, solana.TransactionPayer(depositWallet))
+	if err != nil {
+		t.Fatalf("NewTransaction: %v", err)
+	}
+	tx.Signatures = []solana.Signature{sig}
+	return tx, &rpc.TransactionMeta{
+		PreBalances:  make([]uint64, len(tx.Message.AccountKeys)),
+		PostBalances: make([]uint64, len(tx.Message.AccountKeys)),
+	}
+}
+
+func TestSecurityAlerts(t *testing.T) {
+	hook := solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR")
+	tx, meta := securityTransaction(t, solana.Signature{1},
+		NewSetAuthority2022Instruction(AuthorityFreezeAccount, nil, depositMint, depositWallet).Build(),
+		NewApprove2022Instruction(500, depositSource, depositDestination, depositWallet).Build(),
+		NewUpdateTransferHook2022Instruction(&hook, depositMint, depositWallet).Build(),
+		NewTransferChecked2022Instruction(1_000, 6, depositSource, depositMint, depositDestination, depositWallet).Build(),
+	)
+	parsed, err := ParseTransaction(tx, meta)
+	if err != nil {
+		t.Fatalf("ParseTransaction: %v", err)
+	}
+
+	alerts := SecurityAlerts(parsed, func(solana.PublicKey) bool { return true })
+	if len(alerts) != 3 {
+		t.Fatalf("Expected 3 alerts, got %+v", alerts)
+	}
+	if a := alerts[0]; a.Kind != AlertFreezeAuthorityChange || a.Address != depositMint || a.NewAuthority != nil || a.Authority != depositWallet {
+		t.Errorf("Unexpected freeze authority alert %+v", a)
+	}
+	if a := alerts[1]; a.Kind != AlertDelegateApproval || a.Address != depositSource || a.Delegate != depositDestination || a.Amount != 500 {
+		t.Errorf("Unexpected approval alert %+v", a)
+	}
+	if a := alerts[2]; a.Kind != AlertTransferHookChange || a.HookProgramID == nil || *a.HookProgramID != hook || a.Index != 2 {
+		t.Errorf("Unexpected hook alert %+v", a)
+	}
+	if alerts[0].ID() == alerts[1].ID() || alerts[0].Signature != (solana.Signature{1}) {
+		t.Errorf("Expected distinct IDs with the signature, got %s and %s", alerts[0].ID(), alerts[1].ID())
+	}
+
+	alerts = SecurityAlerts(parsed, depositSource.Equals)
+	if len(alerts) != 1 || alerts[0].Kind != AlertDelegateApproval {
+		t.Errorf("Expected only the approval of the watched account, got %+v", alerts)
+	}
+
+	parsed.Err = &TransactionError{}
+	if alerts := SecurityAlerts(parsed, func(solana.PublicKey) bool { return true }); alerts != nil {
+		t.Errorf("Expected no alerts for a failed transaction, got %+v", alerts)
+	}
+}
+
+func TestAuthorityWatcher(t *testing.T) {
+	ctx := context.Background()
+	client := &historyRPC{txs: map[solana.Signature]*rpc.GetTransactionResult{}}
+	newAuthority := solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR")
+	// newest first: 3 changes the mint authority, 2 is a transfer, 1
+	// predates the watcher.
+	for _, sig := range []solana.Signature{{3}, {2}, {1}} {
+		var inst solana.Instruction
+		switch sig[0] {
+		case 2:
+			inst = NewTransferChecked2022Instruction(1_000, 6, depositSource, depositMint, depositDestination, depositWallet).Build()
+		default:
+			inst = NewSetAuthority2022Instruction(AuthorityMintTokens, &newAuthority, depositMint, depositWallet).Build()
+		}
+		tx, meta := securityTransaction(t, sig, inst)
+		client.add(t, tx, meta, uint64(sig[0])*10)
+	}
+
+	var notified []*SecurityAlert
+	fail := true
+	watcher := NewAuthorityWatcher(client, AlertSinkFunc(func(ctx context.Context, alert *SecurityAlert) error {
+		if fail {
+			return errors.New("pager down")
+		}
+		notified = append(notified, alert)
+		return nil
+	}))
+	watcher.Watch(depositMint)
+	watcher.SetCursor(depositMint, solana.Signature{1})
+
+	if _, err := watcher.Poll(ctx); err == nil {
+		t.Fatal("Expected the sink error")
+	}
+	if cursor := watcher.Cursors()[depositMint]; cursor != (solana.Signature{1}) {
+		t.Errorf("Expected the cursor to stay at 1 after a sink error, got %s", cursor)
+	}
+
+	fail = false
+	alerts, err := watcher.Poll(ctx)
+	if err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if len(alerts) != 1 || len(notified) != 1 || notified[0] != alerts[0] {
+		t.Fatalf("Expected one notified alert, got %+v and %+v", alerts, notified)
+	}
+	a := alerts[0]
+	if a.Kind != AlertAuthorityChange || a.AuthorityType != AuthorityMintTokens || a.NewAuthority == nil || *a.NewAuthority != newAuthority || a.Slot != 30 || a.Signature != (solana.Signature{3}) {
+		t.Errorf("Unexpected alert %+v", a)
+	}
+	if cursor := watcher.Cursors()[depositMint]; cursor != (solana.Signature{3}) {
+		t.Errorf("Expected the cursor at 3, got %s", cursor)
+	}
+	if alerts, err := watcher.Poll(ctx); err != nil || len(alerts) != 0 {
+		t.Errorf("Expected nothing new, got %+v, %v", alerts, err)
+	}
+
+	fresh := NewAuthorityWatcher(client, NewLogAlertSink(nil))
+	fresh.Watch(depositMint)
+	if alerts, err := fresh.Poll(ctx); err != nil || len(alerts) != 0 {
+		t.Errorf("Expected the first poll to only record the cursor, got %+v, %v", alerts, err)
+	}
+	if cursor := fresh.Cursors()[depositMint]; cursor != (solana.Signature{3}) {
+		t.Errorf("Expected the cursor at the latest signature, got %s", cursor)
+	}
+}
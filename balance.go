@@ -0,0 +1,278 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// TokenBalanceChange is the change of a Token-2022 account's balance in a
+// transaction.
+type TokenBalanceChange struct {
+	Account solana.PublicKey
+	// Owner is the account owner, or the zero key when the RPC node did
+	// not report it.
+	Owner    solana.PublicKey
+	Mint     solana.PublicKey
+	Decimals uint8
+	Pre      uint64
+	Post     uint64
+	// Delta is Post - Pre.
+	Delta *big.Int
+	// FeeWithheld is the transfer fee withheld in the account by the
+	// transfers into it. It is inferred from the transfer instructions of
+	// the transaction, so it is zero when the transfers were made through
+	// an unsupported instruction.
+	FeeWithheld uint64
+}
+
+// LamportBalanceChange is the change of an account's SOL balance in a
+// transaction.
+type LamportBalanceChange struct {
+	Account solana.PublicKey
+	Pre     uint64
+	Post    uint64
+	// Delta is Post - Pre.
+	Delta int64
+}
+
+// OwnerBalanceChange is the net change of all the token accounts of an
+// owner for one mint.
+type OwnerBalanceChange struct {
+	Owner       solana.PublicKey
+	Mint        solana.PublicKey
+	Decimals    uint8
+	Delta       *big.Int
+	FeeWithheld uint64
+}
+
+// BalanceChanges are the balance changes of a transaction.
+type BalanceChanges struct {
+	// Tokens lists the Token-2022 accounts whose balance changed, in
+	// account order.
+	Tokens []*TokenBalanceChange
+	// Lamports lists the accounts whose SOL balance changed, in account
+	// order. The fee payer's change includes the transaction fee.
+	Lamports []*LamportBalanceChange
+	// Fee is the transaction fee in lamports.
+	Fee uint64
+}
+
+// ByOwner sums the token balance changes per owner and mint. Changes of
+// accounts with an unknown owner are grouped under the zero key.
+func (c *BalanceChanges) ByOwner() []*OwnerBalanceChange {
+	type ownerMint struct{ owner, mint solana.PublicKey }
+	var out []*OwnerBalanceChange
+	byKey := map[ownerMint]*OwnerBalanceChange{}
+	for _, change := range c.Tokens {
+		key := ownerMint{change.Owner, change.Mint}
+		total, ok := byKey[key]
+		if !ok {
+			total = &OwnerBalanceChange{
+				Owner:    change.Owner,
+				Mint:     change.Mint,
+				Decimals: change.Decimals,
+				Delta:    new(big.Int),
+			}
+			byKey[key] = total
+			out = append(out, total)
+		}
+		total.Delta.Add(total.Delta, change.Delta)
+		total.FeeWithheld += change.FeeWithheld
+	}
+	return out
+}
+
+// Token returns the change of a token account, or nil when its balance
+// did not change.
+func (c *BalanceChanges) Token(account solana.PublicKey) *TokenBalanceChange {
+	for _, change := range c.Tokens {
+		if change.Account.Equals(account) {
+			return change
+		}
+	}
+	return nil
+}
+
+// ExtractBalanceChanges diffs the pre and post token balances and
+// lamports of a transaction. Token balances of other token programs are
+// ignored. Accounts created by the transaction have a Pre of zero and
+// closed accounts a Post of zero.
+func ExtractBalanceChanges(tx *solana.Transaction, meta *rpc.TransactionMeta) (*BalanceChanges, error) {
+	if tx == nil {
+		return nil, errNotSet("transaction")
+	}
+	if meta == nil {
+		return nil, errNotSet("transaction meta")
+	}
+	keys := newTransactionKeys(&tx.Message, meta)
+	account := func(index uint16) (solana.PublicKey, error) {
+		if int(index) >= len(keys.keys) {
+			return solana.PublicKey{}, fmt.Errorf("account index %d out of range (%d accounts)", index, len(keys.keys))
+		}
+		return keys.keys[index], nil
+	}
+
+	changes := &BalanceChanges{Fee: meta.Fee}
+
+	if len(meta.PreBalances) != len(meta.PostBalances) {
+		return nil, fmt.Errorf("got %d pre balances and %d post balances", len(meta.PreBalances), len(meta.PostBalances))
+	}
+	for i := range meta.PreBalances {
+		pre, post := meta.PreBalances[i], meta.PostBalances[i]
+		if pre == post {
+			continue
+		}
+		pubkey, err := account(uint16(i))
+		if err != nil {
+			return nil, err
+		}
+		changes.Lamports = append(changes.Lamports, &LamportBalanceChange{
+			Account: pubkey,
+			Pre:     pre,
+			Post:    post,
+			Delta:   int64(post) - int64(pre),
+		})
+	}
+
+	byIndex := map[uint16]*TokenBalanceChange{}
+	collect := func(balances []rpc.TokenBalance, post bool) error {
+		for _, balance := range balances {
+			if balance.ProgramId != nil && !balance.ProgramId.Equals(solana.Token2022ProgramID) {
+				continue
+			}
+			if balance.UiTokenAmount == nil {
+				return fmt.Errorf("token balance of account %d has no amount", balance.AccountIndex)
+			}
+			amount, ok := new(big.Int).SetString(balance.UiTokenAmount.Amount, 10)
+			if !ok || !amount.IsUint64() {
+				return fmt.Errorf("invalid token amount %q of account %d", balance.UiTokenAmount.Amount, balance.AccountIndex)
+			}
+			change, ok := byIndex[balance.AccountIndex]
+			if !ok {
+				pubkey, err := account(balance.AccountIndex)
+				if err != nil {
+					return err
+				}
+				change = &TokenBalanceChange{Account: pubkey}
+				byIndex[balance.AccountIndex] = change
+			}
+			change.Mint = balance.Mint
+			change.Decimals = balance.UiTokenAmount.Decimals
+			if balance.Owner != nil {
+				change.Owner = *balance.Owner
+			}
+			if post {
+				change.Post = amount.Uint64()
+			} else {
+				change.Pre = amount.Uint64()
+			}
+		}
+		return nil
+	}
+	if err := collect(meta.PreTokenBalances, false); err != nil {
+		return nil, err
+	}
+	if err := collect(meta.PostTokenBalances, true); err != nil {
+		return nil, err
+	}
+
+	indexes := make([]int, 0, len(byIndex))
+	for index, change := range byIndex {
+		if change.Pre != change.Post {
+			indexes = append(indexes, int(index))
+		}
+	}
+	sort.Ints(indexes)
+	for _, index := range indexes {
+		change := byIndex[uint16(index)]
+		change.Delta = new(big.Int).Sub(new(big.Int).SetUint64(change.Post), new(big.Int).SetUint64(change.Pre))
+		changes.Tokens = append(changes.Tokens, change)
+	}
+
+	if meta.Err == nil && len(changes.Tokens) > 0 {
+		if err := inferWithheldFees(tx, meta, changes); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+// inferWithheldFees compares the balance change of every account that
+// received a transfer with the change its instructions account for. A
+// shortfall, up to the amount transferred in, is the withheld transfer fee.
+func inferWithheldFees(tx *solana.Transaction, meta *rpc.TransactionMeta, changes *BalanceChanges) error {
+	parsed, err := ParseTransaction(tx, meta)
+	if err != nil {
+		return err
+	}
+	expected := map[solana.PublicKey]*big.Int{}
+	transferredIn := map[solana.PublicKey]*big.Int{}
+	add := func(m map[solana.PublicKey]*big.Int, account solana.PublicKey, amount uint64, sign int) {
+		total, ok := m[account]
+		if !ok {
+			total = new(big.Int)
+			m[account] = total
+		}
+		v := new(big.Int).SetUint64(amount)
+		if sign < 0 {
+			v.Neg(v)
+		}
+		total.Add(total, v)
+	}
+	transfer := func(source, destination solana.PublicKey, amount uint64) {
+		add(expected, source, amount, -1)
+		add(expected, destination, amount, 1)
+		add(transferredIn, destination, amount, 1)
+	}
+	for _, inst := range parsed.Instructions {
+		switch inst := inst.Instruction.(type) {
+		case *Transfer2022:
+			transfer(inst.Source, inst.Destination, inst.Amount)
+		case *TransferChecked2022:
+			transfer(inst.Source, inst.Destination, inst.Amount)
+		case *TransferCheckedWithFee2022:
+			transfer(inst.Source, inst.Destination, inst.Amount)
+		case *MintTo2022:
+			add(expected, inst.Destination, inst.Amount, 1)
+		case *MintToChecked2022:
+			add(expected, inst.Destination, inst.Amount, 1)
+		case *Burn2022:
+			add(expected, inst.Account, inst.Amount, -1)
+		case *BurnChecked2022:
+			add(expected, inst.Account, inst.Amount, -1)
+		}
+	}
+	for _, change := range changes.Tokens {
+		in, ok := transferredIn[change.Account]
+		if !ok {
+			continue
+		}
+		shortfall := new(big.Int).Sub(expected[change.Account], change.Delta)
+		if shortfall.Sign() <= 0 {
+			continue
+		}
+		if shortfall.Cmp(in) > 0 {
+			shortfall = in
+		}
+		change.FeeWithheld = shortfall.Uint64()
+	}
+	return nil
+}
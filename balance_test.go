@@ -0,0 +1,138 @@
+package token2022
+
+import (
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestExtractBalanceChanges(t *testing.T) {
+
+	var (
+		wallet      = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		recipient   = solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112")
+	)
+
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{
+			NewTransferCheckedWithFee2022Instruction(1_000, 6, 10, source, mint, destination, wallet).Build(),
+			NewTransferChecked2022Instruction(500, 6, source, mint, destination, wallet).Build(),
+		},
+		solana.Hash(source),
+		solana.TransactionPayer(wallet),
+	)
+	if err != nil {
+		t.Fatalf("NewTransaction: %v", err)
+	}
+	tx.Signatures = []solana.Signature{{1}}
+
+	index := func(key solana.PublicKey) uint16 {
+		for i, k := range tx.Message.AccountKeys {
+			if k.Equals(key) {
+				return uint16(i)
+			}
+		}
+		t.Fatalf("key %s not in transaction", key)
+		return 0
+	}
+	balance := func(account solana.PublicKey, owner solana.PublicKey, amount string) rpc.TokenBalance {
+		return rpc.TokenBalance{
+			AccountIndex:  index(account),
+			Owner:         &owner,
+			ProgramId:     &solana.Token2022ProgramID,
+			Mint:          mint,
+			UiTokenAmount: &rpc.UiTokenAmount{Amount: amount, Decimals: 6},
+		}
+	}
+
+	meta := &rpc.TransactionMeta{
+		Fee:          5_000,
+		PreBalances:  make([]uint64, len(tx.Message.AccountKeys)),
+		PostBalances: make([]uint64, len(tx.Message.AccountKeys)),
+		PreTokenBalances: []rpc.TokenBalance{
+			balance(source, wallet, "5000"),
+		},
+		// The destination is created by the transaction; the transfer
+		// fee is 10 on both transfers.
+		PostTokenBalances: []rpc.TokenBalance{
+			balance(source, wallet, "3500"),
+			balance(destination, recipient, "1480"),
+		},
+	}
+	meta.PreBalances[0] = 1_000_000
+	meta.PostBalances[0] = 995_000
+	for i := range meta.PreBalances[1:] {
+		meta.PreBalances[i+1] = 42
+		meta.PostBalances[i+1] = 42
+	}
+
+	changes, err := ExtractBalanceChanges(tx, meta)
+	if err != nil {
+		t.Fatalf("ExtractBalanceChanges: %v", err)
+	}
+	if changes.Fee != 5_000 {
+		t.Errorf("unexpected fee %d", changes.Fee)
+	}
+	if len(changes.Lamports) != 1 || !changes.Lamports[0].Account.Equals(wallet) || changes.Lamports[0].Delta != -5_000 {
+		t.Errorf("unexpected lamport changes %+v", changes.Lamports)
+	}
+	if len(changes.Tokens) != 2 {
+		t.Fatalf("expected 2 token changes, got %d", len(changes.Tokens))
+	}
+
+	sent := changes.Token(source)
+	if sent == nil || sent.Delta.Int64() != -1_500 || sent.FeeWithheld != 0 || !sent.Owner.Equals(wallet) {
+		t.Errorf("unexpected source change %+v", sent)
+	}
+	received := changes.Token(destination)
+	if received == nil || received.Pre != 0 || received.Post != 1_480 || received.Delta.Int64() != 1_480 {
+		t.Fatalf("unexpected destination change %+v", received)
+	}
+	if received.FeeWithheld != 20 {
+		t.Errorf("expected 20 withheld, got %d", received.FeeWithheld)
+	}
+
+	owners := changes.ByOwner()
+	if len(owners) != 2 || !owners[1].Owner.Equals(recipient) || owners[1].Delta.Int64() != 1_480 || owners[1].FeeWithheld != 20 {
+		t.Errorf("unexpected owner changes %+v", owners)
+	}
+}
+
+func TestExtractBalanceChangesIgnoresOtherPrograms(t *testing.T) {
+
+	var (
+		account = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		mint    = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	)
+
+	tx := &solana.Transaction{
+		Message: solana.Message{
+			Header:      solana.MessageHeader{NumRequiredSignatures: 1},
+			AccountKeys: solana.PublicKeySlice{account},
+		},
+	}
+	meta := &rpc.TransactionMeta{
+		PreTokenBalances: []rpc.TokenBalance{{
+			ProgramId:     &solana.TokenProgramID,
+			Mint:          mint,
+			UiTokenAmount: &rpc.UiTokenAmount{Amount: "1"},
+		}},
+	}
+	changes, err := ExtractBalanceChanges(tx, meta)
+	if err != nil {
+		t.Fatalf("ExtractBalanceChanges: %v", err)
+	}
+	if len(changes.Tokens) != 0 {
+		t.Errorf("expected no token changes, got %d", len(changes.Tokens))
+	}
+
+	meta.PreTokenBalances[0].ProgramId = &solana.Token2022ProgramID
+	meta.PreTokenBalances[0].UiTokenAmount.Amount = "not a number"
+	if _, err := ExtractBalanceChanges(tx, meta); err == nil {
+		t.Errorf("expected an error for an invalid amount")
+	}
+}
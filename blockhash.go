@@ -0,0 +1,166 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// BlockhashProvider supplies recent blockhashes to TxBuilder.
+type BlockhashProvider interface {
+	LatestBlockhash(ctx context.Context) (*rpc.LatestBlockhashResult, error)
+}
+
+// DefaultMinRemainingBlocks is how many blocks of validity a cached
+// blockhash must have left before BlockhashCache hands it out.
+const DefaultMinRemainingBlocks = 30
+
+// BlockhashCache caches the latest blockhash and refreshes it in the
+// background, so high-throughput senders share one getLatestBlockhash
+// call instead of making one per transaction.
+//
+// The cache also tracks the current block height and never returns a
+// blockhash that is about to expire.
+type BlockhashCache struct {
+	client             RPCClient
+	commitment         rpc.CommitmentType
+	refreshInterval    time.Duration
+	minRemainingBlocks uint64
+
+	mu          sync.RWMutex
+	current     *rpc.LatestBlockhashResult
+	blockHeight uint64
+	fetchedAt   time.Time
+}
+
+// NewBlockhashCache creates a cache that refreshes every two seconds.
+func NewBlockhashCache(client RPCClient) *BlockhashCache {
+	return &BlockhashCache{
+		client:             client,
+		commitment:         rpc.CommitmentConfirmed,
+		refreshInterval:    2 * time.Second,
+		minRemainingBlocks: DefaultMinRemainingBlocks,
+	}
+}
+
+func (c *BlockhashCache) SetCommitment(commitment rpc.CommitmentType) *BlockhashCache {
+	c.commitment = commitment
+	return c
+}
+
+func (c *BlockhashCache) SetRefreshInterval(interval time.Duration) *BlockhashCache {
+	c.refreshInterval = interval
+	return c
+}
+
+func (c *BlockhashCache) SetMinRemainingBlocks(blocks uint64) *BlockhashCache {
+	c.minRemainingBlocks = blocks
+	return c
+}
+
+// Start refreshes the cache in a background goroutine until ctx is done.
+func (c *BlockhashCache) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(c.refreshInterval)
+		defer ticker.Stop()
+		for {
+			// Errors are not fatal here: LatestBlockhash falls back to a
+			// synchronous refresh when the cached value is unusable.
+			_ = c.Refresh(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Refresh fetches the latest blockhash and the current block height.
+func (c *BlockhashCache) Refresh(ctx context.Context) error {
+	latest, err := c.client.GetLatestBlockhash(ctx, c.commitment)
+	if err != nil {
+		return fmt.Errorf("error while GetLatestBlockhash: %w", err)
+	}
+	height, err := c.client.GetBlockHeight(ctx, c.commitment)
+	if err != nil {
+		return fmt.Errorf("error while GetBlockHeight: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.current = latest.Value
+	c.blockHeight = height
+	c.fetchedAt = time.Now()
+	return nil
+}
+
+// LatestBlockhash returns the cached blockhash, refreshing synchronously
+// when the cache is empty, stale, or close to expiry.
+func (c *BlockhashCache) LatestBlockhash(ctx context.Context) (*rpc.LatestBlockhashResult, error) {
+	if current, ok := c.usable(); ok {
+		return current, nil
+	}
+	if err := c.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.current, nil
+}
+
+// BlockHeight returns the block height observed at the last refresh,
+// advanced by the time elapsed since then.
+func (c *BlockhashCache) BlockHeight() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.estimatedHeight()
+}
+
+// IsValid reports whether a blockhash with the given last valid block
+// height can still land, based on the tracked block height.
+func (c *BlockhashCache) IsValid(lastValidBlockHeight uint64) bool {
+	return c.BlockHeight() <= lastValidBlockHeight
+}
+
+func (c *BlockhashCache) usable() (*rpc.LatestBlockhashResult, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.current == nil {
+		return nil, false
+	}
+	if time.Since(c.fetchedAt) > 2*c.refreshInterval {
+		return nil, false
+	}
+	if c.estimatedHeight()+c.minRemainingBlocks > c.current.LastValidBlockHeight {
+		return nil, false
+	}
+	return c.current, true
+}
+
+// estimatedHeight assumes the nominal 400ms slot time. Callers must hold c.mu.
+func (c *BlockhashCache) estimatedHeight() uint64 {
+	if c.fetchedAt.IsZero() {
+		return c.blockHeight
+	}
+	return c.blockHeight + uint64(time.Since(c.fetchedAt)/(400*time.Millisecond))
+}
+
+var _ BlockhashProvider = (*BlockhashCache)(nil)
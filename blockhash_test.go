@@ -0,0 +1,65 @@
+package token2022
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+func TestBlockhashCache(t *testing.T) {
+
+	var (
+		client = newMockRPC()
+		cache  = NewBlockhashCache(client).SetRefreshInterval(time.Minute)
+		ctx    = context.Background()
+	)
+
+	for i := 0; i < 3; i++ {
+		latest, err := cache.LatestBlockhash(ctx)
+		if err != nil {
+			t.Fatalf("Error getting blockhash: %v", err)
+		}
+		if latest.Blockhash != client.blockhash {
+			t.Errorf("Expected blockhash %s, got %s", client.blockhash, latest.Blockhash)
+		}
+	}
+	if client.calls["getLatestBlockhash"] != 1 {
+		t.Errorf("Expected 1 getLatestBlockhash call, got %d", client.calls["getLatestBlockhash"])
+	}
+	if !cache.IsValid(client.lastValidBlockHeight) {
+		t.Errorf("Expected blockhash to be valid")
+	}
+
+	// A blockhash close to expiry is refreshed instead of handed out.
+	client.blockHeight = client.lastValidBlockHeight - 10
+	if err := cache.Refresh(ctx); err != nil {
+		t.Fatalf("Error refreshing: %v", err)
+	}
+	if _, err := cache.LatestBlockhash(ctx); err != nil {
+		t.Fatalf("Error getting blockhash: %v", err)
+	}
+	if client.calls["getLatestBlockhash"] != 3 {
+		t.Errorf("Expected 3 getLatestBlockhash calls, got %d", client.calls["getLatestBlockhash"])
+	}
+
+	// TxBuilder takes blockhashes from the provider.
+	client.blockHeight = 850
+	wallet := solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	mint := solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	before := client.calls["getLatestBlockhash"]
+	for i := 0; i < 2; i++ {
+		_, err := NewTxBuilder(client).
+			SetBlockhashProvider(cache).
+			SetFeePayer(wallet).
+			AddInstruction(NewCreate2022Instruction(wallet, wallet, mint).Build()).
+			Build(ctx)
+		if err != nil {
+			t.Fatalf("Error building transaction: %v", err)
+		}
+	}
+	if calls := client.calls["getLatestBlockhash"] - before; calls > 1 {
+		t.Errorf("Expected at most 1 getLatestBlockhash call for 2 builds, got %d", calls)
+	}
+}
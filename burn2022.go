@@ -0,0 +1,424 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	solana "github.com/gagliardetto/solana-go"
+	format "github.com/gagliardetto/solana-go/text/format"
+	treeout "github.com/gagliardetto/treeout"
+)
+
+// Burn2022 burns tokens from an account.
+type Burn2022 struct {
+	// The amount of tokens to burn.
+	Amount uint64
+
+	Account solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Mint    solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Owner   solana.PublicKey `bin:"-" borsh_skip:"true"`
+	// Signers are the signers of a multisig authority.
+	Signers []solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Account
+	// ··········· The account to burn from
+	//
+	// [1] = [WRITE] Mint
+	// ··········· The token mint
+	//
+	// [2] = [SIGNER] Owner
+	// ··········· The account's owner or delegate, or a multisig
+	//
+	// [3...] = [SIGNER] Signers
+	// ··········· M signer accounts when the authority is a multisig
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewBurn2022InstructionBuilder creates a new `Burn2022` instruction builder.
+func NewBurn2022InstructionBuilder() *Burn2022 {
+	nd := &Burn2022{}
+	return nd
+}
+
+func (inst *Burn2022) SetAmount(amount uint64) *Burn2022 {
+	inst.Amount = amount
+	return inst
+}
+
+func (inst *Burn2022) SetAccount(account solana.PublicKey) *Burn2022 {
+	inst.Account = account
+	return inst
+}
+
+func (inst *Burn2022) SetMint(mint solana.PublicKey) *Burn2022 {
+	inst.Mint = mint
+	return inst
+}
+
+// SetOwner sets the authority. Pass the signers when the authority is a multisig.
+func (inst *Burn2022) SetOwner(owner solana.PublicKey, multisigSigners ...solana.PublicKey) *Burn2022 {
+	inst.Owner = owner
+	inst.Signers = multisigSigners
+	return inst
+}
+
+func (inst Burn2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Account,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+		{
+			PublicKey:  inst.Mint,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+	}
+	keys = appendAuthority(keys, inst.Owner, inst.Signers)
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst Burn2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *Burn2022) Validate() error {
+	if inst.Account.IsZero() {
+		return errNotSet("Account")
+	}
+	if inst.Mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	if inst.Owner.IsZero() {
+		return errNotSet("Owner")
+	}
+	if err := validateMultisigSigners(inst.Signers); err != nil {
+		return err
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+// ValidateOnChain validates the instruction and checks its accounts on
+// chain; see the package function ValidateOnChain.
+func (inst *Burn2022) ValidateOnChain(ctx context.Context, client RPCClient) error {
+	return ValidateOnChain(ctx, client, inst)
+}
+
+// ValidateStrict validates the instruction and checks its values against
+// each other; see the package function ValidateStrict.
+func (inst *Burn2022) ValidateStrict() error {
+	return ValidateStrict(inst)
+}
+
+func (inst *Burn2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("Burn2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=1]").ParentFunc(func(paramsBranch treeout.Branches) {
+						paramsBranch.Child(format.Param("Amount", inst.Amount))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("account", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(format.Meta("   mint", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(format.Meta("  owner", inst.AccountMetaSlice.Get(2)))
+						encodeRemainingAccounts(accountsBranch, "signer", inst.AccountMetaSlice, 3)
+					})
+				})
+		})
+}
+
+func (inst Burn2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	return encoder.WriteUint64(inst.Amount, bin.LE)
+}
+
+func (inst *Burn2022) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	if inst.Amount, err = decoder.ReadUint64(bin.LE); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst Burn2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *Burn2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("Burn2022", accounts, 3); err != nil {
+		return err
+	}
+	inst.Account = accounts[0].PublicKey
+	inst.Mint = accounts[1].PublicKey
+	inst.Owner = accounts[2].PublicKey
+	inst.Signers = pubkeysOf(accounts[3:])
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst Burn2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *Burn2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewBurn2022Instruction creates a new `Burn2022` instruction.
+func NewBurn2022Instruction(
+	amount uint64,
+	account solana.PublicKey,
+	mint solana.PublicKey,
+	owner solana.PublicKey,
+	multisigSigners ...solana.PublicKey,
+) *Burn2022 {
+	return NewBurn2022InstructionBuilder().
+		SetAmount(amount).
+		SetAccount(account).
+		SetMint(mint).
+		SetOwner(owner, multisigSigners...)
+}
+
+// BurnChecked2022 burns tokens from an account, checking the number of decimals.
+type BurnChecked2022 struct {
+	// The amount of tokens to burn.
+	Amount uint64
+
+	// Expected number of base 10 digits to the right of the decimal place.
+	Decimals uint8
+
+	Account solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Mint    solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Owner   solana.PublicKey `bin:"-" borsh_skip:"true"`
+	// Signers are the signers of a multisig authority.
+	Signers []solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Account
+	// ··········· The account to burn from
+	//
+	// [1] = [WRITE] Mint
+	// ··········· The token mint
+	//
+	// [2] = [SIGNER] Owner
+	// ··········· The account's owner or delegate, or a multisig
+	//
+	// [3...] = [SIGNER] Signers
+	// ··········· M signer accounts when the authority is a multisig
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewBurnChecked2022InstructionBuilder creates a new `BurnChecked2022` instruction builder.
+func NewBurnChecked2022InstructionBuilder() *BurnChecked2022 {
+	nd := &BurnChecked2022{}
+	return nd
+}
+
+func (inst *BurnChecked2022) SetAmount(amount uint64) *BurnChecked2022 {
+	inst.Amount = amount
+	return inst
+}
+
+func (inst *BurnChecked2022) SetDecimals(decimals uint8) *BurnChecked2022 {
+	inst.Decimals = decimals
+	return inst
+}
+
+func (inst *BurnChecked2022) SetAccount(account solana.PublicKey) *BurnChecked2022 {
+	inst.Account = account
+	return inst
+}
+
+func (inst *BurnChecked2022) SetMint(mint solana.PublicKey) *BurnChecked2022 {
+	inst.Mint = mint
+	return inst
+}
+
+// SetOwner sets the authority. Pass the signers when the authority is a multisig.
+func (inst *BurnChecked2022) SetOwner(owner solana.PublicKey, multisigSigners ...solana.PublicKey) *BurnChecked2022 {
+	inst.Owner = owner
+	inst.Signers = multisigSigners
+	return inst
+}
+
+func (inst BurnChecked2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Account,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+		{
+			PublicKey:  inst.Mint,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+	}
+	keys = appendAuthority(keys, inst.Owner, inst.Signers)
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst BurnChecked2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *BurnChecked2022) Validate() error {
+	if inst.Account.IsZero() {
+		return errNotSet("Account")
+	}
+	if inst.Mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	if inst.Owner.IsZero() {
+		return errNotSet("Owner")
+	}
+	if err := validateMultisigSigners(inst.Signers); err != nil {
+		return err
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+// ValidateOnChain validates the instruction and checks its accounts on
+// chain; see the package function ValidateOnChain.
+func (inst *BurnChecked2022) ValidateOnChain(ctx context.Context, client RPCClient) error {
+	return ValidateOnChain(ctx, client, inst)
+}
+
+// ValidateStrict validates the instruction and checks its values against
+// each other; see the package function ValidateStrict.
+func (inst *BurnChecked2022) ValidateStrict() error {
+	return ValidateStrict(inst)
+}
+
+func (inst *BurnChecked2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("BurnChecked2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=2]").ParentFunc(func(paramsBranch treeout.Branches) {
+						paramsBranch.Child(format.Param("  Amount", inst.Amount))
+						paramsBranch.Child(format.Param("Decimals", inst.Decimals))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("account", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(format.Meta("   mint", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(format.Meta("  owner", inst.AccountMetaSlice.Get(2)))
+						encodeRemainingAccounts(accountsBranch, "signer", inst.AccountMetaSlice, 3)
+					})
+				})
+		})
+}
+
+func (inst BurnChecked2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	if err := encoder.WriteUint64(inst.Amount, bin.LE); err != nil {
+		return err
+	}
+	return encoder.WriteUint8(inst.Decimals)
+}
+
+func (inst *BurnChecked2022) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	if inst.Amount, err = decoder.ReadUint64(bin.LE); err != nil {
+		return err
+	}
+	if inst.Decimals, err = decoder.ReadUint8(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst BurnChecked2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *BurnChecked2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("BurnChecked2022", accounts, 3); err != nil {
+		return err
+	}
+	inst.Account = accounts[0].PublicKey
+	inst.Mint = accounts[1].PublicKey
+	inst.Owner = accounts[2].PublicKey
+	inst.Signers = pubkeysOf(accounts[3:])
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst BurnChecked2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *BurnChecked2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewBurnChecked2022Instruction creates a new `BurnChecked2022` instruction.
+func NewBurnChecked2022Instruction(
+	amount uint64,
+	decimals uint8,
+	account solana.PublicKey,
+	mint solana.PublicKey,
+	owner solana.PublicKey,
+	multisigSigners ...solana.PublicKey,
+) *BurnChecked2022 {
+	return NewBurnChecked2022InstructionBuilder().
+		SetAmount(amount).
+		SetDecimals(decimals).
+		SetAccount(account).
+		SetMint(mint).
+		SetOwner(owner, multisigSigners...)
+}
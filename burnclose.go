@@ -0,0 +1,142 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"fmt"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// BurnAndCloseBuilder empties a token account and closes it: it harvests
+// withheld transfer fees to the mint when the account has a
+// TransferFeeAmount extension, since the program refuses to close an
+// account holding them, burns the whole balance with BurnChecked, and
+// closes the account, returning its rent to Destination.
+type BurnAndCloseBuilder struct {
+	Account solana.PublicKey
+	// Decoded is the current state of Account.
+	Decoded     *TokenAccount
+	Decimals    uint8
+	Destination solana.PublicKey
+	// Owner burns the tokens and closes the account, so it must be both
+	// the owner and the close authority of the account.
+	Owner solana.PublicKey
+	// Signers are the signers of a multisig owner.
+	Signers []solana.PublicKey
+}
+
+// NewBurnAndCloseBuilder creates a BurnAndCloseBuilder for account, whose
+// decoded state is decoded and whose mint has decimals decimals.
+func NewBurnAndCloseBuilder(
+	account solana.PublicKey,
+	decoded *TokenAccount,
+	decimals uint8,
+	destination solana.PublicKey,
+	owner solana.PublicKey,
+	multisigSigners ...solana.PublicKey,
+) *BurnAndCloseBuilder {
+	return &BurnAndCloseBuilder{
+		Account:     account,
+		Decoded:     decoded,
+		Decimals:    decimals,
+		Destination: destination,
+		Owner:       owner,
+		Signers:     multisigSigners,
+	}
+}
+
+// FetchBurnAndCloseBuilder fetches account and its mint at commitment and
+// returns a BurnAndCloseBuilder for them.
+func FetchBurnAndCloseBuilder(
+	ctx context.Context,
+	client RPCClient,
+	account solana.PublicKey,
+	destination solana.PublicKey,
+	owner solana.PublicKey,
+	commitment rpc.CommitmentType,
+	multisigSigners ...solana.PublicKey,
+) (*BurnAndCloseBuilder, error) {
+	decoded, err := FetchTokenAccount(ctx, client, account, commitment)
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching token account %s: %w", account, err)
+	}
+	mint, err := FetchMint(ctx, client, decoded.Mint, commitment)
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching mint %s: %w", decoded.Mint, err)
+	}
+	return NewBurnAndCloseBuilder(account, decoded, mint.Decimals, destination, owner, multisigSigners...), nil
+}
+
+// Validate checks that the account can be burned and closed by Owner: it
+// is not frozen, not a native account, and Owner is its owner and close
+// authority.
+func (b *BurnAndCloseBuilder) Validate() error {
+	if b.Account.IsZero() {
+		return errNotSet("Account")
+	}
+	if b.Decoded == nil {
+		return errNotSet("Decoded")
+	}
+	if b.Destination.IsZero() {
+		return errNotSet("Destination")
+	}
+	if b.Owner.IsZero() {
+		return errNotSet("Owner")
+	}
+	if b.Decoded.IsFrozen() {
+		return fmt.Errorf("%w: account %s is frozen", ErrAccountFrozen, b.Account)
+	}
+	if b.Decoded.IsNative != nil {
+		return fmt.Errorf("%w: account %s holds wrapped SOL, close it instead", ErrNativeNotSupported, b.Account)
+	}
+	if !b.Decoded.Owner.Equals(b.Owner) {
+		return fmt.Errorf("%w: account %s is owned by %s, not %s", ErrOwnerMismatch, b.Account, b.Decoded.Owner, b.Owner)
+	}
+	if b.Decoded.CloseAuthority != nil && !b.Decoded.CloseAuthority.Equals(b.Owner) {
+		return fmt.Errorf("%w: account %s is closed by %s, not %s", ErrOwnerMismatch, b.Account, *b.Decoded.CloseAuthority, b.Owner)
+	}
+	return validateMultisigSigners(b.Signers)
+}
+
+// Build returns the harvest, when the account has a TransferFeeAmount
+// extension, the burn, when it holds tokens, and the close, in that order.
+func (b *BurnAndCloseBuilder) Build() ([]solana.Instruction, error) {
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	var instructions []solana.Instruction
+	if _, ok := b.Decoded.Extension(ExtensionTransferFeeAmount); ok {
+		harvest, err := NewHarvestWithheldTokensToMint2022Instruction(b.Decoded.Mint, b.Account).ValidateAndBuild()
+		if err != nil {
+			return nil, err
+		}
+		instructions = append(instructions, harvest)
+	}
+	if b.Decoded.Amount > 0 {
+		burn, err := NewBurnChecked2022Instruction(b.Decoded.Amount, b.Decimals, b.Account, b.Decoded.Mint, b.Owner, b.Signers...).ValidateAndBuild()
+		if err != nil {
+			return nil, err
+		}
+		instructions = append(instructions, burn)
+	}
+	closeAccount, err := NewClose2022Instruction(b.Account, b.Destination, b.Owner, b.Signers...).ValidateAndBuild()
+	if err != nil {
+		return nil, err
+	}
+	return append(instructions, closeAccount), nil
+}
@@ -0,0 +1,62 @@
+package token2022
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestBurnAndClose(t *testing.T) {
+	var (
+		wallet      = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		account     = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		other       = solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR")
+	)
+	client := newMockRPC()
+	client.setAccount(mint, solana.Token2022ProgramID, encodeMint(&wallet, 1000, 6))
+	client.setAccount(account, solana.Token2022ProgramID, encodeTokenAccount(mint, wallet, 250,
+		Extension{Type: ExtensionTransferFeeAmount, Data: make([]byte, 8)}))
+
+	builder, err := FetchBurnAndCloseBuilder(context.Background(), client, account, destination, wallet, rpc.CommitmentConfirmed)
+	if err != nil {
+		t.Fatalf("FetchBurnAndCloseBuilder: %v", err)
+	}
+	instructions, err := builder.Build()
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	expected := []string{"HarvestWithheldTokensToMint", "BurnChecked", "Close"}
+	if len(instructions) != len(expected) {
+		t.Fatalf("Expected %d instructions, got %d", len(expected), len(instructions))
+	}
+	for i, inst := range instructions {
+		data, _ := inst.Data()
+		typed, err := DecodeInstruction(inst.Accounts(), data)
+		if err != nil {
+			t.Fatalf("DecodeInstruction: %v", err)
+		}
+		if name := builderName(typed); name != expected[i] {
+			t.Errorf("Expected instruction %d to be %s, got %s", i, expected[i], name)
+		}
+		if burn, ok := typed.(*BurnChecked2022); ok && (burn.Amount != 250 || burn.Decimals != 6) {
+			t.Errorf("Expected a burn of 250 with 6 decimals, got %d with %d", burn.Amount, burn.Decimals)
+		}
+	}
+
+	empty := NewBurnAndCloseBuilder(account, &TokenAccount{Mint: mint, Owner: wallet, State: AccountStateInitialized}, 6, destination, wallet)
+	if instructions, err := empty.Build(); err != nil || len(instructions) != 1 {
+		t.Errorf("Expected only a close for an empty account, got %d, %v", len(instructions), err)
+	}
+	if _, err := NewBurnAndCloseBuilder(account, builder.Decoded, 6, destination, other).Build(); !errors.Is(err, ErrOwnerMismatch) {
+		t.Errorf("Expected ErrOwnerMismatch, got %v", err)
+	}
+	builder.Decoded.State = AccountStateFrozen
+	if _, err := builder.Build(); !errors.Is(err, ErrAccountFrozen) {
+		t.Errorf("Expected ErrAccountFrozen, got %v", err)
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// AccountCache caches decoded mints and token accounts by address.
+//
+// Entries expire after a TTL, can be invalidated individually, and are
+// discarded when they were read at a slot older than the minimum slot set
+// with InvalidateBefore, which lets callers drop everything that predates
+// a transaction they submitted. Concurrent lookups of the same address
+// share a single RPC request.
+//
+// Decoded values are shared between callers and must not be modified.
+//
+// AccountCache is safe for concurrent use.
+type AccountCache struct {
+	client     RPCClient
+	commitment rpc.CommitmentType
+	ttl        time.Duration
+
+	mu       sync.Mutex
+	entries  map[solana.PublicKey]*cacheEntry
+	inflight map[solana.PublicKey]*inflightFetch
+	minSlot  uint64
+}
+
+type cacheEntry struct {
+	data      []byte
+	slot      uint64
+	fetchedAt time.Time
+	value     interface{}
+}
+
+type inflightFetch struct {
+	done chan struct{}
+	data []byte
+	slot uint64
+	err  error
+}
+
+// NewAccountCache creates a cache that reads at confirmed commitment and
+// keeps entries for ten seconds.
+func NewAccountCache(client RPCClient) *AccountCache {
+	return &AccountCache{
+		client:     client,
+		commitment: rpc.CommitmentConfirmed,
+		ttl:        10 * time.Second,
+		entries:    map[solana.PublicKey]*cacheEntry{},
+		inflight:   map[solana.PublicKey]*inflightFetch{},
+	}
+}
+
+func (c *AccountCache) SetCommitment(commitment rpc.CommitmentType) *AccountCache {
+	c.commitment = commitment
+	return c
+}
+
+func (c *AccountCache) SetTTL(ttl time.Duration) *AccountCache {
+	c.ttl = ttl
+	return c
+}
+
+// Mint returns the decoded mint, fetching it when it is not cached.
+func (c *AccountCache) Mint(ctx context.Context, mint solana.PublicKey) (*Mint, error) {
+	value, err := c.get(ctx, mint, func(data []byte) (interface{}, error) {
+		return DecodeMint(data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	decoded, ok := value.(*Mint)
+	if !ok {
+		return nil, fmt.Errorf("account %s is cached as a %T, not a mint", mint, value)
+	}
+	return decoded, nil
+}
+
+// TokenAccount returns the decoded token account, fetching it when it is
+// not cached.
+func (c *AccountCache) TokenAccount(ctx context.Context, account solana.PublicKey) (*TokenAccount, error) {
+	value, err := c.get(ctx, account, func(data []byte) (interface{}, error) {
+		return DecodeTokenAccount(data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	decoded, ok := value.(*TokenAccount)
+	if !ok {
+		return nil, fmt.Errorf("account %s is cached as a %T, not a token account", account, value)
+	}
+	return decoded, nil
+}
+
+// Invalidate drops the given addresses from the cache.
+func (c *AccountCache) Invalidate(accounts ...solana.PublicKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, account := range accounts {
+		delete(c.entries, account)
+	}
+}
+
+// InvalidateBefore drops every entry read before slot and requests later
+// fetches with slot as their minContextSlot. Call it with the slot at
+// which a transaction landed to make later reads observe its effects.
+func (c *AccountCache) InvalidateBefore(slot uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if slot <= c.minSlot {
+		return
+	}
+	c.minSlot = slot
+	for account, entry := range c.entries {
+		if entry.slot < slot {
+			delete(c.entries, account)
+		}
+	}
+}
+
+// Len returns the number of cached entries.
+func (c *AccountCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+func (c *AccountCache) get(ctx context.Context, account solana.PublicKey, decode func([]byte) (interface{}, error)) (interface{}, error) {
+	c.mu.Lock()
+	if entry, ok := c.entries[account]; ok && c.fresh(entry) {
+		c.mu.Unlock()
+		if entry.value != nil {
+			return entry.value, nil
+		}
+		return decode(entry.data)
+	}
+	fetch, ok := c.inflight[account]
+	if !ok {
+		fetch = &inflightFetch{done: make(chan struct{})}
+		c.inflight[account] = fetch
+		go c.fetch(account, fetch)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-fetch.done:
+	}
+	if fetch.err != nil {
+		return nil, fetch.err
+	}
+	value, err := decode(fetch.data)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[account]; ok && entry.slot == fetch.slot && entry.value == nil {
+		entry.value = value
+	}
+	return value, nil
+}
+
+// fetch runs detached from the caller's context so that a cancelled
+// caller does not fail the other callers waiting on the same request.
+func (c *AccountCache) fetch(account solana.PublicKey, fetch *inflightFetch) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	c.mu.Lock()
+	opts := &FetchOpts{Commitment: c.commitment}
+	if c.minSlot > 0 {
+		minSlot := c.minSlot
+		opts.MinContextSlot = &minSlot
+	}
+	c.mu.Unlock()
+
+	out, err := fetchTokenProgramAccount(ctx, c.client, account, opts)
+	if err == nil {
+		fetch.data = out.Value.Data.GetBinary()
+		fetch.slot = out.Context.Slot
+	}
+	fetch.err = err
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.inflight, account)
+	if err == nil && fetch.slot >= c.minSlot {
+		c.entries[account] = &cacheEntry{
+			data:      fetch.data,
+			slot:      fetch.slot,
+			fetchedAt: time.Now(),
+		}
+	}
+	close(fetch.done)
+}
+
+// fresh reports whether entry can be served. Callers must hold c.mu.
+func (c *AccountCache) fresh(entry *cacheEntry) bool {
+	return entry.slot >= c.minSlot && time.Since(entry.fetchedAt) < c.ttl
+}
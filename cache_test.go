@@ -0,0 +1,129 @@
+package token2022
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// gatedRPC blocks getAccountInfo until release is closed.
+type gatedRPC struct {
+	*mockRPC
+	release chan struct{}
+	fetches atomic.Int32
+}
+
+func (g *gatedRPC) GetAccountInfoWithOpts(ctx context.Context, account solana.PublicKey, opts *rpc.GetAccountInfoOpts) (*rpc.GetAccountInfoResult, error) {
+	g.fetches.Add(1)
+	<-g.release
+	return g.mockRPC.GetAccountInfoWithOpts(ctx, account, opts)
+}
+
+func TestAccountCacheCoalescesRequests(t *testing.T) {
+
+	var (
+		mint   = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		client = &gatedRPC{mockRPC: newMockRPC(), release: make(chan struct{})}
+		cache  = NewAccountCache(client)
+	)
+	client.setAccount(mint, solana.Token2022ProgramID, encodeMint(nil, 500, 6))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			decoded, err := cache.Mint(context.Background(), mint)
+			if err == nil && decoded.Supply != 500 {
+				t.Errorf("unexpected supply %d", decoded.Supply)
+			}
+			errs <- err
+		}()
+	}
+	// Let the callers pile up on the in-flight request.
+	time.Sleep(20 * time.Millisecond)
+	close(client.release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Mint: %v", err)
+		}
+	}
+	if n := client.fetches.Load(); n != 1 {
+		t.Errorf("expected a single fetch, got %d", n)
+	}
+	if _, err := cache.Mint(context.Background(), mint); err != nil {
+		t.Fatalf("Mint: %v", err)
+	}
+	if n := client.fetches.Load(); n != 1 {
+		t.Errorf("expected cached mint to be served, got %d fetches", n)
+	}
+}
+
+func TestAccountCacheInvalidation(t *testing.T) {
+
+	var (
+		wallet  = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint    = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		account = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		client  = newMockRPC()
+		cache   = NewAccountCache(client)
+		ctx     = context.Background()
+	)
+	client.slot = 100
+	client.setAccount(account, solana.Token2022ProgramID, encodeTokenAccount(mint, wallet, 10))
+
+	if _, err := cache.TokenAccount(ctx, account); err != nil {
+		t.Fatalf("TokenAccount: %v", err)
+	}
+	client.setAccount(account, solana.Token2022ProgramID, encodeTokenAccount(mint, wallet, 20))
+
+	// Slot-based invalidation.
+	cache.InvalidateBefore(101)
+	if cache.Len() != 0 {
+		t.Fatalf("expected entry read at slot 100 to be dropped")
+	}
+	// Later fetches require the node to have reached slot 101.
+	if _, err := cache.TokenAccount(ctx, account); err == nil {
+		t.Fatal("expected a node behind the minimum slot to be rejected")
+	}
+
+	client.slot = 101
+	decoded, err := cache.TokenAccount(ctx, account)
+	if err != nil {
+		t.Fatalf("TokenAccount: %v", err)
+	}
+	if decoded.Amount != 20 || cache.Len() != 1 {
+		t.Fatalf("expected fresh cached read, got amount %d and %d entries", decoded.Amount, cache.Len())
+	}
+	cache.Invalidate(account)
+	if cache.Len() != 0 {
+		t.Errorf("expected Invalidate to drop the entry")
+	}
+
+	// TTL expiry.
+	cache.SetTTL(time.Nanosecond)
+	before := client.calls["getAccountInfo"]
+	_, _ = cache.TokenAccount(ctx, account)
+	_, _ = cache.TokenAccount(ctx, account)
+	if client.calls["getAccountInfo"]-before != 2 {
+		t.Errorf("expected expired entries to be refetched")
+	}
+
+	// A token account is not a mint.
+	cache.SetTTL(time.Minute)
+	if _, err := cache.TokenAccount(ctx, account); err != nil {
+		t.Fatalf("TokenAccount: %v", err)
+	}
+	if _, err := cache.Mint(ctx, account); err == nil {
+		t.Error("expected error reading a token account as a mint")
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// RPCClient is the subset of the Solana JSON-RPC API used by this package.
+// *rpc.Client satisfies it; tests and wrappers can provide their own.
+type RPCClient interface {
+	GetLatestBlockhash(ctx context.Context, commitment rpc.CommitmentType) (*rpc.GetLatestBlockhashResult, error)
+	GetBlockHeight(ctx context.Context, commitment rpc.CommitmentType) (uint64, error)
+	GetAccountInfoWithOpts(ctx context.Context, account solana.PublicKey, opts *rpc.GetAccountInfoOpts) (*rpc.GetAccountInfoResult, error)
+	SendTransactionWithOpts(ctx context.Context, transaction *solana.Transaction, opts rpc.TransactionOpts) (solana.Signature, error)
+	GetSignatureStatuses(ctx context.Context, searchTransactionHistory bool, transactionSignatures ...solana.Signature) (*rpc.GetSignatureStatusesResult, error)
+}
+
+var _ RPCClient = (*rpc.Client)(nil)
+
+// FetchOpts controls the consistency of account reads.
+//
+// Set MinContextSlot to the slot at which a submitted transaction landed
+// (SendResult.Slot or Confirmation.Slot) to read your own writes: nodes
+// that have not reached that slot yet reject the request instead of
+// returning stale data, and ThrottledClient retries such rejections.
+type FetchOpts struct {
+	Commitment     rpc.CommitmentType
+	MinContextSlot *uint64
+}
+
+// FetchOptsAtSlot returns options that require a node to have processed
+// at least slot at the given commitment.
+func FetchOptsAtSlot(commitment rpc.CommitmentType, slot uint64) *FetchOpts {
+	return &FetchOpts{Commitment: commitment, MinContextSlot: &slot}
+}
+
+func (o *FetchOpts) accountInfoOpts() *rpc.GetAccountInfoOpts {
+	opts := &rpc.GetAccountInfoOpts{Encoding: solana.EncodingBase64}
+	if o != nil {
+		opts.Commitment = o.Commitment
+		opts.MinContextSlot = o.MinContextSlot
+	}
+	return opts
+}
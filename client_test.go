@@ -0,0 +1,171 @@
+package token2022
+
+import (
+	"bytes"
+	"context"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+// mockRPC is an in-memory RPCClient used by the unit tests.
+type mockRPC struct {
+	blockhash            solana.Hash
+	lastValidBlockHeight uint64
+	blockHeight          uint64
+	slot                 uint64
+	epoch                uint64
+	accounts             map[solana.PublicKey]*rpc.Account
+	statuses             map[solana.Signature]*rpc.SignatureStatusesResult
+	sent                 []*solana.Transaction
+	sentOpts             []rpc.TransactionOpts
+	onSend               func(tx *solana.Transaction)
+	calls                map[string]int
+	// err, when set, is returned by every call.
+	err error
+}
+
+func newMockRPC() *mockRPC {
+	return &mockRPC{
+		blockhash:            solana.Hash(solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")),
+		lastValidBlockHeight: 1000,
+		blockHeight:          850,
+		accounts:             map[solana.PublicKey]*rpc.Account{},
+		statuses:             map[solana.Signature]*rpc.SignatureStatusesResult{},
+		calls:                map[string]int{},
+	}
+}
+
+func (m *mockRPC) setAccount(pubkey solana.PublicKey, owner solana.PublicKey, data []byte) {
+	m.accounts[pubkey] = &rpc.Account{
+		Lamports: 1_000_000,
+		Owner:    owner,
+		Data:     rpc.DataBytesOrJSONFromBytes(data),
+	}
+}
+
+func (m *mockRPC) GetLatestBlockhash(ctx context.Context, commitment rpc.CommitmentType) (*rpc.GetLatestBlockhashResult, error) {
+	m.calls["getLatestBlockhash"]++
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &rpc.GetLatestBlockhashResult{
+		Value: &rpc.LatestBlockhashResult{
+			Blockhash:            m.blockhash,
+			LastValidBlockHeight: m.lastValidBlockHeight,
+		},
+	}, nil
+}
+
+func (m *mockRPC) GetEpochInfo(ctx context.Context, commitment rpc.CommitmentType) (*rpc.GetEpochInfoResult, error) {
+	m.calls["getEpochInfo"]++
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &rpc.GetEpochInfoResult{Epoch: m.epoch, AbsoluteSlot: m.slot}, nil
+}
+
+func (m *mockRPC) GetBlockHeight(ctx context.Context, commitment rpc.CommitmentType) (uint64, error) {
+	m.calls["getBlockHeight"]++
+	if m.err != nil {
+		return 0, m.err
+	}
+	return m.blockHeight, nil
+}
+
+func (m *mockRPC) GetAccountInfoWithOpts(ctx context.Context, account solana.PublicKey, opts *rpc.GetAccountInfoOpts) (*rpc.GetAccountInfoResult, error) {
+	m.calls["getAccountInfo"]++
+	if m.err != nil {
+		return nil, m.err
+	}
+	if opts != nil && opts.MinContextSlot != nil && *opts.MinContextSlot > m.slot {
+		return nil, &jsonrpc.RPCError{Code: -32016, Message: "Minimum context slot has not been reached"}
+	}
+	acc, ok := m.accounts[account]
+	if !ok {
+		return nil, rpc.ErrNotFound
+	}
+	return &rpc.GetAccountInfoResult{RPCContext: rpc.RPCContext{Context: rpc.Context{Slot: m.slot}}, Value: acc}, nil
+}
+
+func (m *mockRPC) GetTokenAccountsByOwner(ctx context.Context, owner solana.PublicKey, conf *rpc.GetTokenAccountsConfig, opts *rpc.GetTokenAccountsOpts) (*rpc.GetTokenAccountsResult, error) {
+	m.calls["getTokenAccountsByOwner"]++
+	if m.err != nil {
+		return nil, m.err
+	}
+	out := &rpc.GetTokenAccountsResult{}
+	for pubkey, acc := range m.accounts {
+		if !acc.Owner.Equals(solana.Token2022ProgramID) {
+			continue
+		}
+		account, err := DecodeTokenAccount(acc.Data.GetBinary())
+		if err != nil || !account.Owner.Equals(owner) {
+			continue
+		}
+		out.Value = append(out.Value, &rpc.TokenAccount{Pubkey: pubkey, Account: *acc})
+	}
+	return out, nil
+}
+
+func (m *mockRPC) GetMultipleAccountsWithOpts(ctx context.Context, accounts []solana.PublicKey, opts *rpc.GetMultipleAccountsOpts) (*rpc.GetMultipleAccountsResult, error) {
+	m.calls["getMultipleAccounts"]++
+	if m.err != nil {
+		return nil, m.err
+	}
+	out := &rpc.GetMultipleAccountsResult{Value: make([]*rpc.Account, len(accounts))}
+	for i, account := range accounts {
+		out.Value[i] = m.accounts[account]
+	}
+	return out, nil
+}
+
+func (m *mockRPC) GetProgramAccountsWithOpts(ctx context.Context, program solana.PublicKey, opts *rpc.GetProgramAccountsOpts) (rpc.GetProgramAccountsResult, error) {
+	m.calls["getProgramAccounts"]++
+	if m.err != nil {
+		return nil, m.err
+	}
+	var out rpc.GetProgramAccountsResult
+accounts:
+	for pubkey, acc := range m.accounts {
+		data := acc.Data.GetBinary()
+		if !acc.Owner.Equals(program) {
+			continue
+		}
+		for _, filter := range opts.Filters {
+			if filter.DataSize != 0 && uint64(len(data)) != filter.DataSize {
+				continue accounts
+			}
+			if memcmp := filter.Memcmp; memcmp != nil && !bytes.HasPrefix(data[min(memcmp.Offset, uint64(len(data))):], memcmp.Bytes) {
+				continue accounts
+			}
+		}
+		out = append(out, &rpc.KeyedAccount{Pubkey: pubkey, Account: acc})
+	}
+	return out, nil
+}
+
+func (m *mockRPC) SendTransactionWithOpts(ctx context.Context, transaction *solana.Transaction, opts rpc.TransactionOpts) (solana.Signature, error) {
+	m.calls["sendTransaction"]++
+	if m.err != nil {
+		return solana.Signature{}, m.err
+	}
+	m.sent = append(m.sent, transaction)
+	m.sentOpts = append(m.sentOpts, opts)
+	if m.onSend != nil {
+		m.onSend(transaction)
+	}
+	return transaction.Signatures[0], nil
+}
+
+func (m *mockRPC) GetSignatureStatuses(ctx context.Context, searchTransactionHistory bool, transactionSignatures ...solana.Signature) (*rpc.GetSignatureStatusesResult, error) {
+	m.calls["getSignatureStatuses"]++
+	if m.err != nil {
+		return nil, m.err
+	}
+	out := &rpc.GetSignatureStatusesResult{}
+	for _, sig := range transactionSignatures {
+		out.Value = append(out.Value, m.statuses[sig])
+	}
+	return out, nil
+}
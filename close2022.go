@@ -0,0 +1,364 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	solana "github.com/gagliardetto/solana-go"
+	format "github.com/gagliardetto/solana-go/text/format"
+	treeout "github.com/gagliardetto/treeout"
+)
+
+// Close2022 closes a token account, or a mint with the MintCloseAuthority
+// extension, and transfers its lamports to the destination.
+type Close2022 struct {
+	Account     solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Destination solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Owner       solana.PublicKey `bin:"-" borsh_skip:"true"`
+	// Signers are the signers of a multisig authority.
+	Signers []solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Account
+	// ··········· The account to close
+	//
+	// [1] = [WRITE] Destination
+	// ··········· The destination of the remaining lamports
+	//
+	// [2] = [SIGNER] Owner
+	// ··········· The account's owner or close authority, or a multisig
+	//
+	// [3...] = [SIGNER] Signers
+	// ··········· M signer accounts when the authority is a multisig
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewClose2022InstructionBuilder creates a new `Close2022` instruction builder.
+func NewClose2022InstructionBuilder() *Close2022 {
+	nd := &Close2022{}
+	return nd
+}
+
+func (inst *Close2022) SetAccount(account solana.PublicKey) *Close2022 {
+	inst.Account = account
+	return inst
+}
+
+func (inst *Close2022) SetDestination(destination solana.PublicKey) *Close2022 {
+	inst.Destination = destination
+	return inst
+}
+
+// SetOwner sets the authority. Pass the signers when the authority is a multisig.
+func (inst *Close2022) SetOwner(owner solana.PublicKey, multisigSigners ...solana.PublicKey) *Close2022 {
+	inst.Owner = owner
+	inst.Signers = multisigSigners
+	return inst
+}
+
+func (inst Close2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Account,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+		{
+			PublicKey:  inst.Destination,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+	}
+	keys = appendAuthority(keys, inst.Owner, inst.Signers)
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst Close2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *Close2022) Validate() error {
+	if inst.Account.IsZero() {
+		return errNotSet("Account")
+	}
+	if inst.Destination.IsZero() {
+		return errNotSet("Destination")
+	}
+	if inst.Owner.IsZero() {
+		return errNotSet("Owner")
+	}
+	if err := validateMultisigSigners(inst.Signers); err != nil {
+		return err
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+// ValidateOnChain validates the instruction and checks on chain that
+// Owner can close the account: a token account must be empty and Owner its
+// close authority, or its owner when none is set; a mint must have no
+// supply and a MintCloseAuthority extension naming Owner. See the package
+// function ValidateOnChain.
+func (inst *Close2022) ValidateOnChain(ctx context.Context, client RPCClient) error {
+	return ValidateOnChain(ctx, client, inst)
+}
+
+func (inst *Close2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("Close2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=0]").ParentFunc(func(paramsBranch treeout.Branches) {})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("    account", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(format.Meta("destination", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(format.Meta("      owner", inst.AccountMetaSlice.Get(2)))
+						encodeRemainingAccounts(accountsBranch, "signer", inst.AccountMetaSlice, 3)
+					})
+				})
+		})
+}
+
+func (inst Close2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	return nil
+}
+
+func (inst *Close2022) UnmarshalWithDecoder(decoder *bin.Decoder) error {
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst Close2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *Close2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("Close2022", accounts, 3); err != nil {
+		return err
+	}
+	inst.Account = accounts[0].PublicKey
+	inst.Destination = accounts[1].PublicKey
+	inst.Owner = accounts[2].PublicKey
+	inst.Signers = pubkeysOf(accounts[3:])
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst Close2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *Close2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewClose2022Instruction creates a new `Close2022` instruction.
+func NewClose2022Instruction(
+	account solana.PublicKey,
+	destination solana.PublicKey,
+	owner solana.PublicKey,
+	multisigSigners ...solana.PublicKey,
+) *Close2022 {
+	return NewClose2022InstructionBuilder().
+		SetAccount(account).
+		SetDestination(destination).
+		SetOwner(owner, multisigSigners...)
+}
+
+// WithdrawExcessLamports2022 withdraws lamports above the rent-exempt minimum from a mint,
+// token account or multisig.
+type WithdrawExcessLamports2022 struct {
+	Source      solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Destination solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Authority   solana.PublicKey `bin:"-" borsh_skip:"true"`
+	// Signers are the signers of a multisig authority.
+	Signers []solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Source
+	// ··········· The account holding excess lamports
+	//
+	// [1] = [WRITE] Destination
+	// ··········· The destination of the excess lamports
+	//
+	// [2] = [SIGNER] Authority
+	// ··········· The source's owner or mint authority, or a multisig
+	//
+	// [3...] = [SIGNER] Signers
+	// ··········· M signer accounts when the authority is a multisig
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewWithdrawExcessLamports2022InstructionBuilder creates a new `WithdrawExcessLamports2022` instruction builder.
+func NewWithdrawExcessLamports2022InstructionBuilder() *WithdrawExcessLamports2022 {
+	nd := &WithdrawExcessLamports2022{}
+	return nd
+}
+
+func (inst *WithdrawExcessLamports2022) SetSource(source solana.PublicKey) *WithdrawExcessLamports2022 {
+	inst.Source = source
+	return inst
+}
+
+func (inst *WithdrawExcessLamports2022) SetDestination(destination solana.PublicKey) *WithdrawExcessLamports2022 {
+	inst.Destination = destination
+	return inst
+}
+
+// SetAuthority sets the authority. Pass the signers when the authority is a multisig.
+func (inst *WithdrawExcessLamports2022) SetAuthority(authority solana.PublicKey, multisigSigners ...solana.PublicKey) *WithdrawExcessLamports2022 {
+	inst.Authority = authority
+	inst.Signers = multisigSigners
+	return inst
+}
+
+func (inst WithdrawExcessLamports2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Source,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+		{
+			PublicKey:  inst.Destination,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+	}
+	keys = appendAuthority(keys, inst.Authority, inst.Signers)
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst WithdrawExcessLamports2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *WithdrawExcessLamports2022) Validate() error {
+	if inst.Source.IsZero() {
+		return errNotSet("Source")
+	}
+	if inst.Destination.IsZero() {
+		return errNotSet("Destination")
+	}
+	if inst.Authority.IsZero() {
+		return errNotSet("Authority")
+	}
+	if err := validateMultisigSigners(inst.Signers); err != nil {
+		return err
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+func (inst *WithdrawExcessLamports2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("WithdrawExcessLamports2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=0]").ParentFunc(func(paramsBranch treeout.Branches) {})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("     source", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(format.Meta("destination", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(format.Meta("  authority", inst.AccountMetaSlice.Get(2)))
+						encodeRemainingAccounts(accountsBranch, "signer", inst.AccountMetaSlice, 3)
+					})
+				})
+		})
+}
+
+func (inst WithdrawExcessLamports2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	return nil
+}
+
+func (inst *WithdrawExcessLamports2022) UnmarshalWithDecoder(decoder *bin.Decoder) error {
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst WithdrawExcessLamports2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *WithdrawExcessLamports2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("WithdrawExcessLamports2022", accounts, 3); err != nil {
+		return err
+	}
+	inst.Source = accounts[0].PublicKey
+	inst.Destination = accounts[1].PublicKey
+	inst.Authority = accounts[2].PublicKey
+	inst.Signers = pubkeysOf(accounts[3:])
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst WithdrawExcessLamports2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *WithdrawExcessLamports2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewWithdrawExcessLamports2022Instruction creates a new `WithdrawExcessLamports2022` instruction.
+func NewWithdrawExcessLamports2022Instruction(
+	source solana.PublicKey,
+	destination solana.PublicKey,
+	authority solana.PublicKey,
+	multisigSigners ...solana.PublicKey,
+) *WithdrawExcessLamports2022 {
+	return NewWithdrawExcessLamports2022InstructionBuilder().
+		SetSource(source).
+		SetDestination(destination).
+		SetAuthority(authority, multisigSigners...)
+}
@@ -0,0 +1,862 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dwmfan/token2022"
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// harvestBatchSize is the number of token accounts harvested per
+// transaction, which keeps transactions under the size limit.
+const harvestBatchSize = 20
+
+// mintExtension is a fixed-size mint extension initialized before
+// InitializeMint.
+type mintExtension struct {
+	length      int
+	instruction solana.Instruction
+}
+
+// mintSpace returns the account size of a mint with extensions.
+func mintSpace(extensions []mintExtension) int {
+	if len(extensions) == 0 {
+		return token2022.MintSize
+	}
+	space := token2022.AccountSize + 1
+	for _, extension := range extensions {
+		space += 4 + extension.length
+	}
+	return space
+}
+
+var createMintCommand = &command{
+	usage: "[flags]",
+	help:  "Create a mint, optionally with extensions and token metadata.",
+	run:   runCreateMint,
+}
+
+func runCreateMint(ctx context.Context, a *app, flags *flag.FlagSet, args []string) error {
+	var (
+		decimals          = flags.Uint("decimals", 9, "number of decimals")
+		mintKeypair       = flags.String("mint-keypair", "", "keypair file of the mint address (default: a new address)")
+		noFreeze          = flags.Bool("no-freeze-authority", false, "create the mint without a freeze authority")
+		closeAuthority    = flags.Bool("enable-close", false, "let the mint authority close the mint")
+		feeBasisPoints    = flags.Uint("transfer-fee-basis-points", 0, "transfer fee in basis points")
+		maximumFee        = flags.Uint64("transfer-fee-maximum", 0, "maximum transfer fee in base units")
+		nonTransferable   = flags.Bool("non-transferable", false, "make tokens non-transferable")
+		permanentDelegate = flags.Bool("enable-permanent-delegate", false, "make the mint authority a permanent delegate")
+		defaultFrozen     = flags.Bool("default-frozen", false, "freeze new token accounts by default")
+		interestRate      = flags.Int("interest-rate", 0, "interest rate in basis points")
+		name              = flags.String("name", "", "token metadata name, stored in the mint")
+		symbol            = flags.String("symbol", "", "token metadata symbol")
+		uri               = flags.String("uri", "", "token metadata URI")
+	)
+	if err := parseArgs(flags, args, 0); err != nil {
+		return err
+	}
+	if *decimals > 255 || *feeBasisPoints > 10_000 {
+		return errors.New("decimals or transfer fee out of range")
+	}
+	if *defaultFrozen && *noFreeze {
+		return errors.New("-default-frozen needs a freeze authority")
+	}
+	key, err := a.signer()
+	if err != nil {
+		return err
+	}
+	authority := key.PublicKey()
+
+	mintKey := solana.NewWallet().PrivateKey
+	if *mintKeypair != "" {
+		if mintKey, err = solana.PrivateKeyFromSolanaKeygenFile(*mintKeypair); err != nil {
+			return fmt.Errorf("error while reading mint keypair: %w", err)
+		}
+	}
+	mint := mintKey.PublicKey()
+
+	var extensions []mintExtension
+	if *closeAuthority {
+		extensions = append(extensions, mintExtension{32,
+			token2022.NewInitializeMintCloseAuthority2022Instruction(&authority, mint).Build()})
+	}
+	if *feeBasisPoints > 0 || *maximumFee > 0 {
+		extensions = append(extensions, mintExtension{108,
+			token2022.NewInitializeTransferFeeConfig2022Instruction(&authority, &authority, uint16(*feeBasisPoints), *maximumFee, mint).Build()})
+	}
+	if *nonTransferable {
+		extensions = append(extensions, mintExtension{0,
+			token2022.NewInitializeNonTransferableMint2022Instruction(mint).Build()})
+	}
+	if *permanentDelegate {
+		extensions = append(extensions, mintExtension{32,
+			token2022.NewInitializePermanentDelegate2022Instruction(authority, mint).Build()})
+	}
+	if *defaultFrozen {
+		extensions = append(extensions, mintExtension{1,
+			token2022.NewInitializeDefaultAccountState2022Instruction(token2022.AccountStateFrozen, mint).Build()})
+	}
+	if *interestRate != 0 {
+		extensions = append(extensions, mintExtension{52,
+			token2022.NewInitializeInterestBearingMint2022Instruction(&authority, int16(*interestRate), mint).Build()})
+	}
+	withMetadata := *name != "" || *symbol != "" || *uri != ""
+	if withMetadata {
+		extensions = append(extensions, mintExtension{64,
+			token2022.NewInitializeMetadataPointer2022Instruction(&authority, &mint, mint).Build()})
+	}
+
+	// The account is created with room for the fixed-size extensions;
+	// the program grows it for the metadata, so its rent is paid upfront.
+	space := mintSpace(extensions)
+	rentSpace := space
+	if withMetadata {
+		rentSpace += 4 + token2022.TokenMetadataLength(&token2022.TokenMetadata{UpdateAuthority: &authority, Mint: mint, Name: *name, Symbol: *symbol, URI: *uri})
+	}
+	lamports, err := a.client.GetMinimumBalanceForRentExemption(ctx, uint64(rentSpace), a.commitment)
+	if err != nil {
+		return fmt.Errorf("error while getting rent exemption: %w", err)
+	}
+
+	instructions := []solana.Instruction{
+		system.NewCreateAccountInstruction(lamports, uint64(space), solana.Token2022ProgramID, authority, mint).Build(),
+	}
+	for _, extension := range extensions {
+		instructions = append(instructions, extension.instruction)
+	}
+	freezeAuthority := &authority
+	if *noFreeze {
+		freezeAuthority = nil
+	}
+	instructions = append(instructions, token2022.NewInitializeMint2022Instruction(uint8(*decimals), authority, freezeAuthority, mint).Build())
+	if withMetadata {
+		instructions = append(instructions, token2022.NewInitializeTokenMetadataInstruction(mint, authority, authority, *name, *symbol, *uri))
+	}
+
+	fmt.Fprintf(a.out, "Mint: %s\n", mint)
+	return a.send(ctx, instructions, mintKey)
+}
+
+var createATACommand = &command{
+	usage: "[-owner OWNER] [-allow-owner-off-curve] MINT",
+	help:  "Create the associated token account of an owner, if it does not exist, and thaw it when the mint freezes new accounts and the keypair is the freeze authority.",
+	run:   runCreateATA,
+}
+
+func runCreateATA(ctx context.Context, a *app, flags *flag.FlagSet, args []string) error {
+	ownerFlag := flags.String("owner", "", "owner of the account (default: the keypair)")
+	offCurve := flags.Bool("allow-owner-off-curve", false, "allow an owner that is a program derived address")
+	if err := parseArgs(flags, args, 1); err != nil {
+		return err
+	}
+	mint, decoded, err := a.mint(ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	owner, err := a.owner(*ownerFlag)
+	if err != nil {
+		return err
+	}
+	key, err := a.signer()
+	if err != nil {
+		return err
+	}
+	plan, err := token2022.PlanAssociatedTokenAccount(mint, decoded, key.PublicKey(), owner, *offCurve, key.PublicKey())
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "Account: %s\n", plan.Address)
+	if err := a.send(ctx, plan.Instructions); err != nil {
+		return err
+	}
+	if plan.Thawed {
+		fmt.Fprintln(a.out, "Thawed the account")
+	} else if plan.NeedsThawBy != nil {
+		fmt.Fprintf(a.out, "The account is frozen until thawed by %s\n", plan.NeedsThawBy)
+	} else if plan.Frozen {
+		fmt.Fprintln(a.out, "The account is frozen and the mint has no freeze authority")
+	}
+	return nil
+}
+
+var transferCommand = &command{
+	usage: "[-fund-recipient] [-allow-owner-off-curve] [-memo TEXT] MINT AMOUNT RECIPIENT",
+	help:  "Transfer tokens from the keypair's associated account to a wallet or token account.",
+	run:   runTransfer,
+}
+
+func runTransfer(ctx context.Context, a *app, flags *flag.FlagSet, args []string) error {
+	fund := flags.Bool("fund-recipient", false, "create the recipient's associated account if needed")
+	memo := flags.String("memo", "", "memo to attach when the recipient's account requires one")
+	offCurve := flags.Bool("allow-owner-off-curve", false, "allow a recipient wallet that is a program derived address")
+	if err := parseArgs(flags, args, 3); err != nil {
+		return err
+	}
+	mint, decoded, err := a.mint(ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	amount, err := token2022.ParseAmount(flags.Arg(1), decoded.Decimals)
+	if err != nil {
+		return err
+	}
+	recipient, err := parsePubkey("recipient", flags.Arg(2))
+	if err != nil {
+		return err
+	}
+	key, err := a.signer()
+	if err != nil {
+		return err
+	}
+	source, _, err := token2022.FindAssociatedTokenAddress2022(key.PublicKey(), mint)
+	if err != nil {
+		return err
+	}
+
+	var instructions []solana.Instruction
+	destination := recipient
+	isAccount, err := a.isTokenAccount(ctx, recipient)
+	if err != nil {
+		return err
+	}
+	if !isAccount {
+		if destination, _, err = token2022.FindAssociatedTokenAddress2022Checked(recipient, mint, *offCurve); err != nil {
+			return err
+		}
+		if *fund {
+			instructions = append(instructions, token2022.NewCreate2022Instruction(key.PublicKey(), recipient, mint).SetIdempotent(true).Build())
+		}
+	}
+	plan, err := token2022.NewTransferPreflight(a.client).
+		SetCommitment(a.commitment).
+		SetMemo(*memo).
+		SetAuthority(key.PublicKey()).
+		PlanTransfer(ctx, mint, source, destination, amount)
+	if err != nil {
+		return err
+	}
+	if plan.Fee > 0 {
+		fee, err := decoded.AmountToUiAmount(plan.Fee, time.Now().Unix())
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(a.out, "Fee: %s\n", fee)
+	}
+	return a.send(ctx, append(instructions, plan.Instructions...))
+}
+
+var mintToCommand = &command{
+	usage: "[-owner OWNER] [-allow-owner-off-curve] [-fund-recipient] MINT AMOUNT",
+	help:  "Mint tokens to the associated account of an owner.",
+	run:   runMintTo,
+}
+
+func runMintTo(ctx context.Context, a *app, flags *flag.FlagSet, args []string) error {
+	ownerFlag := flags.String("owner", "", "owner of the receiving account (default: the keypair)")
+	fund := flags.Bool("fund-recipient", false, "create the associated account if needed")
+	offCurve := flags.Bool("allow-owner-off-curve", false, "allow an owner that is a program derived address")
+	if err := parseArgs(flags, args, 2); err != nil {
+		return err
+	}
+	mint, decoded, err := a.mint(ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	amount, err := token2022.ParseAmount(flags.Arg(1), decoded.Decimals)
+	if err != nil {
+		return err
+	}
+	owner, err := a.owner(*ownerFlag)
+	if err != nil {
+		return err
+	}
+	key, err := a.signer()
+	if err != nil {
+		return err
+	}
+	destination, _, err := token2022.FindAssociatedTokenAddress2022Checked(owner, mint, *offCurve)
+	if err != nil {
+		return err
+	}
+	var instructions []solana.Instruction
+	if *fund {
+		instructions = append(instructions, token2022.NewCreate2022Instruction(key.PublicKey(), owner, mint).SetIdempotent(true).Build())
+	}
+	instructions = append(instructions,
+		token2022.NewMintToChecked2022Instruction(amount, decoded.Decimals, mint, destination, key.PublicKey()).Build())
+	return a.send(ctx, instructions)
+}
+
+var mintToManyCommand = &command{
+	usage: "[-max-supply AMOUNT] [-concurrency N] [-report FILE [-resume]] [-dry-run] MINT CSV",
+	help:  "Mint tokens to the owners listed in a CSV file of owner,amount rows.",
+	run:   runMintToMany,
+}
+
+func runMintToMany(ctx context.Context, a *app, flags *flag.FlagSet, args []string) error {
+	maxSupply := flags.String("max-supply", "", "fail before sending if the supply would exceed this amount")
+	concurrency := flags.Int("concurrency", 4, "number of transactions in flight at once")
+	reportPath := flags.String("report", "", "write the outcome of every row to FILE as JSON")
+	resume := flags.Bool("resume", false, "mint only the rows of the -report file that are pending or failed")
+	dryRun := flags.Bool("dry-run", false, "print the planned transactions without sending them")
+	if err := parseArgs(flags, args, 2); err != nil {
+		return err
+	}
+	if *resume && *reportPath == "" {
+		return errors.New("-resume needs -report")
+	}
+	mint, decoded, err := a.mint(ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+	recipients, err := token2022.ParseRecipientsCSV(file, decoded.Decimals)
+	file.Close()
+	if err != nil {
+		return err
+	}
+	key, err := a.signer()
+	if err != nil {
+		return err
+	}
+
+	distributor := token2022.NewMintToMany(a.client, mint, decoded.Decimals, token2022.NewPrivateKeySigner(key)).
+		SetCommitment(a.commitment).
+		SetSender(a.sender()).
+		SetConcurrency(*concurrency).
+		SetProgress(func(result token2022.DistributionResult) {
+			fmt.Fprintf(a.out, "%-8s %s %s", result.Status, result.Wallet, token2022.FormatAmount(result.Amount, decoded.Decimals))
+			if !result.Signature.IsZero() {
+				fmt.Fprintf(a.out, " %s", result.Signature)
+			}
+			fmt.Fprintln(a.out)
+		})
+	if *maxSupply != "" {
+		limit, err := token2022.ParseAmount(*maxSupply, decoded.Decimals)
+		if err != nil {
+			return err
+		}
+		distributor.SetMaxSupply(limit)
+	}
+
+	if *dryRun {
+		transactions, err := distributor.Plan(ctx, recipients)
+		if err != nil {
+			return err
+		}
+		for i, instructions := range transactions {
+			fmt.Fprintf(a.out, "Transaction %d: %d instructions\n", i+1, len(instructions))
+		}
+		return nil
+	}
+
+	var report *token2022.DistributionReport
+	if *resume {
+		if report, err = readDistributionReport(*reportPath, recipients); err != nil {
+			return err
+		}
+		report, err = distributor.Resume(ctx, report)
+	} else {
+		report, err = distributor.Distribute(ctx, recipients)
+	}
+	if *reportPath != "" && report != nil {
+		if writeErr := writeJSONFile(*reportPath, report); writeErr != nil && err == nil {
+			err = writeErr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	paid := report.Count(token2022.DistributionPaid)
+	fmt.Fprintf(a.out, "Paid: %d, failed: %d, unknown: %d\n", paid,
+		report.Count(token2022.DistributionFailed), report.Count(token2022.DistributionUnknown))
+	if paid != len(report.Results) {
+		return fmt.Errorf("%d of %d rows not paid", len(report.Results)-paid, len(report.Results))
+	}
+	return nil
+}
+
+// readDistributionReport reads a report saved by mint-to-many and checks
+// that it was made for the same rows.
+func readDistributionReport(path string, recipients []token2022.Recipient) (*token2022.DistributionReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var report token2022.DistributionReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("error while reading report %s: %w", path, err)
+	}
+	if len(report.Results) != len(recipients) {
+		return nil, fmt.Errorf("report %s has %d rows, the CSV file %d", path, len(report.Results), len(recipients))
+	}
+	for i, result := range report.Results {
+		if result.Recipient != recipients[i] {
+			return nil, fmt.Errorf("row %d of report %s differs from the CSV file", i+1, path)
+		}
+	}
+	return &report, nil
+}
+
+func writeJSONFile(path string, value any) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+var burnCommand = &command{
+	usage: "[-account ACCOUNT] MINT AMOUNT",
+	help:  "Burn tokens from the keypair's associated account.",
+	run:   runBurn,
+}
+
+func runBurn(ctx context.Context, a *app, flags *flag.FlagSet, args []string) error {
+	accountFlag := flags.String("account", "", "token account to burn from (default: the keypair's associated account)")
+	if err := parseArgs(flags, args, 2); err != nil {
+		return err
+	}
+	mint, decoded, err := a.mint(ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	amount, err := token2022.ParseAmount(flags.Arg(1), decoded.Decimals)
+	if err != nil {
+		return err
+	}
+	key, err := a.signer()
+	if err != nil {
+		return err
+	}
+	account, err := a.tokenAccount(*accountFlag, key.PublicKey(), mint)
+	if err != nil {
+		return err
+	}
+	return a.send(ctx, []solana.Instruction{
+		token2022.NewBurnChecked2022Instruction(amount, decoded.Decimals, account, mint, key.PublicKey()).Build(),
+	})
+}
+
+var freezeCommand = &command{
+	usage: "ACCOUNT",
+	help:  "Freeze a token account with the keypair as freeze authority.",
+	run: func(ctx context.Context, a *app, flags *flag.FlagSet, args []string) error {
+		return runFreezeOrThaw(ctx, a, flags, args, true)
+	},
+}
+
+var thawCommand = &command{
+	usage: "ACCOUNT",
+	help:  "Thaw a token account with the keypair as freeze authority.",
+	run: func(ctx context.Context, a *app, flags *flag.FlagSet, args []string) error {
+		return runFreezeOrThaw(ctx, a, flags, args, false)
+	},
+}
+
+func runFreezeOrThaw(ctx context.Context, a *app, flags *flag.FlagSet, args []string, freeze bool) error {
+	if err := parseArgs(flags, args, 1); err != nil {
+		return err
+	}
+	account, err := parsePubkey("account", flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	decoded, err := token2022.FetchTokenAccount(ctx, a.client, account, a.commitment)
+	if err != nil {
+		return fmt.Errorf("error while fetching token account: %w", err)
+	}
+	key, err := a.signer()
+	if err != nil {
+		return err
+	}
+	inst := token2022.NewThawAccount2022Instruction(account, decoded.Mint, key.PublicKey()).Build()
+	if freeze {
+		inst = token2022.NewFreezeAccount2022Instruction(account, decoded.Mint, key.PublicKey()).Build()
+	}
+	return a.send(ctx, []solana.Instruction{inst})
+}
+
+var setAuthorityCommand = &command{
+	usage: "-type TYPE (-new AUTHORITY | -none [-irreversible]) ADDRESS",
+	help:  "Change or remove an authority of a mint or token account.",
+	run:   runSetAuthority,
+}
+
+func runSetAuthority(ctx context.Context, a *app, flags *flag.FlagSet, args []string) error {
+	var authorityType token2022.AuthorityType
+	flags.TextVar(&authorityType, "type", token2022.AuthorityMintTokens, "authority type, such as MintTokens, FreezeAccount or WithheldWithdraw")
+	newFlag := flags.String("new", "", "new authority")
+	none := flags.Bool("none", false, "remove the authority")
+	irreversible := flags.Bool("irreversible", false, "confirm removing the MintTokens, FreezeAccount or CloseMint authority")
+	if err := parseArgs(flags, args, 1); err != nil {
+		return err
+	}
+	if (*newFlag == "") == !*none {
+		return errors.New("exactly one of -new and -none is required")
+	}
+	address, err := parsePubkey("address", flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	var newAuthority *solana.PublicKey
+	if !*none {
+		pubkey, err := parsePubkey("authority", *newFlag)
+		if err != nil {
+			return err
+		}
+		newAuthority = &pubkey
+	}
+	key, err := a.signer()
+	if err != nil {
+		return err
+	}
+	inst := token2022.NewSetAuthority2022Instruction(authorityType, newAuthority, address, key.PublicKey())
+	if *irreversible {
+		inst.ConfirmIrreversible()
+	}
+	built, err := inst.ValidateAndBuild()
+	if err != nil {
+		return err
+	}
+	return a.send(ctx, []solana.Instruction{built})
+}
+
+var metadataCommand = &command{
+	usage: "get MINT | set -field FIELD MINT VALUE",
+	help:  "Show or update the token metadata stored in a mint.",
+	run:   runMetadata,
+}
+
+func runMetadata(ctx context.Context, a *app, flags *flag.FlagSet, args []string) error {
+	if len(args) == 0 {
+		return errors.New("metadata: expected get or set")
+	}
+	switch args[0] {
+	case "get":
+		if err := parseArgs(flags, args[1:], 1); err != nil {
+			return err
+		}
+		_, decoded, err := a.mint(ctx, flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		metadata, ok, err := decoded.TokenMetadata()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("mint has no token metadata")
+		}
+		return a.printJSON(metadata)
+	case "set":
+		field := flags.String("field", "", "name, symbol, uri or an additional metadata key")
+		if err := parseArgs(flags, args[1:], 2); err != nil {
+			return err
+		}
+		if *field == "" {
+			return errors.New("-field not set")
+		}
+		return a.setMetadataField(ctx, flags.Arg(0), *field, flags.Arg(1))
+	default:
+		return fmt.Errorf("metadata: unknown subcommand %q", args[0])
+	}
+}
+
+// setMetadataField updates a metadata field, first topping up the mint's
+// lamports to cover the rent of its new size.
+func (a *app) setMetadataField(ctx context.Context, mintValue, field, value string) error {
+	mint, decoded, err := a.mint(ctx, mintValue)
+	if err != nil {
+		return err
+	}
+	metadata, ok, err := decoded.TokenMetadata()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("mint has no token metadata")
+	}
+	key, err := a.signer()
+	if err != nil {
+		return err
+	}
+
+	out, err := a.client.GetAccountInfoWithOpts(ctx, mint, &rpc.GetAccountInfoOpts{Encoding: solana.EncodingBase64, Commitment: a.commitment})
+	if err != nil {
+		return fmt.Errorf("error while fetching mint: %w", err)
+	}
+	updated := *metadata
+	setMetadataField(&updated, field, value)
+	size := len(out.Value.Data.GetBinary()) + token2022.TokenMetadataLength(&updated) - token2022.TokenMetadataLength(metadata)
+	rent, err := a.client.GetMinimumBalanceForRentExemption(ctx, uint64(size), a.commitment)
+	if err != nil {
+		return fmt.Errorf("error while getting rent exemption: %w", err)
+	}
+
+	var instructions []solana.Instruction
+	if rent > out.Value.Lamports {
+		instructions = append(instructions, system.NewTransferInstruction(rent-out.Value.Lamports, key.PublicKey(), mint).Build())
+	}
+	instructions = append(instructions, newUpdateMetadataFieldInstruction(mint, key.PublicKey(), field, value))
+	return a.send(ctx, instructions)
+}
+
+var feesCommand = &command{
+	usage: "harvest MINT [ACCOUNT...] | withdraw [-to ACCOUNT] MINT [ACCOUNT...]",
+	help:  "Harvest withheld transfer fees to the mint, or withdraw them with the keypair as withdraw authority.",
+	run:   runFees,
+}
+
+func runFees(ctx context.Context, a *app, flags *flag.FlagSet, args []string) error {
+	if len(args) == 0 {
+		return errors.New("fees: expected harvest or withdraw")
+	}
+	var to *string
+	switch args[0] {
+	case "harvest":
+	case "withdraw":
+		to = flags.String("to", "", "destination token account (default: the keypair's associated account)")
+	default:
+		return fmt.Errorf("fees: unknown subcommand %q", args[0])
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("mint not set")
+	}
+	mint, err := parsePubkey("mint", flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	var sources []solana.PublicKey
+	for _, value := range flags.Args()[1:] {
+		source, err := parsePubkey("account", value)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, source)
+	}
+
+	if to == nil {
+		if len(sources) == 0 {
+			if sources, err = a.accountsWithWithheldFees(ctx, mint); err != nil {
+				return err
+			}
+			if len(sources) == 0 {
+				fmt.Fprintln(a.out, "No withheld fees to harvest")
+				return nil
+			}
+		}
+		for start := 0; start < len(sources); start += harvestBatchSize {
+			end := min(start+harvestBatchSize, len(sources))
+			if err := a.send(ctx, []solana.Instruction{
+				token2022.NewHarvestWithheldTokensToMint2022Instruction(mint, sources[start:end]...).Build(),
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	key, err := a.signer()
+	if err != nil {
+		return err
+	}
+	destination, err := a.tokenAccount(*to, key.PublicKey(), mint)
+	if err != nil {
+		return err
+	}
+	if len(sources) == 0 {
+		return a.send(ctx, []solana.Instruction{
+			token2022.NewWithdrawWithheldTokensFromMint2022Instruction(mint, destination, key.PublicKey()).Build(),
+		})
+	}
+	for start := 0; start < len(sources); start += harvestBatchSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		end := min(start+harvestBatchSize, len(sources))
+		if err := a.send(ctx, []solana.Instruction{
+			token2022.NewWithdrawWithheldTokensFromAccounts2022Instruction(mint, destination, sources[start:end], key.PublicKey()).Build(),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// accountsWithWithheldFees lists the token accounts of mint holding
+// withheld fees.
+func (a *app) accountsWithWithheldFees(ctx context.Context, mint solana.PublicKey) ([]solana.PublicKey, error) {
+	out, err := a.client.GetProgramAccountsWithOpts(ctx, solana.Token2022ProgramID, &rpc.GetProgramAccountsOpts{
+		Commitment: a.commitment,
+		Encoding:   solana.EncodingBase64,
+		Filters: []rpc.RPCFilter{
+			{Memcmp: &rpc.RPCFilterMemcmp{Offset: 0, Bytes: mint[:]}},
+			{Memcmp: &rpc.RPCFilterMemcmp{Offset: token2022.AccountSize, Bytes: solana.Base58{byte(token2022.AccountTypeAccount)}}},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error while listing token accounts: %w", err)
+	}
+	var accounts []solana.PublicKey
+	for _, keyed := range out {
+		account, err := token2022.DecodeTokenAccount(keyed.Account.Data.GetBinary())
+		if err != nil {
+			continue
+		}
+		if withheld, ok, err := account.WithheldAmount(); err == nil && ok && withheld > 0 {
+			accounts = append(accounts, keyed.Pubkey)
+		}
+	}
+	return accounts, nil
+}
+
+var inspectCommand = &command{
+	usage: "ADDRESS",
+	help:  "Decode a mint or token account and print it as JSON.",
+	run:   runInspect,
+}
+
+func runInspect(ctx context.Context, a *app, flags *flag.FlagSet, args []string) error {
+	if err := parseArgs(flags, args, 1); err != nil {
+		return err
+	}
+	address, err := parsePubkey("address", flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	out, err := a.client.GetAccountInfoWithOpts(ctx, address, &rpc.GetAccountInfoOpts{Encoding: solana.EncodingBase64, Commitment: a.commitment})
+	if err != nil {
+		return fmt.Errorf("error while fetching account: %w", err)
+	}
+	if !out.Value.Owner.Equals(solana.Token2022ProgramID) {
+		return fmt.Errorf("%s is not a Token-2022 account (owner %s)", address, out.Value.Owner)
+	}
+	data := out.Value.Data.GetBinary()
+	switch token2022.AccountTypeOf(data) {
+	case token2022.AccountTypeMint:
+		mint, err := token2022.DecodeMint(data)
+		if err != nil {
+			return err
+		}
+		return a.printJSON(struct {
+			Type string          `json:"type"`
+			Mint *token2022.Mint `json:"mint"`
+		}{"mint", mint})
+	case token2022.AccountTypeAccount:
+		account, err := token2022.DecodeTokenAccount(data)
+		if err != nil {
+			return err
+		}
+		return a.printJSON(struct {
+			Type    string                  `json:"type"`
+			Account *token2022.TokenAccount `json:"account"`
+		}{"account", account})
+	default:
+		return fmt.Errorf("%s is neither a mint nor a token account", address)
+	}
+}
+
+var idlCommand = &command{
+	usage: "[token|associated-token]",
+	help:  "Print the Anchor style IDL of the instruction builders as JSON.",
+	run:   runIDL,
+}
+
+func runIDL(ctx context.Context, a *app, flags *flag.FlagSet, args []string) error {
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 1 {
+		flags.Usage()
+		return fmt.Errorf("%s: expected at most 1 argument, got %d", flags.Name(), flags.NArg())
+	}
+	var (
+		idl *token2022.IDL
+		err error
+	)
+	switch program := flags.Arg(0); program {
+	case "", "token":
+		idl, err = token2022.TokenIDL()
+	case "associated-token":
+		idl, err = token2022.AssociatedTokenIDL()
+	default:
+		return fmt.Errorf("unknown program %q", program)
+	}
+	if err != nil {
+		return err
+	}
+	return a.printJSON(idl)
+}
+
+// mint parses and fetches a mint.
+func (a *app) mint(ctx context.Context, value string) (solana.PublicKey, *token2022.Mint, error) {
+	mint, err := parsePubkey("mint", value)
+	if err != nil {
+		return solana.PublicKey{}, nil, err
+	}
+	decoded, err := token2022.FetchMint(ctx, a.client, mint, a.commitment)
+	if err != nil {
+		return solana.PublicKey{}, nil, fmt.Errorf("error while fetching mint: %w", err)
+	}
+	return mint, decoded, nil
+}
+
+// tokenAccount returns the given token account, or the associated token
+// account of owner when value is empty.
+func (a *app) tokenAccount(value string, owner, mint solana.PublicKey) (solana.PublicKey, error) {
+	if value != "" {
+		return parsePubkey("account", value)
+	}
+	account, _, err := token2022.FindAssociatedTokenAddress2022(owner, mint)
+	return account, err
+}
+
+// isTokenAccount reports whether address is an existing Token-2022 token
+// account, as opposed to a wallet.
+func (a *app) isTokenAccount(ctx context.Context, address solana.PublicKey) (bool, error) {
+	out, err := a.client.GetAccountInfoWithOpts(ctx, address, &rpc.GetAccountInfoOpts{Encoding: solana.EncodingBase64, Commitment: a.commitment})
+	if errors.Is(err, rpc.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error while fetching %s: %w", address, err)
+	}
+	return out.Value.Owner.Equals(solana.Token2022ProgramID) &&
+		token2022.AccountTypeOf(out.Value.Data.GetBinary()) == token2022.AccountTypeAccount, nil
+}
+
+func (a *app) printJSON(v interface{}) error {
+	encoder := json.NewEncoder(a.out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,260 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command token2022 manages Token-2022 mints and accounts from the
+// command line. Each subcommand is a short use of the token2022 package
+// and doubles as an example of it.
+//
+// Usage:
+//
+//	token2022 [-url URL] [-keypair FILE] <command> [flags] [args]
+//
+// Run "token2022 help" for the list of commands.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+
+	"github.com/dwmfan/token2022"
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// command is a subcommand. run receives a flag set named after the
+// command and the arguments after its name.
+type command struct {
+	usage string
+	help  string
+	run   func(ctx context.Context, app *app, flags *flag.FlagSet, args []string) error
+}
+
+var commands = map[string]*command{
+	"create-mint":   createMintCommand,
+	"create-ata":    createATACommand,
+	"transfer":      transferCommand,
+	"mint-to":       mintToCommand,
+	"mint-to-many":  mintToManyCommand,
+	"burn":          burnCommand,
+	"freeze":        freezeCommand,
+	"thaw":          thawCommand,
+	"set-authority": setAuthorityCommand,
+	"metadata":      metadataCommand,
+	"fees":          feesCommand,
+	"inspect":       inspectCommand,
+	"idl":           idlCommand,
+}
+
+var urlMonikers = map[string]string{
+	"mainnet-beta": rpc.MainNetBeta_RPC,
+	"m":            rpc.MainNetBeta_RPC,
+	"devnet":       rpc.DevNet_RPC,
+	"d":            rpc.DevNet_RPC,
+	"testnet":      rpc.TestNet_RPC,
+	"t":            rpc.TestNet_RPC,
+	"localhost":    rpc.LocalNet_RPC,
+	"l":            rpc.LocalNet_RPC,
+}
+
+// app holds what the global flags configure.
+type app struct {
+	client      *rpc.Client
+	out         io.Writer
+	commitment  rpc.CommitmentType
+	keypairPath string
+	keypair     solana.PrivateKey
+	sendOptions []token2022.SendOption
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := run(ctx, os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "token2022:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("token2022", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	url := flags.String("url", envOr("TOKEN2022_URL", "devnet"), "JSON-RPC URL or moniker (mainnet-beta, devnet, testnet, localhost)")
+	keypair := flags.String("keypair", envOr("TOKEN2022_KEYPAIR", defaultKeypairPath()), "keypair file paying fees and signing as authority")
+	commitment := flags.String("commitment", string(rpc.CommitmentConfirmed), "commitment to read and confirm at")
+	skipPreflight := flags.Bool("skip-preflight", false, "send without the RPC node's preflight simulation")
+	preflightCommitment := flags.String("preflight-commitment", "", "commitment of the preflight simulation (default: the node's)")
+	maxRetries := flags.Int("max-retries", -1, "times the RPC node rebroadcasts a transaction (default: the node's)")
+	flags.Usage = func() { usage(flags) }
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 || flags.Arg(0) == "help" {
+		usage(flags)
+		return flag.ErrHelp
+	}
+	cmd, ok := commands[flags.Arg(0)]
+	if !ok {
+		usage(flags)
+		return fmt.Errorf("unknown command %q", flags.Arg(0))
+	}
+
+	endpoint := *url
+	if full, ok := urlMonikers[endpoint]; ok {
+		endpoint = full
+	}
+	a := &app{
+		client:      rpc.New(endpoint),
+		out:         stdout,
+		commitment:  rpc.CommitmentType(*commitment),
+		keypairPath: *keypair,
+		sendOptions: []token2022.SendOption{token2022.WithSkipPreflight(*skipPreflight)},
+	}
+	if *preflightCommitment != "" {
+		a.sendOptions = append(a.sendOptions, token2022.WithPreflightCommitment(rpc.CommitmentType(*preflightCommitment)))
+	}
+	if *maxRetries >= 0 {
+		a.sendOptions = append(a.sendOptions, token2022.WithMaxRetries(uint(*maxRetries)))
+	}
+	return cmd.run(ctx, a, newFlagSet(flags.Arg(0), cmd, stderr), flags.Args()[1:])
+}
+
+func usage(flags *flag.FlagSet) {
+	w := flags.Output()
+	fmt.Fprintln(w, "Usage: token2022 [flags] <command> [command flags] [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-14s %s\n", name, commands[name].help)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Flags:")
+	flags.PrintDefaults()
+}
+
+// newFlagSet returns the flag set of a command, printing its usage on
+// errors.
+func newFlagSet(name string, cmd *command, output io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(output)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: token2022 %s %s\n\n%s\n", flags.Name(), cmd.usage, cmd.help)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// parseArgs parses the flags of a command and checks that n positional
+// arguments follow them.
+func parseArgs(flags *flag.FlagSet, args []string, n int) error {
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != n {
+		flags.Usage()
+		return fmt.Errorf("%s: expected %d arguments, got %d", flags.Name(), n, flags.NArg())
+	}
+	return nil
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func defaultKeypairPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "solana", "id.json")
+}
+
+// signer loads the keypair on first use, so that read-only commands work
+// without one.
+func (a *app) signer() (solana.PrivateKey, error) {
+	if a.keypair != nil {
+		return a.keypair, nil
+	}
+	if a.keypairPath == "" {
+		return nil, errors.New("keypair not set")
+	}
+	key, err := solana.PrivateKeyFromSolanaKeygenFile(a.keypairPath)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading keypair: %w", err)
+	}
+	a.keypair = key
+	return key, nil
+}
+
+// send signs instructions with the keypair and extra signers, submits
+// them and prints the signature.
+func (a *app) send(ctx context.Context, instructions []solana.Instruction, extra ...solana.PrivateKey) error {
+	key, err := a.signer()
+	if err != nil {
+		return err
+	}
+	builder := token2022.NewTxBuilder(a.client).
+		SetFeePayer(key.PublicKey()).
+		SetCommitment(a.commitment).
+		AddInstruction(instructions...).
+		AddSigner(token2022.PrivateKeySigners(append([]solana.PrivateKey{key}, extra...)...)...)
+	result, err := a.sender().Send(ctx, builder)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(a.out, "Signature: %s\n", result.Signature)
+	return nil
+}
+
+// sender confirms at the global commitment with the global send options.
+func (a *app) sender() *token2022.Sender {
+	return token2022.NewSender(a.client).SetCommitment(a.commitment).SetSendOptions(a.sendOptions...)
+}
+
+// owner returns the given owner, or the keypair's public key when value
+// is empty.
+func (a *app) owner(value string) (solana.PublicKey, error) {
+	if value != "" {
+		return parsePubkey("owner", value)
+	}
+	key, err := a.signer()
+	if err != nil {
+		return solana.PublicKey{}, err
+	}
+	return key.PublicKey(), nil
+}
+
+func parsePubkey(name, value string) (solana.PublicKey, error) {
+	pubkey, err := solana.PublicKeyFromBase58(value)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("invalid %s %q: %w", name, value, err)
+	}
+	return pubkey, nil
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dwmfan/token2022"
+	"github.com/dwmfan/token2022/token2022test"
+	solana "github.com/gagliardetto/solana-go"
+)
+
+var (
+	wallet = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	mint   = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+)
+
+// runCLI runs the command against server with a new keypair and returns
+// its output and the keypair.
+func runCLI(t *testing.T, server *token2022test.Server, args ...string) (string, solana.PrivateKey) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	key, err := runCLIError(t, server, &stdout, &stderr, args...)
+	if err != nil {
+		t.Fatalf("run %v: %v\n%s", args, err, stderr.String())
+	}
+	return stdout.String(), key
+}
+
+// runCLIError runs the command against server with a new keypair and
+// returns the keypair and the error of the command.
+func runCLIError(t *testing.T, server *token2022test.Server, stdout, stderr *bytes.Buffer, args ...string) (solana.PrivateKey, error) {
+	t.Helper()
+	key := solana.NewWallet().PrivateKey
+	return key, runCLIWithKey(t, server, key, stdout, stderr, args...)
+}
+
+// runCLIWithKey runs the command against server with key as keypair.
+func runCLIWithKey(t *testing.T, server *token2022test.Server, key solana.PrivateKey, stdout, stderr *bytes.Buffer, args ...string) error {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "id.json")
+	// Keygen files are arrays of numbers, which json.Marshal only
+	// produces for non-byte slices.
+	ints := make([]int, len(key))
+	for i, b := range key {
+		ints[i] = int(b)
+	}
+	encoded, err := json.Marshal(ints)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if err := os.WriteFile(path, encoded, 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	args = append([]string{"-url", server.URL(), "-keypair", path}, args...)
+	return run(context.Background(), args, stdout, stderr)
+}
+
+// sentInstructions parses the Token-2022 instructions of the only sent
+// transaction.
+func sentInstructions(t *testing.T, server *token2022test.Server) []*token2022.ParsedInstruction {
+	t.Helper()
+	txs := server.Transactions()
+	if len(txs) != 1 {
+		t.Fatalf("Expected 1 transaction, got %d", len(txs))
+	}
+	parsed, err := token2022.ParseTransaction(txs[0], nil)
+	if err != nil {
+		t.Fatalf("ParseTransaction: %v", err)
+	}
+	return parsed.Instructions
+}
+
+func TestTransfer(t *testing.T) {
+	server := token2022test.NewServer(t)
+	server.SetMint(mint, &token2022.Mint{Decimals: 6, IsInitialized: true})
+
+	out, key := runCLI(t, server, "transfer", "-fund-recipient", mint.String(), "1.5", wallet.String())
+	if !strings.HasPrefix(out, "Signature: ") {
+		t.Errorf("Unexpected output %q", out)
+	}
+	instructions := sentInstructions(t, server)
+	if len(instructions) != 2 || instructions[0].Name != "CreateIdempotent" {
+		t.Fatalf("Expected idempotent create and transfer, got %d instructions", len(instructions))
+	}
+	transfer, ok := instructions[1].Instruction.(*token2022.TransferChecked2022)
+	if !ok {
+		t.Fatalf("Expected *TransferChecked2022, got %T", instructions[1].Instruction)
+	}
+	source, _, _ := token2022.FindAssociatedTokenAddress2022(key.PublicKey(), mint)
+	destination, _, _ := token2022.FindAssociatedTokenAddress2022(wallet, mint)
+	if transfer.Amount != 1_500_000 || transfer.Decimals != 6 || transfer.Source != source || transfer.Destination != destination {
+		t.Errorf("Unexpected transfer %+v", transfer)
+	}
+}
+
+func TestTransferToTokenAccount(t *testing.T) {
+	server := token2022test.NewServer(t)
+	server.SetMint(mint, &token2022.Mint{Decimals: 2, IsInitialized: true})
+	account := solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+	server.SetTokenAccount(account, &token2022.TokenAccount{Mint: mint, Owner: wallet, State: token2022.AccountStateInitialized})
+
+	runCLI(t, server, "transfer", "-fund-recipient", mint.String(), "3", account.String())
+	instructions := sentInstructions(t, server)
+	if len(instructions) != 1 {
+		t.Fatalf("Expected only a transfer, got %d instructions", len(instructions))
+	}
+	if transfer := instructions[0].Instruction.(*token2022.TransferChecked2022); transfer.Destination != account || transfer.Amount != 300 {
+		t.Errorf("Unexpected transfer %+v", transfer)
+	}
+}
+
+func TestTransferWithFeeAndMemo(t *testing.T) {
+	server := token2022test.NewServer(t).SetEpoch(3)
+	// A 1% fee capped at 5000 from epoch 0.
+	fee := make([]byte, 108)
+	binary.LittleEndian.PutUint64(fee[98:], 5000)
+	binary.LittleEndian.PutUint16(fee[106:], 100)
+	server.SetMint(mint, &token2022.Mint{
+		Decimals:      2,
+		IsInitialized: true,
+		Extensions:    []token2022.Extension{{Type: token2022.ExtensionTransferFeeConfig, Data: fee}},
+	})
+	account := solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+	server.SetTokenAccount(account, &token2022.TokenAccount{
+		Mint:       mint,
+		Owner:      wallet,
+		State:      token2022.AccountStateInitialized,
+		Extensions: []token2022.Extension{{Type: token2022.ExtensionMemoTransfer, Data: []byte{1}}},
+	})
+
+	out, _ := runCLI(t, server, "transfer", "-memo", "invoice 7", mint.String(), "3", account.String())
+	if !strings.HasPrefix(out, "Fee: 0.03\n") {
+		t.Errorf("Unexpected output %q", out)
+	}
+	instructions := sentInstructions(t, server)
+	if len(instructions) != 1 {
+		t.Fatalf("Expected only a transfer, got %d instructions", len(instructions))
+	}
+	transfer, ok := instructions[0].Instruction.(*token2022.TransferCheckedWithFee2022)
+	if !ok || transfer.Amount != 300 || transfer.Fee != 3 || instructions[0].Index != 1 {
+		t.Errorf("Expected a transfer of 300 with fee 3 after the memo, got %+v", instructions[0])
+	}
+}
+
+func TestCreateMint(t *testing.T) {
+	server := token2022test.NewServer(t)
+
+	out, _ := runCLI(t, server, "create-mint", "-decimals", "6", "-transfer-fee-basis-points", "50", "-transfer-fee-maximum", "5000",
+		"-name", "Token", "-symbol", "TKN", "-uri", "https://example.com/token.json")
+	if !strings.HasPrefix(out, "Mint: ") {
+		t.Errorf("Unexpected output %q", out)
+	}
+	txs := server.Transactions()
+	if len(txs) != 1 {
+		t.Fatalf("Expected 1 transaction, got %d", len(txs))
+	}
+	var names []string
+	for _, inst := range txs[0].Message.Instructions {
+		program := txs[0].Message.AccountKeys[inst.ProgramIDIndex]
+		if program.Equals(solana.Token2022ProgramID) {
+			names = append(names, token2022.InstructionName(inst.Data))
+		} else {
+			names = append(names, program.String())
+		}
+	}
+	want := []string{solana.SystemProgramID.String(), "TransferFeeExtension", "MetadataPointerExtension", "InitializeMint2"}
+	if len(names) != len(want)+1 {
+		t.Fatalf("Expected %v and metadata initialization, got %v", want, names)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("Instruction %d: expected %s, got %s", i, want[i], names[i])
+		}
+	}
+	if data := txs[0].Message.Instructions[4].Data; !bytes.Equal(data[:8], metadataDiscriminator("initialize_account")) {
+		t.Errorf("Expected metadata initialization, got %x", data)
+	}
+}
+
+func TestCreateATAFrozen(t *testing.T) {
+	server := token2022test.NewServer(t)
+	server.SetMint(mint, &token2022.Mint{
+		Decimals:        6,
+		IsInitialized:   true,
+		FreezeAuthority: &wallet,
+		Extensions:      []token2022.Extension{{Type: token2022.ExtensionDefaultAccountState, Data: []byte{byte(token2022.AccountStateFrozen)}}},
+	})
+
+	out, _ := runCLI(t, server, "create-ata", mint.String())
+	if !strings.Contains(out, "frozen until thawed by "+wallet.String()) {
+		t.Errorf("Unexpected output %q", out)
+	}
+	if instructions := sentInstructions(t, server); len(instructions) != 1 || instructions[0].Name != "CreateIdempotent" {
+		t.Errorf("Expected a single idempotent create, got %d instructions", len(instructions))
+	}
+}
+
+func TestInspect(t *testing.T) {
+	server := token2022test.NewServer(t)
+	server.SetMint(mint, &token2022.Mint{Decimals: 6, Supply: 42, IsInitialized: true})
+
+	out, _ := runCLI(t, server, "inspect", mint.String())
+	var decoded struct {
+		Type string          `json:"type"`
+		Mint *token2022.Mint `json:"mint"`
+	}
+	if err := json.Unmarshal([]byte(out), &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v\n%s", err, out)
+	}
+	if decoded.Type != "mint" || decoded.Mint.Supply != 42 || decoded.Mint.Decimals != 6 {
+		t.Errorf("Unexpected output %s", out)
+	}
+	if len(server.Transactions()) != 0 {
+		t.Errorf("Expected inspect not to send transactions")
+	}
+}
+
+func TestMetadataLength(t *testing.T) {
+	authority := wallet
+	metadata := &token2022.TokenMetadata{UpdateAuthority: &authority, Mint: mint, Name: "Token", Symbol: "TKN", URI: "https://example.com"}
+	setMetadataField(metadata, "color", "blue")
+	setMetadataField(metadata, "name", "Renamed")
+
+	// Encode the extension the way the program does.
+	data := append(append([]byte{}, wallet[:]...), mint[:]...)
+	for _, s := range []string{metadata.Name, metadata.Symbol, metadata.URI} {
+		data = appendString(data, s)
+	}
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(metadata.AdditionalMetadata)))
+	for _, pair := range metadata.AdditionalMetadata {
+		data = appendString(appendString(data, pair[0]), pair[1])
+	}
+
+	decoded, err := token2022.DecodeTokenMetadata(data)
+	if err != nil {
+		t.Fatalf("DecodeTokenMetadata: %v", err)
+	}
+	if decoded.Name != "Renamed" || len(decoded.AdditionalMetadata) != 1 || decoded.AdditionalMetadata[0] != [2]string{"color", "blue"} {
+		t.Errorf("Unexpected metadata %+v", decoded)
+	}
+	if length := token2022.TokenMetadataLength(metadata); length != len(data) {
+		t.Errorf("Expected length %d, got %d", len(data), length)
+	}
+}
+
+func TestSetAuthorityNone(t *testing.T) {
+	server := token2022test.NewServer(t)
+	var stdout, stderr bytes.Buffer
+	if _, err := runCLIError(t, server, &stdout, &stderr, "set-authority", "-type", "MintTokens", "-none", mint.String()); !errors.Is(err, token2022.ErrIrreversible) {
+		t.Fatalf("Expected ErrIrreversible without -irreversible, got %v", err)
+	}
+
+	runCLI(t, server, "set-authority", "-type", "MintTokens", "-none", "-irreversible", mint.String())
+	instructions := sentInstructions(t, server)
+	if len(instructions) != 1 {
+		t.Fatalf("Expected 1 instruction, got %d", len(instructions))
+	}
+	if inst, ok := instructions[0].Instruction.(*token2022.SetAuthority2022); !ok || inst.NewAuthority != nil || inst.AuthorityType != token2022.AuthorityMintTokens {
+		t.Errorf("Unexpected instruction %+v", instructions[0].Instruction)
+	}
+}
+
+func TestMintToMany(t *testing.T) {
+	var (
+		server    = token2022test.NewServer(t)
+		key       = solana.NewWallet().PrivateKey
+		authority = key.PublicKey()
+		dir       = t.TempDir()
+		csvPath   = filepath.Join(dir, "recipients.csv")
+		report    = filepath.Join(dir, "report.json")
+		other     = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+	)
+	server.SetMint(mint, &token2022.Mint{MintAuthority: &authority, Supply: 100, Decimals: 2, IsInitialized: true})
+	rows := "owner,amount\n" + wallet.String() + ",1.5\n" + other.String() + ",0.25\n"
+	if err := os.WriteFile(csvPath, []byte(rows), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	err := runCLIWithKey(t, server, key, &stdout, &stderr, "mint-to-many", "-max-supply", "2", mint.String(), csvPath)
+	if !errors.Is(err, token2022.ErrMaxSupplyExceeded) {
+		t.Fatalf("Expected ErrMaxSupplyExceeded, got %v", err)
+	}
+
+	stdout.Reset()
+	if err := runCLIWithKey(t, server, key, &stdout, &stderr, "mint-to-many", "-report", report, mint.String(), csvPath); err != nil {
+		t.Fatalf("mint-to-many: %v\n%s", err, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Paid: 2, failed: 0, unknown: 0") {
+		t.Errorf("Unexpected output %q", stdout.String())
+	}
+	instructions := sentInstructions(t, server)
+	if len(instructions) != 4 {
+		t.Fatalf("Expected two creates and two mints, got %d instructions", len(instructions))
+	}
+	mintTo, ok := instructions[1].Instruction.(*token2022.MintToChecked2022)
+	if !ok || mintTo.Amount != 150 {
+		t.Errorf("Expected a MintToChecked of 150, got %+v", instructions[1].Instruction)
+	}
+
+	// Every row is paid, so resuming sends nothing.
+	stdout.Reset()
+	if err := runCLIWithKey(t, server, key, &stdout, &stderr, "mint-to-many", "-report", report, "-resume", mint.String(), csvPath); err != nil {
+		t.Fatalf("mint-to-many -resume: %v", err)
+	}
+	if len(server.Transactions()) != 1 {
+		t.Errorf("Expected no new transaction, got %d", len(server.Transactions())-1)
+	}
+}
+
+func TestIDL(t *testing.T) {
+	server := token2022test.NewServer(t)
+
+	out, _ := runCLI(t, server, "idl")
+	var idl token2022.IDL
+	if err := json.Unmarshal([]byte(out), &idl); err != nil {
+		t.Fatalf("Unmarshal: %v\n%s", err, out)
+	}
+	if idl.Address != solana.Token2022ProgramID.String() || len(idl.Instructions) == 0 {
+		t.Errorf("Unexpected IDL %s", out)
+	}
+
+	out, _ = runCLI(t, server, "idl", "associated-token")
+	if err := json.Unmarshal([]byte(out), &idl); err != nil {
+		t.Fatalf("Unmarshal: %v\n%s", err, out)
+	}
+	if idl.Address != token2022.ProgramID.String() {
+		t.Errorf("Unexpected IDL %s", out)
+	}
+}
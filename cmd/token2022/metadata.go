@@ -0,0 +1,80 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/dwmfan/token2022"
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// Token metadata interface instructions are identified by the first 8
+// bytes of the SHA-256 of their name.
+var updateMetadataFieldDiscriminator = metadataDiscriminator("updating_field")
+
+func metadataDiscriminator(name string) []byte {
+	sum := sha256.Sum256([]byte("spl_token_metadata_interface:" + name))
+	return sum[:8]
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(s)))
+	return append(b, s...)
+}
+
+// newUpdateMetadataFieldInstruction sets a field of the metadata stored
+// in the mint. Fields other than name, symbol and uri are additional
+// metadata keys.
+func newUpdateMetadataFieldInstruction(mint, updateAuthority solana.PublicKey, field, value string) solana.Instruction {
+	data := append([]byte{}, updateMetadataFieldDiscriminator...)
+	switch field {
+	case "name":
+		data = append(data, 0)
+	case "symbol":
+		data = append(data, 1)
+	case "uri":
+		data = append(data, 2)
+	default:
+		data = appendString(append(data, 3), field)
+	}
+	data = appendString(data, value)
+	return solana.NewInstruction(solana.Token2022ProgramID, solana.AccountMetaSlice{
+		solana.Meta(mint).WRITE(),
+		solana.Meta(updateAuthority).SIGNER(),
+	}, data)
+}
+
+func setMetadataField(metadata *token2022.TokenMetadata, field, value string) {
+	switch field {
+	case "name":
+		metadata.Name = value
+	case "symbol":
+		metadata.Symbol = value
+	case "uri":
+		metadata.URI = value
+	default:
+		additional := append([][2]string{}, metadata.AdditionalMetadata...)
+		for i, pair := range additional {
+			if pair[0] == field {
+				additional[i][1] = value
+				metadata.AdditionalMetadata = additional
+				return
+			}
+		}
+		metadata.AdditionalMetadata = append(additional, [2]string{field, value})
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// FreezeAction is the operation of a FreezeRequest.
+type FreezeAction string
+
+const (
+	ActionFreeze FreezeAction = "freeze"
+	ActionThaw   FreezeAction = "thaw"
+)
+
+// FreezeRequest freezes or thaws token accounts of a mint, for example to
+// act on a sanctions list.
+type FreezeRequest struct {
+	Action FreezeAction
+	Mint   solana.PublicKey
+	// FreezeAuthority is the freeze authority of Mint, or a multisig
+	// signed by Signers.
+	FreezeAuthority solana.PublicKey
+	Signers         []solana.PublicKey
+	// Owners are wallets whose associated token accounts are targeted.
+	Owners []solana.PublicKey
+	// Accounts are token accounts targeted directly.
+	Accounts []solana.PublicKey
+	// Reason is copied to every audit entry, such as a case number.
+	Reason string
+}
+
+func (r *FreezeRequest) Validate() error {
+	switch {
+	case r.Action != ActionFreeze && r.Action != ActionThaw:
+		return errInvalidField("Action", "must be %q or %q, got %q", ActionFreeze, ActionThaw, r.Action)
+	case r.Mint.IsZero():
+		return errNotSet("Mint")
+	case r.FreezeAuthority.IsZero():
+		return errNotSet("FreezeAuthority")
+	case len(r.Owners) == 0 && len(r.Accounts) == 0:
+		return errNotSet("Owners or Accounts")
+	}
+	return nil
+}
+
+// FreezeAuditEntry records the handling of one targeted account. It
+// encodes to JSON for the compliance record.
+type FreezeAuditEntry struct {
+	Action  FreezeAction     `json:"action"`
+	Mint    solana.PublicKey `json:"mint"`
+	Account solana.PublicKey `json:"account"`
+	// Owner is the owner of Account, or the zero key when the account
+	// does not exist.
+	Owner     solana.PublicKey `json:"owner"`
+	Authority solana.PublicKey `json:"authority"`
+	Reason    string           `json:"reason,omitempty"`
+	// Batch is the index of the transaction in FreezePlan.Transactions
+	// acting on Account, or -1 when it was skipped.
+	Batch int `json:"batch"`
+	// Skipped is why no instruction was planned, such as "account is
+	// already frozen".
+	Skipped   string    `json:"skipped,omitempty"`
+	PlannedAt time.Time `json:"plannedAt"`
+	// Signature and ExecutedAt are set by FreezePlan.Record once the
+	// transaction of Batch landed.
+	Signature  solana.Signature `json:"signature,omitempty"`
+	ExecutedAt *time.Time       `json:"executedAt,omitempty"`
+}
+
+// FreezePlan is the outcome of PlanFreeze.
+type FreezePlan struct {
+	// Transactions holds the FreezeAccount or ThawAccount instructions of
+	// each transaction, paid for by the freeze authority. Each fits in
+	// MaxTransactionSize.
+	Transactions [][]solana.Instruction
+	// Audit has one entry per targeted account, owners first, in the order
+	// of the request.
+	Audit []FreezeAuditEntry
+}
+
+// Record marks the entries of batch as executed by the transaction sig
+// at time at.
+func (p *FreezePlan) Record(batch int, sig solana.Signature, at time.Time) {
+	for i := range p.Audit {
+		if p.Audit[i].Batch == batch {
+			p.Audit[i].Signature = sig
+			p.Audit[i].ExecutedAt = &at
+		}
+	}
+}
+
+// PlanFreeze resolves the associated token accounts of the owners of
+// request, reads every target at commitment and batches one FreezeAccount
+// or ThawAccount per account that needs it. Accounts that do not exist,
+// belong to another mint, or are already in the requested state are
+// recorded as skipped. It fails when FreezeAuthority is not the freeze
+// authority of the mint.
+func PlanFreeze(ctx context.Context, client MultipleAccountsClient, request *FreezeRequest, commitment rpc.CommitmentType) (*FreezePlan, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+	targets := make([]solana.PublicKey, 0, len(request.Owners)+len(request.Accounts))
+	seen := map[solana.PublicKey]bool{}
+	for _, owner := range request.Owners {
+		address, _, err := FindAssociatedTokenAddress2022(owner, request.Mint)
+		if err != nil {
+			return nil, fmt.Errorf("error while finding the associated token account of %s: %w", owner, err)
+		}
+		if !seen[address] {
+			seen[address] = true
+			targets = append(targets, address)
+		}
+	}
+	for _, account := range request.Accounts {
+		if !seen[account] {
+			seen[account] = true
+			targets = append(targets, account)
+		}
+	}
+
+	fetched, err := NewAccountFetcher(client).
+		SetFetchOpts(FetchOpts{Commitment: commitment}).
+		Fetch(ctx, append([]solana.PublicKey{request.Mint}, targets...))
+	if err != nil {
+		return nil, err
+	}
+	if fetched[0] == nil {
+		return nil, fmt.Errorf("%w: mint %s", ErrAccountNotFound, request.Mint)
+	}
+	mint, err := DecodeMint(fetched[0].Data.GetBinary())
+	if err != nil {
+		return nil, fmt.Errorf("error while decoding mint %s: %w", request.Mint, err)
+	}
+	if mint.FreezeAuthority == nil || !mint.FreezeAuthority.Equals(request.FreezeAuthority) {
+		return nil, errInvalidField("FreezeAuthority", "%s is not the freeze authority of mint %s", request.FreezeAuthority, request.Mint)
+	}
+
+	now := time.Now().UTC()
+	plan := &FreezePlan{}
+	var batch []solana.Instruction
+	for i, target := range targets {
+		entry := FreezeAuditEntry{
+			Action:    request.Action,
+			Mint:      request.Mint,
+			Account:   target,
+			Authority: request.FreezeAuthority,
+			Reason:    request.Reason,
+			Batch:     -1,
+			PlannedAt: now,
+		}
+		entry.Skipped = freezeBlocker(fetched[i+1], request, &entry.Owner)
+		if entry.Skipped != "" {
+			plan.Audit = append(plan.Audit, entry)
+			continue
+		}
+
+		var inst solana.Instruction
+		if request.Action == ActionFreeze {
+			inst = NewFreezeAccount2022Instruction(target, request.Mint, request.FreezeAuthority, request.Signers...).Build()
+		} else {
+			inst = NewThawAccount2022Instruction(target, request.Mint, request.FreezeAuthority, request.Signers...).Build()
+		}
+		size, err := transactionSize(request.FreezeAuthority, append(batch[:len(batch):len(batch)], inst), nil)
+		if err != nil {
+			return nil, err
+		}
+		if size > MaxTransactionSize && len(batch) > 0 {
+			plan.Transactions = append(plan.Transactions, batch)
+			batch = nil
+		}
+		batch = append(batch, inst)
+		entry.Batch = len(plan.Transactions)
+		plan.Audit = append(plan.Audit, entry)
+	}
+	if len(batch) > 0 {
+		plan.Transactions = append(plan.Transactions, batch)
+	}
+	return plan, nil
+}
+
+// freezeBlocker returns why the fetched account cannot be acted on, or ""
+// when it can, and stores its owner.
+func freezeBlocker(fetched *rpc.Account, request *FreezeRequest, owner *solana.PublicKey) string {
+	if fetched == nil {
+		return "account does not exist"
+	}
+	if !fetched.Owner.Equals(solana.Token2022ProgramID) {
+		return fmt.Sprintf("account is owned by program %s", fetched.Owner)
+	}
+	account, err := DecodeTokenAccount(fetched.Data.GetBinary())
+	if err != nil {
+		return fmt.Sprintf("account cannot be decoded: %v", err)
+	}
+	*owner = account.Owner
+	switch {
+	case !account.Mint.Equals(request.Mint):
+		return fmt.Sprintf("account belongs to mint %s", account.Mint)
+	case request.Action == ActionFreeze && account.IsFrozen():
+		return "account is already frozen"
+	case request.Action == ActionThaw && !account.IsFrozen():
+		return "account is not frozen"
+	}
+	return ""
+}
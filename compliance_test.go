@@ -0,0 +1,116 @@
+package token2022
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestPlanFreeze(t *testing.T) {
+	var (
+		ctx       = context.Background()
+		authority = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint      = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		client    = newMockRPC()
+	)
+	client.setAccount(mint, solana.Token2022ProgramID, EncodeMint(&Mint{
+		MintAuthority: &authority, FreezeAuthority: &authority, Decimals: 6, IsInitialized: true,
+	}))
+	const owners = 50
+	request := &FreezeRequest{Action: ActionFreeze, Mint: mint, FreezeAuthority: authority, Reason: "case 42"}
+	for i := 0; i < owners; i++ {
+		owner := solana.NewWallet().PublicKey()
+		address, _, err := FindAssociatedTokenAddress2022(owner, mint)
+		if err != nil {
+			t.Fatalf("FindAssociatedTokenAddress2022: %v", err)
+		}
+		client.setAccount(address, solana.Token2022ProgramID, encodeTokenAccount(mint, owner, 10))
+		request.Owners = append(request.Owners, owner)
+	}
+	missing := solana.NewWallet().PublicKey()
+	request.Owners = append(request.Owners, missing)
+	frozen := solana.NewWallet().PublicKey()
+	client.setAccount(frozen, solana.Token2022ProgramID, EncodeTokenAccount(&TokenAccount{Mint: mint, Owner: authority, State: AccountStateFrozen}))
+	otherMint := solana.NewWallet().PublicKey()
+	client.setAccount(otherMint, solana.Token2022ProgramID, encodeTokenAccount(solana.NewWallet().PublicKey(), authority, 1))
+	request.Accounts = []solana.PublicKey{frozen, otherMint}
+
+	plan, err := PlanFreeze(ctx, client, request, rpc.CommitmentConfirmed)
+	if err != nil {
+		t.Fatalf("PlanFreeze: %v", err)
+	}
+	if len(plan.Audit) != owners+3 {
+		t.Fatalf("Expected %d audit entries, got %d", owners+3, len(plan.Audit))
+	}
+	skipped := map[solana.PublicKey]string{}
+	for _, entry := range plan.Audit {
+		if entry.Skipped != "" {
+			skipped[entry.Account] = entry.Skipped
+			continue
+		}
+		if entry.Batch < 0 || entry.Batch >= len(plan.Transactions) || entry.Reason != "case 42" || entry.Authority != authority || entry.Owner.IsZero() {
+			t.Errorf("Unexpected entry %+v", entry)
+		}
+	}
+	if len(skipped) != 3 || skipped[frozen] != "account is already frozen" || skipped[otherMint] == "" {
+		t.Errorf("Expected the missing, frozen and foreign accounts to be skipped, got %v", skipped)
+	}
+	if len(plan.Transactions) < 2 {
+		t.Fatalf("Expected the freezes to span several transactions, got %d", len(plan.Transactions))
+	}
+	instructions := 0
+	for i, batch := range plan.Transactions {
+		size, err := transactionSize(authority, batch, nil)
+		if err != nil {
+			t.Fatalf("transactionSize: %v", err)
+		}
+		if size > MaxTransactionSize {
+			t.Errorf("Expected transaction %d to fit, got %d bytes", i, size)
+		}
+		instructions += len(batch)
+	}
+	if instructions != owners {
+		t.Errorf("Expected one freeze per account, got %d instructions", instructions)
+	}
+
+	at := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	plan.Record(1, solana.Signature{7}, at)
+	recorded := 0
+	for _, entry := range plan.Audit {
+		if entry.ExecutedAt != nil {
+			recorded++
+			if entry.Batch != 1 || entry.Signature != (solana.Signature{7}) || !entry.ExecutedAt.Equal(at) {
+				t.Errorf("Unexpected recorded entry %+v", entry)
+			}
+		}
+	}
+	if recorded != len(plan.Transactions[1]) {
+		t.Errorf("Expected %d recorded entries, got %d", len(plan.Transactions[1]), recorded)
+	}
+	if _, err := json.Marshal(plan.Audit); err != nil {
+		t.Errorf("Marshal: %v", err)
+	}
+
+	thaw := &FreezeRequest{Action: ActionThaw, Mint: mint, FreezeAuthority: authority, Accounts: []solana.PublicKey{frozen, otherMint}}
+	plan, err = PlanFreeze(ctx, client, thaw, rpc.CommitmentConfirmed)
+	if err != nil {
+		t.Fatalf("PlanFreeze: %v", err)
+	}
+	if len(plan.Transactions) != 1 || len(plan.Transactions[0]) != 1 || plan.Audit[0].Batch != 0 {
+		t.Errorf("Expected a single thaw of the frozen account, got %+v", plan.Audit)
+	}
+
+	thaw.FreezeAuthority = missing
+	if _, err := PlanFreeze(ctx, client, thaw, rpc.CommitmentConfirmed); !errors.Is(err, ErrInvalidField) {
+		t.Errorf("Expected ErrInvalidField for a wrong freeze authority, got %v", err)
+	}
+	thaw.Action = "seize"
+	if _, err := PlanFreeze(ctx, client, thaw, rpc.CommitmentConfirmed); !errors.Is(err, ErrInvalidField) {
+		t.Errorf("Expected ErrInvalidField for an unknown action, got %v", err)
+	}
+}
@@ -0,0 +1,401 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+	ws "github.com/gagliardetto/solana-go/rpc/ws"
+)
+
+// Confirmation is the outcome of tracking a submitted transaction.
+type Confirmation struct {
+	Signature solana.Signature
+	Slot      uint64
+	// Commitment is the level that was waited for.
+	Commitment rpc.CommitmentType
+	// Err is nil when the transaction succeeded, a *TransactionError when
+	// it failed on-chain, or the context error when tracking was cancelled.
+	Err error
+}
+
+// TransactionError is a decoded transaction failure reported by the cluster.
+type TransactionError struct {
+	Signature solana.Signature
+	// InstructionIndex is the failing instruction, or -1 when the error
+	// is not tied to an instruction.
+	InstructionIndex int
+	// Kind is the error name, such as "InstructionError", "Custom" or
+	// "InsufficientFundsForFee".
+	Kind string
+	// Custom is the program-specific error code of a Custom instruction error.
+	Custom *uint32
+	// Raw is the error value as returned by the RPC node.
+	Raw interface{}
+}
+
+func (e *TransactionError) Error() string {
+	switch {
+	case e.Custom != nil:
+		return fmt.Sprintf("transaction %s: instruction %d failed: custom program error: 0x%x", e.Signature, e.InstructionIndex, *e.Custom)
+	case e.InstructionIndex >= 0:
+		return fmt.Sprintf("transaction %s: instruction %d failed: %s", e.Signature, e.InstructionIndex, e.Kind)
+	default:
+		return fmt.Sprintf("transaction %s failed: %s", e.Signature, e.Kind)
+	}
+}
+
+// DecodeTransactionError decodes the err value of a signature status or
+// transaction meta, for example {"InstructionError":[1,{"Custom":1}]}.
+func DecodeTransactionError(sig solana.Signature, raw interface{}) *TransactionError {
+	txErr := &TransactionError{
+		Signature:        sig,
+		InstructionIndex: -1,
+		Raw:              raw,
+	}
+	switch v := raw.(type) {
+	case string:
+		txErr.Kind = v
+	case map[string]interface{}:
+		for kind, detail := range v {
+			txErr.Kind = kind
+			if kind != "InstructionError" {
+				continue
+			}
+			pair, ok := detail.([]interface{})
+			if !ok || len(pair) != 2 {
+				continue
+			}
+			if index, ok := toUint64(pair[0]); ok {
+				txErr.InstructionIndex = int(index)
+			}
+			switch inner := pair[1].(type) {
+			case string:
+				txErr.Kind = inner
+			case map[string]interface{}:
+				for innerKind, value := range inner {
+					txErr.Kind = innerKind
+					if code, ok := toUint64(value); ok && innerKind == "Custom" {
+						custom := uint32(code)
+						txErr.Custom = &custom
+					}
+				}
+			}
+		}
+	default:
+		txErr.Kind = fmt.Sprint(raw)
+	}
+	return txErr
+}
+
+func toUint64(v interface{}) (uint64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return uint64(n), true
+	case json.Number:
+		u, err := strconv.ParseUint(n.String(), 10, 64)
+		return u, err == nil
+	case int:
+		return uint64(n), true
+	case uint64:
+		return n, true
+	}
+	return 0, false
+}
+
+// ConfirmationTracker reports when submitted transactions reach a
+// commitment level. It uses signatureSubscribe when a websocket client is
+// configured and always polls getSignatureStatuses as a fallback, so a
+// dropped websocket never loses a confirmation.
+type ConfirmationTracker struct {
+	client       RPCClient
+	ws           *ws.Client
+	pollInterval time.Duration
+}
+
+// NewConfirmationTracker creates a tracker that polls every two seconds.
+func NewConfirmationTracker(client RPCClient) *ConfirmationTracker {
+	return &ConfirmationTracker{
+		client:       client,
+		pollInterval: 2 * time.Second,
+	}
+}
+
+func (t *ConfirmationTracker) SetWebsocket(client *ws.Client) *ConfirmationTracker {
+	t.ws = client
+	return t
+}
+
+func (t *ConfirmationTracker) SetPollInterval(interval time.Duration) *ConfirmationTracker {
+	t.pollInterval = interval
+	return t
+}
+
+// Track returns a channel that receives exactly one Confirmation once the
+// transaction reaches the commitment level, fails, or ctx is done.
+func (t *ConfirmationTracker) Track(ctx context.Context, sig solana.Signature, commitment rpc.CommitmentType) <-chan *Confirmation {
+	out := make(chan *Confirmation, 1)
+	go func() {
+		out <- t.wait(ctx, sig, commitment)
+		close(out)
+	}()
+	return out
+}
+
+// OnConfirmed calls callback in a new goroutine once the transaction is
+// confirmed, fails, or ctx is done.
+func (t *ConfirmationTracker) OnConfirmed(ctx context.Context, sig solana.Signature, commitment rpc.CommitmentType, callback func(*Confirmation)) {
+	go func() {
+		callback(t.wait(ctx, sig, commitment))
+	}()
+}
+
+// TrackStages returns a channel that receives one Confirmation per
+// commitment level, in the order given, as the transaction reaches each of
+// them. The levels must be ascending, for example confirmed then finalized.
+// The channel is closed after the last level, or after the first
+// Confirmation whose Err is set because the transaction failed or ctx is
+// done.
+func (t *ConfirmationTracker) TrackStages(ctx context.Context, sig solana.Signature, commitments ...rpc.CommitmentType) <-chan *Confirmation {
+	out := make(chan *Confirmation, len(commitments)+1)
+	if err := validateStages(commitments); err != nil {
+		out <- &Confirmation{Signature: sig, Err: err}
+		close(out)
+		return out
+	}
+	go func() {
+		defer close(out)
+		for _, commitment := range commitments {
+			confirmation := t.wait(ctx, sig, commitment)
+			out <- confirmation
+			if confirmation.Err != nil {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// OnConfirmedThenFinalized calls onConfirmed once the transaction is
+// confirmed and onFinalized once it is finalized, in a new goroutine.
+// Exchange deposits typically credit a pending balance on the first and
+// make it withdrawable on the second. If the transaction fails or ctx is
+// done, the callback of the stage being waited for receives the
+// Confirmation with its Err and the later one is not called.
+func (t *ConfirmationTracker) OnConfirmedThenFinalized(ctx context.Context, sig solana.Signature, onConfirmed, onFinalized func(*Confirmation)) {
+	go func() {
+		callbacks := []func(*Confirmation){onConfirmed, onFinalized}
+		stage := 0
+		for confirmation := range t.TrackStages(ctx, sig, rpc.CommitmentConfirmed, rpc.CommitmentFinalized) {
+			if callback := callbacks[stage]; callback != nil {
+				callback(confirmation)
+			}
+			stage++
+		}
+	}()
+}
+
+// validateStages checks that commitments are known levels in ascending
+// order.
+func validateStages(commitments []rpc.CommitmentType) error {
+	if len(commitments) == 0 {
+		return errNotSet("Commitments")
+	}
+	previous := -1
+	for _, commitment := range commitments {
+		if err := validateCommitment(commitment); err != nil {
+			return err
+		}
+		rank := commitmentRank(commitment)
+		if rank <= previous {
+			return errInvalidField("Commitments", "must be ascending, got %q after a higher or equal level", commitment)
+		}
+		previous = rank
+	}
+	return nil
+}
+
+// commitmentRank orders processed before confirmed before finalized.
+func commitmentRank(commitment rpc.CommitmentType) int {
+	switch commitment {
+	case rpc.CommitmentProcessed:
+		return 0
+	case rpc.CommitmentFinalized:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// Wait blocks until the transaction reaches the commitment level.
+// The returned error is the Confirmation's Err.
+func (t *ConfirmationTracker) Wait(ctx context.Context, sig solana.Signature, commitment rpc.CommitmentType) (*Confirmation, error) {
+	confirmation := t.wait(ctx, sig, commitment)
+	return confirmation, confirmation.Err
+}
+
+// WaitOpts controls WaitForConfirmation.
+type WaitOpts struct {
+	// Commitment is the level to wait for. It defaults to confirmed.
+	Commitment rpc.CommitmentType
+	// LastValidBlockHeight, when set, makes the wait fail with
+	// ErrBlockhashExpired once the block height passes it and the
+	// transaction has not landed. It is returned with the blockhash the
+	// transaction was built with.
+	LastValidBlockHeight uint64
+	// Backoff spaces the polls: InitialBackoff, MaxBackoff and Multiplier
+	// apply, with jitter, and MaxAttempts is ignored since ctx and the
+	// block height bound the wait. The zero value uses
+	// DefaultWaitBackoff.
+	Backoff RetryPolicy
+}
+
+// DefaultWaitBackoff polls after 250ms, then backs off up to every 4s.
+func DefaultWaitBackoff() RetryPolicy {
+	return RetryPolicy{
+		InitialBackoff: 250 * time.Millisecond,
+		MaxBackoff:     4 * time.Second,
+		Multiplier:     1.5,
+	}
+}
+
+// WaitForConfirmation polls getSignatureStatuses with jittered
+// exponential backoff until the transaction reaches the commitment level,
+// fails, its blockhash expires, or ctx is done. Unlike Wait it never uses
+// the websocket, for environments where websockets are unavailable, and
+// backs off instead of polling at a fixed interval. The returned error is
+// the Confirmation's Err, or the RPC error that cannot be retried.
+func (t *ConfirmationTracker) WaitForConfirmation(ctx context.Context, sig solana.Signature, opts WaitOpts) (*Confirmation, error) {
+	commitment := opts.Commitment
+	if commitment == "" {
+		commitment = rpc.CommitmentConfirmed
+	}
+	if err := validateCommitment(commitment); err != nil {
+		return nil, err
+	}
+	backoff := opts.Backoff
+	if backoff.InitialBackoff <= 0 {
+		backoff = DefaultWaitBackoff()
+	}
+
+	for retry := 1; ; retry++ {
+		statuses, err := t.client.GetSignatureStatuses(ctx, false, sig)
+		if err != nil && !IsRetryable(err) {
+			return nil, fmt.Errorf("error while GetSignatureStatuses: %w", err)
+		}
+		var status *rpc.SignatureStatusesResult
+		if err == nil && len(statuses.Value) > 0 {
+			status = statuses.Value[0]
+		}
+		if status != nil {
+			confirmation := &Confirmation{Signature: sig, Slot: status.Slot, Commitment: commitment}
+			if status.Err != nil {
+				confirmation.Err = DecodeTransactionError(sig, status.Err)
+				return confirmation, confirmation.Err
+			}
+			if reachedCommitment(status.ConfirmationStatus, commitment) {
+				return confirmation, nil
+			}
+		}
+
+		// Once the transaction has landed it can no longer expire.
+		if status == nil && err == nil && opts.LastValidBlockHeight > 0 {
+			height, err := t.client.GetBlockHeight(ctx, rpc.CommitmentConfirmed)
+			if err == nil && height > opts.LastValidBlockHeight {
+				confirmation := &Confirmation{Signature: sig, Commitment: commitment, Err: fmt.Errorf("transaction %s: %w", sig, ErrBlockhashExpired)}
+				return confirmation, confirmation.Err
+			}
+		}
+
+		timer := time.NewTimer(backoff.Backoff(retry, err))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			confirmation := &Confirmation{Signature: sig, Commitment: commitment, Err: ctx.Err()}
+			return confirmation, confirmation.Err
+		case <-timer.C:
+		}
+	}
+}
+
+func (t *ConfirmationTracker) wait(ctx context.Context, sig solana.Signature, commitment rpc.CommitmentType) *Confirmation {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	subscribed := t.subscribe(ctx, sig, commitment)
+	ticker := time.NewTicker(t.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if confirmation := t.poll(ctx, sig, commitment); confirmation != nil {
+			return confirmation
+		}
+		select {
+		case <-ctx.Done():
+			return &Confirmation{Signature: sig, Commitment: commitment, Err: ctx.Err()}
+		case confirmation := <-subscribed:
+			return confirmation
+		case <-ticker.C:
+		}
+	}
+}
+
+// subscribe returns a channel fed by signatureSubscribe, or nil when no
+// websocket is configured or the subscription fails.
+func (t *ConfirmationTracker) subscribe(ctx context.Context, sig solana.Signature, commitment rpc.CommitmentType) <-chan *Confirmation {
+	if t.ws == nil {
+		return nil
+	}
+	sub, err := t.ws.SignatureSubscribe(sig, commitment)
+	if err != nil {
+		return nil
+	}
+	out := make(chan *Confirmation, 1)
+	go func() {
+		defer sub.Unsubscribe()
+		result, err := sub.Recv(ctx)
+		if err != nil {
+			// Leave it to polling.
+			return
+		}
+		confirmation := &Confirmation{Signature: sig, Slot: result.Context.Slot, Commitment: commitment}
+		if result.Value.Err != nil {
+			confirmation.Err = DecodeTransactionError(sig, result.Value.Err)
+		}
+		out <- confirmation
+	}()
+	return out
+}
+
+func (t *ConfirmationTracker) poll(ctx context.Context, sig solana.Signature, commitment rpc.CommitmentType) *Confirmation {
+	statuses, err := t.client.GetSignatureStatuses(ctx, false, sig)
+	if err != nil || len(statuses.Value) == 0 || statuses.Value[0] == nil {
+		return nil
+	}
+	status := statuses.Value[0]
+	if status.Err != nil {
+		return &Confirmation{Signature: sig, Slot: status.Slot, Commitment: commitment, Err: DecodeTransactionError(sig, status.Err)}
+	}
+	if reachedCommitment(status.ConfirmationStatus, commitment) {
+		return &Confirmation{Signature: sig, Slot: status.Slot, Commitment: commitment}
+	}
+	return nil
+}
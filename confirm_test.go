@@ -0,0 +1,196 @@
+package token2022
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestConfirmationTrackerPolling(t *testing.T) {
+
+	var (
+		client  = newMockRPC()
+		okSig   = solana.SignatureFromBytes(make([]byte, 64))
+		failSig = solana.MustSignatureFromBase58("5VERv8NMvzbJMEkV8xnrLkEaWRtSz9CosKDYjCJjBRnbJLgp8uirBgmQpjKhoR4tjF3ZpRzrFmBV6UjKdiSZkQUW")
+	)
+	client.statuses[okSig] = &rpc.SignatureStatusesResult{Slot: 10, ConfirmationStatus: rpc.ConfirmationStatusFinalized}
+
+	var rawErr interface{}
+	if err := json.Unmarshal([]byte(`{"InstructionError":[1,{"Custom":1}]}`), &rawErr); err != nil {
+		t.Fatalf("Error decoding error: %v", err)
+	}
+	client.statuses[failSig] = &rpc.SignatureStatusesResult{Slot: 11, Err: rawErr}
+
+	tracker := NewConfirmationTracker(client).SetPollInterval(time.Millisecond)
+
+	confirmation := <-tracker.Track(context.Background(), okSig, rpc.CommitmentConfirmed)
+	if confirmation.Err != nil || confirmation.Slot != 10 {
+		t.Errorf("Expected confirmation at slot 10, got %+v", confirmation)
+	}
+
+	_, err := tracker.Wait(context.Background(), failSig, rpc.CommitmentConfirmed)
+	var txErr *TransactionError
+	if !errors.As(err, &txErr) {
+		t.Fatalf("Expected TransactionError, got %v", err)
+	}
+	if txErr.InstructionIndex != 1 || txErr.Custom == nil || *txErr.Custom != 1 {
+		t.Errorf("Unexpected decoded error %+v", txErr)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	pending := solana.SignatureFromBytes(append(make([]byte, 63), 1))
+	if _, err := tracker.Wait(ctx, pending, rpc.CommitmentConfirmed); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestDecodeTransactionError(t *testing.T) {
+	txErr := DecodeTransactionError(solana.Signature{}, "InsufficientFundsForFee")
+	if txErr.Kind != "InsufficientFundsForFee" || txErr.InstructionIndex != -1 {
+		t.Errorf("Unexpected decoded error %+v", txErr)
+	}
+
+	var raw interface{}
+	if err := json.Unmarshal([]byte(`{"InstructionError":[0,"InvalidAccountData"]}`), &raw); err != nil {
+		t.Fatalf("Error decoding error: %v", err)
+	}
+	txErr = DecodeTransactionError(solana.Signature{}, raw)
+	if txErr.Kind != "InvalidAccountData" || txErr.InstructionIndex != 0 || txErr.Custom != nil {
+		t.Errorf("Unexpected decoded error %+v", txErr)
+	}
+}
+
+// stagedRPC reports each signature as confirmed for the first polls and
+// finalized afterwards.
+type stagedRPC struct {
+	*mockRPC
+	mu        sync.Mutex
+	confirmed int
+	polls     int
+}
+
+func (s *stagedRPC) GetSignatureStatuses(ctx context.Context, searchTransactionHistory bool, transactionSignatures ...solana.Signature) (*rpc.GetSignatureStatusesResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.polls++
+	status := rpc.ConfirmationStatusConfirmed
+	if s.polls > s.confirmed {
+		status = rpc.ConfirmationStatusFinalized
+	}
+	out := &rpc.GetSignatureStatusesResult{}
+	for range transactionSignatures {
+		out.Value = append(out.Value, &rpc.SignatureStatusesResult{Slot: uint64(s.polls), ConfirmationStatus: status})
+	}
+	return out, nil
+}
+
+func TestConfirmationTrackerStages(t *testing.T) {
+
+	client := &stagedRPC{mockRPC: newMockRPC(), confirmed: 3}
+	tracker := NewConfirmationTracker(client).SetPollInterval(time.Millisecond)
+	sig := solana.SignatureFromBytes(make([]byte, 64))
+
+	var got []*Confirmation
+	for confirmation := range tracker.TrackStages(context.Background(), sig, rpc.CommitmentConfirmed, rpc.CommitmentFinalized) {
+		got = append(got, confirmation)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 stages, got %d", len(got))
+	}
+	if got[0].Err != nil || got[0].Commitment != rpc.CommitmentConfirmed || got[0].Slot != 1 {
+		t.Errorf("Expected confirmed at slot 1, got %+v", got[0])
+	}
+	if got[1].Err != nil || got[1].Commitment != rpc.CommitmentFinalized || got[1].Slot != 4 {
+		t.Errorf("Expected finalized at slot 4, got %+v", got[1])
+	}
+
+	for _, commitments := range [][]rpc.CommitmentType{
+		nil,
+		{rpc.CommitmentFinalized, rpc.CommitmentConfirmed},
+		{rpc.CommitmentConfirmed, "recent"},
+	} {
+		confirmation := <-tracker.TrackStages(context.Background(), sig, commitments...)
+		if !errors.Is(confirmation.Err, ErrNotSet) && !errors.Is(confirmation.Err, ErrInvalidField) {
+			t.Errorf("Expected an error for %v, got %v", commitments, confirmation.Err)
+		}
+	}
+}
+
+func TestConfirmationTrackerConfirmedThenFinalized(t *testing.T) {
+
+	client := &stagedRPC{mockRPC: newMockRPC(), confirmed: 2}
+	tracker := NewConfirmationTracker(client).SetPollInterval(time.Millisecond)
+	sig := solana.SignatureFromBytes(make([]byte, 64))
+
+	confirmed := make(chan *Confirmation, 1)
+	finalized := make(chan *Confirmation, 1)
+	tracker.OnConfirmedThenFinalized(context.Background(), sig,
+		func(c *Confirmation) { confirmed <- c },
+		func(c *Confirmation) { finalized <- c },
+	)
+	if c := <-confirmed; c.Err != nil || c.Commitment != rpc.CommitmentConfirmed {
+		t.Errorf("Expected confirmed callback, got %+v", c)
+	}
+	if c := <-finalized; c.Err != nil || c.Commitment != rpc.CommitmentFinalized {
+		t.Errorf("Expected finalized callback, got %+v", c)
+	}
+
+	// A pending transaction reports the cancellation to onConfirmed only.
+	pending := newMockRPC()
+	tracker = NewConfirmationTracker(pending).SetPollInterval(time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	done := make(chan struct{})
+	tracker.OnConfirmedThenFinalized(ctx, sig,
+		func(c *Confirmation) {
+			if !errors.Is(c.Err, context.DeadlineExceeded) {
+				t.Errorf("Expected deadline exceeded, got %v", c.Err)
+			}
+			close(done)
+		},
+		func(c *Confirmation) { t.Errorf("Unexpected finalized callback %+v", c) },
+	)
+	<-done
+}
+
+func TestWaitForConfirmation(t *testing.T) {
+
+	backoff := RetryPolicy{InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond, Multiplier: 2}
+	sig := solana.SignatureFromBytes(make([]byte, 64))
+
+	staged := &stagedRPC{mockRPC: newMockRPC(), confirmed: 2}
+	confirmation, err := NewConfirmationTracker(staged).WaitForConfirmation(context.Background(), sig, WaitOpts{
+		Commitment:           rpc.CommitmentFinalized,
+		LastValidBlockHeight: 1000,
+		Backoff:              backoff,
+	})
+	if err != nil {
+		t.Fatalf("WaitForConfirmation: %v", err)
+	}
+	if confirmation.Slot != 3 || confirmation.Commitment != rpc.CommitmentFinalized {
+		t.Errorf("Expected finalized at slot 3, got %+v", confirmation)
+	}
+
+	client := newMockRPC()
+	client.blockHeight = 1001
+	tracker := NewConfirmationTracker(client)
+	_, err = tracker.WaitForConfirmation(context.Background(), sig, WaitOpts{LastValidBlockHeight: 1000, Backoff: backoff})
+	if !errors.Is(err, ErrBlockhashExpired) {
+		t.Errorf("Expected ErrBlockhashExpired, got %v", err)
+	}
+
+	client.err = rpc.ErrNotFound
+	if _, err := tracker.WaitForConfirmation(context.Background(), sig, WaitOpts{Backoff: backoff}); !errors.Is(err, rpc.ErrNotFound) {
+		t.Errorf("Expected the non-retryable error, got %v", err)
+	}
+	if _, err := tracker.WaitForConfirmation(context.Background(), sig, WaitOpts{Commitment: "recent"}); !errors.Is(err, ErrInvalidField) {
+		t.Errorf("Expected ErrInvalidField, got %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// COptionLayout is a way the token programs pack Rust's COption, an
+// optional value. A nil pointer is None throughout this package.
+type COptionLayout uint8
+
+const (
+	// COptionInstruction is the layout of instruction data: a one-byte
+	// tag, followed by the value only when it is set.
+	COptionInstruction COptionLayout = 1
+	// COptionAccount is the layout of account state: a four-byte
+	// little-endian tag, followed by the value, zeroed when unset, so that
+	// the field has a fixed size.
+	COptionAccount COptionLayout = 4
+)
+
+// WriteCOption writes value, or None when it is nil, in the given layout.
+// write encodes a value; it is also called with the zero value for None in
+// the COptionAccount layout.
+func WriteCOption[T any](encoder *bin.Encoder, layout COptionLayout, value *T, write func(*bin.Encoder, T) error) error {
+	var tag uint32
+	if value != nil {
+		tag = 1
+	}
+	switch layout {
+	case COptionInstruction:
+		if err := encoder.WriteUint8(uint8(tag)); err != nil {
+			return err
+		}
+		if value == nil {
+			return nil
+		}
+		return write(encoder, *value)
+	case COptionAccount:
+		if err := encoder.WriteUint32(tag, bin.LE); err != nil {
+			return err
+		}
+		if value == nil {
+			var zero T
+			return write(encoder, zero)
+		}
+		return write(encoder, *value)
+	}
+	return fmt.Errorf("invalid COption layout %d", layout)
+}
+
+// ReadCOption reads an optional value in the given layout, returning nil
+// for None. read decodes a value; in the COptionAccount layout it also
+// consumes the zeroed value of None.
+func ReadCOption[T any](decoder *bin.Decoder, layout COptionLayout, read func(*bin.Decoder) (T, error)) (*T, error) {
+	var tag uint32
+	switch layout {
+	case COptionInstruction:
+		flag, err := decoder.ReadUint8()
+		if err != nil {
+			return nil, err
+		}
+		tag = uint32(flag)
+	case COptionAccount:
+		var err error
+		if tag, err = decoder.ReadUint32(bin.LE); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("invalid COption layout %d", layout)
+	}
+	if tag > 1 {
+		return nil, fmt.Errorf("invalid option flag %d", tag)
+	}
+	if tag == 0 && layout == COptionInstruction {
+		return nil, nil
+	}
+	value, err := read(decoder)
+	if err != nil || tag == 0 {
+		return nil, err
+	}
+	return &value, nil
+}
+
+// WriteCOptionPubkey writes an optional public key.
+func WriteCOptionPubkey(encoder *bin.Encoder, layout COptionLayout, key *solana.PublicKey) error {
+	return WriteCOption(encoder, layout, key, func(encoder *bin.Encoder, key solana.PublicKey) error {
+		return encoder.WriteBytes(key[:], false)
+	})
+}
+
+// ReadCOptionPubkey reads an optional public key.
+func ReadCOptionPubkey(decoder *bin.Decoder, layout COptionLayout) (*solana.PublicKey, error) {
+	return ReadCOption(decoder, layout, readPubkey)
+}
+
+// WriteCOptionUint64 writes an optional little-endian u64.
+func WriteCOptionUint64(encoder *bin.Encoder, layout COptionLayout, value *uint64) error {
+	return WriteCOption(encoder, layout, value, func(encoder *bin.Encoder, value uint64) error {
+		return encoder.WriteUint64(value, bin.LE)
+	})
+}
+
+// ReadCOptionUint64 reads an optional little-endian u64.
+func ReadCOptionUint64(decoder *bin.Decoder, layout COptionLayout) (*uint64, error) {
+	return ReadCOption(decoder, layout, func(decoder *bin.Decoder) (uint64, error) {
+		return decoder.ReadUint64(bin.LE)
+	})
+}
@@ -0,0 +1,78 @@
+package token2022
+
+import (
+	"bytes"
+	"testing"
+
+	bin "github.com/gagliardetto/binary"
+	solana "github.com/gagliardetto/solana-go"
+)
+
+func TestCOption(t *testing.T) {
+	key := solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	amount := uint64(42)
+	cases := []struct {
+		layout COptionLayout
+		key    *solana.PublicKey
+		amount *uint64
+		size   int
+	}{
+		{COptionInstruction, &key, &amount, 1 + 32 + 1 + 8},
+		{COptionInstruction, nil, nil, 2},
+		{COptionAccount, &key, &amount, 4 + 32 + 4 + 8},
+		{COptionAccount, nil, nil, 4 + 32 + 4 + 8},
+	}
+	for _, c := range cases {
+		var buf bytes.Buffer
+		encoder := bin.NewBinEncoder(&buf)
+		if err := WriteCOptionPubkey(encoder, c.layout, c.key); err != nil {
+			t.Fatalf("WriteCOptionPubkey: %v", err)
+		}
+		if err := WriteCOptionUint64(encoder, c.layout, c.amount); err != nil {
+			t.Fatalf("WriteCOptionUint64: %v", err)
+		}
+		if buf.Len() != c.size {
+			t.Errorf("Layout %d: expected %d bytes, got %d", c.layout, c.size, buf.Len())
+		}
+
+		decoder := bin.NewBinDecoder(buf.Bytes())
+		gotKey, err := ReadCOptionPubkey(decoder, c.layout)
+		if err != nil {
+			t.Fatalf("ReadCOptionPubkey: %v", err)
+		}
+		gotAmount, err := ReadCOptionUint64(decoder, c.layout)
+		if err != nil {
+			t.Fatalf("ReadCOptionUint64: %v", err)
+		}
+		if (gotKey == nil) != (c.key == nil) || gotKey != nil && *gotKey != key {
+			t.Errorf("Layout %d: expected key %v, got %v", c.layout, c.key, gotKey)
+		}
+		if (gotAmount == nil) != (c.amount == nil) || gotAmount != nil && *gotAmount != amount {
+			t.Errorf("Layout %d: expected amount %v, got %v", c.layout, c.amount, gotAmount)
+		}
+		if decoder.Remaining() != 0 {
+			t.Errorf("Layout %d: %d bytes left", c.layout, decoder.Remaining())
+		}
+	}
+
+	// The account layout matches the state encoding of a mint authority.
+	data := EncodeMint(&Mint{MintAuthority: &key, IsInitialized: true})
+	got, err := ReadCOptionPubkey(bin.NewBinDecoder(data[:36]), COptionAccount)
+	if err != nil || got == nil || *got != key {
+		t.Errorf("Expected the mint authority, got %v, %v", got, err)
+	}
+
+	for _, c := range []struct {
+		layout COptionLayout
+		data   []byte
+	}{
+		{COptionInstruction, []byte{2}},
+		{COptionAccount, []byte{2, 0, 0, 0}},
+		{COptionInstruction, []byte{1, 0}},
+		{COptionLayout(2), []byte{0}},
+	} {
+		if _, err := ReadCOptionPubkey(bin.NewBinDecoder(c.data), c.layout); err == nil {
+			t.Errorf("Layout %d: expected an error for %v", c.layout, c.data)
+		}
+	}
+}
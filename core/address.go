@@ -0,0 +1,113 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"crypto/sha256"
+	"errors"
+
+	"filippo.io/edwards25519/field"
+)
+
+// MaxSeedLength is the maximum length of a single program address seed.
+const MaxSeedLength = 32
+
+// pdaMarker is appended to the seeds hashed into a program derived address.
+const pdaMarker = "ProgramDerivedAddress"
+
+// CreateProgramAddress derives the program address of seeds, which must
+// include the bump seed, under programID. It fails when the result is on
+// the curve.
+func CreateProgramAddress(seeds [][]byte, programID PublicKey) (PublicKey, error) {
+	h := sha256.New()
+	for _, seed := range seeds {
+		if len(seed) > MaxSeedLength {
+			return PublicKey{}, errors.New("max seed length exceeded")
+		}
+		h.Write(seed)
+	}
+	h.Write(programID[:])
+	h.Write([]byte(pdaMarker))
+	var hash [32]byte
+	h.Sum(hash[:0])
+	if IsOnCurve(hash) {
+		return PublicKey{}, errors.New("invalid seeds, address must fall off the curve")
+	}
+	return PublicKey(hash), nil
+}
+
+// FindProgramAddress returns the first program address of seeds under
+// programID, trying bump seeds from 255 down, and the bump seed.
+func FindProgramAddress(seeds [][]byte, programID PublicKey) (PublicKey, uint8, error) {
+	withBump := append(seeds[:len(seeds):len(seeds)], nil)
+	for bump := 255; bump > 0; bump-- {
+		withBump[len(seeds)] = []byte{byte(bump)}
+		address, err := CreateProgramAddress(withBump, programID)
+		if err == nil {
+			return address, uint8(bump), nil
+		}
+	}
+	return PublicKey{}, 0, ErrNoProgramAddress
+}
+
+// FindAssociatedTokenAddress derives the associated token account of
+// wallet for mint under tokenProgram. It hashes the seeds from a fixed
+// stack buffer, so the derivation does not allocate.
+func FindAssociatedTokenAddress(wallet, mint, tokenProgram PublicKey) (PublicKey, uint8, error) {
+	var buf [3*32 + 1 + 32 + len(pdaMarker)]byte
+	copy(buf[0:], wallet[:])
+	copy(buf[32:], tokenProgram[:])
+	copy(buf[64:], mint[:])
+	copy(buf[97:], AssociatedTokenProgramID[:])
+	copy(buf[129:], pdaMarker)
+
+	for bump := 255; bump > 0; bump-- {
+		buf[96] = byte(bump)
+		hash := sha256.Sum256(buf[:])
+		if !IsOnCurve(hash) {
+			return PublicKey(hash), uint8(bump), nil
+		}
+	}
+	return PublicKey{}, 0, ErrNoProgramAddress
+}
+
+// one is the field element 1.
+var one = new(field.Element).One()
+
+// d is the edwards25519 curve constant -121665/121666.
+var d, _ = new(field.Element).SetBytes([]byte{
+	0xa3, 0x78, 0x59, 0x13, 0xca, 0x4d, 0xeb, 0x75,
+	0xab, 0xd8, 0x41, 0x41, 0x4d, 0x0a, 0x70, 0x00,
+	0x98, 0xe8, 0x79, 0x77, 0x79, 0x40, 0xc7, 0x8c,
+	0x73, 0xfe, 0x6f, 0x2b, 0xee, 0x6c, 0x03, 0x52,
+})
+
+// IsOnCurve reports whether b decodes to an edwards25519 point, and so
+// may be a wallet with a private key. Program derived addresses are off
+// the curve. edwards25519.Point.SetBytes allocates its error on every
+// off-curve input, so the decoding is done on field elements here.
+func IsOnCurve(b [32]byte) bool {
+	var y, y2, u, v, x field.Element
+	if _, err := y.SetBytes(b[:]); err != nil {
+		return false
+	}
+	// x² = (y² - 1) / (dy² + 1) must have a square root.
+	y2.Square(&y)
+	u.Subtract(&y2, one)
+	v.Multiply(&y2, d)
+	v.Add(&v, one)
+	_, wasSquare := x.SqrtRatio(&u, &v)
+	return wasSquare == 1
+}
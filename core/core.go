@@ -0,0 +1,81 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package core holds the wire logic of Token-2022 that needs neither
+// networking nor reflection: public keys, program derived and associated
+// token addresses, the fixed-size instruction encodings and the mint and
+// token account layouts with their extension TLV data.
+//
+// It only depends on the standard library, filippo.io/edwards25519 and
+// github.com/mr-tron/base58, and builds with GOOS=js GOARCH=wasm and with
+// TinyGo, so browser tooling runs the exact code the token2022 package
+// uses, which delegates to it.
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mr-tron/base58"
+)
+
+// PublicKey is a 32-byte ed25519 public key or program derived address.
+// It converts to and from solana.PublicKey.
+type PublicKey [32]byte
+
+// Well-known program IDs.
+var (
+	Token2022ProgramID       = MustPublicKeyFromBase58("TokenzQdBNbLqP5VEhdkAS6EPFLC1PHnBqCXEpPxuEb")
+	TokenProgramID           = MustPublicKeyFromBase58("TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA")
+	AssociatedTokenProgramID = MustPublicKeyFromBase58("ATokenGPvbdGVxr1b2hvZbsiqW5xWH25efTNsLJA8knL")
+	SystemProgramID          = PublicKey{}
+	SysvarRentID             = MustPublicKeyFromBase58("SysvarRent111111111111111111111111111111111")
+)
+
+// PublicKeyFromBase58 decodes a base58 public key.
+func PublicKeyFromBase58(s string) (PublicKey, error) {
+	b, err := base58.Decode(s)
+	if err != nil {
+		return PublicKey{}, fmt.Errorf("invalid public key %q: %w", s, err)
+	}
+	if len(b) != len(PublicKey{}) {
+		return PublicKey{}, fmt.Errorf("invalid public key %q: %d bytes, expected 32", s, len(b))
+	}
+	return PublicKey(b), nil
+}
+
+// MustPublicKeyFromBase58 is PublicKeyFromBase58 that panics on error.
+func MustPublicKeyFromBase58(s string) PublicKey {
+	key, err := PublicKeyFromBase58(s)
+	if err != nil {
+		panic(err)
+	}
+	return key
+}
+
+func (k PublicKey) String() string {
+	return base58.Encode(k[:])
+}
+
+func (k PublicKey) IsZero() bool {
+	return k == PublicKey{}
+}
+
+// ErrInvalidAccountData is wrapped by the decoders of mints and token
+// accounts when the data does not have the expected layout.
+var ErrInvalidAccountData = errors.New("invalid account data")
+
+// ErrNoProgramAddress is returned when no bump seed yields an address off
+// the curve, which practically never happens.
+var ErrNoProgramAddress = errors.New("unable to find a valid program address")
@@ -0,0 +1,149 @@
+package core_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/dwmfan/token2022"
+	"github.com/dwmfan/token2022/core"
+	solana "github.com/gagliardetto/solana-go"
+)
+
+var (
+	wallet      = core.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	mint        = core.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	source      = core.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+	destination = core.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+)
+
+func TestPublicKey(t *testing.T) {
+
+	if wallet.String() != "nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun" {
+		t.Errorf("Expected the base58 key back, got %s", wallet)
+	}
+	if core.Token2022ProgramID != core.PublicKey(solana.Token2022ProgramID) || core.AssociatedTokenProgramID != core.PublicKey(solana.SPLAssociatedTokenAccountProgramID) {
+		t.Error("Expected the program IDs of solana-go")
+	}
+	if _, err := core.PublicKeyFromBase58("3yZe7d"); err == nil {
+		t.Error("Expected an error for a short key")
+	}
+}
+
+func TestAddressesMatchSolanaGo(t *testing.T) {
+
+	for _, tokenProgram := range []core.PublicKey{core.Token2022ProgramID, core.TokenProgramID} {
+		address, bump, err := core.FindAssociatedTokenAddress(wallet, mint, tokenProgram)
+		if err != nil {
+			t.Fatalf("FindAssociatedTokenAddress: %v", err)
+		}
+		seeds := [][]byte{wallet[:], tokenProgram[:], mint[:]}
+		expected, expectedBump, err := solana.FindProgramAddress(seeds, solana.SPLAssociatedTokenAccountProgramID)
+		if err != nil {
+			t.Fatalf("FindProgramAddress: %v", err)
+		}
+		if address != core.PublicKey(expected) || bump != expectedBump {
+			t.Errorf("Expected %s/%d, got %s/%d", expected, expectedBump, address, bump)
+		}
+		generic, genericBump, err := core.FindProgramAddress(seeds, core.AssociatedTokenProgramID)
+		if err != nil || generic != address || genericBump != bump {
+			t.Errorf("Expected FindProgramAddress to agree, got %s/%d, %v", generic, genericBump, err)
+		}
+	}
+
+	for _, key := range []core.PublicKey{wallet, mint, source, destination, core.Token2022ProgramID} {
+		if core.IsOnCurve(key) != solana.IsOnCurve(key[:]) {
+			t.Errorf("IsOnCurve(%s) disagrees with solana-go", key)
+		}
+	}
+}
+
+func TestInstructionsMatchBuilders(t *testing.T) {
+
+	create, err := core.CreateAssociatedTokenAccount(wallet, destination, mint, core.Token2022ProgramID, true)
+	if err != nil {
+		t.Fatalf("CreateAssociatedTokenAccount: %v", err)
+	}
+	for _, tc := range []struct {
+		name     string
+		got      core.Instruction
+		expected solana.Instruction
+	}{
+		{"TransferChecked", core.TransferChecked(source, mint, destination, wallet, 1<<40+7, 9),
+			token2022.NewTransferChecked2022Instruction(1<<40+7, 9, solana.PublicKey(source), solana.PublicKey(mint), solana.PublicKey(destination), solana.PublicKey(wallet)).Build()},
+		{"MintToChecked", core.MintToChecked(mint, destination, wallet, 42, 6),
+			token2022.NewMintToChecked2022Instruction(42, 6, solana.PublicKey(mint), solana.PublicKey(destination), solana.PublicKey(wallet)).Build()},
+		{"BurnChecked", core.BurnChecked(source, mint, wallet, 42, 6),
+			token2022.NewBurnChecked2022Instruction(42, 6, solana.PublicKey(source), solana.PublicKey(mint), solana.PublicKey(wallet)).Build()},
+		{"CreateIdempotent", create,
+			token2022.NewCreate2022Instruction(solana.PublicKey(wallet), solana.PublicKey(destination), solana.PublicKey(mint)).SetIdempotent(true).Build()},
+	} {
+		if tc.got.ProgramID != core.PublicKey(tc.expected.ProgramID()) {
+			t.Errorf("%s: expected program %s, got %s", tc.name, tc.expected.ProgramID(), tc.got.ProgramID)
+		}
+		data, err := tc.expected.Data()
+		if err != nil {
+			t.Fatalf("%s: Data: %v", tc.name, err)
+		}
+		if !bytes.Equal(tc.got.Data, data) {
+			t.Errorf("%s: expected data %x, got %x", tc.name, data, tc.got.Data)
+		}
+		accounts := tc.expected.Accounts()
+		if len(tc.got.Accounts) != len(accounts) {
+			t.Fatalf("%s: expected %d accounts, got %d", tc.name, len(accounts), len(tc.got.Accounts))
+		}
+		for i, meta := range accounts {
+			got := tc.got.Accounts[i]
+			if got.PublicKey != core.PublicKey(meta.PublicKey) || got.IsSigner != meta.IsSigner || got.IsWritable != meta.IsWritable {
+				t.Errorf("%s: account %d: expected %+v, got %+v", tc.name, i, meta, got)
+			}
+		}
+	}
+}
+
+func TestStateMatchesToken2022(t *testing.T) {
+
+	authority := wallet
+	reserve := uint64(2_039_280)
+	account := &core.TokenAccount{
+		Mint:       mint,
+		Owner:      wallet,
+		Amount:     500,
+		Delegate:   &authority,
+		State:      core.AccountStateFrozen,
+		IsNative:   &reserve,
+		Extensions: []core.Extension{{Type: uint16(token2022.ExtensionImmutableOwner), Data: []byte{}}},
+	}
+	data := core.EncodeTokenAccount(account)
+	if core.AccountTypeOf(data) != core.AccountTypeAccount {
+		t.Errorf("Expected an account type, got %d", core.AccountTypeOf(data))
+	}
+	decoded, err := token2022.DecodeTokenAccount(data)
+	if err != nil {
+		t.Fatalf("DecodeTokenAccount: %v", err)
+	}
+	if decoded.Amount != 500 || !decoded.IsFrozen() || *decoded.Delegate != solana.PublicKey(wallet) || *decoded.IsNative != reserve {
+		t.Errorf("Unexpected account %+v", decoded)
+	}
+	if _, ok := decoded.Extension(token2022.ExtensionImmutableOwner); !ok {
+		t.Error("Expected the ImmutableOwner extension")
+	}
+	if !bytes.Equal(token2022.EncodeTokenAccount(decoded), data) {
+		t.Error("Expected token2022 to encode the same bytes")
+	}
+
+	roundTrip, err := core.DecodeMint(core.EncodeMint(&core.Mint{MintAuthority: &authority, Supply: 7, Decimals: 2, IsInitialized: true}))
+	if err != nil {
+		t.Fatalf("DecodeMint: %v", err)
+	}
+	if roundTrip.Supply != 7 || roundTrip.Decimals != 2 || *roundTrip.MintAuthority != wallet || roundTrip.FreezeAuthority != nil {
+		t.Errorf("Unexpected mint %+v", roundTrip)
+	}
+
+	if _, err := core.DecodeMint(data[:10]); !errors.Is(err, core.ErrInvalidAccountData) || !errors.Is(err, token2022.ErrInvalidAccountData) {
+		t.Errorf("Expected ErrInvalidAccountData, got %v", err)
+	}
+	if _, err := core.DecodeExtensions(append(data, 9, 0, 200, 0), core.AccountTypeAccount); !errors.Is(err, core.ErrInvalidAccountData) {
+		t.Errorf("Expected an overrunning extension to fail, got %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "encoding/binary"
+
+// Instruction tags, the first byte of Token-2022 instruction data.
+const (
+	TagTransfer        uint8 = 3
+	TagMintTo          uint8 = 7
+	TagTransferChecked uint8 = 12
+	TagMintToChecked   uint8 = 14
+	TagBurnChecked     uint8 = 15
+)
+
+// Sizes in bytes of the instruction data written by the Append*Data
+// functions.
+const (
+	TransferDataSize        = 9
+	TransferCheckedDataSize = 10
+	MintToDataSize          = 9
+	MintToCheckedDataSize   = 10
+	BurnCheckedDataSize     = 10
+)
+
+// AppendTransferData appends the data of a Transfer instruction to dst.
+// It does not allocate when dst has TransferDataSize bytes of capacity
+// left.
+func AppendTransferData(dst []byte, amount uint64) []byte {
+	dst = append(dst, TagTransfer)
+	return binary.LittleEndian.AppendUint64(dst, amount)
+}
+
+// AppendTransferCheckedData appends the data of a TransferChecked
+// instruction to dst.
+func AppendTransferCheckedData(dst []byte, amount uint64, decimals uint8) []byte {
+	dst = append(dst, TagTransferChecked)
+	dst = binary.LittleEndian.AppendUint64(dst, amount)
+	return append(dst, decimals)
+}
+
+// AppendMintToData appends the data of a MintTo instruction to dst.
+func AppendMintToData(dst []byte, amount uint64) []byte {
+	dst = append(dst, TagMintTo)
+	return binary.LittleEndian.AppendUint64(dst, amount)
+}
+
+// AppendMintToCheckedData appends the data of a MintToChecked instruction
+// to dst.
+func AppendMintToCheckedData(dst []byte, amount uint64, decimals uint8) []byte {
+	dst = append(dst, TagMintToChecked)
+	dst = binary.LittleEndian.AppendUint64(dst, amount)
+	return append(dst, decimals)
+}
+
+// AppendBurnCheckedData appends the data of a BurnChecked instruction to
+// dst.
+func AppendBurnCheckedData(dst []byte, amount uint64, decimals uint8) []byte {
+	dst = append(dst, TagBurnChecked)
+	dst = binary.LittleEndian.AppendUint64(dst, amount)
+	return append(dst, decimals)
+}
+
+// AccountMeta is an account of an instruction.
+type AccountMeta struct {
+	PublicKey  PublicKey
+	IsSigner   bool
+	IsWritable bool
+}
+
+// Instruction is a program invocation, ready to be compiled into a
+// transaction message.
+type Instruction struct {
+	ProgramID PublicKey
+	Accounts  []AccountMeta
+	Data      []byte
+}
+
+// TransferChecked moves amount raw units from source to destination,
+// signed by owner, with decimals checked against the mint.
+func TransferChecked(source, mint, destination, owner PublicKey, amount uint64, decimals uint8) Instruction {
+	return Instruction{
+		ProgramID: Token2022ProgramID,
+		Accounts: []AccountMeta{
+			{PublicKey: source, IsWritable: true},
+			{PublicKey: mint},
+			{PublicKey: destination, IsWritable: true},
+			{PublicKey: owner, IsSigner: true},
+		},
+		Data: AppendTransferCheckedData(make([]byte, 0, TransferCheckedDataSize), amount, decimals),
+	}
+}
+
+// MintToChecked mints amount raw units to destination, signed by the mint
+// authority.
+func MintToChecked(mint, destination, authority PublicKey, amount uint64, decimals uint8) Instruction {
+	return Instruction{
+		ProgramID: Token2022ProgramID,
+		Accounts: []AccountMeta{
+			{PublicKey: mint, IsWritable: true},
+			{PublicKey: destination, IsWritable: true},
+			{PublicKey: authority, IsSigner: true},
+		},
+		Data: AppendMintToCheckedData(make([]byte, 0, MintToCheckedDataSize), amount, decimals),
+	}
+}
+
+// BurnChecked burns amount raw units from account, signed by its owner.
+func BurnChecked(account, mint, owner PublicKey, amount uint64, decimals uint8) Instruction {
+	return Instruction{
+		ProgramID: Token2022ProgramID,
+		Accounts: []AccountMeta{
+			{PublicKey: account, IsWritable: true},
+			{PublicKey: mint, IsWritable: true},
+			{PublicKey: owner, IsSigner: true},
+		},
+		Data: AppendBurnCheckedData(make([]byte, 0, BurnCheckedDataSize), amount, decimals),
+	}
+}
+
+// CreateAssociatedTokenAccount creates the associated token account of
+// wallet for mint under tokenProgram, paid by payer. The idempotent
+// variant succeeds when the account already exists. The rent sysvar is
+// passed for compatibility with older versions of the program.
+func CreateAssociatedTokenAccount(payer, wallet, mint, tokenProgram PublicKey, idempotent bool) (Instruction, error) {
+	address, _, err := FindAssociatedTokenAddress(wallet, mint, tokenProgram)
+	if err != nil {
+		return Instruction{}, err
+	}
+	data := []byte{0}
+	if idempotent {
+		data = []byte{1}
+	}
+	return Instruction{
+		ProgramID: AssociatedTokenProgramID,
+		Accounts: []AccountMeta{
+			{PublicKey: payer, IsSigner: true, IsWritable: true},
+			{PublicKey: address, IsWritable: true},
+			{PublicKey: wallet},
+			{PublicKey: mint},
+			{PublicKey: SystemProgramID},
+			{PublicKey: tokenProgram},
+			{PublicKey: SysvarRentID},
+		},
+		Data: data,
+	}, nil
+}
@@ -0,0 +1,240 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Sizes in bytes of the base Token-2022 account layouts, before any
+// extension data.
+const (
+	MintSize     = 82
+	AccountSize  = 165
+	MultisigSize = 355
+)
+
+// Account types, the byte written after the base layout of accounts that
+// carry extensions.
+const (
+	AccountTypeUninitialized uint8 = iota
+	AccountTypeMint
+	AccountTypeAccount
+)
+
+// Account states of a token account.
+const (
+	AccountStateUninitialized uint8 = iota
+	AccountStateInitialized
+	AccountStateFrozen
+)
+
+// AccountTypeOf returns the type of a Token-2022 account from its data:
+// the account type byte of an extended account, or the type implied by
+// the length of a base layout. Other data, such as a multisig, is
+// AccountTypeUninitialized.
+func AccountTypeOf(data []byte) uint8 {
+	switch {
+	case len(data) == MintSize:
+		return AccountTypeMint
+	case len(data) == AccountSize:
+		return AccountTypeAccount
+	case len(data) > AccountSize && len(data) != MultisigSize:
+		return data[AccountSize]
+	}
+	return AccountTypeUninitialized
+}
+
+// Extension is a raw TLV entry. Data aliases the account data it was
+// decoded from.
+type Extension struct {
+	Type uint16
+	Data []byte
+}
+
+// Mint is the base layout of a mint and its raw extensions.
+type Mint struct {
+	MintAuthority   *PublicKey
+	Supply          uint64
+	Decimals        uint8
+	IsInitialized   bool
+	FreezeAuthority *PublicKey
+	Extensions      []Extension
+}
+
+// TokenAccount is the base layout of a token account and its raw
+// extensions. IsNative holds the rent-exempt reserve of native accounts.
+type TokenAccount struct {
+	Mint            PublicKey
+	Owner           PublicKey
+	Amount          uint64
+	Delegate        *PublicKey
+	State           uint8
+	IsNative        *uint64
+	DelegatedAmount uint64
+	CloseAuthority  *PublicKey
+	Extensions      []Extension
+}
+
+// DecodeMint decodes the data of a mint account.
+func DecodeMint(data []byte) (*Mint, error) {
+	if len(data) < MintSize {
+		return nil, fmt.Errorf("%w: mint data too short: %d bytes", ErrInvalidAccountData, len(data))
+	}
+	mint := &Mint{
+		MintAuthority:   decodeOptionalKey(data[0:36]),
+		Supply:          binary.LittleEndian.Uint64(data[36:44]),
+		Decimals:        data[44],
+		IsInitialized:   data[45] != 0,
+		FreezeAuthority: decodeOptionalKey(data[46:82]),
+	}
+	if len(data) > MintSize {
+		extensions, err := DecodeExtensions(data, AccountTypeMint)
+		if err != nil {
+			return nil, err
+		}
+		mint.Extensions = extensions
+	}
+	return mint, nil
+}
+
+// DecodeTokenAccount decodes the data of a token account.
+func DecodeTokenAccount(data []byte) (*TokenAccount, error) {
+	if len(data) < AccountSize {
+		return nil, fmt.Errorf("%w: token account data too short: %d bytes", ErrInvalidAccountData, len(data))
+	}
+	if len(data) == MultisigSize {
+		return nil, fmt.Errorf("%w: account is a multisig, not a token account", ErrInvalidAccountData)
+	}
+	account := &TokenAccount{
+		Mint:            PublicKey(data[0:32]),
+		Owner:           PublicKey(data[32:64]),
+		Amount:          binary.LittleEndian.Uint64(data[64:72]),
+		Delegate:        decodeOptionalKey(data[72:108]),
+		State:           data[108],
+		DelegatedAmount: binary.LittleEndian.Uint64(data[121:129]),
+		CloseAuthority:  decodeOptionalKey(data[129:165]),
+	}
+	if binary.LittleEndian.Uint32(data[109:113]) != 0 {
+		rentExemptReserve := binary.LittleEndian.Uint64(data[113:121])
+		account.IsNative = &rentExemptReserve
+	}
+	if len(data) > AccountSize {
+		extensions, err := DecodeExtensions(data, AccountTypeAccount)
+		if err != nil {
+			return nil, err
+		}
+		account.Extensions = extensions
+	}
+	return account, nil
+}
+
+// EncodeMint encodes a mint in the on-chain layout, followed by its
+// extensions when it has any.
+func EncodeMint(mint *Mint) []byte {
+	data := make([]byte, MintSize)
+	encodeOptionalKey(data[0:36], mint.MintAuthority)
+	binary.LittleEndian.PutUint64(data[36:44], mint.Supply)
+	data[44] = mint.Decimals
+	if mint.IsInitialized {
+		data[45] = 1
+	}
+	encodeOptionalKey(data[46:82], mint.FreezeAuthority)
+	return EncodeExtensions(data, AccountTypeMint, mint.Extensions)
+}
+
+// EncodeTokenAccount encodes a token account in the on-chain layout,
+// followed by its extensions when it has any.
+func EncodeTokenAccount(account *TokenAccount) []byte {
+	data := make([]byte, AccountSize)
+	copy(data[0:32], account.Mint[:])
+	copy(data[32:64], account.Owner[:])
+	binary.LittleEndian.PutUint64(data[64:72], account.Amount)
+	encodeOptionalKey(data[72:108], account.Delegate)
+	data[108] = account.State
+	if account.IsNative != nil {
+		binary.LittleEndian.PutUint32(data[109:113], 1)
+		binary.LittleEndian.PutUint64(data[113:121], *account.IsNative)
+	}
+	binary.LittleEndian.PutUint64(data[121:129], account.DelegatedAmount)
+	encodeOptionalKey(data[129:165], account.CloseAuthority)
+	return EncodeExtensions(data, AccountTypeAccount, account.Extensions)
+}
+
+// DecodeExtensions indexes the TLV entries that follow the account type
+// byte of an extended account of type want. Data of the entries aliases
+// data.
+func DecodeExtensions(data []byte, want uint8) ([]Extension, error) {
+	if len(data) <= AccountSize {
+		return nil, fmt.Errorf("%w: invalid extended account length: %d bytes", ErrInvalidAccountData, len(data))
+	}
+	if got := data[AccountSize]; got != want {
+		return nil, fmt.Errorf("%w: unexpected account type %d, expected %d", ErrInvalidAccountData, got, want)
+	}
+	var extensions []Extension
+	tlv := data[AccountSize+1:]
+	for len(tlv) >= 4 {
+		extType := binary.LittleEndian.Uint16(tlv[0:2])
+		length := int(binary.LittleEndian.Uint16(tlv[2:4]))
+		if extType == 0 {
+			// The rest of the buffer is unused space.
+			break
+		}
+		if 4+length > len(tlv) {
+			return nil, fmt.Errorf("%w: extension %d overruns account data", ErrInvalidAccountData, extType)
+		}
+		extensions = append(extensions, Extension{Type: extType, Data: tlv[4 : 4+length]})
+		tlv = tlv[4+length:]
+	}
+	return extensions, nil
+}
+
+// EncodeExtensions pads base to the account size and appends the account
+// type and the TLV entries. base is returned unchanged when there are no
+// extensions.
+func EncodeExtensions(base []byte, accountType uint8, extensions []Extension) []byte {
+	if len(extensions) == 0 {
+		return base
+	}
+	data := make([]byte, AccountSize, AccountSize+1+4*len(extensions))
+	copy(data, base)
+	data = append(data, accountType)
+	for _, ext := range extensions {
+		data = binary.LittleEndian.AppendUint16(data, ext.Type)
+		data = binary.LittleEndian.AppendUint16(data, uint16(len(ext.Data)))
+		data = append(data, ext.Data...)
+	}
+	return data
+}
+
+// encodeOptionalKey encodes a COption<Pubkey> into a 36-byte slice.
+func encodeOptionalKey(data []byte, key *PublicKey) {
+	if key == nil {
+		return
+	}
+	binary.LittleEndian.PutUint32(data[0:4], 1)
+	copy(data[4:36], key[:])
+}
+
+// decodeOptionalKey decodes a COption<Pubkey>: a 4-byte tag followed by
+// the key.
+func decodeOptionalKey(data []byte) *PublicKey {
+	if binary.LittleEndian.Uint32(data[0:4]) == 0 {
+		return nil
+	}
+	key := PublicKey(data[4:36])
+	return &key
+}
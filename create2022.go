@@ -15,7 +15,6 @@
 package token2022
 
 import (
-	"errors"
 	"fmt"
 
 	bin "github.com/gagliardetto/binary"
@@ -31,6 +30,19 @@ const ProgramName = "Associated Token Account Program"
 var ProgramID = solana.SPLAssociatedTokenAccountProgramID
 
 type Create2022 struct {
+	// Idempotent makes the instruction succeed when the account already
+	// exists (CreateIdempotent) instead of failing.
+	Idempotent bool
+
+	// AllowOwnerOffCurve lets Validate accept a Wallet off the ed25519
+	// curve, such as a program derived address.
+	AllowOwnerOffCurve bool `bin:"-" borsh_skip:"true" json:"-"`
+
+	// TokenProgram is the token program of Mint. The zero value means
+	// Token-2022; set solana.TokenProgramID for mints of the original SPL
+	// Token program.
+	TokenProgram solana.PublicKey `bin:"-" borsh_skip:"true" json:"-"`
+
 	Payer  solana.PublicKey `bin:"-" borsh_skip:"true"`
 	Wallet solana.PublicKey `bin:"-" borsh_skip:"true"`
 	Mint   solana.PublicKey `bin:"-" borsh_skip:"true"`
@@ -79,11 +91,41 @@ func (inst *Create2022) SetMint(mint solana.PublicKey) *Create2022 {
 	return inst
 }
 
+func (inst *Create2022) SetIdempotent(idempotent bool) *Create2022 {
+	inst.Idempotent = idempotent
+	return inst
+}
+
+func (inst *Create2022) SetAllowOwnerOffCurve(allow bool) *Create2022 {
+	inst.AllowOwnerOffCurve = allow
+	return inst
+}
+
+func (inst *Create2022) SetTokenProgram(tokenProgram solana.PublicKey) *Create2022 {
+	inst.TokenProgram = tokenProgram
+	return inst
+}
+
+// tokenProgram returns TokenProgram, defaulting to Token-2022.
+func (inst Create2022) tokenProgram() solana.PublicKey {
+	if inst.TokenProgram.IsZero() {
+		return solana.Token2022ProgramID
+	}
+	return inst.TokenProgram
+}
+
+// withTokenProgram implements tokenProgramTarget.
+func (inst Create2022) withTokenProgram(tokenProgram solana.PublicKey) *Instruction {
+	inst.TokenProgram = tokenProgram
+	return inst.Build()
+}
+
 func (inst Create2022) Build() *Instruction {
 
-	associatedTokenAddress, _, _ := FindAssociatedTokenAddress2022(
+	associatedTokenAddress, _, _ := FindAssociatedTokenAddress(
 		inst.Wallet,
 		inst.Mint,
+		inst.tokenProgram(),
 	)
 
 	keys := []*solana.AccountMeta{
@@ -113,7 +155,7 @@ func (inst Create2022) Build() *Instruction {
 			IsWritable: false,
 		},
 		{
-			PublicKey:  solana.Token2022ProgramID,
+			PublicKey:  inst.tokenProgram(),
 			IsSigner:   false,
 			IsWritable: false,
 		},
@@ -128,7 +170,7 @@ func (inst Create2022) Build() *Instruction {
 
 	return &Instruction{BaseVariant: bin.BaseVariant{
 		Impl:   inst,
-		TypeID: bin.NoTypeIDDefaultID,
+		TypeID: inst.discriminator().TypeID(),
 	}}
 }
 
@@ -144,22 +186,26 @@ func (inst Create2022) ValidateAndBuild() (*Instruction, error) {
 
 func (inst *Create2022) Validate() error {
 	if inst.Payer.IsZero() {
-		return errors.New("Payer not set")
+		return errNotSet("Payer")
 	}
 	if inst.Wallet.IsZero() {
-		return errors.New("Wallet not set")
+		return errNotSet("Wallet")
 	}
 	if inst.Mint.IsZero() {
-		return errors.New("Mint not set")
+		return errNotSet("Mint")
 	}
-	_, _, err := FindAssociatedTokenAddress2022(
+	if err := ValidateOwner(inst.Wallet, inst.AllowOwnerOffCurve); err != nil {
+		return err
+	}
+	_, _, err := FindAssociatedTokenAddress(
 		inst.Wallet,
 		inst.Mint,
+		inst.tokenProgram(),
 	)
 	if err != nil {
-		return fmt.Errorf("error while FindAssociatedTokenAddress2022: %w", err)
+		return fmt.Errorf("error while FindAssociatedTokenAddress: %w", err)
 	}
-	return nil
+	return createAccountSchema.check("Create2022", inst.AccountMetaSlice)
 }
 
 func (inst *Create2022) EncodeToTree(parent treeout.Branches) {
@@ -171,7 +217,9 @@ func (inst *Create2022) EncodeToTree(parent treeout.Branches) {
 				ParentFunc(func(instructionBranch treeout.Branches) {
 
 					// Parameters of the instruction:
-					instructionBranch.Child("Params[len=0]").ParentFunc(func(paramsBranch treeout.Branches) {})
+					instructionBranch.Child("Params[len=1]").ParentFunc(func(paramsBranch treeout.Branches) {
+						paramsBranch.Child(format.Param("Idempotent", inst.Idempotent))
+					})
 
 					// Accounts of the instruction:
 					instructionBranch.Child("Accounts[len=7").ParentFunc(func(accountsBranch treeout.Branches) {
@@ -187,8 +235,17 @@ func (inst *Create2022) EncodeToTree(parent treeout.Branches) {
 		})
 }
 
+// discriminator returns the tag of Create or CreateIdempotent. The
+// instruction has no arguments.
+func (inst Create2022) discriminator() Discriminator {
+	if inst.Idempotent {
+		return NewDiscriminator(AssociatedTokenInstructionCreateIdempotent)
+	}
+	return NewDiscriminator(AssociatedTokenInstructionCreate)
+}
+
 func (inst Create2022) MarshalWithEncoder(encoder *bin.Encoder) error {
-	return encoder.WriteBytes([]byte{}, false)
+	return nil
 }
 
 func (inst *Create2022) UnmarshalWithDecoder(decoder *bin.Decoder) error {
@@ -200,6 +257,34 @@ func (inst Create2022) GetAccounts() []*solana.AccountMeta {
 	return inst.AccountMetaSlice
 }
 
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys.
+func (inst Create2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *Create2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *Create2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("Create2022", accounts, 6); err != nil {
+		return err
+	}
+	tokenProgram := accounts[5].PublicKey
+	if !tokenProgram.Equals(solana.Token2022ProgramID) && !tokenProgram.Equals(solana.TokenProgramID) {
+		return fmt.Errorf("Create2022: token program is %s, not a token program", tokenProgram)
+	}
+	inst.TokenProgram = tokenProgram
+	inst.Payer = accounts[0].PublicKey
+	inst.Wallet = accounts[2].PublicKey
+	inst.Mint = accounts[3].PublicKey
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
 // NewCreate2022Instruction creates a new instruction for creating an associated token account for Token 2022
 func NewCreate2022Instruction(
 	payer solana.PublicKey,
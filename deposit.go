@@ -0,0 +1,549 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// DepositClient is the set of RPC calls used by DepositWatcher.
+// *rpc.Client satisfies it.
+type DepositClient interface {
+	GetSlot(ctx context.Context, commitment rpc.CommitmentType) (uint64, error)
+	GetBlocks(ctx context.Context, startSlot uint64, endSlot *uint64, commitment rpc.CommitmentType) (rpc.BlocksResult, error)
+	GetBlockWithOpts(ctx context.Context, slot uint64, opts *rpc.GetBlockOpts) (*rpc.GetBlockResult, error)
+	HistoryClient
+}
+
+var _ DepositClient = (*rpc.Client)(nil)
+
+// DepositScanMode selects how DepositWatcher finds transactions.
+type DepositScanMode int
+
+const (
+	// ScanSignatures lists the signatures of every watched account with
+	// getSignaturesForAddress. It suits a moderate number of accounts.
+	ScanSignatures DepositScanMode = iota
+	// ScanBlocks reads every block with getBlock. Its cost does not depend
+	// on the number of watched accounts.
+	ScanBlocks
+)
+
+// Deposit is a Token-2022 transfer or mint crediting a watched account.
+type Deposit struct {
+	Signature solana.Signature
+	Slot      uint64
+	BlockTime *solana.UnixTimeSeconds
+	// Index and InnerIndex locate the instruction as in ParsedInstruction.
+	Index      int
+	InnerIndex int
+	// Instruction is the instruction name, such as "TransferChecked" or
+	// "MintTo".
+	Instruction string
+	Account     solana.PublicKey
+	// Owner is the owner of Account, or the zero key when the RPC node did
+	// not report it.
+	Owner solana.PublicKey
+	Mint  solana.PublicKey
+	// Source is the debited token account, or the zero key for mints.
+	Source solana.PublicKey
+	// Amount is the amount of the instruction, before any transfer fee.
+	Amount uint64
+	// Fee is the transfer fee withheld in Account. It is the fee of a
+	// TransferCheckedWithFee instruction, or the fee inferred from the
+	// balance change when Account received a single transfer in the
+	// transaction; otherwise it is zero.
+	Fee uint64
+}
+
+// ID returns a key unique to the deposit, made of the signature and the
+// instruction position, for deduplicating deposits that are delivered
+// again after a restart.
+func (d *Deposit) ID() string {
+	return fmt.Sprintf("%s:%d:%d", d.Signature, d.Index, d.InnerIndex)
+}
+
+// Received returns Amount minus Fee, the amount added to the balance of
+// Account.
+func (d *Deposit) Received() uint64 {
+	return d.Amount - d.Fee
+}
+
+// ExtractDeposits returns the Token-2022 transfers and mints of a
+// successful transaction whose destination is watched, in execution
+// order. Failed transactions have no deposits. Slot and BlockTime are left
+// for the caller to fill in.
+func ExtractDeposits(tx *solana.Transaction, meta *rpc.TransactionMeta, watched func(solana.PublicKey) bool) ([]*Deposit, error) {
+	if tx == nil {
+		return nil, errNotSet("transaction")
+	}
+	if meta == nil {
+		return nil, errNotSet("transaction meta")
+	}
+	if meta.Err != nil {
+		return nil, nil
+	}
+	parsed, err := ParseTransaction(tx, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	var deposits []*Deposit
+	credits := map[solana.PublicKey]int{}
+	for _, inst := range parsed.Instructions {
+		deposit := &Deposit{
+			Signature:   parsed.Signature,
+			Index:       inst.Index,
+			InnerIndex:  inst.InnerIndex,
+			Instruction: inst.Name,
+		}
+		switch inst := inst.Instruction.(type) {
+		case *Transfer2022:
+			deposit.Account, deposit.Source, deposit.Amount = inst.Destination, inst.Source, inst.Amount
+		case *TransferChecked2022:
+			deposit.Account, deposit.Source, deposit.Mint, deposit.Amount = inst.Destination, inst.Source, inst.Mint, inst.Amount
+		case *TransferCheckedWithFee2022:
+			deposit.Account, deposit.Source, deposit.Mint, deposit.Amount, deposit.Fee = inst.Destination, inst.Source, inst.Mint, inst.Amount, inst.Fee
+		case *MintTo2022:
+			deposit.Account, deposit.Mint, deposit.Amount = inst.Destination, inst.Mint, inst.Amount
+		case *MintToChecked2022:
+			deposit.Account, deposit.Mint, deposit.Amount = inst.Destination, inst.Mint, inst.Amount
+		default:
+			continue
+		}
+		if !watched(deposit.Account) {
+			continue
+		}
+		if deposit.Source != (solana.PublicKey{}) {
+			credits[deposit.Account]++
+		}
+		deposits = append(deposits, deposit)
+	}
+	if len(deposits) == 0 {
+		return nil, nil
+	}
+
+	changes, err := ExtractBalanceChanges(tx, meta)
+	if err != nil {
+		return nil, err
+	}
+	balances, err := tokenBalancesByAccount(tx, meta)
+	if err != nil {
+		return nil, err
+	}
+	for _, deposit := range deposits {
+		if balance, ok := balances[deposit.Account]; ok {
+			deposit.Mint = balance.Mint
+			if balance.Owner != nil {
+				deposit.Owner = *balance.Owner
+			}
+		}
+		if deposit.Instruction == "TransferCheckedWithFee" || deposit.Source == (solana.PublicKey{}) || credits[deposit.Account] != 1 {
+			continue
+		}
+		if change := changes.Token(deposit.Account); change != nil {
+			deposit.Fee = change.FeeWithheld
+		}
+	}
+	return deposits, nil
+}
+
+// tokenBalancesByAccount indexes the Token-2022 token balances of a
+// transaction by account, preferring post balances.
+func tokenBalancesByAccount(tx *solana.Transaction, meta *rpc.TransactionMeta) (map[solana.PublicKey]rpc.TokenBalance, error) {
+	keys := newTransactionKeys(&tx.Message, meta)
+	out := map[solana.PublicKey]rpc.TokenBalance{}
+	for _, balances := range [][]rpc.TokenBalance{meta.PreTokenBalances, meta.PostTokenBalances} {
+		for _, balance := range balances {
+			if balance.ProgramId != nil && !balance.ProgramId.Equals(solana.Token2022ProgramID) {
+				continue
+			}
+			if int(balance.AccountIndex) >= len(keys.keys) {
+				return nil, fmt.Errorf("account index %d out of range (%d accounts)", balance.AccountIndex, len(keys.keys))
+			}
+			out[keys.keys[balance.AccountIndex]] = balance
+		}
+	}
+	return out, nil
+}
+
+// DepositBatch is the result of one scan.
+type DepositBatch struct {
+	// Deposits are ordered by slot, then by position in the block (for
+	// ScanBlocks) or by signature listing order (for ScanSignatures).
+	Deposits []*Deposit
+	// Cursor is the last slot covered by the scan. Storing it with the
+	// deposits and passing it to the next Scan or Run resumes after the
+	// batch.
+	Cursor uint64
+}
+
+// DepositWatcher detects deposits into a set of watched token accounts,
+// such as the associated token accounts an exchange hands out to its
+// users. It scans finalized slots, decodes the Token-2022 transfers and
+// mints crediting the watched accounts, and can hold back slots that are
+// less than a number of slots behind the tip.
+//
+// Progress is a slot cursor: a scan from cursor covers the slots after
+// it. A batch is reported with the cursor it reaches, so storing both in
+// one database transaction makes processing idempotent; Deposit.ID
+// deduplicates a batch that is delivered again after a crash.
+//
+// DepositWatcher is safe for concurrent use.
+type DepositWatcher struct {
+	client        DepositClient
+	mode          DepositScanMode
+	commitment    rpc.CommitmentType
+	confirmations uint64
+	maxSlots      uint64
+	pollInterval  time.Duration
+	retry         RetryPolicy
+	logger        Logger
+	metrics       Metrics
+
+	mu      sync.RWMutex
+	watched map[solana.PublicKey]bool
+}
+
+// NewDepositWatcher creates a watcher using ScanSignatures at finalized
+// commitment, covering up to 100 slots per scan and polling every two
+// seconds. A watcher that reads confirmed slots to credit deposits sooner
+// should also set a confirmation depth, since a confirmed slot can still
+// be rolled back.
+func NewDepositWatcher(client DepositClient, accounts ...solana.PublicKey) *DepositWatcher {
+	w := &DepositWatcher{
+		client:       client,
+		mode:         ScanSignatures,
+		commitment:   rpc.CommitmentFinalized,
+		maxSlots:     100,
+		pollInterval: 2 * time.Second,
+		retry:        DefaultRetryPolicy(),
+		watched:      map[solana.PublicKey]bool{},
+	}
+	w.AddAccounts(accounts...)
+	return w
+}
+
+// SetLogger logs every batch that advances the cursor and every retried
+// scan.
+func (w *DepositWatcher) SetLogger(logger Logger) *DepositWatcher {
+	w.logger = logger
+	return w
+}
+
+// SetMetrics counts retried scans and transactions that could not be
+// decoded.
+func (w *DepositWatcher) SetMetrics(metrics Metrics) *DepositWatcher {
+	w.metrics = metrics
+	return w
+}
+
+func (w *DepositWatcher) SetMode(mode DepositScanMode) *DepositWatcher {
+	w.mode = mode
+	return w
+}
+
+// SetCommitment sets the commitment of the tip and of the reads. getBlock
+// does not support processed.
+func (w *DepositWatcher) SetCommitment(commitment rpc.CommitmentType) *DepositWatcher {
+	w.commitment = commitment
+	return w
+}
+
+// SetConfirmations sets how many slots behind the tip a slot must be
+// before its deposits are reported.
+func (w *DepositWatcher) SetConfirmations(slots uint64) *DepositWatcher {
+	w.confirmations = slots
+	return w
+}
+
+// SetMaxSlots sets the largest number of slots one scan covers. With
+// ScanSignatures every scan still lists signatures back to its cursor, so
+// a watcher that is far behind catches up faster with a larger bound.
+func (w *DepositWatcher) SetMaxSlots(slots uint64) *DepositWatcher {
+	if slots > 0 {
+		w.maxSlots = slots
+	}
+	return w
+}
+
+func (w *DepositWatcher) SetPollInterval(interval time.Duration) *DepositWatcher {
+	w.pollInterval = interval
+	return w
+}
+
+// SetRetryPolicy sets how Run retries failed scans.
+func (w *DepositWatcher) SetRetryPolicy(policy RetryPolicy) *DepositWatcher {
+	w.retry = policy
+	return w
+}
+
+// AddAccounts adds token accounts to watch. With ScanSignatures the next
+// scan also covers their history since the cursor.
+func (w *DepositWatcher) AddAccounts(accounts ...solana.PublicKey) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, account := range accounts {
+		w.watched[account] = true
+	}
+}
+
+func (w *DepositWatcher) RemoveAccounts(accounts ...solana.PublicKey) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, account := range accounts {
+		delete(w.watched, account)
+	}
+}
+
+func (w *DepositWatcher) isWatched(account solana.PublicKey) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.watched[account]
+}
+
+func (w *DepositWatcher) accounts() []solana.PublicKey {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	accounts := make([]solana.PublicKey, 0, len(w.watched))
+	for account := range w.watched {
+		accounts = append(accounts, account)
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].String() < accounts[j].String() })
+	return accounts
+}
+
+// Scan reports the deposits in the slots after cursor that are deep
+// enough, up to the maximum number of slots per scan. The batch Cursor
+// equals cursor when there is nothing new to scan. A zero cursor starts a
+// new watcher: its first scan covers the slots just before the tip rather
+// than the whole history of the chain or of the watched accounts.
+func (w *DepositWatcher) Scan(ctx context.Context, cursor uint64) (*DepositBatch, error) {
+	tip, err := w.client.GetSlot(ctx, w.commitment)
+	if err != nil {
+		return nil, fmt.Errorf("error while getting slot: %w", err)
+	}
+	if tip < w.confirmations || tip-w.confirmations <= cursor {
+		return &DepositBatch{Cursor: cursor}, nil
+	}
+	safe := tip - w.confirmations
+	if cursor == 0 && safe > w.maxSlots {
+		cursor = safe - w.maxSlots
+	}
+	end := min(safe, cursor+w.maxSlots)
+
+	if w.mode == ScanBlocks {
+		return w.scanBlocks(ctx, cursor, end)
+	}
+	return w.scanSignatures(ctx, cursor, end)
+}
+
+// Run scans from cursor until ctx is done, calling handle with every batch
+// that advances the cursor, including batches without deposits. Failed
+// scans are retried according to the retry policy. Run returns the error
+// of handle, or of a scan that failed after its retries, without
+// advancing past the failed batch.
+func (w *DepositWatcher) Run(ctx context.Context, cursor uint64, handle func(context.Context, *DepositBatch) error) error {
+	failures := 0
+	for {
+		batch, err := w.Scan(ctx, cursor)
+		var wait time.Duration
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			failures++
+			if failures >= w.retry.MaxAttempts || !IsRetryable(err) {
+				return err
+			}
+			wait = w.retry.Backoff(failures, err)
+			logEvent(ctx, w.logger, slog.LevelWarn, "deposit scan retried", "cursor", cursor, "attempt", failures, "backoff", wait, "error", err)
+			addMetric(w.metrics, MetricRetries, 1, "deposit_scan")
+		case batch.Cursor > cursor:
+			failures = 0
+			logEvent(ctx, w.logger, slog.LevelInfo, "deposits scanned", "from", cursor, "to", batch.Cursor, "deposits", len(batch.Deposits))
+			if err := handle(ctx, batch); err != nil {
+				return err
+			}
+			cursor = batch.Cursor
+			continue
+		default:
+			failures = 0
+			wait = w.pollInterval
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (w *DepositWatcher) scanBlocks(ctx context.Context, cursor, end uint64) (*DepositBatch, error) {
+	slots, err := w.client.GetBlocks(ctx, cursor+1, &end, w.commitment)
+	if err != nil {
+		return nil, fmt.Errorf("error while listing blocks: %w", err)
+	}
+	rewards := false
+	opts := &rpc.GetBlockOpts{
+		Encoding:                       solana.EncodingBase64,
+		TransactionDetails:             rpc.TransactionDetailsFull,
+		Rewards:                        &rewards,
+		Commitment:                     w.commitment,
+		MaxSupportedTransactionVersion: &rpc.MaxSupportedTransactionVersion0,
+	}
+	batch := &DepositBatch{Cursor: end}
+	for _, slot := range slots {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		block, err := w.client.GetBlockWithOpts(ctx, slot, opts)
+		if err != nil {
+			return nil, fmt.Errorf("error while getting block %d: %w", slot, err)
+		}
+		for _, txWithMeta := range block.Transactions {
+			if txWithMeta.Meta == nil || txWithMeta.Meta.Err != nil {
+				continue
+			}
+			tx, err := txWithMeta.GetTransaction()
+			if err != nil {
+				addMetric(w.metrics, MetricDecodeErrors, 1, "deposit")
+				return nil, fmt.Errorf("error while decoding transaction in block %d: %w", slot, err)
+			}
+			if !w.mentionsWatched(tx, txWithMeta.Meta) {
+				continue
+			}
+			if err := w.collect(batch, tx, txWithMeta.Meta, slot, block.BlockTime); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return batch, nil
+}
+
+// mentionsWatched reports whether any account of the transaction is
+// watched, to skip parsing the rest of the block.
+func (w *DepositWatcher) mentionsWatched(tx *solana.Transaction, meta *rpc.TransactionMeta) bool {
+	for _, key := range newTransactionKeys(&tx.Message, meta).keys {
+		if w.isWatched(key) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *DepositWatcher) scanSignatures(ctx context.Context, cursor, end uint64) (*DepositBatch, error) {
+	type found struct {
+		sig  solana.Signature
+		slot uint64
+	}
+	var pending []found
+	seen := map[solana.Signature]bool{}
+	for _, account := range w.accounts() {
+		sigs, err := w.signaturesSince(ctx, account, cursor)
+		if err != nil {
+			return nil, err
+		}
+		for _, sig := range sigs {
+			if sig.Slot > end || sig.Err != nil || seen[sig.Signature] {
+				continue
+			}
+			seen[sig.Signature] = true
+			pending = append(pending, found{sig.Signature, sig.Slot})
+		}
+	}
+	sort.SliceStable(pending, func(i, j int) bool { return pending[i].slot < pending[j].slot })
+
+	opts := &rpc.GetTransactionOpts{
+		Encoding:                       solana.EncodingBase64,
+		Commitment:                     w.commitment,
+		MaxSupportedTransactionVersion: &rpc.MaxSupportedTransactionVersion0,
+	}
+	batch := &DepositBatch{Cursor: end}
+	for _, p := range pending {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		out, err := w.client.GetTransaction(ctx, p.sig, opts)
+		if err != nil {
+			return nil, fmt.Errorf("error while getting transaction %s: %w", p.sig, err)
+		}
+		if out.Transaction == nil || out.Meta == nil {
+			return nil, fmt.Errorf("transaction %s has no content", p.sig)
+		}
+		tx, err := out.Transaction.GetTransaction()
+		if err != nil {
+			addMetric(w.metrics, MetricDecodeErrors, 1, "deposit")
+			return nil, fmt.Errorf("error while decoding transaction %s: %w", p.sig, err)
+		}
+		if err := w.collect(batch, tx, out.Meta, out.Slot, out.BlockTime); err != nil {
+			return nil, err
+		}
+	}
+	return batch, nil
+}
+
+// signaturesSince pages through the signatures of account, newest first,
+// until it reaches one at or before cursor.
+func (w *DepositWatcher) signaturesSince(ctx context.Context, account solana.PublicKey, cursor uint64) ([]*rpc.TransactionSignature, error) {
+	limit := maxSignaturesPerPage
+	opts := &rpc.GetSignaturesForAddressOpts{Limit: &limit, Commitment: w.commitment}
+	var out []*rpc.TransactionSignature
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		page, err := w.client.GetSignaturesForAddressWithOpts(ctx, account, opts)
+		if err != nil {
+			return nil, fmt.Errorf("error while getting signatures of %s: %w", account, err)
+		}
+		for _, sig := range page {
+			if sig.Slot <= cursor {
+				return out, nil
+			}
+			out = append(out, sig)
+		}
+		if len(page) < limit {
+			return out, nil
+		}
+		opts.Before = page[len(page)-1].Signature
+	}
+}
+
+func (w *DepositWatcher) collect(batch *DepositBatch, tx *solana.Transaction, meta *rpc.TransactionMeta, slot uint64, blockTime *solana.UnixTimeSeconds) error {
+	deposits, err := ExtractDeposits(tx, meta, w.isWatched)
+	if err != nil {
+		addMetric(w.metrics, MetricDecodeErrors, 1, "deposit")
+		return fmt.Errorf("error while extracting deposits of %s: %w", tx.Signatures[0], err)
+	}
+	for _, deposit := range deposits {
+		deposit.Slot = slot
+		deposit.BlockTime = blockTime
+	}
+	batch.Deposits = append(batch.Deposits, deposits...)
+	return nil
+}
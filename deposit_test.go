@@ -0,0 +1,260 @@
+package token2022
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+type depositRPC struct {
+	slot       uint64
+	blocks     map[uint64]*rpc.GetBlockResult
+	signatures map[solana.PublicKey][]*rpc.TransactionSignature
+	txs        map[solana.Signature]*rpc.GetTransactionResult
+	blockCalls []uint64
+}
+
+func (m *depositRPC) GetSlot(ctx context.Context, commitment rpc.CommitmentType) (uint64, error) {
+	return m.slot, nil
+}
+
+func (m *depositRPC) GetBlocks(ctx context.Context, startSlot uint64, endSlot *uint64, commitment rpc.CommitmentType) (rpc.BlocksResult, error) {
+	var out rpc.BlocksResult
+	for slot := startSlot; slot <= *endSlot; slot++ {
+		if _, ok := m.blocks[slot]; ok {
+			out = append(out, slot)
+		}
+	}
+	return out, nil
+}
+
+func (m *depositRPC) GetBlockWithOpts(ctx context.Context, slot uint64, opts *rpc.GetBlockOpts) (*rpc.GetBlockResult, error) {
+	m.blockCalls = append(m.blockCalls, slot)
+	return m.blocks[slot], nil
+}
+
+func (m *depositRPC) GetSignaturesForAddressWithOpts(ctx context.Context, account solana.PublicKey, opts *rpc.GetSignaturesForAddressOpts) ([]*rpc.TransactionSignature, error) {
+	return m.signatures[account], nil
+}
+
+func (m *depositRPC) GetTransaction(ctx context.Context, sig solana.Signature, opts *rpc.GetTransactionOpts) (*rpc.GetTransactionResult, error) {
+	out, ok := m.txs[sig]
+	if !ok {
+		return nil, rpc.ErrNotFound
+	}
+	return out, nil
+}
+
+var (
+	depositWallet      = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	depositMint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	depositSource      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+	depositDestination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+)
+
+// depositTransaction transfers 1,000 tokens from depositSource to
+// depositDestination, 10 of which are withheld as a transfer fee.
+func depositTransaction(t *testing.T, sig solana.Signature) (*solana.Transaction, *rpc.TransactionMeta) {
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{NewTransferChecked2022Instruction(1_000, 6, depositSource, depositMint, depositDestination, depositWallet).Build()},
+		solana.Hash{1},
+		solana.TransactionPayer(depositWallet),
+	)
+	if err != nil {
+		t.Fatalf("NewTransaction: %v", err)
+	}
+	tx.Signatures = []solana.Signature{sig}
+
+	balance := func(account solana.PublicKey, amount string) rpc.TokenBalance {
+		for i, key := range tx.Message.AccountKeys {
+			if key.Equals(account) {
+				return rpc.TokenBalance{
+					AccountIndex:  uint16(i),
+					Owner:         &depositWallet,
+					ProgramId:     &solana.Token2022ProgramID,
+					Mint:          depositMint,
+					UiTokenAmount: &rpc.UiTokenAmount{Amount: amount, Decimals: 6},
+				}
+			}
+		}
+		t.Fatalf("key %s not in transaction", account)
+		return rpc.TokenBalance{}
+	}
+	meta := &rpc.TransactionMeta{
+		PreBalances:       make([]uint64, len(tx.Message.AccountKeys)),
+		PostBalances:      make([]uint64, len(tx.Message.AccountKeys)),
+		PreTokenBalances:  []rpc.TokenBalance{balance(depositSource, "5000"), balance(depositDestination, "0")},
+		PostTokenBalances: []rpc.TokenBalance{balance(depositSource, "4000"), balance(depositDestination, "990")},
+	}
+	return tx, meta
+}
+
+func TestExtractDeposits(t *testing.T) {
+	tx, meta := depositTransaction(t, solana.Signature{7})
+
+	deposits, err := ExtractDeposits(tx, meta, func(account solana.PublicKey) bool { return account.Equals(depositDestination) })
+	if err != nil {
+		t.Fatalf("ExtractDeposits: %v", err)
+	}
+	if len(deposits) != 1 {
+		t.Fatalf("Expected 1 deposit, got %d", len(deposits))
+	}
+	deposit := deposits[0]
+	if deposit.Instruction != "TransferChecked" || !deposit.Source.Equals(depositSource) || !deposit.Mint.Equals(depositMint) || !deposit.Owner.Equals(depositWallet) {
+		t.Errorf("Unexpected deposit %+v", deposit)
+	}
+	if deposit.Amount != 1_000 || deposit.Fee != 10 || deposit.Received() != 990 {
+		t.Errorf("Expected 1000 with a fee of 10, got %d with a fee of %d", deposit.Amount, deposit.Fee)
+	}
+	if id := deposit.ID(); id != (solana.Signature{7}).String()+":0:-1" {
+		t.Errorf("Unexpected ID %s", id)
+	}
+
+	deposits, err = ExtractDeposits(tx, meta, func(solana.PublicKey) bool { return false })
+	if err != nil || len(deposits) != 0 {
+		t.Errorf("Expected no deposits to unwatched accounts, got %v, %v", deposits, err)
+	}
+
+	meta.Err = map[string]interface{}{"InstructionError": []interface{}{0, "InvalidAccountData"}}
+	deposits, err = ExtractDeposits(tx, meta, func(solana.PublicKey) bool { return true })
+	if err != nil || len(deposits) != 0 {
+		t.Errorf("Expected no deposits from a failed transaction, got %v, %v", deposits, err)
+	}
+}
+
+func TestDepositWatcherScanBlocks(t *testing.T) {
+	tx, meta := depositTransaction(t, solana.Signature{7})
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	blockTime := solana.UnixTimeSeconds(1_700_000_000)
+	client := &depositRPC{
+		slot: 110,
+		blocks: map[uint64]*rpc.GetBlockResult{
+			102: {},
+			104: {BlockTime: &blockTime, Transactions: []rpc.TransactionWithMeta{{Transaction: rpc.DataBytesOrJSONFromBytes(raw), Meta: meta}}},
+			107: {},
+		},
+	}
+	watcher := NewDepositWatcher(client, depositDestination).SetMode(ScanBlocks).SetConfirmations(5)
+
+	batch, err := watcher.Scan(context.Background(), 100)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if batch.Cursor != 105 {
+		t.Errorf("Expected cursor 105, got %d", batch.Cursor)
+	}
+	if len(batch.Deposits) != 1 || batch.Deposits[0].Slot != 104 || batch.Deposits[0].BlockTime == nil || *batch.Deposits[0].BlockTime != blockTime {
+		t.Fatalf("Expected one deposit in slot 104, got %+v", batch.Deposits)
+	}
+	if len(client.blockCalls) != 2 {
+		t.Errorf("Expected blocks 102 and 104 to be read, got %v", client.blockCalls)
+	}
+
+	batch, err = watcher.Scan(context.Background(), 105)
+	if err != nil || batch.Cursor != 105 || len(batch.Deposits) != 0 {
+		t.Errorf("Expected no progress at the confirmation depth, got %+v, %v", batch, err)
+	}
+}
+
+func TestDepositWatcherScanSignatures(t *testing.T) {
+	tx, meta := depositTransaction(t, solana.Signature{7})
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	var envelope rpc.TransactionResultEnvelope
+	if err := envelope.UnmarshalJSON([]byte(`["` + base64.StdEncoding.EncodeToString(raw) + `","base64"]`)); err != nil {
+		t.Fatalf("UnmarshalJSON: %v", err)
+	}
+	client := &depositRPC{
+		slot: 110,
+		signatures: map[solana.PublicKey][]*rpc.TransactionSignature{
+			depositDestination: {
+				{Signature: solana.Signature{9}, Slot: 108},
+				{Signature: solana.Signature{8}, Slot: 104, Err: "InsufficientFundsForFee"},
+				{Signature: solana.Signature{7}, Slot: 103},
+				{Signature: solana.Signature{6}, Slot: 99},
+			},
+		},
+		txs: map[solana.Signature]*rpc.GetTransactionResult{
+			{7}: {Slot: 103, Transaction: &envelope, Meta: meta},
+		},
+	}
+	watcher := NewDepositWatcher(client, depositDestination).SetConfirmations(5)
+
+	batch, err := watcher.Scan(context.Background(), 100)
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if batch.Cursor != 105 {
+		t.Errorf("Expected cursor 105, got %d", batch.Cursor)
+	}
+	if len(batch.Deposits) != 1 || batch.Deposits[0].Signature != (solana.Signature{7}) || batch.Deposits[0].Slot != 103 {
+		t.Errorf("Expected the deposit of slot 103, got %+v", batch.Deposits)
+	}
+}
+
+func TestDepositWatcherRun(t *testing.T) {
+	client := &depositRPC{slot: 110, blocks: map[uint64]*rpc.GetBlockResult{}}
+	watcher := NewDepositWatcher(client).SetMode(ScanBlocks).SetMaxSlots(4)
+
+	stop := errors.New("stop")
+	var cursors []uint64
+	err := watcher.Run(context.Background(), 100, func(ctx context.Context, batch *DepositBatch) error {
+		cursors = append(cursors, batch.Cursor)
+		if len(cursors) == 3 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("Expected the handler error, got %v", err)
+	}
+	if len(cursors) != 3 || cursors[0] != 104 || cursors[1] != 108 || cursors[2] != 110 {
+		t.Errorf("Unexpected cursors %v", cursors)
+	}
+}
+
+func TestDepositWatcherBounds(t *testing.T) {
+	tx, meta := depositTransaction(t, solana.Signature{9})
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	var envelope rpc.TransactionResultEnvelope
+	if err := envelope.UnmarshalJSON([]byte(`["` + base64.StdEncoding.EncodeToString(raw) + `","base64"]`)); err != nil {
+		t.Fatalf("UnmarshalJSON: %v", err)
+	}
+	client := &depositRPC{
+		slot: 110,
+		signatures: map[solana.PublicKey][]*rpc.TransactionSignature{
+			depositDestination: {
+				{Signature: solana.Signature{9}, Slot: 108},
+				{Signature: solana.Signature{8}, Slot: 104, Err: "InsufficientFundsForFee"},
+			},
+		},
+		txs: map[solana.Signature]*rpc.GetTransactionResult{
+			{9}: {Slot: 108, Transaction: &envelope, Meta: meta},
+		},
+	}
+	watcher := NewDepositWatcher(client, depositDestination).SetMaxSlots(4)
+	if watcher.commitment != rpc.CommitmentFinalized {
+		t.Errorf("Expected finalized commitment by default, got %s", watcher.commitment)
+	}
+
+	batch, err := watcher.Scan(context.Background(), 100)
+	if err != nil || batch.Cursor != 104 || len(batch.Deposits) != 0 {
+		t.Errorf("Expected a scan up to slot 104 skipping slot 108, got %+v, %v", batch, err)
+	}
+	batch, err = watcher.Scan(context.Background(), 0)
+	if err != nil || batch.Cursor != 110 || len(batch.Deposits) != 1 || batch.Deposits[0].Slot != 108 {
+		t.Errorf("Expected a new watcher to scan slots 107 to 110, got %+v, %v", batch, err)
+	}
+}
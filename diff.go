@@ -0,0 +1,184 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// DiffInstructions compares two instruction lists by program ID, ordered
+// account metas (pubkey, signer, writable) and data. It returns an empty
+// string when they are equal and otherwise one line per difference. When
+// both sides decode as the same Token-2022 instruction, differing data is
+// also reported field by field.
+func DiffInstructions(want, got []solana.Instruction) string {
+	var lines []string
+	if len(want) != len(got) {
+		lines = append(lines, fmt.Sprintf("instruction count: want %d, got %d", len(want), len(got)))
+	}
+	for i := 0; i < len(want) || i < len(got); i++ {
+		switch {
+		case i >= len(got):
+			lines = append(lines, fmt.Sprintf("instruction %d (%s): missing", i, diffName(want[i])))
+		case i >= len(want):
+			lines = append(lines, fmt.Sprintf("instruction %d (%s): unexpected", i, diffName(got[i])))
+		default:
+			for _, line := range diffInstruction(want[i], got[i]) {
+				lines = append(lines, fmt.Sprintf("instruction %d (%s): %s", i, diffName(want[i]), line))
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// DiffInstruction compares two instructions like DiffInstructions.
+func DiffInstruction(want, got solana.Instruction) string {
+	return strings.Join(diffInstruction(want, got), "\n")
+}
+
+func diffInstruction(want, got solana.Instruction) []string {
+	var lines []string
+	if !want.ProgramID().Equals(got.ProgramID()) {
+		lines = append(lines, fmt.Sprintf("program: want %s, got %s", want.ProgramID(), got.ProgramID()))
+	}
+
+	wantAccounts, gotAccounts := want.Accounts(), got.Accounts()
+	for i := 0; i < len(wantAccounts) || i < len(gotAccounts); i++ {
+		switch {
+		case i >= len(gotAccounts):
+			lines = append(lines, fmt.Sprintf("account %d: want %s, got none", i, formatAccountMeta(wantAccounts[i])))
+		case i >= len(wantAccounts):
+			lines = append(lines, fmt.Sprintf("account %d: want none, got %s", i, formatAccountMeta(gotAccounts[i])))
+		case !equalAccountMeta(wantAccounts[i], gotAccounts[i]):
+			lines = append(lines, fmt.Sprintf("account %d: want %s, got %s", i, formatAccountMeta(wantAccounts[i]), formatAccountMeta(gotAccounts[i])))
+		}
+	}
+
+	wantData, wantErr := want.Data()
+	gotData, gotErr := got.Data()
+	switch {
+	case wantErr != nil || gotErr != nil:
+		lines = append(lines, fmt.Sprintf("data: want error %v, got error %v", wantErr, gotErr))
+	case !bytes.Equal(wantData, gotData):
+		lines = append(lines, fmt.Sprintf("data: want %x, got %x (first difference at byte %d)",
+			wantData, gotData, firstDifference(wantData, gotData)))
+		if want.ProgramID().Equals(solana.Token2022ProgramID) && got.ProgramID().Equals(solana.Token2022ProgramID) {
+			lines = append(lines, diffFields(wantData, gotData)...)
+		}
+	}
+	return lines
+}
+
+// diffFields reports the differing fields of two Token-2022 instructions
+// of the same type. Accounts are compared separately, so the decoded
+// fields are compared without them.
+func diffFields(wantData, gotData []byte) []string {
+	if InstructionName(wantData) != InstructionName(gotData) {
+		return []string{fmt.Sprintf("type: want %s, got %s", InstructionName(wantData), InstructionName(gotData))}
+	}
+	wantFields, ok := instructionFields(wantData)
+	if !ok {
+		return nil
+	}
+	gotFields, ok := instructionFields(gotData)
+	if !ok {
+		return nil
+	}
+
+	names := make([]string, 0, len(wantFields))
+	for name := range wantFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var lines []string
+	for _, name := range names {
+		if !bytes.Equal(wantFields[name], gotFields[name]) {
+			lines = append(lines, fmt.Sprintf("field %s: want %s, got %s", name, wantFields[name], gotFields[name]))
+		}
+	}
+	return lines
+}
+
+// instructionFields decodes Token-2022 instruction data into its JSON
+// fields.
+func instructionFields(data []byte) (map[string]json.RawMessage, bool) {
+	newInstruction, err := lookupInstruction(data)
+	if err != nil {
+		return nil, false
+	}
+	inst := newInstruction()
+	if err := decodeInstructionData(inst, data); err != nil {
+		return nil, false
+	}
+	encoded, err := json.Marshal(inst)
+	if err != nil {
+		return nil, false
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, false
+	}
+	return fields, true
+}
+
+func diffName(inst solana.Instruction) string {
+	data, err := inst.Data()
+	if err != nil {
+		return "?"
+	}
+	switch {
+	case inst.ProgramID().Equals(solana.Token2022ProgramID):
+		return InstructionName(data)
+	case inst.ProgramID().Equals(solana.SPLAssociatedTokenAccountProgramID):
+		return AssociatedTokenInstructionName(data)
+	}
+	return inst.ProgramID().String()
+}
+
+func equalAccountMeta(a, b *solana.AccountMeta) bool {
+	return a.PublicKey.Equals(b.PublicKey) && a.IsSigner == b.IsSigner && a.IsWritable == b.IsWritable
+}
+
+func formatAccountMeta(meta *solana.AccountMeta) string {
+	var flags []string
+	if meta.IsSigner {
+		flags = append(flags, "signer")
+	}
+	if meta.IsWritable {
+		flags = append(flags, "writable")
+	}
+	if len(flags) == 0 {
+		flags = append(flags, "readonly")
+	}
+	return fmt.Sprintf("%s (%s)", meta.PublicKey, strings.Join(flags, ", "))
+}
+
+func firstDifference(a, b []byte) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	if len(a) < len(b) {
+		return len(a)
+	}
+	return len(b)
+}
@@ -0,0 +1,71 @@
+package token2022
+
+import (
+	"strings"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+func TestDiffInstructions(t *testing.T) {
+
+	var (
+		source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		owner       = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	)
+
+	want := []solana.Instruction{
+		NewTransferChecked2022Instruction(100, 6, source, mint, destination, owner).Build(),
+		NewCreate2022Instruction(owner, owner, mint).Build(),
+	}
+	same := []solana.Instruction{
+		NewTransferChecked2022Instruction(100, 6, source, mint, destination, owner).Build(),
+		NewCreate2022Instruction(owner, owner, mint).Build(),
+	}
+	if diff := DiffInstructions(want, same); diff != "" {
+		t.Errorf("Expected no diff, got:\n%s", diff)
+	}
+
+	got := []solana.Instruction{
+		NewTransferChecked2022Instruction(101, 6, source, mint, owner, owner).Build(),
+	}
+	diff := DiffInstructions(want, got)
+	for _, line := range []string{
+		"instruction count: want 2, got 1",
+		"instruction 0 (TransferChecked): account 2: want 9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin (writable), got nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun (writable)",
+		"instruction 0 (TransferChecked): data: want 0c640000000000000006, got 0c650000000000000006 (first difference at byte 1)",
+		`instruction 0 (TransferChecked): field amount: want "100", got "101"`,
+		"instruction 1 (Create): missing",
+	} {
+		if !strings.Contains(diff, line) {
+			t.Errorf("Expected diff line %q in:\n%s", line, diff)
+		}
+	}
+	if strings.Contains(diff, "field destination") {
+		t.Errorf("Expected accounts to be diffed only as metas:\n%s", diff)
+	}
+}
+
+func TestDiffInstructionProgramAndFlags(t *testing.T) {
+
+	var (
+		account = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+	)
+
+	want := solana.NewInstruction(solana.Token2022ProgramID, solana.AccountMetaSlice{solana.Meta(account).WRITE().SIGNER()}, []byte{9})
+	got := solana.NewInstruction(solana.TokenProgramID, solana.AccountMetaSlice{solana.Meta(account)}, []byte{9})
+	diff := DiffInstruction(want, got)
+	for _, line := range []string{
+		"program: want TokenzQdBNbLqP5VEhdkAS6EPFLC1PHnBqCXEpPxuEb, got TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA",
+		"account 0: want GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi (signer, writable), got GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi (readonly)",
+	} {
+		if !strings.Contains(diff, line) {
+			t.Errorf("Expected diff line %q in:\n%s", line, diff)
+		}
+	}
+	if strings.Contains(diff, "data") {
+		t.Errorf("Expected equal data, got:\n%s", diff)
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"fmt"
+	"reflect"
+
+	bin "github.com/gagliardetto/binary"
+)
+
+// Discriminator is the prefix of the instruction data that selects an
+// instruction: the one-byte instruction tag and, for the instructions of a
+// Token-2022 extension, the one-byte sub-tag that follows it. Instruction
+// writes it ahead of the arguments of every builder and checks it when
+// decoding, so that builders encode their arguments only.
+type Discriminator struct {
+	Tag    uint8
+	SubTag uint8
+	// Extension reports whether SubTag is part of the discriminator.
+	Extension bool
+}
+
+// NewDiscriminator returns the discriminator of an instruction tag, such
+// as InstructionTransferChecked.
+func NewDiscriminator(tag uint8) Discriminator {
+	return Discriminator{Tag: tag}
+}
+
+// NewExtensionDiscriminator returns the discriminator of an extension
+// instruction, such as InstructionTransferFeeExtension with
+// TransferFeeInstructionSetTransferFee.
+func NewExtensionDiscriminator(tag, subTag uint8) Discriminator {
+	return Discriminator{Tag: tag, SubTag: subTag, Extension: true}
+}
+
+// ParseDiscriminator reads the discriminator of Token-2022 instruction
+// data. The sub-tag is part of it when the tag is that of an extension.
+func ParseDiscriminator(data []byte) (Discriminator, error) {
+	if len(data) == 0 {
+		return Discriminator{}, fmt.Errorf("%w: empty instruction data", ErrUnknownInstruction)
+	}
+	if !isExtensionTag(data[0]) {
+		return NewDiscriminator(data[0]), nil
+	}
+	if len(data) < 2 {
+		return Discriminator{}, fmt.Errorf("%w: %s without sub-tag", ErrUnknownInstruction, InstructionName(data))
+	}
+	return NewExtensionDiscriminator(data[0], data[1]), nil
+}
+
+// Len returns the number of bytes of the discriminator.
+func (d Discriminator) Len() int {
+	if d.Extension {
+		return 2
+	}
+	return 1
+}
+
+// Bytes returns the encoded discriminator.
+func (d Discriminator) Bytes() []byte {
+	return d.Append(make([]byte, 0, 2))
+}
+
+// Append appends the encoded discriminator to data.
+func (d Discriminator) Append(data []byte) []byte {
+	data = append(data, d.Tag)
+	if d.Extension {
+		data = append(data, d.SubTag)
+	}
+	return data
+}
+
+// String returns the bytes of the discriminator, such as "[26 5]".
+func (d Discriminator) String() string {
+	return fmt.Sprint(d.Bytes())
+}
+
+// TypeID returns the discriminator as the type ID of a bin.BaseVariant.
+func (d Discriminator) TypeID() bin.TypeID {
+	return bin.TypeIDFromBytes(d.Bytes())
+}
+
+func (d Discriminator) encode(encoder *bin.Encoder) error {
+	return encoder.WriteBytes(d.Bytes(), false)
+}
+
+// check reads a discriminator and checks that it is d.
+func (d Discriminator) check(decoder *bin.Decoder) error {
+	for _, want := range d.Bytes() {
+		got, err := decoder.ReadUint8()
+		if err != nil {
+			return err
+		}
+		if got != want {
+			return fmt.Errorf("%w: unexpected instruction tag %d, expected %d", ErrMalformedInstruction, got, want)
+		}
+	}
+	return nil
+}
+
+// discriminated is implemented by builders whose discriminator depends on
+// their fields, such as Create2022.
+type discriminated interface {
+	discriminator() Discriminator
+}
+
+// builderDiscriminators maps the builder types of the registries to their
+// discriminators.
+var builderDiscriminators = func() map[reflect.Type]Discriminator {
+	discriminators := make(map[reflect.Type]Discriminator)
+	for tag, newInstruction := range instructionRegistry {
+		discriminators[reflect.TypeOf(newInstruction()).Elem()] = NewDiscriminator(tag)
+	}
+	for tag, subRegistry := range extensionInstructionRegistry {
+		for subTag, newInstruction := range subRegistry {
+			discriminators[reflect.TypeOf(newInstruction()).Elem()] = NewExtensionDiscriminator(tag, subTag)
+		}
+	}
+	return discriminators
+}()
+
+// discriminatorOf returns the discriminator of a builder, given by value
+// or by pointer.
+func discriminatorOf(impl interface{}) (Discriminator, error) {
+	if d, ok := impl.(discriminated); ok {
+		return d.discriminator(), nil
+	}
+	t := reflect.TypeOf(impl)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if d, ok := builderDiscriminators[t]; ok {
+		return d, nil
+	}
+	return Discriminator{}, fmt.Errorf("no discriminator for %T", impl)
+}
+
+// isExtensionTag reports whether instructions with tag carry a sub-tag.
+func isExtensionTag(tag uint8) bool {
+	switch tag {
+	case InstructionTransferFeeExtension,
+		InstructionConfidentialTransferExtension,
+		InstructionDefaultAccountStateExtension,
+		InstructionMemoTransferExtension,
+		InstructionInterestBearingMintExtension,
+		InstructionCpiGuardExtension,
+		InstructionTransferHookExtension,
+		InstructionConfidentialTransferFeeExtension,
+		InstructionMetadataPointerExtension,
+		InstructionGroupPointerExtension,
+		InstructionGroupMemberPointerExtension,
+		InstructionConfidentialMintBurnExtension,
+		InstructionScaledUiAmountExtension,
+		InstructionPausableExtension:
+		return true
+	}
+	return false
+}
+
+// decodeInstructionData decodes data, discriminator included, into the
+// builder impl.
+func decodeInstructionData(impl InstructionImpl, data []byte) error {
+	inst := &Instruction{BaseVariant: bin.BaseVariant{Impl: impl}}
+	return inst.UnmarshalWithDecoder(bin.NewBinDecoder(data))
+}
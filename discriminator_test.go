@@ -0,0 +1,329 @@
+package token2022
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	token "github.com/gagliardetto/solana-go/programs/token"
+)
+
+func TestParseDiscriminator(t *testing.T) {
+	tests := []struct {
+		data []byte
+		want Discriminator
+	}{
+		{[]byte{InstructionTransferChecked, 1, 2}, NewDiscriminator(InstructionTransferChecked)},
+		{[]byte{InstructionCloseAccount}, NewDiscriminator(InstructionCloseAccount)},
+		{
+			[]byte{InstructionTransferFeeExtension, TransferFeeInstructionSetTransferFee, 1},
+			NewExtensionDiscriminator(InstructionTransferFeeExtension, TransferFeeInstructionSetTransferFee),
+		},
+		{
+			[]byte{InstructionPausableExtension, PausableInstructionPause},
+			NewExtensionDiscriminator(InstructionPausableExtension, PausableInstructionPause),
+		},
+	}
+	for _, test := range tests {
+		got, err := ParseDiscriminator(test.data)
+		if err != nil {
+			t.Fatalf("ParseDiscriminator(%v): %v", test.data, err)
+		}
+		if got != test.want {
+			t.Errorf("ParseDiscriminator(%v): expected %v, got %v", test.data, test.want, got)
+		}
+		if !bytes.Equal(got.Bytes(), test.data[:got.Len()]) {
+			t.Errorf("Expected bytes %v, got %v", test.data[:got.Len()], got.Bytes())
+		}
+	}
+
+	for _, data := range [][]byte{nil, {InstructionTransferHookExtension}} {
+		if _, err := ParseDiscriminator(data); !errors.Is(err, ErrUnknownInstruction) {
+			t.Errorf("ParseDiscriminator(%v): expected ErrUnknownInstruction, got %v", data, err)
+		}
+	}
+}
+
+func TestBuildersWriteDiscriminator(t *testing.T) {
+	var (
+		wallet      = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+	)
+	for _, inst := range []TypedInstruction{
+		NewTransferChecked2022Instruction(1, 9, source, mint, destination, wallet),
+		NewSetTransferFee2022Instruction(50, 1000, mint, wallet),
+		NewPause2022Instruction(mint, wallet),
+	} {
+		want, err := discriminatorOf(inst)
+		if err != nil {
+			t.Fatalf("discriminatorOf(%T): %v", inst, err)
+		}
+		built := inst.Build()
+		data, err := built.Data()
+		if err != nil {
+			t.Fatalf("%T: %v", inst, err)
+		}
+		got, err := ParseDiscriminator(data)
+		if err != nil {
+			t.Fatalf("%T: %v", inst, err)
+		}
+		if got != want {
+			t.Errorf("%T: expected discriminator %v, got %v", inst, want, got)
+		}
+		if built.TypeID != want.TypeID() {
+			t.Errorf("%T: expected type ID %v, got %v", inst, want.TypeID(), built.TypeID)
+		}
+	}
+}
+
+func TestDecodeRejectsWrongDiscriminator(t *testing.T) {
+	data := []byte{InstructionPausableExtension, PausableInstructionResume}
+	if err := decodeInstructionData(new(Pause2022), data); !errors.Is(err, ErrMalformedInstruction) {
+		t.Errorf("Expected ErrMalformedInstruction, got %v", err)
+	}
+}
+
+// TestBuildersMatchSPLToken checks the instructions Token-2022 shares with
+// the original SPL Token program against the independent encoders of
+// solana-go's token package: the same tag, data and accounts. Extension
+// instructions have no second implementation to compare with here and are
+// covered only by the hand-written vectors of golden_test.go.
+func TestBuildersMatchSPLToken(t *testing.T) {
+	var (
+		wallet      = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		signer      = solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR")
+		signers     = []solana.PublicKey{wallet, signer}
+	)
+	tests := []struct {
+		name string
+		ours TypedInstruction
+		spl  interface {
+			Data() ([]byte, error)
+			Accounts() []*solana.AccountMeta
+		}
+	}{
+		{"InitializeMint", NewInitializeMintLegacy2022Instruction(6, wallet, &signer, mint),
+			token.NewInitializeMintInstruction(6, wallet, signer, mint, solana.SysVarRentPubkey).Build()},
+		{"InitializeAccount", NewInitializeAccountLegacy2022Instruction(source, mint, wallet),
+			token.NewInitializeAccountInstruction(source, mint, wallet, solana.SysVarRentPubkey).Build()},
+		{"InitializeAccount2", NewInitializeAccount2Legacy2022Instruction(wallet, source, mint),
+			token.NewInitializeAccount2Instruction(wallet, source, mint, solana.SysVarRentPubkey).Build()},
+		{"InitializeMultisig", NewInitializeMultisigLegacy2022Instruction(2, source, signers...),
+			token.NewInitializeMultisigInstruction(2, source, solana.SysVarRentPubkey, signers).Build()},
+		{"InitializeMint2", NewInitializeMint2022Instruction(6, wallet, &signer, mint),
+			token.NewInitializeMint2Instruction(6, wallet, signer, mint).Build()},
+		{"InitializeMultisig2", NewInitializeMultisig2022Instruction(2, source, signers...),
+			token.NewInitializeMultisig2Instruction(2, source, signers).Build()},
+		{"Transfer", NewTransfer2022Instruction(1_500, source, destination, wallet),
+			token.NewTransferInstruction(1_500, source, destination, wallet, nil).Build()},
+		{"Transfer multisig", NewTransfer2022Instruction(1_500, source, destination, mint, signers...),
+			token.NewTransferInstruction(1_500, source, destination, mint, signers).Build()},
+		{"Approve", NewApprove2022Instruction(7, source, signer, wallet),
+			token.NewApproveInstruction(7, source, signer, wallet, nil).Build()},
+		{"Revoke", NewRevoke2022Instruction(source, wallet),
+			token.NewRevokeInstruction(source, wallet, nil).Build()},
+		{"SetAuthority", NewSetAuthority2022Instruction(AuthorityAccountOwner, &signer, source, wallet),
+			token.NewSetAuthorityInstruction(token.AuthorityAccountOwner, signer, source, wallet, nil).Build()},
+		{"MintTo", NewMintTo2022Instruction(42, mint, destination, wallet),
+			token.NewMintToInstruction(42, mint, destination, wallet, nil).Build()},
+		{"Burn", NewBurn2022Instruction(42, source, mint, wallet),
+			token.NewBurnInstruction(42, source, mint, wallet, nil).Build()},
+		{"CloseAccount", NewClose2022Instruction(source, destination, wallet),
+			token.NewCloseAccountInstruction(source, destination, wallet, nil).Build()},
+		{"FreezeAccount", NewFreezeAccount2022Instruction(source, mint, wallet),
+			token.NewFreezeAccountInstruction(source, mint, wallet, nil).Build()},
+		{"ThawAccount", NewThawAccount2022Instruction(source, mint, wallet),
+			token.NewThawAccountInstruction(source, mint, wallet, nil).Build()},
+		{"TransferChecked", NewTransferChecked2022Instruction(1_500, 6, source, mint, destination, wallet),
+			token.NewTransferCheckedInstruction(1_500, 6, source, mint, destination, wallet, nil).Build()},
+		{"ApproveChecked", NewApproveChecked2022Instruction(7, 6, source, mint, signer, wallet),
+			token.NewApproveCheckedInstruction(7, 6, source, mint, signer, wallet, nil).Build()},
+		{"MintToChecked", NewMintToChecked2022Instruction(42, 6, mint, destination, wallet),
+			token.NewMintToCheckedInstruction(42, 6, mint, destination, wallet, nil).Build()},
+		{"BurnChecked", NewBurnChecked2022Instruction(42, 6, source, mint, wallet),
+			token.NewBurnCheckedInstruction(42, 6, source, mint, wallet, nil).Build()},
+		{"SyncNative", NewSyncNative2022Instruction(source),
+			token.NewSyncNativeInstruction(source).Build()},
+	}
+	// solana-go marks the signers of a new multisig as signing, which the
+	// program does not require, so only the data is compared.
+	dataOnly := map[string]bool{"InitializeMultisig": true, "InitializeMultisig2": true}
+	for _, tt := range tests {
+		built := tt.ours.Build()
+		got, err := built.Data()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		want, err := tt.spl.Data()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s: expected data %x, got %x", tt.name, want, got)
+		}
+		if !dataOnly[tt.name] && !reflect.DeepEqual(built.Accounts(), tt.spl.Accounts()) {
+			t.Errorf("%s: expected the accounts of solana-go", tt.name)
+		}
+	}
+}
+
+// TestExtensionBuildersMatchSPLToken2022 checks the data of the extension
+// builders against the bytes spl-token-2022 packs for them. The transfer
+// fee and the non-pod instructions use the values of the crate's
+// test_instruction_packing; the pod-encoded extensions follow their
+// instruction structs, with 32 zero bytes for an unset OptionalNonZeroPubkey.
+func TestExtensionBuildersMatchSPLToken2022(t *testing.T) {
+	var (
+		mint      = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		account   = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		wallet    = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		key10     = solana.PublicKeyFromBytes(bytes.Repeat([]byte{10}, 32))
+		key11     = solana.PublicKeyFromBytes(bytes.Repeat([]byte{11}, 32))
+		key12     = solana.PublicKeyFromBytes(bytes.Repeat([]byte{12}, 32))
+		unset     = make([]byte, 32)
+		le16      = func(v uint16) []byte { return binary.LittleEndian.AppendUint16(nil, v) }
+		le64      = func(v uint64) []byte { return binary.LittleEndian.AppendUint64(nil, v) }
+		f64       = func(v float64) []byte { return le64(math.Float64bits(v)) }
+		join      = func(parts ...[]byte) []byte { return bytes.Join(parts, nil) }
+		maxFee    = uint64(math.MaxUint64)
+		maxPoints = uint16(math.MaxUint16)
+	)
+	tests := []struct {
+		name string
+		ours TypedInstruction
+		want []byte
+	}{
+		{"InitializeTransferFeeConfig", NewInitializeTransferFeeConfig2022Instruction(&key11, nil, 111, maxFee, mint),
+			join([]byte{26, 0, 1}, key11[:], []byte{0}, le16(111), le64(maxFee))},
+		{"TransferCheckedWithFee", NewTransferCheckedWithFee2022Instruction(24, 24, 23, account, mint, account, wallet),
+			join([]byte{26, 1}, le64(24), []byte{24}, le64(23))},
+		{"WithdrawWithheldTokensFromMint", NewWithdrawWithheldTokensFromMint2022Instruction(mint, account, wallet),
+			[]byte{26, 2}},
+		{"WithdrawWithheldTokensFromAccounts", NewWithdrawWithheldTokensFromAccounts2022Instruction(mint, account, []solana.PublicKey{key10, key11}, wallet),
+			[]byte{26, 3, 2}},
+		{"HarvestWithheldTokensToMint", NewHarvestWithheldTokensToMint2022Instruction(mint, account),
+			[]byte{26, 4}},
+		{"SetTransferFee", NewSetTransferFee2022Instruction(maxPoints, maxFee, mint, wallet),
+			join([]byte{26, 5}, le16(maxPoints), le64(maxFee))},
+		{"InitializeMintCloseAuthority", NewInitializeMintCloseAuthority2022Instruction(&key10, mint),
+			join([]byte{25, 1}, key10[:])},
+		{"InitializeMintCloseAuthority none", NewInitializeMintCloseAuthority2022Instruction(nil, mint),
+			[]byte{25, 0}},
+		{"InitializePermanentDelegate", NewInitializePermanentDelegate2022Instruction(key11, mint),
+			join([]byte{35}, key11[:])},
+		{"Reallocate", NewReallocate2022Instruction([]ExtensionType{ExtensionImmutableOwner, ExtensionTransferFeeConfig}, account, wallet, wallet),
+			join([]byte{29}, le16(7), le16(1))},
+		{"GetAccountDataSize", NewGetAccountDataSize2022Instruction([]ExtensionType{ExtensionTransferFeeAmount, ExtensionMemoTransfer}, mint),
+			join([]byte{21}, le16(2), le16(8))},
+		{"AmountToUiAmount", NewAmountToUiAmount2022Instruction(42, mint),
+			join([]byte{23}, le64(42))},
+		{"UiAmountToAmount", NewUiAmountToAmount2022Instruction("0.42", mint),
+			[]byte{24, '0', '.', '4', '2'}},
+		{"InitializeImmutableOwner", NewInitializeImmutableOwner2022Instruction(account),
+			[]byte{22}},
+		{"CreateNativeMint", NewCreateNativeMint2022Instruction(wallet, solana.SolMint),
+			[]byte{31}},
+		{"InitializeNonTransferableMint", NewInitializeNonTransferableMint2022Instruction(mint),
+			[]byte{32}},
+		{"WithdrawExcessLamports", NewWithdrawExcessLamports2022Instruction(mint, account, wallet),
+			[]byte{38}},
+		{"InitializeDefaultAccountState", NewInitializeDefaultAccountState2022Instruction(AccountStateFrozen, mint),
+			[]byte{28, 0, 2}},
+		{"UpdateDefaultAccountState", NewUpdateDefaultAccountState2022Instruction(AccountStateInitialized, mint, wallet),
+			[]byte{28, 1, 1}},
+		{"EnableRequiredMemoTransfers", NewEnableRequiredMemoTransfers2022Instruction(account, wallet),
+			[]byte{30, 0}},
+		{"DisableRequiredMemoTransfers", NewDisableRequiredMemoTransfers2022Instruction(account, wallet),
+			[]byte{30, 1}},
+		{"InitializeInterestBearingMint", NewInitializeInterestBearingMint2022Instruction(&key10, -300, mint),
+			join([]byte{33, 0}, key10[:], le16(0xfed4))},
+		{"InitializeInterestBearingMint no authority", NewInitializeInterestBearingMint2022Instruction(nil, 500, mint),
+			join([]byte{33, 0}, unset, le16(500))},
+		{"UpdateInterestRate", NewUpdateInterestRate2022Instruction(500, mint, wallet),
+			join([]byte{33, 1}, le16(500))},
+		{"EnableCpiGuard", NewEnableCpiGuard2022Instruction(account, wallet),
+			[]byte{34, 0}},
+		{"DisableCpiGuard", NewDisableCpiGuard2022Instruction(account, wallet),
+			[]byte{34, 1}},
+		{"InitializeTransferHook", NewInitializeTransferHook2022Instruction(&key10, &key11, mint),
+			join([]byte{36, 0}, key10[:], key11[:])},
+		{"UpdateTransferHook", NewUpdateTransferHook2022Instruction(nil, mint, wallet),
+			join([]byte{36, 1}, unset)},
+		{"InitializeMetadataPointer", NewInitializeMetadataPointer2022Instruction(nil, &mint, mint),
+			join([]byte{39, 0}, unset, mint[:])},
+		{"UpdateMetadataPointer", NewUpdateMetadataPointer2022Instruction(&key12, mint, wallet),
+			join([]byte{39, 1}, key12[:])},
+		{"InitializeGroupPointer", NewInitializeGroupPointer2022Instruction(&key10, &key12, mint),
+			join([]byte{40, 0}, key10[:], key12[:])},
+		{"InitializeGroupMemberPointer", NewInitializeGroupMemberPointer2022Instruction(&key10, &key12, mint),
+			join([]byte{41, 0}, key10[:], key12[:])},
+		{"InitializeScaledUiAmount", NewInitializeScaledUiAmount2022Instruction(&key10, 1.5, mint),
+			join([]byte{43, 0}, key10[:], f64(1.5))},
+		{"UpdateMultiplier", NewUpdateMultiplier2022Instruction(2.25, 1_700_000_000, mint, wallet),
+			join([]byte{43, 1}, f64(2.25), le64(1_700_000_000))},
+		{"InitializePausableConfig", NewInitializePausableConfig2022Instruction(key11, mint),
+			join([]byte{44, 0}, key11[:])},
+		{"Pause", NewPause2022Instruction(mint, wallet),
+			[]byte{44, 1}},
+		{"Resume", NewResume2022Instruction(mint, wallet),
+			[]byte{44, 2}},
+	}
+	for _, tt := range tests {
+		got, err := tt.ours.Build().Data()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("%s: expected data %x, got %x", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestAssociatedTokenDiscriminators(t *testing.T) {
+	var (
+		payer  = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		wallet = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint   = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	)
+	for _, idempotent := range []bool{false, true} {
+		inst, err := NewCreate2022Instruction(payer, wallet, mint).SetIdempotent(idempotent).ValidateAndBuild()
+		if err != nil {
+			t.Fatalf("Error building: %v", err)
+		}
+		data, err := inst.Data()
+		if err != nil {
+			t.Fatalf("Error getting data: %v", err)
+		}
+		want := []byte{AssociatedTokenInstructionCreate}
+		if idempotent {
+			want = []byte{AssociatedTokenInstructionCreateIdempotent}
+		}
+		if !bytes.Equal(data, want) {
+			t.Errorf("Expected data %v, got %v", want, data)
+		}
+		decoded, err := DecodeAssociatedTokenInstruction(inst.Accounts(), data)
+		if err != nil {
+			t.Fatalf("Error decoding: %v", err)
+		}
+		if decoded.(*Create2022).Idempotent != idempotent {
+			t.Errorf("Expected Idempotent %v, got %v", idempotent, decoded.(*Create2022).Idempotent)
+		}
+	}
+
+	decoded, err := DecodeAssociatedTokenInstruction(NewCreate2022Instruction(payer, wallet, mint).Build().Accounts(), nil)
+	if err != nil {
+		t.Fatalf("Error decoding empty data: %v", err)
+	}
+	if decoded.(*Create2022).Idempotent {
+		t.Errorf("Expected empty data to decode as Create")
+	}
+}
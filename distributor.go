@@ -0,0 +1,442 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+// DistributorClient is the set of RPC calls used by Distributor.
+// *rpc.Client satisfies it.
+type DistributorClient interface {
+	RPCClient
+	MultipleAccountsClient
+}
+
+var _ DistributorClient = (*rpc.Client)(nil)
+
+// Recipient is one payment of a distribution, in raw units of the mint.
+type Recipient struct {
+	Wallet solana.PublicKey `json:"wallet"`
+	Amount uint64           `json:"amount,string"`
+}
+
+// DistributionStatus is the outcome of the payment to one recipient.
+type DistributionStatus string
+
+const (
+	// DistributionPending payments have not been sent.
+	DistributionPending DistributionStatus = "pending"
+	// DistributionPaid payments landed.
+	DistributionPaid DistributionStatus = "paid"
+	// DistributionFailed payments did not land: the transaction failed
+	// on-chain or expired unconfirmed. They are safe to send again.
+	DistributionFailed DistributionStatus = "failed"
+	// DistributionUnknown payments were sent but their outcome was not
+	// learned, for instance because ctx was cancelled while waiting for
+	// confirmation. Check them before paying again.
+	DistributionUnknown DistributionStatus = "unknown"
+)
+
+// DistributionResult is the outcome of the payment to one recipient.
+type DistributionResult struct {
+	Recipient
+	// Account is the associated token account of the recipient.
+	Account solana.PublicKey   `json:"account"`
+	Status  DistributionStatus `json:"status"`
+	// Signature is the transaction that paid the recipient, along with
+	// the others of its batch, once known.
+	Signature solana.Signature `json:"signature,omitempty"`
+	Error     string           `json:"error,omitempty"`
+}
+
+// DistributionReport is the outcome of a distribution, with one result per
+// recipient in the order given. It encodes to JSON so a run can be saved
+// and resumed with Distributor.Resume.
+type DistributionReport struct {
+	Results []DistributionResult `json:"results"`
+}
+
+// Count returns the number of results with status.
+func (r *DistributionReport) Count(status DistributionStatus) int {
+	n := 0
+	for _, result := range r.Results {
+		if result.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Distributor pays a list of recipients from one source token account, as
+// for an airdrop. It creates the associated token accounts that do not
+// exist yet, packs as many payments into each transaction as fit in
+// MaxTransactionSize, optionally compressed with address lookup tables,
+// and sends the transactions with a Sender on a bounded pool of workers.
+//
+// Distributor is safe for concurrent use once configured.
+type Distributor struct {
+	client      DistributorClient
+	mint        solana.PublicKey
+	decimals    uint8
+	source      solana.PublicKey
+	authority   Signer
+	feePayer    Signer
+	sender      *Sender
+	commitment  rpc.CommitmentType
+	concurrency int
+	tables      map[solana.PublicKey]solana.PublicKeySlice
+	options     []SendOption
+	progress    func(DistributionResult)
+	// allowOwnerOffCurve pays recipients off the ed25519 curve; see
+	// SetAllowOwnerOffCurve.
+	allowOwnerOffCurve bool
+
+	// minting mints to the recipients instead of transferring from
+	// source; see NewMintToMany.
+	minting   bool
+	maxSupply *uint64
+}
+
+// NewDistributor creates a distributor of mint, with decimals decimals,
+// paying from source, whose owner or delegate is authority. The authority
+// also pays the fees and rent, and four transactions are in flight at
+// once.
+func NewDistributor(client DistributorClient, mint solana.PublicKey, decimals uint8, source solana.PublicKey, authority Signer) *Distributor {
+	return &Distributor{
+		client:      client,
+		mint:        mint,
+		decimals:    decimals,
+		source:      source,
+		authority:   authority,
+		feePayer:    authority,
+		sender:      NewSender(client),
+		commitment:  rpc.CommitmentConfirmed,
+		concurrency: 4,
+	}
+}
+
+// SetFeePayer pays fees and the rent of created accounts from payer
+// instead of the authority.
+func (d *Distributor) SetFeePayer(payer Signer) *Distributor {
+	d.feePayer = payer
+	return d
+}
+
+// SetSender sends the transactions with sender, for instance to change
+// its commitment or attach a logger.
+func (d *Distributor) SetSender(sender *Sender) *Distributor {
+	d.sender = sender
+	return d
+}
+
+// SetSendOptions tunes the submission of every transaction, on top of the
+// options of the sender.
+func (d *Distributor) SetSendOptions(options ...SendOption) *Distributor {
+	d.options = options
+	return d
+}
+
+// SetCommitment sets the commitment at which recipient accounts are read.
+func (d *Distributor) SetCommitment(commitment rpc.CommitmentType) *Distributor {
+	d.commitment = commitment
+	return d
+}
+
+// SetConcurrency sets the number of transactions in flight at once.
+func (d *Distributor) SetConcurrency(workers int) *Distributor {
+	if workers > 0 {
+		d.concurrency = workers
+	}
+	return d
+}
+
+// SetAddressTables compresses the transactions with address lookup
+// tables, so more payments fit in each. A table holding the mint, the
+// source, the authority and the token programs helps every transaction.
+func (d *Distributor) SetAddressTables(tables map[solana.PublicKey]solana.PublicKeySlice) *Distributor {
+	d.tables = tables
+	return d
+}
+
+// SetProgress sets a callback run with the result of every recipient
+// once its transaction completes. Calls are serialized.
+func (d *Distributor) SetProgress(progress func(DistributionResult)) *Distributor {
+	d.progress = progress
+	return d
+}
+
+// SetAllowOwnerOffCurve pays recipients whose wallet is off the ed25519
+// curve, such as program derived addresses. By default their payments are
+// marked failed with ErrOwnerOffCurve, as only the deriving program could
+// move the tokens.
+func (d *Distributor) SetAllowOwnerOffCurve(allow bool) *Distributor {
+	d.allowOwnerOffCurve = allow
+	return d
+}
+
+// Validate checks that the distributor is configured.
+func (d *Distributor) Validate() error {
+	if d.client == nil {
+		return errNotSet("RPC client")
+	}
+	if d.mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	if d.source.IsZero() && !d.minting {
+		return errNotSet("Source")
+	}
+	if d.authority == nil {
+		return errNotSet("Authority")
+	}
+	if d.feePayer == nil {
+		return errNotSet("FeePayer")
+	}
+	return nil
+}
+
+// Distribute pays every recipient and returns a report with one result
+// per recipient. Payments that fail are recorded in the report rather
+// than returned; the error is only set when the run could not start or
+// ctx was done, in which case the report holds the results so far.
+func (d *Distributor) Distribute(ctx context.Context, recipients []Recipient) (*DistributionReport, error) {
+	report := newDistributionReport(recipients)
+	return report, d.run(ctx, report)
+}
+
+// Plan validates a distribution and returns the instructions of each
+// transaction Distribute would send, without sending anything. A
+// recipient Distribute would mark failed, such as a wallet off the curve,
+// is returned as an error.
+func (d *Distributor) Plan(ctx context.Context, recipients []Recipient) ([][]solana.Instruction, error) {
+	report := newDistributionReport(recipients)
+	batches, err := d.prepare(ctx, report)
+	if err != nil {
+		return nil, err
+	}
+	for i, result := range report.Results {
+		if err := ValidateOwner(result.Wallet, d.allowOwnerOffCurve); err != nil {
+			return nil, fmt.Errorf("recipient %d: %w", i, err)
+		}
+	}
+	transactions := make([][]solana.Instruction, len(batches))
+	for i, batch := range batches {
+		transactions[i] = batch.instructions
+	}
+	return transactions, nil
+}
+
+func newDistributionReport(recipients []Recipient) *DistributionReport {
+	report := &DistributionReport{Results: make([]DistributionResult, len(recipients))}
+	for i, recipient := range recipients {
+		report.Results[i] = DistributionResult{Recipient: recipient, Status: DistributionPending}
+	}
+	return report
+}
+
+// Resume continues a distribution from a saved report, sending the
+// payments that are pending or failed again. Unknown payments are left
+// for the caller to check, so that no recipient is paid twice. The report
+// is updated in place and returned.
+func (d *Distributor) Resume(ctx context.Context, report *DistributionReport) (*DistributionReport, error) {
+	return report, d.run(ctx, report)
+}
+
+func (d *Distributor) run(ctx context.Context, report *DistributionReport) error {
+	batches, err := d.prepare(ctx, report)
+	if err != nil || len(batches) == 0 {
+		return err
+	}
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		work = make(chan distributionBatch)
+	)
+	for i := 0; i < min(d.concurrency, len(batches)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range work {
+				result, err := d.sender.Send(ctx, NewTxBuilder(d.client).
+					SetFeePayer(d.feePayer.Pubkey()).
+					SetAddressTables(d.tables).
+					AddInstruction(batch.instructions...).
+					AddSigner(d.authority, d.feePayer), d.options...)
+
+				mu.Lock()
+				for _, i := range batch.recipients {
+					r := &report.Results[i]
+					r.Status, r.Error = distributionStatus(err), ""
+					if err != nil {
+						r.Error = err.Error()
+					}
+					if result != nil {
+						r.Signature = result.Signature
+					}
+					if d.progress != nil {
+						d.progress(*r)
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, batch := range batches {
+		select {
+		case work <- batch:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(work)
+	wg.Wait()
+	return ctx.Err()
+}
+
+// prepare validates the distributor and the pending and failed payments
+// of report and groups them into transactions. Payments to wallets that
+// cannot own an associated token account are marked failed and left out.
+func (d *Distributor) prepare(ctx context.Context, report *DistributionReport) ([]distributionBatch, error) {
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+	var (
+		todo  []int
+		total uint64
+	)
+	for i := range report.Results {
+		result := &report.Results[i]
+		if result.Wallet.IsZero() {
+			return nil, fmt.Errorf("recipient %d: %w", i, errNotSet("Wallet"))
+		}
+		if result.Status != DistributionPending && result.Status != DistributionFailed {
+			continue
+		}
+		account, _, err := FindAssociatedTokenAddress2022Checked(result.Wallet, d.mint, d.allowOwnerOffCurve)
+		if err != nil {
+			result.Status, result.Error = DistributionFailed, err.Error()
+			if d.progress != nil {
+				d.progress(*result)
+			}
+			continue
+		}
+		result.Account = account
+		todo = append(todo, i)
+		sum, err := CheckedAdd(total, result.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("error while adding up the amounts: %w", err)
+		}
+		total = sum
+	}
+	if len(todo) == 0 {
+		return nil, nil
+	}
+	if d.minting {
+		if err := d.checkSupply(ctx, total); err != nil {
+			return nil, err
+		}
+	}
+	return d.plan(ctx, report, todo)
+}
+
+// distributionStatus classifies the outcome of Sender.Send. A failed
+// simulation, an on-chain failure or an expired blockhash with no attempt
+// landed leave the recipients unpaid; anything else may have landed.
+func distributionStatus(err error) DistributionStatus {
+	var rpcErr *jsonrpc.RPCError
+	switch {
+	case err == nil:
+		return DistributionPaid
+	case errors.Is(err, ErrBlockhashExpired), errors.As(err, new(*TransactionError)):
+		return DistributionFailed
+	case errors.As(err, &rpcErr) && rpcErr.Code == rpcSimulationFailed:
+		return DistributionFailed
+	}
+	return DistributionUnknown
+}
+
+// distributionBatch is one transaction of a distribution.
+type distributionBatch struct {
+	recipients   []int
+	instructions []solana.Instruction
+}
+
+// plan groups the payments to the recipients at indexes todo, whose
+// Account prepare derived, into transactions, creating the associated
+// token accounts that are missing.
+func (d *Distributor) plan(ctx context.Context, report *DistributionReport, todo []int) ([]distributionBatch, error) {
+	accounts := make([]solana.PublicKey, len(todo))
+	for j, i := range todo {
+		accounts[j] = report.Results[i].Account
+	}
+	existing, err := NewAccountFetcher(d.client).SetFetchOpts(FetchOpts{Commitment: d.commitment}).Fetch(ctx, accounts)
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching recipient accounts: %w", err)
+	}
+
+	var (
+		batches []distributionBatch
+		current distributionBatch
+		created = map[solana.PublicKey]bool{}
+	)
+	for j, i := range todo {
+		result := &report.Results[i]
+		var steps []solana.Instruction
+		if existing[j] == nil && !created[result.Account] {
+			create, err := NewCreate2022Instruction(d.feePayer.Pubkey(), result.Wallet, d.mint).
+				SetIdempotent(true).
+				SetAllowOwnerOffCurve(d.allowOwnerOffCurve).
+				ValidateAndBuild()
+			if err != nil {
+				return nil, fmt.Errorf("recipient %d: %w", i, err)
+			}
+			steps = append(steps, create)
+			created[result.Account] = true
+		}
+		var payment TypedInstruction = NewTransferChecked2022Instruction(result.Amount, d.decimals, d.source, d.mint, result.Account, d.authority.Pubkey())
+		if d.minting {
+			payment = NewMintToChecked2022Instruction(result.Amount, d.decimals, d.mint, result.Account, d.authority.Pubkey())
+		}
+		if err := payment.Validate(); err != nil {
+			return nil, fmt.Errorf("recipient %d: %w", i, err)
+		}
+		steps = append(steps, payment.Build())
+
+		size, err := transactionSize(d.feePayer.Pubkey(), append(current.instructions[:len(current.instructions):len(current.instructions)], steps...), d.tables)
+		if err != nil {
+			return nil, err
+		}
+		if size > MaxTransactionSize && len(current.instructions) > 0 {
+			batches = append(batches, current)
+			current = distributionBatch{}
+		}
+		current.recipients = append(current.recipients, i)
+		current.instructions = append(current.instructions, steps...)
+	}
+	if len(current.instructions) > 0 {
+		batches = append(batches, current)
+	}
+	return batches, nil
+}
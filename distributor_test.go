@@ -0,0 +1,174 @@
+package token2022
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestDistributor(t *testing.T) {
+	var (
+		authority = solana.NewWallet().PrivateKey
+		mint      = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source    = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		client    = newMockRPC()
+		ctx       = context.Background()
+	)
+	var recipients []Recipient
+	for i := 0; i < 30; i++ {
+		wallet := solana.NewWallet().PublicKey()
+		recipients = append(recipients, Recipient{Wallet: wallet, Amount: uint64(i + 1)})
+		if i%2 == 0 {
+			account, _, _ := FindAssociatedTokenAddress2022(wallet, mint)
+			client.setAccount(account, solana.Token2022ProgramID, encodeTokenAccount(mint, wallet, 0))
+		}
+	}
+
+	// The first transaction fails on-chain, the others land.
+	client.onSend = func(tx *solana.Transaction) {
+		status := &rpc.SignatureStatusesResult{Slot: 42, ConfirmationStatus: rpc.ConfirmationStatusConfirmed}
+		if len(client.sent) == 1 {
+			status.Err = map[string]interface{}{"InstructionError": []interface{}{float64(0), map[string]interface{}{"Custom": float64(1)}}}
+		}
+		client.statuses[tx.Signatures[0]] = status
+	}
+	distributor := NewDistributor(client, mint, 6, source, NewPrivateKeySigner(authority)).
+		SetSender(NewSender(client).SetPollInterval(time.Millisecond)).
+		SetConcurrency(1)
+	report, err := distributor.Distribute(ctx, recipients)
+	if err != nil {
+		t.Fatalf("Distribute: %v", err)
+	}
+	if len(client.sent) < 2 {
+		t.Fatalf("Expected the payments to span several transactions, got %d", len(client.sent))
+	}
+	for i, tx := range client.sent {
+		data, err := tx.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary: %v", err)
+		}
+		if len(data) > MaxTransactionSize {
+			t.Errorf("Expected transaction %d to fit, got %d bytes", i, len(data))
+		}
+	}
+	failed := report.Count(DistributionFailed)
+	if failed == 0 || report.Count(DistributionPaid) != len(recipients)-failed {
+		t.Fatalf("Expected the first batch to fail and the rest to be paid, got %d failed and %d paid", failed, report.Count(DistributionPaid))
+	}
+	if report.Results[0].Status != DistributionFailed || report.Results[0].Error == "" {
+		t.Errorf("Expected the first recipient to have failed, got %+v", report.Results[0])
+	}
+
+	// The report survives a round trip through JSON and resumes with the
+	// failed payments only.
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var saved DistributionReport
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	sent := len(client.sent)
+	resumed, err := distributor.Resume(ctx, &saved)
+	if err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+	if resumed.Count(DistributionPaid) != len(recipients) {
+		t.Errorf("Expected every recipient to be paid, got %d", resumed.Count(DistributionPaid))
+	}
+	if len(client.sent) != sent+1 {
+		t.Errorf("Expected one more transaction, got %d", len(client.sent)-sent)
+	}
+	paid := map[solana.PublicKey]uint64{}
+	for _, tx := range client.sent[1:] {
+		for _, inst := range tx.Message.Instructions {
+			accounts, _ := inst.ResolveInstructionAccounts(&tx.Message)
+			program, _ := tx.Message.Program(inst.ProgramIDIndex)
+			if !program.Equals(solana.Token2022ProgramID) {
+				continue
+			}
+			decoded, err := DecodeInstruction(accounts, inst.Data)
+			if err != nil {
+				t.Fatalf("DecodeInstruction: %v", err)
+			}
+			if transfer, ok := decoded.(*TransferChecked2022); ok {
+				paid[transfer.Destination] += transfer.Amount
+			}
+		}
+	}
+	for _, result := range resumed.Results {
+		if paid[result.Account] != result.Amount {
+			t.Errorf("Expected %s to be paid %d once, got %d", result.Wallet, result.Amount, paid[result.Account])
+		}
+	}
+}
+
+func TestDistributorOwnerOffCurve(t *testing.T) {
+	var (
+		authority = solana.NewWallet().PrivateKey
+		wallet    = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint      = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source    = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		client    = newMockRPC()
+		ctx       = context.Background()
+	)
+	pda, _, _ := FindAssociatedTokenAddress2022(wallet, mint)
+	client.onSend = func(tx *solana.Transaction) {
+		client.statuses[tx.Signatures[0]] = &rpc.SignatureStatusesResult{Slot: 42, ConfirmationStatus: rpc.ConfirmationStatusConfirmed}
+	}
+	distributor := NewDistributor(client, mint, 6, source, NewPrivateKeySigner(authority)).
+		SetSender(NewSender(client).SetPollInterval(time.Millisecond))
+	report, err := distributor.Distribute(ctx, []Recipient{{Wallet: pda, Amount: 1}, {Wallet: wallet, Amount: 2}})
+	if err != nil {
+		t.Fatalf("Distribute: %v", err)
+	}
+	if report.Results[0].Status != DistributionFailed || !strings.Contains(report.Results[0].Error, ErrOwnerOffCurve.Error()) {
+		t.Errorf("Expected the off-curve recipient to fail, got %+v", report.Results[0])
+	}
+	if report.Results[1].Status != DistributionPaid || len(client.sent) != 1 {
+		t.Errorf("Expected the wallet to be paid in one transaction, got %+v", report.Results[1])
+	}
+
+	resumed, err := distributor.SetAllowOwnerOffCurve(true).Resume(ctx, report)
+	if err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+	if resumed.Count(DistributionPaid) != 2 {
+		t.Errorf("Expected the allowed off-curve recipient to be paid, got %+v", resumed.Results[0])
+	}
+}
+
+func TestDistributorAddressTables(t *testing.T) {
+	var (
+		authority = solana.NewWallet().PrivateKey
+		mint      = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source    = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		table     = solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR")
+	)
+	var recipients []Recipient
+	for i := 0; i < 60; i++ {
+		recipients = append(recipients, Recipient{Wallet: solana.NewWallet().PublicKey(), Amount: 1})
+	}
+	report := newDistributionReport(recipients)
+	distributor := NewDistributor(newMockRPC(), mint, 6, source, NewPrivateKeySigner(authority))
+	plain, err := distributor.prepare(context.Background(), report)
+	if err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+	distributor.SetAddressTables(map[solana.PublicKey]solana.PublicKeySlice{
+		table: {mint, source, solana.Token2022ProgramID, solana.SystemProgramID, solana.SPLAssociatedTokenAccountProgramID},
+	})
+	compressed, err := distributor.prepare(context.Background(), report)
+	if err != nil {
+		t.Fatalf("prepare: %v", err)
+	}
+	if len(compressed) >= len(plain) {
+		t.Errorf("Expected lookup tables to need fewer transactions than %d, got %d", len(plain), len(compressed))
+	}
+}
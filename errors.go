@@ -0,0 +1,125 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dwmfan/token2022/core"
+)
+
+// Sentinel errors for branching on failure causes with errors.Is. The
+// errors returned by this package wrap them and add details, such as the
+// account or the length of the data.
+var (
+	// ErrNotSet is matched by every NotSetError.
+	ErrNotSet = errors.New("not set")
+	// ErrInvalidField is matched by every InvalidFieldError.
+	ErrInvalidField = errors.New("invalid field")
+	// ErrMalformedInstruction is wrapped by the decoders of instruction
+	// builders for a wrong tag, too few accounts or malformed data.
+	ErrMalformedInstruction = errors.New("malformed instruction")
+	// ErrInvalidAccountMeta is matched by every AccountMetaError.
+	ErrInvalidAccountMeta = errors.New("invalid account meta")
+	// ErrInvalidAccountData is wrapped by the decoders of mints, token
+	// accounts, extensions and nonce accounts for data of the wrong length
+	// or type. It is the error of package core, which decodes the
+	// layouts.
+	ErrInvalidAccountData = core.ErrInvalidAccountData
+	// ErrAccountNotFound is wrapped when a fetched account does not
+	// exist.
+	ErrAccountNotFound = errors.New("account not found")
+	// ErrInvalidAccountOwner is wrapped when a fetched account is not
+	// owned by the expected program.
+	ErrInvalidAccountOwner = errors.New("invalid account owner")
+	// ErrIncompatibleExtensions is wrapped by MintProfile.Validate for
+	// extensions the program rejects together, or that defeat each other.
+	ErrIncompatibleExtensions = errors.New("incompatible extensions")
+	// ErrPolicyRejected is matched by every PolicyViolation that rejected
+	// a transaction.
+	ErrPolicyRejected = errors.New("rejected by policy")
+)
+
+// NotSetError is returned by Validate when a required account or field of
+// a builder is not set.
+type NotSetError struct {
+	// Field is the name of the builder field, such as "Mint".
+	Field string
+}
+
+func (e *NotSetError) Error() string {
+	return e.Field + " not set"
+}
+
+// Is reports whether target is ErrNotSet.
+func (e *NotSetError) Is(target error) bool {
+	return target == ErrNotSet
+}
+
+func errNotSet(field string) error {
+	return &NotSetError{Field: field}
+}
+
+// InvalidFieldError is returned when a field of a builder is set to a
+// value the program rejects, or, by ValidateStrict, to a value the program
+// accepts but that is almost certainly a mistake.
+type InvalidFieldError struct {
+	Field string
+	// Reason completes the sentence started by Field, such as "must be
+	// positive".
+	Reason string
+}
+
+func (e *InvalidFieldError) Error() string {
+	return e.Field + " " + e.Reason
+}
+
+// Is reports whether target is ErrInvalidField.
+func (e *InvalidFieldError) Is(target error) bool {
+	return target == ErrInvalidField
+}
+
+func errInvalidField(field, reason string, args ...any) error {
+	return &InvalidFieldError{Field: field, Reason: fmt.Sprintf(reason, args...)}
+}
+
+// AccountMetaError is returned by Validate when an account a builder got
+// from SetAccounts lacks a privilege the program requires at its
+// position.
+type AccountMetaError struct {
+	// Instruction is the name of the builder, such as "TransferChecked2022".
+	Instruction string
+	// Index is the position of the account in the instruction.
+	Index int
+	// Account is the name of the account, such as "Owner".
+	Account string
+	// Reason completes the sentence started by Account, such as "must
+	// sign".
+	Reason string
+}
+
+func (e *AccountMetaError) Error() string {
+	return fmt.Sprintf("%s: account %d (%s) %s", e.Instruction, e.Index, e.Account, e.Reason)
+}
+
+// Is reports whether target is ErrInvalidAccountMeta.
+func (e *AccountMetaError) Is(target error) bool {
+	return target == ErrInvalidAccountMeta
+}
+
+func errInvalidAccountMeta(instruction string, index int, account, reason string) error {
+	return &AccountMetaError{Instruction: instruction, Index: index, Account: account, Reason: reason}
+}
@@ -0,0 +1,64 @@
+package token2022
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	var (
+		wallet = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint   = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	)
+
+	err := NewTransferChecked2022InstructionBuilder().SetMint(mint).Validate()
+	var notSet *NotSetError
+	if !errors.As(err, &notSet) || !errors.Is(err, ErrNotSet) {
+		t.Fatalf("Expected a NotSetError, got %v", err)
+	}
+	if notSet.Field == "" || err.Error() != notSet.Field+" not set" {
+		t.Errorf("Unexpected NotSetError %q for field %q", err, notSet.Field)
+	}
+
+	invalid := []struct {
+		name string
+		err  error
+	}{
+		{"InitializeDefaultAccountState2022", NewInitializeDefaultAccountState2022Instruction(AccountStateUninitialized, mint).Validate()},
+		{"UpdateDefaultAccountState2022", NewUpdateDefaultAccountState2022Instruction(AccountStateUninitialized, mint, wallet).Validate()},
+		{"InitializeScaledUiAmount2022", NewInitializeScaledUiAmount2022Instruction(nil, 0, mint).Validate()},
+		{"UpdateMultiplier2022", NewUpdateMultiplier2022Instruction(-1, 0, mint, wallet).Validate()},
+		{"TxBuilder", NewTxBuilder(newMockRPC()).SetFeePayer(wallet).Validate()},
+		{"MultiTransfer", NewMultiTransfer(newMockRPC(), mint, wallet, wallet).Validate()},
+	}
+	for _, tt := range invalid {
+		var invalidField *InvalidFieldError
+		if !errors.As(tt.err, &invalidField) || !errors.Is(tt.err, ErrInvalidField) {
+			t.Errorf("%s: expected an InvalidFieldError, got %v", tt.name, tt.err)
+		}
+	}
+
+	if _, err := DecodeMint(make([]byte, 10)); !errors.Is(err, ErrInvalidAccountData) {
+		t.Errorf("Expected ErrInvalidAccountData, got %v", err)
+	}
+	if _, err := DecodeInstruction(nil, []byte{byte(InstructionTransferChecked)}); !errors.Is(err, ErrMalformedInstruction) {
+		t.Errorf("Expected ErrMalformedInstruction, got %v", err)
+	}
+
+	client := newMockRPC()
+	ctx := context.Background()
+	if _, err := FetchMint(ctx, client, mint, rpc.CommitmentConfirmed); !errors.Is(err, ErrAccountNotFound) || !errors.Is(err, rpc.ErrNotFound) {
+		t.Errorf("Expected ErrAccountNotFound wrapping rpc.ErrNotFound, got %v", err)
+	}
+	client.setAccount(mint, solana.SystemProgramID, make([]byte, MintSize))
+	if _, err := FetchMint(ctx, client, mint, rpc.CommitmentConfirmed); !errors.Is(err, ErrInvalidAccountOwner) {
+		t.Errorf("Expected ErrInvalidAccountOwner, got %v", err)
+	}
+	if _, err := ResolveProgram(ctx, client, wallet, rpc.CommitmentConfirmed); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("Expected ErrAccountNotFound, got %v", err)
+	}
+}
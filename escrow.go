@@ -0,0 +1,159 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"fmt"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// Allowance is what a delegate may still pull from a token account, read
+// from the account's state. Amount is the delegated amount left, which
+// every pull by the delegate lowers, and Balance what the account holds.
+type Allowance struct {
+	Account  solana.PublicKey
+	Owner    solana.PublicKey
+	Delegate solana.PublicKey
+	Amount   uint64
+	Balance  uint64
+	Frozen   bool
+}
+
+// AllowanceOf returns the allowance of delegate over account, the token
+// account at address. The allowance is zero when account approves another
+// delegate or none.
+func AllowanceOf(address solana.PublicKey, account *TokenAccount, delegate solana.PublicKey) *Allowance {
+	allowance := &Allowance{
+		Account:  address,
+		Owner:    account.Owner,
+		Delegate: delegate,
+		Balance:  account.Amount,
+		Frozen:   account.IsFrozen(),
+	}
+	if account.Delegate != nil && account.Delegate.Equals(delegate) {
+		allowance.Amount = account.DelegatedAmount
+	}
+	return allowance
+}
+
+// Available returns how much the delegate can pull now: the allowance,
+// up to the balance, or nothing from a frozen account.
+func (a *Allowance) Available() uint64 {
+	if a.Frozen {
+		return 0
+	}
+	return min(a.Amount, a.Balance)
+}
+
+// Check returns nil when the delegate can pull amount, and otherwise the
+// error the token program would fail the pull with.
+func (a *Allowance) Check(amount uint64) error {
+	switch {
+	case a.Frozen:
+		return fmt.Errorf("%w: %s", ErrAccountFrozen, a.Account)
+	case a.Amount < amount:
+		return fmt.Errorf("%w: %s may pull %d more from %s, not %d", ErrInsufficientFunds, a.Delegate, a.Amount, a.Account, amount)
+	case a.Balance < amount:
+		return fmt.Errorf("%w: %s holds %d, not %d", ErrInsufficientFunds, a.Account, a.Balance, amount)
+	}
+	return nil
+}
+
+// Escrow is an approve-then-pull arrangement between a payer and a
+// service. The payer approves the service's delegate for an amount out of
+// its token account; the service later pulls up to that amount, in one or
+// several TransferChecked instructions signed by the delegate, without the
+// payer signing again. Funds stay in the payer's account until pulled, and
+// the payer can Revoke what is left at any time.
+//
+// Escrow only builds the instructions of both sides; FetchAllowance reads
+// how much is left to pull.
+type Escrow struct {
+	mint     solana.PublicKey
+	decimals uint8
+	payer    solana.PublicKey
+	source   solana.PublicKey
+	delegate solana.PublicKey
+}
+
+// NewEscrow creates an escrow by which payer approves delegate to pull
+// tokens of mint, with decimals decimals, out of the payer's associated
+// token account.
+func NewEscrow(mint solana.PublicKey, decimals uint8, payer, delegate solana.PublicKey) (*Escrow, error) {
+	source, _, err := FindAssociatedTokenAddress2022(payer, mint)
+	if err != nil {
+		return nil, err
+	}
+	return &Escrow{mint: mint, decimals: decimals, payer: payer, source: source, delegate: delegate}, nil
+}
+
+// SetSource pulls from source, a token account of the payer other than
+// its associated token account.
+func (e *Escrow) SetSource(source solana.PublicKey) *Escrow {
+	e.source = source
+	return e
+}
+
+// Source returns the token account the delegate pulls from.
+func (e *Escrow) Source() solana.PublicKey {
+	return e.source
+}
+
+// Approve returns the instruction, signed by the payer, approving the
+// delegate for amount. It replaces whatever was left of an earlier
+// approval.
+func (e *Escrow) Approve(amount uint64, multisigSigners ...solana.PublicKey) *ApproveChecked2022 {
+	return NewApproveChecked2022Instruction(amount, e.decimals, e.source, e.mint, e.delegate, e.payer, multisigSigners...)
+}
+
+// TopUp returns the instruction, signed by the payer, raising the
+// allowance of the delegate by amount on top of allowance, as read by
+// FetchAllowance.
+func (e *Escrow) TopUp(allowance *Allowance, amount uint64, multisigSigners ...solana.PublicKey) (*ApproveChecked2022, error) {
+	total, err := CheckedAdd(allowance.Amount, amount)
+	if err != nil {
+		return nil, fmt.Errorf("error while adding %d to the allowance: %w", amount, err)
+	}
+	return e.Approve(total, multisigSigners...), nil
+}
+
+// Revoke returns the instruction, signed by the payer, cancelling what is
+// left of the allowance.
+func (e *Escrow) Revoke(multisigSigners ...solana.PublicKey) *Revoke2022 {
+	return NewRevoke2022Instruction(e.source, e.payer, multisigSigners...)
+}
+
+// Pull returns the instruction, signed by the delegate, moving amount
+// from the payer to destination. The token program lowers the allowance
+// by amount, and fails the pull when it exceeds what is left.
+func (e *Escrow) Pull(amount uint64, destination solana.PublicKey) *TransferChecked2022 {
+	return NewTransferChecked2022Instruction(amount, e.decimals, e.source, e.mint, destination, e.delegate)
+}
+
+// FetchAllowance reads the source account and returns the allowance of
+// the delegate over it.
+func (e *Escrow) FetchAllowance(ctx context.Context, client RPCClient, commitment rpc.CommitmentType) (*Allowance, error) {
+	account, err := FetchTokenAccount(ctx, client, e.source, commitment)
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching source account: %w", err)
+	}
+	if !account.Owner.Equals(e.payer) {
+		return nil, fmt.Errorf("%w: %s is owned by %s, not %s", ErrOwnerMismatch, e.source, account.Owner, e.payer)
+	}
+	return AllowanceOf(e.source, account, e.delegate), nil
+}
@@ -0,0 +1,93 @@
+package token2022
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestEscrow(t *testing.T) {
+
+	var (
+		client      = newMockRPC()
+		ctx         = context.Background()
+		mint        = solana.NewWallet().PublicKey()
+		payer       = solana.NewWallet().PublicKey()
+		service     = solana.NewWallet().PublicKey()
+		destination = solana.NewWallet().PublicKey()
+	)
+	escrow, err := NewEscrow(mint, 6, payer, service)
+	if err != nil {
+		t.Fatalf("NewEscrow: %v", err)
+	}
+	source, _, _ := FindAssociatedTokenAddress2022(payer, mint)
+	if escrow.Source() != source {
+		t.Fatalf("Expected the payer's associated token account, got %s", escrow.Source())
+	}
+
+	approve := escrow.Approve(500)
+	if err := approve.Validate(); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	if approve.Source != source || approve.Delegate != service || approve.Owner != payer || approve.Amount != 500 {
+		t.Errorf("Unexpected approval %+v", approve)
+	}
+	pull, err := escrow.Pull(200, destination).ValidateAndBuild()
+	if err != nil {
+		t.Fatalf("Pull: %v", err)
+	}
+	if accounts := pull.Accounts(); accounts[0].PublicKey != source || accounts[3].PublicKey != service || !accounts[3].IsSigner {
+		t.Errorf("Expected the delegate to sign the pull from the source, got %+v", accounts)
+	}
+	if revoke := escrow.Revoke(); revoke.Validate() != nil || revoke.Source != source || revoke.Owner != payer {
+		t.Errorf("Unexpected revoke %+v", revoke)
+	}
+
+	setSource := func(delegate solana.PublicKey, delegated, balance uint64) {
+		client.setAccount(source, solana.Token2022ProgramID, EncodeTokenAccount(&TokenAccount{
+			Mint: mint, Owner: payer, Amount: balance, Delegate: &delegate, DelegatedAmount: delegated, State: AccountStateInitialized,
+		}))
+	}
+
+	// 200 of the 500 approved were pulled, and the payer holds 250.
+	setSource(service, 300, 250)
+	allowance, err := escrow.FetchAllowance(ctx, client, rpc.CommitmentConfirmed)
+	if err != nil {
+		t.Fatalf("FetchAllowance: %v", err)
+	}
+	if allowance.Amount != 300 || allowance.Available() != 250 {
+		t.Errorf("Expected 300 allowed and 250 available, got %+v", allowance)
+	}
+	if err := allowance.Check(250); err != nil {
+		t.Errorf("Check: %v", err)
+	}
+	if err := allowance.Check(260); !errors.Is(err, ErrInsufficientFunds) {
+		t.Errorf("Expected ErrInsufficientFunds over the balance, got %v", err)
+	}
+	topUp, err := escrow.TopUp(allowance, 100)
+	if err != nil || topUp.Amount != 400 {
+		t.Errorf("Expected a top-up to 400, got %+v, %v", topUp, err)
+	}
+	if _, err := escrow.TopUp(allowance, math.MaxUint64); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Expected ErrOverflow, got %v", err)
+	}
+
+	// The payer approved someone else.
+	setSource(solana.NewWallet().PublicKey(), 300, 250)
+	allowance, err = escrow.FetchAllowance(ctx, client, rpc.CommitmentConfirmed)
+	if err != nil {
+		t.Fatalf("FetchAllowance: %v", err)
+	}
+	if allowance.Amount != 0 || !errors.Is(allowance.Check(1), ErrInsufficientFunds) {
+		t.Errorf("Expected no allowance, got %+v", allowance)
+	}
+
+	other, _ := NewEscrow(mint, 6, solana.NewWallet().PublicKey(), service)
+	if _, err := other.SetSource(source).FetchAllowance(ctx, client, rpc.CommitmentConfirmed); !errors.Is(err, ErrOwnerMismatch) {
+		t.Errorf("Expected ErrOwnerMismatch, got %v", err)
+	}
+}
@@ -0,0 +1,263 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// LamportsPerSignature is the base fee of each transaction signature.
+const LamportsPerSignature = 5000
+
+const (
+	// defaultComputeUnitsPerInstruction and maxComputeUnits bound the
+	// compute of a transaction without a SetComputeUnitLimit instruction.
+	defaultComputeUnitsPerInstruction = 200_000
+	maxComputeUnits                   = 1_400_000
+
+	computeBudgetSetComputeUnitLimit = 2
+	computeBudgetSetComputeUnitPrice = 3
+)
+
+// NewAccountCost is an account created by a planned instruction set.
+type NewAccountCost struct {
+	Address solana.PublicKey
+	// Space is the account size, or 0 when the instruction states the
+	// lamports rather than the size.
+	Space    int
+	Lamports uint64
+}
+
+// CostEstimate is the result of EstimateCost. Lamport amounts are paid by
+// the fee payer; TransferFees are in token base units.
+type CostEstimate struct {
+	Signatures   int
+	SignatureFee uint64
+	// ComputeUnitLimit and ComputeUnitPrice, in micro-lamports per unit,
+	// come from the ComputeBudget instructions. Without a limit, the
+	// default of 200,000 units per instruction is assumed.
+	ComputeUnitLimit uint32
+	ComputeUnitPrice uint64
+	PriorityFee      uint64
+	// Rent is the rent-exempt balance of NewAccounts.
+	Rent        uint64
+	NewAccounts []NewAccountCost
+	// TransferFees are the transfer fees withheld from the transfers, by
+	// mint.
+	TransferFees map[solana.PublicKey]uint64
+}
+
+// Lamports returns the total lamports the transaction costs the fee payer:
+// the signature and priority fees and the rent of the new accounts.
+func (e *CostEstimate) Lamports() uint64 {
+	return e.SignatureFee + e.PriorityFee + e.Rent
+}
+
+// EstimateCost totals what sending instructions, such as
+// TransferPlan.Instructions, would cost before anything is signed: the
+// signature fees of feePayer and every other signer, the priority fee set
+// by ComputeBudget instructions, the rent of accounts created by System
+// CreateAccount and Associated Token Account instructions, and the
+// transfer fees of Token-2022 transfers at the current epoch.
+//
+// Associated token accounts that already exist are not counted, and the
+// rent of new ones is computed with DefaultRent from the extensions their
+// mint requires.
+func EstimateCost(ctx context.Context, client TransferClient, commitment rpc.CommitmentType, feePayer solana.PublicKey, instructions []solana.Instruction) (*CostEstimate, error) {
+	if feePayer.IsZero() {
+		return nil, errNotSet("fee payer")
+	}
+	e := &costEstimator{
+		client:     client,
+		commitment: commitment,
+		estimate:   &CostEstimate{TransferFees: map[solana.PublicKey]uint64{}},
+		mints:      map[solana.PublicKey]*Mint{},
+	}
+	signers := map[solana.PublicKey]bool{feePayer: true}
+	var computeUnitLimit *uint32
+	instructionCount := 0
+	for i, inst := range instructions {
+		data, err := inst.Data()
+		if err != nil {
+			return nil, fmt.Errorf("error while encoding instruction %d: %w", i, err)
+		}
+		accounts := inst.Accounts()
+		for _, account := range accounts {
+			if account.IsSigner {
+				signers[account.PublicKey] = true
+			}
+		}
+		program := inst.ProgramID()
+		if program.Equals(solana.ComputeBudget) {
+			switch {
+			case len(data) >= 5 && data[0] == computeBudgetSetComputeUnitLimit:
+				limit := binary.LittleEndian.Uint32(data[1:5])
+				computeUnitLimit = &limit
+			case len(data) >= 9 && data[0] == computeBudgetSetComputeUnitPrice:
+				e.estimate.ComputeUnitPrice = binary.LittleEndian.Uint64(data[1:9])
+			}
+			continue
+		}
+		instructionCount++
+		if err := e.add(ctx, program, accounts, data); err != nil {
+			return nil, fmt.Errorf("error while estimating instruction %d: %w", i, err)
+		}
+	}
+
+	estimate := e.estimate
+	estimate.Signatures = len(signers)
+	estimate.SignatureFee = uint64(len(signers)) * LamportsPerSignature
+	if computeUnitLimit != nil {
+		estimate.ComputeUnitLimit = *computeUnitLimit
+	} else {
+		estimate.ComputeUnitLimit = uint32(min(instructionCount*defaultComputeUnitsPerInstruction, maxComputeUnits))
+	}
+	fee, err := priorityFee(estimate.ComputeUnitPrice, estimate.ComputeUnitLimit)
+	if err != nil {
+		return nil, err
+	}
+	estimate.PriorityFee = fee
+	for _, account := range estimate.NewAccounts {
+		if estimate.Rent, err = CheckedAdd(estimate.Rent, account.Lamports); err != nil {
+			return nil, err
+		}
+	}
+	return estimate, nil
+}
+
+// priorityFee returns the lamports paid for limit compute units at price
+// micro-lamports each, rounded up.
+func priorityFee(price uint64, limit uint32) (uint64, error) {
+	fee := new(big.Int).SetUint64(price)
+	fee.Mul(fee, big.NewInt(int64(limit)))
+	fee.Add(fee, big.NewInt(999_999))
+	fee.Quo(fee, big.NewInt(1_000_000))
+	return AmountFromBig(fee)
+}
+
+type costEstimator struct {
+	client     TransferClient
+	commitment rpc.CommitmentType
+	estimate   *CostEstimate
+	mints      map[solana.PublicKey]*Mint
+	epoch      *uint64
+}
+
+func (e *costEstimator) add(ctx context.Context, program solana.PublicKey, accounts []*solana.AccountMeta, data []byte) error {
+	switch {
+	case program.Equals(solana.SystemProgramID):
+		// CreateAccount: u32 tag 0, lamports, space, owner.
+		if len(data) >= 20 && binary.LittleEndian.Uint32(data) == 0 && len(accounts) >= 2 {
+			e.estimate.NewAccounts = append(e.estimate.NewAccounts, NewAccountCost{
+				Address:  accounts[1].PublicKey,
+				Space:    int(binary.LittleEndian.Uint64(data[12:20])),
+				Lamports: binary.LittleEndian.Uint64(data[4:12]),
+			})
+		}
+	case program.Equals(ProgramID):
+		return e.addAssociatedAccount(ctx, accounts)
+	case program.Equals(solana.Token2022ProgramID):
+		decoded, err := DecodeInstruction(accounts, data)
+		if err != nil {
+			// Instructions without a builder move no tokens.
+			return nil
+		}
+		switch transfer := decoded.(type) {
+		case *TransferCheckedWithFee2022:
+			return e.addFee(transfer.Mint, transfer.Fee)
+		case *TransferChecked2022:
+			return e.addTransferFee(ctx, transfer.Mint, transfer.Amount)
+		}
+	}
+	return nil
+}
+
+func (e *costEstimator) addAssociatedAccount(ctx context.Context, accounts []*solana.AccountMeta) error {
+	create := new(Create2022)
+	if err := create.SetAccounts(accounts); err != nil {
+		return err
+	}
+	address := accounts[1].PublicKey
+	out, err := e.client.GetAccountInfoWithOpts(ctx, address, &rpc.GetAccountInfoOpts{Encoding: solana.EncodingBase64, Commitment: e.commitment})
+	if err != nil && !errors.Is(err, rpc.ErrNotFound) {
+		return fmt.Errorf("error while fetching account %s: %w", address, err)
+	}
+	if err == nil && out != nil && out.Value != nil {
+		return nil
+	}
+	space := AccountSize
+	if create.tokenProgram().Equals(solana.Token2022ProgramID) {
+		mint, err := e.mint(ctx, create.Mint)
+		if err != nil {
+			return err
+		}
+		if space, err = AccountSpace(RequiredAccountExtensions(mint)...); err != nil {
+			return err
+		}
+	}
+	e.estimate.NewAccounts = append(e.estimate.NewAccounts, NewAccountCost{
+		Address:  address,
+		Space:    space,
+		Lamports: RentExemptLamports(space),
+	})
+	return nil
+}
+
+func (e *costEstimator) addTransferFee(ctx context.Context, mintKey solana.PublicKey, amount uint64) error {
+	mint, err := e.mint(ctx, mintKey)
+	if err != nil {
+		return err
+	}
+	config, ok, err := mint.TransferFeeConfig()
+	if err != nil || !ok {
+		return err
+	}
+	if e.epoch == nil {
+		info, err := e.client.GetEpochInfo(ctx, e.commitment)
+		if err != nil {
+			return fmt.Errorf("error while fetching epoch: %w", err)
+		}
+		e.epoch = &info.Epoch
+	}
+	return e.addFee(mintKey, config.EffectiveFee(*e.epoch).Fee(amount))
+}
+
+func (e *costEstimator) addFee(mint solana.PublicKey, fee uint64) error {
+	total, err := CheckedAdd(e.estimate.TransferFees[mint], fee)
+	if err != nil {
+		return err
+	}
+	e.estimate.TransferFees[mint] = total
+	return nil
+}
+
+func (e *costEstimator) mint(ctx context.Context, key solana.PublicKey) (*Mint, error) {
+	if mint, ok := e.mints[key]; ok {
+		return mint, nil
+	}
+	mint, err := FetchMint(ctx, e.client, key, e.commitment)
+	if err != nil {
+		return nil, err
+	}
+	e.mints[key] = mint
+	return mint, nil
+}
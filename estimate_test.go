@@ -0,0 +1,74 @@
+package token2022
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	system "github.com/gagliardetto/solana-go/programs/system"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestEstimateCost(t *testing.T) {
+	var (
+		wallet    = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint      = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source    = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		recipient = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		newMint   = solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR")
+		client    = newMockRPC()
+	)
+	client.epoch = 12
+	client.setAccount(mint, solana.Token2022ProgramID, transferFeeMint(
+		TransferFee{BasisPoints: 10, MaximumFee: 1_000},
+		TransferFee{Epoch: 10, BasisPoints: 100, MaximumFee: 1_000_000},
+	))
+	destination, _, _ := FindAssociatedTokenAddress2022(recipient, mint)
+
+	limit := make([]byte, 5)
+	limit[0] = computeBudgetSetComputeUnitLimit
+	binary.LittleEndian.PutUint32(limit[1:], 300_000)
+	price := make([]byte, 9)
+	price[0] = computeBudgetSetComputeUnitPrice
+	binary.LittleEndian.PutUint64(price[1:], 10_000)
+
+	instructions := []solana.Instruction{
+		solana.NewInstruction(solana.ComputeBudget, nil, limit),
+		solana.NewInstruction(solana.ComputeBudget, nil, price),
+		system.NewCreateAccountInstruction(1_000_000, MintSize, solana.Token2022ProgramID, wallet, newMint).Build(),
+		NewCreate2022Instruction(wallet, recipient, mint).SetIdempotent(true).Build(),
+		NewTransferChecked2022Instruction(100_000, 6, source, mint, destination, wallet).Build(),
+	}
+	estimate, err := EstimateCost(context.Background(), client, rpc.CommitmentConfirmed, wallet, instructions)
+	if err != nil {
+		t.Fatalf("EstimateCost: %v", err)
+	}
+	if estimate.Signatures != 2 || estimate.SignatureFee != 10_000 {
+		t.Errorf("Expected 2 signatures for 10000 lamports, got %d for %d", estimate.Signatures, estimate.SignatureFee)
+	}
+	if estimate.ComputeUnitLimit != 300_000 || estimate.PriorityFee != 3_000 {
+		t.Errorf("Expected a priority fee of 3000 lamports for 300000 units, got %d for %d", estimate.PriorityFee, estimate.ComputeUnitLimit)
+	}
+	if len(estimate.NewAccounts) != 2 || estimate.NewAccounts[1].Address != destination || estimate.NewAccounts[1].Space != 182 {
+		t.Fatalf("Expected the mint and a 182-byte %s, got %+v", destination, estimate.NewAccounts)
+	}
+	if want := 1_000_000 + RentExemptLamports(182); estimate.Rent != want {
+		t.Errorf("Expected rent %d, got %d", want, estimate.Rent)
+	}
+	if fee := estimate.TransferFees[mint]; fee != 1_000 {
+		t.Errorf("Expected a transfer fee of 1000, got %d", fee)
+	}
+	if want := estimate.SignatureFee + estimate.PriorityFee + estimate.Rent; estimate.Lamports() != want {
+		t.Errorf("Expected %d lamports, got %d", want, estimate.Lamports())
+	}
+
+	client.setAccount(destination, solana.Token2022ProgramID, make([]byte, 182))
+	estimate, err = EstimateCost(context.Background(), client, rpc.CommitmentConfirmed, wallet, instructions[3:])
+	if err != nil {
+		t.Fatalf("EstimateCost: %v", err)
+	}
+	if len(estimate.NewAccounts) != 0 || estimate.ComputeUnitLimit != 400_000 || estimate.PriorityFee != 0 {
+		t.Errorf("Expected no new accounts and the default limit, got %+v", estimate)
+	}
+}
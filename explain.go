@@ -0,0 +1,208 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// MintInfo is what Explain needs to format the amounts of a mint.
+type MintInfo struct {
+	Decimals uint8
+	// Symbol is appended to amounts, such as "USDC". It may be empty.
+	Symbol string
+}
+
+// Explainer produces compact, human-readable summaries of instructions,
+// such as "TransferChecked 12.5 USDC from GHtX…D3Zi to 9xQe…VFin".
+// Amounts of mints it knows are formatted with their decimals and symbol.
+type Explainer struct {
+	mints map[solana.PublicKey]MintInfo
+}
+
+// NewExplainer creates an explainer that knows no mints.
+func NewExplainer() *Explainer {
+	return &Explainer{mints: map[solana.PublicKey]MintInfo{}}
+}
+
+// AddMint registers the decimals and symbol of a mint.
+func (e *Explainer) AddMint(mint solana.PublicKey, decimals uint8, symbol string) *Explainer {
+	e.mints[mint] = MintInfo{Decimals: decimals, Symbol: symbol}
+	return e
+}
+
+// AddMintAccount registers a decoded mint, taking the symbol from its
+// TokenMetadata extension when present.
+func (e *Explainer) AddMintAccount(mint solana.PublicKey, account *Mint) *Explainer {
+	info := MintInfo{Decimals: account.Decimals}
+	if metadata, ok, err := account.TokenMetadata(); ok && err == nil {
+		info.Symbol = metadata.Symbol
+	}
+	e.mints[mint] = info
+	return e
+}
+
+// Explain summarizes an instruction with an explainer that knows no mints.
+func Explain(instruction interface{}) string {
+	return NewExplainer().Explain(instruction)
+}
+
+// Explain summarizes instruction, which may be a builder, a built
+// *Instruction or a *ParsedInstruction.
+func (e *Explainer) Explain(instruction interface{}) string {
+	switch inst := instruction.(type) {
+	case *ParsedInstruction:
+		if inst.Instruction == nil {
+			return fmt.Sprintf("%s (%v)", inst.Name, inst.DecodeErr)
+		}
+		return e.Explain(inst.Instruction)
+	case *Instruction:
+		data, err := inst.Data()
+		if err != nil {
+			return fmt.Sprintf("invalid instruction: %v", err)
+		}
+		var decoded TypedInstruction
+		if inst.ProgramID().Equals(solana.SPLAssociatedTokenAccountProgramID) {
+			decoded, err = DecodeAssociatedTokenInstruction(inst.Accounts(), data)
+		} else {
+			decoded, err = DecodeInstruction(inst.Accounts(), data)
+		}
+		if err != nil {
+			return fmt.Sprintf("%s (%v)", InstructionName(data), err)
+		}
+		return e.Explain(decoded)
+	}
+
+	switch inst := instruction.(type) {
+	case *Transfer2022:
+		return fmt.Sprintf("Transfer %s from %s to %s", e.amount(inst.Amount, nil, nil), shortKey(inst.Source), shortKey(inst.Destination))
+	case *TransferChecked2022:
+		return fmt.Sprintf("TransferChecked %s from %s to %s", e.amount(inst.Amount, &inst.Mint, &inst.Decimals), shortKey(inst.Source), shortKey(inst.Destination))
+	case *TransferCheckedWithFee2022:
+		return fmt.Sprintf("TransferCheckedWithFee %s from %s to %s, fee %s",
+			e.amount(inst.Amount, &inst.Mint, &inst.Decimals), shortKey(inst.Source), shortKey(inst.Destination), e.amount(inst.Fee, &inst.Mint, &inst.Decimals))
+	case *MintTo2022:
+		return fmt.Sprintf("MintTo %s to %s", e.amount(inst.Amount, &inst.Mint, nil), shortKey(inst.Destination))
+	case *MintToChecked2022:
+		return fmt.Sprintf("MintToChecked %s to %s", e.amount(inst.Amount, &inst.Mint, &inst.Decimals), shortKey(inst.Destination))
+	case *Burn2022:
+		return fmt.Sprintf("Burn %s from %s", e.amount(inst.Amount, &inst.Mint, nil), shortKey(inst.Account))
+	case *BurnChecked2022:
+		return fmt.Sprintf("BurnChecked %s from %s", e.amount(inst.Amount, &inst.Mint, &inst.Decimals), shortKey(inst.Account))
+	case *Approve2022:
+		return fmt.Sprintf("Approve %s from %s to delegate %s", e.amount(inst.Amount, nil, nil), shortKey(inst.Source), shortKey(inst.Delegate))
+	case *ApproveChecked2022:
+		return fmt.Sprintf("ApproveChecked %s from %s to delegate %s", e.amount(inst.Amount, &inst.Mint, &inst.Decimals), shortKey(inst.Source), shortKey(inst.Delegate))
+	case *Revoke2022:
+		return fmt.Sprintf("Revoke delegate of %s", shortKey(inst.Source))
+	case *SetAuthority2022:
+		newAuthority := "none"
+		if inst.NewAuthority != nil {
+			newAuthority = shortKey(*inst.NewAuthority)
+		}
+		return fmt.Sprintf("SetAuthority %s of %s to %s", inst.AuthorityType, shortKey(inst.Account), newAuthority)
+	case *Close2022:
+		return fmt.Sprintf("CloseAccount %s, lamports to %s", shortKey(inst.Account), shortKey(inst.Destination))
+	case *FreezeAccount2022:
+		return fmt.Sprintf("FreezeAccount %s", shortKey(inst.Account))
+	case *ThawAccount2022:
+		return fmt.Sprintf("ThawAccount %s", shortKey(inst.Account))
+	case *InitializeMint2022:
+		return fmt.Sprintf("InitializeMint2 %s with %d decimals, mint authority %s", shortKey(inst.Mint), inst.Decimals, shortKey(inst.MintAuthority))
+	case *InitializeAccount2022:
+		return fmt.Sprintf("InitializeAccount3 %s for mint %s, owner %s", shortKey(inst.Account), e.mintName(inst.Mint), shortKey(inst.Owner))
+	case *InitializeMintLegacy2022:
+		return fmt.Sprintf("InitializeMint %s with %d decimals, mint authority %s", shortKey(inst.Mint), inst.Decimals, shortKey(inst.MintAuthority))
+	case *InitializeAccountLegacy2022:
+		return fmt.Sprintf("InitializeAccount %s for mint %s, owner %s", shortKey(inst.Account), e.mintName(inst.Mint), shortKey(inst.Owner))
+	case *InitializeAccount2Legacy2022:
+		return fmt.Sprintf("InitializeAccount2 %s for mint %s, owner %s", shortKey(inst.Account), e.mintName(inst.Mint), shortKey(inst.Owner))
+	case *SetTransferFee2022:
+		return fmt.Sprintf("SetTransferFee %s to %d bps, maximum %s",
+			e.mintName(inst.Mint), inst.TransferFeeBasisPoints, e.amount(inst.MaximumFee, &inst.Mint, nil))
+	case *HarvestWithheldTokensToMint2022:
+		return fmt.Sprintf("HarvestWithheldTokensToMint %s from %d accounts", e.mintName(inst.Mint), len(inst.Sources))
+	case *WithdrawWithheldTokensFromMint2022:
+		return fmt.Sprintf("WithdrawWithheldTokensFromMint %s to %s", e.mintName(inst.Mint), shortKey(inst.Destination))
+	case *WithdrawWithheldTokensFromAccounts2022:
+		return fmt.Sprintf("WithdrawWithheldTokensFromAccounts %s from %d accounts to %s", e.mintName(inst.Mint), len(inst.Sources), shortKey(inst.Destination))
+	case *Create2022:
+		name := "CreateAssociatedTokenAccount"
+		if inst.Idempotent {
+			name = "CreateAssociatedTokenAccountIdempotent"
+		}
+		return fmt.Sprintf("%s for wallet %s, mint %s", name, shortKey(inst.Wallet), e.mintName(inst.Mint))
+	}
+	return builderName(instruction)
+}
+
+// amount formats an amount with the mint's decimals and symbol when known.
+// decimals, when set, takes precedence over the registered decimals.
+func (e *Explainer) amount(amount uint64, mint *solana.PublicKey, decimals *uint8) string {
+	var info MintInfo
+	known := false
+	if mint != nil {
+		info, known = e.mints[*mint]
+	}
+	if decimals != nil {
+		info.Decimals = *decimals
+		known = true
+	}
+	if !known {
+		return fmt.Sprintf("%d", amount)
+	}
+	formatted := FormatAmount(amount, info.Decimals)
+	if info.Symbol != "" {
+		return formatted + " " + info.Symbol
+	}
+	if mint != nil {
+		return fmt.Sprintf("%s of %s", formatted, shortKey(*mint))
+	}
+	return formatted
+}
+
+// mintName returns the symbol of a known mint, or its shortened address.
+func (e *Explainer) mintName(mint solana.PublicKey) string {
+	if info, ok := e.mints[mint]; ok && info.Symbol != "" {
+		return info.Symbol
+	}
+	return shortKey(mint)
+}
+
+// shortKey abbreviates a public key to its first and last four characters.
+func shortKey(key solana.PublicKey) string {
+	s := key.String()
+	if len(s) <= 9 {
+		return s
+	}
+	return s[:4] + "…" + s[len(s)-4:]
+}
+
+// builderName returns the instruction name of a builder, such as
+// "InitializeTransferHook" for *InitializeTransferHook2022.
+func builderName(instruction interface{}) string {
+	t := reflect.TypeOf(instruction)
+	if t == nil {
+		return "<nil>"
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return strings.TrimSuffix(t.Name(), "2022")
+}
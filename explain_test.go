@@ -0,0 +1,61 @@
+package token2022
+
+import (
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+func TestExplain(t *testing.T) {
+
+	var (
+		owner       = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+	)
+
+	explainer := NewExplainer().AddMint(mint, 6, "USDC")
+	cases := []struct {
+		instruction interface{}
+		want        string
+	}{
+		{
+			NewTransferCheckedWithFee2022Instruction(12_500_000, 6, 125_000, source, mint, destination, owner),
+			"TransferCheckedWithFee 12.5 USDC from GHtX…D3Zi to 9xQe…VFin, fee 0.125 USDC",
+		},
+		{
+			NewTransferChecked2022Instruction(1_000_000, 6, source, mint, destination, owner).Build(),
+			"TransferChecked 1 USDC from GHtX…D3Zi to 9xQe…VFin",
+		},
+		{
+			NewTransfer2022Instruction(5, source, destination, owner),
+			"Transfer 5 from GHtX…D3Zi to 9xQe…VFin",
+		},
+		{
+			NewMintTo2022Instruction(250_000, mint, destination, owner),
+			"MintTo 0.25 USDC to 9xQe…VFin",
+		},
+		{
+			NewSetAuthority2022Instruction(AuthorityCloseMint, nil, mint, owner),
+			"SetAuthority CloseMint of D8zF…yrZn to none",
+		},
+		{
+			NewCreate2022Instruction(owner, owner, mint).SetIdempotent(true).Build(),
+			"CreateAssociatedTokenAccountIdempotent for wallet nrw1…bFun, mint USDC",
+		},
+		{
+			NewInitializeTransferHook2022InstructionBuilder(),
+			"InitializeTransferHook",
+		},
+	}
+	for _, c := range cases {
+		if got := explainer.Explain(c.instruction); got != c.want {
+			t.Errorf("got %q, want %q", got, c.want)
+		}
+	}
+
+	if got := Explain(NewTransferChecked2022Instruction(1_500, 3, source, mint, destination, owner)); got != "TransferChecked 1.5 of D8zF…yrZn from GHtX…D3Zi to 9xQe…VFin" {
+		t.Errorf("unexpected explanation without mint info %q", got)
+	}
+}
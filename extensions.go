@@ -0,0 +1,596 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+	"sync"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// extensionCache memoizes decoded extensions of a decoded account. The
+// TLV entries are only indexed when the account is decoded; an extension's
+// contents are decoded on first access. Copies of an account share the
+// cache, like they share the extension data.
+type extensionCache struct {
+	mu     sync.Mutex
+	values map[ExtensionType]cachedExtension
+}
+
+type cachedExtension struct {
+	value interface{}
+	err   error
+}
+
+func newExtensionCache() *extensionCache {
+	return &extensionCache{values: map[ExtensionType]cachedExtension{}}
+}
+
+// load returns the decoded extension of type t, decoding it with decode on
+// first access. A nil cache, as in an account built by hand, decodes on
+// every call.
+func (c *extensionCache) load(t ExtensionType, decode func() (interface{}, error)) (interface{}, error) {
+	if c == nil {
+		return decode()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, ok := c.values[t]; ok {
+		return cached.value, cached.err
+	}
+	value, err := decode()
+	c.values[t] = cachedExtension{value: value, err: err}
+	return value, err
+}
+
+// TransferFee is one epoch-scoped fee of a TransferFeeConfig.
+type TransferFee struct {
+	Epoch       uint64
+	MaximumFee  uint64
+	BasisPoints uint16
+}
+
+// Fee returns the fee withheld from a transfer of amount: amount times
+// BasisPoints, rounded up and capped at MaximumFee, as the program
+// computes it.
+func (f TransferFee) Fee(amount uint64) uint64 {
+	if f.BasisPoints == 0 || amount == 0 {
+		return 0
+	}
+	hi, lo := bits.Mul64(amount, uint64(f.BasisPoints))
+	fee, rem := bits.Div64(hi, lo, MaxFeeBasisPoints)
+	if rem > 0 {
+		fee++
+	}
+	return min(fee, f.MaximumFee)
+}
+
+// PreFeeAmount returns the amount to transfer for amount to arrive after
+// the fee, the inverse of Fee.
+func (f TransferFee) PreFeeAmount(amount uint64) (uint64, error) {
+	switch {
+	case f.BasisPoints == 0 || amount == 0:
+		return amount, nil
+	case f.BasisPoints >= MaxFeeBasisPoints:
+		return CheckedAdd(amount, f.MaximumFee)
+	}
+	hi, lo := bits.Mul64(amount, MaxFeeBasisPoints)
+	denominator := uint64(MaxFeeBasisPoints - f.BasisPoints)
+	if hi >= denominator {
+		// The uncapped amount overflows, so the fee is capped.
+		return CheckedAdd(amount, f.MaximumFee)
+	}
+	preFee, rem := bits.Div64(hi, lo, denominator)
+	if rem > 0 {
+		preFee++
+	}
+	if preFee-amount >= f.MaximumFee {
+		return CheckedAdd(amount, f.MaximumFee)
+	}
+	return preFee, nil
+}
+
+// TransferFeeConfig is the TransferFeeConfig mint extension. The newer fee
+// takes effect at its epoch; before that the older fee applies.
+type TransferFeeConfig struct {
+	// ConfigAuthority and WithdrawWithheldAuthority are nil when unset.
+	ConfigAuthority           *solana.PublicKey
+	WithdrawWithheldAuthority *solana.PublicKey
+	WithheldAmount            uint64
+	OlderTransferFee          TransferFee
+	NewerTransferFee          TransferFee
+}
+
+// MarshalJSON encodes the config with base58 authorities and u64 values
+// as strings.
+func (c TransferFeeConfig) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&c)
+}
+
+func (c *TransferFeeConfig) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, c)
+}
+
+// transferFeeConfigSize is the size of the TransferFeeConfig extension.
+const transferFeeConfigSize = 108
+
+// DecodeTransferFeeConfig decodes the TransferFeeConfig extension data.
+func DecodeTransferFeeConfig(data []byte) (*TransferFeeConfig, error) {
+	if len(data) != transferFeeConfigSize {
+		return nil, fmt.Errorf("%w: invalid transfer fee config length: %d bytes", ErrInvalidAccountData, len(data))
+	}
+	return &TransferFeeConfig{
+		ConfigAuthority:           decodeNonZeroPubkey(data[0:32]),
+		WithdrawWithheldAuthority: decodeNonZeroPubkey(data[32:64]),
+		WithheldAmount:            binary.LittleEndian.Uint64(data[64:72]),
+		OlderTransferFee:          decodeTransferFee(data[72:90]),
+		NewerTransferFee:          decodeTransferFee(data[90:108]),
+	}, nil
+}
+
+func decodeTransferFee(data []byte) TransferFee {
+	return TransferFee{
+		Epoch:       binary.LittleEndian.Uint64(data[0:8]),
+		MaximumFee:  binary.LittleEndian.Uint64(data[8:16]),
+		BasisPoints: binary.LittleEndian.Uint16(data[16:18]),
+	}
+}
+
+// decodeNonZeroPubkey decodes an OptionalNonZeroPubkey, where the zero key
+// means none.
+func decodeNonZeroPubkey(data []byte) *solana.PublicKey {
+	key := solana.PublicKeyFromBytes(data)
+	if key.IsZero() {
+		return nil
+	}
+	return &key
+}
+
+// EffectiveFee returns the fee in effect at epoch: the newer fee from its
+// epoch on, the older fee before, as the program selects it.
+func (c *TransferFeeConfig) EffectiveFee(epoch uint64) TransferFee {
+	if epoch >= c.NewerTransferFee.Epoch {
+		return c.NewerTransferFee
+	}
+	return c.OlderTransferFee
+}
+
+// EpochClient is the RPC call reading the current epoch. *rpc.Client
+// satisfies it.
+type EpochClient interface {
+	GetEpochInfo(ctx context.Context, commitment rpc.CommitmentType) (*rpc.GetEpochInfoResult, error)
+}
+
+var _ EpochClient = (*rpc.Client)(nil)
+
+// CurrentFee fetches the current epoch and returns the fee in effect at
+// it, with the epoch.
+func (c *TransferFeeConfig) CurrentFee(ctx context.Context, client EpochClient, commitment rpc.CommitmentType) (TransferFee, uint64, error) {
+	info, err := client.GetEpochInfo(ctx, commitment)
+	if err != nil {
+		return TransferFee{}, 0, fmt.Errorf("error while fetching epoch: %w", err)
+	}
+	return c.EffectiveFee(info.Epoch), info.Epoch, nil
+}
+
+// TransferFeeConfig returns the decoded TransferFeeConfig extension of the
+// mint.
+func (m *Mint) TransferFeeConfig() (*TransferFeeConfig, bool, error) {
+	data, ok := m.Extension(ExtensionTransferFeeConfig)
+	if !ok {
+		return nil, false, nil
+	}
+	value, err := m.cache.load(ExtensionTransferFeeConfig, func() (interface{}, error) {
+		return DecodeTransferFeeConfig(data)
+	})
+	if err != nil {
+		return nil, true, err
+	}
+	return value.(*TransferFeeConfig), true, nil
+}
+
+// WithheldAmount returns the transfer fees withheld in the account, from
+// its TransferFeeAmount extension.
+func (a *TokenAccount) WithheldAmount() (uint64, bool, error) {
+	data, ok := a.Extension(ExtensionTransferFeeAmount)
+	if !ok {
+		return 0, false, nil
+	}
+	if len(data) != 8 {
+		return 0, true, fmt.Errorf("%w: invalid transfer fee amount length: %d bytes", ErrInvalidAccountData, len(data))
+	}
+	return binary.LittleEndian.Uint64(data), true, nil
+}
+
+// InterestBearingConfig is the InterestBearingConfig mint extension. Rates
+// are in basis points per year and timestamps in Unix seconds.
+type InterestBearingConfig struct {
+	// RateAuthority is nil when unset.
+	RateAuthority           *solana.PublicKey
+	InitializationTimestamp int64
+	PreUpdateAverageRate    int16
+	LastUpdateTimestamp     int64
+	CurrentRate             int16
+}
+
+// MarshalJSON encodes the config with a base58 rate authority.
+func (c InterestBearingConfig) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&c)
+}
+
+func (c *InterestBearingConfig) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, c)
+}
+
+// interestBearingConfigSize is the size of the InterestBearingConfig
+// extension.
+const interestBearingConfigSize = 52
+
+// DecodeInterestBearingConfig decodes the InterestBearingConfig extension
+// data.
+func DecodeInterestBearingConfig(data []byte) (*InterestBearingConfig, error) {
+	if len(data) != interestBearingConfigSize {
+		return nil, fmt.Errorf("%w: invalid interest bearing config length: %d bytes", ErrInvalidAccountData, len(data))
+	}
+	return &InterestBearingConfig{
+		RateAuthority:           decodeNonZeroPubkey(data[0:32]),
+		InitializationTimestamp: int64(binary.LittleEndian.Uint64(data[32:40])),
+		PreUpdateAverageRate:    int16(binary.LittleEndian.Uint16(data[40:42])),
+		LastUpdateTimestamp:     int64(binary.LittleEndian.Uint64(data[42:50])),
+		CurrentRate:             int16(binary.LittleEndian.Uint16(data[50:52])),
+	}, nil
+}
+
+// InterestBearingConfig returns the decoded InterestBearingConfig
+// extension of the mint.
+func (m *Mint) InterestBearingConfig() (*InterestBearingConfig, bool, error) {
+	data, ok := m.Extension(ExtensionInterestBearingConfig)
+	if !ok {
+		return nil, false, nil
+	}
+	value, err := m.cache.load(ExtensionInterestBearingConfig, func() (interface{}, error) {
+		return DecodeInterestBearingConfig(data)
+	})
+	if err != nil {
+		return nil, true, err
+	}
+	return value.(*InterestBearingConfig), true, nil
+}
+
+// ScaledUiAmountConfig is the ScaledUiAmount mint extension. NewMultiplier
+// replaces Multiplier from NewMultiplierEffectiveTimestamp, in Unix
+// seconds.
+type ScaledUiAmountConfig struct {
+	// Authority is nil when unset.
+	Authority                       *solana.PublicKey
+	Multiplier                      float64
+	NewMultiplierEffectiveTimestamp int64
+	NewMultiplier                   float64
+}
+
+// MarshalJSON encodes the config with a base58 authority.
+func (c ScaledUiAmountConfig) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&c)
+}
+
+func (c *ScaledUiAmountConfig) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, c)
+}
+
+// scaledUiAmountConfigSize is the size of the ScaledUiAmount extension.
+const scaledUiAmountConfigSize = 56
+
+// DecodeScaledUiAmountConfig decodes the ScaledUiAmount extension data.
+func DecodeScaledUiAmountConfig(data []byte) (*ScaledUiAmountConfig, error) {
+	if len(data) != scaledUiAmountConfigSize {
+		return nil, fmt.Errorf("%w: invalid scaled UI amount config length: %d bytes", ErrInvalidAccountData, len(data))
+	}
+	return &ScaledUiAmountConfig{
+		Authority:                       decodeNonZeroPubkey(data[0:32]),
+		Multiplier:                      math.Float64frombits(binary.LittleEndian.Uint64(data[32:40])),
+		NewMultiplierEffectiveTimestamp: int64(binary.LittleEndian.Uint64(data[40:48])),
+		NewMultiplier:                   math.Float64frombits(binary.LittleEndian.Uint64(data[48:56])),
+	}, nil
+}
+
+// ScaledUiAmountConfig returns the decoded ScaledUiAmount extension of the
+// mint.
+func (m *Mint) ScaledUiAmountConfig() (*ScaledUiAmountConfig, bool, error) {
+	data, ok := m.Extension(ExtensionScaledUiAmount)
+	if !ok {
+		return nil, false, nil
+	}
+	value, err := m.cache.load(ExtensionScaledUiAmount, func() (interface{}, error) {
+		return DecodeScaledUiAmountConfig(data)
+	})
+	if err != nil {
+		return nil, true, err
+	}
+	return value.(*ScaledUiAmountConfig), true, nil
+}
+
+// TransferHookConfig is the TransferHook mint extension. Transfers invoke
+// ProgramID, when set, with the accounts listed in its ExtraAccountMetaList.
+type TransferHookConfig struct {
+	// Authority and ProgramID are nil when unset.
+	Authority *solana.PublicKey
+	ProgramID *solana.PublicKey
+}
+
+// MarshalJSON encodes the config with base58 keys.
+func (c TransferHookConfig) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&c)
+}
+
+func (c *TransferHookConfig) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, c)
+}
+
+// transferHookConfigSize is the size of the TransferHook extension.
+const transferHookConfigSize = 64
+
+// DecodeTransferHookConfig decodes the TransferHook extension data.
+func DecodeTransferHookConfig(data []byte) (*TransferHookConfig, error) {
+	if len(data) != transferHookConfigSize {
+		return nil, fmt.Errorf("%w: invalid transfer hook length: %d bytes", ErrInvalidAccountData, len(data))
+	}
+	return &TransferHookConfig{
+		Authority: decodeNonZeroPubkey(data[0:32]),
+		ProgramID: decodeNonZeroPubkey(data[32:64]),
+	}, nil
+}
+
+// TransferHookConfig returns the decoded TransferHook extension of the
+// mint.
+func (m *Mint) TransferHookConfig() (*TransferHookConfig, bool, error) {
+	data, ok := m.Extension(ExtensionTransferHook)
+	if !ok {
+		return nil, false, nil
+	}
+	value, err := m.cache.load(ExtensionTransferHook, func() (interface{}, error) {
+		return DecodeTransferHookConfig(data)
+	})
+	if err != nil {
+		return nil, true, err
+	}
+	return value.(*TransferHookConfig), true, nil
+}
+
+// RequiresIncomingTransferMemos reports whether the account's MemoTransfer
+// extension requires incoming transfers to be preceded by a memo.
+func (a *TokenAccount) RequiresIncomingTransferMemos() (bool, error) {
+	data, ok := a.Extension(ExtensionMemoTransfer)
+	if !ok {
+		return false, nil
+	}
+	if len(data) != 1 {
+		return false, fmt.Errorf("%w: invalid memo transfer length: %d bytes", ErrInvalidAccountData, len(data))
+	}
+	return data[0] != 0, nil
+}
+
+// CpiGuardEnabled reports whether the account's CpiGuard extension locks
+// privileged operations invoked through CPI.
+func (a *TokenAccount) CpiGuardEnabled() (bool, error) {
+	data, ok := a.Extension(ExtensionCpiGuard)
+	if !ok {
+		return false, nil
+	}
+	if len(data) != 1 {
+		return false, fmt.Errorf("%w: invalid CPI guard length: %d bytes", ErrInvalidAccountData, len(data))
+	}
+	return data[0] != 0, nil
+}
+
+// DefaultAccountState returns the state new accounts of the mint start in,
+// from its DefaultAccountState extension.
+func (m *Mint) DefaultAccountState() (AccountState, bool, error) {
+	data, ok := m.Extension(ExtensionDefaultAccountState)
+	if !ok {
+		return AccountStateInitialized, false, nil
+	}
+	if len(data) != 1 {
+		return AccountStateInitialized, true, fmt.Errorf("%w: invalid default account state length: %d bytes", ErrInvalidAccountData, len(data))
+	}
+	return AccountState(data[0]), true, nil
+}
+
+// PermanentDelegate returns the permanent delegate of the mint, from its
+// PermanentDelegate extension. The delegate is nil when unset.
+func (m *Mint) PermanentDelegate() (*solana.PublicKey, bool, error) {
+	data, ok := m.Extension(ExtensionPermanentDelegate)
+	if !ok {
+		return nil, false, nil
+	}
+	if len(data) != 32 {
+		return nil, true, fmt.Errorf("%w: invalid permanent delegate length: %d bytes", ErrInvalidAccountData, len(data))
+	}
+	return decodeNonZeroPubkey(data), true, nil
+}
+
+// MintCloseAuthority returns the close authority of the mint, from its
+// MintCloseAuthority extension. The authority is nil when unset.
+func (m *Mint) MintCloseAuthority() (*solana.PublicKey, bool, error) {
+	data, ok := m.Extension(ExtensionMintCloseAuthority)
+	if !ok {
+		return nil, false, nil
+	}
+	if len(data) != 32 {
+		return nil, true, fmt.Errorf("%w: invalid mint close authority length: %d bytes", ErrInvalidAccountData, len(data))
+	}
+	return decodeNonZeroPubkey(data), true, nil
+}
+
+// Pointer is the layout of the MetadataPointer, GroupPointer and
+// GroupMemberPointer mint extensions: the authority that may update the
+// pointer and the account it points to.
+type Pointer struct {
+	// Authority and Address are nil when unset.
+	Authority *solana.PublicKey
+	Address   *solana.PublicKey
+}
+
+// MarshalJSON encodes the pointer with base58 keys.
+func (p Pointer) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&p)
+}
+
+func (p *Pointer) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, p)
+}
+
+// DecodePointer decodes MetadataPointer, GroupPointer or
+// GroupMemberPointer extension data.
+func DecodePointer(data []byte) (*Pointer, error) {
+	if len(data) != 64 {
+		return nil, fmt.Errorf("%w: invalid pointer length: %d bytes", ErrInvalidAccountData, len(data))
+	}
+	return &Pointer{
+		Authority: decodeNonZeroPubkey(data[0:32]),
+		Address:   decodeNonZeroPubkey(data[32:64]),
+	}, nil
+}
+
+// MetadataPointer returns the decoded MetadataPointer extension of the
+// mint.
+func (m *Mint) MetadataPointer() (*Pointer, bool, error) {
+	return m.pointer(ExtensionMetadataPointer)
+}
+
+func (m *Mint) pointer(t ExtensionType) (*Pointer, bool, error) {
+	data, ok := m.Extension(t)
+	if !ok {
+		return nil, false, nil
+	}
+	value, err := m.cache.load(t, func() (interface{}, error) {
+		return DecodePointer(data)
+	})
+	if err != nil {
+		return nil, true, err
+	}
+	return value.(*Pointer), true, nil
+}
+
+// GroupPointer returns the decoded GroupPointer extension of the mint.
+func (m *Mint) GroupPointer() (*Pointer, bool, error) {
+	return m.pointer(ExtensionGroupPointer)
+}
+
+// GroupMemberPointer returns the decoded GroupMemberPointer extension of
+// the mint.
+func (m *Mint) GroupMemberPointer() (*Pointer, bool, error) {
+	return m.pointer(ExtensionGroupMemberPointer)
+}
+
+// TokenGroup is the token-group interface layout of a group, stored in
+// the TokenGroup mint extension.
+type TokenGroup struct {
+	// UpdateAuthority is nil when the group is immutable.
+	UpdateAuthority *solana.PublicKey
+	Mint            solana.PublicKey
+	Size            uint64
+	MaxSize         uint64
+}
+
+// MarshalJSON encodes the group with base58 keys and u64 values as
+// strings.
+func (g TokenGroup) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&g)
+}
+
+func (g *TokenGroup) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, g)
+}
+
+// DecodeTokenGroup decodes TokenGroup data.
+func DecodeTokenGroup(data []byte) (*TokenGroup, error) {
+	if len(data) != 80 {
+		return nil, fmt.Errorf("%w: invalid token group length: %d bytes", ErrInvalidAccountData, len(data))
+	}
+	return &TokenGroup{
+		UpdateAuthority: decodeNonZeroPubkey(data[0:32]),
+		Mint:            solana.PublicKeyFromBytes(data[32:64]),
+		Size:            binary.LittleEndian.Uint64(data[64:72]),
+		MaxSize:         binary.LittleEndian.Uint64(data[72:80]),
+	}, nil
+}
+
+// TokenGroup returns the decoded TokenGroup extension of the mint.
+func (m *Mint) TokenGroup() (*TokenGroup, bool, error) {
+	data, ok := m.Extension(ExtensionTokenGroup)
+	if !ok {
+		return nil, false, nil
+	}
+	value, err := m.cache.load(ExtensionTokenGroup, func() (interface{}, error) {
+		return DecodeTokenGroup(data)
+	})
+	if err != nil {
+		return nil, true, err
+	}
+	return value.(*TokenGroup), true, nil
+}
+
+// TokenGroupMember is the token-group interface layout of a group member,
+// stored in the TokenGroupMember mint extension. Group is the address of
+// the group's TokenGroup state.
+type TokenGroupMember struct {
+	Mint         solana.PublicKey
+	Group        solana.PublicKey
+	MemberNumber uint64
+}
+
+// MarshalJSON encodes the member with base58 keys and u64 values as
+// strings.
+func (g TokenGroupMember) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&g)
+}
+
+func (g *TokenGroupMember) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, g)
+}
+
+// DecodeTokenGroupMember decodes TokenGroupMember data.
+func DecodeTokenGroupMember(data []byte) (*TokenGroupMember, error) {
+	if len(data) != 72 {
+		return nil, fmt.Errorf("%w: invalid token group member length: %d bytes", ErrInvalidAccountData, len(data))
+	}
+	return &TokenGroupMember{
+		Mint:         solana.PublicKeyFromBytes(data[0:32]),
+		Group:        solana.PublicKeyFromBytes(data[32:64]),
+		MemberNumber: binary.LittleEndian.Uint64(data[64:72]),
+	}, nil
+}
+
+// TokenGroupMember returns the decoded TokenGroupMember extension of the
+// mint.
+func (m *Mint) TokenGroupMember() (*TokenGroupMember, bool, error) {
+	data, ok := m.Extension(ExtensionTokenGroupMember)
+	if !ok {
+		return nil, false, nil
+	}
+	value, err := m.cache.load(ExtensionTokenGroupMember, func() (interface{}, error) {
+		return DecodeTokenGroupMember(data)
+	})
+	if err != nil {
+		return nil, true, err
+	}
+	return value.(*TokenGroupMember), true, nil
+}
@@ -0,0 +1,175 @@
+package token2022
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"math"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestMintTransferFeeConfig(t *testing.T) {
+
+	var (
+		configAuthority   = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		withdrawAuthority = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+	)
+
+	var vectors []goldenAccount
+	loadGolden(t, "accounts.json", &vectors)
+	var data []byte
+	for _, vector := range vectors {
+		if vector.Name == "Mint with extensions" {
+			data, _ = hex.DecodeString(vector.Data)
+		}
+	}
+	mint, err := DecodeMint(data)
+	if err != nil {
+		t.Fatalf("DecodeMint: %v", err)
+	}
+
+	config, ok, err := mint.TransferFeeConfig()
+	if err != nil || !ok {
+		t.Fatalf("Expected transfer fee config, got %v, %v", ok, err)
+	}
+	if !config.ConfigAuthority.Equals(configAuthority) || !config.WithdrawWithheldAuthority.Equals(withdrawAuthority) {
+		t.Errorf("Unexpected authorities %s, %s", config.ConfigAuthority, config.WithdrawWithheldAuthority)
+	}
+	if config.WithheldAmount != 42 {
+		t.Errorf("Expected withheld amount 42, got %d", config.WithheldAmount)
+	}
+	if want := (TransferFee{Epoch: 100, MaximumFee: 5000, BasisPoints: 25}); config.OlderTransferFee != want {
+		t.Errorf("Expected older fee %+v, got %+v", want, config.OlderTransferFee)
+	}
+	if want := (TransferFee{Epoch: 101, MaximumFee: 10000, BasisPoints: 50}); config.NewerTransferFee != want {
+		t.Errorf("Expected newer fee %+v, got %+v", want, config.NewerTransferFee)
+	}
+
+	again, _, _ := mint.TransferFeeConfig()
+	if again != config {
+		t.Errorf("Expected the decoded extension to be cached")
+	}
+	metadata, _, _ := mint.TokenMetadata()
+	if again, _, _ := mint.TokenMetadata(); again != metadata {
+		t.Errorf("Expected the decoded token metadata to be cached")
+	}
+
+	plain, err := DecodeMint(encodeMint(nil, 1, 6))
+	if err != nil {
+		t.Fatalf("DecodeMint: %v", err)
+	}
+	if _, ok, err := plain.TransferFeeConfig(); ok || err != nil {
+		t.Errorf("Expected no transfer fee config, got %v, %v", ok, err)
+	}
+}
+
+func TestDecodeMintSkipsExtensionContents(t *testing.T) {
+	// Truncated metadata only fails when it is accessed.
+	data := encodeMint(nil, 1, 6, Extension{Type: ExtensionTokenMetadata, Data: bytes.Repeat([]byte{1}, 70)})
+	mint, err := DecodeMint(data)
+	if err != nil {
+		t.Fatalf("DecodeMint: %v", err)
+	}
+	if mint.Decimals != 6 {
+		t.Errorf("Expected 6 decimals, got %d", mint.Decimals)
+	}
+	if _, ok, err := mint.TokenMetadata(); !ok || err == nil {
+		t.Errorf("Expected error decoding truncated metadata, got %v, %v", ok, err)
+	}
+}
+
+func BenchmarkDecodeMintLargeMetadata(b *testing.B) {
+	data := encodeMint(nil, 1, 6, Extension{Type: ExtensionTokenMetadata, Data: make([]byte, 8000)})
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		mint, err := DecodeMint(data)
+		if err != nil || mint.Decimals != 6 {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestTokenAccountWithheldAmount(t *testing.T) {
+	account := &TokenAccount{Extensions: []Extension{{Type: ExtensionTransferFeeAmount, Data: []byte{0x39, 0x30, 0, 0, 0, 0, 0, 0}}}}
+	amount, ok, err := account.WithheldAmount()
+	if err != nil || !ok || amount != 12345 {
+		t.Errorf("Expected 12345, got %d, %v, %v", amount, ok, err)
+	}
+
+	if _, ok, err := (&TokenAccount{}).WithheldAmount(); ok || err != nil {
+		t.Errorf("Expected no extension, got %v, %v", ok, err)
+	}
+	account.Extensions[0].Data = account.Extensions[0].Data[:4]
+	if _, _, err := account.WithheldAmount(); err == nil {
+		t.Errorf("Expected an error for truncated data")
+	}
+}
+
+func TestTransferFee(t *testing.T) {
+	fee := TransferFee{BasisPoints: 50, MaximumFee: 5_000}
+	tests := []struct {
+		amount, fee uint64
+	}{
+		{0, 0},
+		{1, 1},
+		{200, 1},
+		{201, 2},
+		{1_000_000, 5_000},
+		{math.MaxUint64, 5_000},
+	}
+	for _, tt := range tests {
+		if got := fee.Fee(tt.amount); got != tt.fee {
+			t.Errorf("Fee(%d): expected %d, got %d", tt.amount, tt.fee, got)
+		}
+	}
+
+	for _, amount := range []uint64{1, 199, 200, 10_000, 999_000, 1_000_000} {
+		preFee, err := fee.PreFeeAmount(amount)
+		if err != nil {
+			t.Fatalf("PreFeeAmount(%d): %v", amount, err)
+		}
+		if got := preFee - fee.Fee(preFee); got != amount {
+			t.Errorf("PreFeeAmount(%d) = %d arrives as %d", amount, preFee, got)
+		}
+	}
+	if _, err := fee.PreFeeAmount(math.MaxUint64); err == nil {
+		t.Errorf("Expected overflow error")
+	}
+	if preFee, _ := (TransferFee{BasisPoints: MaxFeeBasisPoints, MaximumFee: 7}).PreFeeAmount(10); preFee != 17 {
+		t.Errorf("Expected 17 at 100%%, got %d", preFee)
+	}
+}
+
+func TestEffectiveFee(t *testing.T) {
+	config := &TransferFeeConfig{
+		OlderTransferFee: TransferFee{Epoch: 3, BasisPoints: 10, MaximumFee: 100},
+		NewerTransferFee: TransferFee{Epoch: 8, BasisPoints: 50, MaximumFee: 500},
+	}
+	for _, c := range []struct {
+		epoch uint64
+		want  uint16
+	}{{0, 10}, {7, 10}, {8, 50}, {100, 50}} {
+		if got := config.EffectiveFee(c.epoch).BasisPoints; got != c.want {
+			t.Errorf("EffectiveFee(%d): expected %d basis points, got %d", c.epoch, c.want, got)
+		}
+	}
+
+	client := newMockRPC()
+	client.epoch = 8
+	fee, epoch, err := config.CurrentFee(context.Background(), client, rpc.CommitmentConfirmed)
+	if err != nil {
+		t.Fatalf("CurrentFee: %v", err)
+	}
+	if epoch != 8 || fee != config.NewerTransferFee {
+		t.Errorf("Expected the newer fee at epoch 8, got %+v at epoch %d", fee, epoch)
+	}
+	client.err = errors.New("unavailable")
+	if _, _, err := config.CurrentFee(context.Background(), client, rpc.CommitmentConfirmed); err == nil {
+		t.Error("Expected an error when the epoch is unavailable")
+	}
+}
@@ -0,0 +1,251 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+// ErrNoHealthyEndpoint is returned by FailoverClient when every endpoint
+// failed the request.
+var ErrNoHealthyEndpoint = errors.New("no healthy RPC endpoint")
+
+// FailoverClient spreads requests round-robin over several RPC endpoints.
+// An endpoint that fails with a transport, HTTP or node-health error is
+// taken out of rotation for a cooldown period and the request is retried
+// on the next endpoint. Application errors, such as a failed preflight
+// simulation or a missing account, are returned as-is.
+//
+// FailoverClient is safe for concurrent use.
+type FailoverClient struct {
+	endpoints []*failoverEndpoint
+	next      atomic.Uint32
+	cooldown  time.Duration
+	logger    Logger
+}
+
+type failoverEndpoint struct {
+	client RPCClient
+	index  int
+
+	mu             sync.Mutex
+	unhealthyUntil time.Time
+}
+
+// NewFailoverClient creates a client over the given endpoints with a
+// ten-second cooldown for failed endpoints.
+func NewFailoverClient(clients ...RPCClient) *FailoverClient {
+	c := &FailoverClient{cooldown: 10 * time.Second}
+	for i, client := range clients {
+		c.endpoints = append(c.endpoints, &failoverEndpoint{client: client, index: i})
+	}
+	return c
+}
+
+// NewFailoverClientFromURLs creates a FailoverClient with an *rpc.Client
+// for each URL.
+func NewFailoverClientFromURLs(urls ...string) *FailoverClient {
+	clients := make([]RPCClient, len(urls))
+	for i, url := range urls {
+		clients[i] = rpc.New(url)
+	}
+	return NewFailoverClient(clients...)
+}
+
+func (c *FailoverClient) SetCooldown(cooldown time.Duration) *FailoverClient {
+	c.cooldown = cooldown
+	return c
+}
+
+// SetLogger logs every endpoint taken out of rotation by a failed request
+// at warning level.
+func (c *FailoverClient) SetLogger(logger Logger) *FailoverClient {
+	c.logger = logger
+	return c
+}
+
+// Healthy returns the number of endpoints currently in rotation.
+func (c *FailoverClient) Healthy() int {
+	now := time.Now()
+	healthy := 0
+	for _, e := range c.endpoints {
+		if e.healthy(now) {
+			healthy++
+		}
+	}
+	return healthy
+}
+
+// StartHealthChecks probes every endpoint with getBlockHeight at the given
+// interval until ctx is done. Endpoints that answer are put back into
+// rotation; endpoints that fail are taken out.
+func (c *FailoverClient) StartHealthChecks(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			c.CheckHealth(ctx)
+		}
+	}()
+}
+
+// CheckHealth probes every endpoint once and updates its health.
+func (c *FailoverClient) CheckHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, e := range c.endpoints {
+		wg.Add(1)
+		go func(e *failoverEndpoint) {
+			defer wg.Done()
+			_, err := e.client.GetBlockHeight(ctx, rpc.CommitmentProcessed)
+			if err != nil && ctx.Err() == nil {
+				e.markUnhealthy(c.cooldown)
+				return
+			}
+			if err == nil {
+				e.markHealthy()
+			}
+		}(e)
+	}
+	wg.Wait()
+}
+
+func (c *FailoverClient) GetLatestBlockhash(ctx context.Context, commitment rpc.CommitmentType) (out *rpc.GetLatestBlockhashResult, err error) {
+	err = c.do(ctx, func(client RPCClient) (err error) {
+		out, err = client.GetLatestBlockhash(ctx, commitment)
+		return err
+	})
+	return out, err
+}
+
+func (c *FailoverClient) GetBlockHeight(ctx context.Context, commitment rpc.CommitmentType) (out uint64, err error) {
+	err = c.do(ctx, func(client RPCClient) (err error) {
+		out, err = client.GetBlockHeight(ctx, commitment)
+		return err
+	})
+	return out, err
+}
+
+func (c *FailoverClient) GetAccountInfoWithOpts(ctx context.Context, account solana.PublicKey, opts *rpc.GetAccountInfoOpts) (out *rpc.GetAccountInfoResult, err error) {
+	err = c.do(ctx, func(client RPCClient) (err error) {
+		out, err = client.GetAccountInfoWithOpts(ctx, account, opts)
+		return err
+	})
+	return out, err
+}
+
+// SendTransactionWithOpts submits the transaction through the first
+// endpoint that accepts it. Resubmitting a signed transaction to another
+// endpoint is safe: the cluster executes a signature at most once.
+func (c *FailoverClient) SendTransactionWithOpts(ctx context.Context, transaction *solana.Transaction, opts rpc.TransactionOpts) (out solana.Signature, err error) {
+	err = c.do(ctx, func(client RPCClient) (err error) {
+		out, err = client.SendTransactionWithOpts(ctx, transaction, opts)
+		return err
+	})
+	return out, err
+}
+
+func (c *FailoverClient) GetSignatureStatuses(ctx context.Context, searchTransactionHistory bool, transactionSignatures ...solana.Signature) (out *rpc.GetSignatureStatusesResult, err error) {
+	err = c.do(ctx, func(client RPCClient) (err error) {
+		out, err = client.GetSignatureStatuses(ctx, searchTransactionHistory, transactionSignatures...)
+		return err
+	})
+	return out, err
+}
+
+// do runs call against healthy endpoints in round-robin order and falls
+// back to unhealthy ones when no healthy endpoint is left.
+func (c *FailoverClient) do(ctx context.Context, call func(RPCClient) error) error {
+	if len(c.endpoints) == 0 {
+		return ErrNoHealthyEndpoint
+	}
+	now := time.Now()
+	start := int(c.next.Add(1)-1) % len(c.endpoints)
+
+	order := make([]*failoverEndpoint, 0, len(c.endpoints))
+	var unhealthy []*failoverEndpoint
+	for i := range c.endpoints {
+		e := c.endpoints[(start+i)%len(c.endpoints)]
+		if e.healthy(now) {
+			order = append(order, e)
+		} else {
+			unhealthy = append(unhealthy, e)
+		}
+	}
+	order = append(order, unhealthy...)
+
+	var lastErr error
+	for _, e := range order {
+		err := call(e.client)
+		if err == nil {
+			e.markHealthy()
+			return nil
+		}
+		if ctx.Err() != nil || !isEndpointError(err) {
+			return err
+		}
+		e.markUnhealthy(c.cooldown)
+		logEvent(ctx, c.logger, slog.LevelWarn, "endpoint failed", "endpoint", e.index, "cooldown", c.cooldown, "error", err)
+		lastErr = err
+	}
+	return fmt.Errorf("%w: %w", ErrNoHealthyEndpoint, lastErr)
+}
+
+// isEndpointError reports whether err is caused by the endpoint rather
+// than by the request, so that retrying elsewhere may succeed.
+func isEndpointError(err error) bool {
+	if errors.Is(err, rpc.ErrNotFound) {
+		return false
+	}
+	var rpcErr *jsonrpc.RPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr.Code == rpcNodeUnhealthy
+	}
+	return true
+}
+
+func (e *failoverEndpoint) healthy(now time.Time) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return !now.Before(e.unhealthyUntil)
+}
+
+func (e *failoverEndpoint) markHealthy() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.unhealthyUntil = time.Time{}
+}
+
+func (e *failoverEndpoint) markUnhealthy(cooldown time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.unhealthyUntil = time.Now().Add(cooldown)
+}
+
+var _ RPCClient = (*FailoverClient)(nil)
@@ -0,0 +1,102 @@
+package token2022
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+func TestFailoverClientFailsOver(t *testing.T) {
+	ctx := context.Background()
+	down := newMockRPC()
+	down.err = errors.New("connection refused")
+	up := newMockRPC()
+	up.blockHeight = 900
+
+	client := NewFailoverClient(down, up)
+	for i := 0; i < 4; i++ {
+		height, err := client.GetBlockHeight(ctx, rpc.CommitmentConfirmed)
+		if err != nil {
+			t.Fatalf("GetBlockHeight: %v", err)
+		}
+		if height != 900 {
+			t.Fatalf("expected height 900, got %d", height)
+		}
+	}
+	if down.calls["getBlockHeight"] != 1 {
+		t.Errorf("expected the failed endpoint to be tried once, got %d", down.calls["getBlockHeight"])
+	}
+	if client.Healthy() != 1 {
+		t.Errorf("expected 1 healthy endpoint, got %d", client.Healthy())
+	}
+}
+
+func TestFailoverClientRoundRobin(t *testing.T) {
+	ctx := context.Background()
+	a, b := newMockRPC(), newMockRPC()
+	client := NewFailoverClient(a, b)
+	for i := 0; i < 4; i++ {
+		if _, err := client.GetLatestBlockhash(ctx, rpc.CommitmentConfirmed); err != nil {
+			t.Fatalf("GetLatestBlockhash: %v", err)
+		}
+	}
+	if a.calls["getLatestBlockhash"] != 2 || b.calls["getLatestBlockhash"] != 2 {
+		t.Errorf("expected requests to be spread evenly, got %d and %d", a.calls["getLatestBlockhash"], b.calls["getLatestBlockhash"])
+	}
+}
+
+func TestFailoverClientApplicationErrors(t *testing.T) {
+	ctx := context.Background()
+	a, b := newMockRPC(), newMockRPC()
+	client := NewFailoverClient(a, b)
+
+	_, err := client.GetAccountInfoWithOpts(ctx, solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun"), nil)
+	if !errors.Is(err, rpc.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if a.calls["getAccountInfo"]+b.calls["getAccountInfo"] != 1 {
+		t.Errorf("expected a missing account not to be retried")
+	}
+
+	a.err = &jsonrpc.RPCError{Code: -32002, Message: "Transaction simulation failed"}
+	b.err = a.err
+	client = NewFailoverClient(a, b)
+	if _, err := client.GetBlockHeight(ctx, rpc.CommitmentConfirmed); err != a.err {
+		t.Fatalf("expected the RPC error to be returned as-is, got %v", err)
+	}
+	if client.Healthy() != 2 {
+		t.Errorf("expected application errors not to affect health")
+	}
+}
+
+func TestFailoverClientAllDown(t *testing.T) {
+	ctx := context.Background()
+	a, b := newMockRPC(), newMockRPC()
+	a.err = errors.New("timeout")
+	b.err = &jsonrpc.RPCError{Code: -32005, Message: "Node is unhealthy"}
+	client := NewFailoverClient(a, b).SetCooldown(time.Hour)
+
+	if _, err := client.GetBlockHeight(ctx, rpc.CommitmentConfirmed); !errors.Is(err, ErrNoHealthyEndpoint) {
+		t.Fatalf("expected ErrNoHealthyEndpoint, got %v", err)
+	}
+	if client.Healthy() != 0 {
+		t.Fatalf("expected no healthy endpoints, got %d", client.Healthy())
+	}
+
+	// Unhealthy endpoints are still tried as a last resort, and health
+	// checks put recovered endpoints back into rotation.
+	a.err = nil
+	if _, err := client.GetBlockHeight(ctx, rpc.CommitmentConfirmed); err != nil {
+		t.Fatalf("expected recovered endpoint to be used, got %v", err)
+	}
+	b.err = nil
+	client.CheckHealth(ctx)
+	if client.Healthy() != 2 {
+		t.Errorf("expected 2 healthy endpoints after health check, got %d", client.Healthy())
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"github.com/dwmfan/token2022/core"
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// Sizes in bytes of the instruction data written by the Append*Data
+// functions.
+const (
+	TransferDataSize        = core.TransferDataSize
+	TransferCheckedDataSize = core.TransferCheckedDataSize
+	MintToDataSize          = core.MintToDataSize
+	MintToCheckedDataSize   = core.MintToCheckedDataSize
+)
+
+// dataAppender is implemented by builders with a fixed-size encoding that
+// can be written without the reflection-based bin encoder.
+type dataAppender interface {
+	appendData(dst []byte) []byte
+}
+
+// AppendTransferData appends the data of a Transfer instruction to dst.
+// It does not allocate when dst has TransferDataSize bytes of capacity
+// left.
+func AppendTransferData(dst []byte, amount uint64) []byte {
+	return core.AppendTransferData(dst, amount)
+}
+
+// AppendTransferCheckedData appends the data of a TransferChecked
+// instruction to dst.
+func AppendTransferCheckedData(dst []byte, amount uint64, decimals uint8) []byte {
+	return core.AppendTransferCheckedData(dst, amount, decimals)
+}
+
+// AppendMintToData appends the data of a MintTo instruction to dst.
+func AppendMintToData(dst []byte, amount uint64) []byte {
+	return core.AppendMintToData(dst, amount)
+}
+
+// AppendMintToCheckedData appends the data of a MintToChecked instruction
+// to dst.
+func AppendMintToCheckedData(dst []byte, amount uint64, decimals uint8) []byte {
+	return core.AppendMintToCheckedData(dst, amount, decimals)
+}
+
+func (inst Transfer2022) appendData(dst []byte) []byte {
+	return AppendTransferData(dst, inst.Amount)
+}
+
+func (inst TransferChecked2022) appendData(dst []byte) []byte {
+	return AppendTransferCheckedData(dst, inst.Amount, inst.Decimals)
+}
+
+func (inst MintTo2022) appendData(dst []byte) []byte {
+	return AppendMintToData(dst, inst.Amount)
+}
+
+func (inst MintToChecked2022) appendData(dst []byte) []byte {
+	return AppendMintToCheckedData(dst, inst.Amount, inst.Decimals)
+}
+
+// AppendData appends the serialized instruction data to dst. Transfer,
+// TransferChecked, MintTo and MintToChecked are written directly and do
+// not allocate when dst has enough capacity; other instructions fall back
+// to Data.
+func (inst *Instruction) AppendData(dst []byte) ([]byte, error) {
+	if appender, ok := inst.Impl.(dataAppender); ok {
+		return appender.appendData(dst), nil
+	}
+	data, err := inst.Data()
+	if err != nil {
+		return dst, err
+	}
+	return append(dst, data...), nil
+}
+
+// findAssociatedTokenAddress derives the associated token address of
+// wallet for mint under tokenProgram like solana.FindProgramAddress,
+// without allocating.
+func findAssociatedTokenAddress(wallet, tokenProgram, mint solana.PublicKey) (solana.PublicKey, uint8, bool) {
+	address, bump, err := core.FindAssociatedTokenAddress(core.PublicKey(wallet), core.PublicKey(mint), core.PublicKey(tokenProgram))
+	return solana.PublicKey(address), bump, err == nil
+}
@@ -0,0 +1,140 @@
+package token2022
+
+import (
+	"bytes"
+	"testing"
+
+	bin "github.com/gagliardetto/binary"
+	solana "github.com/gagliardetto/solana-go"
+)
+
+func TestFastPathDataMatchesEncoder(t *testing.T) {
+
+	var (
+		source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		owner       = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	)
+
+	for _, inst := range []*Instruction{
+		NewTransfer2022Instruction(1<<40+7, source, destination, owner).Build(),
+		NewTransferChecked2022Instruction(1<<40+7, 9, source, mint, destination, owner).Build(),
+		NewMintTo2022Instruction(1<<40+7, mint, destination, owner).Build(),
+		NewMintToChecked2022Instruction(1<<40+7, 9, mint, destination, owner).Build(),
+	} {
+		buf := new(bytes.Buffer)
+		if err := bin.NewBorshEncoder(buf).Encode(inst); err != nil {
+			t.Fatalf("Error encoding: %v", err)
+		}
+		data, err := inst.Data()
+		if err != nil {
+			t.Fatalf("Error getting data: %v", err)
+		}
+		if !bytes.Equal(data, buf.Bytes()) {
+			t.Errorf("%s: expected %x, got %x", InstructionName(data), buf.Bytes(), data)
+		}
+	}
+}
+
+func TestFastPathAllocations(t *testing.T) {
+
+	var (
+		wallet = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint   = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	)
+
+	buf := make([]byte, 0, 64)
+	if allocs := testing.AllocsPerRun(100, func() {
+		buf = AppendTransferCheckedData(buf[:0], 12345, 6)
+		buf = AppendMintToData(buf, 12345)
+	}); allocs != 0 {
+		t.Errorf("Expected no allocations appending data, got %.0f", allocs)
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() {
+		_, _, _ = FindAssociatedTokenAddress2022(wallet, mint)
+	}); allocs != 0 {
+		t.Errorf("Expected no allocations deriving the ATA, got %.0f", allocs)
+	}
+}
+
+func TestFindAssociatedTokenAddressMatchesFindProgramAddress(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		wallet := solana.NewWallet().PublicKey()
+		mint := solana.NewWallet().PublicKey()
+		want, wantBump, err := solana.FindProgramAddress([][]byte{
+			wallet[:],
+			solana.Token2022ProgramID[:],
+			mint[:],
+		}, solana.SPLAssociatedTokenAccountProgramID)
+		if err != nil {
+			t.Fatalf("Error deriving address: %v", err)
+		}
+		got, gotBump, err := FindAssociatedTokenAddress2022(wallet, mint)
+		if err != nil {
+			t.Fatalf("Error deriving address: %v", err)
+		}
+		if got != want || gotBump != wantBump {
+			t.Errorf("Expected %s/%d, got %s/%d", want, wantBump, got, gotBump)
+		}
+	}
+}
+
+func BenchmarkTransferCheckedData(b *testing.B) {
+
+	var (
+		source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		owner       = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	)
+
+	inst := NewTransferChecked2022Instruction(12345, 6, source, mint, destination, owner).Build()
+
+	b.Run("Encoder", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			buf := new(bytes.Buffer)
+			if err := bin.NewBorshEncoder(buf).Encode(inst); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("Data", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := inst.Data(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("AppendData", func(b *testing.B) {
+		b.ReportAllocs()
+		buf := make([]byte, 0, TransferCheckedDataSize)
+		for i := 0; i < b.N; i++ {
+			buf, _ = inst.AppendData(buf[:0])
+		}
+	})
+}
+
+func BenchmarkFindAssociatedTokenAddress2022(b *testing.B) {
+
+	var (
+		wallet = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint   = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	)
+
+	b.Run("FindProgramAddress", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _, _ = solana.FindProgramAddress([][]byte{wallet[:], solana.Token2022ProgramID[:], mint[:]}, solana.SPLAssociatedTokenAccountProgramID)
+		}
+	})
+	b.Run("FindAssociatedTokenAddress2022", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _, _ = FindAssociatedTokenAddress2022(wallet, mint)
+		}
+	})
+}
@@ -0,0 +1,258 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"fmt"
+	"math/bits"
+	"sort"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// minimumSlotsPerEpoch is the length of the first epoch of a cluster with
+// warmup epochs.
+const minimumSlotsPerEpoch = 32
+
+// EpochSchedule maps slots to epochs.
+type EpochSchedule struct {
+	SlotsPerEpoch uint64
+	// Warmup is set when the epochs before FirstNormalEpoch are shorter,
+	// doubling in length from minimumSlotsPerEpoch.
+	Warmup           bool
+	FirstNormalEpoch uint64
+	FirstNormalSlot  uint64
+}
+
+// MainnetEpochSchedule is the epoch schedule of mainnet-beta, which has no
+// warmup epochs.
+var MainnetEpochSchedule = EpochSchedule{SlotsPerEpoch: 432_000}
+
+// NewEpochSchedule converts the result of getEpochSchedule.
+func NewEpochSchedule(result *rpc.GetEpochScheduleResult) EpochSchedule {
+	return EpochSchedule{
+		SlotsPerEpoch:    result.SlotsPerEpoch,
+		Warmup:           result.Warmup,
+		FirstNormalEpoch: result.FirstNormalEpoch,
+		FirstNormalSlot:  result.FirstNormalSlot,
+	}
+}
+
+// Epoch returns the epoch of slot.
+func (s EpochSchedule) Epoch(slot uint64) uint64 {
+	if s.Warmup && slot < s.FirstNormalSlot {
+		// The length of warmup epoch n is minimumSlotsPerEpoch << n.
+		return uint64(bits.Len64(slot+minimumSlotsPerEpoch)) - uint64(bits.TrailingZeros64(minimumSlotsPerEpoch)) - 1
+	}
+	if s.SlotsPerEpoch == 0 {
+		return 0
+	}
+	return s.FirstNormalEpoch + (slot-s.FirstNormalSlot)/s.SlotsPerEpoch
+}
+
+// EpochFees are the transfer fees of a mint in one epoch.
+type EpochFees struct {
+	Epoch uint64
+	// Transfers is the number of TransferChecked and
+	// TransferCheckedWithFee instructions of the mint.
+	Transfers int
+	// Withheld is the amount withheld in token accounts by transfers.
+	Withheld uint64
+	// Harvests is the number of HarvestWithheldTokensToMint instructions,
+	// and HarvestedAccounts the number of accounts they harvested. The
+	// harvested amounts do not appear in transaction metadata.
+	Harvests          int
+	HarvestedAccounts int
+	// WithdrawnFromMint and WithdrawnFromAccounts are the withheld fees
+	// withdrawn by the withdraw withheld authority.
+	WithdrawnFromMint     uint64
+	WithdrawnFromAccounts uint64
+}
+
+// Withdrawn returns the fees withdrawn in the epoch.
+func (f *EpochFees) Withdrawn() uint64 {
+	return f.WithdrawnFromMint + f.WithdrawnFromAccounts
+}
+
+// FeeReport is the transfer fee accounting of a mint.
+type FeeReport struct {
+	Mint solana.PublicKey
+	// Epochs lists the epochs with fee activity in ascending order.
+	Epochs    []*EpochFees
+	Withheld  uint64
+	Withdrawn uint64
+}
+
+// Unwithdrawn returns the fees withheld and not yet withdrawn, held in
+// token accounts or in the mint. It is only meaningful for a report
+// covering the whole history of the mint, and is zero when more was
+// withdrawn than the report saw withheld.
+func (r *FeeReport) Unwithdrawn() uint64 {
+	if r.Withdrawn > r.Withheld {
+		return 0
+	}
+	return r.Withheld - r.Withdrawn
+}
+
+// Epoch returns the fees of one epoch, or nil when it had no fee
+// activity.
+func (r *FeeReport) Epoch(epoch uint64) *EpochFees {
+	i := sort.Search(len(r.Epochs), func(i int) bool { return r.Epochs[i].Epoch >= epoch })
+	if i < len(r.Epochs) && r.Epochs[i].Epoch == epoch {
+		return r.Epochs[i]
+	}
+	return nil
+}
+
+// FeeReporter aggregates the transfer fees of a mint by epoch from its
+// transaction history, for issuers reconciling fee revenue.
+type FeeReporter struct {
+	history  *HistoryReader
+	schedule EpochSchedule
+}
+
+// NewFeeReporter creates a reporter using MainnetEpochSchedule and
+// fetching four transactions at once.
+func NewFeeReporter(client HistoryClient) *FeeReporter {
+	return &FeeReporter{history: NewHistoryReader(client), schedule: MainnetEpochSchedule}
+}
+
+// SetEpochSchedule sets the schedule of the cluster, as returned by
+// getEpochSchedule.
+func (r *FeeReporter) SetEpochSchedule(schedule EpochSchedule) *FeeReporter {
+	r.schedule = schedule
+	return r
+}
+
+// SetConcurrency sets the number of getTransaction requests in flight at
+// once.
+func (r *FeeReporter) SetConcurrency(workers int) *FeeReporter {
+	r.history.SetConcurrency(workers)
+	return r
+}
+
+// Report reads the history of mint back to firstEpoch and aggregates the
+// fees of the epochs from firstEpoch to lastEpoch, inclusive. Failed
+// transactions are ignored.
+func (r *FeeReporter) Report(ctx context.Context, mint solana.PublicKey, firstEpoch, lastEpoch uint64, commitment rpc.CommitmentType) (*FeeReport, error) {
+	byEpoch := map[uint64]*EpochFees{}
+	opts := &HistoryOpts{Commitment: commitment}
+	for {
+		sigs, txs, next, err := r.history.fetch(ctx, mint, opts)
+		if err != nil {
+			return nil, err
+		}
+		reachedFirst := false
+		for i, out := range txs {
+			epoch := r.schedule.Epoch(out.Slot)
+			if epoch < firstEpoch {
+				reachedFirst = true
+				continue
+			}
+			if epoch > lastEpoch {
+				continue
+			}
+			fees, ok := byEpoch[epoch]
+			if !ok {
+				fees = &EpochFees{Epoch: epoch}
+			}
+			active, err := addEpochFees(fees, out, mint)
+			if err != nil {
+				return nil, fmt.Errorf("error while accounting transaction %s: %w", sigs[i].Signature, err)
+			}
+			if active {
+				byEpoch[epoch] = fees
+			}
+		}
+		if next.IsZero() || reachedFirst {
+			break
+		}
+		opts.Before = next
+	}
+
+	report := &FeeReport{Mint: mint}
+	for _, fees := range byEpoch {
+		report.Epochs = append(report.Epochs, fees)
+		report.Withheld += fees.Withheld
+		report.Withdrawn += fees.Withdrawn()
+	}
+	sort.Slice(report.Epochs, func(i, j int) bool { return report.Epochs[i].Epoch < report.Epochs[j].Epoch })
+	return report, nil
+}
+
+// addEpochFees adds the fee activity of a transaction to fees and reports
+// whether there was any.
+func addEpochFees(fees *EpochFees, out *rpc.GetTransactionResult, mint solana.PublicKey) (bool, error) {
+	if out.Meta.Err != nil {
+		return false, nil
+	}
+	tx, err := out.Transaction.GetTransaction()
+	if err != nil {
+		return false, err
+	}
+	parsed, err := ParseTransaction(tx, out.Meta)
+	if err != nil {
+		return false, err
+	}
+
+	active := false
+	transfers := 0
+	for _, inst := range parsed.Instructions {
+		switch inst := inst.Instruction.(type) {
+		case *TransferChecked2022:
+			if inst.Mint.Equals(mint) {
+				transfers++
+			}
+		case *TransferCheckedWithFee2022:
+			if inst.Mint.Equals(mint) {
+				transfers++
+			}
+		case *HarvestWithheldTokensToMint2022:
+			if inst.Mint.Equals(mint) {
+				fees.Harvests++
+				fees.HarvestedAccounts += len(inst.Sources)
+				active = true
+			}
+		}
+	}
+
+	if transfers > 0 {
+		changes, err := ExtractBalanceChanges(tx, out.Meta)
+		if err != nil {
+			return false, err
+		}
+		for _, change := range changes.Tokens {
+			if change.Mint.Equals(mint) && change.FeeWithheld > 0 {
+				fees.Withheld += change.FeeWithheld
+				active = true
+			}
+		}
+		fees.Transfers += transfers
+		active = true
+	}
+
+	fromMint, fromAccounts, err := withdrawnFees(tx, out.Meta, parsed, mint)
+	if err != nil {
+		return false, err
+	}
+	if fromMint > 0 || fromAccounts > 0 {
+		fees.WithdrawnFromMint += fromMint
+		fees.WithdrawnFromAccounts += fromAccounts
+		active = true
+	}
+	return active, nil
+}
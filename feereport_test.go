@@ -0,0 +1,61 @@
+package token2022
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestEpochSchedule(t *testing.T) {
+	if epoch := MainnetEpochSchedule.Epoch(300_000_000); epoch != 694 {
+		t.Errorf("Expected mainnet epoch 694, got %d", epoch)
+	}
+
+	// The default schedule of a test validator: warmup epochs of 32, 64,
+	// 128... slots, then epochs of 8192 slots from epoch 8.
+	warmup := EpochSchedule{SlotsPerEpoch: 8192, Warmup: true, FirstNormalEpoch: 8, FirstNormalSlot: 8160}
+	for _, c := range []struct{ slot, epoch uint64 }{
+		{0, 0}, {31, 0}, {32, 1}, {95, 1}, {96, 2}, {8159, 7}, {8160, 8}, {8160 + 8192, 9},
+	} {
+		if epoch := warmup.Epoch(c.slot); epoch != c.epoch {
+			t.Errorf("Slot %d: expected epoch %d, got %d", c.slot, c.epoch, epoch)
+		}
+	}
+}
+
+func TestFeeReporter(t *testing.T) {
+	client := &historyRPC{txs: map[solana.Signature]*rpc.GetTransactionResult{}}
+	// Newest first, in epochs of 10 slots.
+	addSupplyTransaction(t, client, 26, NewHarvestWithheldTokensToMint2022Instruction(depositMint, depositSource, depositDestination).Build(), false)
+	addSupplyTransaction(t, client, 25, NewWithdrawWithheldTokensFromMint2022Instruction(depositMint, depositDestination, depositWallet).Build(), false, "700", "720")
+	for _, slot := range []uint64{15, 5} {
+		tx, meta := depositTransaction(t, solana.Signature{byte(slot)})
+		client.add(t, tx, meta, slot)
+	}
+	reporter := NewFeeReporter(client).SetEpochSchedule(EpochSchedule{SlotsPerEpoch: 10})
+
+	report, err := reporter.Report(context.Background(), depositMint, 0, math.MaxUint64, rpc.CommitmentFinalized)
+	if err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+	if len(report.Epochs) != 3 || report.Withheld != 20 || report.Withdrawn != 20 || report.Unwithdrawn() != 0 {
+		t.Fatalf("Unexpected report %+v", report)
+	}
+	if fees := report.Epoch(0); fees == nil || fees.Transfers != 1 || fees.Withheld != 10 {
+		t.Errorf("Unexpected epoch 0 %+v", fees)
+	}
+	if fees := report.Epoch(2); fees == nil || fees.WithdrawnFromMint != 20 || fees.Harvests != 1 || fees.HarvestedAccounts != 2 || fees.Transfers != 0 {
+		t.Errorf("Unexpected epoch 2 %+v", fees)
+	}
+
+	report, err = reporter.Report(context.Background(), depositMint, 1, 1, rpc.CommitmentFinalized)
+	if err != nil {
+		t.Fatalf("Report: %v", err)
+	}
+	if len(report.Epochs) != 1 || report.Epochs[0].Epoch != 1 || report.Withheld != 10 || report.Withdrawn != 0 {
+		t.Errorf("Unexpected report of epoch 1 %+v", report)
+	}
+}
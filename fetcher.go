@@ -0,0 +1,241 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// MaxMultipleAccounts is the largest number of accounts a node returns
+// from one getMultipleAccounts request.
+const MaxMultipleAccounts = 100
+
+// MultipleAccountsClient is the getMultipleAccounts call used by
+// AccountFetcher. *rpc.Client satisfies it.
+type MultipleAccountsClient interface {
+	GetMultipleAccountsWithOpts(ctx context.Context, accounts []solana.PublicKey, opts *rpc.GetMultipleAccountsOpts) (*rpc.GetMultipleAccountsResult, error)
+}
+
+var _ MultipleAccountsClient = (*rpc.Client)(nil)
+
+// FetchProgress reports the state of an AccountFetcher run.
+type FetchProgress struct {
+	// Fetched is the number of accounts whose batch has completed,
+	// including accounts that do not exist.
+	Fetched int
+	Total   int
+	// Retries is the number of failed requests that were retried.
+	Retries int
+}
+
+// AccountFetcher reads large sets of accounts, such as every holder of a
+// mint, with getMultipleAccounts. Batches run on a bounded pool of
+// workers so several requests are in flight at once, and failed requests
+// are retried with backoff according to a RetryPolicy. Combine it with a
+// rate-limited client to stay within the limits of public endpoints.
+//
+// AccountFetcher is safe for concurrent use once configured.
+type AccountFetcher struct {
+	client      MultipleAccountsClient
+	opts        FetchOpts
+	batchSize   int
+	concurrency int
+	retry       RetryPolicy
+	progress    func(FetchProgress)
+}
+
+// NewAccountFetcher creates a fetcher that reads at confirmed commitment
+// in batches of 100 with four workers and the default retry policy.
+func NewAccountFetcher(client MultipleAccountsClient) *AccountFetcher {
+	return &AccountFetcher{
+		client:      client,
+		opts:        FetchOpts{Commitment: rpc.CommitmentConfirmed},
+		batchSize:   MaxMultipleAccounts,
+		concurrency: 4,
+		retry:       DefaultRetryPolicy(),
+	}
+}
+
+func (f *AccountFetcher) SetFetchOpts(opts FetchOpts) *AccountFetcher {
+	f.opts = opts
+	return f
+}
+
+// SetBatchSize sets the number of accounts per request, capped at
+// MaxMultipleAccounts.
+func (f *AccountFetcher) SetBatchSize(size int) *AccountFetcher {
+	if size > MaxMultipleAccounts {
+		size = MaxMultipleAccounts
+	}
+	if size > 0 {
+		f.batchSize = size
+	}
+	return f
+}
+
+// SetConcurrency sets the number of requests in flight at once.
+func (f *AccountFetcher) SetConcurrency(workers int) *AccountFetcher {
+	if workers > 0 {
+		f.concurrency = workers
+	}
+	return f
+}
+
+func (f *AccountFetcher) SetRetryPolicy(policy RetryPolicy) *AccountFetcher {
+	f.retry = policy
+	return f
+}
+
+// SetProgress sets a callback run after every completed batch and retry.
+// Calls are serialized.
+func (f *AccountFetcher) SetProgress(progress func(FetchProgress)) *AccountFetcher {
+	f.progress = progress
+	return f
+}
+
+// Fetch reads accounts and returns them in the same order, with nil for
+// accounts that do not exist. The first batch that fails after its
+// retries cancels the remaining batches and its error is returned.
+func (f *AccountFetcher) Fetch(ctx context.Context, accounts []solana.PublicKey) ([]*rpc.Account, error) {
+	results := make([]*rpc.Account, len(accounts))
+	if len(accounts) == 0 {
+		return results, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		batches  = make(chan int)
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		progress = FetchProgress{Total: len(accounts)}
+	)
+	report := func(update func(*FetchProgress)) {
+		mu.Lock()
+		defer mu.Unlock()
+		update(&progress)
+		if f.progress != nil {
+			f.progress(progress)
+		}
+	}
+
+	workers := f.concurrency
+	if batchCount := (len(accounts) + f.batchSize - 1) / f.batchSize; workers > batchCount {
+		workers = batchCount
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := range batches {
+				end := min(start+f.batchSize, len(accounts))
+				err := f.fetchBatch(ctx, accounts[start:end], results[start:end], func() {
+					report(func(p *FetchProgress) { p.Retries++ })
+				})
+				if err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+					cancel()
+					continue
+				}
+				report(func(p *FetchProgress) { p.Fetched += end - start })
+			}
+		}()
+	}
+
+feed:
+	for start := 0; start < len(accounts); start += f.batchSize {
+		select {
+		case batches <- start:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(batches)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// FetchTokenAccounts reads and decodes token accounts, returning them in
+// the same order with nil for accounts that do not exist. An account that
+// is not a Token-2022 token account is an error.
+func (f *AccountFetcher) FetchTokenAccounts(ctx context.Context, accounts []solana.PublicKey) ([]*TokenAccount, error) {
+	raw, err := f.Fetch(ctx, accounts)
+	if err != nil {
+		return nil, err
+	}
+	decoded := make([]*TokenAccount, len(raw))
+	for i, account := range raw {
+		if account == nil {
+			continue
+		}
+		if !account.Owner.Equals(solana.Token2022ProgramID) {
+			return nil, fmt.Errorf("%w: %s is not owned by the Token-2022 program", ErrInvalidAccountOwner, accounts[i])
+		}
+		if decoded[i], err = DecodeTokenAccount(account.Data.GetBinary()); err != nil {
+			return nil, fmt.Errorf("error while decoding account %s: %w", accounts[i], err)
+		}
+	}
+	return decoded, nil
+}
+
+// fetchBatch reads one batch into results, retrying failed requests.
+func (f *AccountFetcher) fetchBatch(ctx context.Context, accounts []solana.PublicKey, results []*rpc.Account, onRetry func()) error {
+	opts := &rpc.GetMultipleAccountsOpts{
+		Encoding:       solana.EncodingBase64,
+		Commitment:     f.opts.Commitment,
+		MinContextSlot: f.opts.MinContextSlot,
+	}
+	for attempt := 1; ; attempt++ {
+		out, err := f.client.GetMultipleAccountsWithOpts(ctx, accounts, opts)
+		if err == nil {
+			if len(out.Value) != len(accounts) {
+				return fmt.Errorf("getMultipleAccounts returned %d accounts, expected %d", len(out.Value), len(accounts))
+			}
+			copy(results, out.Value)
+			return nil
+		}
+		if attempt >= f.retry.MaxAttempts || !IsRetryable(err) {
+			return fmt.Errorf("error while fetching accounts: %w", err)
+		}
+		onRetry()
+
+		timer := time.NewTimer(f.retry.Backoff(attempt, err))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
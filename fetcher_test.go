@@ -0,0 +1,125 @@
+package token2022
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+// mockMultipleAccounts serves getMultipleAccounts from a map and fails the
+// first request of every batch listed in failures once.
+type mockMultipleAccounts struct {
+	mu       sync.Mutex
+	accounts map[solana.PublicKey]*rpc.Account
+	failures map[solana.PublicKey]error
+	requests int
+	inflight int
+	maxInFly int
+}
+
+func (m *mockMultipleAccounts) GetMultipleAccountsWithOpts(ctx context.Context, accounts []solana.PublicKey, opts *rpc.GetMultipleAccountsOpts) (*rpc.GetMultipleAccountsResult, error) {
+	m.mu.Lock()
+	m.requests++
+	m.inflight++
+	m.maxInFly = max(m.maxInFly, m.inflight)
+	err, fail := m.failures[accounts[0]]
+	delete(m.failures, accounts[0])
+	m.mu.Unlock()
+
+	time.Sleep(time.Millisecond)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inflight--
+	if fail {
+		return nil, err
+	}
+	out := &rpc.GetMultipleAccountsResult{Value: make([]*rpc.Account, len(accounts))}
+	for i, account := range accounts {
+		out.Value[i] = m.accounts[account]
+	}
+	return out, nil
+}
+
+func TestAccountFetcher(t *testing.T) {
+
+	var (
+		mint   = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		client = &mockMultipleAccounts{
+			accounts: map[solana.PublicKey]*rpc.Account{},
+			failures: map[solana.PublicKey]error{},
+		}
+	)
+
+	accounts := make([]solana.PublicKey, 250)
+	for i := range accounts {
+		accounts[i] = solana.NewWallet().PublicKey()
+		if i%7 == 0 {
+			continue // missing
+		}
+		client.accounts[accounts[i]] = &rpc.Account{
+			Owner: solana.Token2022ProgramID,
+			Data:  rpc.DataBytesOrJSONFromBytes(encodeTokenAccount(mint, accounts[i], uint64(i))),
+		}
+	}
+	client.failures[accounts[100]] = &jsonrpc.RPCError{Code: rpcNodeUnhealthy, Message: "Node is unhealthy"}
+
+	var progress []FetchProgress
+	fetcher := NewAccountFetcher(client).
+		SetBatchSize(50).
+		SetConcurrency(3).
+		SetRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, Multiplier: 1}).
+		SetProgress(func(p FetchProgress) { progress = append(progress, p) })
+
+	decoded, err := fetcher.FetchTokenAccounts(context.Background(), accounts)
+	if err != nil {
+		t.Fatalf("Error fetching: %v", err)
+	}
+	for i, account := range decoded {
+		switch {
+		case i%7 == 0 && account != nil:
+			t.Errorf("Expected account %d to be missing", i)
+		case i%7 != 0 && (account == nil || account.Amount != uint64(i) || !account.Owner.Equals(accounts[i])):
+			t.Errorf("Unexpected account %d: %+v", i, account)
+		}
+	}
+
+	if client.requests != 6 {
+		t.Errorf("Expected 6 requests, got %d", client.requests)
+	}
+	if client.maxInFly > 3 {
+		t.Errorf("Expected at most 3 requests in flight, got %d", client.maxInFly)
+	}
+	last := progress[len(progress)-1]
+	if last != (FetchProgress{Fetched: 250, Total: 250, Retries: 1}) {
+		t.Errorf("Unexpected final progress %+v", last)
+	}
+}
+
+func TestAccountFetcherError(t *testing.T) {
+
+	var (
+		client = &mockMultipleAccounts{
+			accounts: map[solana.PublicKey]*rpc.Account{},
+			failures: map[solana.PublicKey]error{},
+		}
+	)
+
+	accounts := make([]solana.PublicKey, 30)
+	for i := range accounts {
+		accounts[i] = solana.NewWallet().PublicKey()
+	}
+	client.failures[accounts[10]] = &jsonrpc.RPCError{Code: -32602, Message: "Invalid params"}
+
+	_, err := NewAccountFetcher(client).SetBatchSize(10).Fetch(context.Background(), accounts)
+	var rpcErr *jsonrpc.RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -32602 {
+		t.Errorf("Expected the batch error, got %v", err)
+	}
+}
@@ -0,0 +1,365 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	solana "github.com/gagliardetto/solana-go"
+	format "github.com/gagliardetto/solana-go/text/format"
+	treeout "github.com/gagliardetto/treeout"
+)
+
+// FreezeAccount2022 freezes a token account using the mint's freeze authority.
+type FreezeAccount2022 struct {
+	Account         solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Mint            solana.PublicKey `bin:"-" borsh_skip:"true"`
+	FreezeAuthority solana.PublicKey `bin:"-" borsh_skip:"true"`
+	// Signers are the signers of a multisig authority.
+	Signers []solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Account
+	// ··········· The account to freeze
+	//
+	// [1] = [] Mint
+	// ··········· The token mint
+	//
+	// [2] = [SIGNER] FreezeAuthority
+	// ··········· The mint's freeze authority, or a multisig
+	//
+	// [3...] = [SIGNER] Signers
+	// ··········· M signer accounts when the authority is a multisig
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewFreezeAccount2022InstructionBuilder creates a new `FreezeAccount2022` instruction builder.
+func NewFreezeAccount2022InstructionBuilder() *FreezeAccount2022 {
+	nd := &FreezeAccount2022{}
+	return nd
+}
+
+func (inst *FreezeAccount2022) SetAccount(account solana.PublicKey) *FreezeAccount2022 {
+	inst.Account = account
+	return inst
+}
+
+func (inst *FreezeAccount2022) SetMint(mint solana.PublicKey) *FreezeAccount2022 {
+	inst.Mint = mint
+	return inst
+}
+
+// SetFreezeAuthority sets the authority. Pass the signers when the authority is a multisig.
+func (inst *FreezeAccount2022) SetFreezeAuthority(freezeAuthority solana.PublicKey, multisigSigners ...solana.PublicKey) *FreezeAccount2022 {
+	inst.FreezeAuthority = freezeAuthority
+	inst.Signers = multisigSigners
+	return inst
+}
+
+func (inst FreezeAccount2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Account,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+		{
+			PublicKey:  inst.Mint,
+			IsSigner:   false,
+			IsWritable: false,
+		},
+	}
+	keys = appendAuthority(keys, inst.FreezeAuthority, inst.Signers)
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst FreezeAccount2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *FreezeAccount2022) Validate() error {
+	if inst.Account.IsZero() {
+		return errNotSet("Account")
+	}
+	if inst.Mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	if inst.FreezeAuthority.IsZero() {
+		return errNotSet("FreezeAuthority")
+	}
+	if err := validateMultisigSigners(inst.Signers); err != nil {
+		return err
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+// ValidateOnChain validates the instruction and checks its accounts on
+// chain; see the package function ValidateOnChain.
+func (inst *FreezeAccount2022) ValidateOnChain(ctx context.Context, client RPCClient) error {
+	return ValidateOnChain(ctx, client, inst)
+}
+
+func (inst *FreezeAccount2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("FreezeAccount2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=0]").ParentFunc(func(paramsBranch treeout.Branches) {})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("        account", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(format.Meta("           mint", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(format.Meta("freezeAuthority", inst.AccountMetaSlice.Get(2)))
+						encodeRemainingAccounts(accountsBranch, "signer", inst.AccountMetaSlice, 3)
+					})
+				})
+		})
+}
+
+func (inst FreezeAccount2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	return nil
+}
+
+func (inst *FreezeAccount2022) UnmarshalWithDecoder(decoder *bin.Decoder) error {
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst FreezeAccount2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *FreezeAccount2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("FreezeAccount2022", accounts, 3); err != nil {
+		return err
+	}
+	inst.Account = accounts[0].PublicKey
+	inst.Mint = accounts[1].PublicKey
+	inst.FreezeAuthority = accounts[2].PublicKey
+	inst.Signers = pubkeysOf(accounts[3:])
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst FreezeAccount2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *FreezeAccount2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewFreezeAccount2022Instruction creates a new `FreezeAccount2022` instruction.
+func NewFreezeAccount2022Instruction(
+	account solana.PublicKey,
+	mint solana.PublicKey,
+	freezeAuthority solana.PublicKey,
+	multisigSigners ...solana.PublicKey,
+) *FreezeAccount2022 {
+	return NewFreezeAccount2022InstructionBuilder().
+		SetAccount(account).
+		SetMint(mint).
+		SetFreezeAuthority(freezeAuthority, multisigSigners...)
+}
+
+// ThawAccount2022 thaws a frozen token account using the mint's freeze authority.
+type ThawAccount2022 struct {
+	Account         solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Mint            solana.PublicKey `bin:"-" borsh_skip:"true"`
+	FreezeAuthority solana.PublicKey `bin:"-" borsh_skip:"true"`
+	// Signers are the signers of a multisig authority.
+	Signers []solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Account
+	// ··········· The account to thaw
+	//
+	// [1] = [] Mint
+	// ··········· The token mint
+	//
+	// [2] = [SIGNER] FreezeAuthority
+	// ··········· The mint's freeze authority, or a multisig
+	//
+	// [3...] = [SIGNER] Signers
+	// ··········· M signer accounts when the authority is a multisig
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewThawAccount2022InstructionBuilder creates a new `ThawAccount2022` instruction builder.
+func NewThawAccount2022InstructionBuilder() *ThawAccount2022 {
+	nd := &ThawAccount2022{}
+	return nd
+}
+
+func (inst *ThawAccount2022) SetAccount(account solana.PublicKey) *ThawAccount2022 {
+	inst.Account = account
+	return inst
+}
+
+func (inst *ThawAccount2022) SetMint(mint solana.PublicKey) *ThawAccount2022 {
+	inst.Mint = mint
+	return inst
+}
+
+// SetFreezeAuthority sets the authority. Pass the signers when the authority is a multisig.
+func (inst *ThawAccount2022) SetFreezeAuthority(freezeAuthority solana.PublicKey, multisigSigners ...solana.PublicKey) *ThawAccount2022 {
+	inst.FreezeAuthority = freezeAuthority
+	inst.Signers = multisigSigners
+	return inst
+}
+
+func (inst ThawAccount2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Account,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+		{
+			PublicKey:  inst.Mint,
+			IsSigner:   false,
+			IsWritable: false,
+		},
+	}
+	keys = appendAuthority(keys, inst.FreezeAuthority, inst.Signers)
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst ThawAccount2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *ThawAccount2022) Validate() error {
+	if inst.Account.IsZero() {
+		return errNotSet("Account")
+	}
+	if inst.Mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	if inst.FreezeAuthority.IsZero() {
+		return errNotSet("FreezeAuthority")
+	}
+	if err := validateMultisigSigners(inst.Signers); err != nil {
+		return err
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+// ValidateOnChain validates the instruction and checks its accounts on
+// chain; see the package function ValidateOnChain.
+func (inst *ThawAccount2022) ValidateOnChain(ctx context.Context, client RPCClient) error {
+	return ValidateOnChain(ctx, client, inst)
+}
+
+func (inst *ThawAccount2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("ThawAccount2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=0]").ParentFunc(func(paramsBranch treeout.Branches) {})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("        account", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(format.Meta("           mint", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(format.Meta("freezeAuthority", inst.AccountMetaSlice.Get(2)))
+						encodeRemainingAccounts(accountsBranch, "signer", inst.AccountMetaSlice, 3)
+					})
+				})
+		})
+}
+
+func (inst ThawAccount2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	return nil
+}
+
+func (inst *ThawAccount2022) UnmarshalWithDecoder(decoder *bin.Decoder) error {
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst ThawAccount2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *ThawAccount2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("ThawAccount2022", accounts, 3); err != nil {
+		return err
+	}
+	inst.Account = accounts[0].PublicKey
+	inst.Mint = accounts[1].PublicKey
+	inst.FreezeAuthority = accounts[2].PublicKey
+	inst.Signers = pubkeysOf(accounts[3:])
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst ThawAccount2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *ThawAccount2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewThawAccount2022Instruction creates a new `ThawAccount2022` instruction.
+func NewThawAccount2022Instruction(
+	account solana.PublicKey,
+	mint solana.PublicKey,
+	freezeAuthority solana.PublicKey,
+	multisigSigners ...solana.PublicKey,
+) *ThawAccount2022 {
+	return NewThawAccount2022InstructionBuilder().
+		SetAccount(account).
+		SetMint(mint).
+		SetFreezeAuthority(freezeAuthority, multisigSigners...)
+}
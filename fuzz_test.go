@@ -0,0 +1,99 @@
+package token2022
+
+import (
+	"encoding/hex"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// addGoldenSeeds adds the data of the golden vectors of kind to the corpus.
+func addGoldenSeeds(f *testing.F, kind string) {
+	var vectors []goldenAccount
+	if kind == "instruction" {
+		var instructions []goldenInstruction
+		loadGolden(f, "instructions.json", &instructions)
+		for _, vector := range instructions {
+			vectors = append(vectors, goldenAccount{Data: vector.Data})
+		}
+	} else {
+		loadGolden(f, "accounts.json", &vectors)
+	}
+	for _, vector := range vectors {
+		if data, err := hex.DecodeString(vector.Data); err == nil {
+			f.Add(data)
+		}
+	}
+}
+
+func FuzzDecodeMint(f *testing.F) {
+	authority := solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	f.Add(encodeMint(&authority, 1, 6))
+	f.Add(encodeMint(nil, 0, 0, Extension{Type: ExtensionMintCloseAuthority, Data: authority.Bytes()}))
+	addGoldenSeeds(f, "account")
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		mint, err := DecodeMint(data)
+		if err != nil {
+			return
+		}
+		for _, ext := range mint.Extensions {
+			if len(ext.Data) > len(data) {
+				t.Fatalf("extension %s has %d bytes, account has %d", ext.Type, len(ext.Data), len(data))
+			}
+		}
+		_, _, _ = mint.TokenMetadata()
+	})
+}
+
+func FuzzDecodeTokenAccount(f *testing.F) {
+	mint := solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	owner := solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	f.Add(encodeTokenAccount(mint, owner, 1))
+	f.Add(encodeTokenAccount(mint, owner, 1, Extension{Type: ExtensionImmutableOwner}, Extension{Type: ExtensionTransferFeeAmount, Data: make([]byte, 8)}))
+	addGoldenSeeds(f, "account")
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		account, err := DecodeTokenAccount(data)
+		if err != nil {
+			return
+		}
+		for _, ext := range account.Extensions {
+			if len(ext.Data) > len(data) {
+				t.Fatalf("extension %s has %d bytes, account has %d", ext.Type, len(ext.Data), len(data))
+			}
+		}
+		_ = account.IsFrozen()
+	})
+}
+
+func FuzzDecodeTokenMetadata(f *testing.F) {
+	f.Add(make([]byte, 64))
+	f.Add(append(make([]byte, 64), 0xff, 0xff, 0xff, 0xff))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, _ = DecodeTokenMetadata(data)
+	})
+}
+
+func FuzzDecodeInstruction(f *testing.F) {
+	addGoldenSeeds(f, "instruction")
+
+	accounts := make([]*solana.AccountMeta, 12)
+	for i := range accounts {
+		accounts[i] = solana.Meta(solana.PublicKey{byte(i + 1)}).WRITE()
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, n := range []int{0, 1, 3, len(accounts)} {
+			inst, err := DecodeInstruction(accounts[:n], data)
+			if err != nil {
+				continue
+			}
+			// A decoded instruction must encode again.
+			if _, err := inst.Build().Data(); err != nil {
+				t.Fatalf("%s decoded from %x but does not encode: %v", InstructionName(data), data, err)
+			}
+			_ = Explain(inst)
+		}
+	})
+}
@@ -0,0 +1,240 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geyser
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// rawMessage is an encoded protobuf message passed through rawCodec.
+type rawMessage []byte
+
+// rawCodec sends and receives rawMessage values as they are. It is named
+// "proto" so requests carry the content type Yellowstone servers expect.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	msg, ok := v.(rawMessage)
+	if !ok {
+		return nil, fmt.Errorf("geyser: cannot marshal %T", v)
+	}
+	return msg, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	msg, ok := v.(*rawMessage)
+	if !ok {
+		return fmt.Errorf("geyser: cannot unmarshal into %T", v)
+	}
+	*msg = append((*msg)[:0], data...)
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
+
+func (t *protoTransaction) toTransaction() (*solana.Transaction, error) {
+	tx := &solana.Transaction{}
+	for _, signature := range t.signatures {
+		if len(signature) != solana.SignatureLength {
+			return nil, fmt.Errorf("invalid signature length %d", len(signature))
+		}
+		tx.Signatures = append(tx.Signatures, solana.SignatureFromBytes(signature))
+	}
+
+	message := &t.message
+	tx.Message.Header = solana.MessageHeader{
+		NumRequiredSignatures:       uint8(message.header[0]),
+		NumReadonlySignedAccounts:   uint8(message.header[1]),
+		NumReadonlyUnsignedAccounts: uint8(message.header[2]),
+	}
+	for _, key := range message.accountKeys {
+		if len(key) != solana.PublicKeyLength {
+			return nil, fmt.Errorf("invalid account key length %d", len(key))
+		}
+		tx.Message.AccountKeys = append(tx.Message.AccountKeys, solana.PublicKeyFromBytes(key))
+	}
+	if len(message.recentBlockhash) == 32 {
+		tx.Message.RecentBlockhash = solana.HashFromBytes(message.recentBlockhash)
+	}
+	for _, inst := range message.instructions {
+		tx.Message.Instructions = append(tx.Message.Instructions, inst.toCompiled())
+	}
+	for _, lookup := range message.lookups {
+		if len(lookup.accountKey) != solana.PublicKeyLength {
+			return nil, fmt.Errorf("invalid lookup table key length %d", len(lookup.accountKey))
+		}
+		tx.Message.AddressTableLookups = append(tx.Message.AddressTableLookups, solana.MessageAddressTableLookup{
+			AccountKey:      solana.PublicKeyFromBytes(lookup.accountKey),
+			WritableIndexes: lookup.writableIndexes,
+			ReadonlyIndexes: lookup.readonlyIndexes,
+		})
+	}
+	if message.versioned {
+		tx.Message.SetVersion(solana.MessageVersionV0)
+	}
+	return tx, nil
+}
+
+func (i protoInstruction) toCompiled() solana.CompiledInstruction {
+	accounts := make([]uint16, len(i.accounts))
+	for j, index := range i.accounts {
+		accounts[j] = uint16(index)
+	}
+	return solana.CompiledInstruction{
+		ProgramIDIndex: uint16(i.programIDIndex),
+		Accounts:       accounts,
+		Data:           i.data,
+	}
+}
+
+func (m *protoMeta) toMeta() *rpc.TransactionMeta {
+	meta := &rpc.TransactionMeta{
+		Fee:                  m.fee,
+		PreBalances:          m.preBalances,
+		PostBalances:         m.postBalances,
+		LogMessages:          m.logMessages,
+		PreTokenBalances:     toTokenBalances(m.preTokenBalances),
+		PostTokenBalances:    toTokenBalances(m.postTokenBalances),
+		ComputeUnitsConsumed: m.computeUnitsConsumed,
+	}
+	if len(m.err) > 0 {
+		meta.Err = decodeTransactionError(m.err)
+	}
+	for _, inner := range m.innerInstructions {
+		converted := rpc.InnerInstruction{Index: uint16(inner.index)}
+		for _, inst := range inner.instructions {
+			converted.Instructions = append(converted.Instructions, inst.toCompiled())
+		}
+		meta.InnerInstructions = append(meta.InnerInstructions, converted)
+	}
+	for _, address := range m.loadedWritable {
+		meta.LoadedAddresses.Writable = append(meta.LoadedAddresses.Writable, solana.PublicKeyFromBytes(address))
+	}
+	for _, address := range m.loadedReadonly {
+		meta.LoadedAddresses.ReadOnly = append(meta.LoadedAddresses.ReadOnly, solana.PublicKeyFromBytes(address))
+	}
+	return meta
+}
+
+func toTokenBalances(balances []protoTokenBalance) []rpc.TokenBalance {
+	var out []rpc.TokenBalance
+	for _, balance := range balances {
+		converted := rpc.TokenBalance{
+			AccountIndex: uint16(balance.accountIndex),
+			UiTokenAmount: &rpc.UiTokenAmount{
+				Amount:         balance.amount,
+				Decimals:       uint8(balance.decimals),
+				UiAmountString: balance.uiAmountString,
+			},
+		}
+		if uiAmount := balance.uiAmount; balance.uiAmountString != "" {
+			converted.UiTokenAmount.UiAmount = &uiAmount
+		}
+		if mint, err := solana.PublicKeyFromBase58(balance.mint); err == nil {
+			converted.Mint = mint
+		}
+		if owner, err := solana.PublicKeyFromBase58(balance.owner); err == nil {
+			converted.Owner = &owner
+		}
+		if programID, err := solana.PublicKeyFromBase58(balance.programID); err == nil {
+			converted.ProgramId = &programID
+		}
+		out = append(out, converted)
+	}
+	return out
+}
+
+// transactionErrorNames are the TransactionError variants in bincode
+// order.
+var transactionErrorNames = []string{
+	"AccountInUse", "AccountLoadedTwice", "AccountNotFound", "ProgramAccountNotFound",
+	"InsufficientFundsForFee", "InvalidAccountForFee", "AlreadyProcessed", "BlockhashNotFound",
+	"InstructionError", "CallChainTooDeep", "MissingSignatureForFee", "InvalidAccountIndex",
+	"SignatureFailure", "InvalidProgramForExecution", "SanitizeFailure", "ClusterMaintenance",
+	"AccountBorrowOutstanding", "WouldExceedMaxBlockCostLimit", "UnsupportedVersion",
+	"InvalidWritableAccount", "WouldExceedMaxAccountCostLimit", "WouldExceedAccountDataBlockLimit",
+	"TooManyAccountLocks", "AddressLookupTableNotFound", "InvalidAddressLookupTableOwner",
+	"InvalidAddressLookupTableData", "InvalidAddressLookupTableIndex", "InvalidRentPayingAccount",
+	"WouldExceedMaxVoteCostLimit", "WouldExceedAccountDataTotalLimit", "DuplicateInstruction",
+	"InsufficientFundsForRent", "MaxLoadedAccountsDataSizeExceeded", "InvalidLoadedAccountsDataSizeLimit",
+	"ResanitizationNeeded", "ProgramExecutionTemporarilyRestricted", "UnbalancedTransaction",
+	"ProgramCacheHitMaxLimit",
+}
+
+// instructionErrorNames are the InstructionError variants in bincode
+// order, up to Custom.
+var instructionErrorNames = []string{
+	"GenericError", "InvalidArgument", "InvalidInstructionData", "InvalidAccountData",
+	"AccountDataTooSmall", "InsufficientFunds", "IncorrectProgramId", "MissingRequiredSignature",
+	"AccountAlreadyInitialized", "UninitializedAccount", "UnbalancedInstruction", "ModifiedProgramId",
+	"ExternalAccountLamportSpend", "ExternalAccountDataModified", "ReadonlyLamportChange",
+	"ReadonlyDataModified", "DuplicateAccountIndex", "ExecutableModified", "RentEpochModified",
+	"NotEnoughAccountKeys", "AccountDataSizeChanged", "AccountNotExecutable", "AccountBorrowFailed",
+	"AccountBorrowOutstanding", "DuplicateAccountOutOfSync", "Custom",
+}
+
+const (
+	transactionErrorInstruction = 8
+	instructionErrorCustom      = 25
+)
+
+// decodeTransactionError converts a bincode TransactionError into the JSON
+// form returned by RPC nodes, such as {"InstructionError":[1,{"Custom":1}]},
+// which token2022.DecodeTransactionError understands.
+func decodeTransactionError(data []byte) interface{} {
+	variant, err := readU32(data, 0)
+	if err != nil {
+		return fmt.Sprintf("TransactionError(%x)", data)
+	}
+	if variant != transactionErrorInstruction {
+		return variantName(transactionErrorNames, variant, "TransactionError")
+	}
+	if len(data) < 5 {
+		return "InstructionError"
+	}
+	index := float64(data[4])
+	inner, err := readU32(data, 5)
+	if err != nil {
+		return map[string]interface{}{"InstructionError": []interface{}{index, "InvalidError"}}
+	}
+	if inner == instructionErrorCustom {
+		code, err := readU32(data, 9)
+		if err == nil {
+			return map[string]interface{}{"InstructionError": []interface{}{index, map[string]interface{}{"Custom": float64(code)}}}
+		}
+	}
+	return map[string]interface{}{"InstructionError": []interface{}{index, variantName(instructionErrorNames, inner, "InstructionError")}}
+}
+
+func readU32(data []byte, offset int) (uint32, error) {
+	if len(data) < offset+4 {
+		return 0, errors.New("truncated")
+	}
+	return binary.LittleEndian.Uint32(data[offset:]), nil
+}
+
+func variantName(names []string, variant uint32, kind string) string {
+	if int(variant) < len(names) {
+		return names[variant]
+	}
+	return fmt.Sprintf("%s(%d)", kind, variant)
+}
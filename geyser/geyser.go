@@ -0,0 +1,265 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package geyser consumes a Yellowstone gRPC (Geyser) stream filtered to
+// Token-2022 accounts and transactions and emits the decoded types of the
+// token2022 package. It suits indexers that need every update, which
+// public websocket endpoints do not guarantee.
+package geyser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dwmfan/token2022"
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// subscribeMethod is the bidirectional streaming Subscribe method of the
+// geyser.Geyser service.
+const subscribeMethod = "/geyser.Geyser/Subscribe"
+
+// CommitmentLevel is the commitment of streamed updates.
+type CommitmentLevel int32
+
+const (
+	CommitmentProcessed CommitmentLevel = iota
+	CommitmentConfirmed
+	CommitmentFinalized
+)
+
+// Filter selects the updates of a subscription. The zero Filter streams
+// every Token-2022 account and every non-vote transaction that touches
+// the Token-2022 program, at confirmed commitment.
+type Filter struct {
+	// Accounts limits account updates to these accounts. By default every
+	// account owned by the Token-2022 program is streamed.
+	Accounts []solana.PublicKey
+	// Owners replaces the Token-2022 program as the owner of streamed
+	// accounts, for example to also stream the legacy Token program.
+	Owners []solana.PublicKey
+	// SkipAccounts and SkipTransactions disable either kind of update.
+	SkipAccounts     bool
+	SkipTransactions bool
+	// TransactionAccounts further limits transactions to those that
+	// include any of these accounts, such as a mint.
+	TransactionAccounts []solana.PublicKey
+	// SkipFailed drops failed transactions.
+	SkipFailed bool
+	Commitment *CommitmentLevel
+	// FromSlot replays updates from an earlier slot, when the server
+	// still has them.
+	FromSlot *uint64
+}
+
+func (f Filter) request() *subscribeRequest {
+	commitment := CommitmentConfirmed
+	if f.Commitment != nil {
+		commitment = *f.Commitment
+	}
+	req := &subscribeRequest{commitment: &commitment, fromSlot: f.FromSlot}
+	if !f.SkipAccounts {
+		filter := accountsFilter{owner: []string{solana.Token2022ProgramID.String()}}
+		if len(f.Owners) > 0 {
+			filter.owner = nil
+			for _, owner := range f.Owners {
+				filter.owner = append(filter.owner, owner.String())
+			}
+		}
+		for _, account := range f.Accounts {
+			filter.account = append(filter.account, account.String())
+		}
+		req.accounts = map[string]accountsFilter{"token2022": filter}
+	}
+	if !f.SkipTransactions {
+		vote := false
+		filter := transactionsFilter{vote: &vote, accountRequired: []string{solana.Token2022ProgramID.String()}}
+		if f.SkipFailed {
+			failed := false
+			filter.failed = &failed
+		}
+		for _, account := range f.TransactionAccounts {
+			filter.accountInclude = append(filter.accountInclude, account.String())
+		}
+		req.transactions = map[string]transactionsFilter{"token2022": filter}
+	}
+	return req
+}
+
+// Client subscribes to a Yellowstone gRPC endpoint.
+type Client struct {
+	conn  grpc.ClientConnInterface
+	token string
+}
+
+// NewClient creates a client on a gRPC connection, such as one from
+// grpc.NewClient with TLS credentials.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{conn: conn}
+}
+
+// SetToken sets the x-token sent with every subscription.
+func (c *Client) SetToken(token string) *Client {
+	c.token = token
+	return c
+}
+
+// Subscribe opens a stream with filter. The stream ends when ctx is done.
+func (c *Client) Subscribe(ctx context.Context, filter Filter) (*Stream, error) {
+	if c.token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-token", c.token)
+	}
+	stream, err := c.conn.NewStream(ctx, &grpc.StreamDesc{
+		StreamName:    "Subscribe",
+		ServerStreams: true,
+		ClientStreams: true,
+	}, subscribeMethod, grpc.ForceCodec(rawCodec{}))
+	if err != nil {
+		return nil, fmt.Errorf("error while opening subscription: %w", err)
+	}
+	if err := stream.SendMsg(rawMessage(filter.request().marshal())); err != nil {
+		return nil, fmt.Errorf("error while sending subscription: %w", err)
+	}
+	return &Stream{stream: stream}, nil
+}
+
+// Stream is an open subscription. Recv must not be called concurrently.
+type Stream struct {
+	stream grpc.ClientStream
+}
+
+// Event is an *AccountEvent or a *TransactionEvent.
+type Event interface {
+	EventSlot() uint64
+}
+
+// AccountEvent is an update of an account owned by the Token-2022
+// program. Exactly one of Mint and TokenAccount is set, or neither for
+// other accounts such as multisigs.
+type AccountEvent struct {
+	Pubkey       solana.PublicKey
+	Slot         uint64
+	Lamports     uint64
+	WriteVersion uint64
+	// Owner is the program owning the account.
+	Owner solana.PublicKey
+	// Signature is the transaction that wrote the account, when known.
+	Signature solana.Signature
+	// IsStartup is set for the initial snapshot sent on connect.
+	IsStartup    bool
+	Data         []byte
+	Mint         *token2022.Mint
+	TokenAccount *token2022.TokenAccount
+}
+
+func (e *AccountEvent) EventSlot() uint64 { return e.Slot }
+
+// TransactionEvent is a transaction that touched the Token-2022 program.
+type TransactionEvent struct {
+	Slot        uint64
+	Index       uint64
+	Signature   solana.Signature
+	Transaction *solana.Transaction
+	Meta        *rpc.TransactionMeta
+	// Parsed holds the decoded Token-2022 and Associated Token Account
+	// instructions, including inner ones.
+	Parsed *token2022.ParsedTransaction
+}
+
+func (e *TransactionEvent) EventSlot() uint64 { return e.Slot }
+
+// Recv returns the next event. Pings are answered and skipped.
+func (s *Stream) Recv() (Event, error) {
+	for {
+		var msg rawMessage
+		if err := s.stream.RecvMsg(&msg); err != nil {
+			return nil, err
+		}
+		update, err := unmarshalUpdate(msg)
+		if err != nil {
+			return nil, fmt.Errorf("error while decoding update: %w", err)
+		}
+		switch {
+		case update.account != nil:
+			return accountEvent(update.account), nil
+		case update.transaction != nil:
+			return transactionEvent(update.transaction)
+		case update.ping:
+			// Answer so proxies keep the stream open.
+			id := int32(1)
+			if err := s.stream.SendMsg(rawMessage((&subscribeRequest{ping: &id}).marshal())); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// Close ends the client side of the stream.
+func (s *Stream) Close() error {
+	return s.stream.CloseSend()
+}
+
+func accountEvent(update *accountUpdate) *AccountEvent {
+	event := &AccountEvent{
+		Pubkey:       solana.PublicKeyFromBytes(update.pubkey),
+		Slot:         update.slot,
+		Lamports:     update.lamports,
+		WriteVersion: update.writeVersion,
+		IsStartup:    update.isStartup,
+		Data:         update.data,
+	}
+	if len(update.txnSignature) == solana.SignatureLength {
+		event.Signature = solana.SignatureFromBytes(update.txnSignature)
+	}
+	if len(update.owner) == solana.PublicKeyLength {
+		event.Owner = solana.PublicKeyFromBytes(update.owner)
+	}
+	switch token2022.AccountTypeOf(update.data) {
+	case token2022.AccountTypeMint:
+		event.Mint, _ = token2022.DecodeMint(update.data)
+	case token2022.AccountTypeAccount:
+		event.TokenAccount, _ = token2022.DecodeTokenAccount(update.data)
+	}
+	return event
+}
+
+func transactionEvent(update *transactionUpdate) (*TransactionEvent, error) {
+	if update.transaction == nil || update.meta == nil {
+		return nil, errors.New("transaction update without transaction or meta")
+	}
+	tx, err := update.transaction.toTransaction()
+	if err != nil {
+		return nil, err
+	}
+	meta := update.meta.toMeta()
+	parsed, err := token2022.ParseTransaction(tx, meta)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing transaction: %w", err)
+	}
+	event := &TransactionEvent{
+		Slot:        update.slot,
+		Index:       update.index,
+		Transaction: tx,
+		Meta:        meta,
+		Parsed:      parsed,
+	}
+	if len(update.signature) == solana.SignatureLength {
+		event.Signature = solana.SignatureFromBytes(update.signature)
+	}
+	return event, nil
+}
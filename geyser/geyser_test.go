@@ -0,0 +1,271 @@
+package geyser
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/dwmfan/token2022"
+	solana "github.com/gagliardetto/solana-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// message builds a protobuf message for the mock server.
+type message []byte
+
+func (m message) bytes(num protowire.Number, value []byte) message {
+	m = protowire.AppendTag(m, num, protowire.BytesType)
+	return protowire.AppendBytes(m, value)
+}
+
+func (m message) varint(num protowire.Number, value uint64) message {
+	m = protowire.AppendTag(m, num, protowire.VarintType)
+	return protowire.AppendVarint(m, value)
+}
+
+func encodeAccountUpdate(pubkey solana.PublicKey, slot uint64, data []byte) []byte {
+	info := message(nil).
+		bytes(1, pubkey[:]).
+		varint(2, 2_039_280).
+		bytes(3, solana.Token2022ProgramID[:]).
+		bytes(6, data).
+		varint(7, 99)
+	account := message(nil).bytes(1, info).varint(2, slot)
+	return message(nil).bytes(1, []byte("token2022")).bytes(updateAccount, account)
+}
+
+func encodeTransactionUpdate(tx *solana.Transaction, slot uint64, err []byte) []byte {
+	header := message(nil).
+		varint(1, uint64(tx.Message.Header.NumRequiredSignatures)).
+		varint(2, uint64(tx.Message.Header.NumReadonlySignedAccounts)).
+		varint(3, uint64(tx.Message.Header.NumReadonlyUnsignedAccounts))
+	msg := message(nil).bytes(1, header)
+	for _, key := range tx.Message.AccountKeys {
+		msg = msg.bytes(2, key[:])
+	}
+	msg = msg.bytes(3, tx.Message.RecentBlockhash[:])
+	for _, inst := range tx.Message.Instructions {
+		accounts := make([]byte, len(inst.Accounts))
+		for i, index := range inst.Accounts {
+			accounts[i] = byte(index)
+		}
+		msg = msg.bytes(4, message(nil).varint(1, uint64(inst.ProgramIDIndex)).bytes(2, accounts).bytes(3, inst.Data))
+	}
+	transaction := message(nil)
+	for _, sig := range tx.Signatures {
+		transaction = transaction.bytes(1, sig[:])
+	}
+	transaction = transaction.bytes(2, msg)
+
+	var balances []byte
+	balances = protowire.AppendVarint(balances, 5000)
+	balances = protowire.AppendVarint(balances, 0)
+	meta := message(nil).varint(2, 5000).bytes(3, balances).bytes(6, []byte("Program log: Instruction: TransferChecked"))
+	if err != nil {
+		meta = meta.bytes(1, message(nil).bytes(1, err))
+	}
+
+	info := message(nil).bytes(1, tx.Signatures[0][:]).bytes(3, transaction).bytes(4, meta).varint(5, 3)
+	return message(nil).bytes(updateTransaction, message(nil).bytes(1, info).varint(2, slot))
+}
+
+// mockGeyser serves the Subscribe method: it records the first request
+// and the metadata, sends updates, then forwards later requests.
+type mockGeyser struct {
+	updates  [][]byte
+	requests chan []byte
+	token    chan string
+}
+
+func (m *mockGeyser) handle(srv interface{}, stream grpc.ServerStream) error {
+	method, _ := grpc.MethodFromServerStream(stream)
+	if method != subscribeMethod {
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	m.token <- firstOf(md.Get("x-token"))
+	for _, update := range m.updates {
+		if err := stream.SendMsg(rawMessage(update)); err != nil {
+			return err
+		}
+	}
+	for {
+		var req rawMessage
+		if err := stream.RecvMsg(&req); err != nil {
+			return nil
+		}
+		m.requests <- req
+	}
+}
+
+func firstOf(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func newMockGeyser(t *testing.T, updates ...[]byte) (*mockGeyser, *Client) {
+	mock := &mockGeyser{updates: updates, requests: make(chan []byte, 8), token: make(chan string, 1)}
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(grpc.ForceServerCodec(rawCodec{}), grpc.UnknownServiceHandler(mock.handle))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Error dialing: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return mock, NewClient(conn).SetToken("secret")
+}
+
+func TestSubscribe(t *testing.T) {
+
+	var (
+		source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		owner       = solana.NewWallet().PrivateKey
+	)
+
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{token2022.NewTransferChecked2022Instruction(250, 6, source, mint, destination, owner.PublicKey()).Build()},
+		solana.Hash{7},
+		solana.TransactionPayer(owner.PublicKey()),
+	)
+	if err != nil {
+		t.Fatalf("Error building transaction: %v", err)
+	}
+	if _, err := tx.Sign(func(solana.PublicKey) *solana.PrivateKey { return &owner }); err != nil {
+		t.Fatalf("Error signing: %v", err)
+	}
+
+	// InstructionError(0, Custom(1)) in bincode.
+	custom := binary.LittleEndian.AppendUint32(nil, transactionErrorInstruction)
+	custom = append(custom, 0)
+	custom = binary.LittleEndian.AppendUint32(custom, instructionErrorCustom)
+	custom = binary.LittleEndian.AppendUint32(custom, 1)
+
+	mock, client := newMockGeyser(t,
+		message(nil).bytes(updatePing, nil),
+		encodeAccountUpdate(destination, 41, token2022.EncodeTokenAccount(&token2022.TokenAccount{Mint: mint, Owner: owner.PublicKey(), Amount: 250})),
+		encodeAccountUpdate(mint, 41, token2022.EncodeMint(&token2022.Mint{Decimals: 6, IsInitialized: true})),
+		encodeTransactionUpdate(tx, 42, custom),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.Subscribe(ctx, Filter{SkipFailed: true})
+	if err != nil {
+		t.Fatalf("Error subscribing: %v", err)
+	}
+	if token := <-mock.token; token != "secret" {
+		t.Errorf("Expected x-token secret, got %q", token)
+	}
+	checkRequest(t, <-mock.requests)
+
+	event, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Error receiving: %v", err)
+	}
+	account, ok := event.(*AccountEvent)
+	if !ok || !account.Pubkey.Equals(destination) || !account.Owner.Equals(solana.Token2022ProgramID) || account.TokenAccount == nil || account.TokenAccount.Amount != 250 || account.WriteVersion != 99 {
+		t.Errorf("Unexpected event %+v", event)
+	}
+	if ping := <-mock.requests; len(ping) == 0 {
+		t.Errorf("Expected the ping to be answered")
+	}
+
+	event, err = stream.Recv()
+	if err != nil {
+		t.Fatalf("Error receiving: %v", err)
+	}
+	if account, ok := event.(*AccountEvent); !ok || account.Mint == nil || account.Mint.Decimals != 6 {
+		t.Errorf("Expected mint event, got %+v", event)
+	}
+
+	event, err = stream.Recv()
+	if err != nil {
+		t.Fatalf("Error receiving: %v", err)
+	}
+	txEvent, ok := event.(*TransactionEvent)
+	if !ok {
+		t.Fatalf("Expected transaction event, got %T", event)
+	}
+	if txEvent.Slot != 42 || txEvent.Index != 3 || txEvent.Signature != tx.Signatures[0] || txEvent.Meta.Fee != 5000 {
+		t.Errorf("Unexpected transaction event %+v", txEvent)
+	}
+	if len(txEvent.Parsed.Instructions) != 1 || txEvent.Parsed.Instructions[0].Name != "TransferChecked" {
+		t.Errorf("Unexpected parsed instructions %+v", txEvent.Parsed.Instructions)
+	}
+	if txEvent.Parsed.Err == nil || txEvent.Parsed.Err.Custom == nil || *txEvent.Parsed.Err.Custom != 1 {
+		t.Errorf("Expected custom error 1, got %v", txEvent.Parsed.Err)
+	}
+}
+
+// checkRequest checks the subscription asks for Token-2022 accounts and
+// non-vote, successful Token-2022 transactions at confirmed commitment.
+func checkRequest(t *testing.T, req []byte) {
+	fields := map[protowire.Number]bool{}
+	var owner, required string
+	var commitment uint64 = 99
+	err := consumeMessage(req, func(num protowire.Number, typ protowire.Type, b []byte) int {
+		fields[num] = true
+		switch num {
+		case requestAccounts, requestTransactions:
+			return readMessage(typ, b, func(entryNum protowire.Number, typ protowire.Type, b []byte) int {
+				if entryNum != 2 {
+					return skipField
+				}
+				return readMessage(typ, b, func(field protowire.Number, typ protowire.Type, b []byte) int {
+					switch {
+					case num == requestAccounts && field == 3:
+						return readString(typ, b, &owner)
+					case num == requestTransactions && field == 6:
+						return readString(typ, b, &required)
+					}
+					return skipField
+				})
+			})
+		case requestCommitment:
+			return readVarint(typ, b, &commitment)
+		}
+		return skipField
+	})
+	if err != nil {
+		t.Fatalf("Error decoding request: %v", err)
+	}
+	if owner != solana.Token2022ProgramID.String() || required != solana.Token2022ProgramID.String() {
+		t.Errorf("Expected Token-2022 filters, got owner %q, required %q", owner, required)
+	}
+	if commitment != uint64(CommitmentConfirmed) {
+		t.Errorf("Expected confirmed commitment, got %d", commitment)
+	}
+}
+
+func TestDecodeTransactionError(t *testing.T) {
+	cases := []struct {
+		data []byte
+		want string
+	}{
+		{binary.LittleEndian.AppendUint32(nil, 7), "BlockhashNotFound"},
+		{append(binary.LittleEndian.AppendUint32(nil, 8), 2, 19, 0, 0, 0), "map[InstructionError:[2 NotEnoughAccountKeys]]"},
+		{binary.LittleEndian.AppendUint32(nil, 200), "TransactionError(200)"},
+	}
+	for _, c := range cases {
+		if got := fmt.Sprint(decodeTransactionError(c.data)); got != c.want {
+			t.Errorf("Expected %s, got %s", c.want, got)
+		}
+	}
+}
@@ -0,0 +1,594 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geyser
+
+import (
+	"fmt"
+	"math"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// This file encodes and decodes the subset of the Yellowstone geyser.proto
+// and solana-storage.proto messages used by the consumer, so the package
+// does not depend on generated code. Field numbers follow the upstream
+// definitions; unknown fields are skipped.
+
+// Field numbers of SubscribeRequest.
+const (
+	requestAccounts     = 1
+	requestTransactions = 3
+	requestCommitment   = 6
+	requestPing         = 9
+	requestFromSlot     = 11
+)
+
+// Field numbers of SubscribeUpdate.
+const (
+	updateAccount     = 2
+	updateTransaction = 4
+	updatePing        = 6
+)
+
+// subscribeRequest is a SubscribeRequest.
+type subscribeRequest struct {
+	accounts     map[string]accountsFilter
+	transactions map[string]transactionsFilter
+	commitment   *CommitmentLevel
+	fromSlot     *uint64
+	ping         *int32
+}
+
+// accountsFilter is a SubscribeRequestFilterAccounts.
+type accountsFilter struct {
+	account []string
+	owner   []string
+}
+
+// transactionsFilter is a SubscribeRequestFilterTransactions.
+type transactionsFilter struct {
+	vote            *bool
+	failed          *bool
+	accountInclude  []string
+	accountRequired []string
+}
+
+func (r *subscribeRequest) marshal() []byte {
+	var b []byte
+	for name, filter := range r.accounts {
+		var value []byte
+		for _, account := range filter.account {
+			value = protowire.AppendTag(value, 2, protowire.BytesType)
+			value = protowire.AppendString(value, account)
+		}
+		for _, owner := range filter.owner {
+			value = protowire.AppendTag(value, 3, protowire.BytesType)
+			value = protowire.AppendString(value, owner)
+		}
+		b = appendMapEntry(b, requestAccounts, name, value)
+	}
+	for name, filter := range r.transactions {
+		var value []byte
+		value = appendOptionalBool(value, 1, filter.vote)
+		value = appendOptionalBool(value, 2, filter.failed)
+		for _, account := range filter.accountInclude {
+			value = protowire.AppendTag(value, 3, protowire.BytesType)
+			value = protowire.AppendString(value, account)
+		}
+		for _, account := range filter.accountRequired {
+			value = protowire.AppendTag(value, 6, protowire.BytesType)
+			value = protowire.AppendString(value, account)
+		}
+		b = appendMapEntry(b, requestTransactions, name, value)
+	}
+	if r.commitment != nil {
+		b = protowire.AppendTag(b, requestCommitment, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(*r.commitment))
+	}
+	if r.ping != nil {
+		var ping []byte
+		ping = protowire.AppendTag(ping, 1, protowire.VarintType)
+		ping = protowire.AppendVarint(ping, uint64(*r.ping))
+		b = protowire.AppendTag(b, requestPing, protowire.BytesType)
+		b = protowire.AppendBytes(b, ping)
+	}
+	if r.fromSlot != nil {
+		b = protowire.AppendTag(b, requestFromSlot, protowire.VarintType)
+		b = protowire.AppendVarint(b, *r.fromSlot)
+	}
+	return b
+}
+
+// appendMapEntry appends an entry of a map<string, Message> field.
+func appendMapEntry(b []byte, field protowire.Number, key string, value []byte) []byte {
+	var entry []byte
+	entry = protowire.AppendTag(entry, 1, protowire.BytesType)
+	entry = protowire.AppendString(entry, key)
+	entry = protowire.AppendTag(entry, 2, protowire.BytesType)
+	entry = protowire.AppendBytes(entry, value)
+	b = protowire.AppendTag(b, field, protowire.BytesType)
+	return protowire.AppendBytes(b, entry)
+}
+
+func appendOptionalBool(b []byte, field protowire.Number, value *bool) []byte {
+	if value == nil {
+		return b
+	}
+	b = protowire.AppendTag(b, field, protowire.VarintType)
+	return protowire.AppendVarint(b, protowire.EncodeBool(*value))
+}
+
+// subscribeUpdate is a SubscribeUpdate. At most one of account,
+// transaction and ping is set.
+type subscribeUpdate struct {
+	account     *accountUpdate
+	transaction *transactionUpdate
+	ping        bool
+}
+
+// accountUpdate is a SubscribeUpdateAccount with its account info.
+type accountUpdate struct {
+	pubkey       []byte
+	lamports     uint64
+	owner        []byte
+	executable   bool
+	rentEpoch    uint64
+	data         []byte
+	writeVersion uint64
+	txnSignature []byte
+	slot         uint64
+	isStartup    bool
+}
+
+// transactionUpdate is a SubscribeUpdateTransaction with its transaction
+// info.
+type transactionUpdate struct {
+	signature   []byte
+	isVote      bool
+	transaction *protoTransaction
+	meta        *protoMeta
+	index       uint64
+	slot        uint64
+}
+
+type protoTransaction struct {
+	signatures [][]byte
+	message    protoMessage
+}
+
+type protoMessage struct {
+	header          [3]uint64
+	accountKeys     [][]byte
+	recentBlockhash []byte
+	instructions    []protoInstruction
+	versioned       bool
+	lookups         []protoLookup
+}
+
+type protoInstruction struct {
+	programIDIndex uint64
+	accounts       []byte
+	data           []byte
+	stackHeight    *uint64
+}
+
+type protoLookup struct {
+	accountKey      []byte
+	writableIndexes []byte
+	readonlyIndexes []byte
+}
+
+type protoMeta struct {
+	err                  []byte
+	fee                  uint64
+	preBalances          []uint64
+	postBalances         []uint64
+	innerInstructions    []protoInnerInstructions
+	logMessages          []string
+	preTokenBalances     []protoTokenBalance
+	postTokenBalances    []protoTokenBalance
+	loadedWritable       [][]byte
+	loadedReadonly       [][]byte
+	computeUnitsConsumed *uint64
+}
+
+type protoInnerInstructions struct {
+	index        uint64
+	instructions []protoInstruction
+}
+
+type protoTokenBalance struct {
+	accountIndex   uint64
+	mint           string
+	uiAmount       float64
+	decimals       uint64
+	amount         string
+	uiAmountString string
+	owner          string
+	programID      string
+}
+
+// fieldFunc handles one field of a message, returning the number of
+// bytes consumed, a negative protowire error code, or skipField.
+type fieldFunc func(num protowire.Number, typ protowire.Type, b []byte) int
+
+const (
+	// skipField makes consumeMessage skip a field.
+	skipField = math.MinInt32
+	// errMalformed reports an invalid embedded message.
+	errMalformed = -100
+)
+
+// consumeMessage walks the fields of a message, calling fn for each.
+func consumeMessage(b []byte, fn fieldFunc) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		n = fn(num, typ, b)
+		if n == skipField {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return fmt.Errorf("field %d: %w", num, protowire.ParseError(n))
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+// Field readers. Each returns the number of bytes consumed, or skipField
+// for a field of an unexpected wire type.
+
+func readVarint(typ protowire.Type, b []byte, v *uint64) int {
+	if typ != protowire.VarintType {
+		return skipField
+	}
+	value, n := protowire.ConsumeVarint(b)
+	*v = value
+	return n
+}
+
+func readBool(typ protowire.Type, b []byte, v *bool) int {
+	var value uint64
+	n := readVarint(typ, b, &value)
+	*v = protowire.DecodeBool(value)
+	return n
+}
+
+func readBytes(typ protowire.Type, b []byte, v *[]byte) int {
+	if typ != protowire.BytesType {
+		return skipField
+	}
+	value, n := protowire.ConsumeBytes(b)
+	*v = value
+	return n
+}
+
+func readString(typ protowire.Type, b []byte, v *string) int {
+	var value []byte
+	n := readBytes(typ, b, &value)
+	*v = string(value)
+	return n
+}
+
+func readDouble(typ protowire.Type, b []byte, v *float64) int {
+	if typ != protowire.Fixed64Type {
+		return skipField
+	}
+	value, n := protowire.ConsumeFixed64(b)
+	*v = math.Float64frombits(value)
+	return n
+}
+
+// readMessage decodes an embedded message with fn.
+func readMessage(typ protowire.Type, b []byte, fn fieldFunc) int {
+	var value []byte
+	n := readBytes(typ, b, &value)
+	if n < 0 {
+		return n
+	}
+	if err := consumeMessage(value, fn); err != nil {
+		return errMalformed
+	}
+	return n
+}
+
+// readRepeatedVarint reads a packed or unpacked repeated varint field.
+func readRepeatedVarint(typ protowire.Type, b []byte, v *[]uint64) int {
+	switch typ {
+	case protowire.VarintType:
+		value, n := protowire.ConsumeVarint(b)
+		*v = append(*v, value)
+		return n
+	case protowire.BytesType:
+		packed, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return n
+		}
+		for len(packed) > 0 {
+			value, m := protowire.ConsumeVarint(packed)
+			if m < 0 {
+				return m
+			}
+			*v = append(*v, value)
+			packed = packed[m:]
+		}
+		return n
+	}
+	return skipField
+}
+
+func unmarshalUpdate(b []byte) (*subscribeUpdate, error) {
+	update := &subscribeUpdate{}
+	err := consumeMessage(b, func(num protowire.Number, typ protowire.Type, b []byte) int {
+		switch num {
+		case updateAccount:
+			update.account = &accountUpdate{}
+			return readMessage(typ, b, update.account.field)
+		case updateTransaction:
+			update.transaction = &transactionUpdate{}
+			return readMessage(typ, b, update.transaction.field)
+		case updatePing:
+			update.ping = true
+		}
+		return skipField
+	})
+	if err != nil {
+		return nil, err
+	}
+	return update, nil
+}
+
+func (u *accountUpdate) field(num protowire.Number, typ protowire.Type, b []byte) int {
+	switch num {
+	case 1:
+		return readMessage(typ, b, u.infoField)
+	case 2:
+		return readVarint(typ, b, &u.slot)
+	case 3:
+		return readBool(typ, b, &u.isStartup)
+	}
+	return skipField
+}
+
+func (u *accountUpdate) infoField(num protowire.Number, typ protowire.Type, b []byte) int {
+	switch num {
+	case 1:
+		return readBytes(typ, b, &u.pubkey)
+	case 2:
+		return readVarint(typ, b, &u.lamports)
+	case 3:
+		return readBytes(typ, b, &u.owner)
+	case 4:
+		return readBool(typ, b, &u.executable)
+	case 5:
+		return readVarint(typ, b, &u.rentEpoch)
+	case 6:
+		return readBytes(typ, b, &u.data)
+	case 7:
+		return readVarint(typ, b, &u.writeVersion)
+	case 8:
+		return readBytes(typ, b, &u.txnSignature)
+	}
+	return skipField
+}
+
+func (u *transactionUpdate) field(num protowire.Number, typ protowire.Type, b []byte) int {
+	switch num {
+	case 1:
+		return readMessage(typ, b, u.infoField)
+	case 2:
+		return readVarint(typ, b, &u.slot)
+	}
+	return skipField
+}
+
+func (u *transactionUpdate) infoField(num protowire.Number, typ protowire.Type, b []byte) int {
+	switch num {
+	case 1:
+		return readBytes(typ, b, &u.signature)
+	case 2:
+		return readBool(typ, b, &u.isVote)
+	case 3:
+		u.transaction = &protoTransaction{}
+		return readMessage(typ, b, u.transaction.field)
+	case 4:
+		u.meta = &protoMeta{}
+		return readMessage(typ, b, u.meta.field)
+	case 5:
+		return readVarint(typ, b, &u.index)
+	}
+	return skipField
+}
+
+func (t *protoTransaction) field(num protowire.Number, typ protowire.Type, b []byte) int {
+	switch num {
+	case 1:
+		var signature []byte
+		n := readBytes(typ, b, &signature)
+		if n >= 0 {
+			t.signatures = append(t.signatures, signature)
+		}
+		return n
+	case 2:
+		return readMessage(typ, b, t.message.field)
+	}
+	return skipField
+}
+
+func (m *protoMessage) field(num protowire.Number, typ protowire.Type, b []byte) int {
+	switch num {
+	case 1:
+		return readMessage(typ, b, func(num protowire.Number, typ protowire.Type, b []byte) int {
+			if num >= 1 && num <= 3 {
+				return readVarint(typ, b, &m.header[num-1])
+			}
+			return skipField
+		})
+	case 2:
+		var key []byte
+		n := readBytes(typ, b, &key)
+		if n >= 0 {
+			m.accountKeys = append(m.accountKeys, key)
+		}
+		return n
+	case 3:
+		return readBytes(typ, b, &m.recentBlockhash)
+	case 4:
+		var inst protoInstruction
+		n := readMessage(typ, b, inst.field)
+		if n >= 0 {
+			m.instructions = append(m.instructions, inst)
+		}
+		return n
+	case 5:
+		return readBool(typ, b, &m.versioned)
+	case 6:
+		var lookup protoLookup
+		n := readMessage(typ, b, func(num protowire.Number, typ protowire.Type, b []byte) int {
+			switch num {
+			case 1:
+				return readBytes(typ, b, &lookup.accountKey)
+			case 2:
+				return readBytes(typ, b, &lookup.writableIndexes)
+			case 3:
+				return readBytes(typ, b, &lookup.readonlyIndexes)
+			}
+			return skipField
+		})
+		if n >= 0 {
+			m.lookups = append(m.lookups, lookup)
+		}
+		return n
+	}
+	return skipField
+}
+
+func (i *protoInstruction) field(num protowire.Number, typ protowire.Type, b []byte) int {
+	switch num {
+	case 1:
+		return readVarint(typ, b, &i.programIDIndex)
+	case 2:
+		return readBytes(typ, b, &i.accounts)
+	case 3:
+		return readBytes(typ, b, &i.data)
+	case 4:
+		i.stackHeight = new(uint64)
+		return readVarint(typ, b, i.stackHeight)
+	}
+	return skipField
+}
+
+func (m *protoMeta) field(num protowire.Number, typ protowire.Type, b []byte) int {
+	switch num {
+	case 1:
+		return readMessage(typ, b, func(num protowire.Number, typ protowire.Type, b []byte) int {
+			if num == 1 {
+				return readBytes(typ, b, &m.err)
+			}
+			return skipField
+		})
+	case 2:
+		return readVarint(typ, b, &m.fee)
+	case 3:
+		return readRepeatedVarint(typ, b, &m.preBalances)
+	case 4:
+		return readRepeatedVarint(typ, b, &m.postBalances)
+	case 5:
+		var inner protoInnerInstructions
+		n := readMessage(typ, b, func(num protowire.Number, typ protowire.Type, b []byte) int {
+			switch num {
+			case 1:
+				return readVarint(typ, b, &inner.index)
+			case 2:
+				var inst protoInstruction
+				n := readMessage(typ, b, inst.field)
+				if n >= 0 {
+					inner.instructions = append(inner.instructions, inst)
+				}
+				return n
+			}
+			return skipField
+		})
+		if n >= 0 {
+			m.innerInstructions = append(m.innerInstructions, inner)
+		}
+		return n
+	case 6:
+		var log string
+		n := readString(typ, b, &log)
+		if n >= 0 {
+			m.logMessages = append(m.logMessages, log)
+		}
+		return n
+	case 7, 8:
+		var balance protoTokenBalance
+		n := readMessage(typ, b, balance.field)
+		if n >= 0 {
+			if num == 7 {
+				m.preTokenBalances = append(m.preTokenBalances, balance)
+			} else {
+				m.postTokenBalances = append(m.postTokenBalances, balance)
+			}
+		}
+		return n
+	case 12, 13:
+		var address []byte
+		n := readBytes(typ, b, &address)
+		if n >= 0 {
+			if num == 12 {
+				m.loadedWritable = append(m.loadedWritable, address)
+			} else {
+				m.loadedReadonly = append(m.loadedReadonly, address)
+			}
+		}
+		return n
+	case 16:
+		m.computeUnitsConsumed = new(uint64)
+		return readVarint(typ, b, m.computeUnitsConsumed)
+	}
+	return skipField
+}
+
+func (t *protoTokenBalance) field(num protowire.Number, typ protowire.Type, b []byte) int {
+	switch num {
+	case 1:
+		return readVarint(typ, b, &t.accountIndex)
+	case 2:
+		return readString(typ, b, &t.mint)
+	case 3:
+		return readMessage(typ, b, func(num protowire.Number, typ protowire.Type, b []byte) int {
+			switch num {
+			case 1:
+				return readDouble(typ, b, &t.uiAmount)
+			case 2:
+				return readVarint(typ, b, &t.decimals)
+			case 3:
+				return readString(typ, b, &t.amount)
+			case 4:
+				return readString(typ, b, &t.uiAmountString)
+			}
+			return skipField
+		})
+	case 4:
+		return readString(typ, b, &t.owner)
+	case 5:
+		return readString(typ, b, &t.programID)
+	}
+	return skipField
+}
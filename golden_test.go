@@ -0,0 +1,184 @@
+package token2022
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// goldenInstruction is a serialized instruction from
+// testdata/golden/instructions.json.
+type goldenInstruction struct {
+	Name     string                     `json:"name"`
+	Data     string                     `json:"data"`
+	Accounts []accountMetaJSON          `json:"accounts"`
+	Fields   map[string]json.RawMessage `json:"fields"`
+}
+
+// goldenAccount is a serialized account from testdata/golden/accounts.json.
+type goldenAccount struct {
+	Name   string                     `json:"name"`
+	Kind   string                     `json:"kind"`
+	Data   string                     `json:"data"`
+	Fields map[string]json.RawMessage `json:"fields"`
+}
+
+func loadGolden(tb testing.TB, name string, v interface{}) {
+	tb.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "golden", name))
+	if err != nil {
+		tb.Fatalf("reading golden file: %v", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		tb.Fatalf("parsing %s: %v", name, err)
+	}
+}
+
+// checkGoldenFields compares the expected fields with the JSON encoding of v.
+func checkGoldenFields(t *testing.T, label string, v interface{}, want map[string]json.RawMessage) {
+	t.Helper()
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		t.Errorf("%s: MarshalJSON: %v", label, err)
+		return
+	}
+	var got map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &got); err != nil {
+		t.Errorf("%s: %v", label, err)
+		return
+	}
+	for key, wantValue := range want {
+		gotValue, ok := got[key]
+		if !ok {
+			t.Errorf("%s: missing field %q in %s", label, key, encoded)
+			continue
+		}
+		if !jsonEqual(gotValue, wantValue) {
+			t.Errorf("%s: field %q is %s, want %s", label, key, gotValue, wantValue)
+		}
+	}
+}
+
+func jsonEqual(a, b json.RawMessage) bool {
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return fmt.Sprint(va) == fmt.Sprint(vb)
+}
+
+func TestGoldenInstructions(t *testing.T) {
+	var vectors []goldenInstruction
+	loadGolden(t, "instructions.json", &vectors)
+
+	for i, vector := range vectors {
+		label := fmt.Sprintf("%d %s", i, vector.Name)
+		data, err := hex.DecodeString(vector.Data)
+		if err != nil {
+			t.Fatalf("%s: %v", label, err)
+		}
+		accounts := make([]*solana.AccountMeta, len(vector.Accounts))
+		for j, meta := range vector.Accounts {
+			accounts[j] = &solana.AccountMeta{PublicKey: meta.PublicKey, IsSigner: meta.IsSigner, IsWritable: meta.IsWritable}
+		}
+
+		decoded, err := DecodeInstruction(accounts, data)
+		if err != nil {
+			t.Errorf("%s: DecodeInstruction: %v", label, err)
+			continue
+		}
+		checkGoldenFields(t, label, decoded, vector.Fields)
+
+		built := decoded.Build()
+		encoded, err := built.Data()
+		if err != nil {
+			t.Errorf("%s: Data: %v", label, err)
+			continue
+		}
+		if !bytes.Equal(encoded, data) {
+			t.Errorf("%s: encoded %x, want %x", label, encoded, data)
+		}
+		if !built.ProgramID().Equals(solana.Token2022ProgramID) {
+			t.Errorf("%s: program %s", label, built.ProgramID())
+		}
+		builtAccounts := built.Accounts()
+		if len(builtAccounts) != len(accounts) {
+			t.Errorf("%s: built %d accounts, want %d", label, len(builtAccounts), len(accounts))
+			continue
+		}
+		for j, want := range accounts {
+			if got := builtAccounts[j]; !got.PublicKey.Equals(want.PublicKey) || got.IsSigner != want.IsSigner || got.IsWritable != want.IsWritable {
+				t.Errorf("%s: account %d is %s (signer %v, writable %v), want %s (signer %v, writable %v)",
+					label, j, got.PublicKey, got.IsSigner, got.IsWritable, want.PublicKey, want.IsSigner, want.IsWritable)
+			}
+		}
+	}
+}
+
+func TestGoldenAccounts(t *testing.T) {
+	var vectors []goldenAccount
+	loadGolden(t, "accounts.json", &vectors)
+
+	for i, vector := range vectors {
+		label := fmt.Sprintf("%d %s", i, vector.Name)
+		data, err := hex.DecodeString(vector.Data)
+		if err != nil {
+			t.Fatalf("%s: %v", label, err)
+		}
+
+		var decoded interface{}
+		var extensions []Extension
+		switch vector.Kind {
+		case "mint":
+			mint, err := DecodeMint(data)
+			if err != nil {
+				t.Errorf("%s: DecodeMint: %v", label, err)
+				continue
+			}
+			decoded, extensions = mint, mint.Extensions
+			if want, ok := vector.Fields["tokenMetadata"]; ok {
+				metadata, _, err := mint.TokenMetadata()
+				if err != nil {
+					t.Errorf("%s: TokenMetadata: %v", label, err)
+				}
+				got, _ := json.Marshal(metadata)
+				if !jsonEqual(got, want) {
+					t.Errorf("%s: token metadata is %s, want %s", label, got, want)
+				}
+			}
+		case "account":
+			account, err := DecodeTokenAccount(data)
+			if err != nil {
+				t.Errorf("%s: DecodeTokenAccount: %v", label, err)
+				continue
+			}
+			decoded, extensions = account, account.Extensions
+		default:
+			t.Fatalf("%s: unknown kind %q", label, vector.Kind)
+		}
+
+		fields := map[string]json.RawMessage{}
+		for key, value := range vector.Fields {
+			fields[key] = value
+		}
+		if want, ok := fields["extensionTypes"]; ok {
+			types := make([]ExtensionType, len(extensions))
+			for j, ext := range extensions {
+				types[j] = ext.Type
+			}
+			got, _ := json.Marshal(types)
+			if !jsonEqual(got, want) {
+				t.Errorf("%s: extensions are %s, want %s", label, got, want)
+			}
+		}
+		delete(fields, "extensionTypes")
+		delete(fields, "tokenMetadata")
+		checkGoldenFields(t, label, decoded, fields)
+	}
+}
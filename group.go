@@ -0,0 +1,182 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// GroupClient is the RPC calls used by GroupMembers. *rpc.Client
+// satisfies it.
+type GroupClient interface {
+	GetProgramAccountsWithOpts(ctx context.Context, publicKey solana.PublicKey, opts *rpc.GetProgramAccountsOpts) (rpc.GetProgramAccountsResult, error)
+	MultipleAccountsClient
+}
+
+var _ GroupClient = (*rpc.Client)(nil)
+
+// GroupMember is a mint that belongs to a token group.
+type GroupMember struct {
+	Mint solana.PublicKey
+	// Address is the account holding the member state: the mint itself,
+	// or the account its GroupMemberPointer names.
+	Address      solana.PublicKey
+	MemberNumber uint64
+}
+
+// MarshalJSON encodes the member with base58 keys and a u64 member
+// number as a string.
+func (m GroupMember) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&m)
+}
+
+func (m *GroupMember) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, m)
+}
+
+var (
+	// tokenGroupMemberDiscriminator identifies TokenGroupMember in the TLV
+	// data of accounts owned by programs implementing the token-group
+	// interface.
+	tokenGroupMemberDiscriminator = interfaceDiscriminator("spl_token_group_interface:member")
+
+	initializeGroupMemberDiscriminator = interfaceDiscriminator("spl_token_group_interface:initialize_member")
+)
+
+// NewInitializeGroupMemberInstruction initializes the TokenGroupMember
+// extension of memberMint, whose GroupMemberPointer must point to the mint
+// itself, as the next member of group. The program grows the mint to fit
+// the member, so the mint must already hold the rent for its final size.
+func NewInitializeGroupMemberInstruction(memberMint, memberMintAuthority, group, groupUpdateAuthority solana.PublicKey) solana.Instruction {
+	return solana.NewInstruction(solana.Token2022ProgramID, solana.AccountMetaSlice{
+		solana.Meta(memberMint).WRITE(),
+		solana.Meta(memberMint),
+		solana.Meta(memberMintAuthority).SIGNER(),
+		solana.Meta(group).WRITE(),
+		solana.Meta(groupUpdateAuthority).SIGNER(),
+	}, append([]byte{}, initializeGroupMemberDiscriminator...))
+}
+
+// GroupMembers lists the members of the group of groupMint, ordered by
+// member number. The group is the mint's TokenGroup extension, or the
+// account its GroupPointer names. Members are the Token-2022 mints whose
+// TokenGroupMember extension, or the account their GroupMemberPointer
+// names, references the group.
+//
+// Member state sits at no fixed offset in a mint, so GroupMembers scans
+// every Token-2022 mint; use an RPC provider that allows a full
+// getProgramAccounts scan of the program.
+func GroupMembers(ctx context.Context, client GroupClient, groupMint solana.PublicKey, commitment rpc.CommitmentType) ([]GroupMember, error) {
+	fetcher := NewAccountFetcher(client).SetFetchOpts(FetchOpts{Commitment: commitment})
+	group, err := groupAddress(ctx, fetcher, groupMint)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := client.GetProgramAccountsWithOpts(ctx, solana.Token2022ProgramID, &rpc.GetProgramAccountsOpts{
+		Commitment: commitment,
+		Encoding:   solana.EncodingBase64,
+		Filters: []rpc.RPCFilter{
+			{Memcmp: &rpc.RPCFilterMemcmp{Offset: AccountSize, Bytes: []byte{byte(AccountTypeMint)}}},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error while listing mints: %w", err)
+	}
+
+	var members []GroupMember
+	external := map[solana.PublicKey]solana.PublicKey{}
+	var externalAddresses []solana.PublicKey
+	for _, keyed := range out {
+		mint, err := DecodeMint(keyed.Account.Data.GetBinary())
+		if err != nil {
+			continue
+		}
+		pointer, _, err := mint.GroupMemberPointer()
+		if err != nil {
+			continue
+		}
+		if pointer != nil && pointer.Address != nil && !pointer.Address.Equals(keyed.Pubkey) {
+			if _, ok := external[*pointer.Address]; !ok {
+				externalAddresses = append(externalAddresses, *pointer.Address)
+			}
+			external[*pointer.Address] = keyed.Pubkey
+			continue
+		}
+		member, ok, err := mint.TokenGroupMember()
+		if !ok || err != nil || !member.Group.Equals(group) || !member.Mint.Equals(keyed.Pubkey) {
+			continue
+		}
+		members = append(members, GroupMember{Mint: keyed.Pubkey, Address: keyed.Pubkey, MemberNumber: member.MemberNumber})
+	}
+
+	if len(externalAddresses) > 0 {
+		raw, err := fetcher.Fetch(ctx, externalAddresses)
+		if err != nil {
+			return nil, fmt.Errorf("error while fetching group member accounts: %w", err)
+		}
+		for i, account := range raw {
+			if account == nil {
+				continue
+			}
+			entry, ok := findTLVEntry(account.Data.GetBinary(), tokenGroupMemberDiscriminator)
+			if !ok {
+				continue
+			}
+			member, err := DecodeTokenGroupMember(entry)
+			if err != nil || !member.Group.Equals(group) || !member.Mint.Equals(external[externalAddresses[i]]) {
+				continue
+			}
+			members = append(members, GroupMember{Mint: member.Mint, Address: externalAddresses[i], MemberNumber: member.MemberNumber})
+		}
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].MemberNumber < members[j].MemberNumber
+	})
+	return members, nil
+}
+
+// groupAddress returns the address of the TokenGroup state of
+// groupMint: the account its GroupPointer names, or the mint itself.
+func groupAddress(ctx context.Context, fetcher *AccountFetcher, groupMint solana.PublicKey) (solana.PublicKey, error) {
+	raw, err := fetcher.Fetch(ctx, []solana.PublicKey{groupMint})
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("error while fetching group mint: %w", err)
+	}
+	if raw[0] == nil {
+		return solana.PublicKey{}, fmt.Errorf("%w: %s", ErrAccountNotFound, groupMint)
+	}
+	if !raw[0].Owner.Equals(solana.Token2022ProgramID) {
+		return solana.PublicKey{}, fmt.Errorf("%w: %s is not owned by the Token-2022 program", ErrInvalidAccountOwner, groupMint)
+	}
+	mint, err := DecodeMint(raw[0].Data.GetBinary())
+	if err != nil {
+		return solana.PublicKey{}, err
+	}
+	pointer, _, err := mint.GroupPointer()
+	if err != nil {
+		return solana.PublicKey{}, err
+	}
+	if pointer != nil && pointer.Address != nil {
+		return *pointer.Address, nil
+	}
+	return groupMint, nil
+}
@@ -0,0 +1,81 @@
+package token2022
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func encodeGroupMember(mint, group solana.PublicKey, number uint64) []byte {
+	data := append(append([]byte{}, mint[:]...), group[:]...)
+	return binary.LittleEndian.AppendUint64(data, number)
+}
+
+func TestGroupMembers(t *testing.T) {
+	group := solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	authority := solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	pointer := func(t ExtensionType, address solana.PublicKey) Extension {
+		return Extension{Type: t, Data: append(append([]byte{}, authority[:]...), address[:]...)}
+	}
+	member := func(mint, group solana.PublicKey, number uint64) Extension {
+		return Extension{Type: ExtensionTokenGroupMember, Data: encodeGroupMember(mint, group, number)}
+	}
+
+	client := newMockRPC()
+	groupData := binary.LittleEndian.AppendUint64(append(append([]byte{}, authority[:]...), group[:]...), 3)
+	client.setAccount(group, solana.Token2022ProgramID, encodeMint(&authority, 0, 0,
+		pointer(ExtensionGroupPointer, group),
+		Extension{Type: ExtensionTokenGroup, Data: binary.LittleEndian.AppendUint64(groupData, 10)}))
+
+	first, second, other, external := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	client.setAccount(second, solana.Token2022ProgramID, encodeMint(&authority, 1, 0, pointer(ExtensionGroupMemberPointer, second), member(second, group, 2)))
+	client.setAccount(first, solana.Token2022ProgramID, encodeMint(&authority, 1, 0, pointer(ExtensionGroupMemberPointer, first), member(first, group, 1)))
+	client.setAccount(other, solana.Token2022ProgramID, encodeMint(&authority, 1, 0, member(other, solana.NewWallet().PublicKey(), 1)))
+	// A token account whose bytes happen to hold nothing of interest.
+	client.setAccount(solana.NewWallet().PublicKey(), solana.Token2022ProgramID, encodeTokenAccount(group, authority, 1))
+
+	// A member whose state lives in another program's account.
+	third, memberAccount := solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()
+	client.setAccount(third, solana.Token2022ProgramID, encodeMint(&authority, 1, 0, pointer(ExtensionGroupMemberPointer, memberAccount)))
+	entry := encodeGroupMember(third, group, 3)
+	tlv := append(append([]byte{}, tokenGroupMemberDiscriminator...), binary.LittleEndian.AppendUint32(nil, uint32(len(entry)))...)
+	client.setAccount(memberAccount, external, append(tlv, entry...))
+
+	members, err := GroupMembers(context.Background(), client, group, rpc.CommitmentConfirmed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []GroupMember{
+		{Mint: first, Address: first, MemberNumber: 1},
+		{Mint: second, Address: second, MemberNumber: 2},
+		{Mint: third, Address: memberAccount, MemberNumber: 3},
+	}
+	if len(members) != len(want) {
+		t.Fatalf("Expected %d members, got %d: %+v", len(want), len(members), members)
+	}
+	for i := range want {
+		if members[i] != want[i] {
+			t.Errorf("Expected member %d to be %+v, got %+v", i, want[i], members[i])
+		}
+	}
+
+	decoded, err := DecodeMint(client.accounts[group].Data.GetBinary())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenGroup, ok, err := decoded.TokenGroup()
+	if err != nil || !ok {
+		t.Fatalf("Expected a token group, got %v %v", ok, err)
+	}
+	if tokenGroup.Size != 3 || tokenGroup.MaxSize != 10 || !tokenGroup.UpdateAuthority.Equals(authority) {
+		t.Errorf("Unexpected token group: %+v", tokenGroup)
+	}
+
+	if _, err := GroupMembers(context.Background(), client, solana.NewWallet().PublicKey(), rpc.CommitmentConfirmed); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("Expected ErrAccountNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,288 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// HistoryClient is the set of RPC calls used by HistoryReader.
+// *rpc.Client satisfies it.
+type HistoryClient interface {
+	GetSignaturesForAddressWithOpts(ctx context.Context, account solana.PublicKey, opts *rpc.GetSignaturesForAddressOpts) ([]*rpc.TransactionSignature, error)
+	GetTransaction(ctx context.Context, sig solana.Signature, opts *rpc.GetTransactionOpts) (*rpc.GetTransactionResult, error)
+}
+
+var _ HistoryClient = (*rpc.Client)(nil)
+
+// maxSignaturesPerPage is the largest limit getSignaturesForAddress
+// accepts.
+const maxSignaturesPerPage = 1000
+
+// HistoryOpts selects the part of an address's history to read.
+type HistoryOpts struct {
+	// Before starts the scan at the signature before this one, newest
+	// first. The zero signature starts at the latest transaction.
+	Before solana.Signature
+	// Until stops the scan at this signature, excluded.
+	Until solana.Signature
+	// Limit is the number of signatures to scan, including those without
+	// Token-2022 operations. Zero means 1000.
+	Limit int
+	// IncludeFailed keeps the operations of failed transactions, with
+	// their Err set.
+	IncludeFailed bool
+	Commitment    rpc.CommitmentType
+}
+
+// TokenOperation is a Token-2022 or Associated Token Account instruction
+// affecting an address.
+type TokenOperation struct {
+	Signature solana.Signature
+	Slot      uint64
+	BlockTime *solana.UnixTimeSeconds
+	// Index and InnerIndex locate the instruction as in ParsedInstruction.
+	Index      int
+	InnerIndex int
+	// Name is the instruction name, such as "TransferChecked".
+	Name string
+	// Instruction is the decoded builder, or nil when the instruction is
+	// not supported by this package.
+	Instruction TypedInstruction
+	// Err is the failure of the transaction. It is only set when
+	// HistoryOpts.IncludeFailed is.
+	Err *TransactionError
+}
+
+// HistoryPage is one page of an address's history.
+type HistoryPage struct {
+	// Operations are in chronological order, oldest first.
+	Operations []*TokenOperation
+	// Next is the oldest signature scanned. Passing it as
+	// HistoryOpts.Before reads the following, older page. It is the zero
+	// signature when the history is exhausted.
+	Next solana.Signature
+}
+
+// HistoryReader decodes the Token-2022 history of an address, such as a
+// wallet or a token account, from getSignaturesForAddress and
+// getTransaction.
+type HistoryReader struct {
+	client      HistoryClient
+	concurrency int
+}
+
+// NewHistoryReader creates a reader fetching four transactions at once.
+func NewHistoryReader(client HistoryClient) *HistoryReader {
+	return &HistoryReader{client: client, concurrency: 4}
+}
+
+// SetConcurrency sets the number of getTransaction requests in flight at
+// once.
+func (r *HistoryReader) SetConcurrency(workers int) *HistoryReader {
+	if workers > 0 {
+		r.concurrency = workers
+	}
+	return r
+}
+
+// History pages through the signatures of address, fetches and parses
+// their transactions, and returns the Token-2022 operations that list
+// address among their accounts. Failed transactions are skipped unless
+// opts.IncludeFailed is set. opts may be nil.
+func (r *HistoryReader) History(ctx context.Context, address solana.PublicKey, opts *HistoryOpts) (*HistoryPage, error) {
+	sigs, txs, next, err := r.fetch(ctx, address, opts)
+	if err != nil {
+		return nil, err
+	}
+	page := &HistoryPage{Next: next}
+	// Signatures are listed newest first.
+	for i := len(txs) - 1; i >= 0; i-- {
+		ops, err := operationsAffecting(txs[i], address)
+		if err != nil {
+			return nil, fmt.Errorf("error while parsing transaction %s: %w", sigs[i].Signature, err)
+		}
+		page.Operations = append(page.Operations, ops...)
+	}
+	return page, nil
+}
+
+// fetch lists the signatures of address selected by opts and fetches
+// their transactions, both newest first. next is the page cursor of
+// HistoryPage.
+func (r *HistoryReader) fetch(ctx context.Context, address solana.PublicKey, opts *HistoryOpts) (sigs []*rpc.TransactionSignature, txs []*rpc.GetTransactionResult, next solana.Signature, err error) {
+	if opts == nil {
+		opts = &HistoryOpts{}
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = maxSignaturesPerPage
+	}
+
+	sigs, exhausted, err := r.signatures(ctx, address, opts, limit)
+	if err != nil {
+		return nil, nil, next, err
+	}
+	if !exhausted && len(sigs) > 0 {
+		next = sigs[len(sigs)-1].Signature
+	}
+
+	if !opts.IncludeFailed {
+		kept := sigs[:0]
+		for _, sig := range sigs {
+			if sig.Err == nil {
+				kept = append(kept, sig)
+			}
+		}
+		sigs = kept
+	}
+	txs, err = r.transactions(ctx, sigs, opts.Commitment)
+	if err != nil {
+		return nil, nil, next, err
+	}
+	return sigs, txs, next, nil
+}
+
+// signatures lists up to limit signatures of address, newest first, and
+// reports whether the history was exhausted.
+func (r *HistoryReader) signatures(ctx context.Context, address solana.PublicKey, opts *HistoryOpts, limit int) ([]*rpc.TransactionSignature, bool, error) {
+	var out []*rpc.TransactionSignature
+	before := opts.Before
+	for len(out) < limit {
+		if err := ctx.Err(); err != nil {
+			return nil, false, err
+		}
+		pageSize := min(limit-len(out), maxSignaturesPerPage)
+		page, err := r.client.GetSignaturesForAddressWithOpts(ctx, address, &rpc.GetSignaturesForAddressOpts{
+			Limit:      &pageSize,
+			Before:     before,
+			Until:      opts.Until,
+			Commitment: opts.Commitment,
+		})
+		if err != nil {
+			return nil, false, fmt.Errorf("error while getting signatures of %s: %w", address, err)
+		}
+		out = append(out, page...)
+		if len(page) < pageSize {
+			return out, true, nil
+		}
+		before = page[len(page)-1].Signature
+	}
+	return out, false, nil
+}
+
+// transactions fetches the transactions of sigs in the same order.
+func (r *HistoryReader) transactions(ctx context.Context, sigs []*rpc.TransactionSignature, commitment rpc.CommitmentType) ([]*rpc.GetTransactionResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	opts := &rpc.GetTransactionOpts{
+		Encoding:                       solana.EncodingBase64,
+		Commitment:                     commitment,
+		MaxSupportedTransactionVersion: &rpc.MaxSupportedTransactionVersion0,
+	}
+	var (
+		results  = make([]*rpc.GetTransactionResult, len(sigs))
+		indexes  = make(chan int)
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for i := 0; i < min(r.concurrency, len(sigs)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				out, err := r.client.GetTransaction(ctx, sigs[index].Signature, opts)
+				if err == nil && (out.Transaction == nil || out.Meta == nil) {
+					err = fmt.Errorf("transaction %s has no content", sigs[index].Signature)
+				}
+				if err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = fmt.Errorf("error while getting transaction %s: %w", sigs[index].Signature, err)
+					}
+					mu.Unlock()
+					cancel()
+					continue
+				}
+				results[index] = out
+			}
+		}()
+	}
+
+feed:
+	for i := range sigs {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// operationsAffecting returns the parsed instructions of a transaction
+// that list address among their accounts.
+func operationsAffecting(out *rpc.GetTransactionResult, address solana.PublicKey) ([]*TokenOperation, error) {
+	tx, err := out.Transaction.GetTransaction()
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := ParseTransaction(tx, out.Meta)
+	if err != nil {
+		return nil, err
+	}
+	var ops []*TokenOperation
+	for _, inst := range parsed.Instructions {
+		if !listsAccount(inst.Accounts, address) {
+			continue
+		}
+		ops = append(ops, &TokenOperation{
+			Signature:   parsed.Signature,
+			Slot:        out.Slot,
+			BlockTime:   out.BlockTime,
+			Index:       inst.Index,
+			InnerIndex:  inst.InnerIndex,
+			Name:        inst.Name,
+			Instruction: inst.Instruction,
+			Err:         parsed.Err,
+		})
+	}
+	return ops, nil
+}
+
+func listsAccount(accounts []*solana.AccountMeta, address solana.PublicKey) bool {
+	for _, account := range accounts {
+		if account.PublicKey.Equals(address) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package token2022
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// historyRPC serves one address's signatures, newest first, honoring
+// the paging options.
+type historyRPC struct {
+	signatures []*rpc.TransactionSignature
+	txs        map[solana.Signature]*rpc.GetTransactionResult
+	pages      int
+}
+
+func (m *historyRPC) GetSignaturesForAddressWithOpts(ctx context.Context, account solana.PublicKey, opts *rpc.GetSignaturesForAddressOpts) ([]*rpc.TransactionSignature, error) {
+	m.pages++
+	var out []*rpc.TransactionSignature
+	started := opts.Before.IsZero()
+	for _, sig := range m.signatures {
+		if !started {
+			started = sig.Signature == opts.Before
+			continue
+		}
+		if sig.Signature == opts.Until || len(out) == *opts.Limit {
+			break
+		}
+		out = append(out, sig)
+	}
+	return out, nil
+}
+
+func (m *historyRPC) GetTransaction(ctx context.Context, sig solana.Signature, opts *rpc.GetTransactionOpts) (*rpc.GetTransactionResult, error) {
+	out, ok := m.txs[sig]
+	if !ok {
+		return nil, rpc.ErrNotFound
+	}
+	return out, nil
+}
+
+// add appends a transaction older than those already added.
+func (m *historyRPC) add(t *testing.T, tx *solana.Transaction, meta *rpc.TransactionMeta, slot uint64) {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	var envelope rpc.TransactionResultEnvelope
+	if err := envelope.UnmarshalJSON([]byte(`["` + base64.StdEncoding.EncodeToString(raw) + `","base64"]`)); err != nil {
+		t.Fatalf("UnmarshalJSON: %v", err)
+	}
+	sig := tx.Signatures[0]
+	m.signatures = append(m.signatures, &rpc.TransactionSignature{Signature: sig, Slot: slot, Err: meta.Err})
+	m.txs[sig] = &rpc.GetTransactionResult{Slot: slot, Transaction: &envelope, Meta: meta}
+}
+
+func newHistoryRPC(t *testing.T) *historyRPC {
+	client := &historyRPC{txs: map[solana.Signature]*rpc.GetTransactionResult{}}
+	for i := byte(3); i >= 1; i-- {
+		tx, meta := depositTransaction(t, solana.Signature{i})
+		if i == 2 {
+			meta.Err = map[string]interface{}{"InstructionError": []interface{}{0, "InvalidAccountData"}}
+		}
+		client.add(t, tx, meta, uint64(i)*10)
+	}
+	return client
+}
+
+func TestHistory(t *testing.T) {
+	ctx := context.Background()
+	client := newHistoryRPC(t)
+	reader := NewHistoryReader(client)
+
+	page, err := reader.History(ctx, depositDestination, nil)
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if !page.Next.IsZero() {
+		t.Errorf("Expected the history to be exhausted, got next %s", page.Next)
+	}
+	if len(page.Operations) != 2 || page.Operations[0].Signature != (solana.Signature{1}) || page.Operations[1].Signature != (solana.Signature{3}) {
+		t.Fatalf("Expected the operations of transactions 1 and 3, got %+v", page.Operations)
+	}
+	op := page.Operations[1]
+	if op.Name != "TransferChecked" || op.Slot != 30 || op.InnerIndex != -1 {
+		t.Errorf("Unexpected operation %+v", op)
+	}
+	if transfer, ok := op.Instruction.(*TransferChecked2022); !ok || transfer.Amount != 1_000 {
+		t.Errorf("Expected a TransferChecked of 1000, got %#v", op.Instruction)
+	}
+
+	page, err = reader.History(ctx, depositDestination, &HistoryOpts{IncludeFailed: true})
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if len(page.Operations) != 3 || page.Operations[1].Err == nil || page.Operations[0].Err != nil {
+		t.Errorf("Expected the failed transaction with its error, got %+v", page.Operations)
+	}
+
+	page, err = reader.History(ctx, depositDestination, &HistoryOpts{Limit: 2})
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if page.Next != (solana.Signature{2}) || len(page.Operations) != 1 || page.Operations[0].Signature != (solana.Signature{3}) {
+		t.Fatalf("Unexpected first page %+v, next %s", page.Operations, page.Next)
+	}
+	page, err = reader.History(ctx, depositDestination, &HistoryOpts{Limit: 2, Before: page.Next})
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if !page.Next.IsZero() || len(page.Operations) != 1 || page.Operations[0].Signature != (solana.Signature{1}) {
+		t.Errorf("Unexpected second page %+v, next %s", page.Operations, page.Next)
+	}
+
+	page, err = reader.History(ctx, solana.SystemProgramID, nil)
+	if err != nil || len(page.Operations) != 0 {
+		t.Errorf("Expected no operations for an unrelated address, got %+v, %v", page, err)
+	}
+}
+
+func TestHistoryCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client := newHistoryRPC(t)
+
+	if _, err := NewHistoryReader(client).History(ctx, depositDestination, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if _, err := NewSupplyReplayer(client).Replay(ctx, depositDestination, rpc.CommitmentConfirmed); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	if client.pages != 0 {
+		t.Errorf("Expected no page to be read after cancellation, got %d", client.pages)
+	}
+}
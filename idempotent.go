@@ -0,0 +1,379 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// ErrIdempotencyKeyFailed is returned when the operation of an
+// idempotency key already failed on-chain. Nothing was transferred; the
+// operation can be retried under a new key.
+var ErrIdempotencyKeyFailed = errors.New("operation of the idempotency key failed")
+
+// ErrIdempotencyKeyInUse is returned when an operation with the same
+// idempotency key is already being sent by this IdempotentSender.
+var ErrIdempotencyKeyInUse = errors.New("idempotency key is in use")
+
+// IdempotencyState is the progress of the operation of an idempotency key.
+type IdempotencyState string
+
+const (
+	// IdempotencyPending means transactions may have been submitted but
+	// none is known to have landed.
+	IdempotencyPending IdempotencyState = "pending"
+	// IdempotencyConfirmed means a transaction landed and succeeded.
+	IdempotencyConfirmed IdempotencyState = "confirmed"
+	// IdempotencyFailed means a transaction landed and failed.
+	IdempotencyFailed IdempotencyState = "failed"
+)
+
+// IdempotentAttempt is a signed transaction submitted for a key. It is
+// saved before it is submitted so that it can be found after a restart.
+type IdempotentAttempt struct {
+	Signature            solana.Signature `json:"signature"`
+	LastValidBlockHeight uint64           `json:"lastValidBlockHeight"`
+	// Transaction is the signed transaction in wire format.
+	Transaction []byte `json:"transaction"`
+}
+
+// IdempotencyRecord is what an IdempotencyStore keeps for a key.
+type IdempotencyRecord struct {
+	Key      string               `json:"key"`
+	State    IdempotencyState     `json:"state"`
+	Attempts []*IdempotentAttempt `json:"attempts"`
+	// Signature and Slot identify the landed transaction once the state
+	// is confirmed or failed.
+	Signature solana.Signature `json:"signature"`
+	Slot      uint64           `json:"slot,omitempty"`
+	// Error is the on-chain error of a failed operation.
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Signatures returns the signatures of every attempt.
+func (r *IdempotencyRecord) Signatures() []solana.Signature {
+	sigs := make([]solana.Signature, len(r.Attempts))
+	for i, attempt := range r.Attempts {
+		sigs[i] = attempt.Signature
+	}
+	return sigs
+}
+
+// attempt returns the attempt whose signature is sig, or nil.
+func (r *IdempotencyRecord) attempt(sig solana.Signature) *IdempotentAttempt {
+	for _, attempt := range r.Attempts {
+		if attempt.Signature == sig {
+			return attempt
+		}
+	}
+	return nil
+}
+
+// IdempotencyStore persists idempotency records. Save must be durable
+// before it returns: a transaction is only submitted once its attempt has
+// been saved.
+type IdempotencyStore interface {
+	// Load returns the record of key, or nil when there is none.
+	Load(ctx context.Context, key string) (*IdempotencyRecord, error)
+	Save(ctx context.Context, record *IdempotencyRecord) error
+}
+
+// MemoryIdempotencyStore is an IdempotencyStore kept in memory, for tests
+// and for processes that do not need to survive restarts.
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string][]byte
+}
+
+var _ IdempotencyStore = (*MemoryIdempotencyStore)(nil)
+
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{records: map[string][]byte{}}
+}
+
+func (s *MemoryIdempotencyStore) Load(ctx context.Context, key string) (*IdempotencyRecord, error) {
+	s.mu.Lock()
+	data, ok := s.records[key]
+	s.mu.Unlock()
+	if !ok {
+		return nil, nil
+	}
+	record := new(IdempotencyRecord)
+	if err := json.Unmarshal(data, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func (s *MemoryIdempotencyStore) Save(ctx context.Context, record *IdempotencyRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.records[record.Key] = data
+	s.mu.Unlock()
+	return nil
+}
+
+// FileIdempotencyStore is an IdempotencyStore that keeps one JSON file per
+// key in a directory. Files are replaced atomically, so a crash leaves
+// either the old or the new record.
+type FileIdempotencyStore struct {
+	dir string
+}
+
+var _ IdempotencyStore = (*FileIdempotencyStore)(nil)
+
+// NewFileIdempotencyStore creates a store in dir, creating it if needed.
+func NewFileIdempotencyStore(dir string) (*FileIdempotencyStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error while creating %s: %w", dir, err)
+	}
+	return &FileIdempotencyStore{dir: dir}, nil
+}
+
+// path names the file of key by its hash, since keys are arbitrary
+// strings.
+func (s *FileIdempotencyStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (s *FileIdempotencyStore) Load(ctx context.Context, key string) (*IdempotencyRecord, error) {
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	record := new(IdempotencyRecord)
+	if err := json.Unmarshal(data, record); err != nil {
+		return nil, fmt.Errorf("error while decoding the record of %q: %w", key, err)
+	}
+	return record, nil
+}
+
+func (s *FileIdempotencyStore) Save(ctx context.Context, record *IdempotencyRecord) error {
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.dir, ".record-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(record.Key))
+}
+
+// IdempotentSender sends operations through a Sender under caller-supplied
+// idempotency keys, such as a withdrawal ID. Every signed transaction is
+// saved to the store before it is submitted, so that after a restart a
+// Send with the same key first waits for the earlier transactions: it
+// returns the landed one instead of submitting the operation again, and
+// only sends anew once all of them have expired without landing.
+type IdempotentSender struct {
+	sender *Sender
+	store  IdempotencyStore
+
+	mu    sync.Mutex
+	inUse map[string]bool
+}
+
+// NewIdempotentSender wraps sender, keeping records in store.
+func NewIdempotentSender(sender *Sender, store IdempotencyStore) *IdempotentSender {
+	return &IdempotentSender{
+		sender: sender,
+		store:  store,
+		inUse:  map[string]bool{},
+	}
+}
+
+// Send sends the operation of builder under key, unless it was already
+// sent. A confirmed key returns its result without touching the cluster;
+// a key that failed on-chain returns the TransactionError wrapped with
+// ErrIdempotencyKeyFailed. When Send returns another error, such as a
+// cancelled ctx, the key stays pending and a later Send resumes it.
+func (s *IdempotentSender) Send(ctx context.Context, key string, builder *TxBuilder, options ...SendOption) (*SendResult, error) {
+	if key == "" {
+		return nil, errNotSet("Key")
+	}
+	if !s.acquire(key) {
+		return nil, fmt.Errorf("%w: %q", ErrIdempotencyKeyInUse, key)
+	}
+	defer s.release(key)
+
+	record, err := s.store.Load(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("error while loading %q: %w", key, err)
+	}
+	if record == nil {
+		now := time.Now()
+		record = &IdempotencyRecord{Key: key, State: IdempotencyPending, CreatedAt: now, UpdatedAt: now}
+	}
+
+	switch record.State {
+	case IdempotencyConfirmed:
+		logEvent(ctx, s.sender.logger, slog.LevelInfo, "operation already confirmed", "key", key, "signature", record.Signature.String())
+		return record.result(), nil
+	case IdempotencyFailed:
+		return record.result(), fmt.Errorf("%w: %q: %s", ErrIdempotencyKeyFailed, key, record.Error)
+	}
+
+	config, err := applySendOptions(s.sender.opts, s.sender.commitment, append(s.sender.options[:len(s.sender.options):len(s.sender.options)], options...))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(record.Attempts) > 0 {
+		result, err := s.resume(ctx, record, config)
+		if result != nil || err != nil {
+			return result, err
+		}
+	}
+
+	config.beforeSend = func(ctx context.Context, tx *solana.Transaction, lastValidBlockHeight uint64) error {
+		data, err := tx.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		record.Attempts = append(record.Attempts, &IdempotentAttempt{
+			Signature:            tx.Signatures[0],
+			LastValidBlockHeight: lastValidBlockHeight,
+			Transaction:          data,
+		})
+		record.UpdatedAt = time.Now()
+		return s.store.Save(ctx, record)
+	}
+	ctx, end := startSpan(ctx, s.sender.tracer, "token2022.IdempotentSender.Send", "key", key)
+	result, err := s.sender.send(ctx, builder, config)
+	end(err)
+	return s.settle(ctx, record, result, err)
+}
+
+// resume waits for the saved attempts of a pending record. It returns a
+// nil result and error when none of them landed and all have expired, so
+// that the operation can be sent anew, and an error that leaves the record
+// pending when one landed below the commitment of the Sender.
+func (s *IdempotentSender) resume(ctx context.Context, record *IdempotencyRecord, config *sendConfig) (*SendResult, error) {
+	for _, attempt := range record.Attempts {
+		tx, err := solana.TransactionFromBytes(attempt.Transaction)
+		if err != nil {
+			return nil, fmt.Errorf("error while decoding the attempt %s of %q: %w", attempt.Signature, record.Key, err)
+		}
+		// This rebroadcasts attempts that are still valid and returns at
+		// once for those that expired.
+		status, err := s.sender.waitForExpiry(ctx, tx, attempt.LastValidBlockHeight, config)
+		if err != nil {
+			return nil, err
+		}
+		if status != nil {
+			result, err := s.sender.result(ctx, tx, attempt.Signature, status, len(record.Attempts))
+			return s.settle(ctx, record, result, err)
+		}
+	}
+
+	// An attempt may have landed after the wait for it ended.
+	landedSig, landed, err := s.sender.findLanded(ctx, record.Signatures())
+	if err != nil {
+		return nil, err
+	}
+	if landed != nil {
+		if landed.Err == nil && !reachedCommitment(landed.ConfirmationStatus, config.commitment) {
+			// Settling now would mark the key confirmed for good while
+			// the transaction can still be rolled back.
+			return nil, fmt.Errorf("attempt %s of %q landed but has not reached %s commitment", landedSig, record.Key, config.commitment)
+		}
+		attempt := record.attempt(landedSig)
+		tx, err := solana.TransactionFromBytes(attempt.Transaction)
+		if err != nil {
+			return nil, fmt.Errorf("error while decoding the attempt %s of %q: %w", attempt.Signature, record.Key, err)
+		}
+		result, err := s.sender.result(ctx, tx, landedSig, landed, len(record.Attempts))
+		return s.settle(ctx, record, result, err)
+	}
+	logEvent(ctx, s.sender.logger, slog.LevelWarn, "pending operation expired, sending it again", "key", record.Key, "attempts", len(record.Attempts))
+	return nil, nil
+}
+
+// settle records the outcome of a Send. Only landed transactions are
+// final; other errors leave the record pending.
+func (s *IdempotentSender) settle(ctx context.Context, record *IdempotencyRecord, result *SendResult, err error) (*SendResult, error) {
+	var txErr *TransactionError
+	switch {
+	case err == nil:
+		record.State = IdempotencyConfirmed
+	case result != nil && errors.As(err, &txErr):
+		record.State = IdempotencyFailed
+		record.Error = txErr.Error()
+	default:
+		return result, err
+	}
+	record.Signature = result.Signature
+	record.Slot = result.Slot
+	record.UpdatedAt = time.Now()
+	if saveErr := s.store.Save(ctx, record); saveErr != nil {
+		return result, fmt.Errorf("error while saving %q: %w", record.Key, saveErr)
+	}
+	return result, err
+}
+
+// result returns the SendResult of a confirmed or failed record.
+func (r *IdempotencyRecord) result() *SendResult {
+	return &SendResult{Signature: r.Signature, Slot: r.Slot, Attempts: len(r.Attempts)}
+}
+
+func (s *IdempotentSender) acquire(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.inUse[key] {
+		return false
+	}
+	s.inUse[key] = true
+	return true
+}
+
+func (s *IdempotentSender) release(key string) {
+	s.mu.Lock()
+	delete(s.inUse, key)
+	s.mu.Unlock()
+}
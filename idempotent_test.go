@@ -0,0 +1,191 @@
+package token2022
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+func newIdempotentFixture(t *testing.T) (*mockRPC, *TxBuilder) {
+	t.Helper()
+	var (
+		owner  = solana.NewWallet().PrivateKey
+		mint   = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		client = newMockRPC()
+	)
+	builder := NewTxBuilder(client).
+		SetFeePayer(owner.PublicKey()).
+		AddInstruction(NewCreate2022Instruction(owner.PublicKey(), owner.PublicKey(), mint).Build()).
+		AddSigner(NewPrivateKeySigner(owner))
+	return client, builder
+}
+
+func TestIdempotentSenderConfirmedOnce(t *testing.T) {
+
+	client, builder := newIdempotentFixture(t)
+	client.onSend = func(tx *solana.Transaction) {
+		client.statuses[tx.Signatures[0]] = &rpc.SignatureStatusesResult{Slot: 42, ConfirmationStatus: rpc.ConfirmationStatusConfirmed}
+	}
+	store := NewMemoryIdempotencyStore()
+	sender := NewIdempotentSender(NewSender(client).SetPollInterval(time.Millisecond), store)
+
+	first, err := sender.Send(context.Background(), "withdrawal-1", builder)
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	second, err := sender.Send(context.Background(), "withdrawal-1", builder)
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(client.sent) != 1 {
+		t.Errorf("Expected 1 submission, got %d", len(client.sent))
+	}
+	if second.Signature != first.Signature || second.Slot != 42 {
+		t.Errorf("Expected the stored result %+v, got %+v", first, second)
+	}
+
+	record, err := store.Load(context.Background(), "withdrawal-1")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if record.State != IdempotencyConfirmed || len(record.Attempts) != 1 || record.Attempts[0].Signature != first.Signature {
+		t.Errorf("Unexpected record %+v", record)
+	}
+
+	if _, err := sender.Send(context.Background(), "", builder); !errors.Is(err, ErrNotSet) {
+		t.Errorf("Expected ErrNotSet for an empty key, got %v", err)
+	}
+}
+
+func TestIdempotentSenderRestart(t *testing.T) {
+
+	client, builder := newIdempotentFixture(t)
+	dir := t.TempDir()
+	store, err := NewFileIdempotencyStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileIdempotencyStore: %v", err)
+	}
+
+	// The process dies after submitting, before the confirmation.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = NewIdempotentSender(NewSender(client).SetPollInterval(time.Millisecond), store).Send(ctx, "withdrawal-1", builder)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected deadline exceeded, got %v", err)
+	}
+	sig := client.sent[0].Signatures[0]
+	submitted := len(client.sent)
+	client.statuses[sig] = &rpc.SignatureStatusesResult{Slot: 50, ConfirmationStatus: rpc.ConfirmationStatusConfirmed}
+
+	store, err = NewFileIdempotencyStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileIdempotencyStore: %v", err)
+	}
+	result, err := NewIdempotentSender(NewSender(client).SetPollInterval(time.Millisecond), store).Send(context.Background(), "withdrawal-1", builder)
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if result.Signature != sig || result.Slot != 50 {
+		t.Errorf("Expected the earlier transaction %s, got %+v", sig, result)
+	}
+	if len(client.sent) != submitted {
+		t.Errorf("Expected no new submission, got %d", len(client.sent)-submitted)
+	}
+}
+
+func TestIdempotentSenderExpiredPending(t *testing.T) {
+
+	client, builder := newIdempotentFixture(t)
+	store := NewMemoryIdempotencyStore()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := NewIdempotentSender(NewSender(client).SetPollInterval(time.Millisecond), store).Send(ctx, "withdrawal-1", builder); err == nil {
+		t.Fatal("Expected the send to be interrupted")
+	}
+	first := client.sent[0].Signatures[0]
+
+	// The first transaction expired without landing, so it is sent anew.
+	client.blockHeight = 1001
+	client.blockhash = solana.Hash(solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin"))
+	client.lastValidBlockHeight = 2000
+	client.onSend = func(tx *solana.Transaction) {
+		client.statuses[tx.Signatures[0]] = &rpc.SignatureStatusesResult{Slot: 60, ConfirmationStatus: rpc.ConfirmationStatusConfirmed}
+	}
+	result, err := NewIdempotentSender(NewSender(client).SetPollInterval(time.Millisecond), store).Send(context.Background(), "withdrawal-1", builder)
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if result.Signature == first || result.Slot != 60 {
+		t.Errorf("Expected a new transaction, got %+v", result)
+	}
+	record, _ := store.Load(context.Background(), "withdrawal-1")
+	if record.State != IdempotencyConfirmed || len(record.Attempts) != 2 {
+		t.Errorf("Expected 2 recorded attempts, got %+v", record)
+	}
+}
+
+func TestIdempotentSenderLandedBelowCommitment(t *testing.T) {
+
+	client, builder := newIdempotentFixture(t)
+	store := NewMemoryIdempotencyStore()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := NewIdempotentSender(NewSender(client).SetPollInterval(time.Millisecond), store).Send(ctx, "withdrawal-1", builder); err == nil {
+		t.Fatal("Expected the send to be interrupted")
+	}
+	sig := client.sent[0].Signatures[0]
+	submitted := len(client.sent)
+
+	// The blockhash expired with the transaction only processed: it is
+	// neither sent again nor settled.
+	client.blockHeight = 1001
+	client.statuses[sig] = &rpc.SignatureStatusesResult{Slot: 70, ConfirmationStatus: rpc.ConfirmationStatusProcessed}
+	sender := NewIdempotentSender(NewSender(client).SetPollInterval(time.Millisecond), store)
+	if _, err := sender.Send(context.Background(), "withdrawal-1", builder); err == nil {
+		t.Fatal("Expected an error for a processed transaction")
+	}
+	record, _ := store.Load(context.Background(), "withdrawal-1")
+	if record.State != IdempotencyPending || len(client.sent) != submitted {
+		t.Errorf("Expected the key to stay pending without a new submission, got %+v and %d submissions", record, len(client.sent)-submitted)
+	}
+
+	client.statuses[sig] = &rpc.SignatureStatusesResult{Slot: 70, ConfirmationStatus: rpc.ConfirmationStatusConfirmed}
+	result, err := sender.Send(context.Background(), "withdrawal-1", builder)
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if result.Signature != sig || result.Slot != 70 {
+		t.Errorf("Expected the processed transaction once confirmed, got %+v", result)
+	}
+}
+
+func TestIdempotentSenderFailed(t *testing.T) {
+
+	client, builder := newIdempotentFixture(t)
+	var rawErr interface{}
+	if err := json.Unmarshal([]byte(`{"InstructionError":[0,{"Custom":1}]}`), &rawErr); err != nil {
+		t.Fatalf("Error decoding error: %v", err)
+	}
+	client.onSend = func(tx *solana.Transaction) {
+		client.statuses[tx.Signatures[0]] = &rpc.SignatureStatusesResult{Slot: 70, Err: rawErr}
+	}
+	sender := NewIdempotentSender(NewSender(client).SetPollInterval(time.Millisecond), NewMemoryIdempotencyStore())
+
+	var txErr *TransactionError
+	if _, err := sender.Send(context.Background(), "withdrawal-1", builder); !errors.As(err, &txErr) {
+		t.Fatalf("Expected TransactionError, got %v", err)
+	}
+	if _, err := sender.Send(context.Background(), "withdrawal-1", builder); !errors.Is(err, ErrIdempotencyKeyFailed) {
+		t.Errorf("Expected ErrIdempotencyKeyFailed, got %v", err)
+	}
+	if len(client.sent) != 1 {
+		t.Errorf("Expected 1 submission, got %d", len(client.sent))
+	}
+}
@@ -0,0 +1,434 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+const (
+	// IDLSpec is the version of the Anchor IDL specification the IDLs of
+	// this package follow.
+	IDLSpec = "0.1.0"
+	// IDLVersion is the version of the IDLs themselves. It changes when
+	// an instruction, account or argument of a builder changes.
+	IDLVersion = "1.0.0"
+)
+
+// IDL is an Anchor style description of a program's instructions: their
+// discriminators, accounts and arguments, derived from the instruction
+// builders of this package so that code generators and explorers decode
+// exactly what the builders encode. See TokenIDL and AssociatedTokenIDL.
+type IDL struct {
+	Address      string           `json:"address"`
+	Metadata     IDLMetadata      `json:"metadata"`
+	Instructions []IDLInstruction `json:"instructions"`
+	Types        []IDLTypeDef     `json:"types,omitempty"`
+}
+
+// IDLMetadata names the program an IDL describes.
+type IDLMetadata struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Spec    string `json:"spec"`
+}
+
+// IDLInstruction describes one instruction. Discriminator is the prefix
+// of the instruction data that selects it: the instruction tag, followed
+// by the sub-tag for the instructions of an extension. RemainingAccounts
+// is not part of the Anchor specification; it lists the variable-length
+// account lists, such as the signers of a multisig owner, that follow
+// Accounts.
+type IDLInstruction struct {
+	Name              string       `json:"name"`
+	Docs              []string     `json:"docs,omitempty"`
+	Discriminator     []int        `json:"discriminator"`
+	Accounts          []IDLAccount `json:"accounts"`
+	RemainingAccounts []IDLAccount `json:"remainingAccounts,omitempty"`
+	Args              []IDLField   `json:"args"`
+}
+
+// IDLAccount is an account of an instruction.
+type IDLAccount struct {
+	Name     string   `json:"name"`
+	Docs     []string `json:"docs,omitempty"`
+	Writable bool     `json:"writable,omitempty"`
+	Signer   bool     `json:"signer,omitempty"`
+}
+
+// IDLField is an argument of an instruction, in encoding order.
+type IDLField struct {
+	Name string  `json:"name"`
+	Type IDLType `json:"type"`
+}
+
+// IDLType is the type of an argument: a primitive such as "u64" or
+// "pubkey", an option or vector of another type, or a type defined in the
+// Types of the IDL. Exactly one field is set.
+type IDLType struct {
+	Primitive string
+	Option    *IDLType
+	Vec       *IDLType
+	Defined   string
+}
+
+func (t IDLType) MarshalJSON() ([]byte, error) {
+	switch {
+	case t.Option != nil:
+		return json.Marshal(map[string]IDLType{"option": *t.Option})
+	case t.Vec != nil:
+		return json.Marshal(map[string]IDLType{"vec": *t.Vec})
+	case t.Defined != "":
+		return json.Marshal(map[string]map[string]string{"defined": {"name": t.Defined}})
+	}
+	return json.Marshal(t.Primitive)
+}
+
+func (t *IDLType) UnmarshalJSON(data []byte) error {
+	*t = IDLType{}
+	if err := json.Unmarshal(data, &t.Primitive); err == nil {
+		return nil
+	}
+	var fields struct {
+		Option  *IDLType `json:"option"`
+		Vec     *IDLType `json:"vec"`
+		Defined *struct {
+			Name string `json:"name"`
+		} `json:"defined"`
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	t.Option, t.Vec = fields.Option, fields.Vec
+	if fields.Defined != nil {
+		t.Defined = fields.Defined.Name
+	}
+	if t.Option == nil && t.Vec == nil && t.Defined == "" {
+		return fmt.Errorf("unknown IDL type %s", data)
+	}
+	return nil
+}
+
+// IDLTypeDef is a type referenced by name from the arguments: an enum
+// encoded as its one-byte variant index, or an alias of another type.
+type IDLTypeDef struct {
+	Name string       `json:"name"`
+	Docs []string     `json:"docs,omitempty"`
+	Type IDLTypeDefTy `json:"type"`
+}
+
+// IDLTypeDefTy is the body of an IDLTypeDef. Kind is "enum" or "type".
+type IDLTypeDefTy struct {
+	Kind     string           `json:"kind"`
+	Variants []IDLEnumVariant `json:"variants,omitempty"`
+	Alias    *IDLType         `json:"alias,omitempty"`
+}
+
+// IDLEnumVariant is a variant of an enum type.
+type IDLEnumVariant struct {
+	Name string `json:"name"`
+}
+
+var (
+	publicKeyType      = reflect.TypeOf(solana.PublicKey{})
+	publicKeysType     = reflect.TypeOf([]solana.PublicKey(nil))
+	publicKeyPtrType   = reflect.TypeOf((*solana.PublicKey)(nil))
+	extensionTypesType = reflect.TypeOf([]ExtensionType(nil))
+)
+
+// idlArgTypes maps the Go types of the builder arguments to IDL types.
+// Optional public keys are resolved by describeInstruction, because the
+// builders encode them either as an option or as an OptionalNonZeroPubkey.
+var idlArgTypes = map[reflect.Type]IDLType{
+	reflect.TypeOf(false):            {Primitive: "bool"},
+	reflect.TypeOf(uint8(0)):         {Primitive: "u8"},
+	reflect.TypeOf(uint16(0)):        {Primitive: "u16"},
+	reflect.TypeOf(uint32(0)):        {Primitive: "u32"},
+	uint64Type:                       {Primitive: "u64"},
+	reflect.TypeOf(int16(0)):         {Primitive: "i16"},
+	reflect.TypeOf(int64(0)):         {Primitive: "i64"},
+	reflect.TypeOf(float64(0)):       {Primitive: "f64"},
+	publicKeyType:                    {Primitive: "pubkey"},
+	reflect.TypeOf(AuthorityType(0)): {Defined: "AuthorityType"},
+	reflect.TypeOf(AccountState(0)):  {Defined: "AccountState"},
+	extensionTypesType:               {Defined: "ExtensionTypes"},
+	reflect.TypeOf(""):               {Defined: "UiAmount"},
+}
+
+// idlProgramAccounts names the well-known accounts the builders add on
+// their own.
+var idlProgramAccounts = map[solana.PublicKey]string{
+	solana.SystemProgramID:    "systemProgram",
+	solana.Token2022ProgramID: "tokenProgram",
+	solana.SysVarRentPubkey:   "rent",
+}
+
+// TokenIDL describes the Token-2022 instructions this package builds.
+func TokenIDL() (*IDL, error) {
+	idl := &IDL{
+		Address:  solana.Token2022ProgramID.String(),
+		Metadata: IDLMetadata{Name: "token2022", Version: IDLVersion, Spec: IDLSpec},
+		Types:    tokenIDLTypes(),
+	}
+	for _, tag := range sortedTags(instructionRegistry) {
+		inst, err := describeInstruction([]byte{tag}, instructionRegistry[tag], nil)
+		if err != nil {
+			return nil, err
+		}
+		inst.Name = jsonFieldName(instructionNames[tag])
+		idl.Instructions = append(idl.Instructions, *inst)
+	}
+	for _, tag := range sortedTags(extensionInstructionRegistry) {
+		subRegistry := extensionInstructionRegistry[tag]
+		for _, subTag := range sortedTags(subRegistry) {
+			inst, err := describeInstruction([]byte{tag, subTag}, subRegistry[subTag], nil)
+			if err != nil {
+				return nil, err
+			}
+			idl.Instructions = append(idl.Instructions, *inst)
+		}
+	}
+	return idl, nil
+}
+
+// AssociatedTokenIDL describes the associated token account instructions
+// built by Create2022.
+func AssociatedTokenIDL() (*IDL, error) {
+	idl := &IDL{
+		Address:  ProgramID.String(),
+		Metadata: IDLMetadata{Name: "associatedToken", Version: IDLVersion, Spec: IDLSpec},
+	}
+	for _, tag := range []uint8{AssociatedTokenInstructionCreate, AssociatedTokenInstructionCreateIdempotent} {
+		idempotent := tag == AssociatedTokenInstructionCreateIdempotent
+		newInst := func() TypedInstruction { return &Create2022{Idempotent: idempotent} }
+		inst, err := describeInstruction([]byte{tag}, newInst, func(inst TypedInstruction) map[solana.PublicKey]string {
+			create := inst.(*Create2022)
+			address, _, _ := FindAssociatedTokenAddress(create.Wallet, create.Mint, create.tokenProgram())
+			return map[solana.PublicKey]string{address: "associatedTokenAccount"}
+		})
+		if err != nil {
+			return nil, err
+		}
+		inst.Name = jsonFieldName(associatedTokenInstructionNames[tag])
+		inst.Args = []IDLField{}
+		idl.Instructions = append(idl.Instructions, *inst)
+	}
+	return idl, nil
+}
+
+// describeInstruction describes the builder newInst returns by building
+// it with a distinct key in every account field and matching the account
+// metas of the result back to the fields. derived names the accounts the
+// builder computes from its fields, such as an associated token address.
+func describeInstruction(discriminator []byte, newInst func() TypedInstruction, derived func(TypedInstruction) map[solana.PublicKey]string) (*IDLInstruction, error) {
+	inst := newInst()
+	rv := reflect.ValueOf(inst).Elem()
+	rt := rv.Type()
+
+	names := make(map[solana.PublicKey]string)
+	lists := make(map[solana.PublicKey]string)
+	listValues := make(map[int]reflect.Value)
+	var probe byte
+	nextKey := func() solana.PublicKey {
+		probe++
+		var key solana.PublicKey
+		for i := range key {
+			key[i] = probe
+		}
+		return key
+	}
+	var args []reflect.StructField
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !jsonField(field) {
+			continue
+		}
+		if field.Tag.Get("bin") != "-" {
+			args = append(args, field)
+			if field.Type == publicKeyPtrType {
+				key := nextKey()
+				rv.Field(i).Set(reflect.ValueOf(&key))
+			}
+			continue
+		}
+		switch field.Type {
+		case publicKeyType:
+			key := nextKey()
+			rv.Field(i).Set(reflect.ValueOf(key))
+			names[key] = jsonFieldName(field.Name)
+		case publicKeysType:
+			key := nextKey()
+			lists[key] = jsonFieldName(field.Name)
+			listValues[i] = reflect.ValueOf([]solana.PublicKey{key})
+		case accountMetasType:
+			key := nextKey()
+			lists[key] = jsonFieldName(field.Name)
+			listValues[i] = reflect.ValueOf([]*solana.AccountMeta{{PublicKey: key}})
+		default:
+			return nil, fmt.Errorf("%s: unsupported account field %s of type %s", rt.Name(), field.Name, field.Type)
+		}
+	}
+	if derived != nil {
+		for key, name := range derived(inst) {
+			names[key] = name
+		}
+	}
+
+	// The accounts are those of a build without the account lists, whose
+	// accounts, such as the signers of a multisig owner, follow them and
+	// may change their flags.
+	built := inst.Build()
+	data, err := built.Data()
+	if err != nil {
+		return nil, fmt.Errorf("%s: error while encoding: %w", rt.Name(), err)
+	}
+	if !strings.HasPrefix(string(data), string(discriminator)) {
+		return nil, fmt.Errorf("%s: data starts with %v, expected discriminator %v", rt.Name(), data[:min(len(data), len(discriminator))], discriminator)
+	}
+
+	out := &IDLInstruction{
+		Name:          jsonFieldName(strings.TrimSuffix(rt.Name(), "2022")),
+		Docs:          []string{fmt.Sprintf("Built by %s.", rt.Name())},
+		Discriminator: make([]int, len(discriminator)),
+		Accounts:      []IDLAccount{},
+		Args:          []IDLField{},
+	}
+	for i, b := range discriminator {
+		out.Discriminator[i] = int(b)
+	}
+	for _, meta := range built.Accounts() {
+		account, ok := names[meta.PublicKey]
+		if !ok {
+			account, ok = idlProgramAccounts[meta.PublicKey]
+		}
+		if !ok {
+			return nil, fmt.Errorf("%s: unknown account %s", rt.Name(), meta.PublicKey)
+		}
+		out.Accounts = append(out.Accounts, IDLAccount{Name: account, Writable: meta.IsWritable, Signer: meta.IsSigner})
+	}
+
+	if len(listValues) > 0 {
+		for i, value := range listValues {
+			rv.Field(i).Set(value)
+		}
+		for i, meta := range inst.Build().Accounts() {
+			if list, ok := lists[meta.PublicKey]; ok {
+				out.RemainingAccounts = append(out.RemainingAccounts, IDLAccount{
+					Name:     list,
+					Docs:     []string{"Zero or more accounts."},
+					Writable: meta.IsWritable,
+					Signer:   meta.IsSigner,
+				})
+				continue
+			}
+			if i < len(out.Accounts) && out.Accounts[i].Signer && !meta.IsSigner {
+				out.Accounts[i].Docs = []string{"Signs unless it is a multisig, whose signers follow the accounts."}
+			}
+		}
+	}
+
+	for _, field := range args {
+		argType, ok := idlArgTypes[field.Type]
+		if field.Type == publicKeyPtrType {
+			argType, err = optionalPubkeyType(inst, field)
+			if err != nil {
+				return nil, err
+			}
+			ok = true
+		}
+		if !ok {
+			return nil, fmt.Errorf("%s: unsupported argument %s of type %s", rt.Name(), field.Name, field.Type)
+		}
+		out.Args = append(out.Args, IDLField{Name: jsonFieldName(field.Name), Type: argType})
+	}
+	return out, nil
+}
+
+// optionalPubkeyType tells the two encodings of an optional public key
+// apart by the size of the data with and without the key: an option
+// drops the key when it is None, an OptionalNonZeroPubkey zeroes it.
+func optionalPubkeyType(inst TypedInstruction, field reflect.StructField) (IDLType, error) {
+	rv := reflect.ValueOf(inst).Elem().FieldByIndex(field.Index)
+	set := rv.Interface()
+	defer rv.Set(reflect.ValueOf(set))
+
+	withKey, err := inst.Build().Data()
+	if err != nil {
+		return IDLType{}, err
+	}
+	rv.Set(reflect.Zero(field.Type))
+	withoutKey, err := inst.Build().Data()
+	if err != nil {
+		return IDLType{}, err
+	}
+	switch len(withKey) - len(withoutKey) {
+	case solana.PublicKeyLength:
+		return IDLType{Option: &IDLType{Primitive: "pubkey"}}, nil
+	case 0:
+		return IDLType{Defined: "OptionalNonZeroPubkey"}, nil
+	}
+	return IDLType{}, errors.New("unknown encoding of optional public key " + field.Name)
+}
+
+func tokenIDLTypes() []IDLTypeDef {
+	var authorityTypes []IDLEnumVariant
+	for _, name := range authorityTypeNames {
+		authorityTypes = append(authorityTypes, IDLEnumVariant{Name: name})
+	}
+	var accountStates []IDLEnumVariant
+	for state := AccountStateUninitialized; state <= AccountStateFrozen; state++ {
+		accountStates = append(accountStates, IDLEnumVariant{Name: state.String()})
+	}
+	return []IDLTypeDef{
+		{Name: "AccountState", Type: IDLTypeDefTy{Kind: "enum", Variants: accountStates}},
+		{Name: "AuthorityType", Type: IDLTypeDefTy{Kind: "enum", Variants: authorityTypes}},
+		{
+			Name: "ExtensionType",
+			Docs: []string{"The u16 type of a Token-2022 extension."},
+			Type: IDLTypeDefTy{Kind: "type", Alias: &IDLType{Primitive: "u16"}},
+		},
+		{
+			Name: "ExtensionTypes",
+			Docs: []string{"Extension types filling the rest of the instruction data, without a length prefix."},
+			Type: IDLTypeDefTy{Kind: "type", Alias: &IDLType{Vec: &IDLType{Defined: "ExtensionType"}}},
+		},
+		{
+			Name: "OptionalNonZeroPubkey",
+			Docs: []string{"A public key that is None when all 32 bytes are zero."},
+			Type: IDLTypeDefTy{Kind: "type", Alias: &IDLType{Primitive: "pubkey"}},
+		},
+		{
+			Name: "UiAmount",
+			Docs: []string{"A decimal amount as UTF-8 text filling the rest of the instruction data, without a length prefix."},
+			Type: IDLTypeDefTy{Kind: "type", Alias: &IDLType{Primitive: "string"}},
+		},
+	}
+}
+
+func sortedTags[T any](registry map[uint8]T) []uint8 {
+	tags := make([]uint8, 0, len(registry))
+	for tag := range registry {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i] < tags[j] })
+	return tags
+}
@@ -0,0 +1,114 @@
+package token2022
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestTokenIDL(t *testing.T) {
+
+	idl, err := TokenIDL()
+	if err != nil {
+		t.Fatalf("TokenIDL failed: %v", err)
+	}
+	count := len(instructionRegistry)
+	for _, subRegistry := range extensionInstructionRegistry {
+		count += len(subRegistry)
+	}
+	if len(idl.Instructions) != count {
+		t.Fatalf("Expected %d instructions, got %d", count, len(idl.Instructions))
+	}
+
+	byName := make(map[string]IDLInstruction)
+	discriminators := make(map[string]string)
+	for _, inst := range idl.Instructions {
+		key := fmt.Sprint(inst.Discriminator)
+		if other, ok := discriminators[key]; ok {
+			t.Errorf("Expected unique discriminators, %s and %s share %s", other, inst.Name, key)
+		}
+		discriminators[key] = inst.Name
+		byName[inst.Name] = inst
+	}
+
+	transfer := byName["transferChecked"]
+	if !reflect.DeepEqual(transfer.Discriminator, []int{12}) {
+		t.Errorf("Expected discriminator [12], got %v", transfer.Discriminator)
+	}
+	expectedAccounts := []string{"source", "mint", "destination", "owner"}
+	if len(transfer.Accounts) != len(expectedAccounts) {
+		t.Fatalf("Expected %d accounts, got %+v", len(expectedAccounts), transfer.Accounts)
+	}
+	for i, name := range expectedAccounts {
+		if transfer.Accounts[i].Name != name {
+			t.Errorf("Expected account %d to be %s, got %s", i, name, transfer.Accounts[i].Name)
+		}
+	}
+	if !transfer.Accounts[0].Writable || transfer.Accounts[1].Writable || !transfer.Accounts[3].Signer {
+		t.Errorf("Unexpected account flags %+v", transfer.Accounts)
+	}
+	if len(transfer.RemainingAccounts) != 2 || transfer.RemainingAccounts[0].Name != "signers" || !transfer.RemainingAccounts[0].Signer {
+		t.Errorf("Unexpected remaining accounts %+v", transfer.RemainingAccounts)
+	}
+	expectedArgs := []IDLField{
+		{Name: "amount", Type: IDLType{Primitive: "u64"}},
+		{Name: "decimals", Type: IDLType{Primitive: "u8"}},
+	}
+	if !reflect.DeepEqual(transfer.Args, expectedArgs) {
+		t.Errorf("Expected args %+v, got %+v", expectedArgs, transfer.Args)
+	}
+
+	if args := byName["initializeMint2"].Args; len(args) != 3 || args[2].Type.Option == nil || args[2].Type.Option.Primitive != "pubkey" {
+		t.Errorf("Expected freezeAuthority to be an option, got %+v", args)
+	}
+	pointer := byName["initializeMetadataPointer"]
+	if !reflect.DeepEqual(pointer.Discriminator, []int{int(InstructionMetadataPointerExtension), int(ExtensionInstructionInitialize)}) {
+		t.Errorf("Unexpected discriminator %v", pointer.Discriminator)
+	}
+	if args := pointer.Args; len(args) != 2 || args[0].Type.Defined != "OptionalNonZeroPubkey" {
+		t.Errorf("Expected authority to be an OptionalNonZeroPubkey, got %+v", args)
+	}
+
+	defined := make(map[string]bool)
+	for _, def := range idl.Types {
+		defined[def.Name] = true
+	}
+	for _, inst := range idl.Instructions {
+		for _, arg := range inst.Args {
+			if arg.Type.Defined != "" && !defined[arg.Type.Defined] {
+				t.Errorf("%s: type %s of %s is not defined", inst.Name, arg.Type.Defined, arg.Name)
+			}
+		}
+	}
+
+	data, err := json.Marshal(idl)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var decoded IDL
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !reflect.DeepEqual(&decoded, idl) {
+		t.Errorf("Expected the IDL to survive a JSON round trip")
+	}
+}
+
+func TestAssociatedTokenIDL(t *testing.T) {
+
+	idl, err := AssociatedTokenIDL()
+	if err != nil {
+		t.Fatalf("AssociatedTokenIDL failed: %v", err)
+	}
+	if len(idl.Instructions) != 2 {
+		t.Fatalf("Expected 2 instructions, got %d", len(idl.Instructions))
+	}
+	create := idl.Instructions[1]
+	if create.Name != "createIdempotent" || !reflect.DeepEqual(create.Discriminator, []int{1}) {
+		t.Errorf("Unexpected instruction %s %v", create.Name, create.Discriminator)
+	}
+	if len(create.Accounts) != 7 || create.Accounts[1].Name != "associatedTokenAccount" || create.Accounts[6].Name != "rent" {
+		t.Errorf("Unexpected accounts %+v", create.Accounts)
+	}
+}
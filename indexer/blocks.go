@@ -0,0 +1,216 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dwmfan/token2022"
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// BlockClient is the set of RPC calls used by BlockSource. *rpc.Client
+// satisfies it.
+type BlockClient interface {
+	GetSlot(ctx context.Context, commitment rpc.CommitmentType) (uint64, error)
+	GetBlocks(ctx context.Context, startSlot uint64, endSlot *uint64, commitment rpc.CommitmentType) (rpc.BlocksResult, error)
+	GetBlockWithOpts(ctx context.Context, slot uint64, opts *rpc.GetBlockOpts) (*rpc.GetBlockResult, error)
+	token2022.MultipleAccountsClient
+}
+
+var _ BlockClient = (*rpc.Client)(nil)
+
+// BlockSource reads confirmed blocks over RPC. It collects the writable
+// accounts of the successful transactions that invoke a configured
+// program, then reads their current state with getMultipleAccounts at or
+// after the last slot of the batch. Accounts touched again later are read
+// again by a later batch.
+type BlockSource struct {
+	client       BlockClient
+	programs     map[solana.PublicKey]bool
+	commitment   rpc.CommitmentType
+	maxSlots     uint64
+	pollInterval time.Duration
+}
+
+// NewBlockSource creates a source of Token-2022 accounts reading up to
+// 100 slots per batch at confirmed commitment and polling every two
+// seconds once it has caught up.
+func NewBlockSource(client BlockClient) *BlockSource {
+	return &BlockSource{
+		client:       client,
+		programs:     map[solana.PublicKey]bool{solana.Token2022ProgramID: true},
+		commitment:   rpc.CommitmentConfirmed,
+		maxSlots:     100,
+		pollInterval: 2 * time.Second,
+	}
+}
+
+// SetPrograms sets the programs whose transactions are followed.
+func (s *BlockSource) SetPrograms(programs ...solana.PublicKey) *BlockSource {
+	s.programs = map[solana.PublicKey]bool{}
+	for _, program := range programs {
+		s.programs[program] = true
+	}
+	return s
+}
+
+func (s *BlockSource) SetCommitment(commitment rpc.CommitmentType) *BlockSource {
+	s.commitment = commitment
+	return s
+}
+
+// SetMaxSlots sets the largest number of slots in one batch.
+func (s *BlockSource) SetMaxSlots(slots uint64) *BlockSource {
+	if slots > 0 {
+		s.maxSlots = slots
+	}
+	return s
+}
+
+func (s *BlockSource) SetPollInterval(interval time.Duration) *BlockSource {
+	s.pollInterval = interval
+	return s
+}
+
+// Next waits for slots after cursor and returns the state of the accounts
+// their transactions wrote. A cursor of zero starts at the current slot.
+func (s *BlockSource) Next(ctx context.Context, cursor uint64) (*Batch, error) {
+	for {
+		tip, err := s.client.GetSlot(ctx, s.commitment)
+		if err != nil {
+			return nil, fmt.Errorf("error while getting slot: %w", err)
+		}
+		if cursor == 0 && tip > 0 {
+			cursor = tip - 1
+		}
+		if tip > cursor {
+			return s.scan(ctx, cursor+1, min(tip, cursor+s.maxSlots))
+		}
+
+		timer := time.NewTimer(s.pollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (s *BlockSource) scan(ctx context.Context, start, end uint64) (*Batch, error) {
+	slots, err := s.client.GetBlocks(ctx, start, &end, s.commitment)
+	if err != nil {
+		return nil, fmt.Errorf("error while listing blocks: %w", err)
+	}
+	rewards := false
+	opts := &rpc.GetBlockOpts{
+		Encoding:                       solana.EncodingBase64,
+		TransactionDetails:             rpc.TransactionDetailsFull,
+		Rewards:                        &rewards,
+		Commitment:                     s.commitment,
+		MaxSupportedTransactionVersion: &rpc.MaxSupportedTransactionVersion0,
+	}
+
+	var touched []solana.PublicKey
+	seen := map[solana.PublicKey]bool{}
+	for _, slot := range slots {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		block, err := s.client.GetBlockWithOpts(ctx, slot, opts)
+		if err != nil {
+			return nil, fmt.Errorf("error while getting block %d: %w", slot, err)
+		}
+		for _, txWithMeta := range block.Transactions {
+			if txWithMeta.Meta == nil || txWithMeta.Meta.Err != nil {
+				continue
+			}
+			tx, err := txWithMeta.GetTransaction()
+			if err != nil {
+				return nil, fmt.Errorf("error while decoding transaction in block %d: %w", slot, err)
+			}
+			for _, account := range s.writableAccounts(tx, txWithMeta.Meta) {
+				if !seen[account] {
+					seen[account] = true
+					touched = append(touched, account)
+				}
+			}
+		}
+	}
+
+	builder := newBatchBuilder(end)
+	if len(touched) == 0 {
+		return builder.batch(), nil
+	}
+	accounts, err := token2022.NewAccountFetcher(s.client).
+		SetFetchOpts(*token2022.FetchOptsAtSlot(s.commitment, end)).
+		Fetch(ctx, touched)
+	if err != nil {
+		return nil, err
+	}
+	for i, account := range accounts {
+		if account == nil {
+			// Written but gone: closed, or never a token account.
+			if err := builder.add(touched[i], solana.PublicKey{}, end, 0, 0, nil); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if !s.programs[account.Owner] {
+			continue
+		}
+		if err := builder.add(touched[i], account.Owner, end, 0, account.Lamports, account.Data.GetBinary()); err != nil {
+			return nil, err
+		}
+	}
+	return builder.batch(), nil
+}
+
+// writableAccounts returns the writable accounts of a transaction that
+// invokes one of the programs, or nil.
+func (s *BlockSource) writableAccounts(tx *solana.Transaction, meta *rpc.TransactionMeta) []solana.PublicKey {
+	keys := append(solana.PublicKeySlice{}, tx.Message.AccountKeys...)
+	keys = append(keys, meta.LoadedAddresses.Writable...)
+	keys = append(keys, meta.LoadedAddresses.ReadOnly...)
+
+	invokes := false
+	for _, key := range keys {
+		if s.programs[key] {
+			invokes = true
+			break
+		}
+	}
+	if !invokes {
+		return nil
+	}
+
+	var out []solana.PublicKey
+	header := tx.Message.Header
+	signed := int(header.NumRequiredSignatures)
+	for i, key := range tx.Message.AccountKeys {
+		writable := i < signed-int(header.NumReadonlySignedAccounts)
+		if i >= signed {
+			writable = i < len(tx.Message.AccountKeys)-int(header.NumReadonlyUnsignedAccounts)
+		}
+		if writable {
+			out = append(out, key)
+		}
+	}
+	return append(out, meta.LoadedAddresses.Writable...)
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dwmfan/token2022/geyser"
+)
+
+// GeyserSource reads account updates from a Yellowstone gRPC
+// subscription and groups them into one batch per slot. A batch is
+// returned once an update of a later slot arrives.
+type GeyserSource struct {
+	client  *geyser.Client
+	filter  geyser.Filter
+	stream  *geyser.Stream
+	pending *geyser.AccountEvent
+}
+
+// NewGeyserSource creates a source subscribing with filter on the first
+// call of Next. Transactions are not streamed. Unless filter sets
+// FromSlot, the subscription resumes from the slot after the cursor of
+// the store, which only works while the server still has that slot.
+func NewGeyserSource(client *geyser.Client, filter geyser.Filter) *GeyserSource {
+	filter.SkipTransactions = true
+	return &GeyserSource{client: client, filter: filter}
+}
+
+// Next returns the updates of the next slot after cursor. The
+// subscription lives until the ctx of the first call is done.
+func (s *GeyserSource) Next(ctx context.Context, cursor uint64) (*Batch, error) {
+	if s.stream == nil {
+		filter := s.filter
+		if filter.FromSlot == nil && cursor > 0 {
+			from := cursor + 1
+			filter.FromSlot = &from
+		}
+		stream, err := s.client.Subscribe(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("error while subscribing: %w", err)
+		}
+		s.stream = stream
+	}
+
+	var builder *batchBuilder
+	if s.pending != nil {
+		builder = newBatchBuilder(s.pending.Slot)
+		if err := builder.add(s.pending.Pubkey, s.pending.Owner, s.pending.Slot, s.pending.WriteVersion, s.pending.Lamports, s.pending.Data); err != nil {
+			return nil, err
+		}
+		s.pending = nil
+	}
+	for {
+		event, err := s.stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		account, ok := event.(*geyser.AccountEvent)
+		if !ok || account.Slot <= cursor {
+			continue
+		}
+		if builder == nil {
+			builder = newBatchBuilder(account.Slot)
+		}
+		if account.Slot > builder.slot {
+			s.pending = account
+			return builder.batch(), nil
+		}
+		// Late updates of older slots join the current batch; the store
+		// keeps whichever record has the higher slot.
+		if err := builder.add(account.Pubkey, account.Owner, account.Slot, account.WriteVersion, account.Lamports, account.Data); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package indexer maintains the current state of token mints and token
+// accounts from a stream of account updates, such as a Yellowstone gRPC
+// subscription or confirmed blocks read over RPC, and writes it to a
+// pluggable Store. Accounts are decoded with the token2022 package, so
+// every supported extension is available to the store.
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dwmfan/token2022"
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// ErrNotFound is returned by Store lookups for accounts that are not
+// indexed.
+var ErrNotFound = errors.New("account not found")
+
+// MintRecord is the indexed state of a mint.
+type MintRecord struct {
+	Pubkey solana.PublicKey
+	// ProgramID is the token program owning the mint.
+	ProgramID solana.PublicKey
+	// Slot is the slot of the update the record was built from.
+	Slot uint64
+	// WriteVersion orders the updates of an account within a slot, when
+	// the source provides it.
+	WriteVersion uint64
+	Lamports     uint64
+	Data         []byte
+	Mint         *token2022.Mint
+}
+
+// TokenAccountRecord is the indexed state of a token account.
+type TokenAccountRecord struct {
+	Pubkey       solana.PublicKey
+	ProgramID    solana.PublicKey
+	Slot         uint64
+	WriteVersion uint64
+	Lamports     uint64
+	Data         []byte
+	Account      *token2022.TokenAccount
+}
+
+// Batch is a set of account updates, applied to a Store at once.
+type Batch struct {
+	// Slot is the slot the batch brings the store to.
+	Slot          uint64
+	Mints         []*MintRecord
+	TokenAccounts []*TokenAccountRecord
+	// Closed lists the accounts closed in the batch.
+	Closed []solana.PublicKey
+}
+
+// Store persists indexed state. Implementations must apply a batch
+// atomically and idempotently:
+//
+//   - a record replaces the stored record of the same account unless the
+//     stored one is newer: it has a higher Slot, or the same Slot and a
+//     higher WriteVersion;
+//   - a closed account is deleted unless its stored record has a higher
+//     Slot than the batch;
+//   - the cursor becomes the batch Slot if that is higher.
+//
+// Lookups of accounts that are not indexed return ErrNotFound. Lists are
+// ordered by base58 account address.
+type Store interface {
+	// Cursor returns the Slot of the last applied batch, or zero.
+	Cursor(ctx context.Context) (uint64, error)
+	Apply(ctx context.Context, batch *Batch) error
+	Mint(ctx context.Context, pubkey solana.PublicKey) (*MintRecord, error)
+	TokenAccount(ctx context.Context, pubkey solana.PublicKey) (*TokenAccountRecord, error)
+	TokenAccountsByOwner(ctx context.Context, owner solana.PublicKey) ([]*TokenAccountRecord, error)
+	TokenAccountsByMint(ctx context.Context, mint solana.PublicKey) ([]*TokenAccountRecord, error)
+}
+
+// Source produces batches of account updates.
+type Source interface {
+	// Next blocks until the next batch after cursor is available. The
+	// same ctx is passed to every call of a run.
+	Next(ctx context.Context, cursor uint64) (*Batch, error)
+}
+
+// Indexer reads batches from a Source and applies the accounts owned by
+// the configured programs to a Store.
+type Indexer struct {
+	store    Store
+	programs map[solana.PublicKey]bool
+	onBatch  func(*Batch)
+}
+
+// New creates an indexer of Token-2022 accounts.
+func New(store Store) *Indexer {
+	return &Indexer{
+		store:    store,
+		programs: map[solana.PublicKey]bool{solana.Token2022ProgramID: true},
+	}
+}
+
+// SetPrograms sets the token programs whose accounts are indexed. Other
+// programs that share the Token-2022 layout, such as the legacy Token
+// program, can be included.
+func (ix *Indexer) SetPrograms(programs ...solana.PublicKey) *Indexer {
+	ix.programs = map[solana.PublicKey]bool{}
+	for _, program := range programs {
+		ix.programs[program] = true
+	}
+	return ix
+}
+
+// SetOnBatch sets a callback run after every applied batch.
+func (ix *Indexer) SetOnBatch(onBatch func(*Batch)) *Indexer {
+	ix.onBatch = onBatch
+	return ix
+}
+
+// Run applies batches from source, starting after the cursor of the
+// store, until ctx is done or source or store fails.
+func (ix *Indexer) Run(ctx context.Context, source Source) error {
+	cursor, err := ix.store.Cursor(ctx)
+	if err != nil {
+		return fmt.Errorf("error while reading cursor: %w", err)
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		batch, err := source.Next(ctx, cursor)
+		if err != nil {
+			return err
+		}
+		batch = ix.filter(batch)
+		if err := ix.store.Apply(ctx, batch); err != nil {
+			return fmt.Errorf("error while applying slot %d: %w", batch.Slot, err)
+		}
+		if ix.onBatch != nil {
+			ix.onBatch(batch)
+		}
+		if batch.Slot > cursor {
+			cursor = batch.Slot
+		}
+	}
+}
+
+// filter drops the records of other programs.
+func (ix *Indexer) filter(batch *Batch) *Batch {
+	out := &Batch{Slot: batch.Slot, Closed: batch.Closed}
+	for _, record := range batch.Mints {
+		if ix.programs[record.ProgramID] {
+			out.Mints = append(out.Mints, record)
+		}
+	}
+	for _, record := range batch.TokenAccounts {
+		if ix.programs[record.ProgramID] {
+			out.TokenAccounts = append(out.TokenAccounts, record)
+		}
+	}
+	return out
+}
+
+// batchBuilder collects the updates of a batch, keeping the last update
+// of each account.
+type batchBuilder struct {
+	slot    uint64
+	order   []solana.PublicKey
+	updates map[solana.PublicKey]interface{}
+}
+
+func newBatchBuilder(slot uint64) *batchBuilder {
+	return &batchBuilder{slot: slot, updates: map[solana.PublicKey]interface{}{}}
+}
+
+// add decodes an account update. Accounts without lamports are closed;
+// accounts that are neither mints nor token accounts, such as multisigs,
+// are ignored.
+func (b *batchBuilder) add(pubkey, programID solana.PublicKey, slot, writeVersion, lamports uint64, data []byte) error {
+	var update interface{}
+	switch {
+	case lamports == 0:
+		update = nil
+	case token2022.AccountTypeOf(data) == token2022.AccountTypeMint:
+		mint, err := token2022.DecodeMint(data)
+		if err != nil {
+			return fmt.Errorf("error while decoding mint %s: %w", pubkey, err)
+		}
+		update = &MintRecord{Pubkey: pubkey, ProgramID: programID, Slot: slot, WriteVersion: writeVersion, Lamports: lamports, Data: data, Mint: mint}
+	case token2022.AccountTypeOf(data) == token2022.AccountTypeAccount:
+		account, err := token2022.DecodeTokenAccount(data)
+		if err != nil {
+			return fmt.Errorf("error while decoding token account %s: %w", pubkey, err)
+		}
+		update = &TokenAccountRecord{Pubkey: pubkey, ProgramID: programID, Slot: slot, WriteVersion: writeVersion, Lamports: lamports, Data: data, Account: account}
+	default:
+		return nil
+	}
+	if _, ok := b.updates[pubkey]; !ok {
+		b.order = append(b.order, pubkey)
+	}
+	b.updates[pubkey] = update
+	return nil
+}
+
+func (b *batchBuilder) batch() *Batch {
+	batch := &Batch{Slot: b.slot}
+	for _, pubkey := range b.order {
+		switch update := b.updates[pubkey].(type) {
+		case *MintRecord:
+			batch.Mints = append(batch.Mints, update)
+		case *TokenAccountRecord:
+			batch.TokenAccounts = append(batch.TokenAccounts, update)
+		default:
+			batch.Closed = append(batch.Closed, pubkey)
+		}
+	}
+	return batch
+}
+
+// isNewer reports whether the update at slot and writeVersion is newer
+// than the one at otherSlot and otherWriteVersion.
+func isNewer(slot, writeVersion, otherSlot, otherWriteVersion uint64) bool {
+	return slot > otherSlot || (slot == otherSlot && writeVersion > otherWriteVersion)
+}
@@ -0,0 +1,206 @@
+package indexer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dwmfan/token2022"
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+var (
+	wallet      = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+	destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+)
+
+func tokenAccountRecord(pubkey solana.PublicKey, slot, amount uint64) *TokenAccountRecord {
+	return &TokenAccountRecord{
+		Pubkey:    pubkey,
+		ProgramID: solana.Token2022ProgramID,
+		Slot:      slot,
+		Lamports:  2_039_280,
+		Account:   &token2022.TokenAccount{Mint: mint, Owner: wallet, Amount: amount, State: token2022.AccountStateInitialized},
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+
+	if err := store.Apply(ctx, &Batch{
+		Slot:          10,
+		Mints:         []*MintRecord{{Pubkey: mint, ProgramID: solana.Token2022ProgramID, Slot: 10, Mint: &token2022.Mint{Decimals: 6}}},
+		TokenAccounts: []*TokenAccountRecord{tokenAccountRecord(source, 10, 100), tokenAccountRecord(destination, 10, 5)},
+	}); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	// A replayed older batch changes nothing.
+	if err := store.Apply(ctx, &Batch{
+		Slot:          8,
+		TokenAccounts: []*TokenAccountRecord{tokenAccountRecord(source, 8, 1)},
+		Closed:        []solana.PublicKey{destination},
+	}); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	if cursor, _ := store.Cursor(ctx); cursor != 10 {
+		t.Errorf("Expected cursor 10, got %d", cursor)
+	}
+	if record, err := store.TokenAccount(ctx, source); err != nil || record.Account.Amount != 100 {
+		t.Errorf("Expected the slot 10 record, got %+v, %v", record, err)
+	}
+	accounts, err := store.TokenAccountsByOwner(ctx, wallet)
+	if err != nil || len(accounts) != 2 || !accounts[0].Pubkey.Equals(destination) {
+		t.Errorf("Expected both accounts in address order, got %v, %v", accounts, err)
+	}
+
+	if err := store.Apply(ctx, &Batch{Slot: 12, Closed: []solana.PublicKey{destination}}); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if _, err := store.TokenAccount(ctx, destination); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected the closed account to be gone, got %v", err)
+	}
+	if accounts, _ := store.TokenAccountsByMint(ctx, mint); len(accounts) != 1 {
+		t.Errorf("Expected 1 account of the mint, got %d", len(accounts))
+	}
+	if record, err := store.Mint(ctx, mint); err != nil || record.Mint.Decimals != 6 {
+		t.Errorf("Unexpected mint %+v, %v", record, err)
+	}
+}
+
+func TestBatchBuilder(t *testing.T) {
+	builder := newBatchBuilder(20)
+	data := token2022.EncodeTokenAccount(&token2022.TokenAccount{Mint: mint, Owner: wallet, Amount: 1, State: token2022.AccountStateInitialized})
+	updated := token2022.EncodeTokenAccount(&token2022.TokenAccount{Mint: mint, Owner: wallet, Amount: 2, State: token2022.AccountStateInitialized})
+	for _, update := range []struct {
+		pubkey   solana.PublicKey
+		lamports uint64
+		data     []byte
+	}{
+		{source, 1, data},
+		{destination, 1, data},
+		{wallet, 1, make([]byte, token2022.MultisigSize)},
+		{source, 1, updated},
+		{destination, 0, nil},
+		{mint, 1, token2022.EncodeMint(&token2022.Mint{Decimals: 9, IsInitialized: true})},
+	} {
+		if err := builder.add(update.pubkey, solana.Token2022ProgramID, 20, 0, update.lamports, update.data); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+	}
+	batch := builder.batch()
+	if len(batch.TokenAccounts) != 1 || batch.TokenAccounts[0].Account.Amount != 2 {
+		t.Errorf("Expected the last update of the source, got %+v", batch.TokenAccounts)
+	}
+	if len(batch.Closed) != 1 || !batch.Closed[0].Equals(destination) {
+		t.Errorf("Expected the destination to be closed, got %v", batch.Closed)
+	}
+	if len(batch.Mints) != 1 || batch.Mints[0].Mint.Decimals != 9 {
+		t.Errorf("Unexpected mints %+v", batch.Mints)
+	}
+}
+
+type blockRPC struct {
+	slot     uint64
+	blocks   map[uint64]*rpc.GetBlockResult
+	accounts map[solana.PublicKey]*rpc.Account
+	fetched  []solana.PublicKey
+}
+
+func (m *blockRPC) GetSlot(ctx context.Context, commitment rpc.CommitmentType) (uint64, error) {
+	return m.slot, nil
+}
+
+func (m *blockRPC) GetBlocks(ctx context.Context, startSlot uint64, endSlot *uint64, commitment rpc.CommitmentType) (rpc.BlocksResult, error) {
+	var out rpc.BlocksResult
+	for slot := startSlot; slot <= *endSlot; slot++ {
+		if _, ok := m.blocks[slot]; ok {
+			out = append(out, slot)
+		}
+	}
+	return out, nil
+}
+
+func (m *blockRPC) GetBlockWithOpts(ctx context.Context, slot uint64, opts *rpc.GetBlockOpts) (*rpc.GetBlockResult, error) {
+	return m.blocks[slot], nil
+}
+
+func (m *blockRPC) GetMultipleAccountsWithOpts(ctx context.Context, accounts []solana.PublicKey, opts *rpc.GetMultipleAccountsOpts) (*rpc.GetMultipleAccountsResult, error) {
+	m.fetched = append(m.fetched, accounts...)
+	out := &rpc.GetMultipleAccountsResult{}
+	for _, account := range accounts {
+		out.Value = append(out.Value, m.accounts[account])
+	}
+	return out, nil
+}
+
+func TestIndexerBlockSource(t *testing.T) {
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{token2022.NewTransferChecked2022Instruction(40, 6, source, mint, destination, wallet).Build()},
+		solana.Hash{1},
+		solana.TransactionPayer(wallet),
+	)
+	if err != nil {
+		t.Fatalf("NewTransaction: %v", err)
+	}
+	tx.Signatures = []solana.Signature{{1}}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+
+	client := &blockRPC{
+		slot: 105,
+		blocks: map[uint64]*rpc.GetBlockResult{
+			103: {Transactions: []rpc.TransactionWithMeta{{Transaction: rpc.DataBytesOrJSONFromBytes(raw), Meta: &rpc.TransactionMeta{}}}},
+		},
+		accounts: map[solana.PublicKey]*rpc.Account{
+			wallet: {Owner: solana.SystemProgramID, Lamports: 1_000_000_000, Data: rpc.DataBytesOrJSONFromBytes(nil)},
+			source: {
+				Owner:    solana.Token2022ProgramID,
+				Lamports: 2_039_280,
+				Data:     rpc.DataBytesOrJSONFromBytes(token2022.EncodeTokenAccount(&token2022.TokenAccount{Mint: mint, Owner: wallet, Amount: 60, State: token2022.AccountStateInitialized})),
+			},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := NewMemoryStore()
+	if err := store.Apply(ctx, &Batch{Slot: 100, TokenAccounts: []*TokenAccountRecord{tokenAccountRecord(destination, 90, 5)}}); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	var batches []*Batch
+	err = New(store).
+		SetOnBatch(func(batch *Batch) {
+			batches = append(batches, batch)
+			cancel()
+		}).
+		Run(ctx, NewBlockSource(client))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected Run to stop with the context, got %v", err)
+	}
+
+	if len(batches) != 1 || batches[0].Slot != 105 {
+		t.Fatalf("Expected one batch up to slot 105, got %+v", batches)
+	}
+	// The fee payer, source and destination are writable; the mint and
+	// the programs are not.
+	if len(client.fetched) != 3 {
+		t.Errorf("Expected 3 accounts to be fetched, got %v", client.fetched)
+	}
+	if record, err := store.TokenAccount(ctx, source); err != nil || record.Account.Amount != 60 || record.Slot != 105 {
+		t.Errorf("Expected the source at slot 105, got %+v, %v", record, err)
+	}
+	if _, err := store.TokenAccount(ctx, destination); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected the closed destination to be removed, got %v", err)
+	}
+	if cursor, _ := store.Cursor(ctx); cursor != 105 {
+		t.Errorf("Expected cursor 105, got %d", cursor)
+	}
+}
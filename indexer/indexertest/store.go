@@ -0,0 +1,163 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package indexertest checks indexer.Store implementations against the
+// contract of the interface.
+package indexertest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dwmfan/token2022"
+	"github.com/dwmfan/token2022/indexer"
+	solana "github.com/gagliardetto/solana-go"
+)
+
+var (
+	wallet = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	mint   = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	first  = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+	second = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+)
+
+func tokenAccount(pubkey solana.PublicKey, slot, writeVersion, amount uint64) *indexer.TokenAccountRecord {
+	account := &token2022.TokenAccount{Mint: mint, Owner: wallet, Amount: amount, State: token2022.AccountStateInitialized}
+	return &indexer.TokenAccountRecord{
+		Pubkey:       pubkey,
+		ProgramID:    solana.Token2022ProgramID,
+		Slot:         slot,
+		WriteVersion: writeVersion,
+		Lamports:     2_039_280,
+		Data:         token2022.EncodeTokenAccount(account),
+		Account:      account,
+	}
+}
+
+// TestStore runs the Store contract tests against stores returned by
+// open, which must be empty.
+func TestStore(t *testing.T, open func(t *testing.T) indexer.Store) {
+	t.Run("Empty", func(t *testing.T) {
+		ctx := context.Background()
+		store := open(t)
+		if cursor, err := store.Cursor(ctx); err != nil || cursor != 0 {
+			t.Errorf("Expected cursor 0, got %d, %v", cursor, err)
+		}
+		if _, err := store.Mint(ctx, mint); !errors.Is(err, indexer.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound for a mint, got %v", err)
+		}
+		if _, err := store.TokenAccount(ctx, first); !errors.Is(err, indexer.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound for a token account, got %v", err)
+		}
+		if records, err := store.TokenAccountsByOwner(ctx, wallet); err != nil || len(records) != 0 {
+			t.Errorf("Expected no accounts, got %v, %v", records, err)
+		}
+	})
+
+	t.Run("Apply", func(t *testing.T) {
+		ctx := context.Background()
+		store := open(t)
+		authority := wallet
+		decoded := &token2022.Mint{MintAuthority: &authority, Supply: 1 << 63, Decimals: 6, IsInitialized: true}
+		apply(t, store, &indexer.Batch{
+			Slot: 10,
+			Mints: []*indexer.MintRecord{{
+				Pubkey:    mint,
+				ProgramID: solana.Token2022ProgramID,
+				Slot:      10,
+				Lamports:  1_461_600,
+				Data:      token2022.EncodeMint(decoded),
+				Mint:      decoded,
+			}},
+			TokenAccounts: []*indexer.TokenAccountRecord{tokenAccount(second, 10, 1, 100), tokenAccount(first, 10, 1, 5)},
+		})
+
+		if cursor, _ := store.Cursor(ctx); cursor != 10 {
+			t.Errorf("Expected cursor 10, got %d", cursor)
+		}
+		record, err := store.Mint(ctx, mint)
+		if err != nil {
+			t.Fatalf("Mint: %v", err)
+		}
+		if record.Slot != 10 || record.Lamports != 1_461_600 || record.Mint.Supply != 1<<63 || record.Mint.MintAuthority == nil || !record.Mint.MintAuthority.Equals(wallet) {
+			t.Errorf("Unexpected mint %+v", record)
+		}
+		for _, list := range []func(context.Context, solana.PublicKey) ([]*indexer.TokenAccountRecord, error){
+			func(ctx context.Context, _ solana.PublicKey) ([]*indexer.TokenAccountRecord, error) {
+				return store.TokenAccountsByOwner(ctx, wallet)
+			},
+			func(ctx context.Context, _ solana.PublicKey) ([]*indexer.TokenAccountRecord, error) {
+				return store.TokenAccountsByMint(ctx, mint)
+			},
+		} {
+			records, err := list(ctx, solana.PublicKey{})
+			if err != nil || len(records) != 2 || !records[0].Pubkey.Equals(first) || records[1].Account.Amount != 100 {
+				t.Errorf("Expected both accounts in address order, got %v, %v", records, err)
+			}
+		}
+	})
+
+	t.Run("Ordering", func(t *testing.T) {
+		ctx := context.Background()
+		store := open(t)
+		apply(t, store, &indexer.Batch{Slot: 10, TokenAccounts: []*indexer.TokenAccountRecord{tokenAccount(first, 10, 5, 1)}})
+
+		for _, c := range []struct {
+			record *indexer.TokenAccountRecord
+			want   uint64
+		}{
+			{tokenAccount(first, 9, 9, 2), 1},  // older slot
+			{tokenAccount(first, 10, 4, 3), 1}, // older write version
+			{tokenAccount(first, 10, 6, 4), 4}, // newer write version
+			{tokenAccount(first, 11, 0, 5), 5}, // newer slot
+		} {
+			apply(t, store, &indexer.Batch{Slot: c.record.Slot, TokenAccounts: []*indexer.TokenAccountRecord{c.record}})
+			record, err := store.TokenAccount(ctx, first)
+			if err != nil || record.Account.Amount != c.want {
+				t.Errorf("After slot %d version %d: expected amount %d, got %+v, %v", c.record.Slot, c.record.WriteVersion, c.want, record, err)
+			}
+		}
+		if cursor, _ := store.Cursor(ctx); cursor != 11 {
+			t.Errorf("Expected cursor 11, got %d", cursor)
+		}
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		ctx := context.Background()
+		store := open(t)
+		apply(t, store, &indexer.Batch{Slot: 10, TokenAccounts: []*indexer.TokenAccountRecord{tokenAccount(first, 10, 0, 1)}})
+
+		// A replayed older close does not delete a newer record.
+		apply(t, store, &indexer.Batch{Slot: 9, Closed: []solana.PublicKey{first}})
+		if _, err := store.TokenAccount(ctx, first); err != nil {
+			t.Errorf("Expected the account to survive an older close, got %v", err)
+		}
+		if cursor, _ := store.Cursor(ctx); cursor != 10 {
+			t.Errorf("Expected the cursor to stay at 10, got %d", cursor)
+		}
+
+		apply(t, store, &indexer.Batch{Slot: 12, Closed: []solana.PublicKey{first}})
+		if _, err := store.TokenAccount(ctx, first); !errors.Is(err, indexer.ErrNotFound) {
+			t.Errorf("Expected the account to be closed, got %v", err)
+		}
+	})
+}
+
+func apply(t *testing.T, store indexer.Store, batch *indexer.Batch) {
+	t.Helper()
+	if err := store.Apply(context.Background(), batch); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+}
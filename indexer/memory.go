@@ -0,0 +1,115 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexer
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// MemoryStore is a Store kept in memory, for tests and for small sets of
+// accounts that are rebuilt on start.
+type MemoryStore struct {
+	mu       sync.RWMutex
+	cursor   uint64
+	mints    map[solana.PublicKey]*MintRecord
+	accounts map[solana.PublicKey]*TokenAccountRecord
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		mints:    map[solana.PublicKey]*MintRecord{},
+		accounts: map[solana.PublicKey]*TokenAccountRecord{},
+	}
+}
+
+func (s *MemoryStore) Cursor(ctx context.Context) (uint64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cursor, nil
+}
+
+func (s *MemoryStore) Apply(ctx context.Context, batch *Batch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, record := range batch.Mints {
+		if stored, ok := s.mints[record.Pubkey]; !ok || !isNewer(stored.Slot, stored.WriteVersion, record.Slot, record.WriteVersion) {
+			s.mints[record.Pubkey] = record
+		}
+	}
+	for _, record := range batch.TokenAccounts {
+		if stored, ok := s.accounts[record.Pubkey]; !ok || !isNewer(stored.Slot, stored.WriteVersion, record.Slot, record.WriteVersion) {
+			s.accounts[record.Pubkey] = record
+		}
+	}
+	for _, pubkey := range batch.Closed {
+		if stored, ok := s.mints[pubkey]; ok && stored.Slot <= batch.Slot {
+			delete(s.mints, pubkey)
+		}
+		if stored, ok := s.accounts[pubkey]; ok && stored.Slot <= batch.Slot {
+			delete(s.accounts, pubkey)
+		}
+	}
+	if batch.Slot > s.cursor {
+		s.cursor = batch.Slot
+	}
+	return nil
+}
+
+func (s *MemoryStore) Mint(ctx context.Context, pubkey solana.PublicKey) (*MintRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	record, ok := s.mints[pubkey]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return record, nil
+}
+
+func (s *MemoryStore) TokenAccount(ctx context.Context, pubkey solana.PublicKey) (*TokenAccountRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	record, ok := s.accounts[pubkey]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return record, nil
+}
+
+func (s *MemoryStore) TokenAccountsByOwner(ctx context.Context, owner solana.PublicKey) ([]*TokenAccountRecord, error) {
+	return s.tokenAccounts(func(record *TokenAccountRecord) bool { return record.Account.Owner.Equals(owner) }), nil
+}
+
+func (s *MemoryStore) TokenAccountsByMint(ctx context.Context, mint solana.PublicKey) ([]*TokenAccountRecord, error) {
+	return s.tokenAccounts(func(record *TokenAccountRecord) bool { return record.Account.Mint.Equals(mint) }), nil
+}
+
+func (s *MemoryStore) tokenAccounts(match func(*TokenAccountRecord) bool) []*TokenAccountRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out []*TokenAccountRecord
+	for _, record := range s.accounts {
+		if match(record) {
+			out = append(out, record)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Pubkey.String() < out[j].Pubkey.String() })
+	return out
+}
@@ -0,0 +1,12 @@
+package indexer_test
+
+import (
+	"testing"
+
+	"github.com/dwmfan/token2022/indexer"
+	"github.com/dwmfan/token2022/indexer/indexertest"
+)
+
+func TestMemoryStoreContract(t *testing.T) {
+	indexertest.TestStore(t, func(t *testing.T) indexer.Store { return indexer.NewMemoryStore() })
+}
@@ -0,0 +1,527 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package postgres is an indexer.Store backed by PostgreSQL, for
+// production indexers. Batches are ingested with COPY into staging
+// tables, and the store adds holder and transfer fee queries.
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/dwmfan/token2022"
+	"github.com/dwmfan/token2022/indexer"
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// migrationLock is the advisory lock key held while migrating, so that
+// several indexers starting at once migrate one at a time.
+const migrationLock = 0x746f6b32303232
+
+// migrations are applied in order; the schema version is the number of
+// migrations applied.
+var migrations = []string{
+	`CREATE TABLE indexer_cursor (
+		id   integer PRIMARY KEY CHECK (id = 0),
+		slot bigint NOT NULL
+	);
+	CREATE TABLE mints (
+		pubkey           text PRIMARY KEY,
+		program_id       text NOT NULL,
+		slot             bigint NOT NULL,
+		write_version    bigint NOT NULL,
+		lamports         bigint NOT NULL,
+		supply           numeric(20) NOT NULL,
+		decimals         smallint NOT NULL,
+		mint_authority   text,
+		freeze_authority text,
+		withheld_amount  numeric(20) NOT NULL,
+		data             bytea NOT NULL
+	);
+	CREATE TABLE token_accounts (
+		pubkey          text PRIMARY KEY,
+		program_id      text NOT NULL,
+		slot            bigint NOT NULL,
+		write_version   bigint NOT NULL,
+		lamports        bigint NOT NULL,
+		mint            text NOT NULL,
+		owner           text NOT NULL,
+		amount          numeric(20) NOT NULL,
+		state           smallint NOT NULL,
+		delegate        text,
+		withheld_amount numeric(20) NOT NULL,
+		data            bytea NOT NULL
+	);
+	CREATE INDEX token_accounts_owner ON token_accounts (owner);
+	CREATE INDEX token_accounts_mint_amount ON token_accounts (mint, amount DESC);
+	CREATE INDEX token_accounts_withheld ON token_accounts (mint) WHERE withheld_amount > 0;`,
+}
+
+var (
+	mintColumns = []string{"pubkey", "program_id", "slot", "write_version", "lamports",
+		"supply", "decimals", "mint_authority", "freeze_authority", "withheld_amount", "data"}
+	tokenAccountColumns = []string{"pubkey", "program_id", "slot", "write_version", "lamports",
+		"mint", "owner", "amount", "state", "delegate", "withheld_amount", "data"}
+)
+
+// DB is the subset of *pgxpool.Pool and *pgx.Conn used by Store.
+type DB interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+var (
+	_ DB = (*pgxpool.Pool)(nil)
+	_ DB = (*pgx.Conn)(nil)
+)
+
+// Store is an indexer.Store in a PostgreSQL database.
+type Store struct {
+	db DB
+}
+
+var _ indexer.Store = (*Store)(nil)
+
+// Open connects a pool to the database at connString and migrates it.
+// Close the pool with Store.Close.
+func Open(ctx context.Context, connString string) (*Store, error) {
+	pool, err := pgxpool.New(ctx, connString)
+	if err != nil {
+		return nil, err
+	}
+	store, err := New(ctx, pool)
+	if err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+// New migrates the database of db and returns a store using it.
+func New(ctx context.Context, db DB) (*Store, error) {
+	if err := migrate(ctx, db); err != nil {
+		return nil, fmt.Errorf("error while migrating: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the pool opened by Open. It does nothing for a store
+// created with New.
+func (s *Store) Close() {
+	if pool, ok := s.db.(*pgxpool.Pool); ok {
+		pool.Close()
+	}
+}
+
+func migrate(ctx context.Context, db DB) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, int64(migrationLock)); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version integer PRIMARY KEY)`); err != nil {
+		return err
+	}
+	var version int
+	if err := tx.QueryRow(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+		return err
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("schema version %d is newer than this package (%d)", version, len(migrations))
+	}
+	for i := version; i < len(migrations); i++ {
+		if _, err := tx.Exec(ctx, migrations[i]); err != nil {
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+		if _, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, i+1); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func (s *Store) Cursor(ctx context.Context) (uint64, error) {
+	var slot int64
+	err := s.db.QueryRow(ctx, `SELECT slot FROM indexer_cursor WHERE id = 0`).Scan(&slot)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	return uint64(slot), err
+}
+
+// Apply copies the records of the batch into temporary staging tables and
+// merges them into the store in one transaction.
+func (s *Store) Apply(ctx context.Context, batch *indexer.Batch) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if len(batch.Mints) > 0 {
+		rows := make([][]any, len(batch.Mints))
+		for i, record := range batch.Mints {
+			if rows[i], err = mintRow(record); err != nil {
+				return err
+			}
+		}
+		if err := merge(ctx, tx, "mints", mintColumns, rows); err != nil {
+			return fmt.Errorf("error while writing mints: %w", err)
+		}
+	}
+	if len(batch.TokenAccounts) > 0 {
+		rows := make([][]any, len(batch.TokenAccounts))
+		for i, record := range batch.TokenAccounts {
+			if rows[i], err = tokenAccountRow(record); err != nil {
+				return err
+			}
+		}
+		if err := merge(ctx, tx, "token_accounts", tokenAccountColumns, rows); err != nil {
+			return fmt.Errorf("error while writing token accounts: %w", err)
+		}
+	}
+	if len(batch.Closed) > 0 {
+		closed := make([]string, len(batch.Closed))
+		for i, pubkey := range batch.Closed {
+			closed[i] = pubkey.String()
+		}
+		for _, query := range []string{
+			`DELETE FROM mints WHERE pubkey = ANY($1) AND slot <= $2`,
+			`DELETE FROM token_accounts WHERE pubkey = ANY($1) AND slot <= $2`,
+		} {
+			if _, err := tx.Exec(ctx, query, closed, int64(batch.Slot)); err != nil {
+				return fmt.Errorf("error while deleting closed accounts: %w", err)
+			}
+		}
+	}
+	if _, err := tx.Exec(ctx, `INSERT INTO indexer_cursor (id, slot) VALUES (0, $1)
+		ON CONFLICT (id) DO UPDATE SET slot = GREATEST(indexer_cursor.slot, excluded.slot)`, int64(batch.Slot)); err != nil {
+		return fmt.Errorf("error while writing cursor: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+// merge copies rows into a staging table and upserts the newest row of
+// each account into table, keeping stored rows that are newer.
+func merge(ctx context.Context, tx pgx.Tx, table string, columns []string, rows [][]any) error {
+	staging := "staged_" + table
+	if _, err := tx.Exec(ctx, `CREATE TEMPORARY TABLE IF NOT EXISTS `+staging+` (LIKE `+table+`) ON COMMIT DELETE ROWS`); err != nil {
+		return err
+	}
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{staging}, columns, pgx.CopyFromRows(rows)); err != nil {
+		return err
+	}
+
+	list, updates := "", ""
+	for i, column := range columns {
+		if i > 0 {
+			list += ", "
+		}
+		list += column
+		if column != "pubkey" {
+			if updates != "" {
+				updates += ", "
+			}
+			updates += column + " = excluded." + column
+		}
+	}
+	_, err := tx.Exec(ctx, `INSERT INTO `+table+` (`+list+`)
+		SELECT DISTINCT ON (pubkey) `+list+` FROM `+staging+`
+		ORDER BY pubkey, slot DESC, write_version DESC
+		ON CONFLICT (pubkey) DO UPDATE SET `+updates+`
+		WHERE excluded.slot > `+table+`.slot
+			OR (excluded.slot = `+table+`.slot AND excluded.write_version >= `+table+`.write_version)`)
+	return err
+}
+
+func mintRow(record *indexer.MintRecord) ([]any, error) {
+	data := record.Data
+	if data == nil {
+		data = token2022.EncodeMint(record.Mint)
+	}
+	var withheld uint64
+	config, ok, err := record.Mint.TransferFeeConfig()
+	if err != nil {
+		return nil, fmt.Errorf("error while decoding transfer fee config of %s: %w", record.Pubkey, err)
+	}
+	if ok {
+		withheld = config.WithheldAmount
+	}
+	return []any{
+		record.Pubkey.String(), record.ProgramID.String(), int64(record.Slot), int64(record.WriteVersion), int64(record.Lamports),
+		numeric(record.Mint.Supply), int16(record.Mint.Decimals),
+		optionalPubkey(record.Mint.MintAuthority), optionalPubkey(record.Mint.FreezeAuthority), numeric(withheld), data,
+	}, nil
+}
+
+func tokenAccountRow(record *indexer.TokenAccountRecord) ([]any, error) {
+	data := record.Data
+	if data == nil {
+		data = token2022.EncodeTokenAccount(record.Account)
+	}
+	withheld, _, err := record.Account.WithheldAmount()
+	if err != nil {
+		return nil, fmt.Errorf("error while decoding withheld amount of %s: %w", record.Pubkey, err)
+	}
+	return []any{
+		record.Pubkey.String(), record.ProgramID.String(), int64(record.Slot), int64(record.WriteVersion), int64(record.Lamports),
+		record.Account.Mint.String(), record.Account.Owner.String(), numeric(record.Account.Amount),
+		int16(record.Account.State), optionalPubkey(record.Account.Delegate), numeric(withheld), data,
+	}, nil
+}
+
+func (s *Store) Mint(ctx context.Context, pubkey solana.PublicKey) (*indexer.MintRecord, error) {
+	row := s.db.QueryRow(ctx, `SELECT pubkey, program_id, slot, write_version, lamports, data FROM mints WHERE pubkey = $1`, pubkey.String())
+	record := &indexer.MintRecord{}
+	if err := scanRecord(row, &record.Pubkey, &record.ProgramID, &record.Slot, &record.WriteVersion, &record.Lamports, &record.Data); err != nil {
+		return nil, err
+	}
+	mint, err := token2022.DecodeMint(record.Data)
+	if err != nil {
+		return nil, fmt.Errorf("error while decoding mint %s: %w", pubkey, err)
+	}
+	record.Mint = mint
+	return record, nil
+}
+
+func (s *Store) TokenAccount(ctx context.Context, pubkey solana.PublicKey) (*indexer.TokenAccountRecord, error) {
+	records, err := s.tokenAccounts(ctx, `WHERE pubkey = $1`, pubkey.String())
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, indexer.ErrNotFound
+	}
+	return records[0], nil
+}
+
+// Lists are ordered with the C collation so that they match the byte
+// order of base58 strings regardless of the database locale.
+
+func (s *Store) TokenAccountsByOwner(ctx context.Context, owner solana.PublicKey) ([]*indexer.TokenAccountRecord, error) {
+	return s.tokenAccounts(ctx, `WHERE owner = $1 ORDER BY pubkey COLLATE "C"`, owner.String())
+}
+
+func (s *Store) TokenAccountsByMint(ctx context.Context, mint solana.PublicKey) ([]*indexer.TokenAccountRecord, error) {
+	return s.tokenAccounts(ctx, `WHERE mint = $1 ORDER BY pubkey COLLATE "C"`, mint.String())
+}
+
+func (s *Store) tokenAccounts(ctx context.Context, where string, args ...any) ([]*indexer.TokenAccountRecord, error) {
+	rows, err := s.db.Query(ctx, `SELECT pubkey, program_id, slot, write_version, lamports, data FROM token_accounts `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*indexer.TokenAccountRecord
+	for rows.Next() {
+		record := &indexer.TokenAccountRecord{}
+		if err := scanRecord(rows, &record.Pubkey, &record.ProgramID, &record.Slot, &record.WriteVersion, &record.Lamports, &record.Data); err != nil {
+			return nil, err
+		}
+		if record.Account, err = token2022.DecodeTokenAccount(record.Data); err != nil {
+			return nil, fmt.Errorf("error while decoding token account %s: %w", record.Pubkey, err)
+		}
+		out = append(out, record)
+	}
+	return out, rows.Err()
+}
+
+// Holder is the balance of one owner across its token accounts of a
+// mint.
+type Holder struct {
+	Owner    solana.PublicKey
+	Amount   uint64
+	Accounts int
+}
+
+// Holders returns the owners holding a balance of mint, largest first.
+// A limit of zero returns every holder.
+func (s *Store) Holders(ctx context.Context, mint solana.PublicKey, limit int) ([]*Holder, error) {
+	query := `SELECT owner, SUM(amount), COUNT(*) FROM token_accounts
+		WHERE mint = $1 AND amount > 0
+		GROUP BY owner ORDER BY SUM(amount) DESC, owner COLLATE "C"`
+	args := []any{mint.String()}
+	if limit > 0 {
+		query += ` LIMIT $2`
+		args = append(args, limit)
+	}
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*Holder
+	for rows.Next() {
+		var (
+			owner  string
+			amount pgtype.Numeric
+			holder = &Holder{}
+		)
+		if err := rows.Scan(&owner, &amount, &holder.Accounts); err != nil {
+			return nil, err
+		}
+		if holder.Owner, err = solana.PublicKeyFromBase58(owner); err != nil {
+			return nil, err
+		}
+		if holder.Amount, err = toUint64(amount); err != nil {
+			return nil, err
+		}
+		out = append(out, holder)
+	}
+	return out, rows.Err()
+}
+
+// HolderCount returns the number of owners holding a balance of mint.
+func (s *Store) HolderCount(ctx context.Context, mint solana.PublicKey) (int, error) {
+	var count int
+	err := s.db.QueryRow(ctx, `SELECT COUNT(DISTINCT owner) FROM token_accounts WHERE mint = $1 AND amount > 0`, mint.String()).Scan(&count)
+	return count, err
+}
+
+// WithheldFee is the transfer fee withheld in a token account.
+type WithheldFee struct {
+	Account solana.PublicKey
+	Amount  uint64
+}
+
+// WithheldFees returns the token accounts of mint holding withheld
+// transfer fees, largest first, for choosing the sources of
+// HarvestWithheldTokensToMint or WithdrawWithheldTokensFromAccounts. A
+// limit of zero returns every account.
+func (s *Store) WithheldFees(ctx context.Context, mint solana.PublicKey, limit int) ([]*WithheldFee, error) {
+	query := `SELECT pubkey, withheld_amount FROM token_accounts
+		WHERE mint = $1 AND withheld_amount > 0
+		ORDER BY withheld_amount DESC, pubkey COLLATE "C"`
+	args := []any{mint.String()}
+	if limit > 0 {
+		query += ` LIMIT $2`
+		args = append(args, limit)
+	}
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*WithheldFee
+	for rows.Next() {
+		var (
+			pubkey string
+			amount pgtype.Numeric
+			fee    = &WithheldFee{}
+		)
+		if err := rows.Scan(&pubkey, &amount); err != nil {
+			return nil, err
+		}
+		if fee.Account, err = solana.PublicKeyFromBase58(pubkey); err != nil {
+			return nil, err
+		}
+		if fee.Amount, err = toUint64(amount); err != nil {
+			return nil, err
+		}
+		out = append(out, fee)
+	}
+	return out, rows.Err()
+}
+
+// TotalWithheld returns the transfer fees of mint withheld in its token
+// accounts and in the mint itself.
+func (s *Store) TotalWithheld(ctx context.Context, mint solana.PublicKey) (inAccounts, inMint uint64, err error) {
+	var accounts, mintAmount pgtype.Numeric
+	err = s.db.QueryRow(ctx, `SELECT
+		(SELECT COALESCE(SUM(withheld_amount), 0) FROM token_accounts WHERE mint = $1),
+		(SELECT COALESCE(MAX(withheld_amount), 0) FROM mints WHERE pubkey = $1)`, mint.String()).Scan(&accounts, &mintAmount)
+	if err != nil {
+		return 0, 0, err
+	}
+	if inAccounts, err = toUint64(accounts); err != nil {
+		return 0, 0, err
+	}
+	if inMint, err = toUint64(mintAmount); err != nil {
+		return 0, 0, err
+	}
+	return inAccounts, inMint, nil
+}
+
+// scanRecord reads the columns shared by mints and token accounts.
+func scanRecord(row pgx.Row, pubkey, programID *solana.PublicKey, slot, writeVersion, lamports *uint64, data *[]byte) error {
+	var (
+		pubkeyText, programText                string
+		slotValue, versionValue, lamportsValue int64
+	)
+	err := row.Scan(&pubkeyText, &programText, &slotValue, &versionValue, &lamportsValue, data)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return indexer.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if *pubkey, err = solana.PublicKeyFromBase58(pubkeyText); err != nil {
+		return err
+	}
+	if *programID, err = solana.PublicKeyFromBase58(programText); err != nil {
+		return err
+	}
+	*slot, *writeVersion, *lamports = uint64(slotValue), uint64(versionValue), uint64(lamportsValue)
+	return nil
+}
+
+func numeric(v uint64) pgtype.Numeric {
+	return pgtype.Numeric{Int: new(big.Int).SetUint64(v), Valid: true}
+}
+
+// toUint64 converts an integral numeric, such as a sum of amounts.
+func toUint64(n pgtype.Numeric) (uint64, error) {
+	if !n.Valid || n.NaN || n.InfinityModifier != pgtype.Finite {
+		return 0, fmt.Errorf("invalid amount %v", n)
+	}
+	v := new(big.Int).Set(n.Int)
+	ten := big.NewInt(10)
+	for exp := n.Exp; exp > 0; exp-- {
+		v.Mul(v, ten)
+	}
+	for exp := n.Exp; exp < 0; exp++ {
+		var rem big.Int
+		v.QuoRem(v, ten, &rem)
+		if rem.Sign() != 0 {
+			return 0, fmt.Errorf("amount %v is not an integer", n)
+		}
+	}
+	if !v.IsUint64() {
+		return 0, fmt.Errorf("amount %v does not fit in 64 bits", n)
+	}
+	return v.Uint64(), nil
+}
+
+func optionalPubkey(pubkey *solana.PublicKey) any {
+	if pubkey == nil {
+		return nil
+	}
+	return pubkey.String()
+}
@@ -0,0 +1,161 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dwmfan/token2022"
+	"github.com/dwmfan/token2022/indexer"
+	"github.com/dwmfan/token2022/indexer/indexertest"
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// openTestStore opens a store in a fresh schema of the database named by
+// TOKEN2022_TEST_POSTGRES_URL, skipping the test when it is unset.
+func openTestStore(t *testing.T) *Store {
+	url := os.Getenv("TOKEN2022_TEST_POSTGRES_URL")
+	if url == "" {
+		t.Skip("TOKEN2022_TEST_POSTGRES_URL not set")
+	}
+	ctx := context.Background()
+
+	conn, err := pgx.Connect(ctx, url)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer conn.Close(ctx)
+	schema := fmt.Sprintf("token2022_test_%d", time.Now().UnixNano())
+	if _, err := conn.Exec(ctx, `CREATE SCHEMA `+schema); err != nil {
+		t.Fatalf("Create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		conn, err := pgx.Connect(ctx, url)
+		if err != nil {
+			return
+		}
+		defer conn.Close(ctx)
+		conn.Exec(ctx, `DROP SCHEMA `+schema+` CASCADE`)
+	})
+
+	config, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		t.Fatalf("ParseConfig: %v", err)
+	}
+	config.ConnConfig.RuntimeParams["search_path"] = schema
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		t.Fatalf("NewWithConfig: %v", err)
+	}
+	store, err := New(ctx, pool)
+	if err != nil {
+		pool.Close()
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(store.Close)
+	return store
+}
+
+func TestStore(t *testing.T) {
+	indexertest.TestStore(t, func(t *testing.T) indexer.Store {
+		return openTestStore(t)
+	})
+}
+
+func TestHolders(t *testing.T) {
+	store := openTestStore(t)
+	ctx := context.Background()
+
+	var (
+		wallet = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		other  = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		mint   = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	)
+	account := func(owner solana.PublicKey, amount, withheld uint64) *indexer.TokenAccountRecord {
+		fee := make([]byte, 8)
+		fee[0] = byte(withheld)
+		return &indexer.TokenAccountRecord{
+			Pubkey:    solana.NewWallet().PublicKey(),
+			ProgramID: solana.Token2022ProgramID,
+			Slot:      1,
+			Lamports:  2_039_280,
+			Account: &token2022.TokenAccount{
+				Mint:       mint,
+				Owner:      owner,
+				Amount:     amount,
+				Extensions: []token2022.Extension{{Type: token2022.ExtensionTransferFeeAmount, Data: fee}},
+			},
+		}
+	}
+	withheld := account(other, 0, 7)
+	batch := &indexer.Batch{Slot: 1, TokenAccounts: []*indexer.TokenAccountRecord{
+		account(wallet, 100, 0),
+		account(wallet, 50, 3),
+		account(other, 120, 0),
+		withheld,
+	}}
+	if err := store.Apply(ctx, batch); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	holders, err := store.Holders(ctx, mint, 0)
+	if err != nil {
+		t.Fatalf("Holders: %v", err)
+	}
+	if len(holders) != 2 {
+		t.Fatalf("Expected 2 holders, got %d", len(holders))
+	}
+	if holders[0].Owner != wallet || holders[0].Amount != 150 || holders[0].Accounts != 2 {
+		t.Errorf("Unexpected first holder %+v", holders[0])
+	}
+	if holders[1].Owner != other || holders[1].Amount != 120 || holders[1].Accounts != 1 {
+		t.Errorf("Unexpected second holder %+v", holders[1])
+	}
+	if count, err := store.HolderCount(ctx, mint); err != nil || count != 2 {
+		t.Errorf("Expected 2 holders, got %d, %v", count, err)
+	}
+
+	fees, err := store.WithheldFees(ctx, mint, 1)
+	if err != nil {
+		t.Fatalf("WithheldFees: %v", err)
+	}
+	if len(fees) != 1 || fees[0].Account != withheld.Pubkey || fees[0].Amount != 7 {
+		t.Errorf("Unexpected withheld fees %+v", fees)
+	}
+	inAccounts, inMint, err := store.TotalWithheld(ctx, mint)
+	if err != nil || inAccounts != 10 || inMint != 0 {
+		t.Errorf("Expected 10 withheld in accounts and 0 in mint, got %d, %d, %v", inAccounts, inMint, err)
+	}
+}
+
+func TestToUint64(t *testing.T) {
+	tests := []struct {
+		n       pgtype.Numeric
+		want    uint64
+		wantErr bool
+	}{
+		{n: numeric(42), want: 42},
+		{n: pgtype.Numeric{Int: big.NewInt(12), Exp: 2, Valid: true}, want: 1200},
+		{n: pgtype.Numeric{Int: big.NewInt(1200), Exp: -2, Valid: true}, want: 12},
+		{n: pgtype.Numeric{Int: big.NewInt(1201), Exp: -2, Valid: true}, wantErr: true},
+		{n: pgtype.Numeric{Int: new(big.Int).Lsh(big.NewInt(1), 64), Valid: true}, wantErr: true},
+		{n: pgtype.Numeric{Int: big.NewInt(-1), Valid: true}, wantErr: true},
+		{n: pgtype.Numeric{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := toUint64(tt.n)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("toUint64(%v): unexpected error %v", tt.n, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("toUint64(%v): expected %d, got %d", tt.n, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sqlite is an indexer.Store backed by SQLite, for small
+// deployments and tests. It uses the cgo driver github.com/mattn/go-sqlite3.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/dwmfan/token2022"
+	"github.com/dwmfan/token2022/indexer"
+	solana "github.com/gagliardetto/solana-go"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// migrations are applied in order; the schema version is the number of
+// migrations applied.
+var migrations = []string{
+	`CREATE TABLE cursor (
+		id   INTEGER PRIMARY KEY CHECK (id = 0),
+		slot INTEGER NOT NULL
+	);
+	CREATE TABLE mints (
+		pubkey           TEXT PRIMARY KEY,
+		program_id       TEXT NOT NULL,
+		slot             INTEGER NOT NULL,
+		write_version    INTEGER NOT NULL,
+		lamports         INTEGER NOT NULL,
+		supply           TEXT NOT NULL,
+		decimals         INTEGER NOT NULL,
+		mint_authority   TEXT,
+		freeze_authority TEXT,
+		data             BLOB NOT NULL
+	);
+	CREATE TABLE token_accounts (
+		pubkey        TEXT PRIMARY KEY,
+		program_id    TEXT NOT NULL,
+		slot          INTEGER NOT NULL,
+		write_version INTEGER NOT NULL,
+		lamports      INTEGER NOT NULL,
+		mint          TEXT NOT NULL,
+		owner         TEXT NOT NULL,
+		amount        TEXT NOT NULL,
+		state         INTEGER NOT NULL,
+		delegate      TEXT,
+		data          BLOB NOT NULL
+	);
+	CREATE INDEX token_accounts_owner ON token_accounts (owner);
+	CREATE INDEX token_accounts_mint ON token_accounts (mint);`,
+}
+
+// Store is an indexer.Store in a SQLite database.
+type Store struct {
+	db *sql.DB
+}
+
+var _ indexer.Store = (*Store)(nil)
+
+// Open opens or creates the database file at path, in WAL mode, and
+// migrates it. ":memory:" opens a private in-memory database.
+func Open(ctx context.Context, path string) (*Store, error) {
+	dsn := "file:" + path + "?_journal_mode=WAL&_busy_timeout=5000"
+	if path == ":memory:" {
+		dsn = ":memory:"
+	}
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite has a single writer; one connection also keeps an in-memory
+	// database alive and shared.
+	db.SetMaxOpenConns(1)
+	store, err := New(ctx, db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+// New migrates db, opened with the sqlite3 driver, and returns a store
+// using it.
+func New(ctx context.Context, db *sql.DB) (*Store, error) {
+	if err := migrate(ctx, db); err != nil {
+		return nil, fmt.Errorf("error while migrating: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func migrate(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY)`); err != nil {
+		return err
+	}
+	var version int
+	if err := db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+		return err
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("schema version %d is newer than this package (%d)", version, len(migrations))
+	}
+	for i := version; i < len(migrations); i++ {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, migrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES (?)`, i+1); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) Cursor(ctx context.Context) (uint64, error) {
+	var slot int64
+	err := s.db.QueryRowContext(ctx, `SELECT slot FROM cursor WHERE id = 0`).Scan(&slot)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	return uint64(slot), err
+}
+
+const (
+	upsertMint = `INSERT INTO mints
+		(pubkey, program_id, slot, write_version, lamports, supply, decimals, mint_authority, freeze_authority, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (pubkey) DO UPDATE SET
+			program_id = excluded.program_id, slot = excluded.slot, write_version = excluded.write_version,
+			lamports = excluded.lamports, supply = excluded.supply, decimals = excluded.decimals,
+			mint_authority = excluded.mint_authority, freeze_authority = excluded.freeze_authority, data = excluded.data
+		WHERE excluded.slot > mints.slot
+			OR (excluded.slot = mints.slot AND excluded.write_version >= mints.write_version)`
+	upsertTokenAccount = `INSERT INTO token_accounts
+		(pubkey, program_id, slot, write_version, lamports, mint, owner, amount, state, delegate, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (pubkey) DO UPDATE SET
+			program_id = excluded.program_id, slot = excluded.slot, write_version = excluded.write_version,
+			lamports = excluded.lamports, mint = excluded.mint, owner = excluded.owner, amount = excluded.amount,
+			state = excluded.state, delegate = excluded.delegate, data = excluded.data
+		WHERE excluded.slot > token_accounts.slot
+			OR (excluded.slot = token_accounts.slot AND excluded.write_version >= token_accounts.write_version)`
+)
+
+func (s *Store) Apply(ctx context.Context, batch *indexer.Batch) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, record := range batch.Mints {
+		data := record.Data
+		if data == nil {
+			data = token2022.EncodeMint(record.Mint)
+		}
+		if _, err := tx.ExecContext(ctx, upsertMint,
+			record.Pubkey.String(), record.ProgramID.String(), int64(record.Slot), int64(record.WriteVersion), int64(record.Lamports),
+			strconv.FormatUint(record.Mint.Supply, 10), record.Mint.Decimals,
+			optionalPubkey(record.Mint.MintAuthority), optionalPubkey(record.Mint.FreezeAuthority), data,
+		); err != nil {
+			return fmt.Errorf("error while writing mint %s: %w", record.Pubkey, err)
+		}
+	}
+	for _, record := range batch.TokenAccounts {
+		data := record.Data
+		if data == nil {
+			data = token2022.EncodeTokenAccount(record.Account)
+		}
+		if _, err := tx.ExecContext(ctx, upsertTokenAccount,
+			record.Pubkey.String(), record.ProgramID.String(), int64(record.Slot), int64(record.WriteVersion), int64(record.Lamports),
+			record.Account.Mint.String(), record.Account.Owner.String(), strconv.FormatUint(record.Account.Amount, 10),
+			int(record.Account.State), optionalPubkey(record.Account.Delegate), data,
+		); err != nil {
+			return fmt.Errorf("error while writing token account %s: %w", record.Pubkey, err)
+		}
+	}
+	for _, pubkey := range batch.Closed {
+		for _, query := range []string{
+			`DELETE FROM mints WHERE pubkey = ? AND slot <= ?`,
+			`DELETE FROM token_accounts WHERE pubkey = ? AND slot <= ?`,
+		} {
+			if _, err := tx.ExecContext(ctx, query, pubkey.String(), int64(batch.Slot)); err != nil {
+				return fmt.Errorf("error while deleting account %s: %w", pubkey, err)
+			}
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO cursor (id, slot) VALUES (0, ?)
+		ON CONFLICT (id) DO UPDATE SET slot = MAX(slot, excluded.slot)`, int64(batch.Slot)); err != nil {
+		return fmt.Errorf("error while writing cursor: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (s *Store) Mint(ctx context.Context, pubkey solana.PublicKey) (*indexer.MintRecord, error) {
+	row := s.db.QueryRowContext(ctx, `SELECT pubkey, program_id, slot, write_version, lamports, data FROM mints WHERE pubkey = ?`, pubkey.String())
+	record := &indexer.MintRecord{}
+	if err := scanRecord(row, &record.Pubkey, &record.ProgramID, &record.Slot, &record.WriteVersion, &record.Lamports, &record.Data); err != nil {
+		return nil, err
+	}
+	mint, err := token2022.DecodeMint(record.Data)
+	if err != nil {
+		return nil, fmt.Errorf("error while decoding mint %s: %w", pubkey, err)
+	}
+	record.Mint = mint
+	return record, nil
+}
+
+func (s *Store) TokenAccount(ctx context.Context, pubkey solana.PublicKey) (*indexer.TokenAccountRecord, error) {
+	records, err := s.tokenAccounts(ctx, `WHERE pubkey = ?`, pubkey.String())
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, indexer.ErrNotFound
+	}
+	return records[0], nil
+}
+
+func (s *Store) TokenAccountsByOwner(ctx context.Context, owner solana.PublicKey) ([]*indexer.TokenAccountRecord, error) {
+	return s.tokenAccounts(ctx, `WHERE owner = ? ORDER BY pubkey`, owner.String())
+}
+
+func (s *Store) TokenAccountsByMint(ctx context.Context, mint solana.PublicKey) ([]*indexer.TokenAccountRecord, error) {
+	return s.tokenAccounts(ctx, `WHERE mint = ? ORDER BY pubkey`, mint.String())
+}
+
+func (s *Store) tokenAccounts(ctx context.Context, where string, args ...interface{}) ([]*indexer.TokenAccountRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT pubkey, program_id, slot, write_version, lamports, data FROM token_accounts `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*indexer.TokenAccountRecord
+	for rows.Next() {
+		record := &indexer.TokenAccountRecord{}
+		if err := scanRecord(rows, &record.Pubkey, &record.ProgramID, &record.Slot, &record.WriteVersion, &record.Lamports, &record.Data); err != nil {
+			return nil, err
+		}
+		if record.Account, err = token2022.DecodeTokenAccount(record.Data); err != nil {
+			return nil, fmt.Errorf("error while decoding token account %s: %w", record.Pubkey, err)
+		}
+		out = append(out, record)
+	}
+	return out, rows.Err()
+}
+
+// scanRecord reads the columns shared by mints and token accounts.
+func scanRecord(row interface{ Scan(...interface{}) error }, pubkey, programID *solana.PublicKey, slot, writeVersion, lamports *uint64, data *[]byte) error {
+	var (
+		pubkeyText, programText                string
+		slotValue, versionValue, lamportsValue int64
+	)
+	err := row.Scan(&pubkeyText, &programText, &slotValue, &versionValue, &lamportsValue, data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return indexer.ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if *pubkey, err = solana.PublicKeyFromBase58(pubkeyText); err != nil {
+		return err
+	}
+	if *programID, err = solana.PublicKeyFromBase58(programText); err != nil {
+		return err
+	}
+	*slot, *writeVersion, *lamports = uint64(slotValue), uint64(versionValue), uint64(lamportsValue)
+	return nil
+}
+
+func optionalPubkey(pubkey *solana.PublicKey) interface{} {
+	if pubkey == nil {
+		return nil
+	}
+	return pubkey.String()
+}
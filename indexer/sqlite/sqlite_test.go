@@ -0,0 +1,63 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/dwmfan/token2022"
+	"github.com/dwmfan/token2022/indexer"
+	"github.com/dwmfan/token2022/indexer/indexertest"
+	solana "github.com/gagliardetto/solana-go"
+)
+
+func TestStore(t *testing.T) {
+	indexertest.TestStore(t, func(t *testing.T) indexer.Store {
+		store, err := Open(context.Background(), ":memory:")
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+		t.Cleanup(func() { store.Close() })
+		return store
+	})
+}
+
+func TestReopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "index.db")
+
+	var (
+		wallet  = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint    = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		account = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+	)
+
+	store, err := Open(ctx, path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	// Records without Data are encoded from the decoded account.
+	if err := store.Apply(ctx, &indexer.Batch{Slot: 7, TokenAccounts: []*indexer.TokenAccountRecord{{
+		Pubkey:    account,
+		ProgramID: solana.Token2022ProgramID,
+		Slot:      7,
+		Lamports:  2_039_280,
+		Account:   &token2022.TokenAccount{Mint: mint, Owner: wallet, Amount: 42, State: token2022.AccountStateFrozen},
+	}}}); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	store.Close()
+
+	store, err = Open(ctx, path)
+	if err != nil {
+		t.Fatalf("Reopen: %v", err)
+	}
+	defer store.Close()
+	if cursor, _ := store.Cursor(ctx); cursor != 7 {
+		t.Errorf("Expected cursor 7, got %d", cursor)
+	}
+	record, err := store.TokenAccount(ctx, account)
+	if err != nil || record.Account.Amount != 42 || record.Account.State != token2022.AccountStateFrozen {
+		t.Errorf("Unexpected account %+v, %v", record, err)
+	}
+}
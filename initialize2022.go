@@ -0,0 +1,621 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	solana "github.com/gagliardetto/solana-go"
+	format "github.com/gagliardetto/solana-go/text/format"
+	treeout "github.com/gagliardetto/treeout"
+)
+
+// InitializeMint2022 initializes a new mint. Extensions must be initialized before
+// the mint itself, in the same transaction that allocates it.
+type InitializeMint2022 struct {
+	// Number of base 10 digits to the right of the decimal place.
+	Decimals uint8
+
+	// The authority that can mint new tokens.
+	MintAuthority solana.PublicKey
+
+	// The authority that can freeze accounts, if any.
+	FreezeAuthority *solana.PublicKey
+
+	Mint solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Mint
+	// ··········· The mint to initialize
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewInitializeMint2022InstructionBuilder creates a new `InitializeMint2022` instruction builder.
+func NewInitializeMint2022InstructionBuilder() *InitializeMint2022 {
+	nd := &InitializeMint2022{}
+	return nd
+}
+
+func (inst *InitializeMint2022) SetDecimals(decimals uint8) *InitializeMint2022 {
+	inst.Decimals = decimals
+	return inst
+}
+
+func (inst *InitializeMint2022) SetMintAuthority(mintAuthority solana.PublicKey) *InitializeMint2022 {
+	inst.MintAuthority = mintAuthority
+	return inst
+}
+
+func (inst *InitializeMint2022) SetFreezeAuthority(freezeAuthority solana.PublicKey) *InitializeMint2022 {
+	inst.FreezeAuthority = &freezeAuthority
+	return inst
+}
+
+func (inst *InitializeMint2022) SetMint(mint solana.PublicKey) *InitializeMint2022 {
+	inst.Mint = mint
+	return inst
+}
+
+func (inst InitializeMint2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Mint,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+	}
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst InitializeMint2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *InitializeMint2022) Validate() error {
+	if inst.MintAuthority.IsZero() {
+		return errNotSet("MintAuthority")
+	}
+	if inst.Mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+// ValidateStrict validates the instruction and checks its values against
+// each other; see the package function ValidateStrict.
+func (inst *InitializeMint2022) ValidateStrict() error {
+	return ValidateStrict(inst)
+}
+
+func (inst *InitializeMint2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("InitializeMint2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=3]").ParentFunc(func(paramsBranch treeout.Branches) {
+						paramsBranch.Child(format.Param("       Decimals", inst.Decimals))
+						paramsBranch.Child(format.Param("  MintAuthority", inst.MintAuthority))
+						paramsBranch.Child(format.Param("FreezeAuthority", inst.FreezeAuthority))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("mint", inst.AccountMetaSlice.Get(0)))
+					})
+				})
+		})
+}
+
+func (inst InitializeMint2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	if err := encoder.WriteUint8(inst.Decimals); err != nil {
+		return err
+	}
+	if err := encoder.WriteBytes(inst.MintAuthority[:], false); err != nil {
+		return err
+	}
+	return WriteCOptionPubkey(encoder, COptionInstruction, inst.FreezeAuthority)
+}
+
+func (inst *InitializeMint2022) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	if inst.Decimals, err = decoder.ReadUint8(); err != nil {
+		return err
+	}
+	if inst.MintAuthority, err = readPubkey(decoder); err != nil {
+		return err
+	}
+	if inst.FreezeAuthority, err = ReadCOptionPubkey(decoder, COptionInstruction); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst InitializeMint2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *InitializeMint2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("InitializeMint2022", accounts, 1); err != nil {
+		return err
+	}
+	inst.Mint = accounts[0].PublicKey
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst InitializeMint2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *InitializeMint2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewInitializeMint2022Instruction creates a new `InitializeMint2022` instruction.
+func NewInitializeMint2022Instruction(
+	decimals uint8,
+	mintAuthority solana.PublicKey,
+	freezeAuthority *solana.PublicKey,
+	mint solana.PublicKey,
+) *InitializeMint2022 {
+	inst := NewInitializeMint2022InstructionBuilder().
+		SetDecimals(decimals).
+		SetMintAuthority(mintAuthority).
+		SetMint(mint)
+	inst.FreezeAuthority = freezeAuthority
+	return inst
+}
+
+// InitializeAccount2022 initializes a new token account. Account extensions must be
+// initialized before the account itself.
+type InitializeAccount2022 struct {
+	// The owner of the new account.
+	Owner solana.PublicKey
+
+	Account solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Mint    solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Account
+	// ··········· The account to initialize
+	//
+	// [1] = [] Mint
+	// ··········· The token mint
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewInitializeAccount2022InstructionBuilder creates a new `InitializeAccount2022` instruction builder.
+func NewInitializeAccount2022InstructionBuilder() *InitializeAccount2022 {
+	nd := &InitializeAccount2022{}
+	return nd
+}
+
+func (inst *InitializeAccount2022) SetOwner(owner solana.PublicKey) *InitializeAccount2022 {
+	inst.Owner = owner
+	return inst
+}
+
+func (inst *InitializeAccount2022) SetAccount(account solana.PublicKey) *InitializeAccount2022 {
+	inst.Account = account
+	return inst
+}
+
+func (inst *InitializeAccount2022) SetMint(mint solana.PublicKey) *InitializeAccount2022 {
+	inst.Mint = mint
+	return inst
+}
+
+func (inst InitializeAccount2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Account,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+		{
+			PublicKey:  inst.Mint,
+			IsSigner:   false,
+			IsWritable: false,
+		},
+	}
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst InitializeAccount2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *InitializeAccount2022) Validate() error {
+	if inst.Owner.IsZero() {
+		return errNotSet("Owner")
+	}
+	if inst.Account.IsZero() {
+		return errNotSet("Account")
+	}
+	if inst.Mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+func (inst *InitializeAccount2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("InitializeAccount2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=1]").ParentFunc(func(paramsBranch treeout.Branches) {
+						paramsBranch.Child(format.Param("Owner", inst.Owner))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("account", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(format.Meta("   mint", inst.AccountMetaSlice.Get(1)))
+					})
+				})
+		})
+}
+
+func (inst InitializeAccount2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	return encoder.WriteBytes(inst.Owner[:], false)
+}
+
+func (inst *InitializeAccount2022) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	if inst.Owner, err = readPubkey(decoder); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst InitializeAccount2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *InitializeAccount2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("InitializeAccount2022", accounts, 2); err != nil {
+		return err
+	}
+	inst.Account = accounts[0].PublicKey
+	inst.Mint = accounts[1].PublicKey
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst InitializeAccount2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *InitializeAccount2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewInitializeAccount2022Instruction creates a new `InitializeAccount2022` instruction.
+func NewInitializeAccount2022Instruction(
+	owner solana.PublicKey,
+	account solana.PublicKey,
+	mint solana.PublicKey,
+) *InitializeAccount2022 {
+	return NewInitializeAccount2022InstructionBuilder().
+		SetOwner(owner).
+		SetAccount(account).
+		SetMint(mint)
+}
+
+// InitializeMultisig2022 initializes a multisig account with M required signers out
+// of up to MaxSigners.
+type InitializeMultisig2022 struct {
+	// The number of signers required to validate an instruction.
+	M uint8
+
+	Multisig solana.PublicKey   `bin:"-" borsh_skip:"true"`
+	Signers  []solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Multisig
+	// ··········· The multisig account to initialize
+	//
+	// [1...] = [] Signers
+	// ··········· The signer accounts of the multisig
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewInitializeMultisig2022InstructionBuilder creates a new `InitializeMultisig2022` instruction builder.
+func NewInitializeMultisig2022InstructionBuilder() *InitializeMultisig2022 {
+	nd := &InitializeMultisig2022{}
+	return nd
+}
+
+func (inst *InitializeMultisig2022) SetM(m uint8) *InitializeMultisig2022 {
+	inst.M = m
+	return inst
+}
+
+func (inst *InitializeMultisig2022) SetMultisig(multisig solana.PublicKey) *InitializeMultisig2022 {
+	inst.Multisig = multisig
+	return inst
+}
+
+func (inst *InitializeMultisig2022) SetSigners(signers ...solana.PublicKey) *InitializeMultisig2022 {
+	inst.Signers = signers
+	return inst
+}
+
+func (inst InitializeMultisig2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Multisig,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+	}
+	for _, account := range inst.Signers {
+		keys = append(keys, solana.Meta(account))
+	}
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst InitializeMultisig2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *InitializeMultisig2022) Validate() error {
+	if inst.Multisig.IsZero() {
+		return errNotSet("Multisig")
+	}
+	if len(inst.Signers) == 0 || len(inst.Signers) > MaxSigners {
+		return fmt.Errorf("multisig needs between 1 and %d signers, got %d", MaxSigners, len(inst.Signers))
+	}
+	if inst.M == 0 || int(inst.M) > len(inst.Signers) {
+		return fmt.Errorf("invalid multisig threshold %d of %d", inst.M, len(inst.Signers))
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+// ValidateStrict validates the instruction and checks its values against
+// each other; see the package function ValidateStrict.
+func (inst *InitializeMultisig2022) ValidateStrict() error {
+	return ValidateStrict(inst)
+}
+
+func (inst *InitializeMultisig2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("InitializeMultisig2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=1]").ParentFunc(func(paramsBranch treeout.Branches) {
+						paramsBranch.Child(format.Param("M", inst.M))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("multisig", inst.AccountMetaSlice.Get(0)))
+						encodeRemainingAccounts(accountsBranch, "signer", inst.AccountMetaSlice, 1)
+					})
+				})
+		})
+}
+
+func (inst InitializeMultisig2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	return encoder.WriteUint8(inst.M)
+}
+
+func (inst *InitializeMultisig2022) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	if inst.M, err = decoder.ReadUint8(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst InitializeMultisig2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *InitializeMultisig2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("InitializeMultisig2022", accounts, 1); err != nil {
+		return err
+	}
+	inst.Multisig = accounts[0].PublicKey
+	inst.Signers = pubkeysOf(accounts[1:])
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst InitializeMultisig2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *InitializeMultisig2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewInitializeMultisig2022Instruction creates a new `InitializeMultisig2022` instruction.
+func NewInitializeMultisig2022Instruction(
+	m uint8,
+	multisig solana.PublicKey,
+	signers ...solana.PublicKey,
+) *InitializeMultisig2022 {
+	return NewInitializeMultisig2022InstructionBuilder().
+		SetM(m).
+		SetMultisig(multisig).
+		SetSigners(signers...)
+}
+
+// InitializeImmutableOwner2022 initializes the ImmutableOwner extension on a token account,
+// which prevents its owner from being changed.
+type InitializeImmutableOwner2022 struct {
+	Account solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Account
+	// ··········· The account to initialize
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewInitializeImmutableOwner2022InstructionBuilder creates a new `InitializeImmutableOwner2022` instruction builder.
+func NewInitializeImmutableOwner2022InstructionBuilder() *InitializeImmutableOwner2022 {
+	nd := &InitializeImmutableOwner2022{}
+	return nd
+}
+
+func (inst *InitializeImmutableOwner2022) SetAccount(account solana.PublicKey) *InitializeImmutableOwner2022 {
+	inst.Account = account
+	return inst
+}
+
+func (inst InitializeImmutableOwner2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Account,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+	}
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst InitializeImmutableOwner2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *InitializeImmutableOwner2022) Validate() error {
+	if inst.Account.IsZero() {
+		return errNotSet("Account")
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+func (inst *InitializeImmutableOwner2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("InitializeImmutableOwner2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=0]").ParentFunc(func(paramsBranch treeout.Branches) {})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("account", inst.AccountMetaSlice.Get(0)))
+					})
+				})
+		})
+}
+
+func (inst InitializeImmutableOwner2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	return nil
+}
+
+func (inst *InitializeImmutableOwner2022) UnmarshalWithDecoder(decoder *bin.Decoder) error {
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst InitializeImmutableOwner2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *InitializeImmutableOwner2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("InitializeImmutableOwner2022", accounts, 1); err != nil {
+		return err
+	}
+	inst.Account = accounts[0].PublicKey
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst InitializeImmutableOwner2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *InitializeImmutableOwner2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewInitializeImmutableOwner2022Instruction creates a new `InitializeImmutableOwner2022` instruction.
+func NewInitializeImmutableOwner2022Instruction(
+	account solana.PublicKey,
+) *InitializeImmutableOwner2022 {
+	return NewInitializeImmutableOwner2022InstructionBuilder().
+		SetAccount(account)
+}
@@ -0,0 +1,703 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	solana "github.com/gagliardetto/solana-go"
+	format "github.com/gagliardetto/solana-go/text/format"
+	treeout "github.com/gagliardetto/treeout"
+)
+
+// InitializeMintLegacy2022 is the deprecated InitializeMint instruction,
+// which also takes the rent sysvar. Clients such as the spl-token CLI still
+// send it; new code should use InitializeMint2022.
+type InitializeMintLegacy2022 struct {
+	// Number of base 10 digits to the right of the decimal place.
+	Decimals uint8
+
+	// The authority that can mint new tokens.
+	MintAuthority solana.PublicKey
+
+	// The authority that can freeze accounts, if any.
+	FreezeAuthority *solana.PublicKey
+
+	Mint solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Mint
+	// ··········· The mint to initialize
+	//
+	// [1] = [] Rent
+	// ··········· Rent sysvar
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewInitializeMintLegacy2022InstructionBuilder creates a new `InitializeMintLegacy2022` instruction builder.
+func NewInitializeMintLegacy2022InstructionBuilder() *InitializeMintLegacy2022 {
+	nd := &InitializeMintLegacy2022{}
+	return nd
+}
+
+func (inst *InitializeMintLegacy2022) SetDecimals(decimals uint8) *InitializeMintLegacy2022 {
+	inst.Decimals = decimals
+	return inst
+}
+
+func (inst *InitializeMintLegacy2022) SetMintAuthority(mintAuthority solana.PublicKey) *InitializeMintLegacy2022 {
+	inst.MintAuthority = mintAuthority
+	return inst
+}
+
+func (inst *InitializeMintLegacy2022) SetFreezeAuthority(freezeAuthority solana.PublicKey) *InitializeMintLegacy2022 {
+	inst.FreezeAuthority = &freezeAuthority
+	return inst
+}
+
+func (inst *InitializeMintLegacy2022) SetMint(mint solana.PublicKey) *InitializeMintLegacy2022 {
+	inst.Mint = mint
+	return inst
+}
+
+func (inst InitializeMintLegacy2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Mint,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+		{
+			PublicKey:  solana.SysVarRentPubkey,
+			IsSigner:   false,
+			IsWritable: false,
+		},
+	}
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst InitializeMintLegacy2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *InitializeMintLegacy2022) Validate() error {
+	if inst.MintAuthority.IsZero() {
+		return errNotSet("MintAuthority")
+	}
+	if inst.Mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+// ValidateStrict validates the instruction and checks its values against
+// each other; see the package function ValidateStrict.
+func (inst *InitializeMintLegacy2022) ValidateStrict() error {
+	return ValidateStrict(inst)
+}
+
+func (inst *InitializeMintLegacy2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("InitializeMintLegacy2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=3]").ParentFunc(func(paramsBranch treeout.Branches) {
+						paramsBranch.Child(format.Param("       Decimals", inst.Decimals))
+						paramsBranch.Child(format.Param("  MintAuthority", inst.MintAuthority))
+						paramsBranch.Child(format.Param("FreezeAuthority", inst.FreezeAuthority))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("mint", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(format.Meta("rent", inst.AccountMetaSlice.Get(1)))
+					})
+				})
+		})
+}
+
+func (inst InitializeMintLegacy2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	if err := encoder.WriteUint8(inst.Decimals); err != nil {
+		return err
+	}
+	if err := encoder.WriteBytes(inst.MintAuthority[:], false); err != nil {
+		return err
+	}
+	return WriteCOptionPubkey(encoder, COptionInstruction, inst.FreezeAuthority)
+}
+
+func (inst *InitializeMintLegacy2022) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	if inst.Decimals, err = decoder.ReadUint8(); err != nil {
+		return err
+	}
+	if inst.MintAuthority, err = readPubkey(decoder); err != nil {
+		return err
+	}
+	if inst.FreezeAuthority, err = ReadCOptionPubkey(decoder, COptionInstruction); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst InitializeMintLegacy2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *InitializeMintLegacy2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("InitializeMintLegacy2022", accounts, 2); err != nil {
+		return err
+	}
+	inst.Mint = accounts[0].PublicKey
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst InitializeMintLegacy2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *InitializeMintLegacy2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewInitializeMintLegacy2022Instruction creates a new `InitializeMintLegacy2022` instruction.
+func NewInitializeMintLegacy2022Instruction(
+	decimals uint8,
+	mintAuthority solana.PublicKey,
+	freezeAuthority *solana.PublicKey,
+	mint solana.PublicKey,
+) *InitializeMintLegacy2022 {
+	inst := NewInitializeMintLegacy2022InstructionBuilder().
+		SetDecimals(decimals).
+		SetMintAuthority(mintAuthority).
+		SetMint(mint)
+	inst.FreezeAuthority = freezeAuthority
+	return inst
+}
+
+// InitializeAccountLegacy2022 is the deprecated InitializeAccount
+// instruction, which takes the owner as an account and also takes the rent
+// sysvar. New code should use InitializeAccount2022.
+type InitializeAccountLegacy2022 struct {
+	Account solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Mint    solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Owner   solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Account
+	// ··········· The account to initialize
+	//
+	// [1] = [] Mint
+	// ··········· The token mint
+	//
+	// [2] = [] Owner
+	// ··········· The owner of the new account
+	//
+	// [3] = [] Rent
+	// ··········· Rent sysvar
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewInitializeAccountLegacy2022InstructionBuilder creates a new `InitializeAccountLegacy2022` instruction builder.
+func NewInitializeAccountLegacy2022InstructionBuilder() *InitializeAccountLegacy2022 {
+	nd := &InitializeAccountLegacy2022{}
+	return nd
+}
+
+func (inst *InitializeAccountLegacy2022) SetAccount(account solana.PublicKey) *InitializeAccountLegacy2022 {
+	inst.Account = account
+	return inst
+}
+
+func (inst *InitializeAccountLegacy2022) SetMint(mint solana.PublicKey) *InitializeAccountLegacy2022 {
+	inst.Mint = mint
+	return inst
+}
+
+func (inst *InitializeAccountLegacy2022) SetOwner(owner solana.PublicKey) *InitializeAccountLegacy2022 {
+	inst.Owner = owner
+	return inst
+}
+
+func (inst InitializeAccountLegacy2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Account,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+		{
+			PublicKey:  inst.Mint,
+			IsSigner:   false,
+			IsWritable: false,
+		},
+		{
+			PublicKey:  inst.Owner,
+			IsSigner:   false,
+			IsWritable: false,
+		},
+		{
+			PublicKey:  solana.SysVarRentPubkey,
+			IsSigner:   false,
+			IsWritable: false,
+		},
+	}
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst InitializeAccountLegacy2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *InitializeAccountLegacy2022) Validate() error {
+	if inst.Account.IsZero() {
+		return errNotSet("Account")
+	}
+	if inst.Mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	if inst.Owner.IsZero() {
+		return errNotSet("Owner")
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+func (inst *InitializeAccountLegacy2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("InitializeAccountLegacy2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=0]").ParentFunc(func(paramsBranch treeout.Branches) {})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("account", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(format.Meta("   mint", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(format.Meta("  owner", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(format.Meta("   rent", inst.AccountMetaSlice.Get(3)))
+					})
+				})
+		})
+}
+
+func (inst InitializeAccountLegacy2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	return nil
+}
+
+func (inst *InitializeAccountLegacy2022) UnmarshalWithDecoder(decoder *bin.Decoder) error {
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst InitializeAccountLegacy2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *InitializeAccountLegacy2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("InitializeAccountLegacy2022", accounts, 4); err != nil {
+		return err
+	}
+	inst.Account = accounts[0].PublicKey
+	inst.Mint = accounts[1].PublicKey
+	inst.Owner = accounts[2].PublicKey
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst InitializeAccountLegacy2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *InitializeAccountLegacy2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewInitializeAccountLegacy2022Instruction creates a new `InitializeAccountLegacy2022` instruction.
+func NewInitializeAccountLegacy2022Instruction(
+	account solana.PublicKey,
+	mint solana.PublicKey,
+	owner solana.PublicKey,
+) *InitializeAccountLegacy2022 {
+	return NewInitializeAccountLegacy2022InstructionBuilder().
+		SetAccount(account).
+		SetMint(mint).
+		SetOwner(owner)
+}
+
+// InitializeAccount2Legacy2022 is the deprecated InitializeAccount2
+// instruction, which also takes the rent sysvar. New code should use
+// InitializeAccount2022.
+type InitializeAccount2Legacy2022 struct {
+	// The owner of the new account.
+	Owner solana.PublicKey
+
+	Account solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Mint    solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Account
+	// ··········· The account to initialize
+	//
+	// [1] = [] Mint
+	// ··········· The token mint
+	//
+	// [2] = [] Rent
+	// ··········· Rent sysvar
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewInitializeAccount2Legacy2022InstructionBuilder creates a new `InitializeAccount2Legacy2022` instruction builder.
+func NewInitializeAccount2Legacy2022InstructionBuilder() *InitializeAccount2Legacy2022 {
+	nd := &InitializeAccount2Legacy2022{}
+	return nd
+}
+
+func (inst *InitializeAccount2Legacy2022) SetOwner(owner solana.PublicKey) *InitializeAccount2Legacy2022 {
+	inst.Owner = owner
+	return inst
+}
+
+func (inst *InitializeAccount2Legacy2022) SetAccount(account solana.PublicKey) *InitializeAccount2Legacy2022 {
+	inst.Account = account
+	return inst
+}
+
+func (inst *InitializeAccount2Legacy2022) SetMint(mint solana.PublicKey) *InitializeAccount2Legacy2022 {
+	inst.Mint = mint
+	return inst
+}
+
+func (inst InitializeAccount2Legacy2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Account,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+		{
+			PublicKey:  inst.Mint,
+			IsSigner:   false,
+			IsWritable: false,
+		},
+		{
+			PublicKey:  solana.SysVarRentPubkey,
+			IsSigner:   false,
+			IsWritable: false,
+		},
+	}
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst InitializeAccount2Legacy2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *InitializeAccount2Legacy2022) Validate() error {
+	if inst.Owner.IsZero() {
+		return errNotSet("Owner")
+	}
+	if inst.Account.IsZero() {
+		return errNotSet("Account")
+	}
+	if inst.Mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+func (inst *InitializeAccount2Legacy2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("InitializeAccount2Legacy2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=1]").ParentFunc(func(paramsBranch treeout.Branches) {
+						paramsBranch.Child(format.Param("Owner", inst.Owner))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("account", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(format.Meta("   mint", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(format.Meta("   rent", inst.AccountMetaSlice.Get(2)))
+					})
+				})
+		})
+}
+
+func (inst InitializeAccount2Legacy2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	return encoder.WriteBytes(inst.Owner[:], false)
+}
+
+func (inst *InitializeAccount2Legacy2022) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	if inst.Owner, err = readPubkey(decoder); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst InitializeAccount2Legacy2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *InitializeAccount2Legacy2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("InitializeAccount2Legacy2022", accounts, 3); err != nil {
+		return err
+	}
+	inst.Account = accounts[0].PublicKey
+	inst.Mint = accounts[1].PublicKey
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst InitializeAccount2Legacy2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *InitializeAccount2Legacy2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewInitializeAccount2Legacy2022Instruction creates a new `InitializeAccount2Legacy2022` instruction.
+func NewInitializeAccount2Legacy2022Instruction(
+	owner solana.PublicKey,
+	account solana.PublicKey,
+	mint solana.PublicKey,
+) *InitializeAccount2Legacy2022 {
+	return NewInitializeAccount2Legacy2022InstructionBuilder().
+		SetOwner(owner).
+		SetAccount(account).
+		SetMint(mint)
+}
+
+// InitializeMultisigLegacy2022 is the deprecated InitializeMultisig
+// instruction, which also takes the rent sysvar. New code should use
+// InitializeMultisig2022.
+type InitializeMultisigLegacy2022 struct {
+	// The number of signers required to validate an instruction.
+	M uint8
+
+	Multisig solana.PublicKey   `bin:"-" borsh_skip:"true"`
+	Signers  []solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Multisig
+	// ··········· The multisig account to initialize
+	//
+	// [1] = [] Rent
+	// ··········· Rent sysvar
+	//
+	// [2...] = [] Signers
+	// ··········· The signer accounts of the multisig
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewInitializeMultisigLegacy2022InstructionBuilder creates a new `InitializeMultisigLegacy2022` instruction builder.
+func NewInitializeMultisigLegacy2022InstructionBuilder() *InitializeMultisigLegacy2022 {
+	nd := &InitializeMultisigLegacy2022{}
+	return nd
+}
+
+func (inst *InitializeMultisigLegacy2022) SetM(m uint8) *InitializeMultisigLegacy2022 {
+	inst.M = m
+	return inst
+}
+
+func (inst *InitializeMultisigLegacy2022) SetMultisig(multisig solana.PublicKey) *InitializeMultisigLegacy2022 {
+	inst.Multisig = multisig
+	return inst
+}
+
+func (inst *InitializeMultisigLegacy2022) SetSigners(signers ...solana.PublicKey) *InitializeMultisigLegacy2022 {
+	inst.Signers = signers
+	return inst
+}
+
+func (inst InitializeMultisigLegacy2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Multisig,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+		{
+			PublicKey:  solana.SysVarRentPubkey,
+			IsSigner:   false,
+			IsWritable: false,
+		},
+	}
+	for _, account := range inst.Signers {
+		keys = append(keys, solana.Meta(account))
+	}
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst InitializeMultisigLegacy2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *InitializeMultisigLegacy2022) Validate() error {
+	if inst.Multisig.IsZero() {
+		return errNotSet("Multisig")
+	}
+	if len(inst.Signers) == 0 || len(inst.Signers) > MaxSigners {
+		return fmt.Errorf("multisig needs between 1 and %d signers, got %d", MaxSigners, len(inst.Signers))
+	}
+	if inst.M == 0 || int(inst.M) > len(inst.Signers) {
+		return fmt.Errorf("invalid multisig threshold %d of %d", inst.M, len(inst.Signers))
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+// ValidateStrict validates the instruction and checks its values against
+// each other; see the package function ValidateStrict.
+func (inst *InitializeMultisigLegacy2022) ValidateStrict() error {
+	return ValidateStrict(inst)
+}
+
+func (inst *InitializeMultisigLegacy2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("InitializeMultisigLegacy2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=1]").ParentFunc(func(paramsBranch treeout.Branches) {
+						paramsBranch.Child(format.Param("M", inst.M))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("multisig", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(format.Meta("    rent", inst.AccountMetaSlice.Get(1)))
+						encodeRemainingAccounts(accountsBranch, "signer", inst.AccountMetaSlice, 2)
+					})
+				})
+		})
+}
+
+func (inst InitializeMultisigLegacy2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	return encoder.WriteUint8(inst.M)
+}
+
+func (inst *InitializeMultisigLegacy2022) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	if inst.M, err = decoder.ReadUint8(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst InitializeMultisigLegacy2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *InitializeMultisigLegacy2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("InitializeMultisigLegacy2022", accounts, 2); err != nil {
+		return err
+	}
+	inst.Multisig = accounts[0].PublicKey
+	inst.Signers = pubkeysOf(accounts[2:])
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst InitializeMultisigLegacy2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *InitializeMultisigLegacy2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewInitializeMultisigLegacy2022Instruction creates a new `InitializeMultisigLegacy2022` instruction.
+func NewInitializeMultisigLegacy2022Instruction(
+	m uint8,
+	multisig solana.PublicKey,
+	signers ...solana.PublicKey,
+) *InitializeMultisigLegacy2022 {
+	return NewInitializeMultisigLegacy2022InstructionBuilder().
+		SetM(m).
+		SetMultisig(multisig).
+		SetSigners(signers...)
+}
@@ -35,13 +35,47 @@ type AccountMetaGettable interface {
 // Instruction is a base type for all instructions.
 type Instruction struct {
 	bin.BaseVariant
+	programID solana.PublicKey
 }
 
-// ProgramID returns the program ID.
+// ProgramID returns the program ID: the Token-2022 program for token
+// instructions, the Associated Token Account program otherwise.
 func (inst *Instruction) ProgramID() solana.PublicKey {
+	if !inst.programID.IsZero() {
+		return inst.programID
+	}
 	return ProgramID
 }
 
+// tokenProgramTarget is implemented by builders of other programs whose
+// accounts name the token program, such as Create2022.
+type tokenProgramTarget interface {
+	withTokenProgram(tokenProgram solana.PublicKey) *Instruction
+}
+
+// WithProgramID makes inst target programID instead of Token-2022, such as
+// solana.TokenProgramID for mints of the original SPL Token program, and
+// returns inst. Any builder's output can be retargeted so one code path
+// serves both programs:
+//
+//	inst := NewTransferChecked2022Instruction(...).Build().WithProgramID(tokenProgram)
+//
+// Associated Token Account instructions keep their program and instead
+// name programID as the token program, deriving the account under it. The
+// zero key restores Token-2022. Instructions of Token-2022 extensions do
+// not exist in the original program, which rejects them.
+func (inst *Instruction) WithProgramID(programID solana.PublicKey) *Instruction {
+	if programID.IsZero() {
+		programID = solana.Token2022ProgramID
+	}
+	if target, ok := inst.Impl.(tokenProgramTarget); ok {
+		*inst = *target.withTokenProgram(programID)
+		return inst
+	}
+	inst.programID = programID
+	return inst
+}
+
 // Accounts returns the list of accounts that this instruction requires.
 func (inst *Instruction) Accounts() []*solana.AccountMeta {
 	return inst.Impl.(AccountMetaGettable).GetAccounts()
@@ -49,6 +83,9 @@ func (inst *Instruction) Accounts() []*solana.AccountMeta {
 
 // Data serializes the instruction data.
 func (inst *Instruction) Data() ([]byte, error) {
+	if appender, ok := inst.Impl.(dataAppender); ok {
+		return appender.appendData(make([]byte, 0, TransferCheckedDataSize)), nil
+	}
 	buf := new(bytes.Buffer)
 	encoder := bin.NewBorshEncoder(buf)
 	err := encoder.Encode(inst)
@@ -58,13 +95,34 @@ func (inst *Instruction) Data() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-// MarshalWithEncoder implements the bin.EncoderDecoder interface
+// Discriminator returns the discriminator that selects the instruction.
+func (inst *Instruction) Discriminator() (Discriminator, error) {
+	return discriminatorOf(inst.Impl)
+}
+
+// MarshalWithEncoder implements the bin.EncoderDecoder interface: it
+// writes the discriminator, followed by the arguments the builder encodes.
 func (inst *Instruction) MarshalWithEncoder(encoder *bin.Encoder) error {
+	discriminator, err := inst.Discriminator()
+	if err != nil {
+		return err
+	}
+	if err := discriminator.encode(encoder); err != nil {
+		return err
+	}
 	return encoder.Encode(inst.Impl)
 }
 
-// UnmarshalWithDecoder implements the bin.EncoderDecoder interface
+// UnmarshalWithDecoder implements the bin.EncoderDecoder interface: it
+// checks the discriminator of the builder, then decodes its arguments.
 func (inst *Instruction) UnmarshalWithDecoder(decoder *bin.Decoder) error {
+	discriminator, err := inst.Discriminator()
+	if err != nil {
+		return err
+	}
+	if err := discriminator.check(decoder); err != nil {
+		return err
+	}
 	return decoder.Decode(inst.Impl)
 }
 
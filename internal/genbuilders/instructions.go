@@ -0,0 +1,329 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+// File is a generated file of the token2022 package.
+type File struct {
+	Name         string
+	Instructions []Instruction
+}
+
+// Instruction describes a builder. The generated type is Name with the
+// 2022 suffix, in the Token-2022 program; its discriminator comes from the
+// registration in registry.go.
+type Instruction struct {
+	Name string
+	// Doc is the doc comment of the type, one line per line.
+	Doc string
+	// Args are the arguments, in encoding order.
+	Args []Arg
+	// Accounts are the accounts, in order. Only the last may be a
+	// Multisig authority.
+	Accounts []Account
+	// Checks are the checks Validate runs after the required arguments
+	// and accounts.
+	Checks []Check
+}
+
+// Arg is an instruction argument. Type is a key of argTypes.
+type Arg struct {
+	Name string
+	Type string
+	Doc  string
+}
+
+// Account is an account of an instruction. A Multisig account is an
+// authority that signs, or a multisig whose signers follow it.
+type Account struct {
+	Name     string
+	Doc      string
+	Writable bool
+	Signer   bool
+	Multisig bool
+}
+
+// Check is a condition on the arguments that Validate rejects with an
+// InvalidFieldError for Field, whose Reason completes the sentence
+// started by Field.
+type Check struct {
+	Cond   string
+	Field  string
+	Reason string
+}
+
+// argType is how an argument of a type is declared, checked and
+// encoded. Write is a format string of the encoding call, with the field
+// as operand; Read is the decoding expression, whose value is converted
+// with Convert when it is set.
+type argType struct {
+	GoType     string
+	SetterType string
+	Write      string
+	Read       string
+	Convert    string
+	// Required arguments are checked to be set by Validate.
+	Required bool
+	// Optional arguments are pointers that are nil when not set.
+	Optional bool
+}
+
+var argTypes = map[string]argType{
+	"u8": {
+		GoType: "uint8", SetterType: "uint8",
+		Write: "encoder.WriteUint8(%s)", Read: "decoder.ReadUint8()",
+	},
+	"u16": {
+		GoType: "uint16", SetterType: "uint16",
+		Write: "encoder.WriteUint16(%s, bin.LE)", Read: "decoder.ReadUint16(bin.LE)",
+	},
+	"u64": {
+		GoType: "uint64", SetterType: "uint64",
+		Write: "encoder.WriteUint64(%s, bin.LE)", Read: "decoder.ReadUint64(bin.LE)",
+	},
+	"i16": {
+		GoType: "int16", SetterType: "int16",
+		Write: "encoder.WriteInt16(%s, bin.LE)", Read: "decoder.ReadInt16(bin.LE)",
+	},
+	"i64": {
+		GoType: "int64", SetterType: "int64",
+		Write: "encoder.WriteInt64(%s, bin.LE)", Read: "decoder.ReadInt64(bin.LE)",
+	},
+	"f64": {
+		GoType: "float64", SetterType: "float64",
+		Write: "encoder.WriteFloat64(%s, bin.LE)", Read: "readFiniteFloat64(decoder)",
+	},
+	"pubkey": {
+		GoType: "solana.PublicKey", SetterType: "solana.PublicKey",
+		Write: "encoder.WriteBytes(%s[:], false)", Read: "readPubkey(decoder)",
+		Required: true,
+	},
+	// optionalNonZeroPubkey is the OptionalNonZeroPubkey of the extension
+	// instructions: 32 bytes, all zero for None.
+	"optionalNonZeroPubkey": {
+		GoType: "*solana.PublicKey", SetterType: "solana.PublicKey",
+		Write: "writeOptionalNonZeroPubkey(encoder, %s)", Read: "readOptionalNonZeroPubkey(decoder)",
+		Optional: true,
+	},
+	"accountState": {
+		GoType: "AccountState", SetterType: "AccountState",
+		Write: "encoder.WriteUint8(uint8(%s))", Read: "decoder.ReadUint8()",
+		Convert: "AccountState",
+	},
+}
+
+// Accounts shared by the table.
+var (
+	mintToInitialize = Account{Name: "Mint", Doc: "The mint to initialize", Writable: true}
+	tokenMint        = Account{Name: "Mint", Doc: "The token mint", Writable: true}
+	tokenAccount     = Account{Name: "Account", Doc: "The token account", Writable: true}
+	accountOwner     = Account{Name: "Owner", Doc: "The account's owner, or a multisig", Multisig: true}
+)
+
+// mintAuthority is the authority of an extension of the mint.
+func mintAuthority(name, kind string) Account {
+	return Account{Name: name, Doc: "The mint's " + kind + " authority, or a multisig", Multisig: true}
+}
+
+// pointer describes the Initialize and Update instructions of a pointer
+// extension, whose field points to an account holding what.
+func pointer(extension, field, what string) []Instruction {
+	return []Instruction{
+		{
+			Name: "Initialize" + extension,
+			Doc: "Initialize" + extension + "2022 initializes the " + extension + " extension, which points to the\n" +
+				"account holding the " + what + " of the mint.",
+			Args: []Arg{
+				{Name: "Authority", Type: "optionalNonZeroPubkey", Doc: "The authority that can update the pointer, if any."},
+				{Name: field, Type: "optionalNonZeroPubkey", Doc: "The account holding the " + what + ", if any."},
+			},
+			Accounts: []Account{mintToInitialize},
+		},
+		{
+			Name: "Update" + extension,
+			Doc:  "Update" + extension + "2022 updates the " + what + " address of the mint.",
+			Args: []Arg{
+				{Name: field, Type: "optionalNonZeroPubkey", Doc: "The new account holding the " + what + ", if any."},
+			},
+			Accounts: []Account{tokenMint, mintAuthority("Authority", lowerFirst(extension))},
+		},
+	}
+}
+
+// toggle describes the Enable and Disable instructions of an account
+// extension.
+func toggle(name, enableDoc, disableDoc string) []Instruction {
+	return []Instruction{
+		{
+			Name:     "Enable" + name,
+			Doc:      "Enable" + name + "2022 " + enableDoc,
+			Accounts: []Account{tokenAccount, accountOwner},
+		},
+		{
+			Name:     "Disable" + name,
+			Doc:      "Disable" + name + "2022 " + disableDoc,
+			Accounts: []Account{tokenAccount, accountOwner},
+		},
+	}
+}
+
+var positiveMultiplier = Check{Cond: "!(inst.Multiplier > 0)", Field: "Multiplier", Reason: "must be positive"}
+
+var initializedState = Check{
+	Cond:   "inst.State == AccountStateUninitialized",
+	Field:  "State",
+	Reason: "cannot be Uninitialized",
+}
+
+// files is the table of generated builders.
+var files = []File{
+	{
+		Name: "cpiguard2022.go",
+		Instructions: toggle("CpiGuard",
+			"enables the CPI guard, which blocks privileged operations on\nthe account when they are invoked through another program.",
+			"disables the CPI guard."),
+	},
+	{
+		Name: "defaultaccountstate2022.go",
+		Instructions: []Instruction{
+			{
+				Name: "InitializeDefaultAccountState",
+				Doc: "InitializeDefaultAccountState2022 initializes the DefaultAccountState extension, which sets the\n" +
+					"state of new token accounts of the mint.",
+				Args:     []Arg{{Name: "State", Type: "accountState", Doc: "The state of new accounts."}},
+				Accounts: []Account{mintToInitialize},
+				Checks:   []Check{initializedState},
+			},
+			{
+				Name:     "UpdateDefaultAccountState",
+				Doc:      "UpdateDefaultAccountState2022 updates the state of new token accounts of the mint.",
+				Args:     []Arg{{Name: "State", Type: "accountState", Doc: "The state of new accounts."}},
+				Accounts: []Account{tokenMint, mintAuthority("FreezeAuthority", "freeze")},
+				Checks:   []Check{initializedState},
+			},
+		},
+	},
+	{
+		Name: "interestbearing2022.go",
+		Instructions: []Instruction{
+			{
+				Name: "InitializeInterestBearingMint",
+				Doc: "InitializeInterestBearingMint2022 initializes the InterestBearingConfig extension, which makes\n" +
+					"the UI amount of the mint accrue interest continuously.",
+				Args: []Arg{
+					{Name: "RateAuthority", Type: "optionalNonZeroPubkey", Doc: "The authority that can update the rate, if any."},
+					{Name: "Rate", Type: "i16", Doc: "The interest rate in basis points."},
+				},
+				Accounts: []Account{mintToInitialize},
+			},
+			{
+				Name:     "UpdateInterestRate",
+				Doc:      "UpdateInterestRate2022 updates the interest rate of the mint.",
+				Args:     []Arg{{Name: "Rate", Type: "i16", Doc: "The new interest rate in basis points."}},
+				Accounts: []Account{tokenMint, mintAuthority("RateAuthority", "rate")},
+			},
+		},
+	},
+	{
+		Name: "memotransfer2022.go",
+		Instructions: toggle("RequiredMemoTransfers",
+			"requires incoming transfers to the account to be preceded by\na memo instruction.",
+			"stops requiring memos on incoming transfers."),
+	},
+	{
+		Name: "pausable2022.go",
+		Instructions: []Instruction{
+			{
+				Name: "InitializePausableConfig",
+				Doc: "InitializePausableConfig2022 initializes the Pausable extension, whose authority can pause\n" +
+					"all transfers, mints and burns of the mint.",
+				Args:     []Arg{{Name: "Authority", Type: "pubkey", Doc: "The authority that can pause and resume the mint."}},
+				Accounts: []Account{mintToInitialize},
+			},
+			{
+				Name:     "Pause",
+				Doc:      "Pause2022 pauses transfers, mints and burns of the mint.",
+				Accounts: []Account{tokenMint, mintAuthority("Authority", "pause")},
+			},
+			{
+				Name:     "Resume",
+				Doc:      "Resume2022 resumes a paused mint.",
+				Accounts: []Account{tokenMint, mintAuthority("Authority", "pause")},
+			},
+		},
+	},
+	{
+		Name: "pointers2022.go",
+		Instructions: concat(
+			pointer("MetadataPointer", "MetadataAddress", "token metadata"),
+			pointer("GroupPointer", "GroupAddress", "group configuration"),
+			pointer("GroupMemberPointer", "MemberAddress", "group membership"),
+		),
+	},
+	{
+		Name: "scaleduiamount2022.go",
+		Instructions: []Instruction{
+			{
+				Name: "InitializeScaledUiAmount",
+				Doc: "InitializeScaledUiAmount2022 initializes the ScaledUiAmount extension, which multiplies\n" +
+					"the UI amount of every balance by a configurable factor.",
+				Args: []Arg{
+					{Name: "Authority", Type: "optionalNonZeroPubkey", Doc: "The authority that can update the multiplier, if any."},
+					{Name: "Multiplier", Type: "f64", Doc: "The initial multiplier."},
+				},
+				Accounts: []Account{mintToInitialize},
+				Checks:   []Check{positiveMultiplier},
+			},
+			{
+				Name: "UpdateMultiplier",
+				Doc:  "UpdateMultiplier2022 schedules a new UI amount multiplier.",
+				Args: []Arg{
+					{Name: "Multiplier", Type: "f64", Doc: "The new multiplier."},
+					{Name: "EffectiveTimestamp", Type: "i64", Doc: "The Unix timestamp at which the new multiplier takes effect."},
+				},
+				Accounts: []Account{tokenMint, mintAuthority("Authority", "scaled UI amount")},
+				Checks:   []Check{positiveMultiplier},
+			},
+		},
+	},
+	{
+		Name: "transferhook2022.go",
+		Instructions: []Instruction{
+			{
+				Name: "InitializeTransferHook",
+				Doc: "InitializeTransferHook2022 initializes the TransferHook extension, which makes every\n" +
+					"transfer of the mint invoke the hook program.",
+				Args: []Arg{
+					{Name: "Authority", Type: "optionalNonZeroPubkey", Doc: "The authority that can change the hook program, if any."},
+					{Name: "HookProgramID", Type: "optionalNonZeroPubkey", Doc: "The transfer hook program, if any."},
+				},
+				Accounts: []Account{mintToInitialize},
+			},
+			{
+				Name:     "UpdateTransferHook",
+				Doc:      "UpdateTransferHook2022 changes the transfer hook program of the mint.",
+				Args:     []Arg{{Name: "HookProgramID", Type: "optionalNonZeroPubkey", Doc: "The new transfer hook program, or nil to remove it."}},
+				Accounts: []Account{tokenMint, mintAuthority("Authority", "transfer hook")},
+			},
+		},
+	},
+}
+
+func concat(lists ...[]Instruction) []Instruction {
+	var out []Instruction
+	for _, list := range lists {
+		out = append(out, list...)
+	}
+	return out
+}
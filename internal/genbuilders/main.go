@@ -0,0 +1,230 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command genbuilders writes the repetitive instruction builders of the
+// token2022 package from the table in instructions.go: the struct with its
+// account list, the setters, Build, Validate, EncodeToTree, the binary and
+// JSON encodings, SetAccounts and the constructor. Adding an instruction
+// of that shape is a change to the table followed by
+//
+//	go generate github.com/dwmfan/token2022
+//
+// Builders with accounts or encodings the table cannot describe, such as
+// transfers with their transfer hook accounts, stay hand-written.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "directory of the token2022 package")
+	flag.Parse()
+	for _, file := range files {
+		src, err := generate(file)
+		if err != nil {
+			log.Fatalf("genbuilders: %s: %v", file.Name, err)
+		}
+		if err := os.WriteFile(filepath.Join(*dir, file.Name), src, 0o644); err != nil {
+			log.Fatalf("genbuilders: %v", err)
+		}
+	}
+}
+
+// generate returns the formatted source of file.
+func generate(file File) ([]byte, error) {
+	var buf bytes.Buffer
+	view, err := newFileView(file)
+	if err != nil {
+		return nil, err
+	}
+	if err := fileTemplate.Execute(&buf, view); err != nil {
+		return nil, err
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("error while formatting: %w\n%s", err, buf.Bytes())
+	}
+	return src, nil
+}
+
+// fileView is what the template renders: an instruction table entry
+// with the names, paddings and encodings worked out.
+type fileView struct {
+	Instructions []instructionView
+}
+
+type instructionView struct {
+	Instruction
+	Type string
+	Args []argView
+	// Accounts are the accounts before the multisig Authority, if any;
+	// AllAccounts includes it.
+	Accounts    []accountView
+	Authority   *accountView
+	AllAccounts []accountView
+	// Required lists the arguments and accounts Validate checks, in
+	// order.
+	Required    []string
+	Params      []labelView
+	Metas       []labelView
+	Constructor []paramView
+	Chain       []string
+	Assign      []string
+}
+
+type argView struct {
+	Arg
+	argType
+	Param string
+	Last  bool
+}
+
+type accountView struct {
+	Account
+	Index int
+	Param string
+	Flags string
+}
+
+type labelView struct {
+	Label string
+	Expr  string
+}
+
+type paramView struct {
+	Name string
+	Type string
+}
+
+func newFileView(file File) (fileView, error) {
+	view := fileView{}
+	for _, inst := range file.Instructions {
+		instView, err := newInstructionView(inst)
+		if err != nil {
+			return fileView{}, err
+		}
+		view.Instructions = append(view.Instructions, instView)
+	}
+	return view, nil
+}
+
+func newInstructionView(inst Instruction) (instructionView, error) {
+	view := instructionView{
+		Instruction: inst,
+		Type:        inst.Name + "2022",
+	}
+
+	var paramLabels, metaLabels []string
+	for i, arg := range inst.Args {
+		t, ok := argTypes[arg.Type]
+		if !ok {
+			return instructionView{}, fmt.Errorf("%s: unknown argument type %q", view.Type, arg.Type)
+		}
+		a := argView{Arg: arg, argType: t, Param: lowerFirst(arg.Name), Last: i == len(inst.Args)-1}
+		view.Args = append(view.Args, a)
+		paramLabels = append(paramLabels, arg.Name)
+		if t.Required {
+			view.Required = append(view.Required, arg.Name)
+		}
+		view.Constructor = append(view.Constructor, paramView{a.Param, t.GoType})
+		if t.Optional {
+			view.Assign = append(view.Assign, fmt.Sprintf("inst.%s = %s", arg.Name, a.Param))
+		} else {
+			view.Chain = append(view.Chain, fmt.Sprintf("Set%s(%s)", arg.Name, a.Param))
+		}
+	}
+	for i, account := range inst.Accounts {
+		a := accountView{Account: account, Index: i, Param: lowerFirst(account.Name), Flags: flags(account)}
+		view.AllAccounts = append(view.AllAccounts, a)
+		view.Required = append(view.Required, account.Name)
+		metaLabels = append(metaLabels, lowerFirst(account.Name))
+		view.Constructor = append(view.Constructor, paramView{a.Param, "solana.PublicKey"})
+		if account.Multisig {
+			if i != len(inst.Accounts)-1 {
+				return instructionView{}, fmt.Errorf("%s: multisig authority %s is not the last account", view.Type, account.Name)
+			}
+			view.Authority = &a
+			view.Chain = append(view.Chain, fmt.Sprintf("Set%s(%s, multisigSigners...)", account.Name, a.Param))
+			continue
+		}
+		view.Accounts = append(view.Accounts, a)
+		view.Chain = append(view.Chain, fmt.Sprintf("Set%s(%s)", account.Name, a.Param))
+	}
+	if view.Authority != nil {
+		view.Constructor = append(view.Constructor, paramView{"multisigSigners", "...solana.PublicKey"})
+	}
+
+	paramLabels = padLeft(paramLabels)
+	for i, arg := range inst.Args {
+		view.Params = append(view.Params, labelView{paramLabels[i], "inst." + arg.Name})
+	}
+	metaLabels = padLeft(metaLabels)
+	for i := range inst.Accounts {
+		view.Metas = append(view.Metas, labelView{metaLabels[i], fmt.Sprintf("inst.AccountMetaSlice.Get(%d)", i)})
+	}
+	return view, nil
+}
+
+// flags renders the access of an account as in the account list
+// comments: "[WRITE]", "[SIGNER]", "[WRITE, SIGNER]" or "[]".
+func flags(account Account) string {
+	var out []string
+	if account.Writable {
+		out = append(out, "WRITE")
+	}
+	if account.Signer || account.Multisig {
+		out = append(out, "SIGNER")
+	}
+	return "[" + strings.Join(out, ", ") + "]"
+}
+
+// padLeft right-aligns labels to the longest one.
+func padLeft(labels []string) []string {
+	width := 0
+	for _, label := range labels {
+		width = max(width, len(label))
+	}
+	out := make([]string, len(labels))
+	for i, label := range labels {
+		out[i] = strings.Repeat(" ", width-len(label)) + label
+	}
+	return out
+}
+
+func lowerFirst(name string) string {
+	runes := []rune(name)
+	runes[0] = unicode.ToLower(runes[0])
+	return string(runes)
+}
+
+var fileTemplate = template.Must(template.New("file").Funcs(template.FuncMap{
+	"comment": func(indent, text string) string {
+		return indent + "// " + strings.ReplaceAll(text, "\n", "\n"+indent+"// ")
+	},
+	"last": func(i int, n int) bool { return i == n-1 },
+}).Parse(fileSource))
+
+func init() {
+	template.Must(fileTemplate.New("instruction").Parse(instructionSource))
+}
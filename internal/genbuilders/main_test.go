@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGeneratedFilesUpToDate(t *testing.T) {
+
+	for _, file := range files {
+		want, err := generate(file)
+		if err != nil {
+			t.Fatalf("%s: generate failed: %v", file.Name, err)
+		}
+		got, err := os.ReadFile(filepath.Join("..", "..", file.Name))
+		if err != nil {
+			t.Fatalf("%s: %v", file.Name, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s is out of date, run go generate", file.Name)
+		}
+	}
+}
+
+func TestGenerateRejectsInvalidTables(t *testing.T) {
+
+	authority := Account{Name: "Authority", Multisig: true}
+	mint := Account{Name: "Mint", Writable: true}
+
+	_, err := generate(File{Name: "x.go", Instructions: []Instruction{{Name: "X", Accounts: []Account{authority, mint}}}})
+	if err == nil || !strings.Contains(err.Error(), "not the last account") {
+		t.Errorf("Expected a misplaced authority to be rejected, got %v", err)
+	}
+	_, err = generate(File{Name: "x.go", Instructions: []Instruction{{Name: "X", Args: []Arg{{Name: "A", Type: "u128"}}}}})
+	if err == nil || !strings.Contains(err.Error(), "unknown argument type") {
+		t.Errorf("Expected an unknown type to be rejected, got %v", err)
+	}
+}
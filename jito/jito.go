@@ -0,0 +1,184 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jito submits Token-2022 transactions to a Jito block engine as
+// bundles. A bundle lands atomically and in order, or not at all, which is
+// what multi-transaction flows such as harvest + withdraw + close need.
+package jito
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+
+	"github.com/dwmfan/token2022"
+	solana "github.com/gagliardetto/solana-go"
+	system "github.com/gagliardetto/solana-go/programs/system"
+	jsonrpc "github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+// DefaultEndpoint is the mainnet block engine bundle endpoint.
+const DefaultEndpoint = "https://mainnet.block-engine.jito.wtf/api/v1/bundles"
+
+// MaxBundleSize is the maximum number of transactions in one bundle.
+const MaxBundleSize = 5
+
+// MinTipLamports is the smallest tip the block engine accepts.
+const MinTipLamports = 1000
+
+// TipAccounts are the mainnet tip payment accounts. Spreading tips across
+// them reduces write-lock contention.
+var TipAccounts = []solana.PublicKey{
+	solana.MustPublicKeyFromBase58("96gYZGLnJYVFmbjzopPSU6QiEV5fGqZNyN9nmNhvrZU5"),
+	solana.MustPublicKeyFromBase58("HFqU5x63VTqvQss8hp11i4wVV8bD44PvwucfZ2bU7gRe"),
+	solana.MustPublicKeyFromBase58("Cw8CFyM9FkoMi7K7Crf6HNQqf4uEMzpKw6QNghXLvLkY"),
+	solana.MustPublicKeyFromBase58("ADaUMid9yfUytqMBgopwjb2DTLSokTSzL1zt6iGPaS49"),
+	solana.MustPublicKeyFromBase58("DfXygSm4jCyNCybVYYK6DwvWqjKee8pbDmJGcLWNDXjh"),
+	solana.MustPublicKeyFromBase58("ADuUkR4vqLUMWXxW9gh6D6L8pMSawimctcNZ5pGwDcEt"),
+	solana.MustPublicKeyFromBase58("DttWaMuVvTiduZRnguLF7jNxTgiMBZ1hyAumKUiL2KRL"),
+	solana.MustPublicKeyFromBase58("3AVi9Tg9Uo68tJfuvoKvqKNWKkC5wPdSSdeBnizKZ6jT"),
+}
+
+// NewTipInstruction returns a transfer of lamports from payer to a random
+// tip account. Put it in the last transaction of the bundle so the tip is
+// only paid if every transaction lands.
+func NewTipInstruction(payer solana.PublicKey, lamports uint64) solana.Instruction {
+	tipAccount := TipAccounts[rand.Intn(len(TipAccounts))]
+	return system.NewTransferInstruction(lamports, payer, tipAccount).Build()
+}
+
+// BuildBundle builds and signs one transaction per builder, in order, and
+// appends a tip of tipLamports from tipPayer to the last one, so the tip
+// is only paid if every transaction of the bundle lands. tipPayer must
+// sign the last transaction, as its fee payer or a signer of its builder.
+// The builders are not changed.
+func BuildBundle(ctx context.Context, tipPayer solana.PublicKey, tipLamports uint64, builders ...*token2022.TxBuilder) ([]*solana.Transaction, error) {
+	if len(builders) == 0 {
+		return nil, errors.New("empty bundle")
+	}
+	if len(builders) > MaxBundleSize {
+		return nil, fmt.Errorf("bundle has %d transactions, max is %d", len(builders), MaxBundleSize)
+	}
+	if tipLamports < MinTipLamports {
+		return nil, fmt.Errorf("tip of %d lamports is below the minimum of %d", tipLamports, MinTipLamports)
+	}
+	txs := make([]*solana.Transaction, len(builders))
+	for i, builder := range builders {
+		if i == len(builders)-1 {
+			builder = builder.Clone().AddInstruction(NewTipInstruction(tipPayer, tipLamports))
+		}
+		tx, err := builder.BuildAndSign(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error while building transaction %d: %w", i, err)
+		}
+		txs[i] = tx
+	}
+	return txs, nil
+}
+
+// BundleStatus is the status of a bundle that reached the chain.
+type BundleStatus struct {
+	BundleID           string   `json:"bundle_id"`
+	Transactions       []string `json:"transactions"`
+	Slot               uint64   `json:"slot"`
+	ConfirmationStatus string   `json:"confirmation_status"`
+	Err                any      `json:"err"`
+}
+
+// InflightBundleStatus is the status of a recently submitted bundle:
+// "Invalid", "Pending", "Failed" or "Landed".
+type InflightBundleStatus struct {
+	BundleID   string  `json:"bundle_id"`
+	Status     string  `json:"status"`
+	LandedSlot *uint64 `json:"landed_slot"`
+}
+
+// Client talks to a block engine bundle endpoint.
+type Client struct {
+	rpc jsonrpc.RPCClient
+}
+
+// New creates a client for the given bundle endpoint, for example DefaultEndpoint.
+func New(endpoint string) *Client {
+	return NewWithRPC(jsonrpc.NewClient(endpoint))
+}
+
+// NewWithRPC creates a client on top of an existing JSON-RPC client,
+// for example one that sets an authentication header.
+func NewWithRPC(rpc jsonrpc.RPCClient) *Client {
+	return &Client{rpc: rpc}
+}
+
+// SendBundle submits fully signed transactions as one bundle and returns the bundle ID.
+func (c *Client) SendBundle(ctx context.Context, txs ...*solana.Transaction) (string, error) {
+	if len(txs) == 0 {
+		return "", errors.New("empty bundle")
+	}
+	if len(txs) > MaxBundleSize {
+		return "", fmt.Errorf("bundle has %d transactions, max is %d", len(txs), MaxBundleSize)
+	}
+	encoded := make([]string, len(txs))
+	for i, tx := range txs {
+		if !token2022.IsFullySigned(tx) {
+			return "", fmt.Errorf("transaction %d is missing signatures for %v", i, token2022.MissingSigners(tx))
+		}
+		data, err := token2022.EncodeTransaction(tx)
+		if err != nil {
+			return "", fmt.Errorf("error while encoding transaction %d: %w", i, err)
+		}
+		encoded[i] = data
+	}
+	var bundleID string
+	err := c.rpc.CallForInto(ctx, &bundleID, "sendBundle", []interface{}{
+		encoded,
+		map[string]interface{}{"encoding": "base64"},
+	})
+	if err != nil {
+		return "", fmt.Errorf("error while sendBundle: %w", err)
+	}
+	return bundleID, nil
+}
+
+// GetTipAccounts returns the tip accounts advertised by the block engine.
+func (c *Client) GetTipAccounts(ctx context.Context) ([]solana.PublicKey, error) {
+	var out []solana.PublicKey
+	if err := c.rpc.CallForInto(ctx, &out, "getTipAccounts", nil); err != nil {
+		return nil, fmt.Errorf("error while getTipAccounts: %w", err)
+	}
+	return out, nil
+}
+
+// GetBundleStatuses returns the on-chain status of landed bundles.
+// Bundles that are unknown to the block engine are returned as nil.
+func (c *Client) GetBundleStatuses(ctx context.Context, bundleIDs ...string) ([]*BundleStatus, error) {
+	var out struct {
+		Value []*BundleStatus `json:"value"`
+	}
+	if err := c.rpc.CallForInto(ctx, &out, "getBundleStatuses", []interface{}{bundleIDs}); err != nil {
+		return nil, fmt.Errorf("error while getBundleStatuses: %w", err)
+	}
+	return out.Value, nil
+}
+
+// GetInflightBundleStatuses returns the status of bundles submitted in the last five minutes.
+func (c *Client) GetInflightBundleStatuses(ctx context.Context, bundleIDs ...string) ([]*InflightBundleStatus, error) {
+	var out struct {
+		Value []*InflightBundleStatus `json:"value"`
+	}
+	if err := c.rpc.CallForInto(ctx, &out, "getInflightBundleStatuses", []interface{}{bundleIDs}); err != nil {
+		return nil, fmt.Errorf("error while getInflightBundleStatuses: %w", err)
+	}
+	return out.Value, nil
+}
@@ -0,0 +1,147 @@
+package jito
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dwmfan/token2022"
+	"github.com/dwmfan/token2022/token2022test"
+	solana "github.com/gagliardetto/solana-go"
+	system "github.com/gagliardetto/solana-go/programs/system"
+)
+
+func TestSendBundle(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     any               `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("Error decoding request: %v", err)
+		}
+		if req.Method != "sendBundle" {
+			t.Errorf("Expected sendBundle, got %s", req.Method)
+		}
+		if err := json.Unmarshal(req.Params[0], &received); err != nil {
+			t.Errorf("Error decoding params: %v", err)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": "bundle-1"})
+	}))
+	defer server.Close()
+
+	payer := solana.NewWallet().PrivateKey
+	blockhash := solana.Hash(payer.PublicKey())
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{NewTipInstruction(payer.PublicKey(), MinTipLamports)},
+		blockhash,
+		solana.TransactionPayer(payer.PublicKey()),
+	)
+	if err != nil {
+		t.Fatalf("Error creating transaction: %v", err)
+	}
+
+	client := New(server.URL)
+	if _, err := client.SendBundle(context.Background(), tx); err == nil {
+		t.Errorf("Expected unsigned transaction to be rejected")
+	}
+
+	if _, err := tx.Sign(func(key solana.PublicKey) *solana.PrivateKey { return &payer }); err != nil {
+		t.Fatalf("Error signing: %v", err)
+	}
+	id, err := client.SendBundle(context.Background(), tx)
+	if err != nil {
+		t.Fatalf("Error sending bundle: %v", err)
+	}
+	if id != "bundle-1" {
+		t.Errorf("Expected bundle-1, got %s", id)
+	}
+	if len(received) != 1 {
+		t.Errorf("Expected 1 transaction in bundle, got %d", len(received))
+	}
+}
+
+func TestNewTipInstruction(t *testing.T) {
+	payer := solana.NewWallet().PublicKey()
+	inst := NewTipInstruction(payer, 5000)
+	if inst.ProgramID() != solana.SystemProgramID {
+		t.Fatalf("Expected system program, got %s", inst.ProgramID())
+	}
+	tipAccount := inst.Accounts()[1].PublicKey
+	if !tipAccount.IsAnyOf(TipAccounts...) {
+		t.Errorf("Expected a tip account, got %s", tipAccount)
+	}
+	data, err := inst.Data()
+	if err != nil {
+		t.Fatalf("Error encoding instruction: %v", err)
+	}
+	if len(data) != 12 || data[0] != byte(system.Instruction_Transfer) {
+		t.Errorf("Unexpected transfer data %x", data)
+	}
+}
+
+func TestBuildBundle(t *testing.T) {
+	var (
+		payer  = solana.NewWallet().PrivateKey
+		ctx    = context.Background()
+		server = token2022test.NewServer(t)
+	)
+	defer server.Close()
+
+	newBuilder := func(memo string) *token2022.TxBuilder {
+		return token2022.NewTxBuilder(server.Client()).
+			SetFeePayer(payer.PublicKey()).
+			AddInstruction(token2022.NewMemoInstruction(memo, payer.PublicKey())).
+			AddSigner(token2022.NewPrivateKeySigner(payer))
+	}
+	harvest, withdraw := newBuilder("harvest"), newBuilder("withdraw")
+
+	txs, err := BuildBundle(ctx, payer.PublicKey(), MinTipLamports, harvest, withdraw)
+	if err != nil {
+		t.Fatalf("Error building bundle: %v", err)
+	}
+	if len(txs) != 2 {
+		t.Fatalf("Expected 2 transactions, got %d", len(txs))
+	}
+	if n := len(txs[0].Message.Instructions); n != 1 {
+		t.Errorf("Expected no tip in the first transaction, got %d instructions", n)
+	}
+	last := txs[1].Message.Instructions
+	if len(last) != 2 {
+		t.Fatalf("Expected the tip to follow the last instruction, got %d instructions", len(last))
+	}
+	tip := last[1]
+	if program, _ := txs[1].Message.Account(tip.ProgramIDIndex); !program.Equals(solana.SystemProgramID) {
+		t.Errorf("Expected a system transfer tip, got program %s", program)
+	}
+	if tipAccount, _ := txs[1].Message.Account(tip.Accounts[1]); !tipAccount.IsAnyOf(TipAccounts...) {
+		t.Errorf("Expected a tip account, got %s", tipAccount)
+	}
+	for i, tx := range txs {
+		if err := tx.VerifySignatures(); err != nil {
+			t.Errorf("Transaction %d: %v", i, err)
+		}
+	}
+
+	tx, err := withdraw.Build(ctx)
+	if err != nil {
+		t.Fatalf("Error building: %v", err)
+	}
+	if n := len(tx.Message.Instructions); n != 1 {
+		t.Errorf("Expected the builder to be unchanged, got %d instructions", n)
+	}
+
+	if _, err := BuildBundle(ctx, payer.PublicKey(), MinTipLamports-1, harvest); err == nil {
+		t.Errorf("Expected a tip below the minimum to be rejected")
+	}
+	if _, err := BuildBundle(ctx, payer.PublicKey(), MinTipLamports); err == nil {
+		t.Errorf("Expected an empty bundle to be rejected")
+	}
+	if _, err := BuildBundle(ctx, solana.NewWallet().PublicKey(), MinTipLamports, harvest); err == nil {
+		t.Errorf("Expected a tip payer that does not sign to be rejected")
+	}
+}
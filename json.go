@@ -0,0 +1,196 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+var (
+	uint64Type       = reflect.TypeOf(uint64(0))
+	accountMetasType = reflect.TypeOf([]*solana.AccountMeta(nil))
+)
+
+// accountMetaJSON is the JSON form of an account meta.
+type accountMetaJSON struct {
+	PublicKey  solana.PublicKey `json:"pubkey"`
+	IsSigner   bool             `json:"isSigner"`
+	IsWritable bool             `json:"isWritable"`
+}
+
+// marshalJSONFields encodes the exported fields of the struct v points to
+// as a JSON object keyed by the lower camel case field names. Public keys
+// are base58 strings and u64 values are decimal strings, so that they
+// survive JSON parsers that use float64 numbers. Embedded fields, such as
+// the AccountMetaSlice of the builders, are left out.
+func marshalJSONFields(v interface{}) ([]byte, error) {
+	rv := reflect.ValueOf(v).Elem()
+	rt := rv.Type()
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !jsonField(field) {
+			continue
+		}
+		value, err := marshalJSONValue(rv.Field(i))
+		if err != nil {
+			return nil, fmt.Errorf("error while encoding %s: %w", field.Name, err)
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(strconv.Quote(jsonFieldName(field.Name)))
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// unmarshalJSONFields decodes an object written by marshalJSONFields into
+// the struct v points to. Missing fields are left unchanged; u64 values
+// may also be given as JSON numbers.
+func unmarshalJSONFields(data []byte, v interface{}) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(v).Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !jsonField(field) {
+			continue
+		}
+		name := jsonFieldName(field.Name)
+		raw, ok := fields[name]
+		if !ok {
+			continue
+		}
+		if err := unmarshalJSONValue(raw, rv.Field(i)); err != nil {
+			return fmt.Errorf("error while decoding %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func jsonField(field reflect.StructField) bool {
+	return field.IsExported() && !field.Anonymous && field.Tag.Get("json") != "-"
+}
+
+func marshalJSONValue(v reflect.Value) ([]byte, error) {
+	switch {
+	case v.Type() == uint64Type:
+		return json.Marshal(strconv.FormatUint(v.Uint(), 10))
+	case v.Kind() == reflect.Ptr && v.Type().Elem() == uint64Type:
+		if v.IsNil() {
+			return []byte("null"), nil
+		}
+		return json.Marshal(strconv.FormatUint(v.Elem().Uint(), 10))
+	case v.Type() == accountMetasType:
+		metas := make([]accountMetaJSON, 0, v.Len())
+		for _, meta := range v.Interface().([]*solana.AccountMeta) {
+			if meta != nil {
+				metas = append(metas, accountMetaJSON{meta.PublicKey, meta.IsSigner, meta.IsWritable})
+			}
+		}
+		return json.Marshal(metas)
+	}
+	return json.Marshal(v.Interface())
+}
+
+func unmarshalJSONValue(raw json.RawMessage, v reflect.Value) error {
+	switch {
+	case v.Type() == uint64Type:
+		n, err := parseJSONUint64(raw)
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+		return nil
+	case v.Kind() == reflect.Ptr && v.Type().Elem() == uint64Type:
+		if string(bytes.TrimSpace(raw)) == "null" {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		n, err := parseJSONUint64(raw)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(&n))
+		return nil
+	case v.Type() == accountMetasType:
+		var metas []accountMetaJSON
+		if err := json.Unmarshal(raw, &metas); err != nil {
+			return err
+		}
+		out := make([]*solana.AccountMeta, len(metas))
+		for i, meta := range metas {
+			out[i] = &solana.AccountMeta{PublicKey: meta.PublicKey, IsSigner: meta.IsSigner, IsWritable: meta.IsWritable}
+		}
+		v.Set(reflect.ValueOf(out))
+		return nil
+	}
+	return json.Unmarshal(raw, v.Addr().Interface())
+}
+
+// parseJSONUint64 parses a u64 written as a decimal string or a number.
+func parseJSONUint64(raw json.RawMessage) (uint64, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		s = string(bytes.TrimSpace(raw))
+	}
+	return strconv.ParseUint(s, 10, 64)
+}
+
+// jsonFieldName converts a Go field name to lower camel case:
+// "MintAuthority" becomes "mintAuthority" and "URI" becomes "uri".
+func jsonFieldName(name string) string {
+	runes := []rune(name)
+	for i := 0; i < len(runes) && unicode.IsUpper(runes[i]); i++ {
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// parseEnumName parses the String form of an enum: one of the count known
+// names, or "<typeName>(<n>)" for other values.
+func parseEnumName(text []byte, typeName string, count uint64, name func(uint64) string, bitSize int) (uint64, error) {
+	s := string(text)
+	for i := uint64(0); i < count; i++ {
+		if name(i) == s {
+			return i, nil
+		}
+	}
+	if inner, ok := strings.CutPrefix(s, typeName+"("); ok {
+		if n, err := strconv.ParseUint(strings.TrimSuffix(inner, ")"), 10, bitSize); err == nil && strings.HasSuffix(inner, ")") {
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown %s %q", typeName, s)
+}
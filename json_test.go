@@ -0,0 +1,128 @@
+package token2022
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+func TestInstructionJSON(t *testing.T) {
+
+	var (
+		source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		owner       = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	)
+
+	inst := NewTransferChecked2022Instruction(18_446_744_073_709_551_615, 6, source, mint, destination, owner)
+	encoded, err := json.Marshal(inst)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	want := `{"amount":"18446744073709551615","decimals":6,` +
+		`"source":"GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi",` +
+		`"mint":"D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn",` +
+		`"destination":"9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin",` +
+		`"owner":"nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun",` +
+		`"signers":null,"additionalAccounts":[]}`
+	if string(encoded) != want {
+		t.Errorf("unexpected JSON\n got %s\nwant %s", encoded, want)
+	}
+
+	// u64 values may also be plain numbers.
+	var decoded TransferChecked2022
+	if err := json.Unmarshal([]byte(`{"amount":1500,"decimals":2}`), &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if decoded.Amount != 1500 || decoded.Decimals != 2 {
+		t.Errorf("unexpected instruction %+v", decoded)
+	}
+	if err := json.Unmarshal([]byte(`{"amount":"-1"}`), &decoded); err == nil {
+		t.Errorf("expected an error for a negative amount")
+	}
+
+	setAuthority := NewSetAuthority2022Instruction(AuthorityTransferFeeConfig, nil, mint, owner)
+	encoded, err = json.Marshal(setAuthority)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var authority map[string]interface{}
+	if err := json.Unmarshal(encoded, &authority); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if authority["authorityType"] != "TransferFeeConfig" || authority["newAuthority"] != nil {
+		t.Errorf("unexpected JSON %s", encoded)
+	}
+}
+
+func TestStateJSON(t *testing.T) {
+
+	var (
+		mint  = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		owner = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	)
+
+	account, err := DecodeTokenAccount(encodeTokenAccount(mint, owner, 42, Extension{Type: ExtensionImmutableOwner}, Extension{Type: ExtensionType(99), Data: []byte{1}}))
+	if err != nil {
+		t.Fatalf("DecodeTokenAccount: %v", err)
+	}
+	encoded, err := json.Marshal(account)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if fields["amount"] != "42" || fields["state"] != "Initialized" || fields["isNative"] != nil || fields["owner"] != owner.String() {
+		t.Errorf("unexpected JSON %s", encoded)
+	}
+
+	var decoded TokenAccount
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(account, &decoded) {
+		t.Errorf("account changed after JSON round trip:\n%+v\n%+v", account, &decoded)
+	}
+
+	native := uint64(2_039_280)
+	account.IsNative = &native
+	encoded, _ = json.Marshal(account)
+	decoded = TokenAccount{}
+	if err := json.Unmarshal(encoded, &decoded); err != nil || decoded.IsNative == nil || *decoded.IsNative != native {
+		t.Errorf("unexpected isNative after round trip: %s", encoded)
+	}
+
+	mintAccount, err := DecodeMint(encodeMint(&owner, 1_000, 6))
+	if err != nil {
+		t.Fatalf("DecodeMint: %v", err)
+	}
+	encoded, _ = json.Marshal(mintAccount)
+	var decodedMint Mint
+	if err := json.Unmarshal(encoded, &decodedMint); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !reflect.DeepEqual(mintAccount, &decodedMint) {
+		t.Errorf("mint changed after JSON round trip: %s", encoded)
+	}
+}
+
+func TestJSONFieldName(t *testing.T) {
+	cases := map[string]string{
+		"Amount":          "amount",
+		"MintAuthority":   "mintAuthority",
+		"URI":             "uri",
+		"HookProgramID":   "hookProgramID",
+		"IsNative":        "isNative",
+		"UpdateAuthority": "updateAuthority",
+	}
+	for name, want := range cases {
+		if got := jsonFieldName(name); got != want {
+			t.Errorf("jsonFieldName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package awskms
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+)
+
+type fakeKMS struct {
+	key ed25519.PrivateKey
+}
+
+func (f *fakeKMS) Sign(ctx context.Context, params *kms.SignInput, optFns ...func(*kms.Options)) (*kms.SignOutput, error) {
+	return &kms.SignOutput{Signature: ed25519.Sign(f.key, params.Message)}, nil
+}
+
+func (f *fakeKMS) GetPublicKey(ctx context.Context, params *kms.GetPublicKeyInput, optFns ...func(*kms.Options)) (*kms.GetPublicKeyOutput, error) {
+	der, err := x509.MarshalPKIXPublicKey(f.key.Public())
+	if err != nil {
+		return nil, err
+	}
+	return &kms.GetPublicKeyOutput{
+		PublicKey: der,
+		KeySpec:   keySpecEd25519,
+	}, nil
+}
+
+func TestSigner(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+
+	signer, err := New(context.Background(), &fakeKMS{key: key}, "alias/treasury")
+	if err != nil {
+		t.Fatalf("Error creating signer: %v", err)
+	}
+	if string(signer.Pubkey().Bytes()) != string(key.Public().(ed25519.PublicKey)) {
+		t.Errorf("Public key mismatch")
+	}
+
+	message := []byte("token2022 message")
+	sig, err := signer.SignMessage(context.Background(), message)
+	if err != nil {
+		t.Fatalf("Error signing: %v", err)
+	}
+	if !sig.Verify(signer.Pubkey(), message) {
+		t.Errorf("Signature does not verify")
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gcpkms provides a token2022.Signer backed by a Google Cloud KMS
+// EC_SIGN_ED25519 key version, so transactions can be signed without the
+// private key ever leaving Cloud KMS.
+package gcpkms
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash/crc32"
+
+	"cloud.google.com/go/kms/apiv1/kmspb"
+	"github.com/dwmfan/token2022"
+	solana "github.com/gagliardetto/solana-go"
+	gax "github.com/googleapis/gax-go/v2"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// API is the subset of the Cloud KMS client used by Signer.
+// *kms.KeyManagementClient satisfies it.
+type API interface {
+	AsymmetricSign(ctx context.Context, req *kmspb.AsymmetricSignRequest, opts ...gax.CallOption) (*kmspb.AsymmetricSignResponse, error)
+	GetPublicKey(ctx context.Context, req *kmspb.GetPublicKeyRequest, opts ...gax.CallOption) (*kmspb.PublicKey, error)
+}
+
+// Signer signs Solana transaction messages with a Cloud KMS ed25519 key version.
+type Signer struct {
+	client API
+	name   string
+	pubkey solana.PublicKey
+}
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// New looks up the public key of the key version and returns a Signer for it.
+// name is the full resource name:
+// projects/*/locations/*/keyRings/*/cryptoKeys/*/cryptoKeyVersions/*.
+func New(ctx context.Context, client API, name string) (*Signer, error) {
+	out, err := client.GetPublicKey(ctx, &kmspb.GetPublicKeyRequest{Name: name})
+	if err != nil {
+		return nil, fmt.Errorf("error while GetPublicKey: %w", err)
+	}
+	if out.Algorithm != kmspb.CryptoKeyVersion_EC_SIGN_ED25519 {
+		return nil, fmt.Errorf("key %s uses %s, expected %s", name, out.Algorithm, kmspb.CryptoKeyVersion_EC_SIGN_ED25519)
+	}
+	pubkey, err := parsePublicKey(out.Pem)
+	if err != nil {
+		return nil, err
+	}
+	return &Signer{
+		client: client,
+		name:   name,
+		pubkey: pubkey,
+	}, nil
+}
+
+func (s *Signer) Pubkey() solana.PublicKey {
+	return s.pubkey
+}
+
+func (s *Signer) SignMessage(ctx context.Context, message []byte) (solana.Signature, error) {
+	out, err := s.client.AsymmetricSign(ctx, &kmspb.AsymmetricSignRequest{
+		Name:       s.name,
+		Data:       message,
+		DataCrc32C: wrapperspb.Int64(int64(crc32.Checksum(message, castagnoli))),
+	})
+	if err != nil {
+		return solana.Signature{}, fmt.Errorf("error while AsymmetricSign: %w", err)
+	}
+	if !out.VerifiedDataCrc32C {
+		return solana.Signature{}, errors.New("request corrupted in transit: data checksum not verified")
+	}
+	if len(out.Signature) != ed25519.SignatureSize {
+		return solana.Signature{}, fmt.Errorf("unexpected signature length %d", len(out.Signature))
+	}
+	sig := solana.SignatureFromBytes(out.Signature)
+	if !sig.Verify(s.pubkey, message) {
+		return solana.Signature{}, errors.New("KMS returned a signature that does not verify")
+	}
+	return sig, nil
+}
+
+func parsePublicKey(encoded string) (solana.PublicKey, error) {
+	block, _ := pem.Decode([]byte(encoded))
+	if block == nil {
+		return solana.PublicKey{}, errors.New("public key is not PEM encoded")
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("error while parsing public key: %w", err)
+	}
+	key, ok := parsed.(ed25519.PublicKey)
+	if !ok {
+		return solana.PublicKey{}, fmt.Errorf("expected an ed25519 public key, got %T", parsed)
+	}
+	return solana.PublicKeyFromBytes(key), nil
+}
+
+var _ token2022.Signer = (*Signer)(nil)
@@ -0,0 +1,60 @@
+package gcpkms
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"cloud.google.com/go/kms/apiv1/kmspb"
+	gax "github.com/googleapis/gax-go/v2"
+)
+
+type fakeKMS struct {
+	key ed25519.PrivateKey
+}
+
+func (f *fakeKMS) AsymmetricSign(ctx context.Context, req *kmspb.AsymmetricSignRequest, opts ...gax.CallOption) (*kmspb.AsymmetricSignResponse, error) {
+	return &kmspb.AsymmetricSignResponse{
+		Signature:          ed25519.Sign(f.key, req.Data),
+		VerifiedDataCrc32C: req.DataCrc32C != nil,
+	}, nil
+}
+
+func (f *fakeKMS) GetPublicKey(ctx context.Context, req *kmspb.GetPublicKeyRequest, opts ...gax.CallOption) (*kmspb.PublicKey, error) {
+	der, err := x509.MarshalPKIXPublicKey(f.key.Public())
+	if err != nil {
+		return nil, err
+	}
+	return &kmspb.PublicKey{
+		Pem:       string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+		Algorithm: kmspb.CryptoKeyVersion_EC_SIGN_ED25519,
+	}, nil
+}
+
+func TestSigner(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Error generating key: %v", err)
+	}
+
+	name := "projects/p/locations/global/keyRings/r/cryptoKeys/k/cryptoKeyVersions/1"
+	signer, err := New(context.Background(), &fakeKMS{key: key}, name)
+	if err != nil {
+		t.Fatalf("Error creating signer: %v", err)
+	}
+	if string(signer.Pubkey().Bytes()) != string(key.Public().(ed25519.PublicKey)) {
+		t.Errorf("Public key mismatch")
+	}
+
+	message := []byte("token2022 message")
+	sig, err := signer.SignMessage(context.Background(), message)
+	if err != nil {
+		t.Fatalf("Error signing: %v", err)
+	}
+	if !sig.Verify(signer.Pubkey(), message) {
+		t.Errorf("Signature does not verify")
+	}
+}
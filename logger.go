@@ -0,0 +1,52 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"log/slog"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// Logger receives the structured events of TxBuilder, Sender,
+// ThrottledClient, FailoverClient and DepositWatcher, such as a built
+// instruction, a sent, confirmed or retried transaction, or a retried
+// request. args alternate keys and values as with slog. *slog.Logger
+// satisfies it; without a Logger nothing is logged.
+type Logger interface {
+	Log(ctx context.Context, level slog.Level, msg string, args ...any)
+}
+
+var _ Logger = (*slog.Logger)(nil)
+
+// logEvent logs to logger when it is set.
+func logEvent(ctx context.Context, logger Logger, level slog.Level, msg string, args ...any) {
+	if logger != nil {
+		logger.Log(ctx, level, msg, args...)
+	}
+}
+
+// instructionLogName names inst for logs: the instruction name of
+// Token-2022 and Associated Token Account instructions, empty otherwise.
+func instructionLogName(inst solana.Instruction, data []byte) string {
+	switch program := inst.ProgramID(); {
+	case program.Equals(solana.Token2022ProgramID), program.Equals(solana.TokenProgramID):
+		return InstructionName(data)
+	case program.Equals(ProgramID):
+		return AssociatedTokenInstructionName(data)
+	}
+	return ""
+}
@@ -0,0 +1,246 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// memoV1ProgramID is the deprecated first version of the memo program.
+var memoV1ProgramID = solana.MustPublicKeyFromBase58("Memo1UhkJRfHyvLMcVucJwxXeuD728EqVDDwQDxFMNo")
+
+// ProgramInvocation is one program invocation reconstructed from
+// transaction logs.
+type ProgramInvocation struct {
+	ProgramID solana.PublicKey
+	// Depth is 1 for top-level instructions and grows with each CPI.
+	Depth int
+	// Logs are the "Program log:" messages, without the prefix.
+	Logs []string
+	// Data are the fields of the "Program data:" entries.
+	Data [][]byte
+	// ReturnData is the data set by the program with sol_set_return_data.
+	ReturnData []byte
+	// ComputeUnits is the number of compute units consumed.
+	ComputeUnits uint64
+	Failed       bool
+	// Err is the failure reported by the runtime, such as
+	// "custom program error: 0x1".
+	Err   string
+	Inner []*ProgramInvocation
+}
+
+// Instruction returns the instruction name logged by the SPL programs as
+// "Instruction: <name>", or "" when there is none.
+func (inv *ProgramInvocation) Instruction() string {
+	for _, line := range inv.Logs {
+		if name, ok := strings.CutPrefix(line, "Instruction: "); ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// ProgramLogs are the program invocations of a transaction, parsed from
+// its log messages.
+type ProgramLogs struct {
+	// Invocations are the top-level invocations, in order.
+	Invocations []*ProgramInvocation
+	// Truncated is set when the runtime truncated the logs.
+	Truncated bool
+}
+
+// ParseLogs reconstructs the program invocations from transaction or
+// simulation logs. Lines it does not recognize are ignored.
+func ParseLogs(logs []string) *ProgramLogs {
+	parsed := &ProgramLogs{}
+	var stack []*ProgramInvocation
+	top := func() *ProgramInvocation {
+		if len(stack) == 0 {
+			return nil
+		}
+		return stack[len(stack)-1]
+	}
+
+	for _, line := range logs {
+		if line == "Log truncated" {
+			parsed.Truncated = true
+			continue
+		}
+		rest, ok := strings.CutPrefix(line, "Program ")
+		if !ok {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(rest, "log: "):
+			if inv := top(); inv != nil {
+				inv.Logs = append(inv.Logs, strings.TrimPrefix(rest, "log: "))
+			}
+		case strings.HasPrefix(rest, "data: "):
+			if inv := top(); inv != nil {
+				for _, field := range strings.Fields(strings.TrimPrefix(rest, "data: ")) {
+					if data, err := base64.StdEncoding.DecodeString(field); err == nil {
+						inv.Data = append(inv.Data, data)
+					}
+				}
+			}
+		case strings.HasPrefix(rest, "return: "):
+			fields := strings.Fields(strings.TrimPrefix(rest, "return: "))
+			inv := top()
+			if inv == nil || len(fields) == 0 {
+				continue
+			}
+			inv.ReturnData = []byte{}
+			if len(fields) > 1 {
+				if data, err := base64.StdEncoding.DecodeString(fields[1]); err == nil {
+					inv.ReturnData = data
+				}
+			}
+		default:
+			programID, event, ok := strings.Cut(rest, " ")
+			if !ok {
+				continue
+			}
+			pubkey, err := solana.PublicKeyFromBase58(programID)
+			if err != nil {
+				continue
+			}
+			switch {
+			case strings.HasPrefix(event, "invoke ["):
+				inv := &ProgramInvocation{ProgramID: pubkey, Depth: len(stack) + 1}
+				if parent := top(); parent != nil {
+					parent.Inner = append(parent.Inner, inv)
+				} else {
+					parsed.Invocations = append(parsed.Invocations, inv)
+				}
+				stack = append(stack, inv)
+			case strings.HasPrefix(event, "consumed "):
+				if inv := top(); inv != nil && inv.ProgramID.Equals(pubkey) {
+					fields := strings.Fields(event)
+					if len(fields) > 1 {
+						inv.ComputeUnits, _ = strconv.ParseUint(fields[1], 10, 64)
+					}
+				}
+			case event == "success":
+				if inv := top(); inv != nil && inv.ProgramID.Equals(pubkey) {
+					stack = stack[:len(stack)-1]
+				}
+			case strings.HasPrefix(event, "failed: "):
+				if inv := top(); inv != nil && inv.ProgramID.Equals(pubkey) {
+					inv.Failed = true
+					inv.Err = strings.TrimPrefix(event, "failed: ")
+					stack = stack[:len(stack)-1]
+				}
+			}
+		}
+	}
+	return parsed
+}
+
+// All returns every invocation, depth first in execution order.
+func (l *ProgramLogs) All() []*ProgramInvocation {
+	var out []*ProgramInvocation
+	var walk func([]*ProgramInvocation)
+	walk = func(invocations []*ProgramInvocation) {
+		for _, inv := range invocations {
+			out = append(out, inv)
+			walk(inv.Inner)
+		}
+	}
+	walk(l.Invocations)
+	return out
+}
+
+// ReturnData returns the return data of the transaction: the data set by
+// the last program that set any.
+func (l *ProgramLogs) ReturnData() (solana.PublicKey, []byte, bool) {
+	all := l.All()
+	for i := len(all) - 1; i >= 0; i-- {
+		if all[i].ReturnData != nil {
+			return all[i].ProgramID, all[i].ReturnData, true
+		}
+	}
+	return solana.PublicKey{}, nil, false
+}
+
+// TokenInstructions returns the names of the Token-2022 instructions that
+// ran, including CPIs.
+func (l *ProgramLogs) TokenInstructions() []string {
+	var names []string
+	for _, inv := range l.All() {
+		if inv.ProgramID.Equals(solana.Token2022ProgramID) {
+			names = append(names, inv.Instruction())
+		}
+	}
+	return names
+}
+
+// Memos returns the memos logged by the memo programs, in order.
+func (l *ProgramLogs) Memos() []string {
+	var memos []string
+	for _, inv := range l.All() {
+		if !inv.ProgramID.Equals(solana.MemoProgramID) && !inv.ProgramID.Equals(memoV1ProgramID) {
+			continue
+		}
+		for _, line := range inv.Logs {
+			if memo, ok := parseMemoLog(line); ok {
+				memos = append(memos, memo)
+			}
+		}
+	}
+	return memos
+}
+
+// parseMemoLog parses the log of the memo program, for example
+// `Memo (len 5): "hello"`.
+func parseMemoLog(line string) (string, bool) {
+	rest, ok := strings.CutPrefix(line, "Memo (len ")
+	if !ok {
+		return "", false
+	}
+	_, quoted, ok := strings.Cut(rest, "): ")
+	if !ok {
+		return "", false
+	}
+	if memo, err := strconv.Unquote(quoted); err == nil {
+		return memo, true
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(quoted, `"`), `"`), true
+}
+
+// DecodeAmountReturnData decodes the u64 returned by UiAmountToAmount and
+// GetAccountDataSize.
+func DecodeAmountReturnData(data []byte) (uint64, error) {
+	if len(data) != 8 {
+		return 0, fmt.Errorf("expected 8 bytes of return data, got %d", len(data))
+	}
+	return binary.LittleEndian.Uint64(data), nil
+}
+
+// DecodeUiAmountReturnData decodes the UI amount string returned by
+// AmountToUiAmount.
+func DecodeUiAmountReturnData(data []byte) (string, error) {
+	if !utf8.Valid(data) {
+		return "", fmt.Errorf("return data is not a UTF-8 string")
+	}
+	return string(data), nil
+}
@@ -0,0 +1,117 @@
+package token2022
+
+import (
+	"encoding/binary"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+func TestParseLogs(t *testing.T) {
+	token := solana.Token2022ProgramID.String()
+	memo := solana.MemoProgramID.String()
+	logs := []string{
+		"Program " + memo + " invoke [1]",
+		`Program log: Memo (len 12): "order #1234\n"`,
+		"Program " + memo + " consumed 4000 of 200000 compute units",
+		"Program " + memo + " success",
+		"Program 9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin invoke [1]",
+		"Program log: routing",
+		"Program " + token + " invoke [2]",
+		"Program log: Instruction: TransferChecked",
+		"Program " + token + " consumed 1200 of 190000 compute units",
+		"Program " + token + " success",
+		"Program " + token + " invoke [2]",
+		"Program log: Instruction: AmountToUiAmount",
+		"Program return: " + token + " MS41",
+		"Program " + token + " success",
+		"Program data: AQID BAU=",
+		"Program 9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin failed: custom program error: 0x1",
+		"Log truncated",
+	}
+
+	parsed := ParseLogs(logs)
+	if len(parsed.Invocations) != 2 || !parsed.Truncated {
+		t.Fatalf("unexpected invocations %d (truncated %v)", len(parsed.Invocations), parsed.Truncated)
+	}
+	if memos := parsed.Memos(); len(memos) != 1 || memos[0] != "order #1234\n" {
+		t.Errorf("unexpected memos %q", memos)
+	}
+	if parsed.Invocations[0].ComputeUnits != 4000 {
+		t.Errorf("unexpected compute units %d", parsed.Invocations[0].ComputeUnits)
+	}
+
+	router := parsed.Invocations[1]
+	if !router.Failed || router.Err != "custom program error: 0x1" || len(router.Inner) != 2 {
+		t.Fatalf("unexpected router invocation %+v", router)
+	}
+	if router.Inner[0].Depth != 2 || router.Inner[0].Instruction() != "TransferChecked" {
+		t.Errorf("unexpected inner invocation %+v", router.Inner[0])
+	}
+	if len(router.Data) != 2 || router.Data[0][2] != 3 || router.Data[1][1] != 5 {
+		t.Errorf("unexpected program data %v", router.Data)
+	}
+	if names := parsed.TokenInstructions(); len(names) != 2 || names[1] != "AmountToUiAmount" {
+		t.Errorf("unexpected token instructions %q", names)
+	}
+
+	programID, data, ok := parsed.ReturnData()
+	if !ok || !programID.Equals(solana.Token2022ProgramID) {
+		t.Fatalf("expected Token-2022 return data")
+	}
+	if amount, err := DecodeUiAmountReturnData(data); err != nil || amount != "1.5" {
+		t.Errorf("unexpected ui amount %q: %v", amount, err)
+	}
+	if _, err := DecodeAmountReturnData(data); err == nil {
+		t.Errorf("expected an error for a 3 byte amount")
+	}
+	if amount, err := DecodeAmountReturnData(binary.LittleEndian.AppendUint64(nil, 1_500_000)); err != nil || amount != 1_500_000 {
+		t.Errorf("unexpected amount %d: %v", amount, err)
+	}
+}
+
+func TestDecodeTokenMetadata(t *testing.T) {
+
+	var (
+		authority = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint      = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	)
+
+	appendString := func(b []byte, s string) []byte {
+		b = binary.LittleEndian.AppendUint32(b, uint32(len(s)))
+		return append(b, s...)
+	}
+	data := append(authority.Bytes(), mint.Bytes()...)
+	data = appendString(data, "Token")
+	data = appendString(data, "TKN")
+	data = appendString(data, "https://example.com/token.json")
+	data = binary.LittleEndian.AppendUint32(data, 1)
+	data = appendString(data, "color")
+	data = appendString(data, "blue")
+
+	metadata, err := DecodeTokenMetadata(data)
+	if err != nil {
+		t.Fatalf("DecodeTokenMetadata: %v", err)
+	}
+	if metadata.UpdateAuthority == nil || !metadata.UpdateAuthority.Equals(authority) || !metadata.Mint.Equals(mint) {
+		t.Errorf("unexpected keys")
+	}
+	if metadata.Name != "Token" || metadata.Symbol != "TKN" || metadata.URI != "https://example.com/token.json" {
+		t.Errorf("unexpected metadata %+v", metadata)
+	}
+	if len(metadata.AdditionalMetadata) != 1 || metadata.AdditionalMetadata[0] != [2]string{"color", "blue"} {
+		t.Errorf("unexpected additional metadata %v", metadata.AdditionalMetadata)
+	}
+
+	mintAccount, err := DecodeMint(encodeMint(&authority, 0, 6, Extension{Type: ExtensionTokenMetadata, Data: data}))
+	if err != nil {
+		t.Fatalf("DecodeMint: %v", err)
+	}
+	if fromMint, ok, err := mintAccount.TokenMetadata(); !ok || err != nil || fromMint.Name != "Token" {
+		t.Errorf("unexpected mint metadata %+v %v %v", fromMint, ok, err)
+	}
+
+	if _, err := DecodeTokenMetadata(data[:len(data)-2]); err == nil {
+		t.Errorf("expected an error for truncated metadata")
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// NewMemoInstruction returns a Memo program instruction logging memo.
+// The memo program checks that the given signers signed the transaction;
+// none are needed for a plain memo.
+func NewMemoInstruction(memo string, signers ...solana.PublicKey) solana.Instruction {
+	accounts := make(solana.AccountMetaSlice, len(signers))
+	for i, signer := range signers {
+		accounts[i] = solana.Meta(signer).SIGNER()
+	}
+	return solana.NewInstruction(solana.MemoProgramID, accounts, []byte(memo))
+}
@@ -0,0 +1,328 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+// MetadataSource is where ResolveMetadata found a mint's metadata.
+type MetadataSource string
+
+const (
+	// MetadataSourceTokenMetadata is token-metadata interface state: the
+	// mint's TokenMetadata extension, or the account its MetadataPointer
+	// names.
+	MetadataSourceTokenMetadata MetadataSource = "token-metadata"
+	// MetadataSourceMetaplex is a Metaplex Token Metadata account.
+	MetadataSourceMetaplex MetadataSource = "metaplex"
+)
+
+// ResolvedMetadata is the name, symbol and URI of a mint, whichever
+// standard describes it.
+type ResolvedMetadata struct {
+	Name   string
+	Symbol string
+	URI    string
+	// UpdateAuthority is nil when the metadata is immutable.
+	UpdateAuthority *solana.PublicKey
+	Source          MetadataSource
+	// Address is the account the metadata was read from; the mint itself
+	// for metadata stored in its TokenMetadata extension.
+	Address solana.PublicKey
+}
+
+// MarshalJSON encodes the metadata with base58 keys.
+func (m ResolvedMetadata) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&m)
+}
+
+func (m *ResolvedMetadata) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, m)
+}
+
+// MetaplexMetadata is the leading part of a Metaplex Token Metadata
+// account: the fields before the optional creators.
+type MetaplexMetadata struct {
+	UpdateAuthority      solana.PublicKey
+	Mint                 solana.PublicKey
+	Name                 string
+	Symbol               string
+	URI                  string
+	SellerFeeBasisPoints uint16
+}
+
+// metaplexMetadataV1 is the key byte of Metaplex metadata accounts.
+const metaplexMetadataV1 = 4
+
+// FindMetaplexMetadataAddress derives the Metaplex metadata account of
+// mint.
+func FindMetaplexMetadataAddress(mint solana.PublicKey) (solana.PublicKey, uint8, error) {
+	return solana.FindProgramAddress([][]byte{
+		[]byte("metadata"),
+		solana.TokenMetadataProgramID[:],
+		mint[:],
+	}, solana.TokenMetadataProgramID)
+}
+
+// DecodeMetaplexMetadata decodes a Metaplex metadata account. Metaplex
+// pads names, symbols and URIs with NUL bytes; they are trimmed.
+func DecodeMetaplexMetadata(data []byte) (*MetaplexMetadata, error) {
+	if len(data) < 65 {
+		return nil, fmt.Errorf("%w: metaplex metadata too short: %d bytes", ErrInvalidAccountData, len(data))
+	}
+	if data[0] != metaplexMetadataV1 {
+		return nil, fmt.Errorf("%w: unexpected metaplex account key %d", ErrInvalidAccountData, data[0])
+	}
+	metadata := &MetaplexMetadata{
+		UpdateAuthority: solana.PublicKeyFromBytes(data[1:33]),
+		Mint:            solana.PublicKeyFromBytes(data[33:65]),
+	}
+	rest := data[65:]
+	readString := func() (string, error) {
+		if len(rest) < 4 {
+			return "", fmt.Errorf("%w: metaplex metadata truncated", ErrInvalidAccountData)
+		}
+		length := binary.LittleEndian.Uint32(rest)
+		if uint64(len(rest)-4) < uint64(length) {
+			return "", fmt.Errorf("%w: metaplex metadata string of %d bytes truncated", ErrInvalidAccountData, length)
+		}
+		s := strings.TrimRight(string(rest[4:4+length]), "\x00")
+		rest = rest[4+length:]
+		return s, nil
+	}
+	var err error
+	if metadata.Name, err = readString(); err != nil {
+		return nil, err
+	}
+	if metadata.Symbol, err = readString(); err != nil {
+		return nil, err
+	}
+	if metadata.URI, err = readString(); err != nil {
+		return nil, err
+	}
+	if len(rest) < 2 {
+		return nil, fmt.Errorf("%w: metaplex metadata truncated", ErrInvalidAccountData)
+	}
+	metadata.SellerFeeBasisPoints = binary.LittleEndian.Uint16(rest)
+	return metadata, nil
+}
+
+// interfaceDiscriminator returns the discriminator of an instruction or
+// TLV entry of the token-metadata or token-group interface: the first 8
+// bytes of the SHA-256 of its name.
+func interfaceDiscriminator(name string) []byte {
+	sum := sha256.Sum256([]byte(name))
+	return sum[:8]
+}
+
+var (
+	// tokenMetadataDiscriminator identifies TokenMetadata in the TLV data
+	// of accounts owned by programs implementing the token-metadata
+	// interface.
+	tokenMetadataDiscriminator = interfaceDiscriminator("spl_token_metadata_interface:token_metadata")
+
+	initializeTokenMetadataDiscriminator = interfaceDiscriminator("spl_token_metadata_interface:initialize_account")
+)
+
+func appendBorshString(b []byte, s string) []byte {
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(s)))
+	return append(b, s...)
+}
+
+// NewInitializeTokenMetadataInstruction initializes the TokenMetadata
+// extension of mint, whose MetadataPointer must point to the mint itself.
+// The program grows the mint to fit the metadata, so the mint must already
+// hold the rent for its final size.
+func NewInitializeTokenMetadataInstruction(mint, updateAuthority, mintAuthority solana.PublicKey, name, symbol, uri string) solana.Instruction {
+	data := append([]byte{}, initializeTokenMetadataDiscriminator...)
+	data = appendBorshString(data, name)
+	data = appendBorshString(data, symbol)
+	data = appendBorshString(data, uri)
+	return solana.NewInstruction(solana.Token2022ProgramID, solana.AccountMetaSlice{
+		solana.Meta(mint).WRITE(),
+		solana.Meta(updateAuthority),
+		solana.Meta(mint),
+		solana.Meta(mintAuthority).SIGNER(),
+	}, data)
+}
+
+// findTLVEntry returns the value of the entry with discriminator in the
+// TLV data of an account owned by a program implementing the
+// token-metadata or token-group interface.
+func findTLVEntry(data, discriminator []byte) ([]byte, bool) {
+	for len(data) >= 12 {
+		length := binary.LittleEndian.Uint32(data[8:12])
+		if uint64(len(data)-12) < uint64(length) {
+			break
+		}
+		if bytes.Equal(data[0:8], discriminator) {
+			return data[12 : 12+length], true
+		}
+		data = data[12+length:]
+	}
+	return nil, false
+}
+
+// ResolveMetadata returns the metadata of mint. It reads the mint's
+// TokenMetadata extension, or the account its MetadataPointer names, and
+// falls back to the Metaplex metadata account derived from the mint. SPL
+// Token mints go straight to Metaplex. It wraps ErrAccountNotFound when
+// no metadata exists.
+func ResolveMetadata(ctx context.Context, client RPCClient, mint solana.PublicKey, commitment rpc.CommitmentType) (*ResolvedMetadata, error) {
+	out, err := fetchAccount(ctx, client, mint, commitment)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case out.Owner.Equals(solana.Token2022ProgramID):
+		decoded, err := DecodeMint(out.Data.GetBinary())
+		if err != nil {
+			return nil, err
+		}
+		resolved, err := resolveMintMetadata(ctx, client, mint, decoded, commitment)
+		if resolved != nil || err != nil {
+			return resolved, err
+		}
+	case out.Owner.Equals(solana.TokenProgramID):
+	default:
+		return nil, fmt.Errorf("%w: %s is not a mint", ErrInvalidAccountOwner, mint)
+	}
+
+	address, _, err := FindMetaplexMetadataAddress(mint)
+	if err != nil {
+		return nil, fmt.Errorf("error while deriving metaplex metadata address: %w", err)
+	}
+	account, err := fetchAccount(ctx, client, address, commitment)
+	if err != nil {
+		if errors.Is(err, ErrAccountNotFound) {
+			return nil, fmt.Errorf("%w: no metadata for mint %s", ErrAccountNotFound, mint)
+		}
+		return nil, err
+	}
+	return resolveAccountMetadata(address, account)
+}
+
+// resolveMintMetadata reads the metadata a Token-2022 mint carries or
+// points to. It returns nil when the mint names no metadata, or points
+// to a Metaplex account that does not exist, so the caller falls back to
+// Metaplex.
+func resolveMintMetadata(ctx context.Context, client RPCClient, mint solana.PublicKey, decoded *Mint, commitment rpc.CommitmentType) (*ResolvedMetadata, error) {
+	pointer, _, err := decoded.MetadataPointer()
+	if err != nil {
+		return nil, err
+	}
+	if pointer == nil || pointer.Address == nil || pointer.Address.Equals(mint) {
+		metadata, ok, err := decoded.TokenMetadata()
+		if err != nil {
+			return nil, fmt.Errorf("error while decoding token metadata: %w", err)
+		}
+		if !ok {
+			return nil, nil
+		}
+		return resolvedTokenMetadata(metadata, mint), nil
+	}
+	account, err := fetchAccount(ctx, client, *pointer.Address, commitment)
+	if errors.Is(err, ErrAccountNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resolveAccountMetadata(*pointer.Address, account)
+}
+
+// resolveAccountMetadata decodes the metadata stored in account by its
+// owner's layout.
+func resolveAccountMetadata(address solana.PublicKey, account *rpc.Account) (*ResolvedMetadata, error) {
+	data := account.Data.GetBinary()
+	switch {
+	case account.Owner.Equals(solana.TokenMetadataProgramID):
+		metadata, err := DecodeMetaplexMetadata(data)
+		if err != nil {
+			return nil, err
+		}
+		resolved := &ResolvedMetadata{
+			Name:    metadata.Name,
+			Symbol:  metadata.Symbol,
+			URI:     metadata.URI,
+			Source:  MetadataSourceMetaplex,
+			Address: address,
+		}
+		if !metadata.UpdateAuthority.IsZero() {
+			resolved.UpdateAuthority = &metadata.UpdateAuthority
+		}
+		return resolved, nil
+	case account.Owner.Equals(solana.Token2022ProgramID):
+		decoded, err := DecodeMint(data)
+		if err != nil {
+			return nil, err
+		}
+		metadata, ok, err := decoded.TokenMetadata()
+		if err != nil {
+			return nil, fmt.Errorf("error while decoding token metadata: %w", err)
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w: %s has no token metadata", ErrInvalidAccountData, address)
+		}
+		return resolvedTokenMetadata(metadata, address), nil
+	default:
+		entry, ok := findTLVEntry(data, tokenMetadataDiscriminator)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s has no token metadata", ErrInvalidAccountData, address)
+		}
+		metadata, err := DecodeTokenMetadata(entry)
+		if err != nil {
+			return nil, err
+		}
+		return resolvedTokenMetadata(metadata, address), nil
+	}
+}
+
+func resolvedTokenMetadata(metadata *TokenMetadata, address solana.PublicKey) *ResolvedMetadata {
+	return &ResolvedMetadata{
+		Name:            metadata.Name,
+		Symbol:          metadata.Symbol,
+		URI:             metadata.URI,
+		UpdateAuthority: metadata.UpdateAuthority,
+		Source:          MetadataSourceTokenMetadata,
+		Address:         address,
+	}
+}
+
+// fetchAccount fetches an account of any owner, wrapping
+// ErrAccountNotFound when it does not exist.
+func fetchAccount(ctx context.Context, client RPCClient, account solana.PublicKey, commitment rpc.CommitmentType) (*rpc.Account, error) {
+	out, err := client.GetAccountInfoWithOpts(ctx, account, &rpc.GetAccountInfoOpts{Encoding: solana.EncodingBase64, Commitment: commitment})
+	if errors.Is(err, rpc.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s: %w", ErrAccountNotFound, account, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching account %s: %w", account, err)
+	}
+	if out == nil || out.Value == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, account)
+	}
+	return out.Value, nil
+}
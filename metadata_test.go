@@ -0,0 +1,165 @@
+package token2022
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func encodeTestTokenMetadata(authority, mint solana.PublicKey, name, symbol, uri string) []byte {
+	data := append(append([]byte{}, authority[:]...), mint[:]...)
+	data = appendBorshString(data, name)
+	data = appendBorshString(data, symbol)
+	data = appendBorshString(data, uri)
+	return binary.LittleEndian.AppendUint32(data, 0)
+}
+
+// encodeMetaplexMetadata encodes a Metaplex metadata account, padding the
+// strings with NULs to their maximum lengths as Metaplex does.
+func encodeMetaplexMetadata(authority, mint solana.PublicKey, name, symbol, uri string) []byte {
+	pad := func(s string, n int) string {
+		for len(s) < n {
+			s += "\x00"
+		}
+		return s
+	}
+	data := append([]byte{metaplexMetadataV1}, authority[:]...)
+	data = append(data, mint[:]...)
+	data = appendBorshString(data, pad(name, 32))
+	data = appendBorshString(data, pad(symbol, 10))
+	data = appendBorshString(data, pad(uri, 200))
+	data = binary.LittleEndian.AppendUint16(data, 500)
+	return append(data, 0, 0, 1)
+}
+
+func TestResolveMetadata(t *testing.T) {
+	mint := solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	authority := solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	external := solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR")
+	metaplex, _, err := FindMetaplexMetadataAddress(mint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pointer := func(address solana.PublicKey) Extension {
+		return Extension{Type: ExtensionMetadataPointer, Data: append(append([]byte{}, authority[:]...), address[:]...)}
+	}
+	tokenMetadata := Extension{Type: ExtensionTokenMetadata, Data: encodeTestTokenMetadata(authority, mint, "Token", "TKN", "https://example.com/token.json")}
+
+	t.Run("token metadata extension", func(t *testing.T) {
+		client := newMockRPC()
+		client.setAccount(mint, solana.Token2022ProgramID, encodeMint(&authority, 0, 6, pointer(mint), tokenMetadata))
+		resolved, err := ResolveMetadata(context.Background(), client, mint, rpc.CommitmentConfirmed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resolved.Source != MetadataSourceTokenMetadata || !resolved.Address.Equals(mint) {
+			t.Errorf("Expected token metadata of the mint, got %s from %s", resolved.Source, resolved.Address)
+		}
+		if resolved.Name != "Token" || resolved.Symbol != "TKN" || resolved.URI != "https://example.com/token.json" {
+			t.Errorf("Unexpected metadata: %+v", resolved)
+		}
+		if resolved.UpdateAuthority == nil || !resolved.UpdateAuthority.Equals(authority) {
+			t.Errorf("Expected update authority %s, got %v", authority, resolved.UpdateAuthority)
+		}
+		if client.calls["getAccountInfo"] != 1 {
+			t.Errorf("Expected 1 getAccountInfo call, got %d", client.calls["getAccountInfo"])
+		}
+	})
+
+	t.Run("metaplex fallback", func(t *testing.T) {
+		client := newMockRPC()
+		client.setAccount(mint, solana.Token2022ProgramID, encodeMint(&authority, 0, 6))
+		client.setAccount(metaplex, solana.TokenMetadataProgramID, encodeMetaplexMetadata(authority, mint, "Legacy", "LGC", "https://example.com/legacy.json"))
+		resolved, err := ResolveMetadata(context.Background(), client, mint, rpc.CommitmentConfirmed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resolved.Source != MetadataSourceMetaplex || !resolved.Address.Equals(metaplex) {
+			t.Errorf("Expected metaplex metadata from %s, got %s from %s", metaplex, resolved.Source, resolved.Address)
+		}
+		if resolved.Name != "Legacy" || resolved.Symbol != "LGC" || resolved.URI != "https://example.com/legacy.json" {
+			t.Errorf("Expected NUL padding trimmed, got %q %q %q", resolved.Name, resolved.Symbol, resolved.URI)
+		}
+	})
+
+	t.Run("spl token mint", func(t *testing.T) {
+		client := newMockRPC()
+		client.setAccount(mint, solana.TokenProgramID, encodeMint(&authority, 0, 6)[:MintSize])
+		client.setAccount(metaplex, solana.TokenMetadataProgramID, encodeMetaplexMetadata(authority, mint, "Legacy", "LGC", "uri"))
+		resolved, err := ResolveMetadata(context.Background(), client, mint, rpc.CommitmentConfirmed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resolved.Source != MetadataSourceMetaplex {
+			t.Errorf("Expected metaplex metadata, got %s", resolved.Source)
+		}
+	})
+
+	t.Run("external pointer", func(t *testing.T) {
+		client := newMockRPC()
+		client.setAccount(mint, solana.Token2022ProgramID, encodeMint(&authority, 0, 6, pointer(external)))
+		entry := encodeTestTokenMetadata(authority, mint, "External", "EXT", "uri")
+		data := append(append([]byte{}, tokenMetadataDiscriminator...), binary.LittleEndian.AppendUint32(nil, uint32(len(entry)))...)
+		client.setAccount(external, authority, append(data, entry...))
+		resolved, err := ResolveMetadata(context.Background(), client, mint, rpc.CommitmentConfirmed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resolved.Name != "External" || !resolved.Address.Equals(external) {
+			t.Errorf("Expected External from %s, got %s from %s", external, resolved.Name, resolved.Address)
+		}
+	})
+
+	t.Run("pointer to metaplex", func(t *testing.T) {
+		client := newMockRPC()
+		client.setAccount(mint, solana.Token2022ProgramID, encodeMint(&authority, 0, 6, pointer(metaplex)))
+		client.setAccount(metaplex, solana.TokenMetadataProgramID, encodeMetaplexMetadata(authority, mint, "Legacy", "LGC", "uri"))
+		resolved, err := ResolveMetadata(context.Background(), client, mint, rpc.CommitmentConfirmed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resolved.Source != MetadataSourceMetaplex || client.calls["getAccountInfo"] != 2 {
+			t.Errorf("Expected metaplex metadata in 2 calls, got %s in %d", resolved.Source, client.calls["getAccountInfo"])
+		}
+	})
+
+	t.Run("no metadata", func(t *testing.T) {
+		client := newMockRPC()
+		client.setAccount(mint, solana.Token2022ProgramID, encodeMint(&authority, 0, 6))
+		_, err := ResolveMetadata(context.Background(), client, mint, rpc.CommitmentConfirmed)
+		if !errors.Is(err, ErrAccountNotFound) {
+			t.Errorf("Expected ErrAccountNotFound, got %v", err)
+		}
+	})
+
+	t.Run("not a mint", func(t *testing.T) {
+		client := newMockRPC()
+		client.setAccount(mint, solana.SystemProgramID, nil)
+		_, err := ResolveMetadata(context.Background(), client, mint, rpc.CommitmentConfirmed)
+		if !errors.Is(err, ErrInvalidAccountOwner) {
+			t.Errorf("Expected ErrInvalidAccountOwner, got %v", err)
+		}
+	})
+}
+
+func TestDecodeMetaplexMetadata(t *testing.T) {
+	mint := solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	authority := solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	data := encodeMetaplexMetadata(authority, mint, "Name", "SYM", "uri")
+	metadata, err := DecodeMetaplexMetadata(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if metadata.SellerFeeBasisPoints != 500 || !metadata.Mint.Equals(mint) {
+		t.Errorf("Unexpected metadata: %+v", metadata)
+	}
+	for _, data := range [][]byte{data[:40], data[:100], append([]byte{1}, data[1:]...)} {
+		if _, err := DecodeMetaplexMetadata(data); !errors.Is(err, ErrInvalidAccountData) {
+			t.Errorf("Expected ErrInvalidAccountData, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// Metric names reported to a Metrics. Counters end in _total and
+// durations are in seconds. The label values passed with each metric are
+// listed next to it, in order.
+const (
+	// MetricTransactionsSent counts transactions accepted by the RPC node,
+	// including the rebuilt attempts of a Sender. No labels.
+	MetricTransactionsSent = "token2022_transactions_sent_total"
+	// MetricTransactionsConfirmed counts transactions that landed. Label:
+	// result, either "confirmed" or "failed".
+	MetricTransactionsConfirmed = "token2022_transactions_confirmed_total"
+	// MetricConfirmationSeconds is the time from sending an attempt to its
+	// confirmation. No labels.
+	MetricConfirmationSeconds = "token2022_confirmation_seconds"
+	// MetricRetries counts retried operations. Label: operation, one of
+	// "blockhash" for a Sender attempt rebuilt after its blockhash
+	// expired, "request" for a ThrottledClient request, "deposit_scan"
+	// for a DepositWatcher scan and "authority_watch" for an
+	// AuthorityWatcher poll.
+	MetricRetries = "token2022_retries_total"
+	// MetricRPCSeconds is the duration of each RPC request made by a
+	// ThrottledClient, retries included. Label: method, the JSON-RPC
+	// method.
+	MetricRPCSeconds = "token2022_rpc_seconds"
+	// MetricDecodeErrors counts transactions that could not be decoded.
+	// Label: source, "deposit" for a DepositWatcher or "authority_watch"
+	// for an AuthorityWatcher.
+	MetricDecodeErrors = "token2022_decode_errors_total"
+	// MetricFeeAccountsHarvested counts the token accounts whose withheld
+	// transfer fees were harvested to the mint or withdrawn by confirmed
+	// transactions of a Sender. Label: instruction, such as
+	// "HarvestWithheldTokensToMint".
+	MetricFeeAccountsHarvested = "token2022_fee_accounts_harvested_total"
+)
+
+// Metrics receives the counters and histograms of Sender, ThrottledClient
+// and DepositWatcher. Add increments a counter and Observe records a
+// histogram sample; labels are the label values of the metric, in the
+// order documented with its name. Implementations should ignore metrics
+// they do not know. Package tokenprom provides a Prometheus Metrics.
+type Metrics interface {
+	Add(name string, delta float64, labels ...string)
+	Observe(name string, value float64, labels ...string)
+}
+
+// addMetric increments a counter when metrics is set.
+func addMetric(metrics Metrics, name string, delta float64, labels ...string) {
+	if metrics != nil {
+		metrics.Add(name, delta, labels...)
+	}
+}
+
+// observeMetric records a histogram sample when metrics is set.
+func observeMetric(metrics Metrics, name string, value float64, labels ...string) {
+	if metrics != nil {
+		metrics.Observe(name, value, labels...)
+	}
+}
+
+// addHarvestedAccounts counts the token accounts harvested or withdrawn
+// from by the instructions of tx.
+func addHarvestedAccounts(metrics Metrics, tx *solana.Transaction) {
+	if metrics == nil {
+		return
+	}
+	parsed, err := ParseTransaction(tx, nil)
+	if err != nil {
+		return
+	}
+	for _, inst := range parsed.Instructions {
+		switch typed := inst.Instruction.(type) {
+		case *HarvestWithheldTokensToMint2022:
+			metrics.Add(MetricFeeAccountsHarvested, float64(len(typed.Sources)), builderName(typed))
+		case *WithdrawWithheldTokensFromAccounts2022:
+			metrics.Add(MetricFeeAccountsHarvested, float64(len(typed.Sources)), builderName(typed))
+		}
+	}
+}
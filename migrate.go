@@ -0,0 +1,256 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"fmt"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// MigrationMode selects how a Migration exchanges old tokens for new ones.
+type MigrationMode int
+
+const (
+	// MigrateBurnAndMint burns the holder's old tokens and mints the same
+	// value of new tokens, signed by the mint authority of the new mint.
+	MigrateBurnAndMint MigrationMode = iota
+	// MigrateEscrowSwap moves the holder's old tokens into an escrow
+	// account of the issuer and pays the new tokens from a reserve
+	// account, for issuers that cannot burn or mint.
+	MigrateEscrowSwap
+)
+
+// MigrationClient is the getProgramAccounts call used by
+// Migration.FetchHolders. *rpc.Client satisfies it.
+type MigrationClient interface {
+	GetProgramAccountsWithOpts(ctx context.Context, publicKey solana.PublicKey, opts *rpc.GetProgramAccountsOpts) (rpc.GetProgramAccountsResult, error)
+}
+
+var _ MigrationClient = (*rpc.Client)(nil)
+
+// MigrationHolder is a token account of the old mint to migrate.
+type MigrationHolder struct {
+	Owner   solana.PublicKey `json:"owner"`
+	Account solana.PublicKey `json:"account"`
+	Amount  uint64           `json:"amount,string"`
+}
+
+// Migration plans moving the holders of an SPL Token (Tokenkeg) mint to a
+// Token-2022 mint, one holder at a time. Each holder's instructions are
+// signed by the holder, who gives up the old tokens, and by Authority, who
+// issues the new ones, so they are usually collected with a
+// MultisigSession or a transaction request rather than sent by the issuer
+// alone.
+type Migration struct {
+	OldMint     solana.PublicKey
+	OldDecimals uint8
+	NewMint     solana.PublicKey
+	NewDecimals uint8
+	Mode        MigrationMode
+	// Authority is the mint authority of NewMint, or the owner of Reserve
+	// in escrow mode.
+	Authority solana.PublicKey
+	// Escrow is the SPL Token account of OldMint receiving the old tokens
+	// and Reserve the Token-2022 account of NewMint paying the new ones,
+	// in escrow mode.
+	Escrow  solana.PublicKey
+	Reserve solana.PublicKey
+	// Payer funds the associated token accounts of NewMint that are
+	// created; it defaults to Authority.
+	Payer solana.PublicKey
+}
+
+// NewMigration creates a burn-and-mint migration from oldMint to newMint,
+// with authority as the mint authority of newMint.
+func NewMigration(oldMint solana.PublicKey, oldDecimals uint8, newMint solana.PublicKey, newDecimals uint8, authority solana.PublicKey) *Migration {
+	return &Migration{
+		OldMint:     oldMint,
+		OldDecimals: oldDecimals,
+		NewMint:     newMint,
+		NewDecimals: newDecimals,
+		Mode:        MigrateBurnAndMint,
+		Authority:   authority,
+	}
+}
+
+// SetEscrowSwap switches the migration to escrow mode: old tokens move to
+// escrow and new tokens are paid from reserve, owned by the authority.
+func (m *Migration) SetEscrowSwap(escrow, reserve solana.PublicKey) *Migration {
+	m.Mode = MigrateEscrowSwap
+	m.Escrow = escrow
+	m.Reserve = reserve
+	return m
+}
+
+func (m *Migration) SetPayer(payer solana.PublicKey) *Migration {
+	m.Payer = payer
+	return m
+}
+
+func (m *Migration) Validate() error {
+	if m.OldMint.IsZero() {
+		return errNotSet("OldMint")
+	}
+	if m.NewMint.IsZero() {
+		return errNotSet("NewMint")
+	}
+	if m.Authority.IsZero() {
+		return errNotSet("Authority")
+	}
+	if m.Mode == MigrateEscrowSwap {
+		if m.Escrow.IsZero() {
+			return errNotSet("Escrow")
+		}
+		if m.Reserve.IsZero() {
+			return errNotSet("Reserve")
+		}
+	}
+	return nil
+}
+
+// ConvertAmount returns the raw amount of the new mint worth amount of
+// the old one. It fails when the new mint has fewer decimals and the
+// amount cannot be represented exactly.
+func (m *Migration) ConvertAmount(amount uint64) (uint64, error) {
+	return AmountFromRat(AmountToRat(amount, m.OldDecimals), m.NewDecimals)
+}
+
+// PlanHolder returns the instructions migrating holder: the idempotent
+// creation of the holder's associated token account of NewMint, then
+// either a burn of the old tokens and a mint of the new ones, or a
+// transfer into Escrow and a transfer from Reserve. The holder and
+// Authority sign them.
+func (m *Migration) PlanHolder(holder MigrationHolder) ([]solana.Instruction, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	if holder.Owner.IsZero() {
+		return nil, errNotSet("Owner")
+	}
+	if holder.Account.IsZero() {
+		return nil, errNotSet("Account")
+	}
+	if holder.Amount == 0 {
+		return nil, errInvalidField("Amount", "holder %s has nothing to migrate", holder.Account)
+	}
+	amount, err := m.ConvertAmount(holder.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("error while converting the balance of %s: %w", holder.Account, err)
+	}
+	payer := m.Payer
+	if payer.IsZero() {
+		payer = m.Authority
+	}
+	destination, _, err := FindAssociatedTokenAddress2022(holder.Owner, m.NewMint)
+	if err != nil {
+		return nil, err
+	}
+	instructions := []solana.Instruction{
+		NewCreate2022Instruction(payer, holder.Owner, m.NewMint).SetIdempotent(true).Build(),
+	}
+	switch m.Mode {
+	case MigrateBurnAndMint:
+		instructions = append(instructions,
+			NewBurnChecked2022Instruction(holder.Amount, m.OldDecimals, holder.Account, m.OldMint, holder.Owner).Build().WithProgramID(solana.TokenProgramID),
+			NewMintToChecked2022Instruction(amount, m.NewDecimals, m.NewMint, destination, m.Authority).Build())
+	case MigrateEscrowSwap:
+		instructions = append(instructions,
+			NewTransferChecked2022Instruction(holder.Amount, m.OldDecimals, holder.Account, m.OldMint, m.Escrow, holder.Owner).Build().WithProgramID(solana.TokenProgramID),
+			NewTransferChecked2022Instruction(amount, m.NewDecimals, m.Reserve, m.NewMint, destination, m.Authority).Build())
+	default:
+		return nil, fmt.Errorf("unknown migration mode %d", m.Mode)
+	}
+	return instructions, nil
+}
+
+// FetchHolders lists the token accounts of OldMint holding tokens at
+// commitment. The escrow account is left out.
+func (m *Migration) FetchHolders(ctx context.Context, client MigrationClient, commitment rpc.CommitmentType) ([]MigrationHolder, error) {
+	out, err := client.GetProgramAccountsWithOpts(ctx, solana.TokenProgramID, &rpc.GetProgramAccountsOpts{
+		Commitment: commitment,
+		Encoding:   solana.EncodingBase64,
+		Filters: []rpc.RPCFilter{
+			{DataSize: AccountSize},
+			{Memcmp: &rpc.RPCFilterMemcmp{Offset: 0, Bytes: m.OldMint[:]}},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error while listing token accounts: %w", err)
+	}
+	var holders []MigrationHolder
+	for _, keyed := range out {
+		account, err := DecodeTokenAccount(keyed.Account.Data.GetBinary())
+		if err != nil || account.Amount == 0 || keyed.Pubkey.Equals(m.Escrow) {
+			continue
+		}
+		holders = append(holders, MigrationHolder{Owner: account.Owner, Account: keyed.Pubkey, Amount: account.Amount})
+	}
+	return holders, nil
+}
+
+// MigrationProgress is the state of a migration, read from the chain.
+type MigrationProgress struct {
+	// Migrated counts the holders whose old account is empty or closed.
+	Migrated int
+	// Pending lists the holders still holding old tokens, with their
+	// current balance.
+	Pending []MigrationHolder
+	// Remaining is the old tokens still held by the pending holders.
+	Remaining uint64
+}
+
+// Progress reads the old accounts of holders and reports which of them
+// have migrated. Progress lives on chain, so it survives restarts and
+// counts holders who migrated through any channel.
+func (m *Migration) Progress(ctx context.Context, client MultipleAccountsClient, holders []MigrationHolder) (*MigrationProgress, error) {
+	accounts := make([]solana.PublicKey, len(holders))
+	for i, holder := range holders {
+		accounts[i] = holder.Account
+	}
+	raw, err := NewAccountFetcher(client).Fetch(ctx, accounts)
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching holder accounts: %w", err)
+	}
+	progress := &MigrationProgress{}
+	for i, out := range raw {
+		if out == nil {
+			progress.Migrated++
+			continue
+		}
+		if !out.Owner.Equals(solana.TokenProgramID) {
+			return nil, fmt.Errorf("%w: %s is not owned by the SPL Token program", ErrInvalidAccountOwner, accounts[i])
+		}
+		account, err := DecodeTokenAccount(out.Data.GetBinary())
+		if err != nil {
+			return nil, fmt.Errorf("error while decoding account %s: %w", accounts[i], err)
+		}
+		if account.Amount == 0 {
+			progress.Migrated++
+			continue
+		}
+		holder := holders[i]
+		holder.Amount = account.Amount
+		progress.Pending = append(progress.Pending, holder)
+		remaining, err := CheckedAdd(progress.Remaining, account.Amount)
+		if err != nil {
+			return nil, err
+		}
+		progress.Remaining = remaining
+	}
+	return progress, nil
+}
@@ -0,0 +1,83 @@
+package token2022
+
+import (
+	"context"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestMigration(t *testing.T) {
+	var (
+		oldMint   = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		newMint   = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		authority = solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR")
+		escrow    = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		ctx       = context.Background()
+		client    = newMockRPC()
+	)
+	owners := []solana.PublicKey{solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey(), solana.NewWallet().PublicKey()}
+	accounts := make([]solana.PublicKey, len(owners))
+	for i, owner := range owners {
+		accounts[i], _, _ = FindAssociatedTokenAddress(owner, oldMint, solana.TokenProgramID)
+		client.setAccount(accounts[i], solana.TokenProgramID, encodeTokenAccount(oldMint, owner, uint64(i)*1_000))
+	}
+	client.setAccount(escrow, solana.TokenProgramID, encodeTokenAccount(oldMint, authority, 5_000))
+	client.setAccount(solana.NewWallet().PublicKey(), solana.Token2022ProgramID, encodeTokenAccount(oldMint, owners[1], 7))
+
+	migration := NewMigration(oldMint, 6, newMint, 9, authority).SetEscrowSwap(escrow, solana.NewWallet().PublicKey())
+	holders, err := migration.FetchHolders(ctx, client, rpc.CommitmentConfirmed)
+	if err != nil {
+		t.Fatalf("FetchHolders: %v", err)
+	}
+	if len(holders) != 2 {
+		t.Fatalf("Expected the two SPL Token accounts holding tokens, got %+v", holders)
+	}
+
+	holder := MigrationHolder{Owner: owners[2], Account: accounts[2], Amount: 2_000}
+	for _, mode := range []MigrationMode{MigrateBurnAndMint, MigrateEscrowSwap} {
+		migration.Mode = mode
+		instructions, err := migration.PlanHolder(holder)
+		if err != nil {
+			t.Fatalf("PlanHolder: %v", err)
+		}
+		if len(instructions) != 3 {
+			t.Fatalf("Expected a create and two token instructions, got %d", len(instructions))
+		}
+		if program := instructions[1].ProgramID(); !program.Equals(solana.TokenProgramID) {
+			t.Errorf("Expected the old tokens to move under SPL Token, got %s", program)
+		}
+		if program := instructions[2].ProgramID(); !program.Equals(solana.Token2022ProgramID) {
+			t.Errorf("Expected the new tokens to move under Token-2022, got %s", program)
+		}
+		data, _ := instructions[2].Data()
+		decoded, err := DecodeInstruction(instructions[2].Accounts(), data)
+		if err != nil {
+			t.Fatalf("DecodeInstruction: %v", err)
+		}
+		var amount uint64
+		switch inst := decoded.(type) {
+		case *MintToChecked2022:
+			amount = inst.Amount
+		case *TransferChecked2022:
+			amount = inst.Amount
+		}
+		if amount != 2_000_000 {
+			t.Errorf("Expected 2000000 new units for mode %d, got %d", mode, amount)
+		}
+	}
+
+	if _, err := NewMigration(oldMint, 9, newMint, 6, authority).PlanHolder(MigrationHolder{Owner: owners[1], Account: accounts[1], Amount: 1_234}); err == nil {
+		t.Errorf("Expected an amount lost to fewer decimals to fail")
+	}
+
+	client.setAccount(accounts[1], solana.TokenProgramID, encodeTokenAccount(oldMint, owners[1], 0))
+	progress, err := migration.Progress(ctx, client, holders)
+	if err != nil {
+		t.Fatalf("Progress: %v", err)
+	}
+	if progress.Migrated != 1 || len(progress.Pending) != 1 || progress.Remaining != 2_000 {
+		t.Errorf("Expected one migrated and one pending holder of 2000, got %+v", progress)
+	}
+}
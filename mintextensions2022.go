@@ -0,0 +1,400 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	solana "github.com/gagliardetto/solana-go"
+	format "github.com/gagliardetto/solana-go/text/format"
+	treeout "github.com/gagliardetto/treeout"
+)
+
+// InitializeMintCloseAuthority2022 initializes the MintCloseAuthority extension, which allows
+// closing the mint once its supply is zero.
+type InitializeMintCloseAuthority2022 struct {
+	// The authority that can close the mint.
+	CloseAuthority *solana.PublicKey
+
+	Mint solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Mint
+	// ··········· The mint to initialize
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewInitializeMintCloseAuthority2022InstructionBuilder creates a new `InitializeMintCloseAuthority2022` instruction builder.
+func NewInitializeMintCloseAuthority2022InstructionBuilder() *InitializeMintCloseAuthority2022 {
+	nd := &InitializeMintCloseAuthority2022{}
+	return nd
+}
+
+func (inst *InitializeMintCloseAuthority2022) SetCloseAuthority(closeAuthority solana.PublicKey) *InitializeMintCloseAuthority2022 {
+	inst.CloseAuthority = &closeAuthority
+	return inst
+}
+
+func (inst *InitializeMintCloseAuthority2022) SetMint(mint solana.PublicKey) *InitializeMintCloseAuthority2022 {
+	inst.Mint = mint
+	return inst
+}
+
+func (inst InitializeMintCloseAuthority2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Mint,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+	}
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst InitializeMintCloseAuthority2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *InitializeMintCloseAuthority2022) Validate() error {
+	if inst.Mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+func (inst *InitializeMintCloseAuthority2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("InitializeMintCloseAuthority2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=1]").ParentFunc(func(paramsBranch treeout.Branches) {
+						paramsBranch.Child(format.Param("CloseAuthority", inst.CloseAuthority))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("mint", inst.AccountMetaSlice.Get(0)))
+					})
+				})
+		})
+}
+
+func (inst InitializeMintCloseAuthority2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	return WriteCOptionPubkey(encoder, COptionInstruction, inst.CloseAuthority)
+}
+
+func (inst *InitializeMintCloseAuthority2022) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	if inst.CloseAuthority, err = ReadCOptionPubkey(decoder, COptionInstruction); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst InitializeMintCloseAuthority2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *InitializeMintCloseAuthority2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("InitializeMintCloseAuthority2022", accounts, 1); err != nil {
+		return err
+	}
+	inst.Mint = accounts[0].PublicKey
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst InitializeMintCloseAuthority2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *InitializeMintCloseAuthority2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewInitializeMintCloseAuthority2022Instruction creates a new `InitializeMintCloseAuthority2022` instruction.
+func NewInitializeMintCloseAuthority2022Instruction(
+	closeAuthority *solana.PublicKey,
+	mint solana.PublicKey,
+) *InitializeMintCloseAuthority2022 {
+	inst := NewInitializeMintCloseAuthority2022InstructionBuilder().
+		SetMint(mint)
+	inst.CloseAuthority = closeAuthority
+	return inst
+}
+
+// InitializeNonTransferableMint2022 initializes the NonTransferable extension, which makes all
+// tokens of the mint non-transferable.
+type InitializeNonTransferableMint2022 struct {
+	Mint solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Mint
+	// ··········· The mint to initialize
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewInitializeNonTransferableMint2022InstructionBuilder creates a new `InitializeNonTransferableMint2022` instruction builder.
+func NewInitializeNonTransferableMint2022InstructionBuilder() *InitializeNonTransferableMint2022 {
+	nd := &InitializeNonTransferableMint2022{}
+	return nd
+}
+
+func (inst *InitializeNonTransferableMint2022) SetMint(mint solana.PublicKey) *InitializeNonTransferableMint2022 {
+	inst.Mint = mint
+	return inst
+}
+
+func (inst InitializeNonTransferableMint2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Mint,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+	}
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst InitializeNonTransferableMint2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *InitializeNonTransferableMint2022) Validate() error {
+	if inst.Mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+func (inst *InitializeNonTransferableMint2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("InitializeNonTransferableMint2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=0]").ParentFunc(func(paramsBranch treeout.Branches) {})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("mint", inst.AccountMetaSlice.Get(0)))
+					})
+				})
+		})
+}
+
+func (inst InitializeNonTransferableMint2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	return nil
+}
+
+func (inst *InitializeNonTransferableMint2022) UnmarshalWithDecoder(decoder *bin.Decoder) error {
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst InitializeNonTransferableMint2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *InitializeNonTransferableMint2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("InitializeNonTransferableMint2022", accounts, 1); err != nil {
+		return err
+	}
+	inst.Mint = accounts[0].PublicKey
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst InitializeNonTransferableMint2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *InitializeNonTransferableMint2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewInitializeNonTransferableMint2022Instruction creates a new `InitializeNonTransferableMint2022` instruction.
+func NewInitializeNonTransferableMint2022Instruction(
+	mint solana.PublicKey,
+) *InitializeNonTransferableMint2022 {
+	return NewInitializeNonTransferableMint2022InstructionBuilder().
+		SetMint(mint)
+}
+
+// InitializePermanentDelegate2022 initializes the PermanentDelegate extension, whose delegate
+// can transfer or burn tokens from any account of the mint.
+type InitializePermanentDelegate2022 struct {
+	// The permanent delegate of the mint.
+	Delegate solana.PublicKey
+
+	Mint solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Mint
+	// ··········· The mint to initialize
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewInitializePermanentDelegate2022InstructionBuilder creates a new `InitializePermanentDelegate2022` instruction builder.
+func NewInitializePermanentDelegate2022InstructionBuilder() *InitializePermanentDelegate2022 {
+	nd := &InitializePermanentDelegate2022{}
+	return nd
+}
+
+func (inst *InitializePermanentDelegate2022) SetDelegate(delegate solana.PublicKey) *InitializePermanentDelegate2022 {
+	inst.Delegate = delegate
+	return inst
+}
+
+func (inst *InitializePermanentDelegate2022) SetMint(mint solana.PublicKey) *InitializePermanentDelegate2022 {
+	inst.Mint = mint
+	return inst
+}
+
+func (inst InitializePermanentDelegate2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Mint,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+	}
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst InitializePermanentDelegate2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *InitializePermanentDelegate2022) Validate() error {
+	if inst.Delegate.IsZero() {
+		return errNotSet("Delegate")
+	}
+	if inst.Mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+func (inst *InitializePermanentDelegate2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("InitializePermanentDelegate2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=1]").ParentFunc(func(paramsBranch treeout.Branches) {
+						paramsBranch.Child(format.Param("Delegate", inst.Delegate))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("mint", inst.AccountMetaSlice.Get(0)))
+					})
+				})
+		})
+}
+
+func (inst InitializePermanentDelegate2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	return encoder.WriteBytes(inst.Delegate[:], false)
+}
+
+func (inst *InitializePermanentDelegate2022) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	if inst.Delegate, err = readPubkey(decoder); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst InitializePermanentDelegate2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *InitializePermanentDelegate2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("InitializePermanentDelegate2022", accounts, 1); err != nil {
+		return err
+	}
+	inst.Mint = accounts[0].PublicKey
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst InitializePermanentDelegate2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *InitializePermanentDelegate2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewInitializePermanentDelegate2022Instruction creates a new `InitializePermanentDelegate2022` instruction.
+func NewInitializePermanentDelegate2022Instruction(
+	delegate solana.PublicKey,
+	mint solana.PublicKey,
+) *InitializePermanentDelegate2022 {
+	return NewInitializePermanentDelegate2022InstructionBuilder().
+		SetDelegate(delegate).
+		SetMint(mint)
+}
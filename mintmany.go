@@ -0,0 +1,107 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// ErrMaxSupplyExceeded is returned by a MintToMany distributor whose
+// payments would take the supply past the limit set with SetMaxSupply.
+var ErrMaxSupplyExceeded = errors.New("minting would exceed the maximum supply")
+
+// NewMintToMany creates a Distributor that mints to the recipients with
+// MintToChecked instead of transferring, signed by the mint authority.
+// Before sending, it checks that authority is the mint authority of mint
+// and that the total fits the supply, or the limit set with SetMaxSupply.
+// Everything else, from account creation to resuming, works as for
+// transfers.
+func NewMintToMany(client DistributorClient, mint solana.PublicKey, decimals uint8, authority Signer) *Distributor {
+	d := NewDistributor(client, mint, decimals, solana.PublicKey{}, authority)
+	d.minting = true
+	return d
+}
+
+// SetMaxSupply caps the supply of the mint after a MintToMany
+// distribution, for instance at the amount the issuer intends to put in
+// circulation. It only applies to distributors from NewMintToMany.
+func (d *Distributor) SetMaxSupply(maxSupply uint64) *Distributor {
+	d.maxSupply = &maxSupply
+	return d
+}
+
+// checkSupply checks that the distributor may mint total more tokens.
+func (d *Distributor) checkSupply(ctx context.Context, total uint64) error {
+	mint, err := FetchMint(ctx, d.client, d.mint, d.commitment)
+	if err != nil {
+		return fmt.Errorf("error while fetching mint %s: %w", d.mint, err)
+	}
+	if mint.MintAuthority == nil {
+		return fmt.Errorf("%w: mint %s has no mint authority", ErrOwnerMismatch, d.mint)
+	}
+	if !mint.MintAuthority.Equals(d.authority.Pubkey()) {
+		return fmt.Errorf("%w: mint %s is minted by %s, not %s", ErrOwnerMismatch, d.mint, *mint.MintAuthority, d.authority.Pubkey())
+	}
+	if mint.Decimals != d.decimals {
+		return fmt.Errorf("%w: mint %s has %d decimals, not %d", ErrMintDecimalsMismatch, d.mint, mint.Decimals, d.decimals)
+	}
+	supply, err := CheckedAdd(mint.Supply, total)
+	if err != nil {
+		return fmt.Errorf("error while adding %d to the supply of %d: %w", total, mint.Supply, err)
+	}
+	if d.maxSupply != nil && supply > *d.maxSupply {
+		return fmt.Errorf("%w: supply would be %d, above %d", ErrMaxSupplyExceeded, supply, *d.maxSupply)
+	}
+	return nil
+}
+
+// ParseRecipientsCSV reads recipients from CSV rows of a wallet address
+// and a decimal amount, such as "9xQe…VFin,12.5", converting amounts to
+// raw units with decimals. A first row whose address does not parse is
+// taken as a header and skipped.
+func ParseRecipientsCSV(r io.Reader, decimals uint8) ([]Recipient, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+	var recipients []Recipient
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return recipients, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error while reading recipients: %w", err)
+		}
+		wallet, err := solana.PublicKeyFromBase58(strings.TrimSpace(record[0]))
+		if err != nil {
+			if row == 1 {
+				continue
+			}
+			return nil, fmt.Errorf("row %d: invalid wallet %q: %w", row, record[0], err)
+		}
+		amount, err := ParseAmount(strings.TrimSpace(record[1]), decimals)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", row, err)
+		}
+		recipients = append(recipients, Recipient{Wallet: wallet, Amount: amount})
+	}
+}
@@ -0,0 +1,88 @@
+package token2022
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestMintToMany(t *testing.T) {
+	var (
+		authority = solana.NewWallet().PrivateKey
+		mint      = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		other     = solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR")
+		client    = newMockRPC()
+		ctx       = context.Background()
+	)
+	mintAuthority := authority.PublicKey()
+	client.setAccount(mint, solana.Token2022ProgramID, encodeMint(&mintAuthority, 1_000, 2))
+	client.onSend = func(tx *solana.Transaction) {
+		client.statuses[tx.Signatures[0]] = &rpc.SignatureStatusesResult{Slot: 42, ConfirmationStatus: rpc.ConfirmationStatusConfirmed}
+	}
+
+	recipients, err := ParseRecipientsCSV(strings.NewReader(
+		"wallet,amount\n"+
+			"nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun,1.5\n"+
+			"9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin, 2\n"), 2)
+	if err != nil {
+		t.Fatalf("ParseRecipientsCSV: %v", err)
+	}
+	if len(recipients) != 2 || recipients[0].Amount != 150 || recipients[1].Amount != 200 {
+		t.Fatalf("Unexpected recipients %+v", recipients)
+	}
+	if _, err := ParseRecipientsCSV(strings.NewReader("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun,1.234\n"), 2); err == nil {
+		t.Errorf("Expected too many decimal places to fail")
+	}
+
+	distributor := NewMintToMany(client, mint, 2, NewPrivateKeySigner(authority)).
+		SetSender(NewSender(client).SetPollInterval(time.Millisecond)).
+		SetConcurrency(1)
+	transactions, err := distributor.Plan(ctx, recipients)
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	if len(transactions) != 1 || len(transactions[0]) != 4 {
+		t.Fatalf("Expected one transaction with two creates and two mints, got %v", transactions)
+	}
+	data, _ := transactions[0][1].Data()
+	decoded, err := DecodeInstruction(transactions[0][1].Accounts(), data)
+	if err != nil {
+		t.Fatalf("DecodeInstruction: %v", err)
+	}
+	if mintTo, ok := decoded.(*MintToChecked2022); !ok || mintTo.Amount != 150 {
+		t.Errorf("Expected a MintToChecked of 150, got %+v", decoded)
+	}
+
+	if _, err := distributor.SetMaxSupply(1_300).Plan(ctx, recipients); !errors.Is(err, ErrMaxSupplyExceeded) {
+		t.Errorf("Expected ErrMaxSupplyExceeded, got %v", err)
+	}
+	report, err := distributor.SetMaxSupply(1_350).Distribute(ctx, recipients)
+	if err != nil {
+		t.Fatalf("Distribute: %v", err)
+	}
+	if report.Count(DistributionPaid) != 2 {
+		t.Errorf("Expected both recipients to be paid, got %+v", report.Results)
+	}
+
+	// A program derived address from the CSV, here an associated token
+	// account, fails the plan unless off-curve owners are allowed.
+	pda, _, _ := FindAssociatedTokenAddress2022(recipients[0].Wallet, mint)
+	offCurve := append([]Recipient{{Wallet: pda, Amount: 1}}, recipients...)
+	if _, err := distributor.Plan(ctx, offCurve); !errors.Is(err, ErrOwnerOffCurve) {
+		t.Errorf("Expected ErrOwnerOffCurve, got %v", err)
+	}
+	if _, err := distributor.SetMaxSupply(1_400).SetAllowOwnerOffCurve(true).Plan(ctx, offCurve); err != nil {
+		t.Errorf("Expected an allowed off-curve owner to plan, got %v", err)
+	}
+	distributor.SetAllowOwnerOffCurve(false)
+
+	client.setAccount(mint, solana.Token2022ProgramID, encodeMint(&other, 1_000, 2))
+	if _, err := distributor.Plan(ctx, recipients); !errors.Is(err, ErrOwnerMismatch) {
+		t.Errorf("Expected ErrOwnerMismatch for another mint authority, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/programs/system"
+)
+
+// MintPlan is the creation of a mint: the instructions creating and
+// initializing it, to send in one transaction, with the space the account
+// is created with and the lamports funding it.
+type MintPlan struct {
+	Mint solana.PublicKey
+	// Extensions lists the extensions of the mint in the order the
+	// instructions initialize them.
+	Extensions []ExtensionType
+	// Space is the size the account is created with. Variable-length
+	// extensions such as TokenMetadata grow it afterwards; Lamports
+	// covers the rent of the final size.
+	Space        int
+	Lamports     uint64
+	Instructions []solana.Instruction
+}
+
+// mintPlanner assembles a MintPlan: the extension instructions that must
+// precede InitializeMint, then InitializeMint, then the instructions that
+// write variable-length extensions into the initialized mint.
+type mintPlanner struct {
+	mint solana.PublicKey
+	// fixed lists the extensions the account is created with, variable
+	// those written into it after InitializeMint.
+	fixed    []ExtensionType
+	variable []ExtensionType
+	before   []solana.Instruction
+	after    []solana.Instruction
+	// grow is the space the after instructions add to the mint.
+	grow int
+}
+
+// extension adds a fixed-size extension initialized before the mint.
+func (p *mintPlanner) extension(t ExtensionType, inst solana.Instruction) {
+	p.fixed = append(p.fixed, t)
+	p.before = append(p.before, inst)
+}
+
+// grown adds a variable-length extension t, written into the initialized
+// mint by inst and growing it by size bytes.
+func (p *mintPlanner) grown(t ExtensionType, inst solana.Instruction, size int) {
+	p.variable = append(p.variable, t)
+	p.after = append(p.after, inst)
+	p.grow += 4 + size
+}
+
+// metadata adds a MetadataPointer to the mint itself and the
+// TokenMetadata it points to.
+func (p *mintPlanner) metadata(updateAuthority, mintAuthority solana.PublicKey, name, symbol, uri string) {
+	p.extension(ExtensionMetadataPointer, NewInitializeMetadataPointer2022Instruction(&updateAuthority, &p.mint, p.mint).Build())
+	p.grown(ExtensionTokenMetadata,
+		NewInitializeTokenMetadataInstruction(p.mint, updateAuthority, mintAuthority, name, symbol, uri),
+		TokenMetadataLength(&TokenMetadata{UpdateAuthority: &updateAuthority, Mint: p.mint, Name: name, Symbol: symbol, URI: uri}))
+}
+
+// groupMember adds a GroupMemberPointer to the mint itself and the
+// TokenGroupMember it points to, making the mint the next member of
+// group.
+func (p *mintPlanner) groupMember(pointerAuthority, mintAuthority, group, groupUpdateAuthority solana.PublicKey) {
+	p.extension(ExtensionGroupMemberPointer, NewInitializeGroupMemberPointer2022Instruction(&pointerAuthority, &p.mint, p.mint).Build())
+	p.grown(ExtensionTokenGroupMember,
+		NewInitializeGroupMemberInstruction(p.mint, mintAuthority, group, groupUpdateAuthority),
+		extensionLengths[ExtensionTokenGroupMember])
+}
+
+// plannedExtensions returns the extensions of the mint in the order the
+// plan initializes them.
+func (p *mintPlanner) plannedExtensions() []ExtensionType {
+	return append(append([]ExtensionType{}, p.fixed...), p.variable...)
+}
+
+// plan returns the plan creating the mint with initialize, funded by
+// payer.
+func (p *mintPlanner) plan(payer solana.PublicKey, initialize solana.Instruction) (*MintPlan, error) {
+	space, err := MintSpace(p.fixed...)
+	if err != nil {
+		return nil, err
+	}
+	lamports := RentExemptLamports(space + p.grow)
+	instructions := []solana.Instruction{
+		system.NewCreateAccountInstruction(lamports, uint64(space), solana.Token2022ProgramID, payer, p.mint).Build(),
+	}
+	instructions = append(instructions, p.before...)
+	instructions = append(instructions, initialize)
+	instructions = append(instructions, p.after...)
+	return &MintPlan{
+		Mint:         p.mint,
+		Extensions:   p.plannedExtensions(),
+		Space:        space,
+		Lamports:     lamports,
+		Instructions: instructions,
+	}, nil
+}
+
+// keyOr returns *key, or fallback when key is nil.
+func keyOr(key *solana.PublicKey, fallback solana.PublicKey) solana.PublicKey {
+	if key != nil {
+		return *key
+	}
+	return fallback
+}
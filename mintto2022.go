@@ -0,0 +1,425 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	solana "github.com/gagliardetto/solana-go"
+	format "github.com/gagliardetto/solana-go/text/format"
+	treeout "github.com/gagliardetto/treeout"
+)
+
+// MintTo2022 mints new tokens to an account.
+type MintTo2022 struct {
+	// The amount of new tokens to mint.
+	Amount uint64
+
+	Mint          solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Destination   solana.PublicKey `bin:"-" borsh_skip:"true"`
+	MintAuthority solana.PublicKey `bin:"-" borsh_skip:"true"`
+	// Signers are the signers of a multisig authority.
+	Signers []solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Mint
+	// ··········· The mint
+	//
+	// [1] = [WRITE] Destination
+	// ··········· The account to mint tokens to
+	//
+	// [2] = [SIGNER] MintAuthority
+	// ··········· The mint's minting authority, or a multisig
+	//
+	// [3...] = [SIGNER] Signers
+	// ··········· M signer accounts when the authority is a multisig
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewMintTo2022InstructionBuilder creates a new `MintTo2022` instruction builder.
+func NewMintTo2022InstructionBuilder() *MintTo2022 {
+	nd := &MintTo2022{}
+	return nd
+}
+
+func (inst *MintTo2022) SetAmount(amount uint64) *MintTo2022 {
+	inst.Amount = amount
+	return inst
+}
+
+func (inst *MintTo2022) SetMint(mint solana.PublicKey) *MintTo2022 {
+	inst.Mint = mint
+	return inst
+}
+
+func (inst *MintTo2022) SetDestination(destination solana.PublicKey) *MintTo2022 {
+	inst.Destination = destination
+	return inst
+}
+
+// SetMintAuthority sets the authority. Pass the signers when the authority is a multisig.
+func (inst *MintTo2022) SetMintAuthority(mintAuthority solana.PublicKey, multisigSigners ...solana.PublicKey) *MintTo2022 {
+	inst.MintAuthority = mintAuthority
+	inst.Signers = multisigSigners
+	return inst
+}
+
+func (inst MintTo2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Mint,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+		{
+			PublicKey:  inst.Destination,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+	}
+	keys = appendAuthority(keys, inst.MintAuthority, inst.Signers)
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst MintTo2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *MintTo2022) Validate() error {
+	if inst.Mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	if inst.Destination.IsZero() {
+		return errNotSet("Destination")
+	}
+	if inst.MintAuthority.IsZero() {
+		return errNotSet("MintAuthority")
+	}
+	if err := validateMultisigSigners(inst.Signers); err != nil {
+		return err
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+// ValidateOnChain validates the instruction and checks its accounts on
+// chain; see the package function ValidateOnChain.
+func (inst *MintTo2022) ValidateOnChain(ctx context.Context, client RPCClient) error {
+	return ValidateOnChain(ctx, client, inst)
+}
+
+// ValidateStrict validates the instruction and checks its values against
+// each other; see the package function ValidateStrict.
+func (inst *MintTo2022) ValidateStrict() error {
+	return ValidateStrict(inst)
+}
+
+func (inst *MintTo2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("MintTo2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=1]").ParentFunc(func(paramsBranch treeout.Branches) {
+						paramsBranch.Child(format.Param("Amount", inst.Amount))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("         mint", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(format.Meta("  destination", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(format.Meta("mintAuthority", inst.AccountMetaSlice.Get(2)))
+						encodeRemainingAccounts(accountsBranch, "signer", inst.AccountMetaSlice, 3)
+					})
+				})
+		})
+}
+
+func (inst MintTo2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	return encoder.WriteUint64(inst.Amount, bin.LE)
+}
+
+func (inst *MintTo2022) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	if inst.Amount, err = decoder.ReadUint64(bin.LE); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst MintTo2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *MintTo2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("MintTo2022", accounts, 3); err != nil {
+		return err
+	}
+	inst.Mint = accounts[0].PublicKey
+	inst.Destination = accounts[1].PublicKey
+	inst.MintAuthority = accounts[2].PublicKey
+	inst.Signers = pubkeysOf(accounts[3:])
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst MintTo2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *MintTo2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewMintTo2022Instruction creates a new `MintTo2022` instruction.
+func NewMintTo2022Instruction(
+	amount uint64,
+	mint solana.PublicKey,
+	destination solana.PublicKey,
+	mintAuthority solana.PublicKey,
+	multisigSigners ...solana.PublicKey,
+) *MintTo2022 {
+	return NewMintTo2022InstructionBuilder().
+		SetAmount(amount).
+		SetMint(mint).
+		SetDestination(destination).
+		SetMintAuthority(mintAuthority, multisigSigners...)
+}
+
+// MintToChecked2022 mints new tokens to an account, checking the number of
+// decimals.
+type MintToChecked2022 struct {
+	// The amount of new tokens to mint.
+	Amount uint64
+
+	// Expected number of base 10 digits to the right of the decimal place.
+	Decimals uint8
+
+	Mint          solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Destination   solana.PublicKey `bin:"-" borsh_skip:"true"`
+	MintAuthority solana.PublicKey `bin:"-" borsh_skip:"true"`
+	// Signers are the signers of a multisig authority.
+	Signers []solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Mint
+	// ··········· The mint
+	//
+	// [1] = [WRITE] Destination
+	// ··········· The account to mint tokens to
+	//
+	// [2] = [SIGNER] MintAuthority
+	// ··········· The mint's minting authority, or a multisig
+	//
+	// [3...] = [SIGNER] Signers
+	// ··········· M signer accounts when the authority is a multisig
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewMintToChecked2022InstructionBuilder creates a new `MintToChecked2022` instruction builder.
+func NewMintToChecked2022InstructionBuilder() *MintToChecked2022 {
+	nd := &MintToChecked2022{}
+	return nd
+}
+
+func (inst *MintToChecked2022) SetAmount(amount uint64) *MintToChecked2022 {
+	inst.Amount = amount
+	return inst
+}
+
+func (inst *MintToChecked2022) SetDecimals(decimals uint8) *MintToChecked2022 {
+	inst.Decimals = decimals
+	return inst
+}
+
+func (inst *MintToChecked2022) SetMint(mint solana.PublicKey) *MintToChecked2022 {
+	inst.Mint = mint
+	return inst
+}
+
+func (inst *MintToChecked2022) SetDestination(destination solana.PublicKey) *MintToChecked2022 {
+	inst.Destination = destination
+	return inst
+}
+
+// SetMintAuthority sets the authority. Pass the signers when the authority is a multisig.
+func (inst *MintToChecked2022) SetMintAuthority(mintAuthority solana.PublicKey, multisigSigners ...solana.PublicKey) *MintToChecked2022 {
+	inst.MintAuthority = mintAuthority
+	inst.Signers = multisigSigners
+	return inst
+}
+
+func (inst MintToChecked2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Mint,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+		{
+			PublicKey:  inst.Destination,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+	}
+	keys = appendAuthority(keys, inst.MintAuthority, inst.Signers)
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst MintToChecked2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *MintToChecked2022) Validate() error {
+	if inst.Mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	if inst.Destination.IsZero() {
+		return errNotSet("Destination")
+	}
+	if inst.MintAuthority.IsZero() {
+		return errNotSet("MintAuthority")
+	}
+	if err := validateMultisigSigners(inst.Signers); err != nil {
+		return err
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+// ValidateOnChain validates the instruction and checks its accounts on
+// chain; see the package function ValidateOnChain.
+func (inst *MintToChecked2022) ValidateOnChain(ctx context.Context, client RPCClient) error {
+	return ValidateOnChain(ctx, client, inst)
+}
+
+// ValidateStrict validates the instruction and checks its values against
+// each other; see the package function ValidateStrict.
+func (inst *MintToChecked2022) ValidateStrict() error {
+	return ValidateStrict(inst)
+}
+
+func (inst *MintToChecked2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("MintToChecked2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=2]").ParentFunc(func(paramsBranch treeout.Branches) {
+						paramsBranch.Child(format.Param("  Amount", inst.Amount))
+						paramsBranch.Child(format.Param("Decimals", inst.Decimals))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("         mint", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(format.Meta("  destination", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(format.Meta("mintAuthority", inst.AccountMetaSlice.Get(2)))
+						encodeRemainingAccounts(accountsBranch, "signer", inst.AccountMetaSlice, 3)
+					})
+				})
+		})
+}
+
+func (inst MintToChecked2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	if err := encoder.WriteUint64(inst.Amount, bin.LE); err != nil {
+		return err
+	}
+	return encoder.WriteUint8(inst.Decimals)
+}
+
+func (inst *MintToChecked2022) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	if inst.Amount, err = decoder.ReadUint64(bin.LE); err != nil {
+		return err
+	}
+	if inst.Decimals, err = decoder.ReadUint8(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst MintToChecked2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *MintToChecked2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("MintToChecked2022", accounts, 3); err != nil {
+		return err
+	}
+	inst.Mint = accounts[0].PublicKey
+	inst.Destination = accounts[1].PublicKey
+	inst.MintAuthority = accounts[2].PublicKey
+	inst.Signers = pubkeysOf(accounts[3:])
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst MintToChecked2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *MintToChecked2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewMintToChecked2022Instruction creates a new `MintToChecked2022` instruction.
+func NewMintToChecked2022Instruction(
+	amount uint64,
+	decimals uint8,
+	mint solana.PublicKey,
+	destination solana.PublicKey,
+	mintAuthority solana.PublicKey,
+	multisigSigners ...solana.PublicKey,
+) *MintToChecked2022 {
+	return NewMintToChecked2022InstructionBuilder().
+		SetAmount(amount).
+		SetDecimals(decimals).
+		SetMint(mint).
+		SetDestination(destination).
+		SetMintAuthority(mintAuthority, multisigSigners...)
+}
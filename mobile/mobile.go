@@ -0,0 +1,388 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mobile is a facade over token2022 for wallets built with
+// gomobile:
+//
+//	gomobile bind -target=ios,android github.com/dwmfan/token2022/mobile
+//
+// It only uses types gomobile can bind: strings, bools, ints, byte
+// slices and pointers to structs of those. Public keys, signatures and
+// hashes are base58 strings, and token amounts are decimal strings of raw
+// units, since gomobile has no unsigned 64-bit integer. The wallet fetches
+// accounts and blockhashes and signs with its own keystore; this package
+// does not touch the network.
+package mobile
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/dwmfan/token2022"
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// AssociatedTokenAddress returns the Token-2022 associated token account
+// of wallet for mint.
+func AssociatedTokenAddress(wallet, mint string) (string, error) {
+	walletKey, err := parsePublicKey("wallet", wallet)
+	if err != nil {
+		return "", err
+	}
+	mintKey, err := parsePublicKey("mint", mint)
+	if err != nil {
+		return "", err
+	}
+	address, _, err := token2022.FindAssociatedTokenAddress2022(walletKey, mintKey)
+	if err != nil {
+		return "", err
+	}
+	return address.String(), nil
+}
+
+// ParseAmount converts a decimal amount such as "1.5" to raw units of a
+// mint with decimals.
+func ParseAmount(value string, decimals int) (string, error) {
+	d, err := parseDecimals(decimals)
+	if err != nil {
+		return "", err
+	}
+	amount, err := token2022.ParseAmount(value, d)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatUint(amount, 10), nil
+}
+
+// FormatAmount converts raw units of a mint with decimals to a decimal
+// amount such as "1.5".
+func FormatAmount(amount string, decimals int) (string, error) {
+	d, err := parseDecimals(decimals)
+	if err != nil {
+		return "", err
+	}
+	raw, err := parseRawAmount("amount", amount)
+	if err != nil {
+		return "", err
+	}
+	return token2022.FormatAmount(raw, d), nil
+}
+
+// Mint is a decoded Token-2022 mint. Optional authorities are empty when
+// unset, and the metadata fields are empty when the mint has no metadata
+// extension.
+type Mint struct {
+	Supply          string
+	Decimals        int
+	MintAuthority   string
+	FreezeAuthority string
+	Initialized     bool
+	Name            string
+	Symbol          string
+	URI             string
+
+	mint *token2022.Mint
+}
+
+// DecodeMint decodes the data of a mint account.
+func DecodeMint(data []byte) (*Mint, error) {
+	mint, err := token2022.DecodeMint(data)
+	if err != nil {
+		return nil, err
+	}
+	out := &Mint{
+		Supply:          strconv.FormatUint(mint.Supply, 10),
+		Decimals:        int(mint.Decimals),
+		MintAuthority:   optionalKey(mint.MintAuthority),
+		FreezeAuthority: optionalKey(mint.FreezeAuthority),
+		Initialized:     mint.IsInitialized,
+		mint:            mint,
+	}
+	metadata, ok, err := mint.TokenMetadata()
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		out.Name = metadata.Name
+		out.Symbol = metadata.Symbol
+		out.URI = metadata.URI
+	}
+	return out, nil
+}
+
+// ExtensionCount returns the number of extensions of the mint.
+func (m *Mint) ExtensionCount() int {
+	return len(m.mint.Extensions)
+}
+
+// ExtensionName returns the name of the extension at index i, such as
+// "TransferFeeConfig".
+func (m *Mint) ExtensionName(i int) (string, error) {
+	if i < 0 || i >= len(m.mint.Extensions) {
+		return "", fmt.Errorf("extension index %d out of range [0, %d)", i, len(m.mint.Extensions))
+	}
+	return m.mint.Extensions[i].Type.String(), nil
+}
+
+// TransferFee returns the fee withheld from a transfer of amount raw
+// units during epoch, "0" when the mint has no transfer fee.
+func (m *Mint) TransferFee(amount string, epoch int64) (string, error) {
+	raw, err := parseRawAmount("amount", amount)
+	if err != nil {
+		return "", err
+	}
+	if epoch < 0 {
+		return "", fmt.Errorf("negative epoch %d", epoch)
+	}
+	config, ok, err := m.mint.TransferFeeConfig()
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "0", nil
+	}
+	return strconv.FormatUint(config.EffectiveFee(uint64(epoch)).Fee(raw), 10), nil
+}
+
+// TokenAccount is a decoded Token-2022 token account. Delegate is empty
+// when unset.
+type TokenAccount struct {
+	Mint            string
+	Owner           string
+	Amount          string
+	Delegate        string
+	DelegatedAmount string
+	State           string
+	Frozen          bool
+}
+
+// DecodeTokenAccount decodes the data of a token account.
+func DecodeTokenAccount(data []byte) (*TokenAccount, error) {
+	account, err := token2022.DecodeTokenAccount(data)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenAccount{
+		Mint:            account.Mint.String(),
+		Owner:           account.Owner.String(),
+		Amount:          strconv.FormatUint(account.Amount, 10),
+		Delegate:        optionalKey(account.Delegate),
+		DelegatedAmount: strconv.FormatUint(account.DelegatedAmount, 10),
+		State:           account.State.String(),
+		Frozen:          account.IsFrozen(),
+	}, nil
+}
+
+// Transfer describes a TransferChecked from the associated token account
+// of Owner to that of Recipient. Amount and Fee are raw units; set Fee to
+// the TransferFee of the mint to use TransferCheckedWithFee. FeePayer
+// defaults to Owner.
+type Transfer struct {
+	Owner           string
+	Recipient       string
+	Mint            string
+	Amount          string
+	Decimals        int
+	Fee             string
+	FeePayer        string
+	RecentBlockhash string
+	// CreateRecipientAccount creates the associated token account of
+	// Recipient if it does not exist.
+	CreateRecipientAccount bool
+	Memo                   string
+	// AllowOwnerOffCurve accepts a Recipient off the ed25519 curve, such
+	// as a program derived address. Otherwise BuildTransfer rejects it
+	// with token2022.ErrOwnerOffCurve, as only the deriving program could
+	// move the tokens.
+	AllowOwnerOffCurve bool
+}
+
+// NewTransfer returns an empty Transfer to fill in.
+func NewTransfer() *Transfer {
+	return &Transfer{}
+}
+
+// BuildTransfer builds the unsigned transaction of t.
+func BuildTransfer(t *Transfer) (*Transaction, error) {
+	owner, err := parsePublicKey("owner", t.Owner)
+	if err != nil {
+		return nil, err
+	}
+	recipient, err := parsePublicKey("recipient", t.Recipient)
+	if err != nil {
+		return nil, err
+	}
+	mint, err := parsePublicKey("mint", t.Mint)
+	if err != nil {
+		return nil, err
+	}
+	feePayer := owner
+	if t.FeePayer != "" {
+		if feePayer, err = parsePublicKey("fee payer", t.FeePayer); err != nil {
+			return nil, err
+		}
+	}
+	blockhash, err := solana.HashFromBase58(t.RecentBlockhash)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recent blockhash %q: %w", t.RecentBlockhash, err)
+	}
+	amount, err := parseRawAmount("amount", t.Amount)
+	if err != nil {
+		return nil, err
+	}
+	decimals, err := parseDecimals(t.Decimals)
+	if err != nil {
+		return nil, err
+	}
+
+	source, _, err := token2022.FindAssociatedTokenAddress2022(owner, mint)
+	if err != nil {
+		return nil, err
+	}
+	destination, _, err := token2022.FindAssociatedTokenAddress2022Checked(recipient, mint, t.AllowOwnerOffCurve)
+	if err != nil {
+		return nil, err
+	}
+
+	var instructions []solana.Instruction
+	if t.CreateRecipientAccount {
+		create, err := token2022.NewCreate2022Instruction(feePayer, recipient, mint).
+			SetIdempotent(true).
+			SetAllowOwnerOffCurve(t.AllowOwnerOffCurve).
+			ValidateAndBuild()
+		if err != nil {
+			return nil, err
+		}
+		instructions = append(instructions, create)
+	}
+	if t.Memo != "" {
+		// MemoTransfer reads the memo from the instruction right before
+		// the transfer.
+		instructions = append(instructions, token2022.NewMemoInstruction(t.Memo, owner))
+	}
+	if t.Fee != "" {
+		fee, err := parseRawAmount("fee", t.Fee)
+		if err != nil {
+			return nil, err
+		}
+		instructions = append(instructions, token2022.NewTransferCheckedWithFee2022Instruction(amount, decimals, fee, source, mint, destination, owner).Build())
+	} else {
+		instructions = append(instructions, token2022.NewTransferChecked2022Instruction(amount, decimals, source, mint, destination, owner).Build())
+	}
+
+	tx, err := solana.NewTransaction(instructions, blockhash, solana.TransactionPayer(feePayer))
+	if err != nil {
+		return nil, fmt.Errorf("error while building transaction: %w", err)
+	}
+	return &Transaction{tx: tx}, nil
+}
+
+// Transaction is a transaction being signed by the wallet.
+type Transaction struct {
+	tx *solana.Transaction
+}
+
+// DecodeTransaction decodes a base64 transaction, such as one returned by
+// a Solana Pay transaction request.
+func DecodeTransaction(encoded string) (*Transaction, error) {
+	tx, err := token2022.DecodeTransaction(encoded)
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{tx: tx}, nil
+}
+
+// Message returns the bytes every signer signs with ed25519.
+func (t *Transaction) Message() ([]byte, error) {
+	return t.tx.Message.MarshalBinary()
+}
+
+// SignerCount returns the number of required signers.
+func (t *Transaction) SignerCount() int {
+	return int(t.tx.Message.Header.NumRequiredSignatures)
+}
+
+// Signer returns the public key of the required signer at index i.
+func (t *Transaction) Signer(i int) (string, error) {
+	signers := t.tx.Message.Signers()
+	if i < 0 || i >= len(signers) {
+		return "", fmt.Errorf("signer index %d out of range [0, %d)", i, len(signers))
+	}
+	return signers[i].String(), nil
+}
+
+// AddSignature adds the 64-byte signature of the message by signer.
+func (t *Transaction) AddSignature(signer string, signature []byte) error {
+	pubkey, err := parsePublicKey("signer", signer)
+	if err != nil {
+		return err
+	}
+	if len(signature) != solana.SignatureLength {
+		return fmt.Errorf("signature must be %d bytes, got %d", solana.SignatureLength, len(signature))
+	}
+	return token2022.ApplySignatures(t.tx, token2022.SignerSignature{
+		Signer:    pubkey,
+		Signature: solana.SignatureFromBytes(signature),
+	})
+}
+
+// IsSigned reports whether every required signer has signed.
+func (t *Transaction) IsSigned() bool {
+	return token2022.IsFullySigned(t.tx)
+}
+
+// Signature returns the signature of the fee payer, which identifies the
+// transaction once it is signed.
+func (t *Transaction) Signature() string {
+	if len(t.tx.Signatures) == 0 {
+		return ""
+	}
+	return t.tx.Signatures[0].String()
+}
+
+// Base64 encodes the transaction for sendTransaction.
+func (t *Transaction) Base64() (string, error) {
+	return token2022.EncodeTransaction(t.tx)
+}
+
+func parsePublicKey(name, value string) (solana.PublicKey, error) {
+	pubkey, err := solana.PublicKeyFromBase58(value)
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("invalid %s %q: %w", name, value, err)
+	}
+	return pubkey, nil
+}
+
+func parseRawAmount(name, value string) (uint64, error) {
+	amount, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", name, value, err)
+	}
+	return amount, nil
+}
+
+func parseDecimals(decimals int) (uint8, error) {
+	if decimals < 0 || decimals > math.MaxUint8 {
+		return 0, fmt.Errorf("decimals must be between 0 and %d, got %d", math.MaxUint8, decimals)
+	}
+	return uint8(decimals), nil
+}
+
+func optionalKey(pubkey *solana.PublicKey) string {
+	if pubkey == nil {
+		return ""
+	}
+	return pubkey.String()
+}
@@ -0,0 +1,169 @@
+package mobile
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dwmfan/token2022"
+	solana "github.com/gagliardetto/solana-go"
+)
+
+const (
+	testWallet    = "nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun"
+	testMint      = "D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn"
+	testRecipient = "9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin"
+	testBlockhash = "GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi"
+)
+
+func TestAssociatedTokenAddress(t *testing.T) {
+
+	address, err := AssociatedTokenAddress(testWallet, testMint)
+	if err != nil {
+		t.Fatalf("AssociatedTokenAddress: %v", err)
+	}
+	expected, _, _ := token2022.FindAssociatedTokenAddress2022(solana.MustPublicKeyFromBase58(testWallet), solana.MustPublicKeyFromBase58(testMint))
+	if address != expected.String() {
+		t.Errorf("Expected %s, got %s", expected, address)
+	}
+	if _, err := AssociatedTokenAddress("not a key", testMint); err == nil {
+		t.Error("Expected an error for an invalid wallet")
+	}
+}
+
+func TestAmounts(t *testing.T) {
+
+	raw, err := ParseAmount("1.5", 6)
+	if err != nil || raw != "1500000" {
+		t.Errorf("Expected 1500000, got %q, %v", raw, err)
+	}
+	ui, err := FormatAmount("18446744073709551615", 9)
+	if err != nil || ui != "18446744073.709551615" {
+		t.Errorf("Expected the maximum u64 formatted, got %q, %v", ui, err)
+	}
+	if _, err := FormatAmount("-1", 6); err == nil {
+		t.Error("Expected an error for a negative amount")
+	}
+	if _, err := ParseAmount("1", 256); err == nil {
+		t.Error("Expected an error for decimals out of range")
+	}
+}
+
+func TestDecodeAccounts(t *testing.T) {
+
+	authority := solana.MustPublicKeyFromBase58(testWallet)
+	mint, err := DecodeMint(token2022.EncodeMint(&token2022.Mint{
+		MintAuthority: &authority,
+		Supply:        1_000_000,
+		Decimals:      6,
+		IsInitialized: true,
+	}))
+	if err != nil {
+		t.Fatalf("DecodeMint: %v", err)
+	}
+	if mint.Supply != "1000000" || mint.Decimals != 6 || mint.MintAuthority != testWallet || mint.FreezeAuthority != "" {
+		t.Errorf("Unexpected mint %+v", mint)
+	}
+	if mint.ExtensionCount() != 0 {
+		t.Errorf("Expected no extensions, got %d", mint.ExtensionCount())
+	}
+	if fee, err := mint.TransferFee("100", 0); err != nil || fee != "0" {
+		t.Errorf("Expected no fee, got %q, %v", fee, err)
+	}
+
+	account, err := DecodeTokenAccount(token2022.EncodeTokenAccount(&token2022.TokenAccount{
+		Mint:   solana.MustPublicKeyFromBase58(testMint),
+		Owner:  authority,
+		Amount: 42,
+		State:  token2022.AccountStateFrozen,
+	}))
+	if err != nil {
+		t.Fatalf("DecodeTokenAccount: %v", err)
+	}
+	if account.Amount != "42" || account.Owner != testWallet || !account.Frozen || account.State != "Frozen" || account.Delegate != "" {
+		t.Errorf("Unexpected account %+v", account)
+	}
+}
+
+func TestBuildTransfer(t *testing.T) {
+
+	owner := solana.NewWallet().PrivateKey
+	transfer := NewTransfer()
+	transfer.Owner = owner.PublicKey().String()
+	transfer.Recipient = testRecipient
+	transfer.Mint = testMint
+	transfer.Amount = "1500000"
+	transfer.Decimals = 6
+	transfer.RecentBlockhash = testBlockhash
+	transfer.CreateRecipientAccount = true
+	transfer.Memo = "invoice 42"
+
+	tx, err := BuildTransfer(transfer)
+	if err != nil {
+		t.Fatalf("BuildTransfer: %v", err)
+	}
+	if tx.SignerCount() != 1 || tx.IsSigned() {
+		t.Fatalf("Expected one missing signer, got %d", tx.SignerCount())
+	}
+	if signer, _ := tx.Signer(0); signer != transfer.Owner {
+		t.Errorf("Expected the owner to sign, got %s", signer)
+	}
+
+	message, err := tx.Message()
+	if err != nil {
+		t.Fatalf("Message: %v", err)
+	}
+	sig, err := owner.Sign(message)
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if err := tx.AddSignature(transfer.Owner, sig[:5]); err == nil {
+		t.Error("Expected an error for a short signature")
+	}
+	if err := tx.AddSignature(transfer.Owner, sig[:]); err != nil {
+		t.Fatalf("AddSignature: %v", err)
+	}
+	if !tx.IsSigned() || tx.Signature() != sig.String() {
+		t.Errorf("Expected the transaction signed by %s, got %s", sig, tx.Signature())
+	}
+
+	encoded, err := tx.Base64()
+	if err != nil {
+		t.Fatalf("Base64: %v", err)
+	}
+	decoded, err := DecodeTransaction(encoded)
+	if err != nil {
+		t.Fatalf("DecodeTransaction: %v", err)
+	}
+	instructions := decoded.tx.Message.Instructions
+	if len(instructions) != 3 {
+		t.Fatalf("Expected create, memo and transfer, got %d instructions", len(instructions))
+	}
+	for i, expected := range []solana.PublicKey{solana.MemoProgramID, solana.Token2022ProgramID} {
+		programID, err := decoded.tx.ResolveProgramIDIndex(instructions[i+1].ProgramIDIndex)
+		if err != nil {
+			t.Fatalf("ResolveProgramIDIndex: %v", err)
+		}
+		if !programID.Equals(expected) {
+			t.Errorf("Expected instruction %d for %s, got %s", i+1, expected, programID)
+		}
+	}
+	if data := instructions[1].Data; string(data) != transfer.Memo {
+		t.Errorf("Expected the memo right before the transfer, got %q", data)
+	}
+
+	transfer.Decimals = -1
+	if _, err := BuildTransfer(transfer); err == nil {
+		t.Error("Expected an error for negative decimals")
+	}
+	transfer.Decimals = 6
+
+	pda, _ := AssociatedTokenAddress(testRecipient, testMint)
+	transfer.Recipient = pda
+	if _, err := BuildTransfer(transfer); !errors.Is(err, token2022.ErrOwnerOffCurve) {
+		t.Errorf("Expected ErrOwnerOffCurve for a recipient off the curve, got %v", err)
+	}
+	transfer.AllowOwnerOffCurve = true
+	if _, err := BuildTransfer(transfer); err != nil {
+		t.Errorf("Expected an allowed off-curve recipient to build, got %v", err)
+	}
+}
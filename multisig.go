@@ -0,0 +1,237 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// ErrMultisigThreshold is returned when an instruction authorized by a
+// multisig lists fewer distinct members of the multisig as signers than
+// its threshold.
+var ErrMultisigThreshold = errors.New("multisig threshold not met")
+
+// Multisig is a Token-2022 multisig account: M of the first N Signers
+// must sign for it.
+type Multisig struct {
+	M             uint8
+	N             uint8
+	IsInitialized bool
+	Signers       []solana.PublicKey
+}
+
+// IsSigner reports whether key is one of the signers of the multisig.
+func (m *Multisig) IsSigner(key solana.PublicKey) bool {
+	for _, signer := range m.Signers {
+		if signer.Equals(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// DecodeMultisig decodes the data of a multisig account.
+func DecodeMultisig(data []byte) (*Multisig, error) {
+	if len(data) != MultisigSize {
+		return nil, fmt.Errorf("%w: invalid multisig length: %d bytes", ErrInvalidAccountData, len(data))
+	}
+	multisig := &Multisig{M: data[0], N: data[1], IsInitialized: data[2] != 0}
+	if int(multisig.N) > MaxSigners {
+		return nil, fmt.Errorf("%w: multisig has %d signers, maximum is %d", ErrInvalidAccountData, multisig.N, MaxSigners)
+	}
+	for i := 0; i < int(multisig.N); i++ {
+		multisig.Signers = append(multisig.Signers, solana.PublicKeyFromBytes(data[3+32*i:35+32*i]))
+	}
+	return multisig, nil
+}
+
+// EncodeMultisig encodes a multisig account in the on-chain layout.
+func EncodeMultisig(multisig *Multisig) []byte {
+	data := make([]byte, MultisigSize)
+	data[0] = multisig.M
+	data[1] = multisig.N
+	if multisig.IsInitialized {
+		data[2] = 1
+	}
+	for i, signer := range multisig.Signers[:min(len(multisig.Signers), MaxSigners)] {
+		copy(data[3+32*i:], signer[:])
+	}
+	return data
+}
+
+// FetchMultisig fetches and decodes a multisig account.
+func FetchMultisig(ctx context.Context, client RPCClient, multisig solana.PublicKey, commitment rpc.CommitmentType) (*Multisig, error) {
+	out, err := fetchTokenProgramAccount(ctx, client, multisig, &FetchOpts{Commitment: commitment})
+	if err != nil {
+		return nil, err
+	}
+	return DecodeMultisig(out.Value.Data.GetBinary())
+}
+
+// SigningRequest is the transaction a multisig member is asked to sign,
+// encoded with EncodeTransaction. The member signs it with SignOffline and
+// returns the signatures.
+type SigningRequest struct {
+	Signer      solana.PublicKey
+	Transaction string
+}
+
+// MultisigSession coordinates the members of a multisig signing a
+// transaction whose Token-2022 instructions are authorized by the
+// multisig, built with the multisig as authority and the members that are
+// to sign as its multisig signers. The session checks that those members
+// meet the threshold, hands the transaction to each of them, collects
+// their signatures and assembles the final transaction.
+//
+// Collecting signatures can take longer than a blockhash lives; build the
+// transaction with TxBuilder.SetDurableNonce to keep it valid until it is
+// sent.
+type MultisigSession struct {
+	address  solana.PublicKey
+	multisig *Multisig
+	tx       *solana.Transaction
+}
+
+// NewMultisigSession builds the unsigned transaction of builder and starts
+// a session for the multisig at address, decoded as multisig.
+func NewMultisigSession(ctx context.Context, builder *TxBuilder, address solana.PublicKey, multisig *Multisig) (*MultisigSession, error) {
+	tx, err := builder.Build(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newMultisigSession(tx, address, multisig)
+}
+
+// ResumeMultisigSession restarts a session from a transaction exported by
+// Export, keeping the signatures it already carries.
+func ResumeMultisigSession(encoded string, address solana.PublicKey, multisig *Multisig) (*MultisigSession, error) {
+	tx, err := DecodeTransaction(encoded)
+	if err != nil {
+		return nil, err
+	}
+	return newMultisigSession(tx, address, multisig)
+}
+
+func newMultisigSession(tx *solana.Transaction, address solana.PublicKey, multisig *Multisig) (*MultisigSession, error) {
+	if multisig == nil || !multisig.IsInitialized {
+		return nil, fmt.Errorf("multisig %s is not initialized", address)
+	}
+	if err := ensureSignatureSlots(tx); err != nil {
+		return nil, err
+	}
+	s := &MultisigSession{address: address, multisig: multisig, tx: tx}
+	if err := s.checkThreshold(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// checkThreshold checks that every instruction referencing the multisig
+// lists at least M distinct members as signers.
+func (s *MultisigSession) checkThreshold() error {
+	keys := newTransactionKeys(&s.tx.Message, nil)
+	used := false
+	for i, compiled := range s.tx.Message.Instructions {
+		members := map[solana.PublicKey]bool{}
+		references := false
+		for _, index := range compiled.Accounts {
+			meta, err := keys.meta(index)
+			if err != nil {
+				return fmt.Errorf("instruction %d: %w", i, err)
+			}
+			switch {
+			case meta.PublicKey.Equals(s.address):
+				references = true
+			case meta.IsSigner && s.multisig.IsSigner(meta.PublicKey):
+				members[meta.PublicKey] = true
+			}
+		}
+		if !references {
+			continue
+		}
+		used = true
+		if len(members) < int(s.multisig.M) {
+			return fmt.Errorf("%w: instruction %d has %d of %d signers of %s", ErrMultisigThreshold, i, len(members), s.multisig.M, s.address)
+		}
+	}
+	if !used {
+		return fmt.Errorf("no instruction uses multisig %s", s.address)
+	}
+	return nil
+}
+
+// Transaction returns the transaction with the signatures collected so far.
+func (s *MultisigSession) Transaction() *solana.Transaction {
+	return s.tx
+}
+
+// Export encodes the transaction with the signatures collected so far, to
+// store the session or pass it on; ResumeMultisigSession restarts it.
+func (s *MultisigSession) Export() (string, error) {
+	return EncodeTransaction(s.tx)
+}
+
+// Requests returns a signing request for every required signer that has
+// not signed yet, including a fee payer outside the multisig.
+func (s *MultisigSession) Requests() ([]SigningRequest, error) {
+	encoded, err := s.Export()
+	if err != nil {
+		return nil, err
+	}
+	var requests []SigningRequest
+	for _, signer := range MissingSigners(s.tx) {
+		requests = append(requests, SigningRequest{Signer: signer, Transaction: encoded})
+	}
+	return requests, nil
+}
+
+// AddSignatures verifies and stores signatures returned by signers, such
+// as the output of SignOffline.
+func (s *MultisigSession) AddSignatures(sigs ...SignerSignature) error {
+	return ApplySignatures(s.tx, sigs...)
+}
+
+// AddSigned merges the signatures of a copy of the transaction signed by
+// one or more signers and encoded with EncodeTransaction.
+func (s *MultisigSession) AddSigned(encoded string) error {
+	signed, err := DecodeTransaction(encoded)
+	if err != nil {
+		return err
+	}
+	combined, err := CombineTransactions(s.tx, signed)
+	if err != nil {
+		return err
+	}
+	s.tx = combined
+	return nil
+}
+
+// Missing returns the required signers that have not signed yet.
+func (s *MultisigSession) Missing() []solana.PublicKey {
+	return MissingSigners(s.tx)
+}
+
+// Assemble returns the transaction once every required signer has signed.
+func (s *MultisigSession) Assemble() (*solana.Transaction, error) {
+	if missing := s.Missing(); len(missing) > 0 {
+		return nil, fmt.Errorf("missing signatures for %v", missing)
+	}
+	return s.tx, nil
+}
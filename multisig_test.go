@@ -0,0 +1,113 @@
+package token2022
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestMultisigSession(t *testing.T) {
+	var (
+		mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		address     = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		payer       = solana.NewWallet().PrivateKey
+		members     = []solana.PrivateKey{solana.NewWallet().PrivateKey, solana.NewWallet().PrivateKey, solana.NewWallet().PrivateKey}
+		ctx         = context.Background()
+	)
+	client := newMockRPC()
+	client.setAccount(address, solana.Token2022ProgramID, EncodeMultisig(&Multisig{
+		M: 2, N: 3, IsInitialized: true,
+		Signers: []solana.PublicKey{members[0].PublicKey(), members[1].PublicKey(), members[2].PublicKey()},
+	}))
+	multisig, err := FetchMultisig(ctx, client, address, rpc.CommitmentConfirmed)
+	if err != nil {
+		t.Fatalf("FetchMultisig: %v", err)
+	}
+	if multisig.M != 2 || len(multisig.Signers) != 3 || !multisig.IsSigner(members[2].PublicKey()) {
+		t.Fatalf("Unexpected multisig %+v", multisig)
+	}
+
+	builder := func(signers ...solana.PublicKey) *TxBuilder {
+		return NewTxBuilder(client).SetFeePayer(payer.PublicKey()).
+			AddInstruction(NewTransferChecked2022Instruction(10, 6, source, mint, destination, address, signers...).Build())
+	}
+	if _, err := NewMultisigSession(ctx, builder(members[0].PublicKey()), address, multisig); !errors.Is(err, ErrMultisigThreshold) {
+		t.Fatalf("Expected ErrMultisigThreshold with one signer, got %v", err)
+	}
+	if _, err := NewMultisigSession(ctx, builder(members[0].PublicKey(), members[0].PublicKey()), address, multisig); !errors.Is(err, ErrMultisigThreshold) {
+		t.Fatalf("Expected a repeated signer not to count twice, got %v", err)
+	}
+
+	session, err := NewMultisigSession(ctx, builder(members[0].PublicKey(), members[2].PublicKey()), address, multisig)
+	if err != nil {
+		t.Fatalf("NewMultisigSession: %v", err)
+	}
+	requests, err := session.Requests()
+	if err != nil || len(requests) != 3 {
+		t.Fatalf("Expected requests for the payer and two members, got %d, %v", len(requests), err)
+	}
+
+	// The first member returns signatures, the second a signed copy.
+	sigs, err := SignOffline(ctx, requests[1].Transaction, NewPrivateKeySigner(members[0]))
+	if err != nil {
+		t.Fatalf("SignOffline: %v", err)
+	}
+	if err := session.AddSignatures(sigs...); err != nil {
+		t.Fatalf("AddSignatures: %v", err)
+	}
+	signed, err := DecodeTransaction(requests[2].Transaction)
+	if err != nil {
+		t.Fatalf("DecodeTransaction: %v", err)
+	}
+	if err := PartialSign(signed, members[2]); err != nil {
+		t.Fatalf("PartialSign: %v", err)
+	}
+	encoded, err := EncodeTransaction(signed)
+	if err != nil {
+		t.Fatalf("EncodeTransaction: %v", err)
+	}
+	if err := session.AddSigned(encoded); err != nil {
+		t.Fatalf("AddSigned: %v", err)
+	}
+	if _, err := session.Assemble(); err == nil {
+		t.Fatalf("Expected Assemble to fail without the fee payer")
+	}
+
+	exported, err := session.Export()
+	if err != nil {
+		t.Fatalf("Export: %v", err)
+	}
+	resumed, err := ResumeMultisigSession(exported, address, multisig)
+	if err != nil {
+		t.Fatalf("ResumeMultisigSession: %v", err)
+	}
+	if missing := resumed.Missing(); len(missing) != 1 || !missing[0].Equals(payer.PublicKey()) {
+		t.Fatalf("Expected only the payer to be missing, got %v", missing)
+	}
+	if err := PartialSign(resumed.Transaction(), payer); err != nil {
+		t.Fatalf("PartialSign: %v", err)
+	}
+	tx, err := resumed.Assemble()
+	if err != nil {
+		t.Fatalf("Assemble: %v", err)
+	}
+	if err := tx.VerifySignatures(); err != nil {
+		t.Errorf("Expected valid signatures, got %v", err)
+	}
+}
+
+func TestDecodeMultisig(t *testing.T) {
+	if _, err := DecodeMultisig(make([]byte, MintSize)); !errors.Is(err, ErrInvalidAccountData) {
+		t.Errorf("Expected ErrInvalidAccountData, got %v", err)
+	}
+	data := make([]byte, MultisigSize)
+	data[1] = MaxSigners + 1
+	if _, err := DecodeMultisig(data); !errors.Is(err, ErrInvalidAccountData) {
+		t.Errorf("Expected too many signers to fail, got %v", err)
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// ErrTransactionTooLarge is returned by MultiTransfer when the payments do
+// not fit in one transaction of MaxTransactionSize bytes.
+var ErrTransactionTooLarge = errors.New("transaction too large")
+
+// MultiTransferClient is the set of RPC calls used by MultiTransfer.
+// *rpc.Client satisfies it.
+type MultiTransferClient interface {
+	TransferClient
+	MultipleAccountsClient
+}
+
+var _ MultiTransferClient = (*rpc.Client)(nil)
+
+// MultiTransfer pays several recipients from one source token account in a
+// single transaction, as for a payroll run: either every payment lands or
+// none does. It creates the associated token accounts that do not exist
+// yet, resolves the accounts of the mint's transfer hook, and sums up the
+// fees, rent and transfer fees of the transaction before it is signed.
+// Address lookup tables fit more recipients in the transaction.
+//
+// Unlike a Distributor, which spreads any number of payments over as many
+// transactions as needed, MultiTransfer fails with ErrTransactionTooLarge
+// when the payments do not fit.
+type MultiTransfer struct {
+	client     MultiTransferClient
+	mint       solana.PublicKey
+	source     solana.PublicKey
+	authority  solana.PublicKey
+	signers    []solana.PublicKey
+	feePayer   solana.PublicKey
+	commitment rpc.CommitmentType
+	tables     map[solana.PublicKey]solana.PublicKeySlice
+	recipients []Recipient
+	// allowOwnerOffCurve pays recipients off the ed25519 curve; see
+	// SetAllowOwnerOffCurve.
+	allowOwnerOffCurve bool
+}
+
+// NewMultiTransfer creates a transfer of mint from source, whose owner or
+// delegate is authority, or the multisig signed by multisigSigners. The
+// authority also pays the fees and rent, and accounts are read at the
+// confirmed commitment.
+func NewMultiTransfer(client MultiTransferClient, mint, source, authority solana.PublicKey, multisigSigners ...solana.PublicKey) *MultiTransfer {
+	return &MultiTransfer{
+		client:     client,
+		mint:       mint,
+		source:     source,
+		authority:  authority,
+		signers:    multisigSigners,
+		feePayer:   authority,
+		commitment: rpc.CommitmentConfirmed,
+	}
+}
+
+// SetFeePayer pays fees and the rent of created accounts from feePayer
+// instead of the authority.
+func (m *MultiTransfer) SetFeePayer(feePayer solana.PublicKey) *MultiTransfer {
+	m.feePayer = feePayer
+	return m
+}
+
+// SetCommitment sets the commitment at which the mint and the recipient
+// accounts are read.
+func (m *MultiTransfer) SetCommitment(commitment rpc.CommitmentType) *MultiTransfer {
+	m.commitment = commitment
+	return m
+}
+
+// SetAddressTables compresses the transaction with address lookup tables,
+// keyed by table address, so more recipients fit in it.
+func (m *MultiTransfer) SetAddressTables(tables map[solana.PublicKey]solana.PublicKeySlice) *MultiTransfer {
+	m.tables = tables
+	return m
+}
+
+// SetAllowOwnerOffCurve pays recipients whose wallet is off the ed25519
+// curve, such as program derived addresses, which Validate otherwise
+// rejects with ErrOwnerOffCurve.
+func (m *MultiTransfer) SetAllowOwnerOffCurve(allow bool) *MultiTransfer {
+	m.allowOwnerOffCurve = allow
+	return m
+}
+
+// AddRecipients adds payments, in raw units of the mint. A wallet listed
+// twice is paid twice.
+func (m *MultiTransfer) AddRecipients(recipients ...Recipient) *MultiTransfer {
+	m.recipients = append(m.recipients, recipients...)
+	return m
+}
+
+// Validate checks that the transfer is configured and has recipients.
+func (m *MultiTransfer) Validate() error {
+	if m.client == nil {
+		return errNotSet("RPC client")
+	}
+	if m.mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	if m.source.IsZero() {
+		return errNotSet("Source")
+	}
+	if m.authority.IsZero() {
+		return errNotSet("Authority")
+	}
+	if m.feePayer.IsZero() {
+		return errNotSet("FeePayer")
+	}
+	if err := validateMultisigSigners(m.signers); err != nil {
+		return err
+	}
+	if len(m.recipients) == 0 {
+		return errInvalidField("Recipients", "must not be empty")
+	}
+	for i, recipient := range m.recipients {
+		if recipient.Wallet.IsZero() {
+			return fmt.Errorf("recipient %d: %w", i, errNotSet("Wallet"))
+		}
+		if err := ValidateOwner(recipient.Wallet, m.allowOwnerOffCurve); err != nil {
+			return fmt.Errorf("recipient %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// MultiTransferPlan is the transaction of a MultiTransfer and its summary.
+type MultiTransferPlan struct {
+	// Instructions create the missing accounts, then pay the recipients
+	// in order.
+	Instructions []solana.Instruction
+	// Accounts are the associated token accounts of the recipients, in
+	// order.
+	Accounts []solana.PublicKey
+	// Created are the accounts the transaction creates.
+	Created  []solana.PublicKey
+	Decimals uint8
+	// Total is the sum of the payments, transfer fees included.
+	Total uint64
+	// Size is the serialized size of the signed transaction, at most
+	// MaxTransactionSize.
+	Size int
+	// Cost sums the signature fees, the rent of Created and the transfer
+	// fees withheld from the payments.
+	Cost *CostEstimate
+	// Transaction is the unsigned transaction, set by Build.
+	Transaction *solana.Transaction
+}
+
+// Plan validates the transfer and returns its instructions and summary
+// without fetching a blockhash.
+func (m *MultiTransfer) Plan(ctx context.Context) (*MultiTransferPlan, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	mint, err := FetchMint(ctx, m.client, m.mint, m.commitment)
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching mint: %w", err)
+	}
+	hook, _, err := mint.TransferHookConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &MultiTransferPlan{Decimals: mint.Decimals, Accounts: make([]solana.PublicKey, len(m.recipients))}
+	for i, recipient := range m.recipients {
+		account, _, err := FindAssociatedTokenAddress2022Checked(recipient.Wallet, m.mint, m.allowOwnerOffCurve)
+		if err != nil {
+			return nil, fmt.Errorf("recipient %d: %w", i, err)
+		}
+		plan.Accounts[i] = account
+		if plan.Total, err = CheckedAdd(plan.Total, recipient.Amount); err != nil {
+			return nil, fmt.Errorf("error while adding up the amounts: %w", err)
+		}
+	}
+	existing, err := NewAccountFetcher(m.client).SetFetchOpts(FetchOpts{Commitment: m.commitment}).Fetch(ctx, plan.Accounts)
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching recipient accounts: %w", err)
+	}
+
+	created := map[solana.PublicKey]bool{}
+	var transfers []solana.Instruction
+	for i, recipient := range m.recipients {
+		account := plan.Accounts[i]
+		if existing[i] == nil && !created[account] {
+			create, err := NewCreate2022Instruction(m.feePayer, recipient.Wallet, m.mint).
+				SetIdempotent(true).
+				SetAllowOwnerOffCurve(m.allowOwnerOffCurve).
+				ValidateAndBuild()
+			if err != nil {
+				return nil, fmt.Errorf("recipient %d: %w", i, err)
+			}
+			plan.Instructions = append(plan.Instructions, create)
+			plan.Created = append(plan.Created, account)
+			created[account] = true
+		}
+		transfer := NewTransferChecked2022Instruction(recipient.Amount, mint.Decimals, m.source, m.mint, account, m.authority, m.signers...)
+		if hook != nil && hook.ProgramID != nil {
+			accounts, err := ResolveTransferHookAccounts(ctx, m.client, m.commitment, *hook.ProgramID, transfer)
+			if err != nil {
+				return nil, fmt.Errorf("recipient %d: %w", i, err)
+			}
+			transfer.SetAdditionalAccounts(accounts...)
+		}
+		built, err := transfer.ValidateAndBuild()
+		if err != nil {
+			return nil, fmt.Errorf("recipient %d: %w", i, err)
+		}
+		transfers = append(transfers, built)
+	}
+	plan.Instructions = append(plan.Instructions, transfers...)
+
+	if plan.Size, err = transactionSize(m.feePayer, plan.Instructions, m.tables); err != nil {
+		return nil, err
+	}
+	if plan.Size > MaxTransactionSize {
+		return nil, fmt.Errorf("%w: %d recipients take %d bytes, more than %d", ErrTransactionTooLarge, len(m.recipients), plan.Size, MaxTransactionSize)
+	}
+	if plan.Cost, err = EstimateCost(ctx, m.client, m.commitment, m.feePayer, plan.Instructions); err != nil {
+		return nil, fmt.Errorf("error while estimating cost: %w", err)
+	}
+	return plan, nil
+}
+
+// Build plans the transfer and sets the unsigned transaction of the plan,
+// to be signed by the fee payer and the authority or its multisig
+// signers.
+func (m *MultiTransfer) Build(ctx context.Context) (*MultiTransferPlan, error) {
+	plan, err := m.Plan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	plan.Transaction, err = NewTxBuilder(m.client).
+		SetFeePayer(m.feePayer).
+		SetAddressTables(m.tables).
+		AddInstruction(plan.Instructions...).
+		Build(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
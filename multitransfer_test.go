@@ -0,0 +1,137 @@
+package token2022
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+func TestMultiTransfer(t *testing.T) {
+	var (
+		key       = solana.NewWallet().PrivateKey
+		authority = key.PublicKey()
+		mint      = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source    = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		client    = newMockRPC()
+		ctx       = context.Background()
+	)
+	client.setAccount(mint, solana.Token2022ProgramID, encodeMint(nil, 1_000_000, 6))
+	var recipients []Recipient
+	for i := 0; i < 4; i++ {
+		wallet := solana.NewWallet().PublicKey()
+		recipients = append(recipients, Recipient{Wallet: wallet, Amount: uint64(100 * (i + 1))})
+		if i%2 == 0 {
+			account, _, _ := FindAssociatedTokenAddress2022(wallet, mint)
+			client.setAccount(account, solana.Token2022ProgramID, encodeTokenAccount(mint, wallet, 0))
+		}
+	}
+	// The second payment to the same new account creates it once.
+	recipients = append(recipients, Recipient{Wallet: recipients[1].Wallet, Amount: 5})
+
+	plan, err := NewMultiTransfer(client, mint, source, authority).AddRecipients(recipients...).Build(ctx)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if len(plan.Created) != 2 || plan.Created[0] != plan.Accounts[1] || plan.Created[1] != plan.Accounts[3] {
+		t.Errorf("Expected the accounts of recipients 1 and 3 to be created, got %v", plan.Created)
+	}
+	if plan.Total != 1_005 || plan.Decimals != 6 {
+		t.Errorf("Expected a total of 1005 with 6 decimals, got %d with %d", plan.Total, plan.Decimals)
+	}
+	if len(plan.Instructions) != 7 {
+		t.Fatalf("Expected 2 creations and 5 transfers, got %d instructions", len(plan.Instructions))
+	}
+	for i, inst := range plan.Instructions[2:] {
+		data, _ := inst.Data()
+		decoded, err := DecodeInstruction(inst.Accounts(), data)
+		if err != nil {
+			t.Fatalf("DecodeInstruction: %v", err)
+		}
+		transfer, ok := decoded.(*TransferChecked2022)
+		if !ok {
+			t.Fatalf("Expected a TransferChecked2022, got %T", decoded)
+		}
+		if transfer.Destination != plan.Accounts[i] || transfer.Amount != recipients[i].Amount || transfer.Decimals != 6 {
+			t.Errorf("Expected %d to %s, got %d to %s", recipients[i].Amount, plan.Accounts[i], transfer.Amount, transfer.Destination)
+		}
+	}
+	if plan.Cost.Signatures != 1 || plan.Cost.SignatureFee != LamportsPerSignature {
+		t.Errorf("Expected one signature, got %d for %d lamports", plan.Cost.Signatures, plan.Cost.SignatureFee)
+	}
+	// Associated token accounts of Token-2022 have the ImmutableOwner
+	// extension.
+	space, _ := AccountSpace(ExtensionImmutableOwner)
+	if want := 2 * RentExemptLamports(space); plan.Cost.Rent != want {
+		t.Errorf("Expected rent %d, got %d", want, plan.Cost.Rent)
+	}
+	if err := SignTransaction(ctx, plan.Transaction, NewPrivateKeySigner(key)); err != nil {
+		t.Fatalf("SignTransaction: %v", err)
+	}
+	data, err := plan.Transaction.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary: %v", err)
+	}
+	if len(data) != plan.Size {
+		t.Errorf("Expected the transaction to take %d bytes, got %d", plan.Size, len(data))
+	}
+}
+
+func TestMultiTransferAddressTables(t *testing.T) {
+	var (
+		authority = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint      = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source    = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		table     = solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR")
+		client    = newMockRPC()
+	)
+	client.setAccount(mint, solana.Token2022ProgramID, encodeMint(nil, 1_000_000, 6))
+	var (
+		recipients []Recipient
+		accounts   = solana.PublicKeySlice{mint, source, solana.Token2022ProgramID}
+	)
+	for i := 0; i < 40; i++ {
+		wallet := solana.NewWallet().PublicKey()
+		recipients = append(recipients, Recipient{Wallet: wallet, Amount: 1})
+		account, _, _ := FindAssociatedTokenAddress2022(wallet, mint)
+		client.setAccount(account, solana.Token2022ProgramID, encodeTokenAccount(mint, wallet, 0))
+		accounts = append(accounts, account)
+	}
+
+	transfer := NewMultiTransfer(client, mint, source, authority).AddRecipients(recipients...)
+	if _, err := transfer.Plan(context.Background()); !errors.Is(err, ErrTransactionTooLarge) {
+		t.Fatalf("Expected ErrTransactionTooLarge, got %v", err)
+	}
+	plan, err := transfer.SetAddressTables(map[solana.PublicKey]solana.PublicKeySlice{table: accounts}).Plan(context.Background())
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+	if plan.Size > MaxTransactionSize || len(plan.Instructions) != 40 {
+		t.Errorf("Expected 40 transfers in %d bytes, got %d in %d", MaxTransactionSize, len(plan.Instructions), plan.Size)
+	}
+}
+
+func TestMultiTransferValidate(t *testing.T) {
+	var (
+		mint   = solana.NewWallet().PublicKey()
+		source = solana.NewWallet().PublicKey()
+		owner  = solana.NewWallet().PublicKey()
+	)
+	if err := NewMultiTransfer(newMockRPC(), mint, source, owner).Validate(); err == nil {
+		t.Errorf("Expected an error without recipients")
+	}
+	err := NewMultiTransfer(newMockRPC(), mint, source, owner).AddRecipients(Recipient{Amount: 1}).Validate()
+	if !errors.Is(err, ErrNotSet) {
+		t.Errorf("Expected ErrNotSet for a recipient without wallet, got %v", err)
+	}
+
+	pda, _, _ := FindAssociatedTokenAddress2022(owner, mint)
+	transfer := NewMultiTransfer(newMockRPC(), mint, source, owner).AddRecipients(Recipient{Wallet: pda, Amount: 1})
+	if err := transfer.Validate(); !errors.Is(err, ErrOwnerOffCurve) {
+		t.Errorf("Expected ErrOwnerOffCurve for a recipient off the curve, got %v", err)
+	}
+	if err := transfer.SetAllowOwnerOffCurve(true).Validate(); err != nil {
+		t.Errorf("Expected an allowed off-curve recipient to validate, got %v", err)
+	}
+}
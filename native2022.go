@@ -0,0 +1,281 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	solana "github.com/gagliardetto/solana-go"
+	format "github.com/gagliardetto/solana-go/text/format"
+	treeout "github.com/gagliardetto/treeout"
+)
+
+// SyncNative2022 updates the token amount of a native (wrapped SOL) account to
+// match its lamports.
+type SyncNative2022 struct {
+	Account solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Account
+	// ··········· The native token account
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewSyncNative2022InstructionBuilder creates a new `SyncNative2022` instruction builder.
+func NewSyncNative2022InstructionBuilder() *SyncNative2022 {
+	nd := &SyncNative2022{}
+	return nd
+}
+
+func (inst *SyncNative2022) SetAccount(account solana.PublicKey) *SyncNative2022 {
+	inst.Account = account
+	return inst
+}
+
+func (inst SyncNative2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Account,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+	}
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst SyncNative2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *SyncNative2022) Validate() error {
+	if inst.Account.IsZero() {
+		return errNotSet("Account")
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+func (inst *SyncNative2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("SyncNative2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=0]").ParentFunc(func(paramsBranch treeout.Branches) {})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("account", inst.AccountMetaSlice.Get(0)))
+					})
+				})
+		})
+}
+
+func (inst SyncNative2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	return nil
+}
+
+func (inst *SyncNative2022) UnmarshalWithDecoder(decoder *bin.Decoder) error {
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst SyncNative2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *SyncNative2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("SyncNative2022", accounts, 1); err != nil {
+		return err
+	}
+	inst.Account = accounts[0].PublicKey
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst SyncNative2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *SyncNative2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewSyncNative2022Instruction creates a new `SyncNative2022` instruction.
+func NewSyncNative2022Instruction(
+	account solana.PublicKey,
+) *SyncNative2022 {
+	return NewSyncNative2022InstructionBuilder().
+		SetAccount(account)
+}
+
+// CreateNativeMint2022 creates the native mint of the Token-2022 program.
+type CreateNativeMint2022 struct {
+	Payer      solana.PublicKey `bin:"-" borsh_skip:"true"`
+	NativeMint solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE, SIGNER] Payer
+	// ··········· Funding account
+	//
+	// [1] = [WRITE] NativeMint
+	// ··········· The native mint address
+	//
+	// [2] = [] SystemProgram
+	// ··········· System program ID
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewCreateNativeMint2022InstructionBuilder creates a new `CreateNativeMint2022` instruction builder.
+func NewCreateNativeMint2022InstructionBuilder() *CreateNativeMint2022 {
+	nd := &CreateNativeMint2022{}
+	return nd
+}
+
+func (inst *CreateNativeMint2022) SetPayer(payer solana.PublicKey) *CreateNativeMint2022 {
+	inst.Payer = payer
+	return inst
+}
+
+func (inst *CreateNativeMint2022) SetNativeMint(nativeMint solana.PublicKey) *CreateNativeMint2022 {
+	inst.NativeMint = nativeMint
+	return inst
+}
+
+func (inst CreateNativeMint2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Payer,
+			IsSigner:   true,
+			IsWritable: true,
+		},
+		{
+			PublicKey:  inst.NativeMint,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+		{
+			PublicKey:  solana.SystemProgramID,
+			IsSigner:   false,
+			IsWritable: false,
+		},
+	}
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst CreateNativeMint2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *CreateNativeMint2022) Validate() error {
+	if inst.Payer.IsZero() {
+		return errNotSet("Payer")
+	}
+	if inst.NativeMint.IsZero() {
+		return errNotSet("NativeMint")
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+func (inst *CreateNativeMint2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("CreateNativeMint2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=0]").ParentFunc(func(paramsBranch treeout.Branches) {})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("        payer", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(format.Meta("   nativeMint", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(format.Meta("systemProgram", inst.AccountMetaSlice.Get(2)))
+					})
+				})
+		})
+}
+
+func (inst CreateNativeMint2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	return nil
+}
+
+func (inst *CreateNativeMint2022) UnmarshalWithDecoder(decoder *bin.Decoder) error {
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst CreateNativeMint2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *CreateNativeMint2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("CreateNativeMint2022", accounts, 3); err != nil {
+		return err
+	}
+	inst.Payer = accounts[0].PublicKey
+	inst.NativeMint = accounts[1].PublicKey
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst CreateNativeMint2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *CreateNativeMint2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewCreateNativeMint2022Instruction creates a new `CreateNativeMint2022` instruction.
+func NewCreateNativeMint2022Instruction(
+	payer solana.PublicKey,
+	nativeMint solana.PublicKey,
+) *CreateNativeMint2022 {
+	return NewCreateNativeMint2022InstructionBuilder().
+		SetPayer(payer).
+		SetNativeMint(nativeMint)
+}
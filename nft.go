@@ -0,0 +1,105 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"fmt"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// NFTConfig describes a Token-2022 NFT for CreateNFT2022.
+type NFTConfig struct {
+	// Mint is the address of the new mint; it signs the transaction.
+	Mint solana.PublicKey
+	// Owner receives the token in its associated token account.
+	Owner solana.PublicKey
+	// Authority is the mint authority; it signs the transaction.
+	Authority solana.PublicKey
+	// Payer funds the new accounts and signs the transaction. It defaults
+	// to Authority.
+	Payer *solana.PublicKey
+	// UpdateAuthority may update the metadata and its pointer. It defaults
+	// to Authority.
+	UpdateAuthority *solana.PublicKey
+	// FreezeAuthority is nil for an NFT that cannot be frozen.
+	FreezeAuthority *solana.PublicKey
+
+	Name   string
+	Symbol string
+	URI    string
+
+	// Group, when set, makes the NFT a member of the collection whose
+	// TokenGroup is stored in that account. GroupUpdateAuthority signs
+	// the transaction; it defaults to Authority.
+	Group                *solana.PublicKey
+	GroupUpdateAuthority *solana.PublicKey
+
+	// RevokeMintAuthority removes the mint authority after minting, so
+	// that the supply stays at one.
+	RevokeMintAuthority bool
+}
+
+// Profile returns the MintProfile of the NFT's mint.
+func (c *NFTConfig) Profile() *MintProfile {
+	profile := &MintProfile{
+		Mint:            c.Mint,
+		Authority:       c.Authority,
+		Payer:           c.Payer,
+		FreezeAuthority: c.FreezeAuthority,
+		Metadata:        &MetadataProfile{Name: c.Name, Symbol: c.Symbol, URI: c.URI, UpdateAuthority: c.UpdateAuthority},
+	}
+	if c.Group != nil {
+		profile.GroupMember = &GroupMemberProfile{Group: *c.Group, GroupUpdateAuthority: c.GroupUpdateAuthority}
+	}
+	return profile
+}
+
+// CreateNFT2022 returns the instructions creating an NFT: a mint with no
+// decimals whose MetadataPointer points to its own TokenMetadata, and,
+// with a Group, whose GroupMemberPointer points to its own
+// TokenGroupMember; then the owner's associated token account, holding
+// the single token. The mint is funded upfront for the size it grows to.
+//
+// The instructions fit in one transaction, signed by Mint, Authority,
+// Payer and GroupUpdateAuthority.
+func CreateNFT2022(config NFTConfig) ([]solana.Instruction, error) {
+	if config.Owner.IsZero() {
+		return nil, errNotSet("Owner")
+	}
+	plan, err := config.Profile().Plan()
+	if err != nil {
+		return nil, err
+	}
+	instructions := plan.Instructions
+
+	account, _, err := FindAssociatedTokenAddress2022(config.Owner, config.Mint)
+	if err != nil {
+		return nil, fmt.Errorf("error while deriving the owner's account: %w", err)
+	}
+	instructions = append(instructions,
+		NewCreate2022Instruction(keyOr(config.Payer, config.Authority), config.Owner, config.Mint).Build(),
+		NewMintToChecked2022Instruction(1, 0, config.Mint, account, config.Authority).Build())
+	if config.RevokeMintAuthority {
+		revoke, err := NewSetAuthority2022Instruction(AuthorityMintTokens, nil, config.Mint, config.Authority).
+			ConfirmIrreversible().
+			ValidateAndBuild()
+		if err != nil {
+			return nil, err
+		}
+		instructions = append(instructions, revoke)
+	}
+	return instructions, nil
+}
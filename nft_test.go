@@ -0,0 +1,118 @@
+package token2022
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// instructionName names a Token-2022 instruction like builderName, and
+// the system, associated token account and interface instructions
+// CreateNFT2022 adds.
+func instructionName(t *testing.T, inst solana.Instruction) string {
+	data, _ := inst.Data()
+	switch {
+	case inst.ProgramID().Equals(solana.SystemProgramID):
+		return "CreateAccount"
+	case inst.ProgramID().Equals(solana.SPLAssociatedTokenAccountProgramID):
+		return "Create"
+	case bytes.HasPrefix(data, initializeTokenMetadataDiscriminator):
+		return "InitializeTokenMetadata"
+	case bytes.HasPrefix(data, initializeGroupMemberDiscriminator):
+		return "InitializeGroupMember"
+	}
+	typed, err := DecodeInstruction(inst.Accounts(), data)
+	if err != nil {
+		t.Fatalf("DecodeInstruction: %v", err)
+	}
+	return builderName(typed)
+}
+
+func TestCreateNFT2022(t *testing.T) {
+	mint := solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	authority := solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	owner := solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+	group := solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR")
+	config := NFTConfig{
+		Mint:      mint,
+		Owner:     owner,
+		Authority: authority,
+		Name:      "Token #1",
+		Symbol:    "TKN",
+		URI:       "https://example.com/1.json",
+	}
+
+	t.Run("plain", func(t *testing.T) {
+		instructions, err := CreateNFT2022(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []string{"CreateAccount", "InitializeMetadataPointer", "InitializeMint", "InitializeTokenMetadata", "Create", "MintToChecked"}
+		if len(instructions) != len(expected) {
+			t.Fatalf("Expected %d instructions, got %d", len(expected), len(instructions))
+		}
+		for i, inst := range instructions {
+			if name := instructionName(t, inst); name != expected[i] {
+				t.Errorf("Expected instruction %d to be %s, got %s", i, expected[i], name)
+			}
+		}
+
+		data, _ := instructions[0].Data()
+		lamports, space := binary.LittleEndian.Uint64(data[4:12]), binary.LittleEndian.Uint64(data[12:20])
+		wantSpace, _ := MintSpace(ExtensionMetadataPointer)
+		if space != uint64(wantSpace) {
+			t.Errorf("Expected the mint created with %d bytes, got %d", wantSpace, space)
+		}
+		if want := RentExemptLamports(wantSpace + 4 + TokenMetadataLength(&TokenMetadata{Name: config.Name, Symbol: config.Symbol, URI: config.URI})); lamports != want {
+			t.Errorf("Expected %d lamports for the grown mint, got %d", want, lamports)
+		}
+
+		data, _ = instructions[5].Data()
+		typed, _ := DecodeInstruction(instructions[5].Accounts(), data)
+		mintTo := typed.(*MintToChecked2022)
+		account, _, _ := FindAssociatedTokenAddress2022(owner, mint)
+		if mintTo.Amount != 1 || mintTo.Decimals != 0 || !mintTo.Destination.Equals(account) {
+			t.Errorf("Expected 1 token with 0 decimals to %s, got %d with %d to %s", account, mintTo.Amount, mintTo.Decimals, mintTo.Destination)
+		}
+	})
+
+	t.Run("group member with revoked authority", func(t *testing.T) {
+		config := config
+		config.Group = &group
+		config.RevokeMintAuthority = true
+		instructions, err := CreateNFT2022(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := []string{"CreateAccount", "InitializeMetadataPointer", "InitializeGroupMemberPointer", "InitializeMint", "InitializeTokenMetadata", "InitializeGroupMember", "Create", "MintToChecked", "SetAuthority"}
+		if len(instructions) != len(expected) {
+			t.Fatalf("Expected %d instructions, got %d", len(expected), len(instructions))
+		}
+		for i, inst := range instructions {
+			if name := instructionName(t, inst); name != expected[i] {
+				t.Errorf("Expected instruction %d to be %s, got %s", i, expected[i], name)
+			}
+		}
+		if accounts := instructions[5].Accounts(); !accounts[3].PublicKey.Equals(group) || !accounts[3].IsWritable {
+			t.Errorf("Expected the group %s to be writable, got %s", group, accounts[3].PublicKey)
+		}
+	})
+
+	for _, field := range []string{"Mint", "Owner", "Authority"} {
+		config := config
+		switch field {
+		case "Mint":
+			config.Mint = solana.PublicKey{}
+		case "Owner":
+			config.Owner = solana.PublicKey{}
+		case "Authority":
+			config.Authority = solana.PublicKey{}
+		}
+		if _, err := CreateNFT2022(config); !errors.Is(err, ErrNotSet) {
+			t.Errorf("Expected ErrNotSet without %s, got %v", field, err)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	solana "github.com/gagliardetto/solana-go"
+	system "github.com/gagliardetto/solana-go/programs/system"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// NonceAccountSize is the size in bytes of a system program nonce account.
+const NonceAccountSize = 80
+
+// Nonce account states as stored by the system program.
+const (
+	NonceStateUninitialized uint32 = 0
+	NonceStateInitialized   uint32 = 1
+)
+
+// DecodeNonceAccount decodes the data of an initialized durable nonce account.
+func DecodeNonceAccount(data []byte) (*system.NonceAccount, error) {
+	if len(data) < NonceAccountSize {
+		return nil, fmt.Errorf("%w: nonce account data too short: %d bytes", ErrInvalidAccountData, len(data))
+	}
+	nonce := new(system.NonceAccount)
+	if err := bin.NewBinDecoder(data).Decode(nonce); err != nil {
+		return nil, fmt.Errorf("error while decoding nonce account: %w", err)
+	}
+	if nonce.State != NonceStateInitialized {
+		return nil, fmt.Errorf("%w: nonce account is not initialized", ErrInvalidAccountData)
+	}
+	return nonce, nil
+}
+
+// FetchNonceAccount fetches and decodes a durable nonce account.
+func FetchNonceAccount(
+	ctx context.Context,
+	client RPCClient,
+	nonceAccount solana.PublicKey,
+	commitment rpc.CommitmentType,
+) (*system.NonceAccount, error) {
+	return FetchNonceAccountWithOpts(ctx, client, nonceAccount, &FetchOpts{Commitment: commitment})
+}
+
+// FetchNonceAccountWithOpts fetches and decodes a durable nonce account.
+func FetchNonceAccountWithOpts(
+	ctx context.Context,
+	client RPCClient,
+	nonceAccount solana.PublicKey,
+	opts *FetchOpts,
+) (*system.NonceAccount, error) {
+	out, err := client.GetAccountInfoWithOpts(ctx, nonceAccount, opts.accountInfoOpts())
+	if errors.Is(err, rpc.ErrNotFound) {
+		return nil, fmt.Errorf("%w: nonce account %s: %w", ErrAccountNotFound, nonceAccount, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching nonce account %s: %w", nonceAccount, err)
+	}
+	if out == nil || out.Value == nil {
+		return nil, fmt.Errorf("%w: nonce account %s", ErrAccountNotFound, nonceAccount)
+	}
+	if !out.Value.Owner.Equals(solana.SystemProgramID) {
+		return nil, fmt.Errorf("%w: nonce account %s is not owned by the system program", ErrInvalidAccountOwner, nonceAccount)
+	}
+	return DecodeNonceAccount(out.Value.Data.GetBinary())
+}
+
+// NewCreateNonceAccountInstructions returns the instructions that allocate
+// a new nonce account and initialize it with the given authority.
+// lamports must cover rent exemption for NonceAccountSize bytes, and
+// nonceAccount must sign the transaction.
+func NewCreateNonceAccountInstructions(
+	payer solana.PublicKey,
+	nonceAccount solana.PublicKey,
+	authority solana.PublicKey,
+	lamports uint64,
+) []solana.Instruction {
+	return []solana.Instruction{
+		system.NewCreateAccountInstruction(
+			lamports,
+			NonceAccountSize,
+			solana.SystemProgramID,
+			payer,
+			nonceAccount,
+		).Build(),
+		system.NewInitializeNonceAccountInstruction(
+			authority,
+			nonceAccount,
+			solana.SysVarRecentBlockHashesPubkey,
+			solana.SysVarRentPubkey,
+		).Build(),
+	}
+}
+
+// NewFundNonceAccountInstruction returns a transfer of lamports into a nonce account.
+func NewFundNonceAccountInstruction(
+	funder solana.PublicKey,
+	nonceAccount solana.PublicKey,
+	lamports uint64,
+) solana.Instruction {
+	return system.NewTransferInstruction(lamports, funder, nonceAccount).Build()
+}
+
+// NewAdvanceNonceInstruction returns an instruction that advances the stored nonce.
+func NewAdvanceNonceInstruction(
+	nonceAccount solana.PublicKey,
+	authority solana.PublicKey,
+) solana.Instruction {
+	return system.NewAdvanceNonceAccountInstruction(
+		nonceAccount,
+		solana.SysVarRecentBlockHashesPubkey,
+		authority,
+	).Build()
+}
+
+// NewAuthorizeNonceInstruction returns an instruction that hands the nonce
+// account over to a new authority.
+func NewAuthorizeNonceInstruction(
+	nonceAccount solana.PublicKey,
+	authority solana.PublicKey,
+	newAuthority solana.PublicKey,
+) solana.Instruction {
+	return system.NewAuthorizeNonceAccountInstruction(newAuthority, nonceAccount, authority).Build()
+}
+
+// NewCloseNonceAccountInstruction returns an instruction that withdraws
+// the full balance of a nonce account, which closes it.
+// lamports must equal the current balance of the account.
+func NewCloseNonceAccountInstruction(
+	nonceAccount solana.PublicKey,
+	authority solana.PublicKey,
+	recipient solana.PublicKey,
+	lamports uint64,
+) solana.Instruction {
+	return system.NewWithdrawNonceAccountInstruction(
+		lamports,
+		nonceAccount,
+		recipient,
+		solana.SysVarRecentBlockHashesPubkey,
+		solana.SysVarRentPubkey,
+		authority,
+	).Build()
+}
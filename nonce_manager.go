@@ -0,0 +1,100 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"sync"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// NonceManager leases durable nonce accounts to concurrent senders.
+//
+// A durable nonce can only back one in-flight transaction at a time: once
+// a transaction advances it, every other transaction built on the same
+// nonce becomes invalid. The manager hands each nonce account to a single
+// holder until the lease is released.
+type NonceManager struct {
+	authority solana.PublicKey
+	free      chan solana.PublicKey
+}
+
+// NonceLease is exclusive use of one nonce account.
+type NonceLease struct {
+	Account   solana.PublicKey
+	Authority solana.PublicKey
+
+	manager *NonceManager
+	once    sync.Once
+}
+
+// NewNonceManager creates a manager over nonce accounts that share one authority.
+func NewNonceManager(authority solana.PublicKey, nonceAccounts ...solana.PublicKey) *NonceManager {
+	m := &NonceManager{
+		authority: authority,
+		free:      make(chan solana.PublicKey, len(nonceAccounts)),
+	}
+	for _, account := range nonceAccounts {
+		m.free <- account
+	}
+	return m
+}
+
+// Acquire waits until a nonce account is free or ctx is done.
+func (m *NonceManager) Acquire(ctx context.Context) (*NonceLease, error) {
+	select {
+	case account := <-m.free:
+		return &NonceLease{
+			Account:   account,
+			Authority: m.authority,
+			manager:   m,
+		}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// TryAcquire returns a lease if a nonce account is free right now.
+func (m *NonceManager) TryAcquire() (*NonceLease, bool) {
+	select {
+	case account := <-m.free:
+		return &NonceLease{
+			Account:   account,
+			Authority: m.authority,
+			manager:   m,
+		}, true
+	default:
+		return nil, false
+	}
+}
+
+// Available returns the number of nonce accounts not currently leased.
+func (m *NonceManager) Available() int {
+	return len(m.free)
+}
+
+// Release returns the nonce account to the manager.
+// Releasing a lease more than once has no effect.
+func (l *NonceLease) Release() {
+	l.once.Do(func() {
+		l.manager.free <- l.Account
+	})
+}
+
+// Apply puts the builder in durable nonce mode using the leased account.
+func (l *NonceLease) Apply(builder *TxBuilder) *TxBuilder {
+	return builder.SetDurableNonce(l.Account, l.Authority)
+}
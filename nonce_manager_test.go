@@ -0,0 +1,73 @@
+package token2022
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+func TestNonceManagerLeases(t *testing.T) {
+
+	var (
+		authority = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		nonceA    = solana.MustPublicKeyFromBase58("83mctxW8BCh6nPGjxx4jmyaEfbpcMZpLQiv7tXVSAV7a")
+		nonceB    = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+	)
+
+	manager := NewNonceManager(authority, nonceA, nonceB)
+
+	first, err := manager.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Error acquiring nonce: %v", err)
+	}
+	second, ok := manager.TryAcquire()
+	if !ok {
+		t.Fatalf("Expected a second nonce to be available")
+	}
+	if first.Account == second.Account {
+		t.Errorf("Expected distinct nonce accounts, got %s twice", first.Account)
+	}
+	if _, ok := manager.TryAcquire(); ok {
+		t.Errorf("Expected no nonce to be available")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := manager.Acquire(ctx); err == nil {
+		t.Errorf("Expected Acquire to time out")
+	}
+
+	first.Release()
+	first.Release()
+	if manager.Available() != 1 {
+		t.Errorf("Expected 1 available nonce, got %d", manager.Available())
+	}
+
+	builder := first.Apply(NewTxBuilder(newMockRPC()))
+	if !builder.UsesDurableNonce() {
+		t.Errorf("Expected builder in durable nonce mode")
+	}
+}
+
+func TestCreateNonceAccountInstructions(t *testing.T) {
+
+	var (
+		payer = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		nonce = solana.MustPublicKeyFromBase58("83mctxW8BCh6nPGjxx4jmyaEfbpcMZpLQiv7tXVSAV7a")
+	)
+
+	instructions := NewCreateNonceAccountInstructions(payer, nonce, payer, 1_447_680)
+	if len(instructions) != 2 {
+		t.Fatalf("Expected 2 instructions, got %d", len(instructions))
+	}
+	for _, inst := range instructions {
+		if inst.ProgramID() != solana.SystemProgramID {
+			t.Errorf("Expected system program, got %s", inst.ProgramID())
+		}
+	}
+	if !instructions[0].Accounts()[1].IsSigner {
+		t.Errorf("Expected the new nonce account to sign CreateAccount")
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// SignerSignature is a signature produced out-of-band by one required signer.
+type SignerSignature struct {
+	Signer    solana.PublicKey
+	Signature solana.Signature
+}
+
+// EncodeTransaction serializes a signed, partially signed or unsigned
+// transaction to base64. Missing signatures are encoded as zero bytes,
+// so the output is a valid wire-format transaction.
+func EncodeTransaction(tx *solana.Transaction) (string, error) {
+	if err := ensureSignatureSlots(tx); err != nil {
+		return "", err
+	}
+	return tx.ToBase64()
+}
+
+// DecodeTransaction parses a transaction produced by EncodeTransaction.
+func DecodeTransaction(encoded string) (*solana.Transaction, error) {
+	tx, err := solana.TransactionFromBase64(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("error while decoding transaction: %w", err)
+	}
+	if err := ensureSignatureSlots(tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// PartialSign signs the transaction with every given key that is a required
+// signer, keeping signatures that are already present.
+func PartialSign(tx *solana.Transaction, keys ...solana.PrivateKey) error {
+	return SignTransaction(context.Background(), tx, PrivateKeySigners(keys...)...)
+}
+
+// SignOffline signs an encoded transaction and returns only the signatures,
+// so an air-gapped signer can hand back a few bytes instead of the whole
+// transaction.
+func SignOffline(ctx context.Context, encoded string, signers ...Signer) ([]SignerSignature, error) {
+	tx, err := DecodeTransaction(encoded)
+	if err != nil {
+		return nil, err
+	}
+	return collectSignatures(ctx, tx, signers)
+}
+
+// ApplySignatures verifies each signature against the transaction message
+// and stores it at the position of its signer.
+func ApplySignatures(tx *solana.Transaction, sigs ...SignerSignature) error {
+	if err := ensureSignatureSlots(tx); err != nil {
+		return err
+	}
+	message, err := tx.Message.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("error while encoding message: %w", err)
+	}
+	signers := tx.Message.Signers()
+	for _, sig := range sigs {
+		index := signerIndex(signers, sig.Signer)
+		if index < 0 {
+			return fmt.Errorf("%s is not a signer of this transaction", sig.Signer)
+		}
+		if !sig.Signature.Verify(sig.Signer, message) {
+			return fmt.Errorf("invalid signature by %s", sig.Signer)
+		}
+		tx.Signatures[index] = sig.Signature
+	}
+	return nil
+}
+
+// MissingSigners returns the required signers that have not signed yet.
+func MissingSigners(tx *solana.Transaction) []solana.PublicKey {
+	signers := tx.Message.Signers()
+	var missing []solana.PublicKey
+	for i, signer := range signers {
+		if i >= len(tx.Signatures) || tx.Signatures[i].IsZero() {
+			missing = append(missing, signer)
+		}
+	}
+	return missing
+}
+
+// IsFullySigned reports whether every required signer has signed.
+func IsFullySigned(tx *solana.Transaction) bool {
+	return len(MissingSigners(tx)) == 0
+}
+
+// CombineTransactions merges the signatures of several copies of the same
+// transaction, each signed by a different party.
+func CombineTransactions(txs ...*solana.Transaction) (*solana.Transaction, error) {
+	if len(txs) == 0 {
+		return nil, errors.New("no transactions to combine")
+	}
+	message, err := txs[0].Message.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("error while encoding message: %w", err)
+	}
+	combined := &solana.Transaction{Message: txs[0].Message}
+	if err := ensureSignatureSlots(combined); err != nil {
+		return nil, err
+	}
+	signers := combined.Message.Signers()
+	for _, tx := range txs {
+		other, err := tx.Message.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("error while encoding message: %w", err)
+		}
+		if !bytes.Equal(message, other) {
+			return nil, errors.New("transactions have different messages")
+		}
+		for i, sig := range tx.Signatures {
+			if sig.IsZero() || i >= len(signers) {
+				continue
+			}
+			if err := ApplySignatures(combined, SignerSignature{Signer: signers[i], Signature: sig}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return combined, nil
+}
+
+func ensureSignatureSlots(tx *solana.Transaction) error {
+	required := int(tx.Message.Header.NumRequiredSignatures)
+	switch len(tx.Signatures) {
+	case required:
+		return nil
+	case 0:
+		tx.Signatures = make([]solana.Signature, required)
+		return nil
+	default:
+		return fmt.Errorf("invalid signatures length, expected %d, got %d", required, len(tx.Signatures))
+	}
+}
+
+func signerIndex(signers []solana.PublicKey, pubkey solana.PublicKey) int {
+	for i, signer := range signers {
+		if signer.Equals(pubkey) {
+			return i
+		}
+	}
+	return -1
+}
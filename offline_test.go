@@ -0,0 +1,76 @@
+package token2022
+
+import (
+	"context"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+func TestOfflinePartialSigning(t *testing.T) {
+
+	var (
+		feePayer = solana.NewWallet().PrivateKey
+		owner    = solana.NewWallet().PrivateKey
+		mint     = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	)
+
+	// The owner pays for the ATA; the fee payer is a separate party.
+	inst := NewCreate2022Instruction(owner.PublicKey(), owner.PublicKey(), mint).Build()
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{inst},
+		newMockRPC().blockhash,
+		solana.TransactionPayer(feePayer.PublicKey()),
+	)
+	if err != nil {
+		t.Fatalf("Error creating transaction: %v", err)
+	}
+
+	unsigned, err := EncodeTransaction(tx)
+	if err != nil {
+		t.Fatalf("Error encoding transaction: %v", err)
+	}
+
+	// Each party signs on its own machine.
+	ownerSigs, err := SignOffline(context.Background(), unsigned, NewPrivateKeySigner(owner))
+	if err != nil {
+		t.Fatalf("Error signing offline: %v", err)
+	}
+	payerCopy, err := DecodeTransaction(unsigned)
+	if err != nil {
+		t.Fatalf("Error decoding transaction: %v", err)
+	}
+	if err := PartialSign(payerCopy, feePayer); err != nil {
+		t.Fatalf("Error partially signing: %v", err)
+	}
+	if IsFullySigned(payerCopy) {
+		t.Fatalf("Expected owner signature to be missing")
+	}
+	if missing := MissingSigners(payerCopy); len(missing) != 1 || missing[0] != owner.PublicKey() {
+		t.Fatalf("Expected owner as only missing signer, got %v", missing)
+	}
+
+	ownerCopy, err := DecodeTransaction(unsigned)
+	if err != nil {
+		t.Fatalf("Error decoding transaction: %v", err)
+	}
+	if err := ApplySignatures(ownerCopy, ownerSigs...); err != nil {
+		t.Fatalf("Error applying signatures: %v", err)
+	}
+
+	combined, err := CombineTransactions(payerCopy, ownerCopy)
+	if err != nil {
+		t.Fatalf("Error combining transactions: %v", err)
+	}
+	if !IsFullySigned(combined) {
+		t.Fatalf("Expected combined transaction to be fully signed")
+	}
+	if err := combined.VerifySignatures(); err != nil {
+		t.Errorf("Error verifying signatures: %v", err)
+	}
+
+	bogus := SignerSignature{Signer: owner.PublicKey(), Signature: combined.Signatures[0]}
+	if err := ApplySignatures(ownerCopy, bogus); err == nil {
+		t.Errorf("Expected invalid signature to be rejected")
+	}
+}
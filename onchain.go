@@ -0,0 +1,213 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// OnChainError reports an instruction that the program would reject given
+// the current state of one of its accounts. Err is the error the program
+// would return, such as ErrAccountFrozen or ErrMintDecimalsMismatch, or
+// ErrAccountNotFound, ErrInvalidAccountOwner or ErrInvalidAccountData for
+// an account that is missing or is not a Token-2022 account.
+type OnChainError struct {
+	Instruction string
+	Account     solana.PublicKey
+	// Detail describes the problem, such as "mint has 9 decimals, not 6".
+	Detail string
+	Err    error
+}
+
+func (e *OnChainError) Error() string {
+	return fmt.Sprintf("%s of account %s: %s", e.Instruction, e.Account, e.Detail)
+}
+
+func (e *OnChainError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateOnChain validates inst and then reads its accounts at confirmed
+// commitment, returning an *OnChainError when the program would reject it:
+// a referenced mint or token account that does not exist or is not owned
+// by Token-2022, a token account of another mint, a frozen account, a
+// decimals argument that differs from the mint, or a closed account that
+// still holds tokens or is closed by someone other than its close
+// authority. Instructions other than transfers, mints, burns,
+// approvals, freezes, thaws and closes are only validated offline.
+func ValidateOnChain(ctx context.Context, client RPCClient, inst TypedInstruction) error {
+	if err := inst.Validate(); err != nil {
+		return err
+	}
+	c := &onChainCheck{ctx: ctx, client: client, instruction: builderName(inst)}
+	switch inst := inst.(type) {
+	case *Transfer2022:
+		source, err := c.tokenAccount(inst.Source, nil, false)
+		if err != nil {
+			return err
+		}
+		_, err = c.tokenAccount(inst.Destination, &source.Mint, false)
+		return err
+	case *TransferChecked2022:
+		return c.transfer(inst.Source, inst.Mint, inst.Destination, inst.Decimals)
+	case *TransferCheckedWithFee2022:
+		return c.transfer(inst.Source, inst.Mint, inst.Destination, inst.Decimals)
+	case *MintTo2022:
+		return c.accountOfMint(inst.Destination, inst.Mint, nil, false)
+	case *MintToChecked2022:
+		return c.accountOfMint(inst.Destination, inst.Mint, &inst.Decimals, false)
+	case *Burn2022:
+		return c.accountOfMint(inst.Account, inst.Mint, nil, false)
+	case *BurnChecked2022:
+		return c.accountOfMint(inst.Account, inst.Mint, &inst.Decimals, false)
+	case *Approve2022:
+		_, err := c.tokenAccount(inst.Source, nil, false)
+		return err
+	case *ApproveChecked2022:
+		return c.accountOfMint(inst.Source, inst.Mint, &inst.Decimals, false)
+	case *FreezeAccount2022:
+		return c.accountOfMint(inst.Account, inst.Mint, nil, false)
+	case *ThawAccount2022:
+		return c.accountOfMint(inst.Account, inst.Mint, nil, true)
+	case *Close2022:
+		return c.close(inst.Account, inst.Owner)
+	}
+	return nil
+}
+
+// onChainCheck fetches the accounts of one instruction.
+type onChainCheck struct {
+	ctx         context.Context
+	client      RPCClient
+	instruction string
+}
+
+func (c *onChainCheck) fail(account solana.PublicKey, err error, format string, args ...any) error {
+	return &OnChainError{Instruction: c.instruction, Account: account, Detail: fmt.Sprintf(format, args...), Err: err}
+}
+
+// fetchFailed converts the errors of a fetch that the program would also
+// fail on into an *OnChainError.
+func (c *onChainCheck) fetchFailed(account solana.PublicKey, kind string, err error) error {
+	switch {
+	case errors.Is(err, ErrAccountNotFound):
+		return c.fail(account, err, "%s does not exist", kind)
+	case errors.Is(err, ErrInvalidAccountOwner):
+		return c.fail(account, err, "%s is not owned by the Token-2022 program", kind)
+	case errors.Is(err, ErrInvalidAccountData):
+		return c.fail(account, err, "account is not a %s", kind)
+	}
+	return fmt.Errorf("error while fetching %s %s: %w", kind, account, err)
+}
+
+func (c *onChainCheck) mint(key solana.PublicKey, decimals *uint8) (*Mint, error) {
+	mint, err := FetchMint(c.ctx, c.client, key, rpc.CommitmentConfirmed)
+	if err != nil {
+		return nil, c.fetchFailed(key, "mint", err)
+	}
+	if decimals != nil && mint.Decimals != *decimals {
+		return nil, c.fail(key, ErrMintDecimalsMismatch, "mint has %d decimals, not %d", mint.Decimals, *decimals)
+	}
+	return mint, nil
+}
+
+// tokenAccount fetches a token account and checks that it belongs to mint,
+// when set, and that it is frozen exactly when frozen is set.
+func (c *onChainCheck) tokenAccount(key solana.PublicKey, mint *solana.PublicKey, frozen bool) (*TokenAccount, error) {
+	account, err := FetchTokenAccount(c.ctx, c.client, key, rpc.CommitmentConfirmed)
+	if err != nil {
+		return nil, c.fetchFailed(key, "token account", err)
+	}
+	if mint != nil && !account.Mint.Equals(*mint) {
+		return nil, c.fail(key, ErrMintMismatch, "account is for mint %s, not %s", account.Mint, *mint)
+	}
+	switch {
+	case account.IsFrozen() && !frozen:
+		return nil, c.fail(key, ErrAccountFrozen, "account is frozen")
+	case !account.IsFrozen() && frozen:
+		return nil, c.fail(key, ErrInvalidState, "account is not frozen")
+	}
+	return account, nil
+}
+
+func (c *onChainCheck) accountOfMint(account, mint solana.PublicKey, decimals *uint8, frozen bool) error {
+	if _, err := c.mint(mint, decimals); err != nil {
+		return err
+	}
+	_, err := c.tokenAccount(account, &mint, frozen)
+	return err
+}
+
+func (c *onChainCheck) transfer(source, mint, destination solana.PublicKey, decimals uint8) error {
+	if err := c.accountOfMint(source, mint, &decimals, false); err != nil {
+		return err
+	}
+	_, err := c.tokenAccount(destination, &mint, false)
+	return err
+}
+
+// close checks that a token account holds no tokens and that owner may
+// close it, or that a mint has a MintCloseAuthority extension naming
+// owner and no supply.
+func (c *onChainCheck) close(key, owner solana.PublicKey) error {
+	account, err := FetchTokenAccount(c.ctx, c.client, key, rpc.CommitmentConfirmed)
+	if errors.Is(err, ErrInvalidAccountData) {
+		return c.closeMint(key, owner)
+	}
+	if err != nil {
+		return c.fetchFailed(key, "token account", err)
+	}
+	if account.IsFrozen() {
+		return c.fail(key, ErrAccountFrozen, "account is frozen")
+	}
+	if account.IsNative == nil && account.Amount > 0 {
+		return c.fail(key, ErrNonNativeHasBalance, "account holds %d tokens", account.Amount)
+	}
+	authority := account.Owner
+	if account.CloseAuthority != nil {
+		authority = *account.CloseAuthority
+	}
+	if !authority.Equals(owner) {
+		return c.fail(key, ErrOwnerMismatch, "account is closed by %s, not %s", authority, owner)
+	}
+	return nil
+}
+
+func (c *onChainCheck) closeMint(key, owner solana.PublicKey) error {
+	mint, err := c.mint(key, nil)
+	if err != nil {
+		return err
+	}
+	authority, ok, err := mint.MintCloseAuthority()
+	switch {
+	case err != nil:
+		return c.fail(key, err, "invalid MintCloseAuthority extension")
+	case !ok:
+		return c.fail(key, ErrAuthorityTypeNotSupported, "mint has no MintCloseAuthority extension")
+	case authority == nil:
+		return c.fail(key, ErrAuthorityTypeNotSupported, "mint has no close authority")
+	case !authority.Equals(owner):
+		return c.fail(key, ErrOwnerMismatch, "mint close authority is %s, not %s", *authority, owner)
+	}
+	if mint.Supply > 0 {
+		return c.fail(key, ErrMintHasSupply, "mint has a supply of %d", mint.Supply)
+	}
+	return nil
+}
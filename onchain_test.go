@@ -0,0 +1,114 @@
+package token2022
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+func TestValidateOnChain(t *testing.T) {
+	var (
+		wallet      = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		other       = solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR")
+		ctx         = context.Background()
+	)
+	client := newMockRPC()
+	client.setAccount(mint, solana.Token2022ProgramID, encodeMint(&wallet, 1000, 6, Extension{Type: ExtensionMintCloseAuthority, Data: wallet.Bytes()}))
+	client.setAccount(source, solana.Token2022ProgramID, encodeTokenAccount(mint, wallet, 100))
+	client.setAccount(destination, solana.Token2022ProgramID, encodeTokenAccount(mint, wallet, 0))
+
+	if err := NewTransferChecked2022Instruction(10, 6, source, mint, destination, wallet).ValidateOnChain(ctx, client); err != nil {
+		t.Fatalf("Expected a valid transfer, got %v", err)
+	}
+	if err := NewMintToChecked2022Instruction(10, 6, mint, destination, wallet).ValidateOnChain(ctx, client); err != nil {
+		t.Fatalf("Expected a valid mint, got %v", err)
+	}
+	if err := NewClose2022Instruction(destination, wallet, wallet).ValidateOnChain(ctx, client); err != nil {
+		t.Fatalf("Expected an empty account to close, got %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		inst    interface{ ValidateOnChain(context.Context, RPCClient) error }
+		account solana.PublicKey
+		want    error
+	}{
+		{"decimals", NewTransferChecked2022Instruction(10, 9, source, mint, destination, wallet), mint, ErrMintDecimalsMismatch},
+		{"missing destination", NewTransferChecked2022Instruction(10, 6, source, mint, other, wallet), other, ErrAccountNotFound},
+		{"wrong mint", NewBurnChecked2022Instruction(10, 6, source, destination, wallet), destination, ErrInvalidAccountData},
+		{"thaw unfrozen", NewThawAccount2022Instruction(source, mint, wallet), source, ErrInvalidState},
+		{"close with balance", NewClose2022Instruction(source, wallet, wallet), source, ErrNonNativeHasBalance},
+		{"close mint with supply", NewClose2022Instruction(mint, wallet, wallet), mint, ErrMintHasSupply},
+	}
+	for _, tt := range cases {
+		err := tt.inst.ValidateOnChain(ctx, client)
+		var onChain *OnChainError
+		if !errors.As(err, &onChain) || !errors.Is(err, tt.want) {
+			t.Errorf("%s: expected an OnChainError matching %v, got %v", tt.name, tt.want, err)
+			continue
+		}
+		if !onChain.Account.Equals(tt.account) {
+			t.Errorf("%s: expected account %s, got %s", tt.name, tt.account, onChain.Account)
+		}
+	}
+
+	frozen := encodeTokenAccount(mint, wallet, 100)
+	frozen[108] = byte(AccountStateFrozen)
+	client.setAccount(source, solana.Token2022ProgramID, frozen)
+	if err := NewTransfer2022Instruction(10, source, destination, wallet).ValidateOnChain(ctx, client); !errors.Is(err, ErrAccountFrozen) {
+		t.Errorf("Expected ErrAccountFrozen, got %v", err)
+	}
+	client.setAccount(destination, solana.Token2022ProgramID, encodeTokenAccount(other, wallet, 0))
+	if err := NewTransfer2022Instruction(10, source, destination, wallet).ValidateOnChain(ctx, client); !errors.Is(err, ErrAccountFrozen) {
+		t.Errorf("Expected the frozen source to be reported first, got %v", err)
+	}
+	client.setAccount(source, solana.Token2022ProgramID, encodeTokenAccount(mint, wallet, 100))
+	if err := NewTransfer2022Instruction(10, source, destination, wallet).ValidateOnChain(ctx, client); !errors.Is(err, ErrMintMismatch) {
+		t.Errorf("Expected ErrMintMismatch, got %v", err)
+	}
+	if err := NewTransfer2022Instruction(10, solana.PublicKey{}, destination, wallet).ValidateOnChain(ctx, client); !errors.Is(err, ErrNotSet) {
+		t.Errorf("Expected offline validation first, got %v", err)
+	}
+}
+
+func TestValidateOnChainClose(t *testing.T) {
+	var (
+		wallet  = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint    = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		account = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		other   = solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR")
+		ctx     = context.Background()
+	)
+	client := newMockRPC()
+	client.setAccount(mint, solana.Token2022ProgramID, encodeMint(&wallet, 0, 6, Extension{Type: ExtensionMintCloseAuthority, Data: wallet.Bytes()}))
+	client.setAccount(account, solana.Token2022ProgramID, EncodeTokenAccount(&TokenAccount{
+		Mint: mint, Owner: wallet, State: AccountStateInitialized, CloseAuthority: &other,
+	}))
+
+	if err := NewClose2022Instruction(mint, wallet, wallet).ValidateOnChain(ctx, client); err != nil {
+		t.Fatalf("Expected the close authority to close an empty mint, got %v", err)
+	}
+	if err := NewClose2022Instruction(mint, wallet, other).ValidateOnChain(ctx, client); !errors.Is(err, ErrOwnerMismatch) {
+		t.Errorf("Expected ErrOwnerMismatch for another signer, got %v", err)
+	}
+	if err := NewClose2022Instruction(account, wallet, other).ValidateOnChain(ctx, client); err != nil {
+		t.Errorf("Expected the account close authority to close it, got %v", err)
+	}
+	if err := NewClose2022Instruction(account, wallet, wallet).ValidateOnChain(ctx, client); !errors.Is(err, ErrOwnerMismatch) {
+		t.Errorf("Expected the owner to be rejected when a close authority is set, got %v", err)
+	}
+
+	client.setAccount(mint, solana.Token2022ProgramID, encodeMint(&wallet, 0, 6, Extension{Type: ExtensionMintCloseAuthority, Data: make([]byte, 32)}))
+	if err := NewClose2022Instruction(mint, wallet, wallet).ValidateOnChain(ctx, client); !errors.Is(err, ErrAuthorityTypeNotSupported) {
+		t.Errorf("Expected ErrAuthorityTypeNotSupported without a close authority, got %v", err)
+	}
+	client.setAccount(mint, solana.Token2022ProgramID, encodeMint(&wallet, 0, 6))
+	if err := NewClose2022Instruction(mint, wallet, wallet).ValidateOnChain(ctx, client); !errors.Is(err, ErrAuthorityTypeNotSupported) {
+		t.Errorf("Expected ErrAuthorityTypeNotSupported without the extension, got %v", err)
+	}
+}
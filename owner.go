@@ -0,0 +1,46 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dwmfan/token2022/core"
+	"github.com/gagliardetto/solana-go"
+)
+
+// ErrOwnerOffCurve is returned when an associated token account is derived
+// or created for an owner that is not on the ed25519 curve, such as a
+// program derived address, without allowing it. Nobody holds a private key
+// for such an owner, so only the program that derived it can move the
+// tokens; sent to a mistyped or foreign address they are lost.
+var ErrOwnerOffCurve = errors.New("owner is not on the ed25519 curve")
+
+// IsOnCurve reports whether key is an ed25519 point, and so may be a
+// wallet with a private key. Program derived addresses are off the curve.
+func IsOnCurve(key solana.PublicKey) bool {
+	return core.IsOnCurve(key)
+}
+
+// ValidateOwner returns ErrOwnerOffCurve when owner is off the curve and
+// allowOwnerOffCurve is false. Pass true only for owners known to be
+// program derived addresses.
+func ValidateOwner(owner solana.PublicKey, allowOwnerOffCurve bool) error {
+	if allowOwnerOffCurve || IsOnCurve(owner) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrOwnerOffCurve, owner)
+}
@@ -0,0 +1,186 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"fmt"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// ParsedInstruction is a Token-2022 or Associated Token Account
+// instruction found in a transaction.
+type ParsedInstruction struct {
+	// Index is the position of the top-level instruction. For inner
+	// instructions it is the top-level instruction that invoked them.
+	Index int
+	// InnerIndex is the position within the inner instructions of Index,
+	// or -1 for a top-level instruction.
+	InnerIndex int
+	ProgramID  solana.PublicKey
+	// Name is the instruction name, such as "TransferChecked".
+	Name string
+	// Instruction is the decoded builder, or nil when DecodeErr is set.
+	Instruction TypedInstruction
+	// DecodeErr is set when the instruction could not be decoded, for
+	// example because it is not supported by this package.
+	DecodeErr error
+	Accounts  []*solana.AccountMeta
+	Data      []byte
+}
+
+// IsInner reports whether the instruction was invoked by another program.
+func (p *ParsedInstruction) IsInner() bool {
+	return p.InnerIndex >= 0
+}
+
+// ParsedTransaction is the list of Token-2022 and Associated Token Account
+// instructions of a transaction, in execution order.
+type ParsedTransaction struct {
+	Signature    solana.Signature
+	Instructions []*ParsedInstruction
+	// Err is the transaction failure, or nil when the transaction succeeded
+	// or no meta was given.
+	Err *TransactionError
+}
+
+// ParseTransaction walks the top-level instructions of tx and, when meta
+// is given, their inner instructions, and decodes every Token-2022 and
+// Associated Token Account instruction through the registry. Each
+// top-level instruction is followed by the inner instructions it invoked.
+//
+// meta is needed to resolve the accounts of versioned transactions that use
+// address lookup tables.
+func ParseTransaction(tx *solana.Transaction, meta *rpc.TransactionMeta) (*ParsedTransaction, error) {
+	if tx == nil {
+		return nil, errNotSet("transaction")
+	}
+	keys := newTransactionKeys(&tx.Message, meta)
+
+	parsed := &ParsedTransaction{}
+	if len(tx.Signatures) > 0 {
+		parsed.Signature = tx.Signatures[0]
+	}
+	if meta != nil && meta.Err != nil {
+		parsed.Err = DecodeTransactionError(parsed.Signature, meta.Err)
+	}
+
+	inner := map[int][]solana.CompiledInstruction{}
+	if meta != nil {
+		for _, set := range meta.InnerInstructions {
+			inner[int(set.Index)] = append(inner[int(set.Index)], set.Instructions...)
+		}
+	}
+
+	for i, compiled := range tx.Message.Instructions {
+		inst, err := keys.parse(compiled, i, -1)
+		if err != nil {
+			return nil, err
+		}
+		if inst != nil {
+			parsed.Instructions = append(parsed.Instructions, inst)
+		}
+		for j, compiled := range inner[i] {
+			inst, err := keys.parse(compiled, i, j)
+			if err != nil {
+				return nil, err
+			}
+			if inst != nil {
+				parsed.Instructions = append(parsed.Instructions, inst)
+			}
+		}
+	}
+	return parsed, nil
+}
+
+// transactionKeys is the full account list of a transaction: the static
+// keys followed by the writable and readonly keys loaded from lookup
+// tables.
+type transactionKeys struct {
+	keys     []solana.PublicKey
+	header   solana.MessageHeader
+	static   int
+	writable int
+}
+
+func newTransactionKeys(message *solana.Message, meta *rpc.TransactionMeta) *transactionKeys {
+	keys := &transactionKeys{
+		keys:   append([]solana.PublicKey{}, message.AccountKeys...),
+		header: message.Header,
+		static: len(message.AccountKeys),
+	}
+	if meta != nil {
+		keys.keys = append(keys.keys, meta.LoadedAddresses.Writable...)
+		keys.writable = len(meta.LoadedAddresses.Writable)
+		keys.keys = append(keys.keys, meta.LoadedAddresses.ReadOnly...)
+	}
+	return keys
+}
+
+func (k *transactionKeys) meta(index uint16) (*solana.AccountMeta, error) {
+	i := int(index)
+	if i >= len(k.keys) {
+		return nil, fmt.Errorf("account index %d out of range (%d accounts)", i, len(k.keys))
+	}
+	signers := int(k.header.NumRequiredSignatures)
+	meta := &solana.AccountMeta{PublicKey: k.keys[i]}
+	switch {
+	case i < signers:
+		meta.IsSigner = true
+		meta.IsWritable = i < signers-int(k.header.NumReadonlySignedAccounts)
+	case i < k.static:
+		meta.IsWritable = i < k.static-int(k.header.NumReadonlyUnsignedAccounts)
+	default:
+		meta.IsWritable = i < k.static+k.writable
+	}
+	return meta, nil
+}
+
+// parse returns the parsed instruction, or nil when it does not belong to
+// the Token-2022 or Associated Token Account program.
+func (k *transactionKeys) parse(compiled solana.CompiledInstruction, index, innerIndex int) (*ParsedInstruction, error) {
+	programID, err := k.meta(compiled.ProgramIDIndex)
+	if err != nil {
+		return nil, fmt.Errorf("instruction %d: %w", index, err)
+	}
+	isToken := programID.PublicKey.Equals(solana.Token2022ProgramID)
+	if !isToken && !programID.PublicKey.Equals(solana.SPLAssociatedTokenAccountProgramID) {
+		return nil, nil
+	}
+
+	accounts := make([]*solana.AccountMeta, len(compiled.Accounts))
+	for i, accountIndex := range compiled.Accounts {
+		if accounts[i], err = k.meta(accountIndex); err != nil {
+			return nil, fmt.Errorf("instruction %d: %w", index, err)
+		}
+	}
+
+	parsed := &ParsedInstruction{
+		Index:      index,
+		InnerIndex: innerIndex,
+		ProgramID:  programID.PublicKey,
+		Accounts:   accounts,
+		Data:       compiled.Data,
+	}
+	if isToken {
+		parsed.Name = InstructionName(compiled.Data)
+		parsed.Instruction, parsed.DecodeErr = DecodeInstruction(accounts, compiled.Data)
+	} else {
+		parsed.Name = AssociatedTokenInstructionName(compiled.Data)
+		parsed.Instruction, parsed.DecodeErr = DecodeAssociatedTokenInstruction(accounts, compiled.Data)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,162 @@
+package token2022
+
+import (
+	"errors"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestParseTransaction(t *testing.T) {
+
+	var (
+		wallet      = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+	)
+
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{
+			NewCreate2022Instruction(wallet, wallet, mint).SetIdempotent(true).Build(),
+			solana.NewInstruction(solana.MemoProgramID, solana.AccountMetaSlice{}, []byte("hello")),
+			NewTransferChecked2022Instruction(1_000, 6, source, mint, destination, wallet).Build(),
+		},
+		solana.Hash(source),
+		solana.TransactionPayer(wallet),
+	)
+	if err != nil {
+		t.Fatalf("NewTransaction: %v", err)
+	}
+	tx.Signatures = []solana.Signature{{1}}
+
+	index := func(key solana.PublicKey) uint16 {
+		for i, k := range tx.Message.AccountKeys {
+			if k.Equals(key) {
+				return uint16(i)
+			}
+		}
+		t.Fatalf("key %s not in transaction", key)
+		return 0
+	}
+	// The memo program invokes a TransferChecked and a Token-2022
+	// instruction this package does not support.
+	meta := &rpc.TransactionMeta{
+		Err: map[string]interface{}{"InstructionError": []interface{}{float64(1), map[string]interface{}{"Custom": float64(1)}}},
+		InnerInstructions: []rpc.InnerInstruction{{
+			Index: 1,
+			Instructions: []solana.CompiledInstruction{
+				{
+					ProgramIDIndex: index(solana.Token2022ProgramID),
+					Accounts:       []uint16{index(source), index(mint), index(destination), index(wallet)},
+					Data:           []byte{12, 5, 0, 0, 0, 0, 0, 0, 0, 6},
+				},
+				{
+					ProgramIDIndex: index(solana.Token2022ProgramID),
+					Accounts:       []uint16{index(source)},
+					Data:           []byte{27, 0},
+				},
+			},
+		}},
+	}
+
+	parsed, err := ParseTransaction(tx, meta)
+	if err != nil {
+		t.Fatalf("ParseTransaction: %v", err)
+	}
+	if parsed.Signature != tx.Signatures[0] {
+		t.Errorf("unexpected signature %s", parsed.Signature)
+	}
+	if parsed.Err == nil || parsed.Err.InstructionIndex != 1 || parsed.Err.Custom == nil {
+		t.Errorf("unexpected error %v", parsed.Err)
+	}
+	if len(parsed.Instructions) != 4 {
+		t.Fatalf("expected 4 instructions, got %d", len(parsed.Instructions))
+	}
+
+	create, ok := parsed.Instructions[0].Instruction.(*Create2022)
+	if !ok || !create.Idempotent || !create.Mint.Equals(mint) || !create.Wallet.Equals(wallet) {
+		t.Errorf("unexpected create instruction %#v (%v)", parsed.Instructions[0].Instruction, parsed.Instructions[0].DecodeErr)
+	}
+	if parsed.Instructions[0].Name != "CreateIdempotent" || parsed.Instructions[0].IsInner() {
+		t.Errorf("unexpected create name %q", parsed.Instructions[0].Name)
+	}
+
+	inner := parsed.Instructions[1]
+	if inner.Index != 1 || inner.InnerIndex != 0 || inner.Name != "TransferChecked" {
+		t.Errorf("unexpected inner instruction %d/%d %q", inner.Index, inner.InnerIndex, inner.Name)
+	}
+	transfer, ok := inner.Instruction.(*TransferChecked2022)
+	if !ok || transfer.Amount != 5 || !transfer.Destination.Equals(destination) {
+		t.Errorf("unexpected inner transfer %#v (%v)", inner.Instruction, inner.DecodeErr)
+	}
+	if !inner.Accounts[3].IsSigner || !inner.Accounts[0].IsWritable || inner.Accounts[1].IsWritable {
+		t.Errorf("unexpected inner account flags")
+	}
+
+	if unsupported := parsed.Instructions[2]; unsupported.Instruction != nil || !errors.Is(unsupported.DecodeErr, ErrUnsupportedInstruction) {
+		t.Errorf("expected unsupported instruction, got %v", unsupported.DecodeErr)
+	}
+
+	last := parsed.Instructions[3]
+	if last.Index != 2 || last.IsInner() {
+		t.Errorf("unexpected position %d/%d", last.Index, last.InnerIndex)
+	}
+	if transfer, ok := last.Instruction.(*TransferChecked2022); !ok || transfer.Amount != 1_000 {
+		t.Errorf("unexpected top-level transfer %#v (%v)", last.Instruction, last.DecodeErr)
+	}
+}
+
+func TestParseTransactionLookupTables(t *testing.T) {
+
+	var (
+		wallet = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint   = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		dest   = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+	)
+
+	tx := &solana.Transaction{
+		Signatures: []solana.Signature{{2}},
+		Message: solana.Message{
+			Header:      solana.MessageHeader{NumRequiredSignatures: 1, NumReadonlyUnsignedAccounts: 1},
+			AccountKeys: solana.PublicKeySlice{wallet, solana.Token2022ProgramID},
+			Instructions: []solana.CompiledInstruction{{
+				ProgramIDIndex: 1,
+				Accounts:       []uint16{2, 4, 3, 0},
+				Data:           []byte{12, 7, 0, 0, 0, 0, 0, 0, 0, 2},
+			}},
+		},
+	}
+	tx.Message.SetVersion(solana.MessageVersionV0)
+
+	if _, err := ParseTransaction(tx, nil); err == nil {
+		t.Fatalf("expected an error without loaded addresses")
+	}
+
+	meta := &rpc.TransactionMeta{
+		LoadedAddresses: rpc.LoadedAddresses{
+			Writable: solana.PublicKeySlice{source, dest},
+			ReadOnly: solana.PublicKeySlice{mint},
+		},
+	}
+	parsed, err := ParseTransaction(tx, meta)
+	if err != nil {
+		t.Fatalf("ParseTransaction: %v", err)
+	}
+	if len(parsed.Instructions) != 1 {
+		t.Fatalf("expected 1 instruction, got %d", len(parsed.Instructions))
+	}
+	transfer, ok := parsed.Instructions[0].Instruction.(*TransferChecked2022)
+	if !ok {
+		t.Fatalf("unexpected instruction %#v (%v)", parsed.Instructions[0].Instruction, parsed.Instructions[0].DecodeErr)
+	}
+	if !transfer.Source.Equals(source) || !transfer.Mint.Equals(mint) || !transfer.Destination.Equals(dest) || !transfer.Owner.Equals(wallet) {
+		t.Errorf("unexpected accounts")
+	}
+	accounts := parsed.Instructions[0].Accounts
+	if !accounts[0].IsWritable || accounts[1].IsWritable || !accounts[2].IsWritable || !accounts[3].IsSigner {
+		t.Errorf("unexpected account flags")
+	}
+}
@@ -0,0 +1,518 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// Payout is a queued withdrawal of one recipient, identified by the ID of
+// the caller, such as a withdrawal request ID.
+type Payout struct {
+	ID string `json:"id"`
+	Recipient
+}
+
+// PayoutStatus is the progress of a payout.
+type PayoutStatus string
+
+const (
+	// PayoutQueued payouts have no transaction that may still land.
+	PayoutQueued PayoutStatus = "queued"
+	// PayoutSubmitted payouts have a signed transaction that may have
+	// landed; the nonce it was built on tells.
+	PayoutSubmitted PayoutStatus = "submitted"
+	// PayoutPaid payouts landed.
+	PayoutPaid PayoutStatus = "paid"
+	// PayoutFailed payouts landed and failed on-chain. Nothing was
+	// transferred, but they are not retried automatically.
+	PayoutFailed PayoutStatus = "failed"
+)
+
+// PayoutRecord is what a PayoutStore keeps for a payout.
+type PayoutRecord struct {
+	Payout
+	Status PayoutStatus `json:"status"`
+	// NonceAccount and Nonce are the durable nonce the submitted
+	// transaction was built on. Once the nonce account holds another
+	// nonce, the transaction either landed or can never land.
+	NonceAccount solana.PublicKey `json:"nonceAccount,omitempty"`
+	Nonce        solana.Hash      `json:"nonce,omitempty"`
+	Signature    solana.Signature `json:"signature,omitempty"`
+	// Transaction is the signed transaction in wire format, kept to
+	// rebroadcast it after a restart.
+	Transaction []byte    `json:"transaction,omitempty"`
+	Slot        uint64    `json:"slot,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	Attempts    int       `json:"attempts"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// PayoutStore persists payout records. Save must be durable before it
+// returns: a transaction is only submitted once its record has been saved.
+type PayoutStore interface {
+	// Load returns the record of id, or nil when there is none.
+	Load(ctx context.Context, id string) (*PayoutRecord, error)
+	Save(ctx context.Context, record *PayoutRecord) error
+	// Unfinished returns the queued and submitted records, oldest first.
+	Unfinished(ctx context.Context) ([]*PayoutRecord, error)
+}
+
+// MemoryPayoutStore is a PayoutStore kept in memory, for tests.
+type MemoryPayoutStore struct {
+	mu      sync.Mutex
+	records map[string][]byte
+}
+
+var _ PayoutStore = (*MemoryPayoutStore)(nil)
+
+func NewMemoryPayoutStore() *MemoryPayoutStore {
+	return &MemoryPayoutStore{records: map[string][]byte{}}
+}
+
+func (s *MemoryPayoutStore) Load(ctx context.Context, id string) (*PayoutRecord, error) {
+	s.mu.Lock()
+	data, ok := s.records[id]
+	s.mu.Unlock()
+	if !ok {
+		return nil, nil
+	}
+	record := new(PayoutRecord)
+	if err := json.Unmarshal(data, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func (s *MemoryPayoutStore) Save(ctx context.Context, record *PayoutRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.records[record.ID] = data
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *MemoryPayoutStore) Unfinished(ctx context.Context) ([]*PayoutRecord, error) {
+	s.mu.Lock()
+	var records []*PayoutRecord
+	for _, data := range s.records {
+		record := new(PayoutRecord)
+		if err := json.Unmarshal(data, record); err != nil {
+			s.mu.Unlock()
+			return nil, err
+		}
+		if record.Status == PayoutQueued || record.Status == PayoutSubmitted {
+			records = append(records, record)
+		}
+	}
+	s.mu.Unlock()
+	sort.Slice(records, func(i, j int) bool {
+		if !records[i].CreatedAt.Equal(records[j].CreatedAt) {
+			return records[i].CreatedAt.Before(records[j].CreatedAt)
+		}
+		return records[i].ID < records[j].ID
+	})
+	return records, nil
+}
+
+// PayoutManager pays queued withdrawals of one mint from one source token
+// account, each in its own transaction built on a durable nonce leased
+// from a NonceManager.
+//
+// The signed transaction is saved before it is submitted. Since a durable
+// nonce can only be consumed once, a payout whose nonce account still
+// holds the saved nonce has not landed and its transaction is
+// rebroadcast, while one whose nonce has moved on either landed, which its
+// signature status tells, or can never land and is built again on a new
+// nonce. A worker that crashes at any point therefore never pays twice:
+// Process picks the payout up where it stopped.
+type PayoutManager struct {
+	client         DistributorClient
+	store          PayoutStore
+	nonces         *NonceManager
+	mint           solana.PublicKey
+	decimals       uint8
+	source         solana.PublicKey
+	authority      Signer
+	feePayer       Signer
+	nonceAuthority Signer
+	commitment     rpc.CommitmentType
+	pollInterval   time.Duration
+	maxAttempts    int
+	logger         Logger
+	metrics        Metrics
+	// allowOwnerOffCurve pays wallets off the ed25519 curve; see
+	// SetAllowOwnerOffCurve.
+	allowOwnerOffCurve bool
+
+	mu sync.Mutex
+}
+
+// NewPayoutManager creates a manager paying mint, with decimals decimals,
+// from source, whose owner or delegate is authority. The authority also
+// pays the fees and rent and is the authority of the nonce accounts.
+func NewPayoutManager(client DistributorClient, store PayoutStore, nonces *NonceManager, mint solana.PublicKey, decimals uint8, source solana.PublicKey, authority Signer) *PayoutManager {
+	return &PayoutManager{
+		client:         client,
+		store:          store,
+		nonces:         nonces,
+		mint:           mint,
+		decimals:       decimals,
+		source:         source,
+		authority:      authority,
+		feePayer:       authority,
+		nonceAuthority: authority,
+		commitment:     rpc.CommitmentConfirmed,
+		pollInterval:   time.Second,
+		maxAttempts:    3,
+	}
+}
+
+func (m *PayoutManager) SetFeePayer(feePayer Signer) *PayoutManager {
+	m.feePayer = feePayer
+	return m
+}
+
+// SetNonceAuthority sets the signer authorized on the nonce accounts of
+// the NonceManager.
+func (m *PayoutManager) SetNonceAuthority(authority Signer) *PayoutManager {
+	m.nonceAuthority = authority
+	return m
+}
+
+func (m *PayoutManager) SetCommitment(commitment rpc.CommitmentType) *PayoutManager {
+	m.commitment = commitment
+	return m
+}
+
+func (m *PayoutManager) SetPollInterval(interval time.Duration) *PayoutManager {
+	m.pollInterval = interval
+	return m
+}
+
+// SetMaxAttempts sets how many nonces a payout is tried on within one
+// Process before it is left queued.
+func (m *PayoutManager) SetMaxAttempts(attempts int) *PayoutManager {
+	m.maxAttempts = attempts
+	return m
+}
+
+// SetAllowOwnerOffCurve accepts payouts to wallets off the ed25519 curve,
+// such as program derived addresses, which Enqueue otherwise rejects with
+// ErrOwnerOffCurve.
+func (m *PayoutManager) SetAllowOwnerOffCurve(allow bool) *PayoutManager {
+	m.allowOwnerOffCurve = allow
+	return m
+}
+
+// SetLogger logs every submitted, paid, failed and requeued payout.
+func (m *PayoutManager) SetLogger(logger Logger) *PayoutManager {
+	m.logger = logger
+	return m
+}
+
+// SetMetrics counts sent, confirmed and failed transactions.
+func (m *PayoutManager) SetMetrics(metrics Metrics) *PayoutManager {
+	m.metrics = metrics
+	return m
+}
+
+// Enqueue saves payout as queued. Enqueueing an ID that is already known
+// returns its record unchanged, so a withdrawal request delivered twice is
+// paid once.
+func (m *PayoutManager) Enqueue(ctx context.Context, payout Payout) (*PayoutRecord, error) {
+	if payout.ID == "" {
+		return nil, errNotSet("ID")
+	}
+	if payout.Wallet.IsZero() {
+		return nil, errNotSet("Wallet")
+	}
+	if err := ValidateOwner(payout.Wallet, m.allowOwnerOffCurve); err != nil {
+		return nil, err
+	}
+	if payout.Amount == 0 {
+		return nil, errInvalidField("Amount", "must be greater than zero")
+	}
+	record, err := m.store.Load(ctx, payout.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error while loading payout %q: %w", payout.ID, err)
+	}
+	if record != nil {
+		return record, nil
+	}
+	now := time.Now()
+	record = &PayoutRecord{Payout: payout, Status: PayoutQueued, CreatedAt: now, UpdatedAt: now}
+	if err := m.store.Save(ctx, record); err != nil {
+		return nil, fmt.Errorf("error while saving payout %q: %w", payout.ID, err)
+	}
+	return record, nil
+}
+
+// Process settles the submitted payouts of the store, then pays the
+// queued ones, as many at once as there are free nonce accounts. It
+// returns the records it processed with their new status, and the first
+// error. Payouts left queued or submitted by an error are picked up by
+// the next Process. Only one Process runs at a time.
+func (m *PayoutManager) Process(ctx context.Context) ([]*PayoutRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	records, err := m.store.Unfinished(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while listing payouts: %w", err)
+	}
+
+	// Submitted payouts hold on to their nonce account until settled, so
+	// they go first, before any nonce is leased.
+	var firstErr error
+	var queued []*PayoutRecord
+	for _, record := range records {
+		if record.Status == PayoutSubmitted {
+			if err := m.settle(ctx, record); err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+		}
+		if record.Status == PayoutQueued {
+			queued = append(queued, record)
+		}
+	}
+	if firstErr != nil {
+		return records, firstErr
+	}
+
+	workers := m.nonces.Available()
+	if workers < 1 {
+		workers = 1
+	}
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		jobs = make(chan *PayoutRecord)
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for record := range jobs {
+				if err := m.pay(ctx, record); err != nil {
+					mu.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, record := range queued {
+		jobs <- record
+	}
+	close(jobs)
+	wg.Wait()
+	return records, firstErr
+}
+
+// pay submits a queued payout on a leased nonce until it is paid or
+// failed, building it again when its nonce was consumed without it.
+func (m *PayoutManager) pay(ctx context.Context, record *PayoutRecord) error {
+	for attempt := 1; attempt <= m.maxAttempts; attempt++ {
+		lease, err := m.nonces.Acquire(ctx)
+		if err != nil {
+			return err
+		}
+		err = m.submit(ctx, record, lease)
+		if err == nil {
+			err = m.settle(ctx, record)
+		}
+		lease.Release()
+		if err != nil || record.Status != PayoutQueued {
+			return err
+		}
+	}
+	return fmt.Errorf("payout %q: no nonce held after %d attempts", record.ID, m.maxAttempts)
+}
+
+// submit builds the payout on the leased nonce, saves it and sends it.
+func (m *PayoutManager) submit(ctx context.Context, record *PayoutRecord, lease *NonceLease) error {
+	account, _, err := FindAssociatedTokenAddress2022Checked(record.Wallet, m.mint, m.allowOwnerOffCurve)
+	if err != nil {
+		return fmt.Errorf("payout %q: %w", record.ID, err)
+	}
+	create, err := NewCreate2022Instruction(m.feePayer.Pubkey(), record.Wallet, m.mint).
+		SetIdempotent(true).
+		SetAllowOwnerOffCurve(m.allowOwnerOffCurve).
+		ValidateAndBuild()
+	if err != nil {
+		return fmt.Errorf("payout %q: %w", record.ID, err)
+	}
+	builder := NewTxBuilder(m.client).
+		SetFeePayer(m.feePayer.Pubkey()).
+		AddInstruction(
+			create,
+			NewTransferChecked2022Instruction(record.Amount, m.decimals, m.source, m.mint, account, m.authority.Pubkey()).Build(),
+		).
+		AddSigner(uniqueSigners(m.authority, m.feePayer, m.nonceAuthority)...)
+	tx, _, err := lease.Apply(builder).buildAndSign(ctx)
+	if err != nil {
+		return fmt.Errorf("error while building payout %q: %w", record.ID, err)
+	}
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	record.Status = PayoutSubmitted
+	record.NonceAccount = lease.Account
+	record.Nonce = tx.Message.RecentBlockhash
+	record.Signature = tx.Signatures[0]
+	record.Transaction = data
+	record.Attempts++
+	if err := m.save(ctx, record); err != nil {
+		return err
+	}
+	if _, err := sendTransaction(ctx, m.client, tx, rpc.TransactionOpts{}); err != nil {
+		// The record stays submitted: the node may have forwarded the
+		// transaction anyway, and the next Process settles it.
+		logEvent(ctx, m.logger, slog.LevelError, "payout not sent", "id", record.ID, "signature", record.Signature.String(), "error", err)
+		return fmt.Errorf("error while SendTransaction: %w", err)
+	}
+	logEvent(ctx, m.logger, slog.LevelInfo, "payout sent", "id", record.ID, "signature", record.Signature.String(), "nonce_account", lease.Account.String())
+	addMetric(m.metrics, MetricTransactionsSent, 1)
+	return nil
+}
+
+// settle waits for a submitted payout to land, rebroadcasting it while
+// its nonce is unused. It leaves the record paid, failed, or queued when
+// the nonce moved on without it.
+func (m *PayoutManager) settle(ctx context.Context, record *PayoutRecord) error {
+	tx, err := solana.TransactionFromBytes(record.Transaction)
+	if err != nil {
+		return fmt.Errorf("error while decoding payout %q: %w", record.ID, err)
+	}
+	ticker := time.NewTicker(m.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		status, err := m.status(ctx, record.Signature)
+		if err != nil {
+			return err
+		}
+		if status == nil {
+			nonce, err := FetchNonceAccount(ctx, m.client, record.NonceAccount, rpc.CommitmentConfirmed)
+			if err != nil {
+				return fmt.Errorf("error while fetching nonce account %s: %w", record.NonceAccount, err)
+			}
+			if solana.Hash(nonce.Nonce) != record.Nonce {
+				// The nonce moved on. Look again, since the payout may
+				// be what advanced it.
+				if status, err = m.status(ctx, record.Signature); err != nil {
+					return err
+				}
+				if status == nil {
+					return m.requeue(ctx, record)
+				}
+			}
+		}
+		if status != nil {
+			if status.Err != nil {
+				txErr := DecodeTransactionError(record.Signature, status.Err)
+				record.Status = PayoutFailed
+				record.Slot = status.Slot
+				record.Error = txErr.Error()
+				logEvent(ctx, m.logger, slog.LevelError, "payout failed", "id", record.ID, "signature", record.Signature.String(), "error", txErr)
+				addMetric(m.metrics, MetricTransactionsConfirmed, 1, "failed")
+				return m.save(ctx, record)
+			}
+			if reachedCommitment(status.ConfirmationStatus, m.commitment) {
+				record.Status = PayoutPaid
+				record.Slot = status.Slot
+				logEvent(ctx, m.logger, slog.LevelInfo, "payout paid", "id", record.ID, "signature", record.Signature.String(), "slot", status.Slot)
+				addMetric(m.metrics, MetricTransactionsConfirmed, 1, "confirmed")
+				return m.save(ctx, record)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		if status == nil {
+			// Rebroadcasting is safe: the nonce lets it land only once.
+			_, _ = sendTransaction(ctx, m.client, tx, rpc.TransactionOpts{})
+		}
+	}
+}
+
+// requeue forgets the transaction of a payout whose nonce was consumed
+// without it, so that it is built again.
+func (m *PayoutManager) requeue(ctx context.Context, record *PayoutRecord) error {
+	logEvent(ctx, m.logger, slog.LevelWarn, "payout nonce consumed without it, requeueing", "id", record.ID, "signature", record.Signature.String())
+	record.Status = PayoutQueued
+	record.NonceAccount = solana.PublicKey{}
+	record.Nonce = solana.Hash{}
+	record.Signature = solana.Signature{}
+	record.Transaction = nil
+	return m.save(ctx, record)
+}
+
+func (m *PayoutManager) status(ctx context.Context, sig solana.Signature) (*rpc.SignatureStatusesResult, error) {
+	statuses, err := m.client.GetSignatureStatuses(ctx, true, sig)
+	if err != nil {
+		return nil, fmt.Errorf("error while GetSignatureStatuses: %w", err)
+	}
+	if len(statuses.Value) == 0 {
+		return nil, nil
+	}
+	return statuses.Value[0], nil
+}
+
+func (m *PayoutManager) save(ctx context.Context, record *PayoutRecord) error {
+	record.UpdatedAt = time.Now()
+	if err := m.store.Save(ctx, record); err != nil {
+		return fmt.Errorf("error while saving payout %q: %w", record.ID, err)
+	}
+	return nil
+}
+
+// uniqueSigners drops signers listed more than once.
+func uniqueSigners(signers ...Signer) []Signer {
+	seen := map[solana.PublicKey]bool{}
+	var out []Signer
+	for _, signer := range signers {
+		if signer == nil || seen[signer.Pubkey()] {
+			continue
+		}
+		seen[signer.Pubkey()] = true
+		out = append(out, signer)
+	}
+	return out
+}
@@ -0,0 +1,173 @@
+package token2022
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+type payoutFixture struct {
+	client       *mockRPC
+	store        *MemoryPayoutStore
+	authority    solana.PrivateKey
+	nonceAccount solana.PublicKey
+}
+
+func newPayoutFixture(t *testing.T) *payoutFixture {
+	t.Helper()
+	f := &payoutFixture{
+		client:       newMockRPC(),
+		store:        NewMemoryPayoutStore(),
+		authority:    solana.NewWallet().PrivateKey,
+		nonceAccount: solana.MustPublicKeyFromBase58("83mctxW8BCh6nPGjxx4jmyaEfbpcMZpLQiv7tXVSAV7a"),
+	}
+	f.advanceNonce(t, solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin"))
+	return f
+}
+
+func (f *payoutFixture) advanceNonce(t *testing.T, nonce solana.PublicKey) {
+	f.client.setAccount(f.nonceAccount, solana.SystemProgramID, encodeNonceAccount(t, f.authority.PublicKey(), nonce))
+}
+
+func (f *payoutFixture) manager() *PayoutManager {
+	var (
+		mint   = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+	)
+	nonces := NewNonceManager(f.authority.PublicKey(), f.nonceAccount)
+	return NewPayoutManager(f.client, f.store, nonces, mint, 6, source, NewPrivateKeySigner(f.authority)).
+		SetPollInterval(time.Millisecond)
+}
+
+// land makes every sent transaction confirmed and consume the nonce.
+func (f *payoutFixture) land(t *testing.T) {
+	f.client.onSend = func(tx *solana.Transaction) {
+		if _, ok := f.client.statuses[tx.Signatures[0]]; ok {
+			return
+		}
+		f.client.statuses[tx.Signatures[0]] = &rpc.SignatureStatusesResult{Slot: 42, ConfirmationStatus: rpc.ConfirmationStatusConfirmed}
+		f.advanceNonce(t, solana.PublicKey(tx.Signatures[0][:32]))
+	}
+}
+
+func TestPayoutManagerPays(t *testing.T) {
+
+	f := newPayoutFixture(t)
+	f.land(t)
+	manager := f.manager()
+
+	wallet := solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	other := solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR")
+	for _, payout := range []Payout{
+		{ID: "w-1", Recipient: Recipient{Wallet: wallet, Amount: 100}},
+		{ID: "w-2", Recipient: Recipient{Wallet: other, Amount: 200}},
+		{ID: "w-1", Recipient: Recipient{Wallet: wallet, Amount: 100}},
+	} {
+		if _, err := manager.Enqueue(context.Background(), payout); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+
+	records, err := manager.Process(context.Background())
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 payouts, got %d", len(records))
+	}
+	for _, record := range records {
+		if record.Status != PayoutPaid || record.Attempts != 1 || record.NonceAccount != f.nonceAccount {
+			t.Errorf("Expected %s paid on the nonce account, got %+v", record.ID, record)
+		}
+	}
+	if len(f.client.sent) != 2 {
+		t.Errorf("Expected 2 transactions, got %d", len(f.client.sent))
+	}
+	if records[0].Nonce == records[1].Nonce {
+		t.Error("Expected each payout on its own nonce")
+	}
+
+	if records, err := manager.Process(context.Background()); err != nil || len(records) != 0 {
+		t.Errorf("Expected nothing left to pay, got %d records and %v", len(records), err)
+	}
+	if _, err := manager.Enqueue(context.Background(), Payout{ID: "w-3"}); !errors.Is(err, ErrNotSet) {
+		t.Errorf("Expected ErrNotSet for a payout without wallet, got %v", err)
+	}
+	pda, _, _ := FindAssociatedTokenAddress2022(wallet, other)
+	if _, err := manager.Enqueue(context.Background(), Payout{ID: "w-4", Recipient: Recipient{Wallet: pda, Amount: 100}}); !errors.Is(err, ErrOwnerOffCurve) {
+		t.Errorf("Expected ErrOwnerOffCurve for a wallet off the curve, got %v", err)
+	}
+	if record, _ := f.store.Load(context.Background(), "w-4"); record != nil {
+		t.Errorf("Expected the off-curve payout not to be queued, got %+v", record)
+	}
+}
+
+func TestPayoutManagerRecoversLanded(t *testing.T) {
+
+	f := newPayoutFixture(t)
+	wallet := solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	if _, err := f.manager().Enqueue(context.Background(), Payout{ID: "w-1", Recipient: Recipient{Wallet: wallet, Amount: 100}}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	// The worker crashes after submitting; the transaction lands meanwhile.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := f.manager().Process(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected deadline exceeded, got %v", err)
+	}
+	record, _ := f.store.Load(context.Background(), "w-1")
+	if record.Status != PayoutSubmitted {
+		t.Fatalf("Expected the payout submitted, got %s", record.Status)
+	}
+	f.client.statuses[record.Signature] = &rpc.SignatureStatusesResult{Slot: 50, ConfirmationStatus: rpc.ConfirmationStatusConfirmed}
+	f.advanceNonce(t, solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR"))
+	submitted := len(f.client.sent)
+
+	records, err := f.manager().Process(context.Background())
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if len(records) != 1 || records[0].Status != PayoutPaid || records[0].Signature != record.Signature || records[0].Slot != 50 {
+		t.Errorf("Expected the submitted transaction paid, got %+v", records[0])
+	}
+	if len(f.client.sent) != submitted {
+		t.Errorf("Expected no new transaction, got %d", len(f.client.sent)-submitted)
+	}
+}
+
+func TestPayoutManagerRequeuesConsumedNonce(t *testing.T) {
+
+	f := newPayoutFixture(t)
+	wallet := solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	if _, err := f.manager().Enqueue(context.Background(), Payout{ID: "w-1", Recipient: Recipient{Wallet: wallet, Amount: 100}}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := f.manager().Process(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected deadline exceeded, got %v", err)
+	}
+	first, _ := f.store.Load(context.Background(), "w-1")
+
+	// Something else consumed the nonce: the transaction can never land
+	// and the payout is built again.
+	f.advanceNonce(t, solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR"))
+	f.land(t)
+
+	records, err := f.manager().Process(context.Background())
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	record := records[0]
+	if record.Status != PayoutPaid || record.Attempts != 2 || record.Signature == first.Signature {
+		t.Errorf("Expected a second transaction paid, got %+v", record)
+	}
+	if record.Nonce != solana.Hash(solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR")) {
+		t.Errorf("Expected the new nonce, got %s", record.Nonce)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"fmt"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// PermanentDelegateBuilder builds transfers and burns signed by the
+// permanent delegate of a mint instead of the owner of the token account.
+type PermanentDelegateBuilder struct {
+	Mint     solana.PublicKey
+	Decoded  *Mint
+	Delegate solana.PublicKey
+	// Signers are the signers of a multisig delegate.
+	Signers []solana.PublicKey
+}
+
+// NewPermanentDelegateBuilder creates a PermanentDelegateBuilder for mint,
+// whose decoded account is decoded, with delegate as the signing
+// authority.
+func NewPermanentDelegateBuilder(mint solana.PublicKey, decoded *Mint, delegate solana.PublicKey, multisigSigners ...solana.PublicKey) *PermanentDelegateBuilder {
+	return &PermanentDelegateBuilder{Mint: mint, Decoded: decoded, Delegate: delegate, Signers: multisigSigners}
+}
+
+// Validate checks that the mint carries the PermanentDelegate extension
+// and that Delegate is its permanent delegate.
+func (b *PermanentDelegateBuilder) Validate() error {
+	if b.Mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	if b.Decoded == nil {
+		return errNotSet("Decoded")
+	}
+	if b.Delegate.IsZero() {
+		return errNotSet("Delegate")
+	}
+	delegate, ok, err := b.Decoded.PermanentDelegate()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("mint %s has no permanent delegate extension", b.Mint)
+	}
+	if delegate == nil {
+		return fmt.Errorf("mint %s has no permanent delegate", b.Mint)
+	}
+	if !delegate.Equals(b.Delegate) {
+		return fmt.Errorf("%s is not the permanent delegate of mint %s, %s is", b.Delegate, b.Mint, delegate)
+	}
+	return validateMultisigSigners(b.Signers)
+}
+
+// Transfer returns a TransferChecked instruction moving amount from source
+// to destination, signed by the permanent delegate.
+func (b *PermanentDelegateBuilder) Transfer(amount uint64, source, destination solana.PublicKey) (*TransferChecked2022, error) {
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	inst := NewTransferChecked2022Instruction(amount, b.Decoded.Decimals, source, b.Mint, destination, b.Delegate, b.Signers...)
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst, nil
+}
+
+// Burn returns a BurnChecked instruction burning amount from account,
+// signed by the permanent delegate.
+func (b *PermanentDelegateBuilder) Burn(amount uint64, account solana.PublicKey) (*BurnChecked2022, error) {
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	inst := NewBurnChecked2022Instruction(amount, b.Decoded.Decimals, account, b.Mint, b.Delegate, b.Signers...)
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst, nil
+}
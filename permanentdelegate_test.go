@@ -0,0 +1,47 @@
+package token2022
+
+import (
+	"strings"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+func TestPermanentDelegateBuilder(t *testing.T) {
+	var (
+		delegate    = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+	)
+	decoded := &Mint{Decimals: 6, IsInitialized: true}
+
+	if _, err := NewPermanentDelegateBuilder(mint, decoded, delegate).Transfer(1, source, destination); err == nil || !strings.Contains(err.Error(), "no permanent delegate extension") {
+		t.Errorf("Expected a missing extension error, got %v", err)
+	}
+
+	decoded.Extensions = []Extension{{Type: ExtensionPermanentDelegate, Data: delegate.Bytes()}}
+	if _, err := NewPermanentDelegateBuilder(mint, decoded, destination).Burn(1, source); err == nil || !strings.Contains(err.Error(), "is not the permanent delegate") {
+		t.Errorf("Expected a delegate mismatch error, got %v", err)
+	}
+
+	transfer, err := NewPermanentDelegateBuilder(mint, decoded, delegate).Transfer(250, source, destination)
+	if err != nil {
+		t.Fatalf("Transfer: %v", err)
+	}
+	if transfer.Owner != delegate || transfer.Decimals != 6 || transfer.Amount != 250 || transfer.Source != source {
+		t.Errorf("Unexpected transfer %+v", transfer)
+	}
+	burn, err := NewPermanentDelegateBuilder(mint, decoded, delegate).Burn(100, source)
+	if err != nil {
+		t.Fatalf("Burn: %v", err)
+	}
+	if accounts := burn.Build().Accounts(); accounts[2].PublicKey != delegate || !accounts[2].IsSigner {
+		t.Errorf("Expected the delegate to sign the burn, got %+v", accounts[2])
+	}
+
+	decoded.Extensions[0].Data = make([]byte, 32)
+	if err := NewPermanentDelegateBuilder(mint, decoded, delegate).Validate(); err == nil || !strings.Contains(err.Error(), "has no permanent delegate") {
+		t.Errorf("Expected an unset delegate error, got %v", err)
+	}
+}
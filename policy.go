@@ -0,0 +1,284 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"fmt"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// PolicyOperation is a token instruction of a transaction, as inspected by
+// a Policy. The fields an instruction does not have are left
+// zero: an unchecked Transfer has no Mint, and only approvals have a
+// Delegate.
+type PolicyOperation struct {
+	// Index is the position of the instruction in the list given to
+	// PolicyOperations. For a TxBuilder it is the order of AddInstruction,
+	// before any AdvanceNonceAccount is prepended.
+	Index int
+	// ProgramID is Token-2022 or the original SPL Token program.
+	ProgramID solana.PublicKey
+	// Name is the instruction name, such as "TransferChecked".
+	Name string
+	// Instruction is the decoded builder, or nil when the instruction is
+	// not supported by this package. CheckPolicies rejects those.
+	Instruction TypedInstruction
+	Mint        solana.PublicKey
+	Source      solana.PublicKey
+	// Destination is the credited token account of transfers, mints and
+	// withheld fee withdrawals, the new authority of SetAuthority, and the
+	// lamport recipient of CloseAccount and WithdrawExcessLamports.
+	Destination solana.PublicKey
+	Delegate    solana.PublicKey
+	Authority   solana.PublicKey
+	Amount      uint64
+}
+
+// PolicyOperations decodes the instructions of Token-2022 and of the
+// original SPL Token program, which shares the layouts of the instructions
+// up to UiAmountToAmount, among instructions. Instructions of other
+// programs are skipped.
+func PolicyOperations(instructions []solana.Instruction) ([]*PolicyOperation, error) {
+	var ops []*PolicyOperation
+	for i, inst := range instructions {
+		programID := inst.ProgramID()
+		legacy := programID.Equals(solana.TokenProgramID)
+		if !legacy && !programID.Equals(solana.Token2022ProgramID) {
+			continue
+		}
+		data, err := inst.Data()
+		if err != nil {
+			return nil, fmt.Errorf("error while encoding instruction %d: %w", i, err)
+		}
+		op := &PolicyOperation{Index: i, ProgramID: programID, Name: InstructionName(data)}
+		if legacy && len(data) > 0 && data[0] > InstructionUiAmountToAmount {
+			// Token-2022 extension tags mean nothing to the original
+			// program; leave them undecoded so they are rejected.
+			ops = append(ops, op)
+			continue
+		}
+		if decoded, err := DecodeInstruction(inst.Accounts(), data); err == nil {
+			op.Instruction = decoded
+			op.describe()
+		}
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
+
+// describe copies the accounts and amount of the decoded instruction.
+func (op *PolicyOperation) describe() {
+	switch inst := op.Instruction.(type) {
+	case *Transfer2022:
+		op.Source, op.Destination, op.Authority, op.Amount = inst.Source, inst.Destination, inst.Owner, inst.Amount
+	case *TransferChecked2022:
+		op.Source, op.Mint, op.Destination, op.Authority, op.Amount = inst.Source, inst.Mint, inst.Destination, inst.Owner, inst.Amount
+	case *TransferCheckedWithFee2022:
+		op.Source, op.Mint, op.Destination, op.Authority, op.Amount = inst.Source, inst.Mint, inst.Destination, inst.Owner, inst.Amount
+	case *MintTo2022:
+		op.Mint, op.Destination, op.Authority, op.Amount = inst.Mint, inst.Destination, inst.MintAuthority, inst.Amount
+	case *MintToChecked2022:
+		op.Mint, op.Destination, op.Authority, op.Amount = inst.Mint, inst.Destination, inst.MintAuthority, inst.Amount
+	case *Burn2022:
+		op.Source, op.Mint, op.Authority, op.Amount = inst.Account, inst.Mint, inst.Owner, inst.Amount
+	case *BurnChecked2022:
+		op.Source, op.Mint, op.Authority, op.Amount = inst.Account, inst.Mint, inst.Owner, inst.Amount
+	case *Approve2022:
+		op.Source, op.Delegate, op.Authority, op.Amount = inst.Source, inst.Delegate, inst.Owner, inst.Amount
+	case *ApproveChecked2022:
+		op.Source, op.Mint, op.Delegate, op.Authority, op.Amount = inst.Source, inst.Mint, inst.Delegate, inst.Owner, inst.Amount
+	case *SetAuthority2022:
+		// A new account owner takes the whole balance, so the new
+		// authority is screened like a destination.
+		op.Source, op.Authority = inst.Account, inst.Authority
+		if inst.AuthorityType != AuthorityAccountOwner && inst.AuthorityType != AuthorityCloseAccount {
+			op.Source, op.Mint = solana.PublicKey{}, inst.Account
+		}
+		if inst.NewAuthority != nil {
+			op.Destination = *inst.NewAuthority
+		}
+	case *Close2022:
+		op.Source, op.Destination, op.Authority = inst.Account, inst.Destination, inst.Owner
+	case *WithdrawExcessLamports2022:
+		op.Source, op.Destination, op.Authority = inst.Source, inst.Destination, inst.Authority
+	case *WithdrawWithheldTokensFromMint2022:
+		op.Mint, op.Destination, op.Authority = inst.Mint, inst.Destination, inst.WithdrawWithheldAuthority
+	case *WithdrawWithheldTokensFromAccounts2022:
+		op.Mint, op.Destination, op.Authority = inst.Mint, inst.Destination, inst.WithdrawWithheldAuthority
+	}
+}
+
+// PolicyAction is the verdict of a Policy on one operation.
+type PolicyAction int
+
+const (
+	PolicyAllow PolicyAction = iota
+	// PolicyFlag lets the transaction through and reports the operation
+	// to the flag handler of the TxBuilder, for example for manual review
+	// after the fact.
+	PolicyFlag
+	// PolicyReject stops the TxBuilder before the transaction is signed.
+	PolicyReject
+)
+
+var policyActionNames = []string{"allow", "flag", "reject"}
+
+func (a PolicyAction) String() string {
+	if a >= 0 && int(a) < len(policyActionNames) {
+		return policyActionNames[a]
+	}
+	return fmt.Sprintf("PolicyAction(%d)", int(a))
+}
+
+// PolicyVerdict is the decision of a Policy with its reason, such as
+// "destination is on the deny list".
+type PolicyVerdict struct {
+	Action PolicyAction
+	Reason string
+}
+
+// Policy inspects the token instructions of a transaction before it
+// is signed. Custodial integrators plug their own rules, such as
+// sanctions screening or withdrawal limits, into a TxBuilder with
+// SetPolicy. An error aborts the build.
+type Policy interface {
+	Check(ctx context.Context, op *PolicyOperation) (PolicyVerdict, error)
+}
+
+// PolicyFunc adapts a function to Policy.
+type PolicyFunc func(ctx context.Context, op *PolicyOperation) (PolicyVerdict, error)
+
+func (f PolicyFunc) Check(ctx context.Context, op *PolicyOperation) (PolicyVerdict, error) {
+	return f(ctx, op)
+}
+
+// PolicyViolation is a flagged or rejected operation. When Action is
+// PolicyReject it is the error of the TxBuilder and matches
+// ErrPolicyRejected.
+type PolicyViolation struct {
+	Operation *PolicyOperation
+	Action    PolicyAction
+	Reason    string
+}
+
+func (v *PolicyViolation) Error() string {
+	verb := "flagged"
+	if v.Action == PolicyReject {
+		verb = "rejected"
+	}
+	return fmt.Sprintf("instruction %d (%s) %s by policy: %s", v.Operation.Index, v.Operation.Name, verb, v.Reason)
+}
+
+// Is reports whether target is ErrPolicyRejected and the operation was
+// rejected.
+func (v *PolicyViolation) Is(target error) bool {
+	return target == ErrPolicyRejected && v.Action == PolicyReject
+}
+
+// CheckPolicies runs every policy on every operation and returns the
+// flagged operations. The first rejection is returned as a
+// *PolicyViolation error. Operations that could not be decoded are
+// rejected without consulting the policies, which could not inspect them.
+func CheckPolicies(ctx context.Context, ops []*PolicyOperation, policies ...Policy) ([]*PolicyViolation, error) {
+	var flagged []*PolicyViolation
+	for _, op := range ops {
+		if op.Instruction == nil && len(policies) > 0 {
+			return nil, &PolicyViolation{Operation: op, Action: PolicyReject, Reason: "instruction cannot be inspected"}
+		}
+		for _, policy := range policies {
+			verdict, err := policy.Check(ctx, op)
+			if err != nil {
+				return nil, fmt.Errorf("error while checking instruction %d (%s): %w", op.Index, op.Name, err)
+			}
+			switch verdict.Action {
+			case PolicyAllow:
+			case PolicyFlag:
+				flagged = append(flagged, &PolicyViolation{Operation: op, Action: PolicyFlag, Reason: verdict.Reason})
+			default:
+				return nil, &PolicyViolation{Operation: op, Action: PolicyReject, Reason: verdict.Reason}
+			}
+		}
+	}
+	return flagged, nil
+}
+
+// ListPolicy is a Policy of allow and deny lists with amount limits. Empty
+// lists and zero limits are not enforced. Destination rules apply to the
+// credited token account of transfers and mints, to the delegate of
+// approvals, to new authorities and to the recipient of closed accounts,
+// as all of them receive control of tokens or lamports.
+type ListPolicy struct {
+	// AllowedMints, when set, are the only mints instructions may name.
+	// Unchecked Transfer, CloseAccount and token account SetAuthority
+	// instructions, which name no mint, are rejected.
+	AllowedMints []solana.PublicKey
+	DeniedMints  []solana.PublicKey
+	// AllowedDestinations, when set, are the only token accounts,
+	// delegates, authorities and lamport recipients tokens may go to.
+	AllowedDestinations []solana.PublicKey
+	DeniedDestinations  []solana.PublicKey
+	// MaxAmount rejects transfers, mints, burns and approvals of more raw
+	// units than this.
+	MaxAmount uint64
+	// FlagAmount flags those of more raw units than this.
+	FlagAmount uint64
+}
+
+var _ Policy = (*ListPolicy)(nil)
+
+func (p *ListPolicy) Check(ctx context.Context, op *PolicyOperation) (PolicyVerdict, error) {
+	reject := func(reason string, args ...any) (PolicyVerdict, error) {
+		return PolicyVerdict{Action: PolicyReject, Reason: fmt.Sprintf(reason, args...)}, nil
+	}
+	moves := op.Amount > 0 || !op.Destination.IsZero() || !op.Delegate.IsZero()
+	if !op.Mint.IsZero() && containsKey(p.DeniedMints, op.Mint) {
+		return reject("mint %s is denied", op.Mint)
+	}
+	if len(p.AllowedMints) > 0 && moves && !containsKey(p.AllowedMints, op.Mint) {
+		if op.Mint.IsZero() {
+			return reject("instruction names no mint")
+		}
+		return reject("mint %s is not allowed", op.Mint)
+	}
+	for _, destination := range []solana.PublicKey{op.Destination, op.Delegate} {
+		if destination.IsZero() {
+			continue
+		}
+		if containsKey(p.DeniedDestinations, destination) {
+			return reject("destination %s is denied", destination)
+		}
+		if len(p.AllowedDestinations) > 0 && !containsKey(p.AllowedDestinations, destination) {
+			return reject("destination %s is not allowed", destination)
+		}
+	}
+	if p.MaxAmount > 0 && op.Amount > p.MaxAmount {
+		return reject("amount %d exceeds the limit of %d", op.Amount, p.MaxAmount)
+	}
+	if p.FlagAmount > 0 && op.Amount > p.FlagAmount {
+		return PolicyVerdict{Action: PolicyFlag, Reason: fmt.Sprintf("amount %d exceeds %d", op.Amount, p.FlagAmount)}, nil
+	}
+	return PolicyVerdict{}, nil
+}
+
+func containsKey(keys []solana.PublicKey, key solana.PublicKey) bool {
+	for _, k := range keys {
+		if k.Equals(key) {
+			return true
+		}
+	}
+	return false
+}
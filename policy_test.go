@@ -0,0 +1,178 @@
+package token2022
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	system "github.com/gagliardetto/solana-go/programs/system"
+)
+
+func TestListPolicy(t *testing.T) {
+	var (
+		wallet      = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		sanctioned  = solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR")
+	)
+	policy := &ListPolicy{
+		AllowedMints:       []solana.PublicKey{mint},
+		DeniedDestinations: []solana.PublicKey{sanctioned},
+		MaxAmount:          1_000_000,
+		FlagAmount:         10_000,
+	}
+	tests := []struct {
+		name   string
+		inst   solana.Instruction
+		action PolicyAction
+	}{
+		{"allowed transfer", NewTransferChecked2022Instruction(100, 6, source, mint, destination, wallet).Build(), PolicyAllow},
+		{"large transfer", NewTransferChecked2022Instruction(50_000, 6, source, mint, destination, wallet).Build(), PolicyFlag},
+		{"over the limit", NewTransferChecked2022Instruction(2_000_000, 6, source, mint, destination, wallet).Build(), PolicyReject},
+		{"denied destination", NewTransferChecked2022Instruction(100, 6, source, mint, sanctioned, wallet).Build(), PolicyReject},
+		{"denied delegate", NewApprove2022Instruction(100, source, sanctioned, wallet).Build(), PolicyReject},
+		{"other mint", NewMintTo2022Instruction(100, sanctioned, destination, wallet).Build(), PolicyReject},
+		{"unchecked transfer", NewTransfer2022Instruction(100, source, destination, wallet).Build(), PolicyReject},
+		{"revoke", NewRevoke2022Instruction(source, wallet).Build(), PolicyAllow},
+		{"owner change to denied wallet", NewSetAuthority2022Instruction(AuthorityAccountOwner, &sanctioned, source, wallet).Build(), PolicyReject},
+		{"close to denied wallet", NewClose2022Instruction(source, sanctioned, wallet).Build(), PolicyReject},
+		{"fees from mint to denied account", NewWithdrawWithheldTokensFromMint2022Instruction(mint, sanctioned, wallet).Build(), PolicyReject},
+		{"fees from accounts to denied account", NewWithdrawWithheldTokensFromAccounts2022Instruction(mint, sanctioned, []solana.PublicKey{source}, wallet).Build(), PolicyReject},
+		{"fees to treasury", NewWithdrawWithheldTokensFromMint2022Instruction(mint, destination, wallet).Build(), PolicyAllow},
+	}
+	for _, tt := range tests {
+		ops, err := PolicyOperations([]solana.Instruction{tt.inst})
+		if err != nil {
+			t.Fatalf("%s: PolicyOperations: %v", tt.name, err)
+		}
+		if len(ops) != 1 {
+			t.Fatalf("%s: Expected one operation, got %d", tt.name, len(ops))
+		}
+		verdict, err := policy.Check(context.Background(), ops[0])
+		if err != nil {
+			t.Fatalf("%s: Check: %v", tt.name, err)
+		}
+		if verdict.Action != tt.action {
+			t.Errorf("%s: Expected %s, got %s (%s)", tt.name, tt.action, verdict.Action, verdict.Reason)
+		}
+	}
+}
+
+func TestTxBuilderPolicy(t *testing.T) {
+	var (
+		wallet      = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		client      = newMockRPC()
+	)
+	var flagged []*PolicyViolation
+	builder := NewTxBuilder(client).
+		SetFeePayer(wallet).
+		SetPolicy(&ListPolicy{FlagAmount: 10, AllowedDestinations: []solana.PublicKey{destination}}).
+		OnPolicyFlag(func(ctx context.Context, violation *PolicyViolation) { flagged = append(flagged, violation) }).
+		AddInstruction(
+			system.NewTransferInstruction(1, wallet, destination).Build(),
+			NewTransferChecked2022Instruction(100, 6, source, mint, destination, wallet).Build(),
+		)
+	if _, err := builder.Build(context.Background()); err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if len(flagged) != 1 || flagged[0].Operation.Index != 1 || flagged[0].Operation.Amount != 100 {
+		t.Fatalf("Expected the token transfer to be flagged, got %+v", flagged)
+	}
+
+	calls := client.calls["getLatestBlockhash"]
+	builder.AddInstruction(NewTransferChecked2022Instruction(1, 6, source, mint, wallet, wallet).Build())
+	_, err := builder.Build(context.Background())
+	var violation *PolicyViolation
+	if !errors.Is(err, ErrPolicyRejected) || !errors.As(err, &violation) || violation.Operation.Index != 2 {
+		t.Fatalf("Expected the third instruction to be rejected, got %v", err)
+	}
+	if client.calls["getLatestBlockhash"] != calls {
+		t.Errorf("Expected no blockhash to be fetched for a rejected transaction")
+	}
+	if errors.Is(flagged[0], ErrPolicyRejected) {
+		t.Errorf("Expected a flag not to match ErrPolicyRejected")
+	}
+}
+
+func TestListPolicyAllowedDestinations(t *testing.T) {
+	var (
+		wallet      = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		other       = solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR")
+	)
+	policy := &ListPolicy{AllowedDestinations: []solana.PublicKey{destination}}
+	tests := []struct {
+		name   string
+		inst   solana.Instruction
+		action PolicyAction
+	}{
+		{"owner change", NewSetAuthority2022Instruction(AuthorityAccountOwner, &other, source, wallet).Build(), PolicyReject},
+		{"owner change to allowed", NewSetAuthority2022Instruction(AuthorityAccountOwner, &destination, source, wallet).Build(), PolicyAllow},
+		{"close authority change", NewSetAuthority2022Instruction(AuthorityCloseAccount, &other, source, wallet).Build(), PolicyReject},
+		{"mint authority removal", NewSetAuthority2022Instruction(AuthorityMintTokens, nil, mint, wallet).Build(), PolicyAllow},
+		{"close", NewClose2022Instruction(source, other, wallet).Build(), PolicyReject},
+		{"close to allowed", NewClose2022Instruction(source, destination, wallet).Build(), PolicyAllow},
+	}
+	for _, tt := range tests {
+		ops, err := PolicyOperations([]solana.Instruction{tt.inst})
+		if err != nil {
+			t.Fatalf("%s: PolicyOperations: %v", tt.name, err)
+		}
+		verdict, err := policy.Check(context.Background(), ops[0])
+		if err != nil {
+			t.Fatalf("%s: Check: %v", tt.name, err)
+		}
+		if verdict.Action != tt.action {
+			t.Errorf("%s: Expected %s, got %s (%s)", tt.name, tt.action, verdict.Action, verdict.Reason)
+		}
+	}
+}
+
+func TestPolicyOperationsLegacyProgram(t *testing.T) {
+	var (
+		wallet     = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint       = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source     = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		sanctioned = solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR")
+	)
+	policy := &ListPolicy{DeniedDestinations: []solana.PublicKey{sanctioned}}
+	ops, err := PolicyOperations([]solana.Instruction{
+		NewTransferChecked2022Instruction(100, 6, source, mint, sanctioned, wallet).Build().WithProgramID(solana.TokenProgramID),
+	})
+	if err != nil {
+		t.Fatalf("PolicyOperations: %v", err)
+	}
+	if len(ops) != 1 || ops[0].Instruction == nil || !ops[0].ProgramID.Equals(solana.TokenProgramID) {
+		t.Fatalf("Expected the SPL Token transfer to be decoded, got %+v", ops)
+	}
+	if _, err := CheckPolicies(context.Background(), ops, policy); !errors.Is(err, ErrPolicyRejected) {
+		t.Errorf("Expected the SPL Token transfer to a denied destination to be rejected, got %v", err)
+	}
+
+	// Extension instructions do not exist in the original program and
+	// cannot be inspected.
+	ops, err = PolicyOperations([]solana.Instruction{
+		NewInitializeMintCloseAuthority2022Instruction(&wallet, mint).Build().WithProgramID(solana.TokenProgramID),
+		solana.NewInstruction(solana.Token2022ProgramID, solana.AccountMetaSlice{}, []byte{255}),
+	})
+	if err != nil {
+		t.Fatalf("PolicyOperations: %v", err)
+	}
+	if len(ops) != 2 {
+		t.Fatalf("Expected two operations, got %d", len(ops))
+	}
+	for _, op := range ops {
+		_, err := CheckPolicies(context.Background(), []*PolicyOperation{op}, policy)
+		var violation *PolicyViolation
+		if !errors.As(err, &violation) || violation.Action != PolicyReject {
+			t.Errorf("Expected instruction %d (%s) to be rejected, got %v", op.Index, op.Name, err)
+		}
+	}
+}
@@ -0,0 +1,361 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// ErrMemoRequired is returned by TransferPreflight when the destination
+// requires a memo and none was set.
+var ErrMemoRequired = errors.New("destination account requires a memo")
+
+// TransferClient is the set of RPC calls used by TransferPreflight: the
+// epoch selects the transfer fee of mints with the TransferFeeConfig
+// extension. *rpc.Client satisfies it.
+type TransferClient interface {
+	RPCClient
+	EpochClient
+}
+
+var _ TransferClient = (*rpc.Client)(nil)
+
+// TransferPreflight checks the accounts of a Token-2022 transfer before it
+// is sent, and adds the instructions the program would otherwise fail the
+// transfer without.
+type TransferPreflight struct {
+	client      TransferClient
+	commitment  rpc.CommitmentType
+	memo        string
+	memoSigners []solana.PublicKey
+	viaCPI      bool
+	authority   solana.PublicKey
+	signers     []solana.PublicKey
+}
+
+// NewTransferPreflight creates a TransferPreflight reading accounts at the
+// confirmed commitment.
+func NewTransferPreflight(client TransferClient) *TransferPreflight {
+	return &TransferPreflight{client: client, commitment: rpc.CommitmentConfirmed}
+}
+
+// SetCommitment sets the commitment accounts are read at.
+func (p *TransferPreflight) SetCommitment(commitment rpc.CommitmentType) *TransferPreflight {
+	p.commitment = commitment
+	return p
+}
+
+// SetMemo sets the memo prepended to transfers whose destination has
+// required memos enabled through its MemoTransfer extension. Signers, if
+// any, must sign the memo instruction.
+func (p *TransferPreflight) SetMemo(memo string, signers ...solana.PublicKey) *TransferPreflight {
+	p.memo = memo
+	p.memoSigners = signers
+	return p
+}
+
+// SetInvokedViaCPI tells the preflight that the transfer will be invoked
+// by another program through CPI rather than included in the transaction
+// as is, which the CpiGuard extension of the source account may block.
+func (p *TransferPreflight) SetInvokedViaCPI(viaCPI bool) *TransferPreflight {
+	p.viaCPI = viaCPI
+	return p
+}
+
+// SetAuthority sets the authority signing transfers built by PlanTransfer,
+// such as a delegate. It defaults to the owner of the source account.
+// multisigSigners are the signers of a multisig authority.
+func (p *TransferPreflight) SetAuthority(authority solana.PublicKey, multisigSigners ...solana.PublicKey) *TransferPreflight {
+	p.authority = authority
+	p.signers = multisigSigners
+	return p
+}
+
+// Prepare returns the instructions to send in place of transfer, which
+// must be a Transfer, TransferChecked or TransferCheckedWithFee
+// instruction. When the destination requires memos, a memo instruction
+// immediately precedes the transfer, as the program checks; it returns
+// ErrMemoRequired if no memo was set. A destination that does not exist
+// yet, such as an associated token account created in the same
+// transaction, is assumed not to require memos. When the transfer is
+// invoked via CPI, it also returns a *CpiGuardError if the CpiGuard
+// extension of the source account blocks it.
+func (p *TransferPreflight) Prepare(ctx context.Context, transfer solana.Instruction) ([]solana.Instruction, error) {
+	decoded, err := decodeTransfer(transfer)
+	if err != nil {
+		return nil, err
+	}
+	source, destination := transferAccounts(decoded)
+	if p.viaCPI {
+		account, err := FetchTokenAccount(ctx, p.client, source, p.commitment)
+		if err != nil {
+			return nil, fmt.Errorf("error while fetching source account: %w", err)
+		}
+		if err := CheckCpiGuard(account, source, decoded, true); err != nil {
+			return nil, err
+		}
+	}
+	memo, err := p.memoFor(ctx, destination)
+	if err != nil {
+		return nil, err
+	}
+	if memo == nil {
+		return []solana.Instruction{transfer}, nil
+	}
+	return []solana.Instruction{memo, transfer}, nil
+}
+
+// memoFor returns the memo instruction required by destination, or nil
+// when it does not require memos.
+func (p *TransferPreflight) memoFor(ctx context.Context, destination solana.PublicKey) (solana.Instruction, error) {
+	account, err := FetchTokenAccount(ctx, p.client, destination, p.commitment)
+	if errors.Is(err, rpc.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching destination account: %w", err)
+	}
+	required, err := account.RequiresIncomingTransferMemos()
+	if err != nil || !required {
+		return nil, err
+	}
+	if p.memo == "" {
+		return nil, fmt.Errorf("%w: %s", ErrMemoRequired, destination)
+	}
+	return NewMemoInstruction(p.memo, p.memoSigners...), nil
+}
+
+// TransferPlan is the result of TransferPreflight.PlanTransfer.
+type TransferPlan struct {
+	// Instructions are the instructions to send: the memo, when the
+	// destination requires one, then the transfer.
+	Instructions []solana.Instruction
+	// Transfer is the transfer builder: a *TransferCheckedWithFee2022 for
+	// mints with a transfer fee, a *TransferChecked2022 otherwise.
+	Transfer TypedInstruction
+	Decimals uint8
+	// Fee is the transfer fee withheld from the amount at the current
+	// epoch. The destination receives the amount minus Fee.
+	Fee uint64
+	// HookProgram is the transfer hook program of the mint, whose accounts
+	// are appended to the transfer, or nil.
+	HookProgram *solana.PublicKey
+	// Memo reports that the destination requires a memo, which
+	// Instructions include.
+	Memo bool
+}
+
+// PlanTransfer returns the instructions transferring amount from the
+// source token account to the destination token account, after reading
+// the mint's extensions. Mints with a transfer fee get a
+// TransferCheckedWithFee instruction asserting the fee of the current
+// epoch; other mints get TransferChecked. The accounts of the mint's
+// transfer hook are resolved and the memo required by the destination is
+// added, failing with ErrMemoRequired when none was set. When the transfer
+// is invoked via CPI, a *CpiGuardError is returned if the source account
+// blocks it.
+func (p *TransferPreflight) PlanTransfer(ctx context.Context, mint, source, destination solana.PublicKey, amount uint64) (*TransferPlan, error) {
+	decoded, err := FetchMint(ctx, p.client, mint, p.commitment)
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching mint: %w", err)
+	}
+	authority := p.authority
+	var sourceAccount *TokenAccount
+	if authority.IsZero() || p.viaCPI {
+		if sourceAccount, err = FetchTokenAccount(ctx, p.client, source, p.commitment); err != nil {
+			return nil, fmt.Errorf("error while fetching source account: %w", err)
+		}
+		if !sourceAccount.Mint.Equals(mint) {
+			return nil, fmt.Errorf("source account %s is for mint %s, not %s", source, sourceAccount.Mint, mint)
+		}
+		if authority.IsZero() {
+			authority = sourceAccount.Owner
+		}
+	}
+
+	plan := &TransferPlan{Decimals: decoded.Decimals}
+	feeConfig, hasFee, err := decoded.TransferFeeConfig()
+	if err != nil {
+		return nil, err
+	}
+	var additional func(...*solana.AccountMeta)
+	if hasFee {
+		fee, _, err := feeConfig.CurrentFee(ctx, p.client, p.commitment)
+		if err != nil {
+			return nil, err
+		}
+		plan.Fee = fee.Fee(amount)
+		transfer := NewTransferCheckedWithFee2022Instruction(amount, decoded.Decimals, plan.Fee, source, mint, destination, authority, p.signers...)
+		plan.Transfer, additional = transfer, func(accounts ...*solana.AccountMeta) { transfer.SetAdditionalAccounts(accounts...) }
+	} else {
+		transfer := NewTransferChecked2022Instruction(amount, decoded.Decimals, source, mint, destination, authority, p.signers...)
+		plan.Transfer, additional = transfer, func(accounts ...*solana.AccountMeta) { transfer.SetAdditionalAccounts(accounts...) }
+	}
+
+	hook, _, err := decoded.TransferHookConfig()
+	if err != nil {
+		return nil, err
+	}
+	if hook != nil && hook.ProgramID != nil {
+		accounts, err := ResolveTransferHookAccounts(ctx, p.client, p.commitment, *hook.ProgramID, plan.Transfer)
+		if err != nil {
+			return nil, err
+		}
+		additional(accounts...)
+		plan.HookProgram = hook.ProgramID
+	}
+
+	if p.viaCPI {
+		if err := CheckCpiGuard(sourceAccount, source, plan.Transfer, true); err != nil {
+			return nil, err
+		}
+	}
+	memo, err := p.memoFor(ctx, destination)
+	if err != nil {
+		return nil, err
+	}
+	if memo != nil {
+		plan.Memo = true
+		plan.Instructions = append(plan.Instructions, memo)
+	}
+	plan.Instructions = append(plan.Instructions, plan.Transfer.Build())
+	return plan, nil
+}
+
+// decodeTransfer decodes a Token-2022 Transfer, TransferChecked or
+// TransferCheckedWithFee instruction.
+func decodeTransfer(transfer solana.Instruction) (TypedInstruction, error) {
+	if transfer == nil {
+		return nil, errNotSet("transfer")
+	}
+	if !transfer.ProgramID().Equals(solana.Token2022ProgramID) {
+		return nil, fmt.Errorf("instruction is for program %s, not Token-2022", transfer.ProgramID())
+	}
+	data, err := transfer.Data()
+	if err != nil {
+		return nil, fmt.Errorf("error while encoding transfer: %w", err)
+	}
+	decoded, err := DecodeInstruction(transfer.Accounts(), data)
+	if err != nil {
+		return nil, err
+	}
+	switch decoded.(type) {
+	case *Transfer2022, *TransferChecked2022, *TransferCheckedWithFee2022:
+		return decoded, nil
+	}
+	return nil, fmt.Errorf("%s is not a transfer instruction", InstructionName(data))
+}
+
+// transferAccounts returns the source and destination of an instruction
+// returned by decodeTransfer.
+func transferAccounts(transfer TypedInstruction) (source, destination solana.PublicKey) {
+	switch inst := transfer.(type) {
+	case *Transfer2022:
+		return inst.Source, inst.Destination
+	case *TransferChecked2022:
+		return inst.Source, inst.Destination
+	case *TransferCheckedWithFee2022:
+		return inst.Source, inst.Destination
+	}
+	return solana.PublicKey{}, solana.PublicKey{}
+}
+
+// CpiGuardError reports an instruction that the CpiGuard extension of a
+// token account would make the program reject. Err is the error the
+// program would return, so errors.Is(err, ErrCpiGuardTransferBlocked)
+// matches a blocked transfer.
+type CpiGuardError struct {
+	Account     solana.PublicKey
+	Instruction string
+	Err         TokenError
+}
+
+func (e *CpiGuardError) Error() string {
+	return fmt.Sprintf("%s of account %s: %s", e.Instruction, e.Account, e.Err.Error())
+}
+
+func (e *CpiGuardError) Unwrap() error {
+	return e.Err
+}
+
+// CheckCpiGuard returns a *CpiGuardError if the CpiGuard extension of
+// account, at address key, blocks inst. viaCPI tells whether inst is
+// invoked by another program through CPI; only owner changes are blocked
+// outside CPI. Through CPI, the guard blocks transfers and burns signed by
+// the owner rather than a delegate, approvals, closing to an account other
+// than the owner, and setting a close authority. Instructions that do not
+// act on key as their source account are not blocked.
+func CheckCpiGuard(account *TokenAccount, key solana.PublicKey, inst TypedInstruction, viaCPI bool) error {
+	enabled, err := account.CpiGuardEnabled()
+	if err != nil || !enabled {
+		return err
+	}
+	blocked := func(name string, code TokenError) error {
+		return &CpiGuardError{Account: key, Instruction: name, Err: code}
+	}
+	if !viaCPI {
+		if set, ok := inst.(*SetAuthority2022); ok && set.Account == key && set.AuthorityType == AuthorityAccountOwner {
+			return blocked("SetAuthority", ErrCpiGuardOwnerChangeBlocked)
+		}
+		return nil
+	}
+	switch inst := inst.(type) {
+	case *Transfer2022:
+		if inst.Source == key && inst.Owner == account.Owner {
+			return blocked("Transfer", ErrCpiGuardTransferBlocked)
+		}
+	case *TransferChecked2022:
+		if inst.Source == key && inst.Owner == account.Owner {
+			return blocked("TransferChecked", ErrCpiGuardTransferBlocked)
+		}
+	case *TransferCheckedWithFee2022:
+		if inst.Source == key && inst.Owner == account.Owner {
+			return blocked("TransferCheckedWithFee", ErrCpiGuardTransferBlocked)
+		}
+	case *Burn2022:
+		if inst.Account == key && inst.Owner == account.Owner {
+			return blocked("Burn", ErrCpiGuardBurnBlocked)
+		}
+	case *BurnChecked2022:
+		if inst.Account == key && inst.Owner == account.Owner {
+			return blocked("BurnChecked", ErrCpiGuardBurnBlocked)
+		}
+	case *Approve2022:
+		if inst.Source == key {
+			return blocked("Approve", ErrCpiGuardApproveBlocked)
+		}
+	case *ApproveChecked2022:
+		if inst.Source == key {
+			return blocked("ApproveChecked", ErrCpiGuardApproveBlocked)
+		}
+	case *Close2022:
+		if inst.Account == key && inst.Destination != account.Owner {
+			return blocked("CloseAccount", ErrCpiGuardCloseAccountBlocked)
+		}
+	case *SetAuthority2022:
+		if inst.Account != key {
+			break
+		}
+		if inst.AuthorityType == AuthorityAccountOwner || inst.AuthorityType == AuthorityCloseAccount && inst.NewAuthority != nil {
+			return blocked("SetAuthority", ErrCpiGuardSetAuthorityBlocked)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package token2022
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+func TestTransferPreflightMemo(t *testing.T) {
+	var (
+		owner       = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		client      = newMockRPC()
+		ctx         = context.Background()
+	)
+	transfer := NewTransferChecked2022Instruction(100, 6, source, mint, destination, owner).Build()
+
+	// A missing destination does not require a memo.
+	instructions, err := NewTransferPreflight(client).Prepare(ctx, transfer)
+	if err != nil || len(instructions) != 1 {
+		t.Fatalf("Expected the transfer alone, got %d instructions, %v", len(instructions), err)
+	}
+
+	client.setAccount(destination, solana.Token2022ProgramID, EncodeTokenAccount(&TokenAccount{
+		Mint:       mint,
+		Owner:      owner,
+		State:      AccountStateInitialized,
+		Extensions: []Extension{{Type: ExtensionMemoTransfer, Data: []byte{1}}},
+	}))
+	if _, err := NewTransferPreflight(client).Prepare(ctx, transfer); !errors.Is(err, ErrMemoRequired) {
+		t.Fatalf("Expected ErrMemoRequired, got %v", err)
+	}
+
+	instructions, err = NewTransferPreflight(client).SetMemo("invoice 7", owner).Prepare(ctx, transfer)
+	if err != nil {
+		t.Fatalf("Prepare: %v", err)
+	}
+	if len(instructions) != 2 || instructions[1] != transfer {
+		t.Fatalf("Expected memo and transfer, got %d instructions", len(instructions))
+	}
+	memo := instructions[0]
+	data, _ := memo.Data()
+	if !memo.ProgramID().Equals(solana.MemoProgramID) || string(data) != "invoice 7" {
+		t.Errorf("Expected memo \"invoice 7\", got %s %q", memo.ProgramID(), data)
+	}
+	if accounts := memo.Accounts(); len(accounts) != 1 || accounts[0].PublicKey != owner || !accounts[0].IsSigner {
+		t.Errorf("Expected the owner to sign the memo, got %+v", accounts)
+	}
+
+	// Disabled memo transfers need no memo.
+	client.setAccount(destination, solana.Token2022ProgramID, EncodeTokenAccount(&TokenAccount{
+		Mint:       mint,
+		Owner:      owner,
+		State:      AccountStateInitialized,
+		Extensions: []Extension{{Type: ExtensionMemoTransfer, Data: []byte{0}}},
+	}))
+	if instructions, err := NewTransferPreflight(client).Prepare(ctx, transfer); err != nil || len(instructions) != 1 {
+		t.Errorf("Expected the transfer alone, got %d instructions, %v", len(instructions), err)
+	}
+
+	if _, err := NewTransferPreflight(client).Prepare(ctx, NewBurn2022Instruction(1, source, mint, owner).Build()); err == nil {
+		t.Error("Expected an error for a burn")
+	}
+}
+
+func TestCheckCpiGuard(t *testing.T) {
+	var (
+		owner       = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		delegate    = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		destination = solana.NewWallet().PublicKey()
+	)
+	account := &TokenAccount{
+		Mint:       mint,
+		Owner:      owner,
+		Delegate:   &delegate,
+		State:      AccountStateInitialized,
+		Extensions: []Extension{{Type: ExtensionCpiGuard, Data: []byte{1}}},
+	}
+	for _, tt := range []struct {
+		name   string
+		inst   TypedInstruction
+		viaCPI bool
+		want   TokenError
+	}{
+		{"owner transfer", NewTransferChecked2022Instruction(1, 6, source, mint, destination, owner), true, ErrCpiGuardTransferBlocked},
+		{"delegate transfer", NewTransferChecked2022Instruction(1, 6, source, mint, destination, delegate), true, 0},
+		{"top-level transfer", NewTransferChecked2022Instruction(1, 6, source, mint, destination, owner), false, 0},
+		{"other source", NewTransfer2022Instruction(1, destination, source, owner), true, 0},
+		{"owner burn", NewBurn2022Instruction(1, source, mint, owner), true, ErrCpiGuardBurnBlocked},
+		{"approve", NewApprove2022Instruction(1, source, delegate, owner), true, ErrCpiGuardApproveBlocked},
+		{"close to owner", NewClose2022Instruction(source, owner, owner), true, 0},
+		{"close elsewhere", NewClose2022Instruction(source, destination, owner), true, ErrCpiGuardCloseAccountBlocked},
+		{"set close authority", NewSetAuthority2022Instruction(AuthorityCloseAccount, &destination, source, owner), true, ErrCpiGuardSetAuthorityBlocked},
+		{"clear close authority", NewSetAuthority2022Instruction(AuthorityCloseAccount, nil, source, owner), true, 0},
+		{"owner change", NewSetAuthority2022Instruction(AuthorityAccountOwner, &destination, source, owner), false, ErrCpiGuardOwnerChangeBlocked},
+		{"owner change via CPI", NewSetAuthority2022Instruction(AuthorityAccountOwner, &destination, source, owner), true, ErrCpiGuardSetAuthorityBlocked},
+	} {
+		err := CheckCpiGuard(account, source, tt.inst, tt.viaCPI)
+		if tt.want == 0 {
+			if err != nil {
+				t.Errorf("%s: expected no error, got %v", tt.name, err)
+			}
+			continue
+		}
+		var guardErr *CpiGuardError
+		if !errors.As(err, &guardErr) || !errors.Is(err, tt.want) || guardErr.Account != source {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, err)
+		}
+	}
+
+	account.Extensions[0].Data[0] = 0
+	if err := CheckCpiGuard(account, source, NewBurn2022Instruction(1, source, mint, owner), true); err != nil {
+		t.Errorf("Expected a disabled guard to allow burns, got %v", err)
+	}
+}
+
+func TestTransferPreflightCpiGuard(t *testing.T) {
+	var (
+		owner       = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		client      = newMockRPC()
+		ctx         = context.Background()
+	)
+	client.setAccount(source, solana.Token2022ProgramID, EncodeTokenAccount(&TokenAccount{
+		Mint:       mint,
+		Owner:      owner,
+		State:      AccountStateInitialized,
+		Extensions: []Extension{{Type: ExtensionCpiGuard, Data: []byte{1}}},
+	}))
+	transfer := NewTransferChecked2022Instruction(100, 6, source, mint, destination, owner).Build()
+
+	if _, err := NewTransferPreflight(client).Prepare(ctx, transfer); err != nil {
+		t.Errorf("Expected a top-level transfer to pass, got %v", err)
+	}
+	_, err := NewTransferPreflight(client).SetInvokedViaCPI(true).Prepare(ctx, transfer)
+	if !errors.Is(err, ErrCpiGuardTransferBlocked) {
+		t.Errorf("Expected ErrCpiGuardTransferBlocked, got %v", err)
+	}
+}
+
+func TestPlanTransfer(t *testing.T) {
+	var (
+		owner       = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		hook        = solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR")
+		client      = newMockRPC()
+		ctx         = context.Background()
+	)
+	client.setAccount(mint, solana.Token2022ProgramID, EncodeMint(&Mint{Decimals: 6, IsInitialized: true}))
+	client.setAccount(source, solana.Token2022ProgramID, EncodeTokenAccount(&TokenAccount{Mint: mint, Owner: owner, Amount: 1_000_000, State: AccountStateInitialized}))
+
+	plan, err := NewTransferPreflight(client).PlanTransfer(ctx, mint, source, destination, 500)
+	if err != nil {
+		t.Fatalf("PlanTransfer: %v", err)
+	}
+	transfer, ok := plan.Transfer.(*TransferChecked2022)
+	if !ok {
+		t.Fatalf("Expected *TransferChecked2022, got %T", plan.Transfer)
+	}
+	if transfer.Owner != owner || transfer.Decimals != 6 || plan.Fee != 0 || plan.Memo || plan.HookProgram != nil || len(plan.Instructions) != 1 {
+		t.Errorf("Unexpected plan %+v", plan)
+	}
+	if client.calls["getEpochInfo"] != 0 {
+		t.Errorf("Expected no epoch lookup without a transfer fee")
+	}
+
+	// A fee, a hook and a destination requiring memos.
+	mintData := transferFeeMint(
+		TransferFee{BasisPoints: 10, MaximumFee: 1_000},
+		TransferFee{Epoch: 10, BasisPoints: 100, MaximumFee: 3},
+	)
+	decoded, _ := DecodeMint(mintData)
+	decoded.Extensions = append(decoded.Extensions, Extension{Type: ExtensionTransferHook, Data: append(make([]byte, 32), hook.Bytes()...)})
+	client.setAccount(mint, solana.Token2022ProgramID, EncodeMint(decoded))
+	list, _, _ := FindExtraAccountMetasAddress(mint, hook)
+	client.setAccount(list, hook, encodeExtraAccountMetaList([]ExtraAccountMeta{
+		{Discriminator: 0, AddressConfig: addressConfig(solana.MemoProgramID.Bytes()...)},
+	}))
+	client.setAccount(destination, solana.Token2022ProgramID, EncodeTokenAccount(&TokenAccount{
+		Mint:       mint,
+		Owner:      owner,
+		State:      AccountStateInitialized,
+		Extensions: []Extension{{Type: ExtensionMemoTransfer, Data: []byte{1}}},
+	}))
+	client.epoch = 11
+
+	if _, err := NewTransferPreflight(client).PlanTransfer(ctx, mint, source, destination, 500); !errors.Is(err, ErrMemoRequired) {
+		t.Fatalf("Expected ErrMemoRequired, got %v", err)
+	}
+	delegate := solana.NewWallet().PublicKey()
+	plan, err = NewTransferPreflight(client).SetMemo("refund").SetAuthority(delegate).PlanTransfer(ctx, mint, source, destination, 500)
+	if err != nil {
+		t.Fatalf("PlanTransfer: %v", err)
+	}
+	withFee, ok := plan.Transfer.(*TransferCheckedWithFee2022)
+	if !ok {
+		t.Fatalf("Expected *TransferCheckedWithFee2022, got %T", plan.Transfer)
+	}
+	if plan.Fee != 3 || withFee.Fee != 3 || withFee.Owner != delegate {
+		t.Errorf("Expected a fee of 3 signed by the delegate, got %d signed by %s", withFee.Fee, withFee.Owner)
+	}
+	if !plan.Memo || len(plan.Instructions) != 2 || !plan.Instructions[0].ProgramID().Equals(solana.MemoProgramID) {
+		t.Errorf("Expected memo and transfer, got %d instructions", len(plan.Instructions))
+	}
+	if plan.HookProgram == nil || *plan.HookProgram != hook {
+		t.Errorf("Expected hook program %s, got %v", hook, plan.HookProgram)
+	}
+	accounts := plan.Instructions[1].Accounts()
+	if len(accounts) != 7 || accounts[4].PublicKey != solana.MemoProgramID || accounts[5].PublicKey != hook || accounts[6].PublicKey != list {
+		t.Errorf("Expected the hook accounts after the transfer accounts, got %d accounts", len(accounts))
+	}
+
+	if _, err := NewTransferPreflight(client).PlanTransfer(ctx, hook, source, destination, 500); err == nil {
+		t.Error("Expected an error for a missing mint")
+	}
+}
@@ -0,0 +1,347 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// TransferFeeProfile is the TransferFeeConfig extension of a MintProfile.
+type TransferFeeProfile struct {
+	BasisPoints uint16
+	MaximumFee  uint64
+	// ConfigAuthority and WithdrawAuthority are nil when unset.
+	ConfigAuthority   *solana.PublicKey
+	WithdrawAuthority *solana.PublicKey
+}
+
+// InterestBearingProfile is the InterestBearingConfig extension of a
+// MintProfile. Rate is in basis points per year.
+type InterestBearingProfile struct {
+	Rate int16
+	// RateAuthority is nil when unset.
+	RateAuthority *solana.PublicKey
+}
+
+// ScaledUiAmountProfile is the ScaledUiAmount extension of a MintProfile.
+type ScaledUiAmountProfile struct {
+	Multiplier float64
+	// Authority is nil when unset.
+	Authority *solana.PublicKey
+}
+
+// TransferHookProfile is the TransferHook extension of a MintProfile.
+type TransferHookProfile struct {
+	ProgramID solana.PublicKey
+	// Authority is nil when unset.
+	Authority *solana.PublicKey
+}
+
+// MetadataProfile is the TokenMetadata stored in the mint of a
+// MintProfile, with a MetadataPointer to the mint itself.
+type MetadataProfile struct {
+	Name   string
+	Symbol string
+	URI    string
+	// UpdateAuthority may update the metadata and its pointer. It is the
+	// profile's Authority when nil.
+	UpdateAuthority *solana.PublicKey
+}
+
+// GroupMemberProfile makes the mint of a MintProfile a member of Group,
+// with a GroupMemberPointer to the mint itself.
+type GroupMemberProfile struct {
+	// Group is the account holding the TokenGroup, usually the
+	// collection's mint.
+	Group solana.PublicKey
+	// GroupUpdateAuthority is the update authority of the group; it signs
+	// the transaction. It is the profile's Authority when nil.
+	GroupUpdateAuthority *solana.PublicKey
+}
+
+// MintProfile declares a mint: its decimals, authorities and extensions,
+// in one struct. A nil or false field leaves its extension out. Validate
+// checks the extensions fit together; Plan orders their initialization
+// and creates the mint. SoulboundPreset, StablecoinPreset and
+// CreateNFT2022 build profiles, and MintProfileOf reads one back from a
+// mint, so creation and administration share a description of the mint.
+type MintProfile struct {
+	// Mint is the address of the new mint; it signs the transaction.
+	Mint solana.PublicKey
+	// Authority is the mint authority; it signs the transaction.
+	Authority solana.PublicKey
+	// Payer funds the mint and signs the transaction. It defaults to
+	// Authority.
+	Payer           *solana.PublicKey
+	Decimals        uint8
+	FreezeAuthority *solana.PublicKey
+
+	TransferFee     *TransferFeeProfile
+	InterestBearing *InterestBearingProfile
+	ScaledUiAmount  *ScaledUiAmountProfile
+	TransferHook    *TransferHookProfile
+	// DefaultFrozen makes new accounts start frozen.
+	DefaultFrozen   bool
+	NonTransferable bool
+	// PermanentDelegate, CloseAuthority and PauseAuthority add the
+	// PermanentDelegate, MintCloseAuthority and Pausable extensions.
+	PermanentDelegate *solana.PublicKey
+	CloseAuthority    *solana.PublicKey
+	PauseAuthority    *solana.PublicKey
+	Metadata          *MetadataProfile
+	GroupMember       *GroupMemberProfile
+}
+
+// Validate checks the profile's fields and that its extensions are
+// compatible, wrapping ErrIncompatibleExtensions for extensions the
+// program rejects together or that defeat each other.
+func (p *MintProfile) Validate() error {
+	if p.Mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	if p.Authority.IsZero() {
+		return errNotSet("Authority")
+	}
+	for _, key := range []struct {
+		field string
+		key   *solana.PublicKey
+	}{
+		{"Payer", p.Payer},
+		{"FreezeAuthority", p.FreezeAuthority},
+		{"PermanentDelegate", p.PermanentDelegate},
+		{"CloseAuthority", p.CloseAuthority},
+		{"PauseAuthority", p.PauseAuthority},
+	} {
+		if key.key != nil && key.key.IsZero() {
+			return errInvalidField(key.field, "is the zero key")
+		}
+	}
+	if fee := p.TransferFee; fee != nil && fee.BasisPoints > MaxFeeBasisPoints {
+		return errInvalidField("TransferFee", "%d basis points exceed %d", fee.BasisPoints, MaxFeeBasisPoints)
+	}
+	if scaled := p.ScaledUiAmount; scaled != nil && (!(scaled.Multiplier > 0) || math.IsInf(scaled.Multiplier, 0)) {
+		return errInvalidField("ScaledUiAmount", "multiplier %v must be positive and finite", scaled.Multiplier)
+	}
+	if hook := p.TransferHook; hook != nil && hook.ProgramID.IsZero() {
+		return errNotSet("TransferHook.ProgramID")
+	}
+	if member := p.GroupMember; member != nil && member.Group.IsZero() {
+		return errNotSet("GroupMember.Group")
+	}
+	if p.DefaultFrozen && p.FreezeAuthority == nil {
+		return errInvalidField("DefaultFrozen", "needs a FreezeAuthority to thaw new accounts")
+	}
+
+	if p.InterestBearing != nil && p.ScaledUiAmount != nil {
+		return fmt.Errorf("%w: %s and %s both scale UI amounts", ErrIncompatibleExtensions, ExtensionInterestBearingConfig, ExtensionScaledUiAmount)
+	}
+	if p.NonTransferable && p.TransferFee != nil {
+		return fmt.Errorf("%w: %s tokens never pay a %s", ErrIncompatibleExtensions, ExtensionNonTransferable, ExtensionTransferFeeConfig)
+	}
+	if p.NonTransferable && p.TransferHook != nil {
+		return fmt.Errorf("%w: %s tokens never invoke a %s", ErrIncompatibleExtensions, ExtensionNonTransferable, ExtensionTransferHook)
+	}
+	return nil
+}
+
+// Extensions returns the extensions of the profile's mint, in the order
+// Plan initializes them.
+func (p *MintProfile) Extensions() []ExtensionType {
+	return p.planner().plannedExtensions()
+}
+
+// Plan validates the profile and returns the plan creating the mint,
+// signed by Mint, Authority, Payer and, with a GroupMember, the group's
+// update authority. Fixed-size extensions are initialized before the
+// mint; TokenMetadata and TokenGroupMember are written after it, into the
+// space Lamports pays for.
+func (p *MintProfile) Plan() (*MintPlan, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p.planner().plan(keyOr(p.Payer, p.Authority), NewInitializeMint2022Instruction(p.Decimals, p.Authority, p.FreezeAuthority, p.Mint).Build())
+}
+
+func (p *MintProfile) planner() *mintPlanner {
+	planner := &mintPlanner{mint: p.Mint}
+	if fee := p.TransferFee; fee != nil {
+		planner.extension(ExtensionTransferFeeConfig, NewInitializeTransferFeeConfig2022Instruction(fee.ConfigAuthority, fee.WithdrawAuthority, fee.BasisPoints, fee.MaximumFee, p.Mint).Build())
+	}
+	if interest := p.InterestBearing; interest != nil {
+		planner.extension(ExtensionInterestBearingConfig, NewInitializeInterestBearingMint2022Instruction(interest.RateAuthority, interest.Rate, p.Mint).Build())
+	}
+	if scaled := p.ScaledUiAmount; scaled != nil {
+		planner.extension(ExtensionScaledUiAmount, NewInitializeScaledUiAmount2022Instruction(scaled.Authority, scaled.Multiplier, p.Mint).Build())
+	}
+	if hook := p.TransferHook; hook != nil {
+		planner.extension(ExtensionTransferHook, NewInitializeTransferHook2022Instruction(hook.Authority, &hook.ProgramID, p.Mint).Build())
+	}
+	if p.DefaultFrozen {
+		planner.extension(ExtensionDefaultAccountState, NewInitializeDefaultAccountState2022Instruction(AccountStateFrozen, p.Mint).Build())
+	}
+	if p.NonTransferable {
+		planner.extension(ExtensionNonTransferable, NewInitializeNonTransferableMint2022Instruction(p.Mint).Build())
+	}
+	if p.PauseAuthority != nil {
+		planner.extension(ExtensionPausable, NewInitializePausableConfig2022Instruction(*p.PauseAuthority, p.Mint).Build())
+	}
+	if p.PermanentDelegate != nil {
+		planner.extension(ExtensionPermanentDelegate, NewInitializePermanentDelegate2022Instruction(*p.PermanentDelegate, p.Mint).Build())
+	}
+	if p.CloseAuthority != nil {
+		planner.extension(ExtensionMintCloseAuthority, NewInitializeMintCloseAuthority2022Instruction(p.CloseAuthority, p.Mint).Build())
+	}
+	if metadata := p.Metadata; metadata != nil {
+		planner.metadata(p.pointerAuthority(), p.Authority, metadata.Name, metadata.Symbol, metadata.URI)
+	}
+	if member := p.GroupMember; member != nil {
+		planner.groupMember(p.pointerAuthority(), p.Authority, member.Group, keyOr(member.GroupUpdateAuthority, p.Authority))
+	}
+	return planner
+}
+
+// Authorities returns the key holding each authority type the profile
+// sets, such as to check a created mint with MintAuthorities or to plan
+// a RotateAuthorities.
+func (p *MintProfile) Authorities() map[AuthorityType]solana.PublicKey {
+	authorities := map[AuthorityType]solana.PublicKey{AuthorityMintTokens: p.Authority}
+	set := func(authorityType AuthorityType, key *solana.PublicKey) {
+		if key != nil {
+			authorities[authorityType] = *key
+		}
+	}
+	set(AuthorityFreezeAccount, p.FreezeAuthority)
+	if fee := p.TransferFee; fee != nil {
+		set(AuthorityTransferFeeConfig, fee.ConfigAuthority)
+		set(AuthorityWithheldWithdraw, fee.WithdrawAuthority)
+	}
+	if interest := p.InterestBearing; interest != nil {
+		set(AuthorityInterestRate, interest.RateAuthority)
+	}
+	if scaled := p.ScaledUiAmount; scaled != nil {
+		set(AuthorityScaledUiAmount, scaled.Authority)
+	}
+	if hook := p.TransferHook; hook != nil {
+		set(AuthorityTransferHookProgramID, hook.Authority)
+	}
+	set(AuthorityPermanentDelegate, p.PermanentDelegate)
+	set(AuthorityCloseMint, p.CloseAuthority)
+	set(AuthorityPause, p.PauseAuthority)
+	if p.Metadata != nil {
+		authorities[AuthorityMetadataPointer] = p.pointerAuthority()
+	}
+	if p.GroupMember != nil {
+		authorities[AuthorityGroupMemberPointer] = p.pointerAuthority()
+	}
+	return authorities
+}
+
+// pointerAuthority returns the authority of the metadata and group member
+// pointers: the metadata's update authority, or Authority.
+func (p *MintProfile) pointerAuthority() solana.PublicKey {
+	if p.Metadata != nil && p.Metadata.UpdateAuthority != nil {
+		return *p.Metadata.UpdateAuthority
+	}
+	return p.Authority
+}
+
+// AuthorityTypes returns the authority types of the profile held by key,
+// in AuthorityType order, like MintAuthorities for a decoded mint.
+func (p *MintProfile) AuthorityTypes(key solana.PublicKey) []AuthorityType {
+	var types []AuthorityType
+	for authorityType, holder := range p.Authorities() {
+		if holder.Equals(key) {
+			types = append(types, authorityType)
+		}
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+// MintProfileOf reads the profile of a decoded mint at address mint. The
+// fee is the newer transfer fee and the rate the current rate; fields
+// that only matter at creation, such as Payer, are left unset. A mint
+// without a mint authority has a zero Authority.
+func MintProfileOf(mint solana.PublicKey, decoded *Mint) (*MintProfile, error) {
+	profile := &MintProfile{
+		Mint:            mint,
+		Decimals:        decoded.Decimals,
+		FreezeAuthority: decoded.FreezeAuthority,
+	}
+	if decoded.MintAuthority != nil {
+		profile.Authority = *decoded.MintAuthority
+	}
+	if fee, ok, err := decoded.TransferFeeConfig(); err != nil {
+		return nil, err
+	} else if ok {
+		profile.TransferFee = &TransferFeeProfile{
+			BasisPoints:       fee.NewerTransferFee.BasisPoints,
+			MaximumFee:        fee.NewerTransferFee.MaximumFee,
+			ConfigAuthority:   fee.ConfigAuthority,
+			WithdrawAuthority: fee.WithdrawWithheldAuthority,
+		}
+	}
+	if interest, ok, err := decoded.InterestBearingConfig(); err != nil {
+		return nil, err
+	} else if ok {
+		profile.InterestBearing = &InterestBearingProfile{Rate: interest.CurrentRate, RateAuthority: interest.RateAuthority}
+	}
+	if scaled, ok, err := decoded.ScaledUiAmountConfig(); err != nil {
+		return nil, err
+	} else if ok {
+		profile.ScaledUiAmount = &ScaledUiAmountProfile{Multiplier: scaled.Multiplier, Authority: scaled.Authority}
+	}
+	if hook, ok, err := decoded.TransferHookConfig(); err != nil {
+		return nil, err
+	} else if ok {
+		profile.TransferHook = &TransferHookProfile{Authority: hook.Authority}
+		if hook.ProgramID != nil {
+			profile.TransferHook.ProgramID = *hook.ProgramID
+		}
+	}
+	state, _, err := decoded.DefaultAccountState()
+	if err != nil {
+		return nil, err
+	}
+	profile.DefaultFrozen = state == AccountStateFrozen
+	_, profile.NonTransferable = decoded.Extension(ExtensionNonTransferable)
+	if profile.PermanentDelegate, _, err = decoded.PermanentDelegate(); err != nil {
+		return nil, err
+	}
+	if profile.CloseAuthority, _, err = decoded.MintCloseAuthority(); err != nil {
+		return nil, err
+	}
+	if data, ok := decoded.Extension(ExtensionPausable); ok {
+		if len(data) != 33 {
+			return nil, fmt.Errorf("%w: invalid pausable config length: %d bytes", ErrInvalidAccountData, len(data))
+		}
+		profile.PauseAuthority = decodeNonZeroPubkey(data[0:32])
+	}
+	if metadata, ok, err := decoded.TokenMetadata(); err != nil {
+		return nil, err
+	} else if ok {
+		profile.Metadata = &MetadataProfile{Name: metadata.Name, Symbol: metadata.Symbol, URI: metadata.URI, UpdateAuthority: metadata.UpdateAuthority}
+	}
+	if member, ok, err := decoded.TokenGroupMember(); err != nil {
+		return nil, err
+	} else if ok {
+		profile.GroupMember = &GroupMemberProfile{Group: member.Group}
+	}
+	return profile, nil
+}
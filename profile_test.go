@@ -0,0 +1,135 @@
+package token2022
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+func TestMintProfilePlan(t *testing.T) {
+	mint := solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	authority := solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	hook := solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR")
+	profile := MintProfile{
+		Mint:            mint,
+		Authority:       authority,
+		Decimals:        6,
+		FreezeAuthority: &authority,
+		TransferFee:     &TransferFeeProfile{BasisPoints: 50, MaximumFee: 5_000, ConfigAuthority: &authority},
+		InterestBearing: &InterestBearingProfile{Rate: 500, RateAuthority: &authority},
+		TransferHook:    &TransferHookProfile{ProgramID: hook},
+		DefaultFrozen:   true,
+		CloseAuthority:  &authority,
+		Metadata:        &MetadataProfile{Name: "Token", Symbol: "TKN"},
+	}
+	plan, err := profile.Plan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []ExtensionType{
+		ExtensionTransferFeeConfig,
+		ExtensionInterestBearingConfig,
+		ExtensionTransferHook,
+		ExtensionDefaultAccountState,
+		ExtensionMintCloseAuthority,
+		ExtensionMetadataPointer,
+		ExtensionTokenMetadata,
+	}
+	if len(plan.Extensions) != len(expected) {
+		t.Fatalf("Expected extensions %v, got %v", expected, plan.Extensions)
+	}
+	for i := range expected {
+		if plan.Extensions[i] != expected[i] {
+			t.Errorf("Expected extension %d to be %s, got %s", i, expected[i], plan.Extensions[i])
+		}
+	}
+	extensions := profile.Extensions()
+	if len(extensions) != len(expected) {
+		t.Errorf("Expected Extensions to match the plan, got %v", extensions)
+	}
+	space, _ := MintSpace(expected[:6]...)
+	if plan.Space != space {
+		t.Errorf("Expected space %d, got %d", space, plan.Space)
+	}
+	// CreateAccount, six extensions, InitializeMint and the metadata.
+	if len(plan.Instructions) != 9 || instructionName(t, plan.Instructions[7]) != "InitializeMint" {
+		t.Errorf("Expected InitializeMint before the metadata, got %d instructions", len(plan.Instructions))
+	}
+}
+
+func TestMintProfileValidate(t *testing.T) {
+	mint := solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	authority := solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	for _, tc := range []struct {
+		name    string
+		profile MintProfile
+		err     error
+	}{
+		{"no authority", MintProfile{Mint: mint}, ErrNotSet},
+		{"interest and scaled", MintProfile{Mint: mint, Authority: authority, InterestBearing: &InterestBearingProfile{}, ScaledUiAmount: &ScaledUiAmountProfile{Multiplier: 1}}, ErrIncompatibleExtensions},
+		{"non-transferable with fee", MintProfile{Mint: mint, Authority: authority, NonTransferable: true, TransferFee: &TransferFeeProfile{}}, ErrIncompatibleExtensions},
+		{"non-transferable with hook", MintProfile{Mint: mint, Authority: authority, NonTransferable: true, TransferHook: &TransferHookProfile{ProgramID: authority}}, ErrIncompatibleExtensions},
+		{"frozen without freeze authority", MintProfile{Mint: mint, Authority: authority, DefaultFrozen: true}, ErrInvalidField},
+		{"zero multiplier", MintProfile{Mint: mint, Authority: authority, ScaledUiAmount: &ScaledUiAmountProfile{}}, ErrInvalidField},
+		{"hook without program", MintProfile{Mint: mint, Authority: authority, TransferHook: &TransferHookProfile{}}, ErrNotSet},
+		{"zero delegate", MintProfile{Mint: mint, Authority: authority, PermanentDelegate: &solana.PublicKey{}}, ErrInvalidField},
+	} {
+		if err := tc.profile.Validate(); !errors.Is(err, tc.err) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.err, err)
+		}
+	}
+}
+
+func TestMintProfileOf(t *testing.T) {
+	mint := solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	authority := solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	delegate := solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR")
+
+	fee := make([]byte, transferFeeConfigSize)
+	copy(fee[0:32], authority[:])
+	binary.LittleEndian.PutUint64(fee[98:106], 5_000)
+	binary.LittleEndian.PutUint16(fee[106:108], 50)
+	decoded, err := DecodeMint(encodeMint(&authority, 0, 6,
+		Extension{Type: ExtensionTransferFeeConfig, Data: fee},
+		Extension{Type: ExtensionDefaultAccountState, Data: []byte{byte(AccountStateFrozen)}},
+		Extension{Type: ExtensionPermanentDelegate, Data: delegate[:]},
+		Extension{Type: ExtensionPausable, Data: append(append([]byte{}, authority[:]...), 0)},
+		Extension{Type: ExtensionMetadataPointer, Data: append(append([]byte{}, authority[:]...), mint[:]...)},
+		Extension{Type: ExtensionTokenMetadata, Data: encodeTestTokenMetadata(authority, mint, "Token", "TKN", "uri")},
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	profile, err := MintProfileOf(mint, decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.TransferFee == nil || profile.TransferFee.BasisPoints != 50 || profile.TransferFee.MaximumFee != 5_000 || profile.TransferFee.WithdrawAuthority != nil {
+		t.Errorf("Unexpected transfer fee: %+v", profile.TransferFee)
+	}
+	if !profile.DefaultFrozen || profile.NonTransferable {
+		t.Errorf("Expected frozen default state and transferable tokens, got %v and %v", profile.DefaultFrozen, profile.NonTransferable)
+	}
+	if profile.PermanentDelegate == nil || !profile.PermanentDelegate.Equals(delegate) {
+		t.Errorf("Expected permanent delegate %s, got %v", delegate, profile.PermanentDelegate)
+	}
+	if profile.Metadata == nil || profile.Metadata.Symbol != "TKN" {
+		t.Errorf("Unexpected metadata: %+v", profile.Metadata)
+	}
+
+	types := profile.AuthorityTypes(authority)
+	expected := []AuthorityType{AuthorityMintTokens, AuthorityTransferFeeConfig, AuthorityMetadataPointer, AuthorityPause}
+	if len(types) != len(expected) {
+		t.Fatalf("Expected authority types %v, got %v", expected, types)
+	}
+	for i := range expected {
+		if types[i] != expected[i] {
+			t.Errorf("Expected authority type %d to be %v, got %v", i, expected[i], types[i])
+		}
+	}
+	if got := MintAuthorities(decoded, authority); len(got) != len(expected) {
+		t.Errorf("Expected MintAuthorities to agree with the profile, got %v", got)
+	}
+}
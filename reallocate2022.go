@@ -0,0 +1,212 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	solana "github.com/gagliardetto/solana-go"
+	format "github.com/gagliardetto/solana-go/text/format"
+	treeout "github.com/gagliardetto/treeout"
+)
+
+// Reallocate2022 grows a token account to make room for the given extensions.
+type Reallocate2022 struct {
+	// The extensions to make room for.
+	ExtensionTypes []ExtensionType
+
+	Account solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Payer   solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Owner   solana.PublicKey `bin:"-" borsh_skip:"true"`
+	// Signers are the signers of a multisig authority.
+	Signers []solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Account
+	// ··········· The account to reallocate
+	//
+	// [1] = [WRITE, SIGNER] Payer
+	// ··········· The payer of the additional rent
+	//
+	// [2] = [] SystemProgram
+	// ··········· System program ID
+	//
+	// [3] = [SIGNER] Owner
+	// ··········· The account's owner, or a multisig
+	//
+	// [4...] = [SIGNER] Signers
+	// ··········· M signer accounts when the authority is a multisig
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewReallocate2022InstructionBuilder creates a new `Reallocate2022` instruction builder.
+func NewReallocate2022InstructionBuilder() *Reallocate2022 {
+	nd := &Reallocate2022{}
+	return nd
+}
+
+func (inst *Reallocate2022) SetExtensionTypes(extensionTypes []ExtensionType) *Reallocate2022 {
+	inst.ExtensionTypes = extensionTypes
+	return inst
+}
+
+func (inst *Reallocate2022) SetAccount(account solana.PublicKey) *Reallocate2022 {
+	inst.Account = account
+	return inst
+}
+
+func (inst *Reallocate2022) SetPayer(payer solana.PublicKey) *Reallocate2022 {
+	inst.Payer = payer
+	return inst
+}
+
+// SetOwner sets the authority. Pass the signers when the authority is a multisig.
+func (inst *Reallocate2022) SetOwner(owner solana.PublicKey, multisigSigners ...solana.PublicKey) *Reallocate2022 {
+	inst.Owner = owner
+	inst.Signers = multisigSigners
+	return inst
+}
+
+func (inst Reallocate2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Account,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+		{
+			PublicKey:  inst.Payer,
+			IsSigner:   true,
+			IsWritable: true,
+		},
+		{
+			PublicKey:  solana.SystemProgramID,
+			IsSigner:   false,
+			IsWritable: false,
+		},
+	}
+	keys = appendAuthority(keys, inst.Owner, inst.Signers)
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst Reallocate2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *Reallocate2022) Validate() error {
+	if inst.Account.IsZero() {
+		return errNotSet("Account")
+	}
+	if inst.Payer.IsZero() {
+		return errNotSet("Payer")
+	}
+	if inst.Owner.IsZero() {
+		return errNotSet("Owner")
+	}
+	if err := validateMultisigSigners(inst.Signers); err != nil {
+		return err
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+func (inst *Reallocate2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("Reallocate2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=1]").ParentFunc(func(paramsBranch treeout.Branches) {
+						paramsBranch.Child(format.Param("ExtensionTypes", inst.ExtensionTypes))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("      account", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(format.Meta("        payer", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(format.Meta("systemProgram", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(format.Meta("        owner", inst.AccountMetaSlice.Get(3)))
+						encodeRemainingAccounts(accountsBranch, "signer", inst.AccountMetaSlice, 4)
+					})
+				})
+		})
+}
+
+func (inst Reallocate2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	return writeExtensionTypes(encoder, inst.ExtensionTypes)
+}
+
+func (inst *Reallocate2022) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	if inst.ExtensionTypes, err = readExtensionTypes(decoder); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst Reallocate2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *Reallocate2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("Reallocate2022", accounts, 4); err != nil {
+		return err
+	}
+	inst.Account = accounts[0].PublicKey
+	inst.Payer = accounts[1].PublicKey
+	inst.Owner = accounts[3].PublicKey
+	inst.Signers = pubkeysOf(accounts[4:])
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst Reallocate2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *Reallocate2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewReallocate2022Instruction creates a new `Reallocate2022` instruction.
+func NewReallocate2022Instruction(
+	extensionTypes []ExtensionType,
+	account solana.PublicKey,
+	payer solana.PublicKey,
+	owner solana.PublicKey,
+	multisigSigners ...solana.PublicKey,
+) *Reallocate2022 {
+	return NewReallocate2022InstructionBuilder().
+		SetExtensionTypes(extensionTypes).
+		SetAccount(account).
+		SetPayer(payer).
+		SetOwner(owner, multisigSigners...)
+}
@@ -0,0 +1,374 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+//go:generate go run ./internal/genbuilders
+
+import (
+	"errors"
+	"fmt"
+
+	solana "github.com/gagliardetto/solana-go"
+	treeout "github.com/gagliardetto/treeout"
+)
+
+// Token-2022 instruction tags, the first byte of the instruction data.
+const (
+	InstructionInitializeMint uint8 = iota
+	InstructionInitializeAccount
+	InstructionInitializeMultisig
+	InstructionTransfer
+	InstructionApprove
+	InstructionRevoke
+	InstructionSetAuthority
+	InstructionMintTo
+	InstructionBurn
+	InstructionCloseAccount
+	InstructionFreezeAccount
+	InstructionThawAccount
+	InstructionTransferChecked
+	InstructionApproveChecked
+	InstructionMintToChecked
+	InstructionBurnChecked
+	InstructionInitializeAccount2
+	InstructionSyncNative
+	InstructionInitializeAccount3
+	InstructionInitializeMultisig2
+	InstructionInitializeMint2
+	InstructionGetAccountDataSize
+	InstructionInitializeImmutableOwner
+	InstructionAmountToUiAmount
+	InstructionUiAmountToAmount
+	InstructionInitializeMintCloseAuthority
+	InstructionTransferFeeExtension
+	InstructionConfidentialTransferExtension
+	InstructionDefaultAccountStateExtension
+	InstructionReallocate
+	InstructionMemoTransferExtension
+	InstructionCreateNativeMint
+	InstructionInitializeNonTransferableMint
+	InstructionInterestBearingMintExtension
+	InstructionCpiGuardExtension
+	InstructionInitializePermanentDelegate
+	InstructionTransferHookExtension
+	InstructionConfidentialTransferFeeExtension
+	InstructionWithdrawExcessLamports
+	InstructionMetadataPointerExtension
+	InstructionGroupPointerExtension
+	InstructionGroupMemberPointerExtension
+	InstructionConfidentialMintBurnExtension
+	InstructionScaledUiAmountExtension
+	InstructionPausableExtension
+)
+
+// Sub-tags of the TransferFeeExtension instruction, the second byte of
+// the instruction data.
+const (
+	TransferFeeInstructionInitializeConfig uint8 = iota
+	TransferFeeInstructionTransferCheckedWithFee
+	TransferFeeInstructionWithdrawWithheldTokensFromMint
+	TransferFeeInstructionWithdrawWithheldTokensFromAccounts
+	TransferFeeInstructionHarvestWithheldTokensToMint
+	TransferFeeInstructionSetTransferFee
+)
+
+// Sub-tags of the DefaultAccountStateExtension instruction.
+const (
+	DefaultAccountStateInstructionInitialize uint8 = iota
+	DefaultAccountStateInstructionUpdate
+)
+
+// Sub-tags of the MemoTransferExtension and CpiGuardExtension instructions.
+const (
+	ToggleInstructionEnable uint8 = iota
+	ToggleInstructionDisable
+)
+
+// Sub-tags of the InterestBearingMint, TransferHook, MetadataPointer,
+// GroupPointer, GroupMemberPointer and ScaledUiAmount extension
+// instructions.
+const (
+	ExtensionInstructionInitialize uint8 = iota
+	ExtensionInstructionUpdate
+)
+
+// Sub-tags of the PausableExtension instruction.
+const (
+	PausableInstructionInitialize uint8 = iota
+	PausableInstructionPause
+	PausableInstructionResume
+)
+
+// Associated Token Account program instruction tags. Create is also
+// encoded as empty instruction data.
+const (
+	AssociatedTokenInstructionCreate uint8 = iota
+	AssociatedTokenInstructionCreateIdempotent
+	AssociatedTokenInstructionRecoverNested
+)
+
+var associatedTokenInstructionNames = []string{
+	"Create",
+	"CreateIdempotent",
+	"RecoverNested",
+}
+
+var instructionNames = []string{
+	"InitializeMint",
+	"InitializeAccount",
+	"InitializeMultisig",
+	"Transfer",
+	"Approve",
+	"Revoke",
+	"SetAuthority",
+	"MintTo",
+	"Burn",
+	"CloseAccount",
+	"FreezeAccount",
+	"ThawAccount",
+	"TransferChecked",
+	"ApproveChecked",
+	"MintToChecked",
+	"BurnChecked",
+	"InitializeAccount2",
+	"SyncNative",
+	"InitializeAccount3",
+	"InitializeMultisig2",
+	"InitializeMint2",
+	"GetAccountDataSize",
+	"InitializeImmutableOwner",
+	"AmountToUiAmount",
+	"UiAmountToAmount",
+	"InitializeMintCloseAuthority",
+	"TransferFeeExtension",
+	"ConfidentialTransferExtension",
+	"DefaultAccountStateExtension",
+	"Reallocate",
+	"MemoTransferExtension",
+	"CreateNativeMint",
+	"InitializeNonTransferableMint",
+	"InterestBearingMintExtension",
+	"CpiGuardExtension",
+	"InitializePermanentDelegate",
+	"TransferHookExtension",
+	"ConfidentialTransferFeeExtension",
+	"WithdrawExcessLamports",
+	"MetadataPointerExtension",
+	"GroupPointerExtension",
+	"GroupMemberPointerExtension",
+	"ConfidentialMintBurnExtension",
+	"ScaledUiAmountExtension",
+	"PausableExtension",
+}
+
+// ErrUnknownInstruction is returned by DecodeInstruction for instruction
+// data that does not start with a known Token-2022 tag.
+var ErrUnknownInstruction = errors.New("unknown Token-2022 instruction")
+
+// ErrUnsupportedInstruction is returned by DecodeInstruction for valid
+// Token-2022 instructions that have no typed builder in this package,
+// such as the confidential transfer extensions.
+var ErrUnsupportedInstruction = errors.New("unsupported Token-2022 instruction")
+
+// TypedInstruction is a decoded Token-2022 instruction. The concrete type
+// is one of the package's builders, such as *TransferChecked2022, so it
+// can be inspected, modified and rebuilt.
+type TypedInstruction interface {
+	InstructionImpl
+	AccountMetaGettable
+	SetAccounts(accounts []*solana.AccountMeta) error
+	EncodeToTree(parent treeout.Branches)
+	Build() *Instruction
+}
+
+// InstructionName returns the name of the Token-2022 instruction encoded
+// in data, such as "TransferChecked" or "TransferFeeExtension".
+func InstructionName(data []byte) string {
+	if len(data) == 0 {
+		return "Empty"
+	}
+	if int(data[0]) < len(instructionNames) {
+		return instructionNames[data[0]]
+	}
+	return fmt.Sprintf("Unknown(%d)", data[0])
+}
+
+// DecodeInstruction decodes the accounts and data of a Token-2022
+// instruction into the matching builder, dispatching on the instruction
+// tag and, for extension instructions, the sub-tag.
+func DecodeInstruction(accounts []*solana.AccountMeta, data []byte) (TypedInstruction, error) {
+	newInstruction, err := lookupInstruction(data)
+	if err != nil {
+		return nil, err
+	}
+	inst := newInstruction()
+	if err := decodeInstructionData(inst, data); err != nil {
+		return nil, fmt.Errorf("%w: error while decoding %s: %w", ErrMalformedInstruction, InstructionName(data), err)
+	}
+	if err := inst.SetAccounts(accounts); err != nil {
+		return nil, err
+	}
+	return inst, nil
+}
+
+// AssociatedTokenInstructionName returns the name of the Associated Token
+// Account instruction encoded in data.
+func AssociatedTokenInstructionName(data []byte) string {
+	if len(data) == 0 {
+		return associatedTokenInstructionNames[AssociatedTokenInstructionCreate]
+	}
+	if int(data[0]) < len(associatedTokenInstructionNames) {
+		return associatedTokenInstructionNames[data[0]]
+	}
+	return fmt.Sprintf("Unknown(%d)", data[0])
+}
+
+// DecodeAssociatedTokenInstruction decodes an Associated Token Account
+// program instruction that creates a Token-2022 account into a
+// *Create2022.
+func DecodeAssociatedTokenInstruction(accounts []*solana.AccountMeta, data []byte) (TypedInstruction, error) {
+	if len(data) > 0 && data[0] == AssociatedTokenInstructionRecoverNested {
+		return nil, fmt.Errorf("%w: RecoverNested", ErrUnsupportedInstruction)
+	}
+	if len(data) > 0 && data[0] > AssociatedTokenInstructionRecoverNested {
+		return nil, fmt.Errorf("unknown Associated Token Account instruction: tag %d", data[0])
+	}
+	inst := &Create2022{Idempotent: len(data) > 0 && data[0] == AssociatedTokenInstructionCreateIdempotent}
+	if len(data) == 0 {
+		// Clients predating CreateIdempotent send no data for Create.
+		return inst, inst.SetAccounts(accounts)
+	}
+	if err := decodeInstructionData(inst, data); err != nil {
+		return nil, fmt.Errorf("error while decoding %s: %w", AssociatedTokenInstructionName(data), err)
+	}
+	if err := inst.SetAccounts(accounts); err != nil {
+		return nil, err
+	}
+	return inst, nil
+}
+
+func lookupInstruction(data []byte) (func() TypedInstruction, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: empty instruction data", ErrUnknownInstruction)
+	}
+	tag := data[0]
+	if int(tag) >= len(instructionNames) {
+		return nil, fmt.Errorf("%w: tag %d", ErrUnknownInstruction, tag)
+	}
+	if newInstruction, ok := instructionRegistry[tag]; ok {
+		return newInstruction, nil
+	}
+	subRegistry, ok := extensionInstructionRegistry[tag]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedInstruction, instructionNames[tag])
+	}
+	if len(data) < 2 {
+		return nil, fmt.Errorf("%w: %s without sub-tag", ErrUnknownInstruction, instructionNames[tag])
+	}
+	newInstruction, ok := subRegistry[data[1]]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s sub-tag %d", ErrUnknownInstruction, instructionNames[tag], data[1])
+	}
+	return newInstruction, nil
+}
+
+// instructionRegistry maps instruction tags to builders.
+var instructionRegistry = map[uint8]func() TypedInstruction{
+	InstructionTransfer:                      func() TypedInstruction { return new(Transfer2022) },
+	InstructionTransferChecked:               func() TypedInstruction { return new(TransferChecked2022) },
+	InstructionApprove:                       func() TypedInstruction { return new(Approve2022) },
+	InstructionApproveChecked:                func() TypedInstruction { return new(ApproveChecked2022) },
+	InstructionRevoke:                        func() TypedInstruction { return new(Revoke2022) },
+	InstructionSetAuthority:                  func() TypedInstruction { return new(SetAuthority2022) },
+	InstructionMintTo:                        func() TypedInstruction { return new(MintTo2022) },
+	InstructionMintToChecked:                 func() TypedInstruction { return new(MintToChecked2022) },
+	InstructionBurn:                          func() TypedInstruction { return new(Burn2022) },
+	InstructionBurnChecked:                   func() TypedInstruction { return new(BurnChecked2022) },
+	InstructionCloseAccount:                  func() TypedInstruction { return new(Close2022) },
+	InstructionWithdrawExcessLamports:        func() TypedInstruction { return new(WithdrawExcessLamports2022) },
+	InstructionFreezeAccount:                 func() TypedInstruction { return new(FreezeAccount2022) },
+	InstructionThawAccount:                   func() TypedInstruction { return new(ThawAccount2022) },
+	InstructionInitializeMint:                func() TypedInstruction { return new(InitializeMintLegacy2022) },
+	InstructionInitializeAccount:             func() TypedInstruction { return new(InitializeAccountLegacy2022) },
+	InstructionInitializeAccount2:            func() TypedInstruction { return new(InitializeAccount2Legacy2022) },
+	InstructionInitializeMultisig:            func() TypedInstruction { return new(InitializeMultisigLegacy2022) },
+	InstructionInitializeMint2:               func() TypedInstruction { return new(InitializeMint2022) },
+	InstructionInitializeAccount3:            func() TypedInstruction { return new(InitializeAccount2022) },
+	InstructionInitializeMultisig2:           func() TypedInstruction { return new(InitializeMultisig2022) },
+	InstructionInitializeImmutableOwner:      func() TypedInstruction { return new(InitializeImmutableOwner2022) },
+	InstructionSyncNative:                    func() TypedInstruction { return new(SyncNative2022) },
+	InstructionCreateNativeMint:              func() TypedInstruction { return new(CreateNativeMint2022) },
+	InstructionGetAccountDataSize:            func() TypedInstruction { return new(GetAccountDataSize2022) },
+	InstructionAmountToUiAmount:              func() TypedInstruction { return new(AmountToUiAmount2022) },
+	InstructionUiAmountToAmount:              func() TypedInstruction { return new(UiAmountToAmount2022) },
+	InstructionReallocate:                    func() TypedInstruction { return new(Reallocate2022) },
+	InstructionInitializeMintCloseAuthority:  func() TypedInstruction { return new(InitializeMintCloseAuthority2022) },
+	InstructionInitializeNonTransferableMint: func() TypedInstruction { return new(InitializeNonTransferableMint2022) },
+	InstructionInitializePermanentDelegate:   func() TypedInstruction { return new(InitializePermanentDelegate2022) },
+}
+
+// extensionInstructionRegistry maps extension instruction tags and
+// sub-tags to builders.
+var extensionInstructionRegistry = map[uint8]map[uint8]func() TypedInstruction{
+	InstructionTransferFeeExtension: {
+		TransferFeeInstructionInitializeConfig:                   func() TypedInstruction { return new(InitializeTransferFeeConfig2022) },
+		TransferFeeInstructionTransferCheckedWithFee:             func() TypedInstruction { return new(TransferCheckedWithFee2022) },
+		TransferFeeInstructionWithdrawWithheldTokensFromMint:     func() TypedInstruction { return new(WithdrawWithheldTokensFromMint2022) },
+		TransferFeeInstructionWithdrawWithheldTokensFromAccounts: func() TypedInstruction { return new(WithdrawWithheldTokensFromAccounts2022) },
+		TransferFeeInstructionHarvestWithheldTokensToMint:        func() TypedInstruction { return new(HarvestWithheldTokensToMint2022) },
+		TransferFeeInstructionSetTransferFee:                     func() TypedInstruction { return new(SetTransferFee2022) },
+	},
+	InstructionDefaultAccountStateExtension: {
+		DefaultAccountStateInstructionInitialize: func() TypedInstruction { return new(InitializeDefaultAccountState2022) },
+		DefaultAccountStateInstructionUpdate:     func() TypedInstruction { return new(UpdateDefaultAccountState2022) },
+	},
+	InstructionMemoTransferExtension: {
+		ToggleInstructionEnable:  func() TypedInstruction { return new(EnableRequiredMemoTransfers2022) },
+		ToggleInstructionDisable: func() TypedInstruction { return new(DisableRequiredMemoTransfers2022) },
+	},
+	InstructionCpiGuardExtension: {
+		ToggleInstructionEnable:  func() TypedInstruction { return new(EnableCpiGuard2022) },
+		ToggleInstructionDisable: func() TypedInstruction { return new(DisableCpiGuard2022) },
+	},
+	InstructionInterestBearingMintExtension: {
+		ExtensionInstructionInitialize: func() TypedInstruction { return new(InitializeInterestBearingMint2022) },
+		ExtensionInstructionUpdate:     func() TypedInstruction { return new(UpdateInterestRate2022) },
+	},
+	InstructionTransferHookExtension: {
+		ExtensionInstructionInitialize: func() TypedInstruction { return new(InitializeTransferHook2022) },
+		ExtensionInstructionUpdate:     func() TypedInstruction { return new(UpdateTransferHook2022) },
+	},
+	InstructionMetadataPointerExtension: {
+		ExtensionInstructionInitialize: func() TypedInstruction { return new(InitializeMetadataPointer2022) },
+		ExtensionInstructionUpdate:     func() TypedInstruction { return new(UpdateMetadataPointer2022) },
+	},
+	InstructionGroupPointerExtension: {
+		ExtensionInstructionInitialize: func() TypedInstruction { return new(InitializeGroupPointer2022) },
+		ExtensionInstructionUpdate:     func() TypedInstruction { return new(UpdateGroupPointer2022) },
+	},
+	InstructionGroupMemberPointerExtension: {
+		ExtensionInstructionInitialize: func() TypedInstruction { return new(InitializeGroupMemberPointer2022) },
+		ExtensionInstructionUpdate:     func() TypedInstruction { return new(UpdateGroupMemberPointer2022) },
+	},
+	InstructionScaledUiAmountExtension: {
+		ExtensionInstructionInitialize: func() TypedInstruction { return new(InitializeScaledUiAmount2022) },
+		ExtensionInstructionUpdate:     func() TypedInstruction { return new(UpdateMultiplier2022) },
+	},
+	InstructionPausableExtension: {
+		PausableInstructionInitialize: func() TypedInstruction { return new(InitializePausableConfig2022) },
+		PausableInstructionPause:      func() TypedInstruction { return new(Pause2022) },
+		PausableInstructionResume:     func() TypedInstruction { return new(Resume2022) },
+	},
+}
@@ -0,0 +1,257 @@
+package token2022
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	treeout "github.com/gagliardetto/treeout"
+)
+
+func TestTransferChecked2022Encoding(t *testing.T) {
+
+	var (
+		source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		owner       = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	)
+
+	inst, err := NewTransferChecked2022Instruction(1_500_000, 6, source, mint, destination, owner).ValidateAndBuild()
+	if err != nil {
+		t.Fatalf("ValidateAndBuild: %v", err)
+	}
+	if inst.ProgramID() != solana.Token2022ProgramID {
+		t.Errorf("expected Token-2022 program, got %s", inst.ProgramID())
+	}
+	data, err := inst.Data()
+	if err != nil {
+		t.Fatalf("Data: %v", err)
+	}
+	want := []byte{12, 0x60, 0xe3, 0x16, 0, 0, 0, 0, 0, 6}
+	if !bytes.Equal(data, want) {
+		t.Errorf("unexpected data %v, want %v", data, want)
+	}
+	accounts := inst.Accounts()
+	if len(accounts) != 4 {
+		t.Fatalf("expected 4 accounts, got %d", len(accounts))
+	}
+	if !accounts[0].IsWritable || accounts[1].IsWritable || !accounts[2].IsWritable || !accounts[3].IsSigner {
+		t.Errorf("unexpected account flags")
+	}
+}
+
+func TestMultisigAuthority(t *testing.T) {
+
+	var (
+		account  = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		mint     = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		multisig = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		signer1  = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		signer2  = solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112")
+	)
+
+	accounts := NewFreezeAccount2022Instruction(account, mint, multisig, signer1, signer2).Build().Accounts()
+	if len(accounts) != 5 {
+		t.Fatalf("expected 5 accounts, got %d", len(accounts))
+	}
+	if accounts[2].IsSigner {
+		t.Error("a multisig authority must not sign")
+	}
+	if !accounts[3].IsSigner || !accounts[4].IsSigner {
+		t.Error("multisig signers must sign")
+	}
+}
+
+func TestExtensionInstructionEncoding(t *testing.T) {
+
+	var (
+		mint      = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		authority = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	)
+
+	data, err := NewSetTransferFee2022Instruction(50, 5000, mint, authority).Build().Data()
+	if err != nil {
+		t.Fatalf("Data: %v", err)
+	}
+	want := []byte{26, 5, 50, 0, 0x88, 0x13, 0, 0, 0, 0, 0, 0}
+	if !bytes.Equal(data, want) {
+		t.Errorf("unexpected SetTransferFee data %v, want %v", data, want)
+	}
+
+	data, err = NewInitializeMetadataPointer2022Instruction(nil, &mint, mint).Build().Data()
+	if err != nil {
+		t.Fatalf("Data: %v", err)
+	}
+	if len(data) != 66 || data[0] != 39 || data[1] != 0 || !bytes.Equal(data[2:34], make([]byte, 32)) || !bytes.Equal(data[34:], mint[:]) {
+		t.Errorf("unexpected InitializeMetadataPointer data %v", data)
+	}
+
+	data, err = NewInitializeMint2022Instruction(9, authority, nil, mint).Build().Data()
+	if err != nil {
+		t.Fatalf("Data: %v", err)
+	}
+	if len(data) != 35 || data[0] != 20 || data[1] != 9 || data[34] != 0 {
+		t.Errorf("unexpected InitializeMint2 data %v", data)
+	}
+}
+
+func TestDecodeInstructionRoundTrip(t *testing.T) {
+
+	var (
+		a = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		b = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		c = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		d = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		e = solana.MustPublicKeyFromBase58("So11111111111111111111111111111111111111112")
+	)
+
+	builders := []TypedInstruction{
+		NewTransfer2022Instruction(10, a, b, c),
+		NewTransferChecked2022Instruction(10, 6, a, b, c, d).SetAdditionalAccounts(solana.Meta(e)),
+		NewApprove2022Instruction(10, a, b, c, d, e),
+		NewApproveChecked2022Instruction(10, 6, a, b, c, d),
+		NewRevoke2022Instruction(a, b),
+		NewSetAuthority2022Instruction(AuthorityCloseMint, &d, a, b),
+		NewSetAuthority2022Instruction(AuthorityFreezeAccount, nil, a, b).ConfirmIrreversible(),
+		NewMintTo2022Instruction(10, a, b, c),
+		NewMintToChecked2022Instruction(10, 6, a, b, c),
+		NewBurn2022Instruction(10, a, b, c),
+		NewBurnChecked2022Instruction(10, 6, a, b, c),
+		NewClose2022Instruction(a, b, c),
+		NewWithdrawExcessLamports2022Instruction(a, b, c),
+		NewFreezeAccount2022Instruction(a, b, c),
+		NewThawAccount2022Instruction(a, b, c, d, e),
+		NewInitializeMint2022Instruction(6, a, &b, c),
+		NewInitializeAccount2022Instruction(a, b, c),
+		NewInitializeMultisig2022Instruction(2, a, b, c, d),
+		NewInitializeMintLegacy2022Instruction(6, a, nil, c),
+		NewInitializeAccountLegacy2022Instruction(a, b, c),
+		NewInitializeAccount2Legacy2022Instruction(a, b, c),
+		NewInitializeMultisigLegacy2022Instruction(1, a, b, c),
+		NewInitializeImmutableOwner2022Instruction(a),
+		NewSyncNative2022Instruction(a),
+		NewCreateNativeMint2022Instruction(a, b),
+		NewGetAccountDataSize2022Instruction([]ExtensionType{ExtensionImmutableOwner, ExtensionCpiGuard}, a),
+		NewAmountToUiAmount2022Instruction(10, a),
+		NewUiAmountToAmount2022Instruction("12.5", a),
+		NewReallocate2022Instruction([]ExtensionType{ExtensionMemoTransfer}, a, b, c),
+		NewInitializeMintCloseAuthority2022Instruction(&a, b),
+		NewInitializeNonTransferableMint2022Instruction(a),
+		NewInitializePermanentDelegate2022Instruction(a, b),
+		NewInitializeTransferFeeConfig2022Instruction(&a, nil, 100, 5000, b),
+		NewTransferCheckedWithFee2022Instruction(10, 6, 1, a, b, c, d),
+		NewWithdrawWithheldTokensFromMint2022Instruction(a, b, c),
+		NewWithdrawWithheldTokensFromAccounts2022Instruction(a, b, []solana.PublicKey{d, e}, c),
+		NewHarvestWithheldTokensToMint2022Instruction(a, b, c),
+		NewSetTransferFee2022Instruction(10, 20, a, b),
+		NewInitializeDefaultAccountState2022Instruction(AccountStateFrozen, a),
+		NewUpdateDefaultAccountState2022Instruction(AccountStateInitialized, a, b),
+		NewEnableRequiredMemoTransfers2022Instruction(a, b),
+		NewDisableRequiredMemoTransfers2022Instruction(a, b),
+		NewEnableCpiGuard2022Instruction(a, b),
+		NewDisableCpiGuard2022Instruction(a, b),
+		NewInitializeInterestBearingMint2022Instruction(&a, 500, b),
+		NewUpdateInterestRate2022Instruction(-20, a, b),
+		NewInitializeTransferHook2022Instruction(&a, &b, c),
+		NewUpdateTransferHook2022Instruction(nil, a, b),
+		NewInitializeMetadataPointer2022Instruction(&a, &b, c),
+		NewUpdateMetadataPointer2022Instruction(&a, b, c),
+		NewInitializeGroupPointer2022Instruction(&a, &b, c),
+		NewUpdateGroupPointer2022Instruction(&a, b, c),
+		NewInitializeGroupMemberPointer2022Instruction(&a, &b, c),
+		NewUpdateGroupMemberPointer2022Instruction(&a, b, c),
+		NewInitializeScaledUiAmount2022Instruction(&a, 1.5, b),
+		NewUpdateMultiplier2022Instruction(2, 1_700_000_000, a, b),
+		NewInitializePausableConfig2022Instruction(a, b),
+		NewPause2022Instruction(a, b),
+		NewResume2022Instruction(a, b),
+	}
+
+	for _, builder := range builders {
+		name := reflect.TypeOf(builder).Elem().Name()
+		if err := builder.Validate(); err != nil {
+			t.Errorf("%s: Validate: %v", name, err)
+			continue
+		}
+		built := builder.Build()
+		data, err := built.Data()
+		if err != nil {
+			t.Errorf("%s: Data: %v", name, err)
+			continue
+		}
+
+		decoded, err := DecodeInstruction(built.Accounts(), data)
+		if err != nil {
+			t.Errorf("%s: DecodeInstruction: %v", name, err)
+			continue
+		}
+		if got := reflect.TypeOf(decoded).Elem().Name(); got != name {
+			t.Errorf("decoded %s as %s", name, got)
+			continue
+		}
+		rebuilt := decoded.Build()
+		redata, err := rebuilt.Data()
+		if err != nil {
+			t.Errorf("%s: Data after decode: %v", name, err)
+			continue
+		}
+		if !bytes.Equal(data, redata) {
+			t.Errorf("%s: data changed after round trip: %v != %v", name, data, redata)
+		}
+		if !reflect.DeepEqual(built.Accounts(), rebuilt.Accounts()) {
+			t.Errorf("%s: accounts changed after round trip", name)
+		}
+
+		// The tree encoder must handle every builder.
+		decoded.EncodeToTree(treeout.New("test"))
+
+		// So must JSON, without losing anything Build needs.
+		encoded, err := json.Marshal(decoded)
+		if err != nil {
+			t.Errorf("%s: MarshalJSON: %v", name, err)
+			continue
+		}
+		fromJSON := reflect.New(reflect.TypeOf(decoded).Elem()).Interface().(TypedInstruction)
+		if err := json.Unmarshal(encoded, fromJSON); err != nil {
+			t.Errorf("%s: UnmarshalJSON: %v", name, err)
+			continue
+		}
+		jsonBuilt := fromJSON.Build()
+		jsonData, err := jsonBuilt.Data()
+		if err != nil {
+			t.Errorf("%s: Data after JSON: %v", name, err)
+			continue
+		}
+		if !bytes.Equal(data, jsonData) || !reflect.DeepEqual(built.Accounts(), jsonBuilt.Accounts()) {
+			t.Errorf("%s: instruction changed after JSON round trip: %s", name, encoded)
+		}
+	}
+}
+
+func TestDecodeInstructionErrors(t *testing.T) {
+	if _, err := DecodeInstruction(nil, nil); !errors.Is(err, ErrUnknownInstruction) {
+		t.Errorf("expected ErrUnknownInstruction for empty data, got %v", err)
+	}
+	if _, err := DecodeInstruction(nil, []byte{200}); !errors.Is(err, ErrUnknownInstruction) {
+		t.Errorf("expected ErrUnknownInstruction for unknown tag, got %v", err)
+	}
+	if _, err := DecodeInstruction(nil, []byte{InstructionConfidentialTransferExtension, 0}); !errors.Is(err, ErrUnsupportedInstruction) {
+		t.Errorf("expected ErrUnsupportedInstruction for confidential transfers, got %v", err)
+	}
+	if _, err := DecodeInstruction(nil, []byte{InstructionTransferFeeExtension, 99}); !errors.Is(err, ErrUnknownInstruction) {
+		t.Errorf("expected ErrUnknownInstruction for unknown sub-tag, got %v", err)
+	}
+	if _, err := DecodeInstruction(nil, []byte{InstructionTransferChecked, 1, 2}); err == nil {
+		t.Error("expected error for truncated data")
+	}
+	if _, err := DecodeInstruction(nil, []byte{InstructionRevoke}); err == nil {
+		t.Error("expected error for missing accounts")
+	}
+	if name := InstructionName([]byte{InstructionTransferChecked}); name != "TransferChecked" {
+		t.Errorf("unexpected name %s", name)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// BuildForRelayer builds the transaction and signs it with the registered
+// signers, leaving only the fee payer signature empty. The result is meant
+// for a relayer service that pays the fee, co-signs with CosignAsFeePayer
+// and submits the transaction (gasless transfers).
+func (b *TxBuilder) BuildForRelayer(ctx context.Context) (*solana.Transaction, error) {
+	tx, err := b.Build(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := SignTransaction(ctx, tx, b.signers...); err != nil {
+		return nil, err
+	}
+	for _, signer := range MissingSigners(tx) {
+		if !signer.Equals(b.feePayer) {
+			return nil, fmt.Errorf("missing signature for %s", signer)
+		}
+	}
+	return tx, nil
+}
+
+// CosignOption configures CosignAsFeePayer.
+type CosignOption func(*cosignConfig)
+
+type cosignConfig struct {
+	maxPriorityFee uint64
+}
+
+// WithMaxPriorityFee lets CosignAsFeePayer accept a compute unit price
+// whose priority fee, the price times the compute unit limit, is at most
+// lamports. Without it, any nonzero compute unit price is refused.
+func WithMaxPriorityFee(lamports uint64) CosignOption {
+	return func(config *cosignConfig) {
+		config.maxPriorityFee = lamports
+	}
+}
+
+// CosignAsFeePayer is the relayer side of BuildForRelayer. It checks that
+// the relayer key is the fee payer and is not referenced by any
+// instruction, so a client cannot use the relayer's signature to move its
+// funds, then adds the fee payer signature.
+//
+// The fee payer also pays the priority fee, so ComputeBudget instructions
+// other than SetComputeUnitLimit and SetComputeUnitPrice are refused, and
+// so is a priority fee above the maximum set by WithMaxPriorityFee.
+func CosignAsFeePayer(ctx context.Context, tx *solana.Transaction, feePayer Signer, opts ...CosignOption) error {
+	config := &cosignConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	payer := feePayer.Pubkey()
+	signers := tx.Message.Signers()
+	if len(signers) == 0 || !signers[0].Equals(payer) {
+		return fmt.Errorf("%s is not the fee payer of this transaction", payer)
+	}
+	var (
+		computeUnitLimit *uint32
+		computeUnitPrice *uint64
+		instructionCount int
+	)
+	for i, inst := range tx.Message.Instructions {
+		for _, index := range inst.Accounts {
+			key, err := tx.Message.Account(index)
+			if err != nil {
+				return fmt.Errorf("instruction %d: %w", i, err)
+			}
+			if key.Equals(payer) {
+				return fmt.Errorf("instruction %d references the fee payer %s", i, payer)
+			}
+		}
+		program, err := tx.Message.Account(inst.ProgramIDIndex)
+		if err != nil {
+			return fmt.Errorf("instruction %d: %w", i, err)
+		}
+		if !program.Equals(solana.ComputeBudget) {
+			instructionCount++
+			continue
+		}
+		data := inst.Data
+		switch {
+		case len(data) == 5 && data[0] == computeBudgetSetComputeUnitLimit && computeUnitLimit == nil:
+			limit := binary.LittleEndian.Uint32(data[1:])
+			computeUnitLimit = &limit
+		case len(data) == 9 && data[0] == computeBudgetSetComputeUnitPrice && computeUnitPrice == nil:
+			price := binary.LittleEndian.Uint64(data[1:])
+			computeUnitPrice = &price
+		default:
+			return fmt.Errorf("instruction %d: ComputeBudget instruction %x is not allowed", i, data)
+		}
+	}
+	if computeUnitPrice != nil {
+		limit := uint32(min(instructionCount*defaultComputeUnitsPerInstruction, maxComputeUnits))
+		if computeUnitLimit != nil {
+			limit = *computeUnitLimit
+		}
+		fee, err := priorityFee(*computeUnitPrice, limit)
+		if err != nil {
+			return err
+		}
+		if fee > config.maxPriorityFee {
+			return fmt.Errorf("priority fee of %d lamports exceeds the maximum of %d", fee, config.maxPriorityFee)
+		}
+	}
+	if err := SignTransaction(ctx, tx, feePayer); err != nil {
+		return err
+	}
+	if missing := MissingSigners(tx); len(missing) > 0 {
+		return fmt.Errorf("missing signatures for %v", missing)
+	}
+	return nil
+}
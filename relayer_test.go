@@ -0,0 +1,112 @@
+package token2022
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+func TestRelayerFlow(t *testing.T) {
+
+	var (
+		owner   = solana.NewWallet().PrivateKey
+		relayer = solana.NewWallet().PrivateKey
+		mint    = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		ctx     = context.Background()
+	)
+
+	tx, err := NewTxBuilder(newMockRPC()).
+		SetFeePayer(relayer.PublicKey()).
+		AddInstruction(NewCreate2022Instruction(owner.PublicKey(), owner.PublicKey(), mint).Build()).
+		AddSigner(NewPrivateKeySigner(owner)).
+		BuildForRelayer(ctx)
+	if err != nil {
+		t.Fatalf("Error building transaction: %v", err)
+	}
+	if missing := MissingSigners(tx); len(missing) != 1 || missing[0] != relayer.PublicKey() {
+		t.Fatalf("Expected only the fee payer signature to be missing, got %v", missing)
+	}
+
+	if err := CosignAsFeePayer(ctx, tx, NewPrivateKeySigner(relayer)); err != nil {
+		t.Fatalf("Error co-signing: %v", err)
+	}
+	if err := tx.VerifySignatures(); err != nil {
+		t.Errorf("Error verifying signatures: %v", err)
+	}
+}
+
+func TestCosignRejectsFeePayerInInstruction(t *testing.T) {
+
+	var (
+		relayer = solana.NewWallet().PrivateKey
+		mint    = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	)
+
+	// The relayer would fund the ATA rent, which it must refuse.
+	tx, err := NewTxBuilder(newMockRPC()).
+		SetFeePayer(relayer.PublicKey()).
+		AddInstruction(NewCreate2022Instruction(relayer.PublicKey(), relayer.PublicKey(), mint).Build()).
+		Build(context.Background())
+	if err != nil {
+		t.Fatalf("Error building transaction: %v", err)
+	}
+	if err := CosignAsFeePayer(context.Background(), tx, NewPrivateKeySigner(relayer)); err == nil {
+		t.Errorf("Expected co-signing to be refused")
+	}
+}
+
+func TestCosignLimitsComputeBudget(t *testing.T) {
+	var (
+		owner   = solana.NewWallet().PrivateKey
+		relayer = solana.NewWallet().PrivateKey
+		mint    = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		ctx     = context.Background()
+	)
+	computeBudget := func(tag byte, value uint64, size int) solana.Instruction {
+		data := binary.LittleEndian.AppendUint64([]byte{tag}, value)
+		return solana.NewInstruction(solana.ComputeBudget, nil, data[:1+size])
+	}
+	build := func(budget ...solana.Instruction) *solana.Transaction {
+		builder := NewTxBuilder(newMockRPC()).SetFeePayer(relayer.PublicKey())
+		for _, inst := range budget {
+			builder.AddInstruction(inst)
+		}
+		tx, err := builder.
+			AddInstruction(NewCreate2022Instruction(owner.PublicKey(), owner.PublicKey(), mint).Build()).
+			AddSigner(NewPrivateKeySigner(owner)).
+			BuildForRelayer(ctx)
+		if err != nil {
+			t.Fatalf("Error building transaction: %v", err)
+		}
+		return tx
+	}
+	limit := computeBudget(computeBudgetSetComputeUnitLimit, 100_000, 4)
+	// 100,000 units at 50,000 micro-lamports cost 5,000 lamports.
+	price := computeBudget(computeBudgetSetComputeUnitPrice, 50_000, 8)
+
+	tests := []struct {
+		name   string
+		tx     *solana.Transaction
+		opts   []CosignOption
+		refuse bool
+	}{
+		{"limit only", build(limit), nil, false},
+		{"price without maximum", build(limit, price), nil, true},
+		{"price below maximum", build(limit, price), []CosignOption{WithMaxPriorityFee(5_000)}, false},
+		{"price above maximum", build(limit, price), []CosignOption{WithMaxPriorityFee(4_999)}, true},
+		{"price with default limit", build(price), []CosignOption{WithMaxPriorityFee(5_000)}, true},
+		{"duplicate price", build(limit, price, price), []CosignOption{WithMaxPriorityFee(5_000)}, true},
+		{"heap frame", build(computeBudget(1, 64*1024, 4)), nil, true},
+	}
+	for _, tt := range tests {
+		err := CosignAsFeePayer(ctx, tt.tx, NewPrivateKeySigner(relayer), tt.opts...)
+		if tt.refuse && err == nil {
+			t.Errorf("%s: expected co-signing to be refused", tt.name)
+		}
+		if !tt.refuse && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"fmt"
+)
+
+// extensionLengths are the data lengths of the fixed-size extensions.
+var extensionLengths = map[ExtensionType]int{
+	ExtensionTransferFeeConfig:             108,
+	ExtensionTransferFeeAmount:             8,
+	ExtensionMintCloseAuthority:            32,
+	ExtensionConfidentialTransferMint:      65,
+	ExtensionConfidentialTransferAccount:   295,
+	ExtensionDefaultAccountState:           1,
+	ExtensionImmutableOwner:                0,
+	ExtensionMemoTransfer:                  1,
+	ExtensionNonTransferable:               0,
+	ExtensionInterestBearingConfig:         52,
+	ExtensionCpiGuard:                      1,
+	ExtensionPermanentDelegate:             32,
+	ExtensionNonTransferableAccount:        0,
+	ExtensionTransferHook:                  64,
+	ExtensionTransferHookAccount:           1,
+	ExtensionConfidentialTransferFeeConfig: 129,
+	ExtensionConfidentialTransferFeeAmount: 64,
+	ExtensionMetadataPointer:               64,
+	ExtensionGroupPointer:                  64,
+	ExtensionTokenGroup:                    80,
+	ExtensionGroupMemberPointer:            64,
+	ExtensionTokenGroupMember:              72,
+	ExtensionConfidentialMintBurn:          196,
+	ExtensionScaledUiAmount:                56,
+	ExtensionPausable:                      33,
+	ExtensionPausableAccount:               0,
+}
+
+// ExtensionLength returns the data length of an extension, without its
+// 4-byte type and length header. It reports false for TokenMetadata, whose
+// length depends on its content (see TokenMetadataLength), and for unknown
+// types.
+func ExtensionLength(t ExtensionType) (int, bool) {
+	length, ok := extensionLengths[t]
+	return length, ok
+}
+
+// MintSpace returns the account size of a mint with extensions, the space
+// to allocate before initializing them. TokenMetadata is initialized after
+// the mint and grows the account itself; add 4+TokenMetadataLength to the
+// space when funding the mint for it.
+func MintSpace(extensions ...ExtensionType) (int, error) {
+	return extendedSpace(MintSize, extensions)
+}
+
+// AccountSpace returns the account size of a token account with
+// extensions. Associated token accounts of Token-2022 mints always have
+// ImmutableOwner, plus the account extensions their mint requires, such as
+// TransferFeeAmount for mints with a TransferFeeConfig.
+func AccountSpace(extensions ...ExtensionType) (int, error) {
+	return extendedSpace(AccountSize, extensions)
+}
+
+func extendedSpace(base int, extensions []ExtensionType) (int, error) {
+	if len(extensions) == 0 {
+		return base, nil
+	}
+	space := AccountSize + 1
+	seen := make(map[ExtensionType]bool, len(extensions))
+	for _, t := range extensions {
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		length, ok := ExtensionLength(t)
+		if !ok {
+			return 0, fmt.Errorf("extension %s has no fixed length", t)
+		}
+		space += 4 + length
+	}
+	// An account the size of a multisig would be read as one, so the
+	// program pads it with an empty extension type.
+	if space == MultisigSize {
+		space += 2
+	}
+	return space, nil
+}
+
+// TokenMetadataLength returns the data length of the TokenMetadata
+// extension holding metadata.
+func TokenMetadataLength(metadata *TokenMetadata) int {
+	length := 32 + 32 + 4 + len(metadata.Name) + 4 + len(metadata.Symbol) + 4 + len(metadata.URI) + 4
+	for _, pair := range metadata.AdditionalMetadata {
+		length += 4 + len(pair[0]) + 4 + len(pair[1])
+	}
+	return length
+}
+
+// Rent holds the cluster's rent parameters.
+type Rent struct {
+	LamportsPerByteYear uint64
+	ExemptionThreshold  float64
+}
+
+// DefaultRent is the rent of mainnet-beta, devnet and testnet.
+var DefaultRent = Rent{
+	LamportsPerByteYear: 3480,
+	ExemptionThreshold:  2,
+}
+
+// accountStorageOverhead is the size of the account metadata the rent is
+// also charged for.
+const accountStorageOverhead = 128
+
+// ExemptLamports returns the minimum balance for an account of dataLen
+// bytes to be rent exempt, as getMinimumBalanceForRentExemption computes
+// it.
+func (r Rent) ExemptLamports(dataLen int) uint64 {
+	return uint64(float64(uint64(accountStorageOverhead+dataLen)*r.LamportsPerByteYear) * r.ExemptionThreshold)
+}
+
+// RentExemptLamports returns the minimum balance for an account of dataLen
+// bytes to be rent exempt under DefaultRent, without an RPC round trip.
+func RentExemptLamports(dataLen int) uint64 {
+	return DefaultRent.ExemptLamports(dataLen)
+}
+
+// RequiredAccountExtensions returns the extensions the program adds to a
+// token account of mint when initializing it: ImmutableOwner, which
+// associated token accounts always have, and the account counterparts of
+// the mint's extensions. AccountSpace of the result sizes the account.
+func RequiredAccountExtensions(mint *Mint) []ExtensionType {
+	extensions := []ExtensionType{ExtensionImmutableOwner}
+	for _, pair := range [...][2]ExtensionType{
+		{ExtensionTransferFeeConfig, ExtensionTransferFeeAmount},
+		{ExtensionNonTransferable, ExtensionNonTransferableAccount},
+		{ExtensionTransferHook, ExtensionTransferHookAccount},
+		{ExtensionPausable, ExtensionPausableAccount},
+	} {
+		if _, ok := mint.Extension(pair[0]); ok {
+			extensions = append(extensions, pair[1])
+		}
+	}
+	return extensions
+}
@@ -0,0 +1,54 @@
+package token2022
+
+import "testing"
+
+func TestRentExemptLamports(t *testing.T) {
+	for _, tt := range []struct {
+		dataLen int
+		want    uint64
+	}{
+		{0, 890_880},
+		{MintSize, 1_461_600},
+		{AccountSize + 5, 2_074_080},
+	} {
+		if got := RentExemptLamports(tt.dataLen); got != tt.want {
+			t.Errorf("Expected %d lamports for %d bytes, got %d", tt.want, tt.dataLen, got)
+		}
+	}
+	rent := Rent{LamportsPerByteYear: 1000, ExemptionThreshold: 1.5}
+	if got := rent.ExemptLamports(72); got != 300_000 {
+		t.Errorf("Expected 300000 lamports, got %d", got)
+	}
+}
+
+func TestMintSpace(t *testing.T) {
+	for _, tt := range []struct {
+		extensions []ExtensionType
+		want       int
+	}{
+		{nil, MintSize},
+		{[]ExtensionType{ExtensionTransferFeeConfig}, 278},
+		{[]ExtensionType{ExtensionMintCloseAuthority, ExtensionMetadataPointer}, 270},
+		{[]ExtensionType{ExtensionPermanentDelegate, ExtensionPermanentDelegate}, 202},
+	} {
+		got, err := MintSpace(tt.extensions...)
+		if err != nil {
+			t.Fatalf("MintSpace(%v): %v", tt.extensions, err)
+		}
+		if got != tt.want {
+			t.Errorf("Expected %d bytes for %v, got %d", tt.want, tt.extensions, got)
+		}
+	}
+	if _, err := MintSpace(ExtensionTokenMetadata); err == nil {
+		t.Errorf("Expected TokenMetadata to have no fixed length")
+	}
+
+	if got, _ := AccountSpace(ExtensionImmutableOwner); got != AccountSize+5 {
+		t.Errorf("Expected %d bytes, got %d", AccountSize+5, got)
+	}
+	// 166 + 4+108 + 4+64 + 4+0 + 4+1 would be the multisig size.
+	multisig := []ExtensionType{ExtensionTransferFeeConfig, ExtensionTransferHook, ExtensionNonTransferable, ExtensionDefaultAccountState}
+	if got, _ := MintSpace(multisig...); got != MultisigSize+2 {
+		t.Errorf("Expected %d bytes, got %d", MultisigSize+2, got)
+	}
+}
@@ -0,0 +1,241 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+	"golang.org/x/time/rate"
+)
+
+// JSON-RPC error codes returned by Solana nodes that lag behind the
+// cluster. Retrying, possibly against another node, usually succeeds.
+const (
+	rpcBlockNotAvailable  = -32004
+	rpcNodeUnhealthy      = -32005
+	rpcMinContextSlot     = -32016
+	rpcSimulationFailed   = -32002
+	blockhashNotFoundText = "Blockhash not found"
+)
+
+// RetryPolicy controls how ThrottledClient retries failed requests.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of tries, including the first one.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry. Each further
+	// retry waits Multiplier times longer, up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	// RateLimitBackoff is the minimum wait after an HTTP 429 response.
+	RateLimitBackoff time.Duration
+}
+
+// DefaultRetryPolicy tries five times, backing off from 200ms to 5s.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:      5,
+		InitialBackoff:   200 * time.Millisecond,
+		MaxBackoff:       5 * time.Second,
+		Multiplier:       2,
+		RateLimitBackoff: time.Second,
+	}
+}
+
+// Backoff returns the wait before the given retry (1 for the first
+// retry), with up to 20% jitter so that concurrent callers spread out.
+func (p RetryPolicy) Backoff(retry int, err error) time.Duration {
+	backoff := float64(p.InitialBackoff)
+	for i := 1; i < retry; i++ {
+		backoff *= p.Multiplier
+		if backoff >= float64(p.MaxBackoff) {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+	if IsRateLimited(err) && backoff < float64(p.RateLimitBackoff) {
+		backoff = float64(p.RateLimitBackoff)
+	}
+	return time.Duration(backoff * (1 + 0.2*rand.Float64()))
+}
+
+// IsRateLimited reports whether err is an HTTP 429 response.
+func IsRateLimited(err error) bool {
+	var httpErr *jsonrpc.HTTPError
+	return errors.As(err, &httpErr) && httpErr.Code == http.StatusTooManyRequests
+}
+
+// IsRetryable reports whether a failed request may succeed when repeated:
+// transport errors, HTTP 429 and 5xx responses, and errors from nodes
+// that are behind the cluster, such as an unreached minContextSlot or a
+// blockhash the node has not seen yet.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, rpc.ErrNotFound) {
+		return false
+	}
+	var httpErr *jsonrpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code == http.StatusTooManyRequests || httpErr.Code >= 500
+	}
+	var rpcErr *jsonrpc.RPCError
+	if errors.As(err, &rpcErr) {
+		switch rpcErr.Code {
+		case rpcBlockNotAvailable, rpcNodeUnhealthy, rpcMinContextSlot:
+			return true
+		case rpcSimulationFailed:
+			return strings.Contains(rpcErr.Message, blockhashNotFoundText)
+		}
+		return false
+	}
+	return true
+}
+
+// ThrottledClient wraps an RPCClient with a token-bucket rate limiter and
+// retries with exponential backoff. Every helper that takes an RPCClient
+// can be given a ThrottledClient, which keeps mint scans and holder
+// snapshots within the limits of public RPC endpoints.
+//
+// ThrottledClient is safe for concurrent use.
+type ThrottledClient struct {
+	client  RPCClient
+	limiter *rate.Limiter
+	retry   RetryPolicy
+	logger  Logger
+	metrics Metrics
+}
+
+// NewThrottledClient allows requestsPerSecond requests on average with
+// bursts of up to burst requests, and uses DefaultRetryPolicy.
+func NewThrottledClient(client RPCClient, requestsPerSecond float64, burst int) *ThrottledClient {
+	return &ThrottledClient{
+		client:  client,
+		limiter: rate.NewLimiter(rate.Limit(requestsPerSecond), burst),
+		retry:   DefaultRetryPolicy(),
+	}
+}
+
+func (c *ThrottledClient) SetRetryPolicy(policy RetryPolicy) *ThrottledClient {
+	c.retry = policy
+	return c
+}
+
+// SetLogger logs every retried request at warning level.
+func (c *ThrottledClient) SetLogger(logger Logger) *ThrottledClient {
+	c.logger = logger
+	return c
+}
+
+// SetMetrics records the duration of every request and counts retries.
+func (c *ThrottledClient) SetMetrics(metrics Metrics) *ThrottledClient {
+	c.metrics = metrics
+	return c
+}
+
+// SetRateLimit changes the rate limit; a requestsPerSecond of 0 or less
+// disables it.
+func (c *ThrottledClient) SetRateLimit(requestsPerSecond float64, burst int) *ThrottledClient {
+	if requestsPerSecond <= 0 {
+		c.limiter.SetLimit(rate.Inf)
+	} else {
+		c.limiter.SetLimit(rate.Limit(requestsPerSecond))
+	}
+	c.limiter.SetBurst(burst)
+	return c
+}
+
+func (c *ThrottledClient) GetLatestBlockhash(ctx context.Context, commitment rpc.CommitmentType) (out *rpc.GetLatestBlockhashResult, err error) {
+	err = c.do(ctx, "getLatestBlockhash", func() (err error) {
+		out, err = c.client.GetLatestBlockhash(ctx, commitment)
+		return err
+	})
+	return out, err
+}
+
+func (c *ThrottledClient) GetBlockHeight(ctx context.Context, commitment rpc.CommitmentType) (out uint64, err error) {
+	err = c.do(ctx, "getBlockHeight", func() (err error) {
+		out, err = c.client.GetBlockHeight(ctx, commitment)
+		return err
+	})
+	return out, err
+}
+
+func (c *ThrottledClient) GetAccountInfoWithOpts(ctx context.Context, account solana.PublicKey, opts *rpc.GetAccountInfoOpts) (out *rpc.GetAccountInfoResult, err error) {
+	err = c.do(ctx, "getAccountInfo", func() (err error) {
+		out, err = c.client.GetAccountInfoWithOpts(ctx, account, opts)
+		return err
+	})
+	return out, err
+}
+
+func (c *ThrottledClient) SendTransactionWithOpts(ctx context.Context, transaction *solana.Transaction, opts rpc.TransactionOpts) (out solana.Signature, err error) {
+	err = c.do(ctx, "sendTransaction", func() (err error) {
+		out, err = c.client.SendTransactionWithOpts(ctx, transaction, opts)
+		return err
+	})
+	return out, err
+}
+
+func (c *ThrottledClient) GetSignatureStatuses(ctx context.Context, searchTransactionHistory bool, transactionSignatures ...solana.Signature) (out *rpc.GetSignatureStatusesResult, err error) {
+	err = c.do(ctx, "getSignatureStatuses", func() (err error) {
+		out, err = c.client.GetSignatureStatuses(ctx, searchTransactionHistory, transactionSignatures...)
+		return err
+	})
+	return out, err
+}
+
+func (c *ThrottledClient) do(ctx context.Context, method string, call func() error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		if waitErr := c.limiter.Wait(ctx); waitErr != nil {
+			if err != nil {
+				return err
+			}
+			return waitErr
+		}
+		start := time.Now()
+		err = call()
+		observeMetric(c.metrics, MetricRPCSeconds, time.Since(start).Seconds(), method)
+		if err == nil || attempt >= c.retry.MaxAttempts || !IsRetryable(err) {
+			return err
+		}
+
+		backoff := c.retry.Backoff(attempt, err)
+		logEvent(ctx, c.logger, slog.LevelWarn, "request retried", "attempt", attempt, "backoff", backoff, "error", err)
+		addMetric(c.metrics, MetricRetries, 1, "request")
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+var _ RPCClient = (*ThrottledClient)(nil)
@@ -0,0 +1,125 @@
+package token2022
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	rpc "github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+// flakyRPC fails GetBlockHeight with the queued errors before succeeding.
+type flakyRPC struct {
+	*mockRPC
+	failures []error
+}
+
+func (f *flakyRPC) GetBlockHeight(ctx context.Context, commitment rpc.CommitmentType) (uint64, error) {
+	if len(f.failures) > 0 {
+		err := f.failures[0]
+		f.failures = f.failures[1:]
+		f.calls["getBlockHeight"]++
+		return 0, err
+	}
+	return f.mockRPC.GetBlockHeight(ctx, commitment)
+}
+
+func fastRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:      4,
+		InitialBackoff:   time.Millisecond,
+		MaxBackoff:       4 * time.Millisecond,
+		Multiplier:       2,
+		RateLimitBackoff: 2 * time.Millisecond,
+	}
+}
+
+func TestThrottledClientRetries(t *testing.T) {
+	flaky := &flakyRPC{
+		mockRPC: newMockRPC(),
+		failures: []error{
+			jsonrpc.NewHTTPError(http.StatusTooManyRequests, errors.New("too many requests")),
+			&jsonrpc.RPCError{Code: -32016, Message: "Minimum context slot has not been reached"},
+			errors.New("connection reset by peer"),
+		},
+	}
+	client := NewThrottledClient(flaky, 1000, 10).SetRetryPolicy(fastRetryPolicy())
+
+	height, err := client.GetBlockHeight(context.Background(), rpc.CommitmentConfirmed)
+	if err != nil {
+		t.Fatalf("GetBlockHeight: %v", err)
+	}
+	if height != 850 {
+		t.Errorf("expected height 850, got %d", height)
+	}
+	if flaky.calls["getBlockHeight"] != 4 {
+		t.Errorf("expected 4 calls, got %d", flaky.calls["getBlockHeight"])
+	}
+}
+
+func TestThrottledClientGivesUp(t *testing.T) {
+	unavailable := jsonrpc.NewHTTPError(http.StatusServiceUnavailable, errors.New("unavailable"))
+	flaky := &flakyRPC{
+		mockRPC:  newMockRPC(),
+		failures: []error{unavailable, unavailable, unavailable, unavailable, unavailable},
+	}
+	client := NewThrottledClient(flaky, 1000, 10).SetRetryPolicy(fastRetryPolicy())
+
+	if _, err := client.GetBlockHeight(context.Background(), rpc.CommitmentConfirmed); err != unavailable {
+		t.Fatalf("expected the last error, got %v", err)
+	}
+	if flaky.calls["getBlockHeight"] != 4 {
+		t.Errorf("expected MaxAttempts calls, got %d", flaky.calls["getBlockHeight"])
+	}
+}
+
+func TestThrottledClientDoesNotRetryApplicationErrors(t *testing.T) {
+	simulationFailed := &jsonrpc.RPCError{Code: -32002, Message: "Transaction simulation failed: Error processing Instruction 0"}
+	flaky := &flakyRPC{mockRPC: newMockRPC(), failures: []error{simulationFailed}}
+	client := NewThrottledClient(flaky, 1000, 10).SetRetryPolicy(fastRetryPolicy())
+
+	if _, err := client.GetBlockHeight(context.Background(), rpc.CommitmentConfirmed); err != simulationFailed {
+		t.Fatalf("expected the simulation error, got %v", err)
+	}
+	if flaky.calls["getBlockHeight"] != 1 {
+		t.Errorf("expected a single call, got %d", flaky.calls["getBlockHeight"])
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{errors.New("EOF"), true},
+		{jsonrpc.NewHTTPError(http.StatusBadGateway, errors.New("bad gateway")), true},
+		{jsonrpc.NewHTTPError(http.StatusForbidden, errors.New("forbidden")), false},
+		{&jsonrpc.RPCError{Code: -32005, Message: "Node is behind by 42 slots"}, true},
+		{&jsonrpc.RPCError{Code: -32002, Message: "Transaction simulation failed: Blockhash not found"}, true},
+		{&jsonrpc.RPCError{Code: -32602, Message: "Invalid params"}, false},
+		{rpc.ErrNotFound, false},
+		{context.Canceled, false},
+	}
+	for _, c := range cases {
+		if got := IsRetryable(c.err); got != c.want {
+			t.Errorf("IsRetryable(%v) = %v, want %v", c.err, got, c.want)
+		}
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := DefaultRetryPolicy()
+	if b := policy.Backoff(1, errors.New("EOF")); b < 200*time.Millisecond || b > 240*time.Millisecond {
+		t.Errorf("unexpected first backoff %v", b)
+	}
+	if b := policy.Backoff(10, errors.New("EOF")); b < 5*time.Second || b > 6*time.Second {
+		t.Errorf("expected backoff capped at MaxBackoff, got %v", b)
+	}
+	rateLimited := jsonrpc.NewHTTPError(http.StatusTooManyRequests, errors.New("slow down"))
+	if b := policy.Backoff(1, rateLimited); b < time.Second {
+		t.Errorf("expected at least RateLimitBackoff after a 429, got %v", b)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"fmt"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// Delegation is an active delegation on a token account: a delegate that
+// can still move DelegatedAmount tokens out of Account.
+type Delegation struct {
+	Account  solana.PublicKey
+	Mint     solana.PublicKey
+	Delegate solana.PublicKey
+	// DelegatedAmount is the remaining allowance of Delegate.
+	DelegatedAmount uint64
+	// Amount is the balance of Account.
+	Amount uint64
+	Frozen bool
+}
+
+// RevokePlan revokes the delegations of an owner.
+type RevokePlan struct {
+	// Transactions holds the Revoke instructions of each transaction, paid
+	// for and signed by the owner. Each fits in MaxTransactionSize.
+	Transactions [][]solana.Instruction
+	// Revoked lists the delegations revoked by Transactions, in order.
+	Revoked []Delegation
+	// Skipped lists the delegations that cannot be revoked yet.
+	Skipped []SkippedAccount
+}
+
+// FindDelegations lists the Token-2022 accounts of owner at commitment
+// and returns those with a delegate and a non-zero delegated amount, such
+// as the approvals left behind by a compromised dapp.
+func FindDelegations(ctx context.Context, client SweepClient, owner solana.PublicKey, commitment rpc.CommitmentType) ([]Delegation, error) {
+	out, err := client.GetTokenAccountsByOwner(ctx, owner,
+		&rpc.GetTokenAccountsConfig{ProgramId: solana.Token2022ProgramID.ToPointer()},
+		&rpc.GetTokenAccountsOpts{Commitment: commitment, Encoding: solana.EncodingBase64},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error while listing token accounts: %w", err)
+	}
+	var delegations []Delegation
+	for _, keyed := range out.Value {
+		account, err := DecodeTokenAccount(keyed.Account.Data.GetBinary())
+		if err != nil || !account.Owner.Equals(owner) || account.Delegate == nil || account.DelegatedAmount == 0 {
+			continue
+		}
+		delegations = append(delegations, Delegation{
+			Account:         keyed.Pubkey,
+			Mint:            account.Mint,
+			Delegate:        *account.Delegate,
+			DelegatedAmount: account.DelegatedAmount,
+			Amount:          account.Amount,
+			Frozen:          account.IsFrozen(),
+		})
+	}
+	return delegations, nil
+}
+
+// PlanRevokes batches one Revoke per delegation into transactions signed
+// by owner. Frozen accounts are reported in Skipped, as the program
+// refuses to revoke their delegate until they are thawed.
+func PlanRevokes(owner solana.PublicKey, delegations []Delegation) (*RevokePlan, error) {
+	plan := &RevokePlan{}
+	var batch []solana.Instruction
+	for _, delegation := range delegations {
+		if delegation.Frozen {
+			plan.Skipped = append(plan.Skipped, SkippedAccount{Account: delegation.Account, Reason: "account is frozen"})
+			continue
+		}
+		revoke := NewRevoke2022Instruction(delegation.Account, owner).Build()
+		size, err := transactionSize(owner, append(batch[:len(batch):len(batch)], revoke), nil)
+		if err != nil {
+			return nil, err
+		}
+		if size > MaxTransactionSize && len(batch) > 0 {
+			plan.Transactions = append(plan.Transactions, batch)
+			batch = nil
+		}
+		batch = append(batch, revoke)
+		plan.Revoked = append(plan.Revoked, delegation)
+	}
+	if len(batch) > 0 {
+		plan.Transactions = append(plan.Transactions, batch)
+	}
+	return plan, nil
+}
+
+// SweepDelegations finds the active delegations of owner and plans
+// revoking all of them.
+func SweepDelegations(ctx context.Context, client SweepClient, owner solana.PublicKey, commitment rpc.CommitmentType) (*RevokePlan, error) {
+	delegations, err := FindDelegations(ctx, client, owner, commitment)
+	if err != nil {
+		return nil, err
+	}
+	return PlanRevokes(owner, delegations)
+}
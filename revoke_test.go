@@ -0,0 +1,84 @@
+package token2022
+
+import (
+	"context"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestSweepDelegations(t *testing.T) {
+	var (
+		wallet   = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		delegate = solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR")
+		client   = newMockRPC()
+	)
+	const delegated = 60
+	for i := 0; i < delegated; i++ {
+		client.setAccount(solana.NewWallet().PublicKey(), solana.Token2022ProgramID, EncodeTokenAccount(&TokenAccount{
+			Mint: solana.NewWallet().PublicKey(), Owner: wallet, Amount: 100, State: AccountStateInitialized,
+			Delegate: &delegate, DelegatedAmount: 50,
+		}))
+	}
+	frozen := solana.NewWallet().PublicKey()
+	client.setAccount(frozen, solana.Token2022ProgramID, EncodeTokenAccount(&TokenAccount{
+		Mint: solana.NewWallet().PublicKey(), Owner: wallet, State: AccountStateFrozen,
+		Delegate: &delegate, DelegatedAmount: 1,
+	}))
+	// A delegate whose allowance was spent is harmless.
+	client.setAccount(solana.NewWallet().PublicKey(), solana.Token2022ProgramID, EncodeTokenAccount(&TokenAccount{
+		Mint: solana.NewWallet().PublicKey(), Owner: wallet, State: AccountStateInitialized, Delegate: &delegate,
+	}))
+	client.setAccount(solana.NewWallet().PublicKey(), solana.Token2022ProgramID, encodeTokenAccount(solana.NewWallet().PublicKey(), wallet, 5))
+
+	delegations, err := FindDelegations(context.Background(), client, wallet, rpc.CommitmentConfirmed)
+	if err != nil {
+		t.Fatalf("FindDelegations: %v", err)
+	}
+	if len(delegations) != delegated+1 {
+		t.Fatalf("Expected %d delegations, got %d", delegated+1, len(delegations))
+	}
+	for _, delegation := range delegations {
+		if delegation.Delegate != delegate || (delegation.DelegatedAmount != 50 && !delegation.Frozen) {
+			t.Errorf("Unexpected delegation %+v", delegation)
+		}
+	}
+
+	plan, err := SweepDelegations(context.Background(), client, wallet, rpc.CommitmentConfirmed)
+	if err != nil {
+		t.Fatalf("SweepDelegations: %v", err)
+	}
+	if len(plan.Revoked) != delegated {
+		t.Errorf("Expected %d revoked delegations, got %d", delegated, len(plan.Revoked))
+	}
+	if len(plan.Skipped) != 1 || plan.Skipped[0].Account != frozen {
+		t.Errorf("Expected the frozen account to be skipped, got %v", plan.Skipped)
+	}
+	if len(plan.Transactions) < 2 {
+		t.Fatalf("Expected the revokes to span several transactions, got %d", len(plan.Transactions))
+	}
+	instructions := 0
+	for i, batch := range plan.Transactions {
+		size, err := transactionSize(wallet, batch, nil)
+		if err != nil {
+			t.Fatalf("transactionSize: %v", err)
+		}
+		if size > MaxTransactionSize {
+			t.Errorf("Expected transaction %d to fit, got %d bytes", i, size)
+		}
+		for _, inst := range batch {
+			data, err := inst.Data()
+			if err != nil {
+				t.Fatalf("Data: %v", err)
+			}
+			if name := InstructionName(data); name != "Revoke" {
+				t.Errorf("Expected a Revoke, got %s", name)
+			}
+		}
+		instructions += len(batch)
+	}
+	if instructions != delegated {
+		t.Errorf("Expected one revoke per account, got %d instructions", instructions)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"fmt"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// mintAuthorityOffsets locates each mint authority inside its extension.
+// Every one of them is an OptionalNonZeroPubkey, all zeroes when unset.
+var mintAuthorityOffsets = []struct {
+	authorityType AuthorityType
+	extension     ExtensionType
+	offset        int
+}{
+	{AuthorityTransferFeeConfig, ExtensionTransferFeeConfig, 0},
+	{AuthorityWithheldWithdraw, ExtensionTransferFeeConfig, 32},
+	{AuthorityCloseMint, ExtensionMintCloseAuthority, 0},
+	{AuthorityInterestRate, ExtensionInterestBearingConfig, 0},
+	{AuthorityPermanentDelegate, ExtensionPermanentDelegate, 0},
+	{AuthorityConfidentialTransferMint, ExtensionConfidentialTransferMint, 0},
+	{AuthorityTransferHookProgramID, ExtensionTransferHook, 0},
+	{AuthorityConfidentialTransferFeeConfig, ExtensionConfidentialTransferFeeConfig, 0},
+	{AuthorityMetadataPointer, ExtensionMetadataPointer, 0},
+	{AuthorityGroupPointer, ExtensionGroupPointer, 0},
+	{AuthorityGroupMemberPointer, ExtensionGroupMemberPointer, 0},
+	{AuthorityScaledUiAmount, ExtensionScaledUiAmount, 0},
+	{AuthorityPause, ExtensionPausable, 0},
+}
+
+// MintAuthorities returns the authority types of mint held by authority,
+// in AuthorityType order. Pass the result to RotateAuthorities to hand
+// every one of them over.
+func MintAuthorities(mint *Mint, authority solana.PublicKey) []AuthorityType {
+	var types []AuthorityType
+	if mint.MintAuthority != nil && mint.MintAuthority.Equals(authority) {
+		types = append(types, AuthorityMintTokens)
+	}
+	if mint.FreezeAuthority != nil && mint.FreezeAuthority.Equals(authority) {
+		types = append(types, AuthorityFreezeAccount)
+	}
+	for _, o := range mintAuthorityOffsets {
+		data, ok := mint.Extension(o.extension)
+		if !ok || len(data) < o.offset+32 {
+			continue
+		}
+		if solana.PublicKeyFromBytes(data[o.offset : o.offset+32]).Equals(authority) {
+			types = append(types, o.authorityType)
+		}
+	}
+	return types
+}
+
+// RotateAuthorities returns one SetAuthority2022 instruction per type in
+// which, each moving that authority of mint from oldSigner to
+// newAuthority. Sending them in a single transaction hands the mint over
+// atomically: either every authority moves or none does.
+//
+// AccountOwner and CloseAccount belong to token accounts, not mints, and
+// are rejected, as are repeated types.
+func RotateAuthorities(
+	mint solana.PublicKey,
+	oldSigner solana.PublicKey,
+	newAuthority solana.PublicKey,
+	which []AuthorityType,
+	multisigSigners ...solana.PublicKey,
+) ([]solana.Instruction, error) {
+	if len(which) == 0 {
+		return nil, errNotSet("authority types")
+	}
+	if newAuthority.IsZero() {
+		return nil, errNotSet("NewAuthority")
+	}
+	seen := make(map[AuthorityType]bool, len(which))
+	instructions := make([]solana.Instruction, 0, len(which))
+	for _, authorityType := range which {
+		switch {
+		case authorityType == AuthorityAccountOwner || authorityType == AuthorityCloseAccount:
+			return nil, errInvalidField("which", "%s is a token account authority", authorityType)
+		case int(authorityType) >= len(authorityTypeNames):
+			return nil, errInvalidField("which", "unknown %s", authorityType)
+		case seen[authorityType]:
+			return nil, errInvalidField("which", "%s listed twice", authorityType)
+		}
+		seen[authorityType] = true
+		inst, err := NewSetAuthority2022Instruction(authorityType, &newAuthority, mint, oldSigner, multisigSigners...).ValidateAndBuild()
+		if err != nil {
+			return nil, fmt.Errorf("error while rotating the %s authority: %w", authorityType, err)
+		}
+		instructions = append(instructions, inst)
+	}
+	return instructions, nil
+}
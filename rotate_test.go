@@ -0,0 +1,77 @@
+package token2022
+
+import (
+	"errors"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+func TestRotateAuthorities(t *testing.T) {
+	var (
+		mint  = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		old   = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		other = solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR")
+		next  = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+	)
+	feeConfig := make([]byte, transferFeeConfigSize)
+	copy(feeConfig, old[:])
+	copy(feeConfig[32:], other[:])
+	pointer := make([]byte, 64)
+	copy(pointer, old[:])
+	decoded := &Mint{
+		MintAuthority:   &old,
+		FreezeAuthority: &other,
+		IsInitialized:   true,
+		Extensions: []Extension{
+			{Type: ExtensionTransferFeeConfig, Data: feeConfig},
+			{Type: ExtensionMetadataPointer, Data: pointer},
+		},
+	}
+
+	which := MintAuthorities(decoded, old)
+	expected := []AuthorityType{AuthorityMintTokens, AuthorityTransferFeeConfig, AuthorityMetadataPointer}
+	if len(which) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, which)
+	}
+	for i := range expected {
+		if which[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v", expected, which)
+		}
+	}
+
+	instructions, err := RotateAuthorities(mint, old, next, which)
+	if err != nil {
+		t.Fatalf("RotateAuthorities: %v", err)
+	}
+	if len(instructions) != len(which) {
+		t.Fatalf("Expected %d instructions, got %d", len(which), len(instructions))
+	}
+	for i, inst := range instructions {
+		data, err := inst.Data()
+		if err != nil {
+			t.Fatalf("Data: %v", err)
+		}
+		typed, err := DecodeInstruction(inst.Accounts(), data)
+		if err != nil {
+			t.Fatalf("DecodeInstruction: %v", err)
+		}
+		set, ok := typed.(*SetAuthority2022)
+		if !ok {
+			t.Fatalf("Expected SetAuthority2022, got %T", typed)
+		}
+		if set.AuthorityType != which[i] || set.NewAuthority == nil || !set.NewAuthority.Equals(next) || !set.Authority.Equals(old) {
+			t.Errorf("Unexpected instruction %d: %+v", i, set)
+		}
+	}
+
+	if _, err := RotateAuthorities(mint, old, next, nil); !errors.Is(err, ErrNotSet) {
+		t.Errorf("Expected ErrNotSet, got %v", err)
+	}
+	if _, err := RotateAuthorities(mint, old, next, []AuthorityType{AuthorityCloseAccount}); !errors.Is(err, ErrInvalidField) {
+		t.Errorf("Expected ErrInvalidField for an account authority, got %v", err)
+	}
+	if _, err := RotateAuthorities(mint, old, next, []AuthorityType{AuthorityPause, AuthorityPause}); !errors.Is(err, ErrInvalidField) {
+		t.Errorf("Expected ErrInvalidField for a repeated type, got %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// ResolveProgram returns the token program that owns mint:
+// solana.TokenProgramID for mints of the original SPL Token program or
+// solana.Token2022ProgramID. It fails for accounts owned by any other
+// program.
+func ResolveProgram(ctx context.Context, client RPCClient, mint solana.PublicKey, commitment rpc.CommitmentType) (solana.PublicKey, error) {
+	out, err := client.GetAccountInfoWithOpts(ctx, mint, &rpc.GetAccountInfoOpts{
+		Encoding:   solana.EncodingBase64,
+		Commitment: commitment,
+		DataSlice:  &rpc.DataSlice{Offset: new(uint64), Length: new(uint64)},
+	})
+	if errors.Is(err, rpc.ErrNotFound) {
+		return solana.PublicKey{}, fmt.Errorf("%w: %s: %w", ErrAccountNotFound, mint, err)
+	}
+	if err != nil {
+		return solana.PublicKey{}, fmt.Errorf("error while fetching account %s: %w", mint, err)
+	}
+	if out == nil || out.Value == nil {
+		return solana.PublicKey{}, fmt.Errorf("%w: %s", ErrAccountNotFound, mint)
+	}
+	owner := out.Value.Owner
+	if !owner.Equals(solana.TokenProgramID) && !owner.Equals(solana.Token2022ProgramID) {
+		return solana.PublicKey{}, fmt.Errorf("%w: %s is owned by %s, not a token program", ErrInvalidAccountOwner, mint, owner)
+	}
+	return owner, nil
+}
+
+// ProgramRouter sends builders and associated account derivations to the
+// token program of their mint, so one code path serves mints of both the
+// original SPL Token program and Token-2022. The program of a mint never
+// changes, so it is read once per mint and remembered.
+//
+// ProgramRouter is safe for concurrent use.
+type ProgramRouter struct {
+	client     RPCClient
+	commitment rpc.CommitmentType
+
+	mu       sync.Mutex
+	programs map[solana.PublicKey]solana.PublicKey
+}
+
+// NewProgramRouter creates a router that reads mints at confirmed
+// commitment.
+func NewProgramRouter(client RPCClient) *ProgramRouter {
+	return &ProgramRouter{
+		client:     client,
+		commitment: rpc.CommitmentConfirmed,
+		programs:   map[solana.PublicKey]solana.PublicKey{},
+	}
+}
+
+func (r *ProgramRouter) SetCommitment(commitment rpc.CommitmentType) *ProgramRouter {
+	r.commitment = commitment
+	return r
+}
+
+// SetProgram records the program of mint without reading it, such as for
+// mints whose owner the caller already fetched.
+func (r *ProgramRouter) SetProgram(mint, program solana.PublicKey) *ProgramRouter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.programs[mint] = program
+	return r
+}
+
+// ResolveProgram returns the token program of mint like the package-level
+// ResolveProgram, reading it only the first time.
+func (r *ProgramRouter) ResolveProgram(ctx context.Context, mint solana.PublicKey) (solana.PublicKey, error) {
+	r.mu.Lock()
+	program, ok := r.programs[mint]
+	r.mu.Unlock()
+	if ok {
+		return program, nil
+	}
+	program, err := ResolveProgram(ctx, r.client, mint, r.commitment)
+	if err != nil {
+		return solana.PublicKey{}, err
+	}
+	r.SetProgram(mint, program)
+	return program, nil
+}
+
+// FindAssociatedTokenAddress derives the associated token account of
+// wallet for mint under the mint's program.
+func (r *ProgramRouter) FindAssociatedTokenAddress(ctx context.Context, wallet, mint solana.PublicKey) (solana.PublicKey, error) {
+	program, err := r.ResolveProgram(ctx, mint)
+	if err != nil {
+		return solana.PublicKey{}, err
+	}
+	address, _, err := FindAssociatedTokenAddress(wallet, mint, program)
+	return address, err
+}
+
+// Route validates builder and builds it for the program of mint. For mints
+// of the original SPL Token program it rejects the instructions of
+// Token-2022 extensions, which that program does not have.
+func (r *ProgramRouter) Route(ctx context.Context, mint solana.PublicKey, builder TypedInstruction) (*Instruction, error) {
+	if err := builder.Validate(); err != nil {
+		return nil, err
+	}
+	program, err := r.ResolveProgram(ctx, mint)
+	if err != nil {
+		return nil, err
+	}
+	inst := builder.Build().WithProgramID(program)
+	if program.Equals(solana.TokenProgramID) && !inst.ProgramID().Equals(ProgramID) {
+		data, err := inst.Data()
+		if err != nil {
+			return nil, err
+		}
+		if len(data) > 0 && data[0] > InstructionUiAmountToAmount {
+			return nil, fmt.Errorf("%s is a Token-2022 instruction and mint %s belongs to the SPL Token program", InstructionName(data), mint)
+		}
+	}
+	return inst, nil
+}
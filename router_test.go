@@ -0,0 +1,65 @@
+package token2022
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestProgramRouter(t *testing.T) {
+	var (
+		wallet      = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+	)
+	client := newMockRPC()
+	client.setAccount(mint, solana.TokenProgramID, make([]byte, MintSize))
+	client.setAccount(source, solana.SystemProgramID, nil)
+	ctx := context.Background()
+
+	router := NewProgramRouter(client)
+	program, err := router.ResolveProgram(ctx, mint)
+	if err != nil {
+		t.Fatalf("ResolveProgram: %v", err)
+	}
+	if program != solana.TokenProgramID {
+		t.Errorf("Expected program %s, got %s", solana.TokenProgramID, program)
+	}
+	if _, err := router.ResolveProgram(ctx, mint); err != nil || client.calls["getAccountInfo"] != 1 {
+		t.Errorf("Expected the program to be remembered, got %d reads, %v", client.calls["getAccountInfo"], err)
+	}
+
+	address, err := router.FindAssociatedTokenAddress(ctx, wallet, mint)
+	if err != nil {
+		t.Fatalf("FindAssociatedTokenAddress: %v", err)
+	}
+	if want, _, _ := solana.FindAssociatedTokenAddress(wallet, mint); address != want {
+		t.Errorf("Expected address %s, got %s", want, address)
+	}
+
+	inst, err := router.Route(ctx, mint, NewTransferChecked2022Instruction(5, 6, source, mint, destination, wallet))
+	if err != nil {
+		t.Fatalf("Route: %v", err)
+	}
+	if inst.ProgramID() != solana.TokenProgramID {
+		t.Errorf("Expected program %s, got %s", solana.TokenProgramID, inst.ProgramID())
+	}
+	inst, err = router.Route(ctx, mint, NewCreate2022Instruction(wallet, wallet, mint))
+	if err != nil {
+		t.Fatalf("Route: %v", err)
+	}
+	if inst.ProgramID() != ProgramID || inst.Accounts()[1].PublicKey != address {
+		t.Errorf("Expected a create of %s, got %s", address, inst.Accounts()[1].PublicKey)
+	}
+	if _, err := router.Route(ctx, mint, NewHarvestWithheldTokensToMint2022Instruction(mint, source)); err == nil || !strings.Contains(err.Error(), "Token-2022 instruction") {
+		t.Errorf("Expected an extension instruction to be rejected, got %v", err)
+	}
+
+	if _, err := ResolveProgram(ctx, client, source, rpc.CommitmentConfirmed); err == nil {
+		t.Errorf("Expected an account of the system program to be rejected")
+	}
+}
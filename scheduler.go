@@ -0,0 +1,524 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// Schedule is a recurring transfer of Amount raw units to Wallet every
+// Interval, identified by the ID of the caller, such as a subscription ID.
+type Schedule struct {
+	ID string `json:"id"`
+	Recipient
+	Interval time.Duration `json:"interval"`
+	// Start is the time of the first run. It defaults to the time the
+	// schedule is added.
+	Start time.Time `json:"start"`
+	// MaxRuns ends the schedule after that many runs, paid or skipped.
+	// Zero runs it until cancelled.
+	MaxRuns int `json:"maxRuns,omitempty"`
+}
+
+// Allowance returns the amount a delegate needs to pay runs runs of the
+// schedule, for Scheduler.Approve.
+func (s Schedule) Allowance(runs int) (uint64, error) {
+	if runs < 0 {
+		return 0, errInvalidField("runs", "must not be negative")
+	}
+	return CheckedMul(s.Amount, uint64(runs))
+}
+
+// ScheduleStatus is the state of a schedule.
+type ScheduleStatus string
+
+const (
+	// ScheduleActive schedules have runs left.
+	ScheduleActive ScheduleStatus = "active"
+	// ScheduleCompleted schedules made MaxRuns runs.
+	ScheduleCompleted ScheduleStatus = "completed"
+	// ScheduleCancelled schedules were cancelled with Scheduler.Cancel.
+	ScheduleCancelled ScheduleStatus = "cancelled"
+)
+
+// ScheduleRecord is what a ScheduleStore keeps for a schedule.
+type ScheduleRecord struct {
+	Schedule
+	Status ScheduleStatus `json:"status"`
+	// NextRun is the time the next run is due.
+	NextRun time.Time `json:"nextRun"`
+	// Runs counts the paid runs and Skipped the missed runs left unpaid
+	// by CatchUpLatest.
+	Runs    int `json:"runs"`
+	Skipped int `json:"skipped,omitempty"`
+	// Attempt counts the attempts of the next run that failed on-chain.
+	// It is part of the idempotency key of the run, so that such a run is
+	// tried again under a new key.
+	Attempt int `json:"attempt,omitempty"`
+	// Failures counts the consecutive failed attempts of the next run.
+	Failures      int              `json:"failures,omitempty"`
+	LastSignature solana.Signature `json:"lastSignature,omitempty"`
+	LastError     string           `json:"lastError,omitempty"`
+	CreatedAt     time.Time        `json:"createdAt"`
+	UpdatedAt     time.Time        `json:"updatedAt"`
+}
+
+// run returns the number of the next run, counting from zero.
+func (r *ScheduleRecord) run() int {
+	return r.Runs + r.Skipped
+}
+
+// idempotencyKey returns the key the next run is sent under.
+func (r *ScheduleRecord) idempotencyKey() string {
+	return fmt.Sprintf("schedule/%s/%d/%d", r.ID, r.run(), r.Attempt)
+}
+
+// ScheduleStore persists schedule records. Save must be durable before it
+// returns.
+type ScheduleStore interface {
+	// Load returns the record of id, or nil when there is none.
+	Load(ctx context.Context, id string) (*ScheduleRecord, error)
+	Save(ctx context.Context, record *ScheduleRecord) error
+	// Active returns the active records, earliest next run first.
+	Active(ctx context.Context) ([]*ScheduleRecord, error)
+}
+
+// MemoryScheduleStore is a ScheduleStore kept in memory, for tests.
+type MemoryScheduleStore struct {
+	mu      sync.Mutex
+	records map[string][]byte
+}
+
+var _ ScheduleStore = (*MemoryScheduleStore)(nil)
+
+func NewMemoryScheduleStore() *MemoryScheduleStore {
+	return &MemoryScheduleStore{records: map[string][]byte{}}
+}
+
+func (s *MemoryScheduleStore) Load(ctx context.Context, id string) (*ScheduleRecord, error) {
+	s.mu.Lock()
+	data, ok := s.records[id]
+	s.mu.Unlock()
+	if !ok {
+		return nil, nil
+	}
+	record := new(ScheduleRecord)
+	if err := json.Unmarshal(data, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func (s *MemoryScheduleStore) Save(ctx context.Context, record *ScheduleRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.records[record.ID] = data
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *MemoryScheduleStore) Active(ctx context.Context) ([]*ScheduleRecord, error) {
+	s.mu.Lock()
+	var records []*ScheduleRecord
+	for _, data := range s.records {
+		record := new(ScheduleRecord)
+		if err := json.Unmarshal(data, record); err != nil {
+			s.mu.Unlock()
+			return nil, err
+		}
+		if record.Status == ScheduleActive {
+			records = append(records, record)
+		}
+	}
+	s.mu.Unlock()
+	sort.Slice(records, func(i, j int) bool {
+		if !records[i].NextRun.Equal(records[j].NextRun) {
+			return records[i].NextRun.Before(records[j].NextRun)
+		}
+		return records[i].ID < records[j].ID
+	})
+	return records, nil
+}
+
+// CatchUpPolicy decides what a Scheduler does with the runs it missed,
+// for instance while it was stopped.
+type CatchUpPolicy int
+
+const (
+	// CatchUpAll pays every missed run, oldest first.
+	CatchUpAll CatchUpPolicy = iota
+	// CatchUpLatest pays the latest missed run and skips the others.
+	CatchUpLatest
+)
+
+// ScheduleRun is the outcome of one run of a schedule.
+type ScheduleRun struct {
+	ScheduleID string
+	// Run numbers the runs of the schedule from zero.
+	Run int
+	// Due is the time the run was due.
+	Due       time.Time
+	Amount    uint64
+	Signature solana.Signature
+	// Err is set when the run failed; it is tried again at the next Poll.
+	Err error
+	// Failures counts the consecutive failed attempts of the run.
+	Failures int
+}
+
+// Scheduler pays recurring transfers of one mint from one source token
+// account. The authority signs the transfers either as the owner of the
+// source or as a delegate approved for the amounts due, as set up with
+// Approve; before every run the source is read to check that the
+// authority may move the amount and that the balance covers it.
+//
+// Poll pays the runs that are due. Runs missed while the scheduler was not
+// polling are caught up according to the CatchUpPolicy. Every run is sent
+// through an IdempotentSender under a key made of the schedule ID and run
+// number, so with a durable IdempotencyStore, such as a
+// FileIdempotencyStore, a run interrupted by a crash is not paid twice. A
+// failed run is logged, passed to the handler set with OnFailure, and
+// tried again at the next Poll, under a new key when it failed on-chain.
+type Scheduler struct {
+	client       DistributorClient
+	store        ScheduleStore
+	sender       *Sender
+	idempotency  IdempotencyStore
+	idempotent   *IdempotentSender
+	mint         solana.PublicKey
+	decimals     uint8
+	source       solana.PublicKey
+	authority    Signer
+	feePayer     Signer
+	commitment   rpc.CommitmentType
+	catchUp      CatchUpPolicy
+	pollInterval time.Duration
+	logger       Logger
+	onFailure    func(context.Context, *ScheduleRun)
+	now          func() time.Time
+	// allowOwnerOffCurve pays wallets off the ed25519 curve; see
+	// SetAllowOwnerOffCurve.
+	allowOwnerOffCurve bool
+
+	mu sync.Mutex
+}
+
+// NewScheduler creates a scheduler paying mint, with decimals decimals,
+// from source, whose owner or approved delegate is authority. The
+// authority also pays the fees and rent. Idempotency records are kept in
+// memory until SetIdempotencyStore is called.
+func NewScheduler(client DistributorClient, store ScheduleStore, mint solana.PublicKey, decimals uint8, source solana.PublicKey, authority Signer) *Scheduler {
+	s := &Scheduler{
+		client:       client,
+		store:        store,
+		sender:       NewSender(client),
+		idempotency:  NewMemoryIdempotencyStore(),
+		mint:         mint,
+		decimals:     decimals,
+		source:       source,
+		authority:    authority,
+		feePayer:     authority,
+		commitment:   rpc.CommitmentConfirmed,
+		pollInterval: time.Minute,
+		now:          time.Now,
+	}
+	s.idempotent = NewIdempotentSender(s.sender, s.idempotency)
+	return s
+}
+
+func (s *Scheduler) SetFeePayer(feePayer Signer) *Scheduler {
+	s.feePayer = feePayer
+	return s
+}
+
+// SetSender sends the runs with sender, for instance to change its
+// commitment or attach a logger.
+func (s *Scheduler) SetSender(sender *Sender) *Scheduler {
+	s.sender = sender
+	s.idempotent = NewIdempotentSender(s.sender, s.idempotency)
+	return s
+}
+
+// SetIdempotencyStore keeps the idempotency records of the runs in store.
+func (s *Scheduler) SetIdempotencyStore(store IdempotencyStore) *Scheduler {
+	s.idempotency = store
+	s.idempotent = NewIdempotentSender(s.sender, s.idempotency)
+	return s
+}
+
+// SetCommitment sets the commitment at which the source account is read.
+func (s *Scheduler) SetCommitment(commitment rpc.CommitmentType) *Scheduler {
+	s.commitment = commitment
+	return s
+}
+
+func (s *Scheduler) SetCatchUpPolicy(policy CatchUpPolicy) *Scheduler {
+	s.catchUp = policy
+	return s
+}
+
+// SetPollInterval sets how often Run polls for due runs.
+func (s *Scheduler) SetPollInterval(interval time.Duration) *Scheduler {
+	s.pollInterval = interval
+	return s
+}
+
+// SetAllowOwnerOffCurve accepts schedules paying wallets off the ed25519
+// curve, such as program derived addresses, which Add otherwise rejects
+// with ErrOwnerOffCurve.
+func (s *Scheduler) SetAllowOwnerOffCurve(allow bool) *Scheduler {
+	s.allowOwnerOffCurve = allow
+	return s
+}
+
+// SetLogger logs every paid, skipped and failed run.
+func (s *Scheduler) SetLogger(logger Logger) *Scheduler {
+	s.logger = logger
+	return s
+}
+
+// OnFailure sets the function called with every failed run, for instance
+// to page an operator.
+func (s *Scheduler) OnFailure(handle func(context.Context, *ScheduleRun)) *Scheduler {
+	s.onFailure = handle
+	return s
+}
+
+// Approve returns the instruction by which owner, the owner of the
+// source, approves the authority of the scheduler as delegate for
+// allowance, such as Schedule.Allowance of the runs to prepay. It replaces
+// any earlier approval of the source.
+func (s *Scheduler) Approve(owner solana.PublicKey, allowance uint64) *ApproveChecked2022 {
+	return NewApproveChecked2022Instruction(allowance, s.decimals, s.source, s.mint, s.authority.Pubkey(), owner)
+}
+
+// Add saves schedule as active, due first at its Start. Adding an ID that
+// is already known returns its record unchanged.
+func (s *Scheduler) Add(ctx context.Context, schedule Schedule) (*ScheduleRecord, error) {
+	if schedule.ID == "" {
+		return nil, errNotSet("ID")
+	}
+	if schedule.Wallet.IsZero() {
+		return nil, errNotSet("Wallet")
+	}
+	if err := ValidateOwner(schedule.Wallet, s.allowOwnerOffCurve); err != nil {
+		return nil, err
+	}
+	if schedule.Amount == 0 {
+		return nil, errInvalidField("Amount", "must be greater than zero")
+	}
+	if schedule.Interval <= 0 {
+		return nil, errInvalidField("Interval", "must be positive")
+	}
+	if schedule.MaxRuns < 0 {
+		return nil, errInvalidField("MaxRuns", "must not be negative")
+	}
+	record, err := s.store.Load(ctx, schedule.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error while loading schedule %q: %w", schedule.ID, err)
+	}
+	if record != nil {
+		return record, nil
+	}
+	now := s.now()
+	if schedule.Start.IsZero() {
+		schedule.Start = now
+	}
+	record = &ScheduleRecord{Schedule: schedule, Status: ScheduleActive, NextRun: schedule.Start, CreatedAt: now, UpdatedAt: now}
+	if err := s.store.Save(ctx, record); err != nil {
+		return nil, fmt.Errorf("error while saving schedule %q: %w", schedule.ID, err)
+	}
+	return record, nil
+}
+
+// Cancel stops the schedule id. Runs already sent are not affected.
+func (s *Scheduler) Cancel(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, err := s.store.Load(ctx, id)
+	if err != nil {
+		return fmt.Errorf("error while loading schedule %q: %w", id, err)
+	}
+	if record == nil {
+		return fmt.Errorf("schedule %q not found", id)
+	}
+	record.Status = ScheduleCancelled
+	return s.save(ctx, record)
+}
+
+// Poll pays the runs of the active schedules that are due, and returns
+// them in order. Failed runs are returned with Err set rather than as
+// the error, which is only set when the store failed or ctx was done.
+// Only one Poll runs at a time.
+func (s *Scheduler) Poll(ctx context.Context) ([]*ScheduleRun, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.store.Active(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error while listing schedules: %w", err)
+	}
+	var runs []*ScheduleRun
+	for _, record := range records {
+		recordRuns, err := s.process(ctx, record)
+		runs = append(runs, recordRuns...)
+		if err != nil {
+			return runs, err
+		}
+	}
+	return runs, nil
+}
+
+// process pays the due runs of record until one fails.
+func (s *Scheduler) process(ctx context.Context, record *ScheduleRecord) ([]*ScheduleRun, error) {
+	now := s.now()
+	if record.NextRun.After(now) {
+		return nil, nil
+	}
+	if s.catchUp == CatchUpLatest {
+		if missed := int(now.Sub(record.NextRun) / record.Interval); missed > 0 {
+			if record.MaxRuns > 0 {
+				missed = min(missed, record.MaxRuns-record.run()-1)
+			}
+			record.Skipped += missed
+			record.NextRun = record.NextRun.Add(time.Duration(missed) * record.Interval)
+			record.Attempt, record.Failures = 0, 0
+			logEvent(ctx, s.logger, slog.LevelWarn, "scheduled runs skipped", "id", record.ID, "skipped", missed)
+			if err := s.save(ctx, record); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var runs []*ScheduleRun
+	for record.Status == ScheduleActive && !record.NextRun.After(now) {
+		if err := ctx.Err(); err != nil {
+			return runs, err
+		}
+		run := &ScheduleRun{ScheduleID: record.ID, Run: record.run(), Due: record.NextRun, Amount: record.Amount}
+		runs = append(runs, run)
+		run.Signature, run.Err = s.pay(ctx, record)
+		if run.Err != nil {
+			var txErr *TransactionError
+			if errors.Is(run.Err, ErrIdempotencyKeyFailed) || errors.As(run.Err, &txErr) {
+				record.Attempt++
+			}
+			record.Failures++
+			record.LastError = run.Err.Error()
+			run.Failures = record.Failures
+			logEvent(ctx, s.logger, slog.LevelError, "scheduled run failed", "id", record.ID, "run", run.Run, "failures", record.Failures, "error", run.Err)
+			if s.onFailure != nil {
+				s.onFailure(ctx, run)
+			}
+			return runs, s.save(ctx, record)
+		}
+		record.Runs++
+		record.NextRun = record.NextRun.Add(record.Interval)
+		record.Attempt, record.Failures = 0, 0
+		record.LastSignature, record.LastError = run.Signature, ""
+		if record.MaxRuns > 0 && record.run() >= record.MaxRuns {
+			record.Status = ScheduleCompleted
+		}
+		logEvent(ctx, s.logger, slog.LevelInfo, "scheduled run paid", "id", record.ID, "run", run.Run, "signature", run.Signature.String())
+		if err := s.save(ctx, record); err != nil {
+			return runs, err
+		}
+	}
+	return runs, nil
+}
+
+// pay checks that the authority may move the amount of the next run of
+// record from the source, then sends it.
+func (s *Scheduler) pay(ctx context.Context, record *ScheduleRecord) (solana.Signature, error) {
+	source, err := FetchTokenAccount(ctx, s.client, s.source, s.commitment)
+	if err != nil {
+		return solana.Signature{}, fmt.Errorf("error while fetching source account: %w", err)
+	}
+	authority := s.authority.Pubkey()
+	switch {
+	case source.Owner.Equals(authority):
+	case source.Delegate != nil && source.Delegate.Equals(authority):
+		if source.DelegatedAmount < record.Amount {
+			return solana.Signature{}, fmt.Errorf("%w: %s may move %d more, not %d", ErrInsufficientFunds, authority, source.DelegatedAmount, record.Amount)
+		}
+	default:
+		return solana.Signature{}, fmt.Errorf("%w: %s is neither the owner nor the delegate of %s", ErrOwnerMismatch, authority, s.source)
+	}
+	if source.Amount < record.Amount {
+		return solana.Signature{}, fmt.Errorf("%w: %s holds %d, not %d", ErrInsufficientFunds, s.source, source.Amount, record.Amount)
+	}
+
+	account, _, err := FindAssociatedTokenAddress2022Checked(record.Wallet, s.mint, s.allowOwnerOffCurve)
+	if err != nil {
+		return solana.Signature{}, err
+	}
+	create, err := NewCreate2022Instruction(s.feePayer.Pubkey(), record.Wallet, s.mint).
+		SetIdempotent(true).
+		SetAllowOwnerOffCurve(s.allowOwnerOffCurve).
+		ValidateAndBuild()
+	if err != nil {
+		return solana.Signature{}, err
+	}
+	builder := NewTxBuilder(s.client).
+		SetFeePayer(s.feePayer.Pubkey()).
+		AddInstruction(
+			create,
+			NewTransferChecked2022Instruction(record.Amount, s.decimals, s.source, s.mint, account, authority).Build(),
+		).
+		AddSigner(uniqueSigners(s.authority, s.feePayer)...)
+	result, err := s.idempotent.Send(ctx, record.idempotencyKey(), builder)
+	if err != nil {
+		return solana.Signature{}, err
+	}
+	return result.Signature, nil
+}
+
+// Run polls every poll interval until ctx is done, and returns the first
+// error of Poll. Failed runs do not end it.
+func (s *Scheduler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.Poll(ctx); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) save(ctx context.Context, record *ScheduleRecord) error {
+	record.UpdatedAt = s.now()
+	if err := s.store.Save(ctx, record); err != nil {
+		return fmt.Errorf("error while saving schedule %q: %w", record.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,218 @@
+package token2022
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+type schedulerFixture struct {
+	client    *mockRPC
+	scheduler *Scheduler
+	store     *MemoryScheduleStore
+	mint      solana.PublicKey
+	source    solana.PublicKey
+	now       time.Time
+}
+
+func newSchedulerFixture(t *testing.T, authority solana.PrivateKey) *schedulerFixture {
+	t.Helper()
+	f := &schedulerFixture{
+		client: newMockRPC(),
+		store:  NewMemoryScheduleStore(),
+		mint:   solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn"),
+		source: solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi"),
+		now:    time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	f.client.onSend = func(tx *solana.Transaction) {
+		f.client.statuses[tx.Signatures[0]] = &rpc.SignatureStatusesResult{Slot: 42, ConfirmationStatus: rpc.ConfirmationStatusConfirmed}
+	}
+	f.scheduler = NewScheduler(f.client, f.store, f.mint, 6, f.source, NewPrivateKeySigner(authority)).
+		SetSender(NewSender(f.client).SetPollInterval(time.Millisecond))
+	f.scheduler.now = func() time.Time { return f.now }
+	return f
+}
+
+func TestSchedulerCatchUp(t *testing.T) {
+
+	var (
+		owner = solana.NewWallet().PrivateKey
+		f     = newSchedulerFixture(t, owner)
+		ctx   = context.Background()
+		start = f.now
+	)
+	f.client.setAccount(f.source, solana.Token2022ProgramID, encodeTokenAccount(f.mint, owner.PublicKey(), 1_000))
+	schedule := Schedule{ID: "rent", Recipient: Recipient{Wallet: solana.NewWallet().PublicKey(), Amount: 100}, Interval: time.Hour, MaxRuns: 8}
+	if _, err := f.scheduler.Add(ctx, schedule); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	// Three runs were due while the scheduler was stopped.
+	f.now = f.now.Add(150 * time.Minute)
+	runs, err := f.scheduler.Poll(ctx)
+	if err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if len(runs) != 3 || len(f.client.sent) != 3 {
+		t.Fatalf("Expected 3 runs sent, got %d runs and %d transactions", len(runs), len(f.client.sent))
+	}
+	for i, run := range runs {
+		if run.Run != i || run.Err != nil || run.Signature != f.client.sent[i].Signatures[0] {
+			t.Errorf("Unexpected run %d: %+v", i, run)
+		}
+	}
+	if runs, _ := f.scheduler.Poll(ctx); len(runs) != 0 {
+		t.Errorf("Expected no run before the next one is due, got %d", len(runs))
+	}
+
+	// With CatchUpLatest, only the last missed run is paid.
+	f.scheduler.SetCatchUpPolicy(CatchUpLatest)
+	f.now = f.now.Add(4 * time.Hour)
+	runs, err = f.scheduler.Poll(ctx)
+	if err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if len(runs) != 1 || runs[0].Run != 6 {
+		t.Fatalf("Expected run 6 only, got %+v", runs)
+	}
+	record, err := f.store.Load(ctx, "rent")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if record.Runs != 4 || record.Skipped != 3 || !record.NextRun.Equal(start.Add(7*time.Hour)) {
+		t.Errorf("Expected 4 runs paid and 3 skipped, got %+v", record)
+	}
+
+	// The eighth run completes the schedule.
+	f.now = f.now.Add(time.Hour)
+	if _, err := f.scheduler.Poll(ctx); err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if record, _ := f.store.Load(ctx, "rent"); record.Status != ScheduleCompleted || record.run() != 8 {
+		t.Errorf("Expected the schedule to complete after 8 runs, got %+v", record)
+	}
+}
+
+func TestSchedulerDelegate(t *testing.T) {
+
+	var (
+		owner    = solana.NewWallet().PublicKey()
+		delegate = solana.NewWallet().PrivateKey
+		f        = newSchedulerFixture(t, delegate)
+		ctx      = context.Background()
+		failures []*ScheduleRun
+	)
+	f.scheduler.OnFailure(func(ctx context.Context, run *ScheduleRun) { failures = append(failures, run) })
+	setSource := func(allowance uint64) {
+		key := delegate.PublicKey()
+		f.client.setAccount(f.source, solana.Token2022ProgramID, EncodeTokenAccount(&TokenAccount{
+			Mint: f.mint, Owner: owner, Amount: 1_000, Delegate: &key, DelegatedAmount: allowance, State: AccountStateInitialized,
+		}))
+	}
+	schedule := Schedule{ID: "subscription", Recipient: Recipient{Wallet: solana.NewWallet().PublicKey(), Amount: 100}, Interval: time.Hour}
+	if _, err := f.scheduler.Add(ctx, schedule); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	setSource(50)
+	runs, err := f.scheduler.Poll(ctx)
+	if err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if len(runs) != 1 || !errors.Is(runs[0].Err, ErrInsufficientFunds) || len(f.client.sent) != 0 {
+		t.Fatalf("Expected the run to fail on the allowance without sending, got %+v", runs)
+	}
+	if len(failures) != 1 || failures[0].Failures != 1 {
+		t.Errorf("Expected one failure alert, got %+v", failures)
+	}
+
+	// The owner approves the delegate for three runs.
+	allowance, err := schedule.Allowance(3)
+	if err != nil {
+		t.Fatalf("Allowance: %v", err)
+	}
+	approve := f.scheduler.Approve(owner, allowance)
+	if approve.Amount != 300 || approve.Delegate != delegate.PublicKey() || approve.Owner != owner {
+		t.Errorf("Unexpected approval %+v", approve)
+	}
+	setSource(allowance)
+	runs, err = f.scheduler.Poll(ctx)
+	if err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if len(runs) != 1 || runs[0].Err != nil || len(f.client.sent) != 1 {
+		t.Fatalf("Expected the run to be paid, got %+v", runs)
+	}
+	accounts := f.client.sent[0].Message.AccountKeys
+	if !accounts[0].Equals(delegate.PublicKey()) {
+		t.Errorf("Expected the delegate to sign and pay, got %s", accounts[0])
+	}
+}
+
+func TestSchedulerRetriesFailedRun(t *testing.T) {
+
+	var (
+		owner = solana.NewWallet().PrivateKey
+		f     = newSchedulerFixture(t, owner)
+		ctx   = context.Background()
+	)
+	f.client.setAccount(f.source, solana.Token2022ProgramID, encodeTokenAccount(f.mint, owner.PublicKey(), 1_000))
+	f.client.onSend = func(tx *solana.Transaction) {
+		status := &rpc.SignatureStatusesResult{Slot: 42, ConfirmationStatus: rpc.ConfirmationStatusConfirmed}
+		if len(f.client.sent) == 1 {
+			status.Err = map[string]interface{}{"InstructionError": []interface{}{float64(1), map[string]interface{}{"Custom": float64(1)}}}
+		}
+		f.client.statuses[tx.Signatures[0]] = status
+	}
+	if _, err := f.scheduler.Add(ctx, Schedule{ID: "payroll", Recipient: Recipient{Wallet: solana.NewWallet().PublicKey(), Amount: 100}, Interval: time.Hour}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	runs, err := f.scheduler.Poll(ctx)
+	if err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if len(runs) != 1 || runs[0].Err == nil {
+		t.Fatalf("Expected the run to fail on-chain, got %+v", runs)
+	}
+	record, _ := f.store.Load(ctx, "payroll")
+	if record.Attempt != 1 || record.Runs != 0 || record.LastError == "" {
+		t.Errorf("Expected a second attempt of run 0, got %+v", record)
+	}
+
+	runs, err = f.scheduler.Poll(ctx)
+	if err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+	if len(runs) != 1 || runs[0].Err != nil || len(f.client.sent) != 2 {
+		t.Fatalf("Expected the run to be sent again and paid, got %+v", runs)
+	}
+
+	if err := f.scheduler.Cancel(ctx, "payroll"); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	f.now = f.now.Add(time.Hour)
+	if runs, _ := f.scheduler.Poll(ctx); len(runs) != 0 {
+		t.Errorf("Expected no run after Cancel, got %d", len(runs))
+	}
+}
+
+func TestSchedulerAddOwnerOffCurve(t *testing.T) {
+	var (
+		f      = newSchedulerFixture(t, solana.NewWallet().PrivateKey)
+		ctx    = context.Background()
+		wallet = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	)
+	pda, _, _ := FindAssociatedTokenAddress2022(wallet, f.mint)
+	schedule := Schedule{ID: "vault", Recipient: Recipient{Wallet: pda, Amount: 100}, Interval: time.Hour}
+	if _, err := f.scheduler.Add(ctx, schedule); !errors.Is(err, ErrOwnerOffCurve) {
+		t.Errorf("Expected ErrOwnerOffCurve for a wallet off the curve, got %v", err)
+	}
+	if _, err := f.scheduler.SetAllowOwnerOffCurve(true).Add(ctx, schedule); err != nil {
+		t.Errorf("Expected an allowed off-curve wallet to be added, got %v", err)
+	}
+}
@@ -0,0 +1,285 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// ErrBlockhashExpired is returned when a transaction did not land before
+// its blockhash expired and no attempts are left.
+var ErrBlockhashExpired = errors.New("blockhash expired before the transaction was confirmed")
+
+// SendResult describes a confirmed transaction.
+type SendResult struct {
+	Signature solana.Signature
+	Slot      uint64
+	// Attempts is the number of distinct transactions (blockhashes) tried.
+	Attempts int
+}
+
+// Sender submits a logical operation built by a TxBuilder and, when its
+// blockhash expires without confirmation, rebuilds it with a fresh
+// blockhash, re-signs and resubmits it.
+//
+// A new attempt is only made once the block height has passed the last
+// valid block height of every earlier attempt and none of their
+// signatures has landed, so the operation is executed at most once.
+type Sender struct {
+	client       RPCClient
+	commitment   rpc.CommitmentType
+	pollInterval time.Duration
+	maxAttempts  int
+	opts         rpc.TransactionOpts
+	options      []SendOption
+	logger       Logger
+	tracer       Tracer
+	metrics      Metrics
+}
+
+// NewSender creates a sender that waits for confirmed commitment and
+// tries up to three blockhashes.
+func NewSender(client RPCClient) *Sender {
+	return &Sender{
+		client:       client,
+		commitment:   rpc.CommitmentConfirmed,
+		pollInterval: time.Second,
+		maxAttempts:  3,
+	}
+}
+
+func (s *Sender) SetCommitment(commitment rpc.CommitmentType) *Sender {
+	s.commitment = commitment
+	return s
+}
+
+func (s *Sender) SetPollInterval(interval time.Duration) *Sender {
+	s.pollInterval = interval
+	return s
+}
+
+func (s *Sender) SetMaxAttempts(attempts int) *Sender {
+	s.maxAttempts = attempts
+	return s
+}
+
+func (s *Sender) SetTransactionOpts(opts rpc.TransactionOpts) *Sender {
+	s.opts = opts
+	return s
+}
+
+// SetSendOptions sets the options applied to every Send, after the
+// transaction opts and before the options passed to Send.
+func (s *Sender) SetSendOptions(options ...SendOption) *Sender {
+	s.options = options
+	return s
+}
+
+// SetLogger logs every sent, rebroadcast, expired, confirmed and failed
+// transaction.
+func (s *Sender) SetLogger(logger Logger) *Sender {
+	s.logger = logger
+	return s
+}
+
+// SetTracer traces every Send and the confirmation of each attempt.
+func (s *Sender) SetTracer(tracer Tracer) *Sender {
+	s.tracer = tracer
+	return s
+}
+
+// SetMetrics counts sent, confirmed and failed transactions, rebuilt
+// attempts and harvested fee accounts, and records confirmation times.
+func (s *Sender) SetMetrics(metrics Metrics) *Sender {
+	s.metrics = metrics
+	return s
+}
+
+// Send builds, signs and submits the operation until it is confirmed,
+// fails on-chain, runs out of attempts, or ctx is done. options tune the
+// submission of this operation only.
+func (s *Sender) Send(ctx context.Context, builder *TxBuilder, options ...SendOption) (*SendResult, error) {
+	config, err := applySendOptions(s.opts, s.commitment, append(s.options[:len(s.options):len(s.options)], options...))
+	if err != nil {
+		return nil, err
+	}
+	ctx, end := startSpan(ctx, s.tracer, "token2022.Sender.Send", "commitment", string(config.commitment))
+	result, err := s.send(ctx, builder, config)
+	end(err)
+	return result, err
+}
+
+func (s *Sender) send(ctx context.Context, builder *TxBuilder, config *sendConfig) (*SendResult, error) {
+	var sent []solana.Signature
+
+	for attempt := 1; attempt <= s.maxAttempts; attempt++ {
+		tx, lastValidBlockHeight, err := builder.buildAndSign(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sig := tx.Signatures[0]
+		sent = append(sent, sig)
+
+		if config.beforeSend != nil {
+			if err := config.beforeSend(ctx, tx, lastValidBlockHeight); err != nil {
+				return nil, fmt.Errorf("error while recording the transaction: %w", err)
+			}
+		}
+		if _, err := sendTransaction(ctx, s.client, tx, config.opts); err != nil {
+			logEvent(ctx, s.logger, slog.LevelError, "transaction not sent", "signature", sig.String(), "attempt", attempt, "error", err)
+			return nil, fmt.Errorf("error while SendTransaction: %w", err)
+		}
+		logEvent(ctx, s.logger, slog.LevelInfo, "transaction sent", "signature", sig.String(), "attempt", attempt)
+		addMetric(s.metrics, MetricTransactionsSent, 1)
+		sentAt := time.Now()
+
+		confirmCtx, end := startSpan(ctx, s.tracer, "token2022.Sender.Confirm", "signature", sig.String(), "attempt", attempt)
+		status, err := s.waitForExpiry(confirmCtx, tx, lastValidBlockHeight, config)
+		end(err)
+		if err != nil {
+			return nil, err
+		}
+		if status != nil {
+			observeMetric(s.metrics, MetricConfirmationSeconds, time.Since(sentAt).Seconds())
+			return s.result(ctx, tx, sig, status, attempt)
+		}
+
+		// The blockhash expired. Make sure no earlier attempt landed
+		// late before building a new transaction.
+		landedSig, landed, err := s.findLanded(ctx, sent)
+		if err != nil {
+			return nil, err
+		}
+		if landed != nil {
+			return s.result(ctx, tx, landedSig, landed, attempt)
+		}
+		if builder.UsesDurableNonce() {
+			// The nonce was advanced by something else; rebuilding would
+			// pick up the new nonce and could execute the operation twice.
+			return nil, errors.New("durable nonce advanced without the transaction landing")
+		}
+		logEvent(ctx, s.logger, slog.LevelWarn, "blockhash expired", "signature", sig.String(), "attempt", attempt, "retrying", attempt < s.maxAttempts)
+		if attempt < s.maxAttempts {
+			addMetric(s.metrics, MetricRetries, 1, "blockhash")
+		}
+	}
+	return nil, ErrBlockhashExpired
+}
+
+// waitForExpiry polls the signature status and rebroadcasts the same
+// transaction until it reaches the commitment level or its blockhash
+// expires, in which case it returns a nil status.
+func (s *Sender) waitForExpiry(ctx context.Context, tx *solana.Transaction, lastValidBlockHeight uint64, config *sendConfig) (*rpc.SignatureStatusesResult, error) {
+	sig := tx.Signatures[0]
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		statuses, err := s.client.GetSignatureStatuses(ctx, false, sig)
+		if err == nil && len(statuses.Value) > 0 && statuses.Value[0] != nil {
+			status := statuses.Value[0]
+			if status.Err != nil || reachedCommitment(status.ConfirmationStatus, config.commitment) {
+				return status, nil
+			}
+		}
+
+		if lastValidBlockHeight > 0 {
+			height, err := s.client.GetBlockHeight(ctx, rpc.CommitmentConfirmed)
+			if err == nil && height > lastValidBlockHeight {
+				return nil, nil
+			}
+		} else if s.nonceAdvanced(ctx, tx) {
+			return nil, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+
+		// Rebroadcasting the same signed transaction is always safe.
+		_, _ = sendTransaction(ctx, s.client, tx, config.opts)
+		logEvent(ctx, s.logger, slog.LevelDebug, "transaction rebroadcast", "signature", sig.String())
+	}
+}
+
+// nonceAdvanced reports whether the durable nonce used as the
+// transaction's blockhash has changed.
+func (s *Sender) nonceAdvanced(ctx context.Context, tx *solana.Transaction) bool {
+	if len(tx.Message.Instructions) == 0 || len(tx.Message.Instructions[0].Accounts) == 0 {
+		return false
+	}
+	nonceAccount, err := tx.Message.Account(tx.Message.Instructions[0].Accounts[0])
+	if err != nil {
+		return false
+	}
+	nonce, err := FetchNonceAccount(ctx, s.client, nonceAccount, rpc.CommitmentConfirmed)
+	if err != nil {
+		return false
+	}
+	return solana.Hash(nonce.Nonce) != tx.Message.RecentBlockhash
+}
+
+func (s *Sender) findLanded(ctx context.Context, sigs []solana.Signature) (solana.Signature, *rpc.SignatureStatusesResult, error) {
+	statuses, err := s.client.GetSignatureStatuses(ctx, true, sigs...)
+	if err != nil {
+		return solana.Signature{}, nil, fmt.Errorf("error while GetSignatureStatuses: %w", err)
+	}
+	for i, status := range statuses.Value {
+		if status != nil && i < len(sigs) {
+			return sigs[i], status, nil
+		}
+	}
+	return solana.Signature{}, nil, nil
+}
+
+// result reports the landed transaction sig; tx is the last attempt,
+// which carries the same instructions.
+func (s *Sender) result(ctx context.Context, tx *solana.Transaction, sig solana.Signature, status *rpc.SignatureStatusesResult, attempts int) (*SendResult, error) {
+	result := &SendResult{
+		Signature: sig,
+		Slot:      status.Slot,
+		Attempts:  attempts,
+	}
+	if status.Err != nil {
+		err := DecodeTransactionError(sig, status.Err)
+		logEvent(ctx, s.logger, slog.LevelError, "transaction failed", "signature", sig.String(), "slot", status.Slot, "error", err)
+		addMetric(s.metrics, MetricTransactionsConfirmed, 1, "failed")
+		return result, err
+	}
+	logEvent(ctx, s.logger, slog.LevelInfo, "transaction confirmed", "signature", sig.String(), "slot", status.Slot, "attempts", attempts)
+	addMetric(s.metrics, MetricTransactionsConfirmed, 1, "confirmed")
+	addHarvestedAccounts(s.metrics, tx)
+	return result, nil
+}
+
+func reachedCommitment(status rpc.ConfirmationStatusType, commitment rpc.CommitmentType) bool {
+	switch commitment {
+	case rpc.CommitmentProcessed:
+		return status != ""
+	case rpc.CommitmentFinalized:
+		return status == rpc.ConfirmationStatusFinalized
+	default:
+		return status == rpc.ConfirmationStatusConfirmed || status == rpc.ConfirmationStatusFinalized
+	}
+}
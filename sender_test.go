@@ -0,0 +1,196 @@
+package token2022
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestSenderResubmitsOnExpiry(t *testing.T) {
+
+	var (
+		owner  = solana.NewWallet().PrivateKey
+		mint   = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		client = newMockRPC()
+	)
+
+	client.onSend = func(tx *solana.Transaction) {
+		if tx.Message.RecentBlockhash == client.blockhash && len(client.sent) == 1 {
+			// First attempt never lands and its blockhash expires.
+			client.blockHeight = client.lastValidBlockHeight + 1
+			client.blockhash = solana.Hash(solana.NewWallet().PublicKey())
+			client.lastValidBlockHeight += 300
+			return
+		}
+		client.statuses[tx.Signatures[0]] = &rpc.SignatureStatusesResult{
+			Slot:               42,
+			ConfirmationStatus: rpc.ConfirmationStatusConfirmed,
+		}
+	}
+
+	builder := NewTxBuilder(client).
+		SetFeePayer(owner.PublicKey()).
+		AddInstruction(NewCreate2022Instruction(owner.PublicKey(), owner.PublicKey(), mint).Build()).
+		AddSigner(NewPrivateKeySigner(owner))
+
+	result, err := NewSender(client).
+		SetPollInterval(time.Millisecond).
+		Send(context.Background(), builder)
+	if err != nil {
+		t.Fatalf("Error sending: %v", err)
+	}
+	if result.Attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", result.Attempts)
+	}
+	if result.Signature != client.sent[len(client.sent)-1].Signatures[0] {
+		t.Errorf("Expected signature of the last attempt")
+	}
+	if result.Slot != 42 {
+		t.Errorf("Expected slot 42, got %d", result.Slot)
+	}
+}
+
+func TestSenderDetectsLateLanding(t *testing.T) {
+
+	var (
+		owner  = solana.NewWallet().PrivateKey
+		mint   = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		client = newMockRPC()
+	)
+
+	// The first attempt lands just as its blockhash expires; the sender
+	// must not submit a second copy.
+	client.onSend = func(tx *solana.Transaction) {
+		client.blockHeight = client.lastValidBlockHeight + 1
+		client.statuses[tx.Signatures[0]] = &rpc.SignatureStatusesResult{
+			Slot:               7,
+			ConfirmationStatus: rpc.ConfirmationStatusProcessed,
+		}
+	}
+
+	builder := NewTxBuilder(client).
+		SetFeePayer(owner.PublicKey()).
+		AddInstruction(NewCreate2022Instruction(owner.PublicKey(), owner.PublicKey(), mint).Build()).
+		AddSigner(NewPrivateKeySigner(owner))
+
+	result, err := NewSender(client).
+		SetPollInterval(time.Millisecond).
+		Send(context.Background(), builder)
+	if err != nil {
+		t.Fatalf("Error sending: %v", err)
+	}
+	if result.Attempts != 1 {
+		t.Errorf("Expected 1 attempt, got %d", result.Attempts)
+	}
+	if len(client.sent) != 1 {
+		t.Errorf("Expected 1 submission, got %d", len(client.sent))
+	}
+}
+
+func TestSenderLogs(t *testing.T) {
+	var (
+		owner  = solana.NewWallet().PrivateKey
+		mint   = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		client = newMockRPC()
+		buf    bytes.Buffer
+	)
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client.onSend = func(tx *solana.Transaction) {
+		if len(client.sent) == 1 {
+			client.blockHeight = client.lastValidBlockHeight + 1
+			client.blockhash = solana.Hash(solana.NewWallet().PublicKey())
+			client.lastValidBlockHeight += 300
+			return
+		}
+		client.statuses[tx.Signatures[0]] = &rpc.SignatureStatusesResult{Slot: 42, ConfirmationStatus: rpc.ConfirmationStatusConfirmed}
+	}
+
+	builder := NewTxBuilder(client).
+		SetLogger(logger).
+		SetFeePayer(owner.PublicKey()).
+		AddInstruction(NewCreate2022Instruction(owner.PublicKey(), owner.PublicKey(), mint).Build()).
+		AddSigner(NewPrivateKeySigner(owner))
+	if _, err := NewSender(client).SetLogger(logger).SetPollInterval(time.Millisecond).Send(context.Background(), builder); err != nil {
+		t.Fatalf("Error sending: %v", err)
+	}
+
+	out := buf.String()
+	last := -1
+	for _, event := range []string{
+		`msg="instruction built" index=0 program=` + ProgramID.String() + ` instruction=Create`,
+		`msg="transaction sent"`,
+		`msg="blockhash expired"`,
+		`msg="transaction sent"`,
+		`msg="transaction confirmed"`,
+	} {
+		i := strings.Index(out[last+1:], event)
+		if i < 0 {
+			t.Fatalf("Expected %s after offset %d in\n%s", event, last, out)
+		}
+		last += 1 + i
+	}
+}
+
+func TestSenderSendOptions(t *testing.T) {
+	var (
+		owner  = solana.NewWallet().PrivateKey
+		mint   = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		client = newMockRPC()
+	)
+	client.onSend = func(tx *solana.Transaction) {
+		client.statuses[tx.Signatures[0]] = &rpc.SignatureStatusesResult{Slot: 42, ConfirmationStatus: rpc.ConfirmationStatusConfirmed}
+	}
+	builder := NewTxBuilder(client).
+		SetFeePayer(owner.PublicKey()).
+		AddInstruction(NewCreate2022Instruction(owner.PublicKey(), owner.PublicKey(), mint).Build()).
+		AddSigner(NewPrivateKeySigner(owner))
+	sender := NewSender(client).
+		SetPollInterval(time.Millisecond).
+		SetTransactionOpts(rpc.TransactionOpts{PreflightCommitment: rpc.CommitmentFinalized}).
+		SetSendOptions(WithMaxRetries(0))
+
+	if _, err := sender.Send(context.Background(), builder, WithSkipPreflight(true), WithMaxRetries(2)); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	opts := client.sentOpts[0]
+	if !opts.SkipPreflight || opts.MaxRetries == nil || *opts.MaxRetries != 2 || opts.PreflightCommitment != rpc.CommitmentFinalized {
+		t.Errorf("Expected the per-call options on top of the defaults, got %+v", opts)
+	}
+
+	if _, err := sender.Send(context.Background(), builder); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if opts := client.sentOpts[1]; opts.SkipPreflight || *opts.MaxRetries != 0 {
+		t.Errorf("Expected the per-call options not to stick, got %+v", opts)
+	}
+
+	if _, err := sender.Send(context.Background(), builder, WithEncoding(solana.EncodingJSONParsed)); !errors.Is(err, ErrInvalidField) {
+		t.Errorf("Expected ErrInvalidField for an unsupported encoding, got %v", err)
+	}
+	if _, err := sender.Send(context.Background(), builder, WithEncoding(solana.EncodingBase58)); err == nil || !strings.Contains(err.Error(), "base58") {
+		t.Errorf("Expected base58 to need an encoded send client, got %v", err)
+	}
+
+	client.onSend = func(tx *solana.Transaction) {
+		client.statuses[tx.Signatures[0]] = &rpc.SignatureStatusesResult{Slot: 43, ConfirmationStatus: rpc.ConfirmationStatusProcessed}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	result, err := sender.Send(ctx, builder, WithCommitment(rpc.CommitmentProcessed))
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if result.Slot != 43 {
+		t.Errorf("Expected a processed result at slot 43, got %d", result.Slot)
+	}
+	if _, err := sender.Send(context.Background(), builder, WithCommitment("recent")); !errors.Is(err, ErrInvalidField) {
+		t.Errorf("Expected ErrInvalidField for an unknown commitment, got %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"fmt"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+	"github.com/mr-tron/base58"
+)
+
+// SendOption tunes how a transaction is submitted to the RPC node and
+// confirmed, trading latency for safety per operation. Options are applied
+// in order on top of the defaults of the Sender or Distributor.
+type SendOption func(*sendConfig)
+
+// sendConfig is what SendOptions configure.
+type sendConfig struct {
+	opts       rpc.TransactionOpts
+	commitment rpc.CommitmentType
+	// beforeSend, when set, runs before every attempt is submitted and
+	// aborts the send when it fails.
+	beforeSend func(ctx context.Context, tx *solana.Transaction, lastValidBlockHeight uint64) error
+}
+
+// WithSkipPreflight skips the simulation the RPC node runs before
+// forwarding the transaction. It saves a round of execution but lets
+// failing transactions land and pay their fee.
+func WithSkipPreflight(skip bool) SendOption {
+	return func(config *sendConfig) {
+		config.opts.SkipPreflight = skip
+	}
+}
+
+// WithPreflightCommitment sets the commitment of the bank the preflight
+// simulation runs against. It should usually match the commitment the
+// transaction is confirmed at.
+func WithPreflightCommitment(commitment rpc.CommitmentType) SendOption {
+	return func(config *sendConfig) {
+		config.opts.PreflightCommitment = commitment
+	}
+}
+
+// WithMaxRetries sets how many times the RPC node rebroadcasts the
+// transaction to the leader. Zero leaves rebroadcasting to the Sender.
+func WithMaxRetries(retries uint) SendOption {
+	return func(config *sendConfig) {
+		config.opts.MaxRetries = &retries
+	}
+}
+
+// WithMinContextSlot makes the RPC node refuse the transaction until it
+// has processed slot.
+func WithMinContextSlot(slot uint64) SendOption {
+	return func(config *sendConfig) {
+		config.opts.MinContextSlot = &slot
+	}
+}
+
+// WithEncoding sets the wire encoding of the transaction, either
+// solana.EncodingBase64, the default, or solana.EncodingBase58, which is
+// slower and deprecated but still accepted by older RPC nodes. Sending
+// base58 needs a client with SendEncodedTransactionWithOpts, such as
+// *rpc.Client.
+func WithEncoding(encoding solana.EncodingType) SendOption {
+	return func(config *sendConfig) {
+		config.opts.Encoding = encoding
+	}
+}
+
+// WithCommitment waits for the transaction to reach commitment instead of
+// the commitment of the Sender: processed for the lowest latency, confirmed,
+// or finalized when the operation must not be rolled back.
+func WithCommitment(commitment rpc.CommitmentType) SendOption {
+	return func(config *sendConfig) {
+		config.commitment = commitment
+	}
+}
+
+// EncodedSendClient is the sendTransaction call taking an encoded
+// transaction. *rpc.Client satisfies it.
+type EncodedSendClient interface {
+	SendEncodedTransactionWithOpts(ctx context.Context, encodedTx string, opts rpc.TransactionOpts) (solana.Signature, error)
+}
+
+var _ EncodedSendClient = (*rpc.Client)(nil)
+
+// applySendOptions applies options to the transaction opts and
+// commitment, checking the encoding and the commitment.
+func applySendOptions(opts rpc.TransactionOpts, commitment rpc.CommitmentType, options []SendOption) (*sendConfig, error) {
+	config := &sendConfig{opts: opts, commitment: commitment}
+	for _, option := range options {
+		option(config)
+	}
+	switch config.opts.Encoding {
+	case "", solana.EncodingBase64, solana.EncodingBase58:
+	default:
+		return nil, errInvalidField("Encoding", "must be %q or %q, got %q", solana.EncodingBase64, solana.EncodingBase58, config.opts.Encoding)
+	}
+	if err := validateCommitment(config.commitment); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// validateCommitment accepts the three commitment levels of the cluster.
+func validateCommitment(commitment rpc.CommitmentType) error {
+	switch commitment {
+	case rpc.CommitmentProcessed, rpc.CommitmentConfirmed, rpc.CommitmentFinalized:
+		return nil
+	}
+	return errInvalidField("Commitment", "must be %q, %q or %q, got %q", rpc.CommitmentProcessed, rpc.CommitmentConfirmed, rpc.CommitmentFinalized, commitment)
+}
+
+// sendTransaction submits tx with opts, encoding it in base58 when asked.
+func sendTransaction(ctx context.Context, client RPCClient, tx *solana.Transaction, opts rpc.TransactionOpts) (solana.Signature, error) {
+	if opts.Encoding != solana.EncodingBase58 {
+		return client.SendTransactionWithOpts(ctx, tx, opts)
+	}
+	encoded, ok := client.(EncodedSendClient)
+	if !ok {
+		return solana.Signature{}, fmt.Errorf("client %T cannot send base58 transactions", client)
+	}
+	data, err := tx.MarshalBinary()
+	if err != nil {
+		return solana.Signature{}, fmt.Errorf("error while encoding transaction: %w", err)
+	}
+	return encoded.SendEncodedTransactionWithOpts(ctx, base58.Encode(data), opts)
+}
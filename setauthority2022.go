@@ -0,0 +1,227 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"errors"
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	solana "github.com/gagliardetto/solana-go"
+	format "github.com/gagliardetto/solana-go/text/format"
+	treeout "github.com/gagliardetto/treeout"
+)
+
+// ErrIrreversible is returned by SetAuthority2022.Validate when the
+// instruction removes the mint, freeze or close authority of a mint
+// without ConfirmIrreversible.
+var ErrIrreversible = errors.New("removing the authority cannot be undone")
+
+// SetAuthority2022 sets a new authority of a mint or account. A nil NewAuthority
+// removes the authority, which cannot be undone.
+type SetAuthority2022 struct {
+	// The type of authority to update.
+	AuthorityType AuthorityType
+
+	// The new authority, or nil to remove it.
+	NewAuthority *solana.PublicKey
+
+	Account   solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Authority solana.PublicKey `bin:"-" borsh_skip:"true"`
+	// Signers are the signers of a multisig authority.
+	Signers []solana.PublicKey `bin:"-" borsh_skip:"true"`
+	// Irreversible lets Validate accept removing the MintTokens,
+	// FreezeAccount or CloseMint authority.
+	Irreversible bool `bin:"-" borsh_skip:"true" json:"-"`
+
+	// [0] = [WRITE] Account
+	// ··········· The mint or account to change the authority of
+	//
+	// [1] = [SIGNER] Authority
+	// ··········· The current authority, or a multisig
+	//
+	// [2...] = [SIGNER] Signers
+	// ··········· M signer accounts when the authority is a multisig
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewSetAuthority2022InstructionBuilder creates a new `SetAuthority2022` instruction builder.
+func NewSetAuthority2022InstructionBuilder() *SetAuthority2022 {
+	nd := &SetAuthority2022{}
+	return nd
+}
+
+func (inst *SetAuthority2022) SetAuthorityType(authorityType AuthorityType) *SetAuthority2022 {
+	inst.AuthorityType = authorityType
+	return inst
+}
+
+func (inst *SetAuthority2022) SetNewAuthority(newAuthority solana.PublicKey) *SetAuthority2022 {
+	inst.NewAuthority = &newAuthority
+	return inst
+}
+
+func (inst *SetAuthority2022) SetAccount(account solana.PublicKey) *SetAuthority2022 {
+	inst.Account = account
+	return inst
+}
+
+// SetAuthority sets the authority. Pass the signers when the authority is a multisig.
+func (inst *SetAuthority2022) SetAuthority(authority solana.PublicKey, multisigSigners ...solana.PublicKey) *SetAuthority2022 {
+	inst.Authority = authority
+	inst.Signers = multisigSigners
+	return inst
+}
+
+// ConfirmIrreversible acknowledges that a nil NewAuthority permanently
+// removes the authority. Without it, Validate rejects removing the
+// MintTokens, FreezeAccount or CloseMint authority of a mint, which fixes
+// its supply, or makes it impossible to freeze its accounts or to close it.
+func (inst *SetAuthority2022) ConfirmIrreversible() *SetAuthority2022 {
+	inst.Irreversible = true
+	return inst
+}
+
+func (inst SetAuthority2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Account,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+	}
+	keys = appendAuthority(keys, inst.Authority, inst.Signers)
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst SetAuthority2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *SetAuthority2022) Validate() error {
+	if inst.Account.IsZero() {
+		return errNotSet("Account")
+	}
+	if inst.Authority.IsZero() {
+		return errNotSet("Authority")
+	}
+	if err := validateMultisigSigners(inst.Signers); err != nil {
+		return err
+	}
+	if inst.NewAuthority == nil && !inst.Irreversible {
+		switch inst.AuthorityType {
+		case AuthorityMintTokens, AuthorityFreezeAccount, AuthorityCloseMint:
+			return fmt.Errorf("%w: call ConfirmIrreversible to remove the %s authority", ErrIrreversible, inst.AuthorityType)
+		}
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+func (inst *SetAuthority2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("SetAuthority2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=2]").ParentFunc(func(paramsBranch treeout.Branches) {
+						paramsBranch.Child(format.Param("AuthorityType", inst.AuthorityType))
+						paramsBranch.Child(format.Param(" NewAuthority", inst.NewAuthority))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("  account", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(format.Meta("authority", inst.AccountMetaSlice.Get(1)))
+						encodeRemainingAccounts(accountsBranch, "signer", inst.AccountMetaSlice, 2)
+					})
+				})
+		})
+}
+
+func (inst SetAuthority2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	if err := encoder.WriteUint8(uint8(inst.AuthorityType)); err != nil {
+		return err
+	}
+	return WriteCOptionPubkey(encoder, COptionInstruction, inst.NewAuthority)
+}
+
+func (inst *SetAuthority2022) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	value, err := decoder.ReadUint8()
+	if err != nil {
+		return err
+	}
+	inst.AuthorityType = AuthorityType(value)
+	if inst.NewAuthority, err = ReadCOptionPubkey(decoder, COptionInstruction); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst SetAuthority2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *SetAuthority2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("SetAuthority2022", accounts, 2); err != nil {
+		return err
+	}
+	inst.Account = accounts[0].PublicKey
+	inst.Authority = accounts[1].PublicKey
+	inst.Signers = pubkeysOf(accounts[2:])
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst SetAuthority2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *SetAuthority2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewSetAuthority2022Instruction creates a new `SetAuthority2022` instruction.
+func NewSetAuthority2022Instruction(
+	authorityType AuthorityType,
+	newAuthority *solana.PublicKey,
+	account solana.PublicKey,
+	authority solana.PublicKey,
+	multisigSigners ...solana.PublicKey,
+) *SetAuthority2022 {
+	inst := NewSetAuthority2022InstructionBuilder().
+		SetAuthorityType(authorityType).
+		SetAccount(account).
+		SetAuthority(authority, multisigSigners...)
+	inst.NewAuthority = newAuthority
+	return inst
+}
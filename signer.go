@@ -0,0 +1,93 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"fmt"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// Signer produces ed25519 signatures for one public key.
+//
+// Implementations can keep the key in memory, on a hardware wallet, or
+// behind a remote signing service; the package only ever hands them the
+// serialized transaction message.
+type Signer interface {
+	Pubkey() solana.PublicKey
+	SignMessage(ctx context.Context, message []byte) (solana.Signature, error)
+}
+
+// PrivateKeySigner is a Signer backed by an in-memory private key.
+type PrivateKeySigner struct {
+	key solana.PrivateKey
+}
+
+// NewPrivateKeySigner wraps a private key as a Signer.
+func NewPrivateKeySigner(key solana.PrivateKey) *PrivateKeySigner {
+	return &PrivateKeySigner{key: key}
+}
+
+func (s *PrivateKeySigner) Pubkey() solana.PublicKey {
+	return s.key.PublicKey()
+}
+
+func (s *PrivateKeySigner) SignMessage(ctx context.Context, message []byte) (solana.Signature, error) {
+	return s.key.Sign(message)
+}
+
+// PrivateKeySigners wraps each private key as a Signer.
+func PrivateKeySigners(keys ...solana.PrivateKey) []Signer {
+	signers := make([]Signer, len(keys))
+	for i, key := range keys {
+		signers[i] = NewPrivateKeySigner(key)
+	}
+	return signers
+}
+
+// SignTransaction signs the transaction with every signer that is a
+// required signer, keeping signatures that are already present.
+// Signers that are not required by the message are ignored.
+func SignTransaction(ctx context.Context, tx *solana.Transaction, signers ...Signer) error {
+	sigs, err := collectSignatures(ctx, tx, signers)
+	if err != nil {
+		return err
+	}
+	return ApplySignatures(tx, sigs...)
+}
+
+func collectSignatures(ctx context.Context, tx *solana.Transaction, signers []Signer) ([]SignerSignature, error) {
+	message, err := tx.Message.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("error while encoding message: %w", err)
+	}
+	required := tx.Message.Signers()
+	var sigs []SignerSignature
+	for _, signer := range signers {
+		pubkey := signer.Pubkey()
+		if signerIndex(required, pubkey) < 0 {
+			continue
+		}
+		sig, err := signer.SignMessage(ctx, message)
+		if err != nil {
+			return nil, fmt.Errorf("error while signing with %s: %w", pubkey, err)
+		}
+		sigs = append(sigs, SignerSignature{Signer: pubkey, Signature: sig})
+	}
+	return sigs, nil
+}
+
+var _ Signer = (*PrivateKeySigner)(nil)
@@ -0,0 +1,211 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// SimulateClient is the set of RPC calls used by SimulateTokenTx.
+// *rpc.Client satisfies it.
+type SimulateClient interface {
+	SimulateTransactionWithOpts(ctx context.Context, transaction *solana.Transaction, opts *rpc.SimulateTransactionOpts) (*rpc.SimulateTransactionResponse, error)
+	MultipleAccountsClient
+}
+
+var _ SimulateClient = (*rpc.Client)(nil)
+
+// SimulateOpts configures SimulateTokenTx. The zero value simulates at
+// the node's default commitment without verifying signatures, so unsigned
+// transactions can be simulated.
+type SimulateOpts struct {
+	Commitment rpc.CommitmentType
+	// SigVerify verifies the signatures of the transaction.
+	SigVerify bool
+	// ReplaceRecentBlockhash simulates with the latest blockhash instead
+	// of the one of the transaction. It conflicts with SigVerify.
+	ReplaceRecentBlockhash bool
+}
+
+// SimulationResult is the decoded outcome of a simulated transaction.
+type SimulationResult struct {
+	// Slot is the slot the simulation ran at.
+	Slot uint64
+	// Err is nil when the simulation succeeded. Otherwise it wraps a
+	// *TransactionError and, when the failure came from Token-2022, the
+	// TokenError, so errors.Is(result.Err, ErrInsufficientFunds) works.
+	Err  error
+	Logs []string
+	// Programs are the program invocations parsed from Logs.
+	Programs      *ProgramLogs
+	UnitsConsumed uint64
+	// Tokens are the projected balance changes of the Token-2022 accounts
+	// the transaction writes, in account order. Accounts it creates have
+	// a Pre of zero and accounts it closes a Post of zero. FeeWithheld is
+	// the growth of the withheld transfer fees of the account.
+	Tokens []*TokenBalanceChange
+}
+
+// TokenError returns the Token-2022 error of a failed simulation.
+func (r *SimulationResult) TokenError() (TokenError, bool) {
+	return AsTokenError(r.Err)
+}
+
+// SimulateTokenTx simulates tx and decodes the result: the Token-2022
+// error, the program invocations, the compute units consumed and the
+// balance changes of the Token-2022 accounts the transaction writes. The
+// writable accounts are read before the simulation and requested back
+// from it, so the changes are relative to the state at that time. Only
+// the accounts listed in the message, not those loaded from address
+// lookup tables, are diffed. An error is returned when the RPC calls
+// fail, not when the simulated transaction does; check
+// SimulationResult.Err for that.
+func SimulateTokenTx(ctx context.Context, client SimulateClient, tx *solana.Transaction, opts *SimulateOpts) (*SimulationResult, error) {
+	if tx == nil {
+		return nil, errNotSet("transaction")
+	}
+	if opts == nil {
+		opts = &SimulateOpts{}
+	}
+	writable, err := tx.Message.Writable()
+	if err != nil {
+		return nil, fmt.Errorf("error while listing writable accounts: %w", err)
+	}
+	fetcher := NewAccountFetcher(client).SetFetchOpts(FetchOpts{Commitment: opts.Commitment})
+	before, err := fetcher.Fetch(ctx, writable)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := client.SimulateTransactionWithOpts(ctx, tx, &rpc.SimulateTransactionOpts{
+		SigVerify:              opts.SigVerify,
+		Commitment:             opts.Commitment,
+		ReplaceRecentBlockhash: opts.ReplaceRecentBlockhash,
+		Accounts: &rpc.SimulateTransactionAccountsOpts{
+			Encoding:  solana.EncodingBase64,
+			Addresses: writable,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error while simulating transaction: %w", err)
+	}
+	if out == nil || out.Value == nil {
+		return nil, fmt.Errorf("simulation returned no result")
+	}
+	result := &SimulationResult{
+		Slot:     out.Context.Slot,
+		Logs:     out.Value.Logs,
+		Programs: ParseLogs(out.Value.Logs),
+	}
+	if out.Value.UnitsConsumed != nil {
+		result.UnitsConsumed = *out.Value.UnitsConsumed
+	}
+	if out.Value.Err != nil {
+		var sig solana.Signature
+		if len(tx.Signatures) > 0 {
+			sig = tx.Signatures[0]
+		}
+		result.Err = MapTokenError(DecodeTransactionError(sig, out.Value.Err), out.Value.Logs)
+		return result, nil
+	}
+	if len(out.Value.Accounts) != len(writable) {
+		return result, nil
+	}
+	result.Tokens, err = projectedTokenChanges(ctx, fetcher, writable, before, out.Value.Accounts)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// projectedTokenChanges diffs the Token-2022 accounts among the states
+// before and after a simulation and fills in the decimals of their mints.
+func projectedTokenChanges(ctx context.Context, fetcher *AccountFetcher, keys []solana.PublicKey, before, after []*rpc.Account) ([]*TokenBalanceChange, error) {
+	var changes []*TokenBalanceChange
+	var mints []solana.PublicKey
+	seen := map[solana.PublicKey]bool{}
+	for i, key := range keys {
+		pre, post := simulatedTokenAccount(before[i]), simulatedTokenAccount(after[i])
+		if pre == nil && post == nil {
+			continue
+		}
+		change := &TokenBalanceChange{Account: key}
+		var preWithheld, postWithheld uint64
+		for _, state := range []*TokenAccount{pre, post} {
+			if state != nil {
+				change.Owner, change.Mint = state.Owner, state.Mint
+			}
+		}
+		if pre != nil {
+			change.Pre = pre.Amount
+			preWithheld, _, _ = pre.WithheldAmount()
+		}
+		if post != nil {
+			change.Post = post.Amount
+			postWithheld, _, _ = post.WithheldAmount()
+		}
+		if change.Pre == change.Post && preWithheld == postWithheld {
+			continue
+		}
+		if postWithheld > preWithheld {
+			change.FeeWithheld = postWithheld - preWithheld
+		}
+		change.Delta = new(big.Int).Sub(new(big.Int).SetUint64(change.Post), new(big.Int).SetUint64(change.Pre))
+		changes = append(changes, change)
+		if !seen[change.Mint] {
+			seen[change.Mint] = true
+			mints = append(mints, change.Mint)
+		}
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	fetched, err := fetcher.Fetch(ctx, mints)
+	if err != nil {
+		return nil, err
+	}
+	decimals := map[solana.PublicKey]uint8{}
+	for i, account := range fetched {
+		if account == nil {
+			continue
+		}
+		if mint, err := DecodeMint(account.Data.GetBinary()); err == nil {
+			decimals[mints[i]] = mint.Decimals
+		}
+	}
+	for _, change := range changes {
+		change.Decimals = decimals[change.Mint]
+	}
+	return changes, nil
+}
+
+// simulatedTokenAccount decodes a Token-2022 token account, returning nil
+// for missing accounts and accounts of any other kind.
+func simulatedTokenAccount(account *rpc.Account) *TokenAccount {
+	if account == nil || !account.Owner.Equals(solana.Token2022ProgramID) {
+		return nil
+	}
+	decoded, err := DecodeTokenAccount(account.Data.GetBinary())
+	if err != nil {
+		return nil
+	}
+	return decoded
+}
@@ -0,0 +1,115 @@
+package token2022
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// simulateRPC answers simulateTransaction with result, filling in the
+// requested accounts from after.
+type simulateRPC struct {
+	*mockRPC
+	result *rpc.SimulateTransactionResult
+	after  map[solana.PublicKey]*rpc.Account
+}
+
+func (m *simulateRPC) SimulateTransactionWithOpts(ctx context.Context, tx *solana.Transaction, opts *rpc.SimulateTransactionOpts) (*rpc.SimulateTransactionResponse, error) {
+	result := *m.result
+	if result.Err == nil {
+		for _, address := range opts.Accounts.Addresses {
+			result.Accounts = append(result.Accounts, m.after[address])
+		}
+	}
+	return &rpc.SimulateTransactionResponse{RPCContext: rpc.RPCContext{Context: rpc.Context{Slot: 42}}, Value: &result}, nil
+}
+
+func TestSimulateTokenTx(t *testing.T) {
+	var (
+		ctx         = context.Background()
+		wallet      = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		units       = uint64(4_645)
+		client      = &simulateRPC{mockRPC: newMockRPC(), after: map[solana.PublicKey]*rpc.Account{}}
+	)
+	client.setAccount(mint, solana.Token2022ProgramID, encodeMint(&wallet, 5_000, 6))
+	client.setAccount(source, solana.Token2022ProgramID, encodeTokenAccount(mint, wallet, 5_000))
+	client.after[source] = &rpc.Account{Owner: solana.Token2022ProgramID, Data: rpc.DataBytesOrJSONFromBytes(encodeTokenAccount(mint, wallet, 4_000))}
+	// The destination is created by an earlier instruction of the
+	// transaction.
+	client.after[destination] = &rpc.Account{Owner: solana.Token2022ProgramID, Data: rpc.DataBytesOrJSONFromBytes(encodeTokenAccount(mint, destination, 1_000))}
+	client.after[wallet] = &rpc.Account{Owner: solana.SystemProgramID}
+
+	tx, err := solana.NewTransaction(
+		[]solana.Instruction{NewTransferChecked2022Instruction(1_000, 6, source, mint, destination, wallet).Build()},
+		solana.Hash{1},
+		solana.TransactionPayer(wallet),
+	)
+	if err != nil {
+		t.Fatalf("NewTransaction: %v", err)
+	}
+	client.result = &rpc.SimulateTransactionResult{
+		Logs: []string{
+			"Program TokenzQdBNbLqP5VEhdkAS6EPFLC1PHnBqCXEpPxuEb invoke [1]",
+			"Program log: Instruction: TransferChecked",
+			"Program TokenzQdBNbLqP5VEhdkAS6EPFLC1PHnBqCXEpPxuEb consumed 4645 of 200000 compute units",
+			"Program TokenzQdBNbLqP5VEhdkAS6EPFLC1PHnBqCXEpPxuEb success",
+		},
+		UnitsConsumed: &units,
+	}
+
+	result, err := SimulateTokenTx(ctx, client, tx, nil)
+	if err != nil {
+		t.Fatalf("SimulateTokenTx: %v", err)
+	}
+	if result.Err != nil || result.Slot != 42 || result.UnitsConsumed != units {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if names := result.Programs.TokenInstructions(); len(names) != 1 || names[0] != "TransferChecked" {
+		t.Errorf("Expected the parsed TransferChecked, got %v", names)
+	}
+	if len(result.Tokens) != 2 {
+		t.Fatalf("Expected 2 token balance changes, got %d", len(result.Tokens))
+	}
+	for _, change := range result.Tokens {
+		want := int64(1_000)
+		if change.Account == source {
+			want = -1_000
+		}
+		if change.Delta.Int64() != want || change.Decimals != 6 || change.Mint != mint {
+			t.Errorf("Unexpected change %+v", change)
+		}
+	}
+
+	client.result = &rpc.SimulateTransactionResult{
+		Err: map[string]interface{}{"InstructionError": []interface{}{float64(0), map[string]interface{}{"Custom": float64(1)}}},
+		Logs: []string{
+			"Program TokenzQdBNbLqP5VEhdkAS6EPFLC1PHnBqCXEpPxuEb invoke [1]",
+			"Program log: Instruction: TransferChecked",
+			"Program log: Error: insufficient funds",
+			"Program TokenzQdBNbLqP5VEhdkAS6EPFLC1PHnBqCXEpPxuEb failed: custom program error: 0x1",
+		},
+	}
+	result, err = SimulateTokenTx(ctx, client, tx, nil)
+	if err != nil {
+		t.Fatalf("SimulateTokenTx: %v", err)
+	}
+	if !errors.Is(result.Err, ErrInsufficientFunds) {
+		t.Errorf("Expected ErrInsufficientFunds, got %v", result.Err)
+	}
+	var txErr *TransactionError
+	if !errors.As(result.Err, &txErr) || txErr.InstructionIndex != 0 {
+		t.Errorf("Expected the failing instruction, got %v", result.Err)
+	}
+	if code, ok := result.TokenError(); !ok || code != ErrInsufficientFunds {
+		t.Errorf("Expected the TokenError, got %v, %t", code, ok)
+	}
+	if len(result.Tokens) != 0 || !result.Programs.Invocations[0].Failed {
+		t.Errorf("Expected a failed invocation without changes, got %+v", result)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// solanaPayScheme is the scheme of Solana Pay URLs.
+const solanaPayScheme = "solana:"
+
+// solanaPayAmount is the amount format of Solana Pay: a non-negative
+// decimal with a leading digit and no exponent.
+var solanaPayAmount = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+
+// TransferRequest is a Solana Pay transfer request, a "solana:" URL asking
+// a wallet to send tokens to Recipient.
+type TransferRequest struct {
+	Recipient solana.PublicKey
+	// Amount is the decimal amount in the mint's UI units, as displayed by
+	// wallets, or empty to let the wallet ask for it. SetRawAmount
+	// formats it from a raw amount.
+	Amount string
+	// SPLToken is the mint, or nil to request SOL.
+	SPLToken *solana.PublicKey
+	// References are added to the transfer as read-only accounts, so that
+	// the payment can be found with getSignaturesForAddress.
+	References []solana.PublicKey
+	Label      string
+	Message    string
+	// Memo is attached to the transfer with the Memo program.
+	Memo string
+}
+
+// NewTransferRequest creates a request for tokens of mint to be sent to
+// recipient.
+func NewTransferRequest(recipient, mint solana.PublicKey) *TransferRequest {
+	return &TransferRequest{Recipient: recipient, SPLToken: &mint}
+}
+
+// SetRawAmount sets Amount to amount base units of mint, formatted with
+// the mint's decimals. Mints with the InterestBearingConfig or
+// ScaledUiAmount extension are converted at unixTimestamp, since wallets
+// display and request their adjusted amounts.
+func (r *TransferRequest) SetRawAmount(amount uint64, mint *Mint, unixTimestamp int64) error {
+	uiAmount, err := mint.AmountToUiAmount(amount, unixTimestamp)
+	if err != nil {
+		return err
+	}
+	r.Amount = uiAmount
+	return nil
+}
+
+// RawAmount converts Amount to base units of mint at unixTimestamp, the
+// inverse of SetRawAmount.
+func (r *TransferRequest) RawAmount(mint *Mint, unixTimestamp int64) (uint64, error) {
+	if r.Amount == "" {
+		return 0, errNotSet("amount")
+	}
+	return mint.UiAmountToAmount(r.Amount, unixTimestamp)
+}
+
+func (r *TransferRequest) Validate() error {
+	if r.Recipient.IsZero() {
+		return errNotSet("Recipient")
+	}
+	if r.Amount != "" && !solanaPayAmount.MatchString(r.Amount) {
+		return fmt.Errorf("invalid amount %q", r.Amount)
+	}
+	return nil
+}
+
+// URL encodes the request as a "solana:" URL.
+func (r *TransferRequest) URL() (string, error) {
+	if err := r.Validate(); err != nil {
+		return "", err
+	}
+	var params []string
+	add := func(key, value string) {
+		params = append(params, key+"="+url.QueryEscape(value))
+	}
+	if r.Amount != "" {
+		add("amount", r.Amount)
+	}
+	if r.SPLToken != nil {
+		add("spl-token", r.SPLToken.String())
+	}
+	for _, reference := range r.References {
+		add("reference", reference.String())
+	}
+	if r.Label != "" {
+		add("label", r.Label)
+	}
+	if r.Message != "" {
+		add("message", r.Message)
+	}
+	if r.Memo != "" {
+		add("memo", r.Memo)
+	}
+	u := solanaPayScheme + r.Recipient.String()
+	if len(params) > 0 {
+		u += "?" + strings.Join(params, "&")
+	}
+	return u, nil
+}
+
+// ParseTransferRequest parses a "solana:" transfer request URL. Parameters
+// other than those of TransferRequest are ignored.
+func ParseTransferRequest(rawURL string) (*TransferRequest, error) {
+	if !strings.HasPrefix(rawURL, solanaPayScheme) {
+		return nil, fmt.Errorf("not a Solana Pay URL: %q", rawURL)
+	}
+	path, query, _ := strings.Cut(strings.TrimPrefix(rawURL, solanaPayScheme), "?")
+	if strings.Contains(path, ":") {
+		return nil, errors.New("not a transfer request: the URL is a transaction request")
+	}
+	recipient, err := solana.PublicKeyFromBase58(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient %q: %w", path, err)
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters: %w", err)
+	}
+
+	r := &TransferRequest{
+		Recipient: recipient,
+		Amount:    values.Get("amount"),
+		Label:     values.Get("label"),
+		Message:   values.Get("message"),
+		Memo:      values.Get("memo"),
+	}
+	if mint := values.Get("spl-token"); mint != "" {
+		pubkey, err := solana.PublicKeyFromBase58(mint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid spl-token %q: %w", mint, err)
+		}
+		r.SPLToken = &pubkey
+	}
+	for _, reference := range values["reference"] {
+		pubkey, err := solana.PublicKeyFromBase58(reference)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reference %q: %w", reference, err)
+		}
+		r.References = append(r.References, pubkey)
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
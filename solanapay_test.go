@@ -0,0 +1,66 @@
+package token2022
+
+import (
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+func TestTransferRequestURL(t *testing.T) {
+	var (
+		recipient = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint      = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		reference = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+	)
+	request := NewTransferRequest(recipient, mint)
+	if err := request.SetRawAmount(1_250_000, &Mint{Decimals: 6}, 0); err != nil {
+		t.Fatalf("SetRawAmount: %v", err)
+	}
+	request.References = []solana.PublicKey{reference}
+	request.Label = "Coffee shop"
+	request.Memo = "order#42"
+
+	got, err := request.URL()
+	if err != nil {
+		t.Fatalf("URL: %v", err)
+	}
+	want := "solana:" + recipient.String() + "?amount=1.25&spl-token=" + mint.String() +
+		"&reference=" + reference.String() + "&label=Coffee+shop&memo=order%2342"
+	if got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+
+	parsed, err := ParseTransferRequest(got)
+	if err != nil {
+		t.Fatalf("ParseTransferRequest: %v", err)
+	}
+	if parsed.Recipient != recipient || parsed.SPLToken == nil || *parsed.SPLToken != mint ||
+		len(parsed.References) != 1 || parsed.References[0] != reference ||
+		parsed.Label != "Coffee shop" || parsed.Memo != "order#42" {
+		t.Errorf("Unexpected request %+v", parsed)
+	}
+	if amount, err := parsed.RawAmount(&Mint{Decimals: 6}, 0); err != nil || amount != 1_250_000 {
+		t.Errorf("Expected raw amount 1250000, got %d, %v", amount, err)
+	}
+}
+
+func TestParseTransferRequestErrors(t *testing.T) {
+	recipient := "nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun"
+	for _, u := range []string{
+		"https://example.com",
+		"solana:https://example.com/pay",
+		"solana:not-a-key",
+		"solana:" + recipient + "?amount=.5",
+		"solana:" + recipient + "?amount=1e3",
+		"solana:" + recipient + "?spl-token=bad",
+	} {
+		if _, err := ParseTransferRequest(u); err == nil {
+			t.Errorf("Expected error for %q", u)
+		}
+	}
+	// SOL requests have no spl-token.
+	request, err := ParseTransferRequest("solana:" + recipient + "?amount=0.01")
+	if err != nil || request.SPLToken != nil || request.Amount != "0.01" {
+		t.Errorf("Unexpected request %+v, %v", request, err)
+	}
+}
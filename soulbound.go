@@ -0,0 +1,87 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// SoulboundPreset describes a non-transferable mint for credentials and
+// badges: tokens are minted to a holder and stay with them, described by
+// metadata stored in the mint. Holders may still burn their tokens, and
+// the issuer may close the mint once the supply is burnt when
+// CloseAuthority is set. The mint has no decimals.
+type SoulboundPreset struct {
+	// Mint is the address of the new mint; it signs the transaction.
+	Mint solana.PublicKey
+	// Authority is the mint authority; it signs the transaction.
+	Authority solana.PublicKey
+	// Payer funds the mint and signs the transaction. It defaults to
+	// Authority.
+	Payer *solana.PublicKey
+	// UpdateAuthority may update the metadata and its pointer. It
+	// defaults to Authority.
+	UpdateAuthority *solana.PublicKey
+	// FreezeAuthority, when set, may freeze holders' accounts, such as to
+	// suspend a credential.
+	FreezeAuthority *solana.PublicKey
+	// CloseAuthority, when set, adds the MintCloseAuthority extension.
+	CloseAuthority *solana.PublicKey
+
+	Name   string
+	Symbol string
+	URI    string
+}
+
+// Validate checks the preset.
+func (p *SoulboundPreset) Validate() error {
+	if p.Mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	if p.Authority.IsZero() {
+		return errNotSet("Authority")
+	}
+	if p.Name == "" {
+		return errNotSet("Name")
+	}
+	if p.CloseAuthority != nil && p.CloseAuthority.IsZero() {
+		return errInvalidField("CloseAuthority", "is the zero key")
+	}
+	return nil
+}
+
+// Profile returns the MintProfile of the preset.
+func (p *SoulboundPreset) Profile() *MintProfile {
+	return &MintProfile{
+		Mint:            p.Mint,
+		Authority:       p.Authority,
+		Payer:           p.Payer,
+		FreezeAuthority: p.FreezeAuthority,
+		NonTransferable: true,
+		CloseAuthority:  p.CloseAuthority,
+		Metadata:        &MetadataProfile{Name: p.Name, Symbol: p.Symbol, URI: p.URI, UpdateAuthority: p.UpdateAuthority},
+	}
+}
+
+// Plan validates the preset and returns the plan creating the mint, signed
+// by Mint, Authority and Payer. Holders' associated token accounts get
+// the ImmutableOwner extension non-transferable tokens require from the
+// associated token account program.
+func (p *SoulboundPreset) Plan() (*MintPlan, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p.Profile().Plan()
+}
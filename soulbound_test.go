@@ -0,0 +1,61 @@
+package token2022
+
+import (
+	"errors"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+func TestSoulboundPreset(t *testing.T) {
+	mint := solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	authority := solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	preset := SoulboundPreset{
+		Mint:           mint,
+		Authority:      authority,
+		CloseAuthority: &authority,
+		Name:           "Contributor 2025",
+		Symbol:         "BADGE",
+		URI:            "https://example.com/badge.json",
+	}
+	plan, err := preset.Plan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"CreateAccount", "InitializeNonTransferableMint", "InitializeMintCloseAuthority", "InitializeMetadataPointer", "InitializeMint", "InitializeTokenMetadata"}
+	if len(plan.Instructions) != len(expected) {
+		t.Fatalf("Expected %d instructions, got %d", len(expected), len(plan.Instructions))
+	}
+	for i, inst := range plan.Instructions {
+		if name := instructionName(t, inst); name != expected[i] {
+			t.Errorf("Expected instruction %d to be %s, got %s", i, expected[i], name)
+		}
+	}
+	space, _ := MintSpace(ExtensionNonTransferable, ExtensionMintCloseAuthority, ExtensionMetadataPointer)
+	if plan.Space != space {
+		t.Errorf("Expected space %d, got %d", space, plan.Space)
+	}
+	if plan.Lamports <= RentExemptLamports(space) {
+		t.Errorf("Expected lamports for the metadata on top of %d, got %d", RentExemptLamports(space), plan.Lamports)
+	}
+	data, _ := plan.Instructions[4].Data()
+	typed, _ := DecodeInstruction(plan.Instructions[4].Accounts(), data)
+	if initialize := typed.(*InitializeMint2022); initialize.Decimals != 0 || initialize.FreezeAuthority != nil {
+		t.Errorf("Expected 0 decimals and no freeze authority, got %d and %v", initialize.Decimals, initialize.FreezeAuthority)
+	}
+
+	preset.CloseAuthority = nil
+	if plan, err := preset.Plan(); err != nil || len(plan.Instructions) != len(expected)-1 {
+		t.Errorf("Expected no MintCloseAuthority without a close authority, got %v", err)
+	}
+
+	preset.Name = ""
+	if _, err := preset.Plan(); !errors.Is(err, ErrNotSet) {
+		t.Errorf("Expected ErrNotSet without a name, got %v", err)
+	}
+	preset.Name = "Badge"
+	preset.CloseAuthority = &solana.PublicKey{}
+	if _, err := preset.Plan(); !errors.Is(err, ErrInvalidField) {
+		t.Errorf("Expected ErrInvalidField for a zero close authority, got %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// StablecoinFee is the transfer fee of a StablecoinPreset.
+type StablecoinFee struct {
+	BasisPoints uint16
+	MaximumFee  uint64
+	// ConfigAuthority may change the fee and WithdrawAuthority withdraws
+	// withheld fees. Both default to the preset's Authority.
+	ConfigAuthority   *solana.PublicKey
+	WithdrawAuthority *solana.PublicKey
+}
+
+// maxStablecoinDecimals is the most decimals a StablecoinPreset accepts:
+// with more, a u64 supply holds fewer than 10 whole tokens.
+const maxStablecoinDecimals = 18
+
+// StablecoinPreset describes the mint of a regulated issuer. New accounts
+// start frozen until the issuer thaws them, such as after KYC; the issuer
+// can pause all transfers, mints and burns, and seize or burn tokens from
+// any account as permanent delegate; metadata is stored in the mint, and
+// the mint can be closed once its supply is burnt. A transfer fee is
+// optional.
+//
+// Each role defaults to Authority. Issuers separating duties set them to
+// distinct keys, often multisigs.
+type StablecoinPreset struct {
+	// Mint is the address of the new mint; it signs the transaction.
+	Mint solana.PublicKey
+	// Authority is the mint authority; it signs the transaction.
+	Authority solana.PublicKey
+	// Payer funds the mint and signs the transaction. It defaults to
+	// Authority.
+	Payer    *solana.PublicKey
+	Decimals uint8
+
+	FreezeAuthority   *solana.PublicKey
+	PauseAuthority    *solana.PublicKey
+	PermanentDelegate *solana.PublicKey
+	CloseAuthority    *solana.PublicKey
+	UpdateAuthority   *solana.PublicKey
+
+	// TransferFee, when set, adds the TransferFeeConfig extension.
+	TransferFee *StablecoinFee
+
+	Name   string
+	Symbol string
+	URI    string
+}
+
+// Validate checks the preset.
+func (p *StablecoinPreset) Validate() error {
+	if p.Mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	if p.Authority.IsZero() {
+		return errNotSet("Authority")
+	}
+	if p.Name == "" {
+		return errNotSet("Name")
+	}
+	if p.Symbol == "" {
+		return errNotSet("Symbol")
+	}
+	if p.Decimals > maxStablecoinDecimals {
+		return errInvalidField("Decimals", "%d exceeds %d", p.Decimals, maxStablecoinDecimals)
+	}
+	for _, role := range []struct {
+		field string
+		key   *solana.PublicKey
+	}{
+		{"FreezeAuthority", p.FreezeAuthority},
+		{"PauseAuthority", p.PauseAuthority},
+		{"PermanentDelegate", p.PermanentDelegate},
+		{"CloseAuthority", p.CloseAuthority},
+		{"UpdateAuthority", p.UpdateAuthority},
+	} {
+		if role.key != nil && role.key.IsZero() {
+			return errInvalidField(role.field, "is the zero key")
+		}
+	}
+	if fee := p.TransferFee; fee != nil {
+		if fee.BasisPoints > MaxFeeBasisPoints {
+			return errInvalidField("TransferFee", "%d basis points exceed %d", fee.BasisPoints, MaxFeeBasisPoints)
+		}
+		if fee.BasisPoints > 0 && fee.MaximumFee == 0 {
+			return errInvalidField("TransferFee", "maximum fee of 0 caps every fee at 0")
+		}
+	}
+	return nil
+}
+
+// Profile returns the MintProfile of the preset, with each unset role
+// given to Authority.
+func (p *StablecoinPreset) Profile() *MintProfile {
+	role := func(key *solana.PublicKey) *solana.PublicKey {
+		value := keyOr(key, p.Authority)
+		return &value
+	}
+	profile := &MintProfile{
+		Mint:              p.Mint,
+		Authority:         p.Authority,
+		Payer:             p.Payer,
+		Decimals:          p.Decimals,
+		FreezeAuthority:   role(p.FreezeAuthority),
+		DefaultFrozen:     true,
+		PauseAuthority:    role(p.PauseAuthority),
+		PermanentDelegate: role(p.PermanentDelegate),
+		CloseAuthority:    role(p.CloseAuthority),
+		Metadata:          &MetadataProfile{Name: p.Name, Symbol: p.Symbol, URI: p.URI, UpdateAuthority: p.UpdateAuthority},
+	}
+	if fee := p.TransferFee; fee != nil {
+		profile.TransferFee = &TransferFeeProfile{
+			BasisPoints:       fee.BasisPoints,
+			MaximumFee:        fee.MaximumFee,
+			ConfigAuthority:   role(fee.ConfigAuthority),
+			WithdrawAuthority: role(fee.WithdrawAuthority),
+		}
+	}
+	return profile
+}
+
+// Plan validates the preset and returns the plan creating the mint, signed
+// by Mint, Authority and Payer.
+func (p *StablecoinPreset) Plan() (*MintPlan, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p.Profile().Plan()
+}
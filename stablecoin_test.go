@@ -0,0 +1,91 @@
+package token2022
+
+import (
+	"errors"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+func TestStablecoinPreset(t *testing.T) {
+	mint := solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	authority := solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	compliance := solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR")
+	preset := StablecoinPreset{
+		Mint:              mint,
+		Authority:         authority,
+		Decimals:          6,
+		FreezeAuthority:   &compliance,
+		PermanentDelegate: &compliance,
+		TransferFee:       &StablecoinFee{BasisPoints: 10, MaximumFee: 1_000_000},
+		Name:              "Example Dollar",
+		Symbol:            "EUSD",
+		URI:               "https://example.com/eusd.json",
+	}
+	plan, err := preset.Plan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"CreateAccount",
+		"InitializeTransferFeeConfig",
+		"InitializeDefaultAccountState",
+		"InitializePausableConfig",
+		"InitializePermanentDelegate",
+		"InitializeMintCloseAuthority",
+		"InitializeMetadataPointer",
+		"InitializeMint",
+		"InitializeTokenMetadata",
+	}
+	if len(plan.Instructions) != len(expected) {
+		t.Fatalf("Expected %d instructions, got %d", len(expected), len(plan.Instructions))
+	}
+	for i, inst := range plan.Instructions {
+		name := instructionName(t, inst)
+		if name != expected[i] {
+			t.Errorf("Expected instruction %d to be %s, got %s", i, expected[i], name)
+			continue
+		}
+		data, _ := inst.Data()
+		switch typed, _ := DecodeInstruction(inst.Accounts(), data); typed := typed.(type) {
+		case *InitializeDefaultAccountState2022:
+			if typed.State != AccountStateFrozen {
+				t.Errorf("Expected new accounts frozen, got %v", typed.State)
+			}
+		case *InitializePermanentDelegate2022:
+			if !typed.Delegate.Equals(compliance) {
+				t.Errorf("Expected permanent delegate %s, got %s", compliance, typed.Delegate)
+			}
+		case *InitializeMint2022:
+			if typed.Decimals != 6 || typed.FreezeAuthority == nil || !typed.FreezeAuthority.Equals(compliance) {
+				t.Errorf("Expected 6 decimals and freeze authority %s, got %d and %v", compliance, typed.Decimals, typed.FreezeAuthority)
+			}
+		}
+	}
+	if len(plan.Extensions) != 7 || plan.Extensions[6] != ExtensionTokenMetadata {
+		t.Errorf("Expected 7 extensions ending with TokenMetadata, got %v", plan.Extensions)
+	}
+
+	preset.TransferFee = nil
+	if plan, err := preset.Plan(); err != nil || len(plan.Instructions) != len(expected)-1 {
+		t.Errorf("Expected no TransferFeeConfig without a fee, got %v", err)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		modify func(*StablecoinPreset)
+		err    error
+	}{
+		{"no symbol", func(p *StablecoinPreset) { p.Symbol = "" }, ErrNotSet},
+		{"too many decimals", func(p *StablecoinPreset) { p.Decimals = 19 }, ErrInvalidField},
+		{"zero pause authority", func(p *StablecoinPreset) { p.PauseAuthority = &solana.PublicKey{} }, ErrInvalidField},
+		{"fee above 100%", func(p *StablecoinPreset) { p.TransferFee = &StablecoinFee{BasisPoints: 10_001, MaximumFee: 1} }, ErrInvalidField},
+		{"fee capped at zero", func(p *StablecoinPreset) { p.TransferFee = &StablecoinFee{BasisPoints: 10} }, ErrInvalidField},
+	} {
+		invalid := preset
+		tc.modify(&invalid)
+		if _, err := invalid.Plan(); !errors.Is(err, tc.err) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.err, err)
+		}
+	}
+}
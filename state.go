@@ -0,0 +1,483 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/dwmfan/token2022/core"
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// Sizes in bytes of the base Token-2022 account layouts, before any
+// extension data.
+const (
+	MintSize     = core.MintSize
+	AccountSize  = core.AccountSize
+	MultisigSize = core.MultisigSize
+)
+
+// AccountType is the byte written after the base layout of accounts that
+// carry extensions.
+type AccountType uint8
+
+const (
+	AccountTypeUninitialized AccountType = iota
+	AccountTypeMint
+	AccountTypeAccount
+)
+
+// AccountTypeOf returns the type of a Token-2022 account from its data:
+// the AccountType byte of an extended account, or the type implied by the
+// length of a base layout. Other data, such as a multisig, is
+// AccountTypeUninitialized.
+func AccountTypeOf(data []byte) AccountType {
+	return AccountType(core.AccountTypeOf(data))
+}
+
+// AccountState is the state of a token account.
+type AccountState uint8
+
+const (
+	AccountStateUninitialized AccountState = iota
+	AccountStateInitialized
+	AccountStateFrozen
+)
+
+func (s AccountState) String() string {
+	switch s {
+	case AccountStateUninitialized:
+		return "Uninitialized"
+	case AccountStateInitialized:
+		return "Initialized"
+	case AccountStateFrozen:
+		return "Frozen"
+	}
+	return fmt.Sprintf("AccountState(%d)", uint8(s))
+}
+
+func (s AccountState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *AccountState) UnmarshalText(text []byte) error {
+	n, err := parseEnumName(text, "AccountState", 3, func(i uint64) string { return AccountState(i).String() }, 8)
+	*s = AccountState(n)
+	return err
+}
+
+// ExtensionType identifies a Token-2022 extension in the TLV data that
+// follows the base account layout.
+type ExtensionType uint16
+
+const (
+	ExtensionUninitialized ExtensionType = iota
+	ExtensionTransferFeeConfig
+	ExtensionTransferFeeAmount
+	ExtensionMintCloseAuthority
+	ExtensionConfidentialTransferMint
+	ExtensionConfidentialTransferAccount
+	ExtensionDefaultAccountState
+	ExtensionImmutableOwner
+	ExtensionMemoTransfer
+	ExtensionNonTransferable
+	ExtensionInterestBearingConfig
+	ExtensionCpiGuard
+	ExtensionPermanentDelegate
+	ExtensionNonTransferableAccount
+	ExtensionTransferHook
+	ExtensionTransferHookAccount
+	ExtensionConfidentialTransferFeeConfig
+	ExtensionConfidentialTransferFeeAmount
+	ExtensionMetadataPointer
+	ExtensionTokenMetadata
+	ExtensionGroupPointer
+	ExtensionTokenGroup
+	ExtensionGroupMemberPointer
+	ExtensionTokenGroupMember
+	ExtensionConfidentialMintBurn
+	ExtensionScaledUiAmount
+	ExtensionPausable
+	ExtensionPausableAccount
+)
+
+var extensionNames = map[ExtensionType]string{
+	ExtensionUninitialized:                 "Uninitialized",
+	ExtensionTransferFeeConfig:             "TransferFeeConfig",
+	ExtensionTransferFeeAmount:             "TransferFeeAmount",
+	ExtensionMintCloseAuthority:            "MintCloseAuthority",
+	ExtensionConfidentialTransferMint:      "ConfidentialTransferMint",
+	ExtensionConfidentialTransferAccount:   "ConfidentialTransferAccount",
+	ExtensionDefaultAccountState:           "DefaultAccountState",
+	ExtensionImmutableOwner:                "ImmutableOwner",
+	ExtensionMemoTransfer:                  "MemoTransfer",
+	ExtensionNonTransferable:               "NonTransferable",
+	ExtensionInterestBearingConfig:         "InterestBearingConfig",
+	ExtensionCpiGuard:                      "CpiGuard",
+	ExtensionPermanentDelegate:             "PermanentDelegate",
+	ExtensionNonTransferableAccount:        "NonTransferableAccount",
+	ExtensionTransferHook:                  "TransferHook",
+	ExtensionTransferHookAccount:           "TransferHookAccount",
+	ExtensionConfidentialTransferFeeConfig: "ConfidentialTransferFeeConfig",
+	ExtensionConfidentialTransferFeeAmount: "ConfidentialTransferFeeAmount",
+	ExtensionMetadataPointer:               "MetadataPointer",
+	ExtensionTokenMetadata:                 "TokenMetadata",
+	ExtensionGroupPointer:                  "GroupPointer",
+	ExtensionTokenGroup:                    "TokenGroup",
+	ExtensionGroupMemberPointer:            "GroupMemberPointer",
+	ExtensionTokenGroupMember:              "TokenGroupMember",
+	ExtensionConfidentialMintBurn:          "ConfidentialMintBurn",
+	ExtensionScaledUiAmount:                "ScaledUiAmount",
+	ExtensionPausable:                      "Pausable",
+	ExtensionPausableAccount:               "PausableAccount",
+}
+
+func (t ExtensionType) String() string {
+	if name, ok := extensionNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("ExtensionType(%d)", uint16(t))
+}
+
+func (t ExtensionType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *ExtensionType) UnmarshalText(text []byte) error {
+	n, err := parseEnumName(text, "ExtensionType", uint64(ExtensionPausableAccount)+1, func(i uint64) string { return ExtensionType(i).String() }, 16)
+	*t = ExtensionType(n)
+	return err
+}
+
+// Extension is a raw TLV entry. Data aliases the account data it was
+// decoded from.
+type Extension struct {
+	Type ExtensionType
+	Data []byte
+}
+
+// MarshalJSON encodes the extension with its type name and base64 data.
+func (e Extension) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&e)
+}
+
+func (e *Extension) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, e)
+}
+
+// Mint is a decoded Token-2022 mint account.
+type Mint struct {
+	MintAuthority   *solana.PublicKey
+	Supply          uint64
+	Decimals        uint8
+	IsInitialized   bool
+	FreezeAuthority *solana.PublicKey
+	Extensions      []Extension
+
+	cache *extensionCache
+}
+
+// MarshalJSON encodes the mint with base58 public keys and u64 values as
+// strings.
+func (m Mint) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&m)
+}
+
+func (m *Mint) UnmarshalJSON(data []byte) error {
+	if err := unmarshalJSONFields(data, m); err != nil {
+		return err
+	}
+	if len(m.Extensions) > 0 {
+		m.cache = newExtensionCache()
+	}
+	return nil
+}
+
+// Extension returns the data of the extension of type t.
+func (m *Mint) Extension(t ExtensionType) ([]byte, bool) {
+	return findExtension(m.Extensions, t)
+}
+
+// TokenMetadata returns the decoded TokenMetadata extension of the mint.
+// It is decoded on first access; later calls return the same value.
+func (m *Mint) TokenMetadata() (*TokenMetadata, bool, error) {
+	data, ok := m.Extension(ExtensionTokenMetadata)
+	if !ok {
+		return nil, false, nil
+	}
+	value, err := m.cache.load(ExtensionTokenMetadata, func() (interface{}, error) {
+		return DecodeTokenMetadata(data)
+	})
+	if err != nil {
+		return nil, true, err
+	}
+	return value.(*TokenMetadata), true, nil
+}
+
+// TokenMetadata is the token-metadata interface layout, stored in the
+// TokenMetadata mint extension and returned by the Emit instruction.
+type TokenMetadata struct {
+	// UpdateAuthority is nil when the metadata is immutable.
+	UpdateAuthority    *solana.PublicKey
+	Mint               solana.PublicKey
+	Name               string
+	Symbol             string
+	URI                string
+	AdditionalMetadata [][2]string
+}
+
+// MarshalJSON encodes the metadata with a base58 update authority and mint.
+func (m TokenMetadata) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&m)
+}
+
+func (m *TokenMetadata) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, m)
+}
+
+// DecodeTokenMetadata decodes Borsh-encoded TokenMetadata.
+func DecodeTokenMetadata(data []byte) (*TokenMetadata, error) {
+	if len(data) < 64 {
+		return nil, fmt.Errorf("%w: token metadata too short: %d bytes", ErrInvalidAccountData, len(data))
+	}
+	metadata := &TokenMetadata{Mint: solana.PublicKeyFromBytes(data[32:64])}
+	if authority := solana.PublicKeyFromBytes(data[0:32]); !authority.IsZero() {
+		metadata.UpdateAuthority = &authority
+	}
+	rest := data[64:]
+	readString := func() (string, error) {
+		if len(rest) < 4 {
+			return "", fmt.Errorf("%w: token metadata truncated", ErrInvalidAccountData)
+		}
+		length := binary.LittleEndian.Uint32(rest)
+		if uint64(len(rest)-4) < uint64(length) {
+			return "", fmt.Errorf("%w: token metadata string of %d bytes truncated", ErrInvalidAccountData, length)
+		}
+		s := string(rest[4 : 4+length])
+		rest = rest[4+length:]
+		return s, nil
+	}
+	var err error
+	if metadata.Name, err = readString(); err != nil {
+		return nil, err
+	}
+	if metadata.Symbol, err = readString(); err != nil {
+		return nil, err
+	}
+	if metadata.URI, err = readString(); err != nil {
+		return nil, err
+	}
+	if len(rest) < 4 {
+		return nil, fmt.Errorf("%w: token metadata truncated", ErrInvalidAccountData)
+	}
+	count := binary.LittleEndian.Uint32(rest)
+	rest = rest[4:]
+	for i := uint32(0); i < count; i++ {
+		key, err := readString()
+		if err != nil {
+			return nil, err
+		}
+		value, err := readString()
+		if err != nil {
+			return nil, err
+		}
+		metadata.AdditionalMetadata = append(metadata.AdditionalMetadata, [2]string{key, value})
+	}
+	return metadata, nil
+}
+
+// TokenAccount is a decoded Token-2022 token account.
+type TokenAccount struct {
+	Mint            solana.PublicKey
+	Owner           solana.PublicKey
+	Amount          uint64
+	Delegate        *solana.PublicKey
+	State           AccountState
+	IsNative        *uint64
+	DelegatedAmount uint64
+	CloseAuthority  *solana.PublicKey
+	Extensions      []Extension
+}
+
+// MarshalJSON encodes the account with base58 public keys and u64 values
+// as strings.
+func (a TokenAccount) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&a)
+}
+
+func (a *TokenAccount) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, a)
+}
+
+// Extension returns the data of the extension of type t.
+func (a *TokenAccount) Extension(t ExtensionType) ([]byte, bool) {
+	return findExtension(a.Extensions, t)
+}
+
+// IsFrozen reports whether the account is frozen.
+func (a *TokenAccount) IsFrozen() bool {
+	return a.State == AccountStateFrozen
+}
+
+// DecodeMint decodes the data of a mint account. Extensions are indexed
+// but their contents are only decoded by accessors such as TokenMetadata,
+// so the cost does not depend on their size.
+func DecodeMint(data []byte) (*Mint, error) {
+	decoded, err := core.DecodeMint(data)
+	if err != nil {
+		return nil, err
+	}
+	mint := &Mint{
+		MintAuthority:   (*solana.PublicKey)(decoded.MintAuthority),
+		Supply:          decoded.Supply,
+		Decimals:        decoded.Decimals,
+		IsInitialized:   decoded.IsInitialized,
+		FreezeAuthority: (*solana.PublicKey)(decoded.FreezeAuthority),
+	}
+	if len(data) > MintSize {
+		mint.Extensions = fromCoreExtensions(decoded.Extensions)
+		mint.cache = newExtensionCache()
+	}
+	return mint, nil
+}
+
+// DecodeTokenAccount decodes the data of a token account, indexing its
+// extensions like DecodeMint.
+func DecodeTokenAccount(data []byte) (*TokenAccount, error) {
+	decoded, err := core.DecodeTokenAccount(data)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenAccount{
+		Mint:            solana.PublicKey(decoded.Mint),
+		Owner:           solana.PublicKey(decoded.Owner),
+		Amount:          decoded.Amount,
+		Delegate:        (*solana.PublicKey)(decoded.Delegate),
+		State:           AccountState(decoded.State),
+		IsNative:        decoded.IsNative,
+		DelegatedAmount: decoded.DelegatedAmount,
+		CloseAuthority:  (*solana.PublicKey)(decoded.CloseAuthority),
+		Extensions:      fromCoreExtensions(decoded.Extensions),
+	}, nil
+}
+
+// EncodeMint encodes a mint in the on-chain layout, followed by its
+// extensions when it has any.
+func EncodeMint(mint *Mint) []byte {
+	return core.EncodeMint(&core.Mint{
+		MintAuthority:   (*core.PublicKey)(mint.MintAuthority),
+		Supply:          mint.Supply,
+		Decimals:        mint.Decimals,
+		IsInitialized:   mint.IsInitialized,
+		FreezeAuthority: (*core.PublicKey)(mint.FreezeAuthority),
+		Extensions:      toCoreExtensions(mint.Extensions),
+	})
+}
+
+// EncodeTokenAccount encodes a token account in the on-chain layout,
+// followed by its extensions when it has any.
+func EncodeTokenAccount(account *TokenAccount) []byte {
+	return core.EncodeTokenAccount(&core.TokenAccount{
+		Mint:            core.PublicKey(account.Mint),
+		Owner:           core.PublicKey(account.Owner),
+		Amount:          account.Amount,
+		Delegate:        (*core.PublicKey)(account.Delegate),
+		State:           uint8(account.State),
+		IsNative:        account.IsNative,
+		DelegatedAmount: account.DelegatedAmount,
+		CloseAuthority:  (*core.PublicKey)(account.CloseAuthority),
+		Extensions:      toCoreExtensions(account.Extensions),
+	})
+}
+
+// FetchMint fetches and decodes a mint account.
+func FetchMint(ctx context.Context, client RPCClient, mint solana.PublicKey, commitment rpc.CommitmentType) (*Mint, error) {
+	return FetchMintWithOpts(ctx, client, mint, &FetchOpts{Commitment: commitment})
+}
+
+// FetchMintWithOpts fetches and decodes a mint account.
+func FetchMintWithOpts(ctx context.Context, client RPCClient, mint solana.PublicKey, opts *FetchOpts) (*Mint, error) {
+	out, err := fetchTokenProgramAccount(ctx, client, mint, opts)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeMint(out.Value.Data.GetBinary())
+}
+
+// FetchTokenAccount fetches and decodes a token account.
+func FetchTokenAccount(ctx context.Context, client RPCClient, account solana.PublicKey, commitment rpc.CommitmentType) (*TokenAccount, error) {
+	return FetchTokenAccountWithOpts(ctx, client, account, &FetchOpts{Commitment: commitment})
+}
+
+// FetchTokenAccountWithOpts fetches and decodes a token account.
+func FetchTokenAccountWithOpts(ctx context.Context, client RPCClient, account solana.PublicKey, opts *FetchOpts) (*TokenAccount, error) {
+	out, err := fetchTokenProgramAccount(ctx, client, account, opts)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeTokenAccount(out.Value.Data.GetBinary())
+}
+
+func fetchTokenProgramAccount(ctx context.Context, client RPCClient, account solana.PublicKey, opts *FetchOpts) (*rpc.GetAccountInfoResult, error) {
+	out, err := client.GetAccountInfoWithOpts(ctx, account, opts.accountInfoOpts())
+	if errors.Is(err, rpc.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s: %w", ErrAccountNotFound, account, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching account %s: %w", account, err)
+	}
+	if out == nil || out.Value == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, account)
+	}
+	if !out.Value.Owner.Equals(solana.Token2022ProgramID) {
+		return nil, fmt.Errorf("%w: %s is not owned by the Token-2022 program", ErrInvalidAccountOwner, account)
+	}
+	return out, nil
+}
+
+func fromCoreExtensions(extensions []core.Extension) []Extension {
+	if extensions == nil {
+		return nil
+	}
+	out := make([]Extension, len(extensions))
+	for i, ext := range extensions {
+		out[i] = Extension{Type: ExtensionType(ext.Type), Data: ext.Data}
+	}
+	return out
+}
+
+func toCoreExtensions(extensions []Extension) []core.Extension {
+	if extensions == nil {
+		return nil
+	}
+	out := make([]core.Extension, len(extensions))
+	for i, ext := range extensions {
+		out[i] = core.Extension{Type: uint16(ext.Type), Data: ext.Data}
+	}
+	return out
+}
+
+func findExtension(extensions []Extension, t ExtensionType) ([]byte, bool) {
+	for _, ext := range extensions {
+		if ext.Type == t {
+			return ext.Data, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,220 @@
+package token2022
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// encodeMint encodes an initialized mint with the given authority and
+// extensions.
+func encodeMint(authority *solana.PublicKey, supply uint64, decimals uint8, extensions ...Extension) []byte {
+	return EncodeMint(&Mint{
+		MintAuthority: authority,
+		Supply:        supply,
+		Decimals:      decimals,
+		IsInitialized: true,
+		Extensions:    extensions,
+	})
+}
+
+// encodeTokenAccount encodes an initialized token account.
+func encodeTokenAccount(mint, owner solana.PublicKey, amount uint64, extensions ...Extension) []byte {
+	return EncodeTokenAccount(&TokenAccount{
+		Mint:       mint,
+		Owner:      owner,
+		Amount:     amount,
+		State:      AccountStateInitialized,
+		Extensions: extensions,
+	})
+}
+
+func TestDecodeMint(t *testing.T) {
+
+	var (
+		authority = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		data      = encodeMint(&authority, 1_000_000, 6)
+	)
+
+	mint, err := DecodeMint(data)
+	if err != nil {
+		t.Fatalf("DecodeMint: %v", err)
+	}
+	if mint.MintAuthority == nil || !mint.MintAuthority.Equals(authority) {
+		t.Errorf("unexpected mint authority %v", mint.MintAuthority)
+	}
+	if mint.FreezeAuthority != nil {
+		t.Errorf("expected no freeze authority, got %s", mint.FreezeAuthority)
+	}
+	if mint.Supply != 1_000_000 || mint.Decimals != 6 || !mint.IsInitialized {
+		t.Errorf("unexpected mint %+v", mint)
+	}
+	if len(mint.Extensions) != 0 {
+		t.Errorf("expected no extensions, got %d", len(mint.Extensions))
+	}
+
+	if _, err := DecodeMint(data[:40]); err == nil {
+		t.Error("expected error for short data")
+	}
+}
+
+func TestDecodeMintExtensions(t *testing.T) {
+
+	delegate := solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	data := encodeMint(nil, 0, 9,
+		Extension{Type: ExtensionPermanentDelegate, Data: delegate[:]},
+		Extension{Type: ExtensionNonTransferable, Data: []byte{}},
+	)
+
+	mint, err := DecodeMint(data)
+	if err != nil {
+		t.Fatalf("DecodeMint: %v", err)
+	}
+	if len(mint.Extensions) != 2 {
+		t.Fatalf("expected 2 extensions, got %d", len(mint.Extensions))
+	}
+	ext, ok := mint.Extension(ExtensionPermanentDelegate)
+	if !ok || !solana.PublicKeyFromBytes(ext).Equals(delegate) {
+		t.Errorf("unexpected permanent delegate extension %x", ext)
+	}
+	if _, ok := mint.Extension(ExtensionNonTransferable); !ok {
+		t.Error("expected NonTransferable extension")
+	}
+	if _, ok := mint.Extension(ExtensionTransferHook); ok {
+		t.Error("unexpected TransferHook extension")
+	}
+
+	// A token account type byte must not decode as a mint.
+	data[AccountSize] = byte(AccountTypeAccount)
+	if _, err := DecodeMint(data); err == nil {
+		t.Error("expected error for wrong account type")
+	}
+
+	// Truncated TLV data.
+	if _, err := DecodeMint(data[:len(data)-1]); err == nil {
+		t.Error("expected error for truncated extension")
+	}
+}
+
+func TestDecodeTokenAccount(t *testing.T) {
+
+	var (
+		mint  = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		owner = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	)
+	data := encodeTokenAccount(mint, owner, 42, Extension{Type: ExtensionImmutableOwner, Data: []byte{}})
+	data[108] = byte(AccountStateFrozen)
+
+	account, err := DecodeTokenAccount(data)
+	if err != nil {
+		t.Fatalf("DecodeTokenAccount: %v", err)
+	}
+	if !account.Mint.Equals(mint) || !account.Owner.Equals(owner) || account.Amount != 42 {
+		t.Errorf("unexpected account %+v", account)
+	}
+	if !account.IsFrozen() || account.State.String() != "Frozen" {
+		t.Errorf("expected frozen account, got %s", account.State)
+	}
+	if account.Delegate != nil || account.IsNative != nil || account.CloseAuthority != nil {
+		t.Errorf("expected unset optional fields, got %+v", account)
+	}
+	if _, ok := account.Extension(ExtensionImmutableOwner); !ok {
+		t.Error("expected ImmutableOwner extension")
+	}
+
+	if _, err := DecodeTokenAccount(make([]byte, MultisigSize)); err == nil {
+		t.Error("expected error for multisig data")
+	}
+}
+
+func TestExtensionTypeString(t *testing.T) {
+	if ExtensionTransferFeeConfig.String() != "TransferFeeConfig" {
+		t.Errorf("unexpected name %s", ExtensionTransferFeeConfig)
+	}
+	if ExtensionType(999).String() != "ExtensionType(999)" {
+		t.Errorf("unexpected name %s", ExtensionType(999))
+	}
+}
+
+func TestFetchTokenAccountMinContextSlot(t *testing.T) {
+
+	var (
+		mint    = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		owner   = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		account = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		client  = newMockRPC()
+		ctx     = context.Background()
+	)
+	client.slot = 50
+	client.setAccount(account, solana.Token2022ProgramID, encodeTokenAccount(mint, owner, 7))
+
+	if _, err := FetchTokenAccountWithOpts(ctx, client, account, FetchOptsAtSlot(rpc.CommitmentConfirmed, 60)); err == nil {
+		t.Fatal("expected error from a node behind the minimum context slot")
+	}
+	client.slot = 60
+	decoded, err := FetchTokenAccountWithOpts(ctx, client, account, FetchOptsAtSlot(rpc.CommitmentConfirmed, 60))
+	if err != nil {
+		t.Fatalf("FetchTokenAccountWithOpts: %v", err)
+	}
+	if decoded.Amount != 7 {
+		t.Errorf("unexpected amount %d", decoded.Amount)
+	}
+
+	client.setAccount(mint, solana.TokenProgramID, encodeMint(nil, 0, 0))
+	if _, err := FetchMint(ctx, client, mint, rpc.CommitmentConfirmed); err == nil {
+		t.Error("expected error for an account not owned by Token-2022")
+	}
+}
+
+func TestEncodeStateRoundTrip(t *testing.T) {
+	var vectors []goldenAccount
+	loadGolden(t, "accounts.json", &vectors)
+
+	for _, vector := range vectors {
+		data, err := hex.DecodeString(vector.Data)
+		if err != nil {
+			t.Fatalf("%s: %v", vector.Name, err)
+		}
+		var encoded []byte
+		switch vector.Kind {
+		case "mint":
+			mint, err := DecodeMint(data)
+			if err != nil {
+				t.Fatalf("%s: DecodeMint: %v", vector.Name, err)
+			}
+			encoded = EncodeMint(mint)
+		case "account":
+			account, err := DecodeTokenAccount(data)
+			if err != nil {
+				t.Fatalf("%s: DecodeTokenAccount: %v", vector.Name, err)
+			}
+			encoded = EncodeTokenAccount(account)
+		}
+		if !bytes.Equal(encoded, data) {
+			t.Errorf("%s: encoded %x, want %x", vector.Name, encoded, data)
+		}
+	}
+}
+
+func TestAccountTypeOf(t *testing.T) {
+	var vectors []goldenAccount
+	loadGolden(t, "accounts.json", &vectors)
+
+	for _, vector := range vectors {
+		data, err := hex.DecodeString(vector.Data)
+		if err != nil {
+			t.Fatalf("%s: %v", vector.Name, err)
+		}
+		want := map[string]AccountType{"mint": AccountTypeMint, "account": AccountTypeAccount}[vector.Kind]
+		if got := AccountTypeOf(data); got != want {
+			t.Errorf("%s: expected %d, got %d", vector.Name, want, got)
+		}
+	}
+	if got := AccountTypeOf(make([]byte, MultisigSize)); got != AccountTypeUninitialized {
+		t.Errorf("Expected a multisig to be uninitialized, got %d", got)
+	}
+}
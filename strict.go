@@ -0,0 +1,168 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"reflect"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// MaxStrictDecimals is the largest number of decimals ValidateStrict
+// accepts. The program allows up to 255, but with more than 9 decimals a
+// u64 amount holds fewer than 18.4 billion whole tokens.
+const MaxStrictDecimals = 9
+
+// ValidateStrict runs Validate and then checks the values of inst against
+// each other, returning an *InvalidFieldError for:
+//
+//   - a zero amount in a transfer, mint, burn or approval;
+//   - more than MaxStrictDecimals decimals in a checked instruction or a
+//     new mint;
+//   - a TransferCheckedWithFee fee larger than its amount;
+//   - a transfer fee above MaxFeeBasisPoints, or a non-zero fee with a
+//     zero maximum;
+//   - multisig signers listed twice. The program marks each matched signer
+//     once, so a duplicate is harmless on-chain; rejecting it is local
+//     hygiene that catches a signer list built by mistake.
+//
+// ValidateStrictMultisig also checks the signers against the threshold of
+// a decoded multisig.
+func ValidateStrict(inst TypedInstruction) error {
+	if err := inst.Validate(); err != nil {
+		return err
+	}
+	if err := validateUniqueSigners(inst); err != nil {
+		return err
+	}
+	switch inst := inst.(type) {
+	case *Transfer2022:
+		return validatePositive(inst.Amount)
+	case *TransferChecked2022:
+		return validateAmountAndDecimals(inst.Amount, inst.Decimals)
+	case *TransferCheckedWithFee2022:
+		if inst.Fee > inst.Amount {
+			return errInvalidField("Fee", "of %d exceeds the amount of %d", inst.Fee, inst.Amount)
+		}
+		return validateAmountAndDecimals(inst.Amount, inst.Decimals)
+	case *MintTo2022:
+		return validatePositive(inst.Amount)
+	case *MintToChecked2022:
+		return validateAmountAndDecimals(inst.Amount, inst.Decimals)
+	case *Burn2022:
+		return validatePositive(inst.Amount)
+	case *BurnChecked2022:
+		return validateAmountAndDecimals(inst.Amount, inst.Decimals)
+	case *Approve2022:
+		return validatePositive(inst.Amount)
+	case *ApproveChecked2022:
+		return validateAmountAndDecimals(inst.Amount, inst.Decimals)
+	case *InitializeMint2022:
+		return validateDecimals(inst.Decimals)
+	case *InitializeMintLegacy2022:
+		return validateDecimals(inst.Decimals)
+	case *InitializeTransferFeeConfig2022:
+		return validateTransferFee(inst.TransferFeeBasisPoints, inst.MaximumFee)
+	case *SetTransferFee2022:
+		return validateTransferFee(inst.TransferFeeBasisPoints, inst.MaximumFee)
+	}
+	return nil
+}
+
+func validatePositive(amount uint64) error {
+	if amount == 0 {
+		return errInvalidField("Amount", "must be positive")
+	}
+	return nil
+}
+
+func validateDecimals(decimals uint8) error {
+	if decimals > MaxStrictDecimals {
+		return errInvalidField("Decimals", "of %d exceeds %d", decimals, MaxStrictDecimals)
+	}
+	return nil
+}
+
+func validateAmountAndDecimals(amount uint64, decimals uint8) error {
+	if err := validatePositive(amount); err != nil {
+		return err
+	}
+	return validateDecimals(decimals)
+}
+
+func validateTransferFee(basisPoints uint16, maximumFee uint64) error {
+	if basisPoints > MaxFeeBasisPoints {
+		return errInvalidField("TransferFeeBasisPoints", "of %d exceeds %d", basisPoints, MaxFeeBasisPoints)
+	}
+	if basisPoints > 0 && maximumFee == 0 {
+		return errInvalidField("MaximumFee", "must be positive when TransferFeeBasisPoints is set")
+	}
+	return nil
+}
+
+// ValidateStrictMultisig runs ValidateStrict on inst, whose authority is
+// multisig, and checks that at least M of its Signers are distinct signers
+// of multisig. With fewer the program rejects the instruction with
+// NotEnoughSigners.
+func ValidateStrictMultisig(inst TypedInstruction, multisig *Multisig) error {
+	if err := ValidateStrict(inst); err != nil {
+		return err
+	}
+	if multisig == nil {
+		return errNotSet("Multisig")
+	}
+	var count int
+	for _, signer := range builderSigners(inst) {
+		if multisig.IsSigner(signer) {
+			count++
+		}
+	}
+	if count < int(multisig.M) {
+		return errInvalidField("Signers", "hold %d signers of the multisig, fewer than its threshold of %d", count, multisig.M)
+	}
+	return nil
+}
+
+// builderSigners returns the Signers field of a builder, which lists
+// multisig signers, or nil when it has none.
+func builderSigners(inst TypedInstruction) []solana.PublicKey {
+	v := reflect.ValueOf(inst)
+	for v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	field := v.FieldByName("Signers")
+	if !field.IsValid() {
+		return nil
+	}
+	signers, _ := field.Interface().([]solana.PublicKey)
+	return signers
+}
+
+// validateUniqueSigners checks that the Signers field of a builder has no
+// duplicates.
+func validateUniqueSigners(inst TypedInstruction) error {
+	signers := builderSigners(inst)
+	seen := make(map[solana.PublicKey]bool, len(signers))
+	for _, signer := range signers {
+		if seen[signer] {
+			return errInvalidField("Signers", "list %s twice", signer)
+		}
+		seen[signer] = true
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package token2022
+
+import (
+	"errors"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+func TestValidateStrict(t *testing.T) {
+	var (
+		wallet      = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+	)
+	valid := []interface{ ValidateStrict() error }{
+		NewTransferChecked2022Instruction(10, 6, source, mint, destination, wallet),
+		NewTransferCheckedWithFee2022Instruction(10, 6, 1, source, mint, destination, wallet),
+		NewInitializeMultisig2022Instruction(2, wallet, source, destination),
+		NewSetTransferFee2022Instruction(0, 0, mint, wallet),
+	}
+	for _, inst := range valid {
+		if err := inst.ValidateStrict(); err != nil {
+			t.Errorf("Expected %T to be valid, got %v", inst, err)
+		}
+	}
+
+	cases := []struct {
+		inst  interface{ ValidateStrict() error }
+		field string
+	}{
+		{NewTransfer2022Instruction(0, source, destination, wallet), "Amount"},
+		{NewMintToChecked2022Instruction(10, 12, mint, destination, wallet), "Decimals"},
+		{NewTransferCheckedWithFee2022Instruction(10, 6, 11, source, mint, destination, wallet), "Fee"},
+		{NewSetTransferFee2022Instruction(50, 0, mint, wallet), "MaximumFee"},
+		{NewBurnChecked2022Instruction(10, 6, source, mint, wallet, destination, destination), "Signers"},
+		{NewInitializeMultisig2022Instruction(2, wallet, source, source), "Signers"},
+	}
+	for _, tt := range cases {
+		err := tt.inst.ValidateStrict()
+		var invalid *InvalidFieldError
+		if !errors.As(err, &invalid) || !errors.Is(err, ErrInvalidField) || invalid.Field != tt.field {
+			t.Errorf("Expected %T to have an invalid %s, got %v", tt.inst, tt.field, err)
+		}
+	}
+
+	if err := ValidateStrict(NewTransfer2022Instruction(10, source, solana.PublicKey{}, wallet)); !errors.Is(err, ErrNotSet) {
+		t.Errorf("Expected Validate to run first, got %v", err)
+	}
+}
+
+func TestValidateStrictMultisig(t *testing.T) {
+	var (
+		mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		multisig    = solana.MustPublicKeyFromBase58("83mctxW8BCh6nPGjxx4jmyaEfbpcMZpLQiv7tXVSAV7a")
+		signer1     = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		signer2     = solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR")
+	)
+	decoded := &Multisig{M: 2, N: 3, IsInitialized: true, Signers: []solana.PublicKey{signer1, signer2, destination}}
+
+	if err := ValidateStrictMultisig(NewTransferChecked2022Instruction(10, 6, source, mint, destination, multisig, signer1, signer2), decoded); err != nil {
+		t.Errorf("Expected two of three signers to be enough, got %v", err)
+	}
+	for name, signers := range map[string][]solana.PublicKey{
+		"below threshold": {signer1},
+		"not a signer":    {signer1, mint},
+		"no signers":      nil,
+	} {
+		err := ValidateStrictMultisig(NewTransferChecked2022Instruction(10, 6, source, mint, destination, multisig, signers...), decoded)
+		var invalid *InvalidFieldError
+		if !errors.As(err, &invalid) || invalid.Field != "Signers" {
+			t.Errorf("%s: Expected invalid Signers, got %v", name, err)
+		}
+	}
+	if err := ValidateStrictMultisig(NewTransferChecked2022Instruction(0, 6, source, mint, destination, multisig, signer1, signer2), decoded); !errors.Is(err, ErrInvalidField) {
+		t.Errorf("Expected ValidateStrict to run first, got %v", err)
+	}
+}
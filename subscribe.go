@@ -0,0 +1,266 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/ws"
+)
+
+// TokenAccountEvent is an update of a Token-2022 token account received
+// over a websocket subscription.
+type TokenAccountEvent struct {
+	Pubkey   solana.PublicKey
+	Slot     uint64
+	Lamports uint64
+	// Account is nil when the account was closed.
+	Account *TokenAccount
+}
+
+// AccountSubscriber streams token account updates from accountSubscribe
+// and programSubscribe. Each subscription has its own websocket
+// connection, which is re-established with backoff when it drops.
+// Updates are delivered in slot order per account: an update older than
+// the last one delivered for the same account, or a repeat of it, is
+// dropped.
+//
+// Updates made while a connection is down are missed unless an RPC
+// client is set with SetClient, in which case SubscribeTokenAccount
+// re-reads the account after reconnecting. The re-read cannot report an
+// account closed while disconnected.
+type AccountSubscriber struct {
+	url        string
+	commitment rpc.CommitmentType
+	retry      RetryPolicy
+	client     RPCClient
+}
+
+// NewAccountSubscriber creates a subscriber for a websocket endpoint that
+// listens at confirmed commitment and reconnects with the backoff of
+// DefaultRetryPolicy.
+func NewAccountSubscriber(wsURL string) *AccountSubscriber {
+	return &AccountSubscriber{
+		url:        wsURL,
+		commitment: rpc.CommitmentConfirmed,
+		retry:      DefaultRetryPolicy(),
+	}
+}
+
+func (s *AccountSubscriber) SetCommitment(commitment rpc.CommitmentType) *AccountSubscriber {
+	s.commitment = commitment
+	return s
+}
+
+// SetReconnectPolicy sets the backoff between reconnection attempts.
+// MaxAttempts is ignored: a subscription reconnects until its context is
+// done.
+func (s *AccountSubscriber) SetReconnectPolicy(policy RetryPolicy) *AccountSubscriber {
+	s.retry = policy
+	return s
+}
+
+// SetClient sets the RPC client used to re-read an account after a
+// reconnection.
+func (s *AccountSubscriber) SetClient(client RPCClient) *AccountSubscriber {
+	s.client = client
+	return s
+}
+
+// SubscribeTokenAccount streams updates of a token account. The channel
+// is closed when ctx is done. Only the first connection error is
+// returned; later disconnections are retried.
+func (s *AccountSubscriber) SubscribeTokenAccount(ctx context.Context, account solana.PublicKey) (<-chan *TokenAccountEvent, error) {
+	subscribe := func(client *ws.Client) (accountStream, error) {
+		sub, err := client.AccountSubscribeWithOpts(account, s.commitment, solana.EncodingBase64)
+		if err != nil {
+			return accountStream{}, err
+		}
+		return accountStream{
+			recv: func(ctx context.Context) (*rpc.KeyedAccount, uint64, error) {
+				result, err := sub.Recv(ctx)
+				if err != nil {
+					return nil, 0, err
+				}
+				value := result.Value.Account
+				return &rpc.KeyedAccount{Pubkey: account, Account: &value}, result.Context.Slot, nil
+			},
+			unsubscribe: sub.Unsubscribe,
+		}, nil
+	}
+	resync := func(ctx context.Context) (*rpc.KeyedAccount, uint64, bool) {
+		if s.client == nil {
+			return nil, 0, false
+		}
+		out, err := s.client.GetAccountInfoWithOpts(ctx, account, &rpc.GetAccountInfoOpts{
+			Encoding:   solana.EncodingBase64,
+			Commitment: s.commitment,
+		})
+		if err != nil {
+			return nil, 0, false
+		}
+		return &rpc.KeyedAccount{Pubkey: account, Account: out.Value}, out.Context.Slot, true
+	}
+	return s.run(ctx, subscribe, resync)
+}
+
+// SubscribeOwner streams updates of every Token-2022 token account owned
+// by owner, including accounts created after subscribing.
+func (s *AccountSubscriber) SubscribeOwner(ctx context.Context, owner solana.PublicKey) (<-chan *TokenAccountEvent, error) {
+	filters := []rpc.RPCFilter{{
+		Memcmp: &rpc.RPCFilterMemcmp{Offset: 32, Bytes: owner[:]},
+	}}
+	subscribe := func(client *ws.Client) (accountStream, error) {
+		sub, err := client.ProgramSubscribeWithOpts(solana.Token2022ProgramID, s.commitment, solana.EncodingBase64, filters)
+		if err != nil {
+			return accountStream{}, err
+		}
+		return accountStream{
+			recv: func(ctx context.Context) (*rpc.KeyedAccount, uint64, error) {
+				result, err := sub.Recv(ctx)
+				if err != nil {
+					return nil, 0, err
+				}
+				return &result.Value, result.Context.Slot, nil
+			},
+			unsubscribe: sub.Unsubscribe,
+		}, nil
+	}
+	return s.run(ctx, subscribe, nil)
+}
+
+// accountStream is an open subscription.
+type accountStream struct {
+	recv        func(ctx context.Context) (*rpc.KeyedAccount, uint64, error)
+	unsubscribe func()
+}
+
+// lastUpdate is the last update delivered for an account.
+type lastUpdate struct {
+	slot uint64
+	data []byte
+}
+
+func (s *AccountSubscriber) run(
+	ctx context.Context,
+	subscribe func(*ws.Client) (accountStream, error),
+	resync func(context.Context) (*rpc.KeyedAccount, uint64, bool),
+) (<-chan *TokenAccountEvent, error) {
+	client, stream, err := s.connect(ctx, subscribe)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan *TokenAccountEvent)
+	go func() {
+		defer close(events)
+		last := map[solana.PublicKey]lastUpdate{}
+		emit := func(keyed *rpc.KeyedAccount, slot uint64) bool {
+			event, ok := tokenAccountEvent(keyed, slot, last)
+			if !ok {
+				return true
+			}
+			select {
+			case events <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		for {
+			keyed, slot, err := stream.recv(ctx)
+			if err == nil {
+				if !emit(keyed, slot) {
+					stream.unsubscribe()
+					client.Close()
+					return
+				}
+				continue
+			}
+
+			stream.unsubscribe()
+			client.Close()
+			if client, stream = s.reconnect(ctx, subscribe); client == nil {
+				return
+			}
+			if resync != nil {
+				if keyed, slot, ok := resync(ctx); ok && !emit(keyed, slot) {
+					stream.unsubscribe()
+					client.Close()
+					return
+				}
+			}
+		}
+	}()
+	return events, nil
+}
+
+func (s *AccountSubscriber) connect(ctx context.Context, subscribe func(*ws.Client) (accountStream, error)) (*ws.Client, accountStream, error) {
+	client, err := ws.Connect(ctx, s.url)
+	if err != nil {
+		return nil, accountStream{}, err
+	}
+	stream, err := subscribe(client)
+	if err != nil {
+		client.Close()
+		return nil, accountStream{}, err
+	}
+	return client, stream, nil
+}
+
+// reconnect retries connect with backoff until it succeeds or ctx is
+// done, in which case it returns a nil client.
+func (s *AccountSubscriber) reconnect(ctx context.Context, subscribe func(*ws.Client) (accountStream, error)) (*ws.Client, accountStream) {
+	for attempt := 1; ; attempt++ {
+		timer := time.NewTimer(s.retry.Backoff(attempt, nil))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, accountStream{}
+		case <-timer.C:
+		}
+		if client, stream, err := s.connect(ctx, subscribe); err == nil {
+			return client, stream
+		}
+	}
+}
+
+// tokenAccountEvent decodes an update, returning false when it is stale,
+// a repeat, or not a token account.
+func tokenAccountEvent(keyed *rpc.KeyedAccount, slot uint64, last map[solana.PublicKey]lastUpdate) (*TokenAccountEvent, bool) {
+	var data []byte
+	if keyed.Account.Data != nil {
+		data = keyed.Account.Data.GetBinary()
+	}
+	if prev, ok := last[keyed.Pubkey]; ok && (slot < prev.slot || slot == prev.slot && bytes.Equal(data, prev.data)) {
+		return nil, false
+	}
+
+	event := &TokenAccountEvent{Pubkey: keyed.Pubkey, Slot: slot, Lamports: keyed.Account.Lamports}
+	if keyed.Account.Owner.Equals(solana.Token2022ProgramID) && len(data) > 0 {
+		account, err := DecodeTokenAccount(data)
+		if err != nil {
+			return nil, false
+		}
+		event.Account = account
+	}
+	last[keyed.Pubkey] = lastUpdate{slot: slot, data: data}
+	return event, true
+}
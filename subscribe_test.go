@@ -0,0 +1,202 @@
+package token2022
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gorilla/websocket"
+)
+
+// mockWS is a websocket endpoint that accepts one subscription per
+// connection and hands the connection to the test.
+type mockWS struct {
+	server *httptest.Server
+	conns  chan *mockWSConn
+}
+
+type mockWSConn struct {
+	conn   *websocket.Conn
+	method string
+	params []json.RawMessage
+}
+
+func newMockWS(t *testing.T) *mockWS {
+	m := &mockWS{conns: make(chan *mockWSConn, 4)}
+	upgrader := websocket.Upgrader{}
+	m.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		var req struct {
+			ID     uint64            `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := conn.ReadJSON(&req); err != nil {
+			conn.Close()
+			return
+		}
+		if err := conn.WriteJSON(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": 7}); err != nil {
+			conn.Close()
+			return
+		}
+		m.conns <- &mockWSConn{conn: conn, method: req.Method, params: req.Params}
+	}))
+	t.Cleanup(m.server.Close)
+	return m
+}
+
+func (m *mockWS) url() string {
+	return "ws" + strings.TrimPrefix(m.server.URL, "http")
+}
+
+func (m *mockWS) accept(t *testing.T) *mockWSConn {
+	select {
+	case conn := <-m.conns:
+		return conn
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for a subscription")
+		return nil
+	}
+}
+
+// notify sends a notification for an account. A nil data closes it.
+func (c *mockWSConn) notify(t *testing.T, pubkey solana.PublicKey, slot uint64, data []byte) {
+	owner := solana.Token2022ProgramID
+	if data == nil {
+		owner = solana.SystemProgramID
+	}
+	account := map[string]interface{}{
+		"lamports":   len(data) * 10,
+		"owner":      owner.String(),
+		"data":       []string{base64.StdEncoding.EncodeToString(data), "base64"},
+		"executable": false,
+		"rentEpoch":  0,
+	}
+	var value interface{} = account
+	method := "accountNotification"
+	if c.method == "programSubscribe" {
+		value = map[string]interface{}{"pubkey": pubkey.String(), "account": account}
+		method = "programNotification"
+	}
+	err := c.conn.WriteJSON(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  method,
+		"params": map[string]interface{}{
+			"subscription": 7,
+			"result": map[string]interface{}{
+				"context": map[string]interface{}{"slot": slot},
+				"value":   value,
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Error writing notification: %v", err)
+	}
+}
+
+func nextEvent(t *testing.T, events <-chan *TokenAccountEvent) *TokenAccountEvent {
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Timed out waiting for an event")
+		return nil
+	}
+}
+
+func TestSubscribeTokenAccount(t *testing.T) {
+
+	var (
+		account = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		mint    = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		owner   = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		server  = newMockWS(t)
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := NewAccountSubscriber(server.url()).
+		SetReconnectPolicy(RetryPolicy{InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, Multiplier: 1}).
+		SubscribeTokenAccount(ctx, account)
+	if err != nil {
+		t.Fatalf("Error subscribing: %v", err)
+	}
+
+	conn := server.accept(t)
+	if conn.method != "accountSubscribe" || !strings.Contains(string(conn.params[0]), account.String()) {
+		t.Fatalf("Unexpected subscription %s %s", conn.method, conn.params)
+	}
+	conn.notify(t, account, 10, encodeTokenAccount(mint, owner, 5))
+	conn.notify(t, account, 9, encodeTokenAccount(mint, owner, 4))  // stale
+	conn.notify(t, account, 10, encodeTokenAccount(mint, owner, 5)) // repeat
+	conn.notify(t, account, 11, encodeTokenAccount(mint, owner, 6))
+
+	if event := nextEvent(t, events); event.Slot != 10 || event.Account.Amount != 5 {
+		t.Errorf("Unexpected event %+v", event)
+	}
+	if event := nextEvent(t, events); event.Slot != 11 || event.Account.Amount != 6 {
+		t.Errorf("Unexpected event %+v", event)
+	}
+
+	// The connection drops; the subscriber reconnects and resubscribes.
+	conn.conn.Close()
+	conn = server.accept(t)
+	conn.notify(t, account, 11, encodeTokenAccount(mint, owner, 6)) // repeat
+	conn.notify(t, account, 12, nil)
+	if event := nextEvent(t, events); event.Slot != 12 || event.Account != nil || !event.Pubkey.Equals(account) {
+		t.Errorf("Expected close event, got %+v", event)
+	}
+
+	cancel()
+	for range events {
+	}
+}
+
+func TestSubscribeOwner(t *testing.T) {
+
+	var (
+		first  = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		second = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		mint   = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		owner  = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		server = newMockWS(t)
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := NewAccountSubscriber(server.url()).SubscribeOwner(ctx, owner)
+	if err != nil {
+		t.Fatalf("Error subscribing: %v", err)
+	}
+
+	conn := server.accept(t)
+	if conn.method != "programSubscribe" || !strings.Contains(string(conn.params[1]), owner.String()) {
+		t.Fatalf("Unexpected subscription %s %s", conn.method, conn.params)
+	}
+	conn.notify(t, first, 20, encodeTokenAccount(mint, owner, 1))
+	conn.notify(t, second, 19, encodeTokenAccount(mint, owner, 2)) // older slot, other account
+	conn.notify(t, first, 18, encodeTokenAccount(mint, owner, 0))  // stale
+
+	if event := nextEvent(t, events); !event.Pubkey.Equals(first) || event.Account.Amount != 1 {
+		t.Errorf("Unexpected event %+v", event)
+	}
+	if event := nextEvent(t, events); !event.Pubkey.Equals(second) || event.Account.Amount != 2 {
+		t.Errorf("Unexpected event %+v", event)
+	}
+	select {
+	case event := <-events:
+		t.Errorf("Expected stale update to be dropped, got %+v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"fmt"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// SupplyChange is the effect of one transaction on the supply of a mint.
+type SupplyChange struct {
+	Signature solana.Signature
+	Slot      uint64
+	BlockTime *solana.UnixTimeSeconds
+	Minted    uint64
+	Burned    uint64
+	// FeesWithdrawn is the amount of withheld transfer fees withdrawn to
+	// a token account. Withdrawals do not change the supply. The amount is
+	// inferred from the balance change of the destination account.
+	FeesWithdrawn uint64
+	// Supply is the supply after the transaction.
+	Supply uint64
+}
+
+// SupplyHistory is the supply of a mint over time.
+type SupplyHistory struct {
+	Mint solana.PublicKey
+	// Changes lists the transactions that minted, burned or withdrew fees,
+	// oldest first.
+	Changes            []*SupplyChange
+	Supply             uint64
+	TotalMinted        uint64
+	TotalBurned        uint64
+	TotalFeesWithdrawn uint64
+}
+
+// Reconcile returns an error when the replayed supply differs from
+// supply, usually the Supply of the decoded mint. A difference means
+// that the history read was incomplete, for example because the RPC node
+// does not keep the mint's oldest transactions.
+func (h *SupplyHistory) Reconcile(supply uint64) error {
+	if h.Supply != supply {
+		return fmt.Errorf("replayed supply %d of mint %s differs from supply %d", h.Supply, h.Mint, supply)
+	}
+	return nil
+}
+
+// SupplyReplayer reconstructs the supply history of a mint from its
+// transactions, for auditing issuers. Every MintTo, MintToChecked, Burn
+// and BurnChecked of a successful transaction is counted, including those
+// invoked by other programs.
+type SupplyReplayer struct {
+	history *HistoryReader
+}
+
+// NewSupplyReplayer creates a replayer fetching four transactions at once.
+func NewSupplyReplayer(client HistoryClient) *SupplyReplayer {
+	return &SupplyReplayer{history: NewHistoryReader(client)}
+}
+
+// SetConcurrency sets the number of getTransaction requests in flight at
+// once.
+func (r *SupplyReplayer) SetConcurrency(workers int) *SupplyReplayer {
+	r.history.SetConcurrency(workers)
+	return r
+}
+
+// Replay reads the whole history of mint and replays it from a supply of
+// zero. It fails when a burn exceeds the replayed supply, which happens
+// when the oldest transactions are missing.
+func (r *SupplyReplayer) Replay(ctx context.Context, mint solana.PublicKey, commitment rpc.CommitmentType) (*SupplyHistory, error) {
+	// Pages are read newest first; changes are collected in that order
+	// and replayed in reverse.
+	var newestFirst []*SupplyChange
+	opts := &HistoryOpts{Commitment: commitment}
+	for {
+		sigs, txs, next, err := r.history.fetch(ctx, mint, opts)
+		if err != nil {
+			return nil, err
+		}
+		for i, out := range txs {
+			change, err := supplyChange(out, mint)
+			if err != nil {
+				return nil, fmt.Errorf("error while replaying transaction %s: %w", sigs[i].Signature, err)
+			}
+			if change != nil {
+				newestFirst = append(newestFirst, change)
+			}
+		}
+		if next.IsZero() {
+			break
+		}
+		opts.Before = next
+	}
+
+	history := &SupplyHistory{Mint: mint}
+	for i := len(newestFirst) - 1; i >= 0; i-- {
+		change := newestFirst[i]
+		supply := history.Supply + change.Minted
+		if supply < history.Supply {
+			return nil, fmt.Errorf("supply overflows at transaction %s", change.Signature)
+		}
+		if change.Burned > supply {
+			return nil, fmt.Errorf("transaction %s burns %d with a replayed supply of %d; the history is incomplete", change.Signature, change.Burned, supply)
+		}
+		change.Supply = supply - change.Burned
+		history.Supply = change.Supply
+		history.TotalMinted += change.Minted
+		history.TotalBurned += change.Burned
+		history.TotalFeesWithdrawn += change.FeesWithdrawn
+		history.Changes = append(history.Changes, change)
+	}
+	return history, nil
+}
+
+// supplyChange returns the effect of a transaction on the supply of mint,
+// or nil when it has none.
+func supplyChange(out *rpc.GetTransactionResult, mint solana.PublicKey) (*SupplyChange, error) {
+	if out.Meta.Err != nil {
+		return nil, nil
+	}
+	tx, err := out.Transaction.GetTransaction()
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := ParseTransaction(tx, out.Meta)
+	if err != nil {
+		return nil, err
+	}
+
+	change := &SupplyChange{Signature: parsed.Signature, Slot: out.Slot, BlockTime: out.BlockTime}
+	for _, inst := range parsed.Instructions {
+		switch inst := inst.Instruction.(type) {
+		case *MintTo2022:
+			if inst.Mint.Equals(mint) {
+				change.Minted += inst.Amount
+			}
+		case *MintToChecked2022:
+			if inst.Mint.Equals(mint) {
+				change.Minted += inst.Amount
+			}
+		case *Burn2022:
+			if inst.Mint.Equals(mint) {
+				change.Burned += inst.Amount
+			}
+		case *BurnChecked2022:
+			if inst.Mint.Equals(mint) {
+				change.Burned += inst.Amount
+			}
+		}
+	}
+	fromMint, fromAccounts, err := withdrawnFees(tx, out.Meta, parsed, mint)
+	if err != nil {
+		return nil, err
+	}
+	change.FeesWithdrawn = fromMint + fromAccounts
+
+	if change.Minted == 0 && change.Burned == 0 && change.FeesWithdrawn == 0 {
+		return nil, nil
+	}
+	return change, nil
+}
+
+// withdrawnFees returns the withheld fees of mint withdrawn by a
+// transaction from the mint and from token accounts. The amounts are the
+// balance increases of the destination accounts; a destination of both
+// kinds of withdrawal is counted as a withdrawal from the mint.
+func withdrawnFees(tx *solana.Transaction, meta *rpc.TransactionMeta, parsed *ParsedTransaction, mint solana.PublicKey) (fromMint, fromAccounts uint64, err error) {
+	destinations := map[solana.PublicKey]bool{}
+	var order []solana.PublicKey
+	for _, inst := range parsed.Instructions {
+		var destination solana.PublicKey
+		isMint := false
+		switch inst := inst.Instruction.(type) {
+		case *WithdrawWithheldTokensFromMint2022:
+			if !inst.Mint.Equals(mint) {
+				continue
+			}
+			destination, isMint = inst.Destination, true
+		case *WithdrawWithheldTokensFromAccounts2022:
+			if !inst.Mint.Equals(mint) {
+				continue
+			}
+			destination = inst.Destination
+		default:
+			continue
+		}
+		if _, ok := destinations[destination]; !ok {
+			order = append(order, destination)
+		}
+		destinations[destination] = destinations[destination] || isMint
+	}
+	if len(order) == 0 {
+		return 0, 0, nil
+	}
+
+	changes, err := ExtractBalanceChanges(tx, meta)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, destination := range order {
+		balance := changes.Token(destination)
+		if balance == nil || balance.Delta.Sign() <= 0 {
+			continue
+		}
+		if destinations[destination] {
+			fromMint += balance.Delta.Uint64()
+		} else {
+			fromAccounts += balance.Delta.Uint64()
+		}
+	}
+	return fromMint, fromAccounts, nil
+}
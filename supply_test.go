@@ -0,0 +1,83 @@
+package token2022
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// addSupplyTransaction adds a transaction running inst, with the token
+// balance of depositDestination going from balances[0] to balances[1]
+// when given.
+func addSupplyTransaction(t *testing.T, client *historyRPC, slot uint64, inst solana.Instruction, failed bool, balances ...string) {
+	tx, err := solana.NewTransaction([]solana.Instruction{inst}, solana.Hash{1}, solana.TransactionPayer(depositWallet))
+	if err != nil {
+		t.Fatalf("NewTransaction: %v", err)
+	}
+	tx.Signatures = []solana.Signature{{byte(slot)}}
+	meta := &rpc.TransactionMeta{
+		PreBalances:  make([]uint64, len(tx.Message.AccountKeys)),
+		PostBalances: make([]uint64, len(tx.Message.AccountKeys)),
+	}
+	if failed {
+		meta.Err = map[string]interface{}{"InstructionError": []interface{}{0, "InvalidAccountData"}}
+	}
+	if len(balances) == 2 {
+		for i, key := range tx.Message.AccountKeys {
+			if key.Equals(depositDestination) {
+				balance := func(amount string) []rpc.TokenBalance {
+					return []rpc.TokenBalance{{AccountIndex: uint16(i), Mint: depositMint, UiTokenAmount: &rpc.UiTokenAmount{Amount: amount, Decimals: 6}}}
+				}
+				meta.PreTokenBalances, meta.PostTokenBalances = balance(balances[0]), balance(balances[1])
+			}
+		}
+	}
+	client.add(t, tx, meta, slot)
+}
+
+func TestSupplyReplayer(t *testing.T) {
+	client := &historyRPC{txs: map[solana.Signature]*rpc.GetTransactionResult{}}
+	// Newest first.
+	addSupplyTransaction(t, client, 40, NewWithdrawWithheldTokensFromMint2022Instruction(depositMint, depositDestination, depositWallet).Build(), false, "700", "725")
+	addSupplyTransaction(t, client, 30, NewMintTo2022Instruction(500, depositMint, depositDestination, depositWallet).Build(), true)
+	addSupplyTransaction(t, client, 20, NewBurn2022Instruction(300, depositDestination, depositMint, depositWallet).Build(), false)
+	addSupplyTransaction(t, client, 10, NewMintTo2022Instruction(1_000, depositMint, depositDestination, depositWallet).Build(), false)
+
+	history, err := NewSupplyReplayer(client).Replay(context.Background(), depositMint, rpc.CommitmentFinalized)
+	if err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+	if history.Supply != 700 || history.TotalMinted != 1_000 || history.TotalBurned != 300 || history.TotalFeesWithdrawn != 25 {
+		t.Errorf("Unexpected totals %+v", history)
+	}
+	if len(history.Changes) != 3 {
+		t.Fatalf("Expected 3 changes, got %d", len(history.Changes))
+	}
+	for i, want := range []struct {
+		slot, supply, fees uint64
+	}{{10, 1_000, 0}, {20, 700, 0}, {40, 700, 25}} {
+		change := history.Changes[i]
+		if change.Slot != want.slot || change.Supply != want.supply || change.FeesWithdrawn != want.fees {
+			t.Errorf("Change %d: expected %+v, got %+v", i, want, change)
+		}
+	}
+	if err := history.Reconcile(700); err != nil {
+		t.Errorf("Reconcile: %v", err)
+	}
+	if err := history.Reconcile(800); err == nil {
+		t.Errorf("Expected a reconciliation error")
+	}
+}
+
+func TestSupplyReplayerIncompleteHistory(t *testing.T) {
+	client := &historyRPC{txs: map[solana.Signature]*rpc.GetTransactionResult{}}
+	addSupplyTransaction(t, client, 20, NewBurn2022Instruction(300, depositDestination, depositMint, depositWallet).Build(), false)
+
+	_, err := NewSupplyReplayer(client).Replay(context.Background(), depositMint, rpc.CommitmentFinalized)
+	if err == nil || !strings.Contains(err.Error(), "history is incomplete") {
+		t.Errorf("Expected an incomplete history error, got %v", err)
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"fmt"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// MaxTransactionSize is the largest serialized transaction, signatures
+// included, that a validator accepts.
+const MaxTransactionSize = 1232
+
+// SweepClient is the getTokenAccountsByOwner call used by
+// SweepEmptyAccounts. *rpc.Client satisfies it.
+type SweepClient interface {
+	GetTokenAccountsByOwner(ctx context.Context, owner solana.PublicKey, conf *rpc.GetTokenAccountsConfig, opts *rpc.GetTokenAccountsOpts) (*rpc.GetTokenAccountsResult, error)
+}
+
+var _ SweepClient = (*rpc.Client)(nil)
+
+// SkippedAccount is an empty account that SweepEmptyAccounts cannot close.
+type SkippedAccount struct {
+	Account solana.PublicKey
+	Reason  string
+}
+
+// SweepPlan closes the empty associated token accounts of an owner.
+type SweepPlan struct {
+	// Transactions holds the instructions of each transaction, paid for
+	// and signed by the owner. Each fits in MaxTransactionSize.
+	Transactions [][]solana.Instruction
+	// Closed lists the accounts closed by Transactions, in order.
+	Closed []solana.PublicKey
+	// Skipped lists the empty accounts that need another step first.
+	Skipped []SkippedAccount
+	// Lamports is the rent returned to the owner.
+	Lamports uint64
+}
+
+// SweepEmptyAccounts lists the Token-2022 accounts of owner at commitment
+// and plans closing every associated token account that holds no tokens,
+// returning its rent to owner. Accounts with a TransferFeeAmount extension
+// are harvested to their mint first, as the program refuses to close an
+// account holding withheld fees.
+//
+// Frozen accounts, accounts whose close authority is not owner and
+// accounts with a ConfidentialTransferAccount extension, whose confidential
+// balances must be emptied with a zero-knowledge proof first, are reported
+// in Skipped. Accounts that are not associated token accounts are left
+// alone.
+func SweepEmptyAccounts(ctx context.Context, client SweepClient, owner solana.PublicKey, commitment rpc.CommitmentType) (*SweepPlan, error) {
+	out, err := client.GetTokenAccountsByOwner(ctx, owner,
+		&rpc.GetTokenAccountsConfig{ProgramId: solana.Token2022ProgramID.ToPointer()},
+		&rpc.GetTokenAccountsOpts{Commitment: commitment, Encoding: solana.EncodingBase64},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error while listing token accounts: %w", err)
+	}
+	plan := &SweepPlan{}
+	var batch []solana.Instruction
+	for _, keyed := range out.Value {
+		account, err := DecodeTokenAccount(keyed.Account.Data.GetBinary())
+		if err != nil || account.Amount > 0 || !account.Owner.Equals(owner) {
+			continue
+		}
+		address, _, err := FindAssociatedTokenAddress2022(owner, account.Mint)
+		if err != nil || !address.Equals(keyed.Pubkey) {
+			continue
+		}
+		if reason := sweepBlocker(account, owner); reason != "" {
+			plan.Skipped = append(plan.Skipped, SkippedAccount{Account: keyed.Pubkey, Reason: reason})
+			continue
+		}
+		var steps []solana.Instruction
+		if _, ok := account.Extension(ExtensionTransferFeeAmount); ok {
+			steps = append(steps, NewHarvestWithheldTokensToMint2022Instruction(account.Mint, keyed.Pubkey).Build())
+		}
+		steps = append(steps, NewClose2022Instruction(keyed.Pubkey, owner, owner).Build())
+
+		size, err := transactionSize(owner, append(batch[:len(batch):len(batch)], steps...), nil)
+		if err != nil {
+			return nil, err
+		}
+		if size > MaxTransactionSize && len(batch) > 0 {
+			plan.Transactions = append(plan.Transactions, batch)
+			batch = nil
+		}
+		batch = append(batch, steps...)
+		plan.Closed = append(plan.Closed, keyed.Pubkey)
+		plan.Lamports += keyed.Account.Lamports
+	}
+	if len(batch) > 0 {
+		plan.Transactions = append(plan.Transactions, batch)
+	}
+	return plan, nil
+}
+
+// sweepBlocker returns why an empty account cannot be closed by owner
+// right away, or "" when it can.
+func sweepBlocker(account *TokenAccount, owner solana.PublicKey) string {
+	switch {
+	case account.IsFrozen():
+		return "account is frozen"
+	case account.CloseAuthority != nil && !account.CloseAuthority.Equals(owner):
+		return fmt.Sprintf("account is closed by %s", *account.CloseAuthority)
+	}
+	if _, ok := account.Extension(ExtensionConfidentialTransferAccount); ok {
+		return "confidential balances must be emptied with a proof first"
+	}
+	return ""
+}
+
+// transactionSize returns the serialized size of a transaction of
+// instructions paid for by feePayer, with all of its signatures, once
+// compressed with the address lookup tables, if any.
+func transactionSize(feePayer solana.PublicKey, instructions []solana.Instruction, tables map[solana.PublicKey]solana.PublicKeySlice) (int, error) {
+	opts := []solana.TransactionOption{solana.TransactionPayer(feePayer)}
+	if len(tables) > 0 {
+		opts = append(opts, solana.TransactionAddressTables(tables))
+	}
+	tx, err := solana.NewTransaction(instructions, solana.Hash{}, opts...)
+	if err != nil {
+		return 0, fmt.Errorf("error while creating transaction: %w", err)
+	}
+	message, err := tx.Message.MarshalBinary()
+	if err != nil {
+		return 0, fmt.Errorf("error while encoding message: %w", err)
+	}
+	signatures := int(tx.Message.Header.NumRequiredSignatures)
+	prefix := 1
+	if signatures >= 0x80 {
+		prefix = 2
+	}
+	return prefix + signatures*64 + len(message), nil
+}
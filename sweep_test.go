@@ -0,0 +1,73 @@
+package token2022
+
+import (
+	"context"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc"
+)
+
+func TestSweepEmptyAccounts(t *testing.T) {
+	var (
+		wallet = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		other  = solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR")
+		client = newMockRPC()
+	)
+	ata := func(mint solana.PublicKey) solana.PublicKey {
+		address, _, err := FindAssociatedTokenAddress2022(wallet, mint)
+		if err != nil {
+			t.Fatalf("FindAssociatedTokenAddress2022: %v", err)
+		}
+		return address
+	}
+	const empty = 40
+	for i := 0; i < empty; i++ {
+		mint := solana.NewWallet().PublicKey()
+		client.setAccount(ata(mint), solana.Token2022ProgramID, encodeTokenAccount(mint, wallet, 0,
+			Extension{Type: ExtensionTransferFeeAmount, Data: make([]byte, 8)}))
+	}
+	held := solana.NewWallet().PublicKey()
+	client.setAccount(ata(held), solana.Token2022ProgramID, encodeTokenAccount(held, wallet, 5))
+	notAssociated := solana.NewWallet().PublicKey()
+	client.setAccount(notAssociated, solana.Token2022ProgramID, encodeTokenAccount(held, wallet, 0))
+	frozenMint := solana.NewWallet().PublicKey()
+	frozen := encodeTokenAccount(frozenMint, wallet, 0)
+	frozen[108] = byte(AccountStateFrozen)
+	client.setAccount(ata(frozenMint), solana.Token2022ProgramID, frozen)
+	delegatedMint := solana.NewWallet().PublicKey()
+	client.setAccount(ata(delegatedMint), solana.Token2022ProgramID, EncodeTokenAccount(&TokenAccount{
+		Mint: delegatedMint, Owner: wallet, State: AccountStateInitialized, CloseAuthority: &other,
+	}))
+
+	plan, err := SweepEmptyAccounts(context.Background(), client, wallet, rpc.CommitmentConfirmed)
+	if err != nil {
+		t.Fatalf("SweepEmptyAccounts: %v", err)
+	}
+	if len(plan.Closed) != empty {
+		t.Fatalf("Expected %d closed accounts, got %d", empty, len(plan.Closed))
+	}
+	if len(plan.Skipped) != 2 {
+		t.Errorf("Expected the frozen and delegated accounts to be skipped, got %v", plan.Skipped)
+	}
+	if plan.Lamports != empty*1_000_000 {
+		t.Errorf("Expected %d lamports, got %d", empty*1_000_000, plan.Lamports)
+	}
+	if len(plan.Transactions) < 2 {
+		t.Fatalf("Expected the closes to span several transactions, got %d", len(plan.Transactions))
+	}
+	instructions := 0
+	for i, batch := range plan.Transactions {
+		size, err := transactionSize(wallet, batch, nil)
+		if err != nil {
+			t.Fatalf("transactionSize: %v", err)
+		}
+		if size > MaxTransactionSize {
+			t.Errorf("Expected transaction %d to fit, got %d bytes", i, size)
+		}
+		instructions += len(batch)
+	}
+	if instructions != 2*empty {
+		t.Errorf("Expected a harvest and a close per account, got %d instructions", instructions)
+	}
+}
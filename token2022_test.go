@@ -1,6 +1,8 @@
 package token2022
 
 import (
+	"bytes"
+	"errors"
 	"testing"
 
 	solana "github.com/gagliardetto/solana-go"
@@ -49,3 +51,150 @@ func TestFindAssociatedTokenAddress2022(t *testing.T) {
 		t.Errorf("Expected address %s, got %s", expectedAddress, address)
 	}
 }
+
+func TestPlanAssociatedTokenAccount(t *testing.T) {
+	var (
+		owner     = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint      = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		authority = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+	)
+	address, _, _ := FindAssociatedTokenAddress2022(owner, mint)
+	decoded := &Mint{Decimals: 6, IsInitialized: true, FreezeAuthority: &authority}
+
+	plan, err := PlanAssociatedTokenAccount(mint, decoded, owner, owner, false)
+	if err != nil {
+		t.Fatalf("PlanAssociatedTokenAccount: %v", err)
+	}
+	if plan.Address != address || len(plan.Instructions) != 1 || plan.Frozen || plan.NeedsThawBy != nil {
+		t.Errorf("Expected a plain create of %s, got %+v", address, plan)
+	}
+
+	decoded.Extensions = []Extension{{Type: ExtensionDefaultAccountState, Data: []byte{byte(AccountStateFrozen)}}}
+	plan, err = PlanAssociatedTokenAccount(mint, decoded, owner, owner, false)
+	if err != nil {
+		t.Fatalf("PlanAssociatedTokenAccount: %v", err)
+	}
+	if !plan.Frozen || plan.Thawed || plan.NeedsThawBy == nil || *plan.NeedsThawBy != authority || len(plan.Instructions) != 1 {
+		t.Errorf("Expected a thaw by %s to be needed, got %+v", authority, plan)
+	}
+
+	plan, err = PlanAssociatedTokenAccount(mint, decoded, owner, owner, false, owner, authority)
+	if err != nil {
+		t.Fatalf("PlanAssociatedTokenAccount: %v", err)
+	}
+	if !plan.Frozen || !plan.Thawed || plan.NeedsThawBy != nil || len(plan.Instructions) != 2 {
+		t.Fatalf("Expected create and thaw, got %+v", plan)
+	}
+	data, _ := plan.Instructions[1].Data()
+	if name := InstructionName(data); name != "ThawAccount" {
+		t.Errorf("Expected ThawAccount, got %s", name)
+	}
+	if accounts := plan.Instructions[1].Accounts(); accounts[0].PublicKey != address || accounts[2].PublicKey != authority {
+		t.Errorf("Unexpected thaw accounts %+v", accounts)
+	}
+
+	decoded.FreezeAuthority = nil
+	plan, err = PlanAssociatedTokenAccount(mint, decoded, owner, owner, false, authority)
+	if err != nil {
+		t.Fatalf("PlanAssociatedTokenAccount: %v", err)
+	}
+	if !plan.Frozen || plan.Thawed || plan.NeedsThawBy != nil {
+		t.Errorf("Expected a permanently frozen account, got %+v", plan)
+	}
+}
+
+func TestValidateOwner(t *testing.T) {
+	var (
+		wallet = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint   = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	)
+	pda, _, _ := FindAssociatedTokenAddress2022(wallet, mint)
+
+	if !IsOnCurve(wallet) || IsOnCurve(pda) {
+		t.Fatalf("Expected %s on the curve and %s off it", wallet, pda)
+	}
+	if err := ValidateOwner(wallet, false); err != nil {
+		t.Errorf("Expected a wallet owner to validate, got %v", err)
+	}
+	if err := ValidateOwner(pda, false); !errors.Is(err, ErrOwnerOffCurve) {
+		t.Errorf("Expected ErrOwnerOffCurve, got %v", err)
+	}
+	if err := ValidateOwner(pda, true); err != nil {
+		t.Errorf("Expected an allowed PDA owner to validate, got %v", err)
+	}
+
+	if _, _, err := FindAssociatedTokenAddress2022Checked(pda, mint, false); !errors.Is(err, ErrOwnerOffCurve) {
+		t.Errorf("Expected ErrOwnerOffCurve, got %v", err)
+	}
+	if _, _, err := FindAssociatedTokenAddress2022Checked(pda, mint, true); err != nil {
+		t.Errorf("Expected the PDA owner's address, got %v", err)
+	}
+	if err := NewCreate2022Instruction(wallet, pda, mint).Validate(); !errors.Is(err, ErrOwnerOffCurve) {
+		t.Errorf("Expected ErrOwnerOffCurve, got %v", err)
+	}
+	if err := NewCreate2022Instruction(wallet, pda, mint).SetAllowOwnerOffCurve(true).Validate(); err != nil {
+		t.Errorf("Expected the PDA owner to be allowed, got %v", err)
+	}
+	if _, err := PlanAssociatedTokenAccount(mint, &Mint{Decimals: 6, IsInitialized: true}, wallet, pda, false); !errors.Is(err, ErrOwnerOffCurve) {
+		t.Errorf("Expected ErrOwnerOffCurve, got %v", err)
+	}
+}
+
+func TestWithProgramID(t *testing.T) {
+	var (
+		wallet      = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+	)
+
+	transfer := NewTransferChecked2022Instruction(5, 6, source, mint, destination, wallet).Build()
+	want, _ := transfer.Data()
+	transfer.WithProgramID(solana.TokenProgramID)
+	if transfer.ProgramID() != solana.TokenProgramID {
+		t.Errorf("Expected program %s, got %s", solana.TokenProgramID, transfer.ProgramID())
+	}
+	if got, _ := transfer.Data(); !bytes.Equal(got, want) {
+		t.Errorf("Expected data %v, got %v", want, got)
+	}
+	if transfer.WithProgramID(solana.PublicKey{}).ProgramID() != solana.Token2022ProgramID {
+		t.Errorf("Expected the zero key to restore Token-2022")
+	}
+
+	legacy, _, err := solana.FindAssociatedTokenAddress(wallet, mint)
+	if err != nil {
+		t.Fatalf("FindAssociatedTokenAddress: %v", err)
+	}
+	if address, _, _ := FindAssociatedTokenAddress(wallet, mint, solana.TokenProgramID); address != legacy {
+		t.Errorf("Expected address %s, got %s", legacy, address)
+	}
+	create := NewCreate2022Instruction(wallet, wallet, mint).Build().WithProgramID(solana.TokenProgramID)
+	if create.ProgramID() != ProgramID {
+		t.Errorf("Expected program %s, got %s", ProgramID, create.ProgramID())
+	}
+	if accounts := create.Accounts(); accounts[1].PublicKey != legacy || accounts[5].PublicKey != solana.TokenProgramID {
+		t.Errorf("Expected the legacy account and program, got %s and %s", accounts[1].PublicKey, accounts[5].PublicKey)
+	}
+}
+
+func TestSetAuthorityIrreversible(t *testing.T) {
+	var (
+		wallet = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint   = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	)
+	for _, authorityType := range []AuthorityType{AuthorityMintTokens, AuthorityFreezeAccount, AuthorityCloseMint} {
+		inst := NewSetAuthority2022Instruction(authorityType, nil, mint, wallet)
+		if _, err := inst.ValidateAndBuild(); !errors.Is(err, ErrIrreversible) {
+			t.Errorf("%s: expected ErrIrreversible, got %v", authorityType, err)
+		}
+		if _, err := inst.ConfirmIrreversible().ValidateAndBuild(); err != nil {
+			t.Errorf("%s: expected a confirmed removal to validate, got %v", authorityType, err)
+		}
+		if err := NewSetAuthority2022Instruction(authorityType, &wallet, mint, wallet).Validate(); err != nil {
+			t.Errorf("%s: expected a new authority to validate, got %v", authorityType, err)
+		}
+	}
+	if err := NewSetAuthority2022Instruction(AuthorityCloseAccount, nil, mint, wallet).Validate(); err != nil {
+		t.Errorf("Expected removing a close authority to validate, got %v", err)
+	}
+}
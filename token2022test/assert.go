@@ -0,0 +1,41 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022test
+
+import (
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+
+	token2022 "github.com/dwmfan/token2022"
+)
+
+// AssertInstructionEqual fails the test with a readable diff when got
+// differs from want in program ID, ordered account metas or data.
+func AssertInstructionEqual(tb testing.TB, want, got solana.Instruction) {
+	tb.Helper()
+	if diff := token2022.DiffInstruction(want, got); diff != "" {
+		tb.Errorf("Instruction mismatch:\n%s", diff)
+	}
+}
+
+// AssertInstructionsEqual is AssertInstructionEqual for instruction lists,
+// such as the output of a transaction planner.
+func AssertInstructionsEqual(tb testing.TB, want, got []solana.Instruction) {
+	tb.Helper()
+	if diff := token2022.DiffInstructions(want, got); diff != "" {
+		tb.Errorf("Instructions mismatch:\n%s", diff)
+	}
+}
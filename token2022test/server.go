@@ -0,0 +1,464 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package token2022test provides an in-process Solana JSON-RPC server for
+// unit tests of code built on token2022. It serves canned accounts,
+// blockhashes and block heights and records the transactions sent to it,
+// so client flows can be tested without a validator. For end-to-end tests,
+// StartValidator runs solana-test-validator and creates funded keypairs,
+// mints and token accounts.
+package token2022test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	bin "github.com/gagliardetto/binary"
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+
+	token2022 "github.com/dwmfan/token2022"
+)
+
+// JSON-RPC error codes returned by the server.
+const (
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeMinContextSlot = -32016
+)
+
+// Account is an account served by the server.
+type Account struct {
+	Lamports   uint64
+	Owner      solana.PublicKey
+	Data       []byte
+	Executable bool
+}
+
+// SendHook is called for every transaction received by sendTransaction.
+// Returning an error fails the call; a *jsonrpc.RPCError is returned to
+// the client as is.
+type SendHook func(tx *solana.Transaction) error
+
+// Server is an in-process JSON-RPC server. It is safe for concurrent use.
+type Server struct {
+	server *httptest.Server
+
+	mu                   sync.Mutex
+	slot                 uint64
+	epoch                uint64
+	blockHeight          uint64
+	blockhash            solana.Hash
+	lastValidBlockHeight uint64
+	accounts             map[solana.PublicKey]*Account
+	statuses             map[solana.Signature]*rpc.SignatureStatusesResult
+	transactions         []*solana.Transaction
+	calls                map[string]int
+	autoConfirm          rpc.ConfirmationStatusType
+	sendHook             SendHook
+}
+
+// NewServer starts a server at slot 100 and block height 90 whose sent
+// transactions are confirmed immediately. It is closed when the test ends.
+func NewServer(tb testing.TB) *Server {
+	s := &Server{
+		slot:                 100,
+		blockHeight:          90,
+		blockhash:            solana.Hash{1},
+		lastValidBlockHeight: 240,
+		accounts:             map[solana.PublicKey]*Account{},
+		statuses:             map[solana.Signature]*rpc.SignatureStatusesResult{},
+		calls:                map[string]int{},
+		autoConfirm:          rpc.ConfirmationStatusConfirmed,
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	tb.Cleanup(s.Close)
+	return s
+}
+
+// URL returns the RPC endpoint.
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Client returns an RPC client for the server.
+func (s *Server) Client() *rpc.Client {
+	return rpc.New(s.server.URL)
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+func (s *Server) SetSlot(slot uint64) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.slot = slot
+	return s
+}
+
+func (s *Server) SetEpoch(epoch uint64) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.epoch = epoch
+	return s
+}
+
+func (s *Server) SetBlockHeight(height uint64) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blockHeight = height
+	return s
+}
+
+// SetBlockhash sets the blockhash returned by getLatestBlockhash.
+func (s *Server) SetBlockhash(blockhash solana.Hash, lastValidBlockHeight uint64) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blockhash = blockhash
+	s.lastValidBlockHeight = lastValidBlockHeight
+	return s
+}
+
+// SetAutoConfirm sets the status given to sent transactions. An empty
+// status leaves them unconfirmed until SetStatus is called.
+func (s *Server) SetAutoConfirm(status rpc.ConfirmationStatusType) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.autoConfirm = status
+	return s
+}
+
+func (s *Server) SetSendHook(hook SendHook) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sendHook = hook
+	return s
+}
+
+// SetAccount serves an account. A nil account removes it.
+func (s *Server) SetAccount(pubkey solana.PublicKey, account *Account) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if account == nil {
+		delete(s.accounts, pubkey)
+	} else {
+		s.accounts[pubkey] = account
+	}
+	return s
+}
+
+// SetMint serves a Token-2022 mint with rent-exempt lamports.
+func (s *Server) SetMint(pubkey solana.PublicKey, mint *token2022.Mint) *Server {
+	return s.setTokenAccount(pubkey, token2022.EncodeMint(mint))
+}
+
+// SetTokenAccount serves a Token-2022 token account with rent-exempt
+// lamports.
+func (s *Server) SetTokenAccount(pubkey solana.PublicKey, account *token2022.TokenAccount) *Server {
+	return s.setTokenAccount(pubkey, token2022.EncodeTokenAccount(account))
+}
+
+func (s *Server) setTokenAccount(pubkey solana.PublicKey, data []byte) *Server {
+	return s.SetAccount(pubkey, &Account{
+		Lamports: RentExemptLamports(len(data)),
+		Owner:    solana.Token2022ProgramID,
+		Data:     data,
+	})
+}
+
+// SetStatus sets the signature status of a transaction. A nil status
+// makes it unknown.
+func (s *Server) SetStatus(sig solana.Signature, status *rpc.SignatureStatusesResult) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if status == nil {
+		delete(s.statuses, sig)
+	} else {
+		s.statuses[sig] = status
+	}
+	return s
+}
+
+// Transactions returns the transactions received by sendTransaction, in
+// order, including rebroadcasts.
+func (s *Server) Transactions() []*solana.Transaction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*solana.Transaction(nil), s.transactions...)
+}
+
+// Calls returns the number of calls to an RPC method.
+func (s *Server) Calls(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method]
+}
+
+// RentExemptLamports is the rent-exempt minimum the server uses for an
+// account of dataLen bytes.
+func RentExemptLamports(dataLen int) uint64 {
+	return token2022.RentExemptLamports(dataLen)
+}
+
+type request struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+type response struct {
+	JSONRPC string            `json:"jsonrpc"`
+	ID      json.RawMessage   `json:"id"`
+	Result  interface{}       `json:"result,omitempty"`
+	Error   *jsonrpc.RPCError `json:"error,omitempty"`
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	var req request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	result, rpcErr := s.handle(req.Method, req.Params)
+	resp := response{JSONRPC: "2.0", ID: req.ID, Result: result, Error: rpcErr}
+	if rpcErr == nil && result == nil {
+		resp.Result = json.RawMessage("null")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (s *Server) handle(method string, params []json.RawMessage) (interface{}, *jsonrpc.RPCError) {
+	s.mu.Lock()
+	s.calls[method]++
+	s.mu.Unlock()
+
+	switch method {
+	case "getHealth":
+		return "ok", nil
+	case "getSlot":
+		return s.locked(func() interface{} { return s.slot }), nil
+	case "getEpochInfo":
+		return s.locked(func() interface{} {
+			return map[string]interface{}{
+				"absoluteSlot": s.slot,
+				"blockHeight":  s.blockHeight,
+				"epoch":        s.epoch,
+				"slotIndex":    s.slot % 432_000,
+				"slotsInEpoch": 432_000,
+			}
+		}), nil
+	case "getBlockHeight":
+		return s.locked(func() interface{} { return s.blockHeight }), nil
+	case "getLatestBlockhash":
+		return s.locked(func() interface{} {
+			return s.withContext(map[string]interface{}{
+				"blockhash":            s.blockhash.String(),
+				"lastValidBlockHeight": s.lastValidBlockHeight,
+			})
+		}), nil
+	case "getMinimumBalanceForRentExemption":
+		var dataLen int
+		if err := param(params, 0, &dataLen); err != nil {
+			return nil, err
+		}
+		return RentExemptLamports(dataLen), nil
+	case "getBalance":
+		var pubkey solana.PublicKey
+		if err := param(params, 0, &pubkey); err != nil {
+			return nil, err
+		}
+		return s.locked(func() interface{} {
+			var lamports uint64
+			if account, ok := s.accounts[pubkey]; ok {
+				lamports = account.Lamports
+			}
+			return s.withContext(lamports)
+		}), nil
+	case "getAccountInfo":
+		var pubkey solana.PublicKey
+		if err := param(params, 0, &pubkey); err != nil {
+			return nil, err
+		}
+		if err := s.checkMinContextSlot(params, 1); err != nil {
+			return nil, err
+		}
+		return s.locked(func() interface{} { return s.withContext(s.encodeAccount(pubkey)) }), nil
+	case "getMultipleAccounts":
+		var pubkeys []solana.PublicKey
+		if err := param(params, 0, &pubkeys); err != nil {
+			return nil, err
+		}
+		if err := s.checkMinContextSlot(params, 1); err != nil {
+			return nil, err
+		}
+		return s.locked(func() interface{} {
+			values := make([]interface{}, len(pubkeys))
+			for i, pubkey := range pubkeys {
+				values[i] = s.encodeAccount(pubkey)
+			}
+			return s.withContext(values)
+		}), nil
+	case "sendTransaction":
+		tx, err := decodeTransaction(params)
+		if err != nil {
+			return nil, err
+		}
+		return s.send(tx)
+	case "simulateTransaction":
+		if _, err := decodeTransaction(params); err != nil {
+			return nil, err
+		}
+		return s.locked(func() interface{} {
+			return s.withContext(map[string]interface{}{
+				"err":           nil,
+				"logs":          []string{},
+				"accounts":      nil,
+				"unitsConsumed": 0,
+			})
+		}), nil
+	case "getSignatureStatuses":
+		var sigs []solana.Signature
+		if err := param(params, 0, &sigs); err != nil {
+			return nil, err
+		}
+		return s.locked(func() interface{} {
+			values := make([]*rpc.SignatureStatusesResult, len(sigs))
+			for i, sig := range sigs {
+				values[i] = s.statuses[sig]
+			}
+			return s.withContext(values)
+		}), nil
+	}
+	return nil, &jsonrpc.RPCError{Code: codeMethodNotFound, Message: fmt.Sprintf("Method not found: %s", method)}
+}
+
+func (s *Server) send(tx *solana.Transaction) (interface{}, *jsonrpc.RPCError) {
+	s.mu.Lock()
+	hook := s.sendHook
+	s.mu.Unlock()
+	if hook != nil {
+		// The hook runs unlocked so it can call the server's setters.
+		if err := hook(tx); err != nil {
+			if rpcErr, ok := err.(*jsonrpc.RPCError); ok {
+				return nil, rpcErr
+			}
+			return nil, &jsonrpc.RPCError{Code: -32002, Message: err.Error()}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.transactions = append(s.transactions, tx)
+	sig := tx.Signatures[0]
+	if _, ok := s.statuses[sig]; !ok && s.autoConfirm != "" {
+		s.statuses[sig] = &rpc.SignatureStatusesResult{
+			Slot:               s.slot,
+			ConfirmationStatus: s.autoConfirm,
+		}
+	}
+	return sig.String(), nil
+}
+
+// locked runs fn with the server locked.
+func (s *Server) locked(fn func() interface{}) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fn()
+}
+
+func (s *Server) withContext(value interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"context": map[string]interface{}{"slot": s.slot},
+		"value":   value,
+	}
+}
+
+// encodeAccount returns the base64 JSON form of an account, or nil when
+// the server has no such account.
+func (s *Server) encodeAccount(pubkey solana.PublicKey) interface{} {
+	account, ok := s.accounts[pubkey]
+	if !ok {
+		return nil
+	}
+	return map[string]interface{}{
+		"lamports":   account.Lamports,
+		"owner":      account.Owner.String(),
+		"data":       []string{base64.StdEncoding.EncodeToString(account.Data), "base64"},
+		"executable": account.Executable,
+		"rentEpoch":  0,
+		"space":      len(account.Data),
+	}
+}
+
+func (s *Server) checkMinContextSlot(params []json.RawMessage, index int) *jsonrpc.RPCError {
+	var opts struct {
+		MinContextSlot *uint64 `json:"minContextSlot"`
+	}
+	if index >= len(params) {
+		return nil
+	}
+	if err := json.Unmarshal(params[index], &opts); err != nil {
+		return &jsonrpc.RPCError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if opts.MinContextSlot != nil && *opts.MinContextSlot > s.slot {
+		return &jsonrpc.RPCError{
+			Code:    codeMinContextSlot,
+			Message: "Minimum context slot has not been reached",
+			Data:    map[string]interface{}{"contextSlot": s.slot},
+		}
+	}
+	return nil
+}
+
+func param(params []json.RawMessage, index int, v interface{}) *jsonrpc.RPCError {
+	if index >= len(params) {
+		return &jsonrpc.RPCError{Code: codeInvalidParams, Message: fmt.Sprintf("missing parameter %d", index)}
+	}
+	if err := json.Unmarshal(params[index], v); err != nil {
+		return &jsonrpc.RPCError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+// decodeTransaction decodes the base64 transaction of sendTransaction and
+// simulateTransaction.
+func decodeTransaction(params []json.RawMessage) (*solana.Transaction, *jsonrpc.RPCError) {
+	var encoded string
+	if err := param(params, 0, &encoded); err != nil {
+		return nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, &jsonrpc.RPCError{Code: codeInvalidParams, Message: "transaction must be base64 encoded: " + err.Error()}
+	}
+	tx, err := solana.TransactionFromDecoder(bin.NewBinDecoder(data))
+	if err != nil {
+		return nil, &jsonrpc.RPCError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	if len(tx.Signatures) == 0 {
+		return nil, &jsonrpc.RPCError{Code: codeInvalidParams, Message: "transaction has no signatures"}
+	}
+	return tx, nil
+}
@@ -0,0 +1,170 @@
+package token2022test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+
+	token2022 "github.com/dwmfan/token2022"
+)
+
+func TestServerFetch(t *testing.T) {
+
+	var (
+		mint      = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		account   = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		wallet    = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		authority = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		server    = NewServer(t)
+		client    = server.Client()
+		ctx       = context.Background()
+	)
+
+	server.SetMint(mint, &token2022.Mint{
+		MintAuthority: &authority,
+		Supply:        1000,
+		Decimals:      6,
+		IsInitialized: true,
+	})
+	server.SetTokenAccount(account, &token2022.TokenAccount{
+		Mint:   mint,
+		Owner:  wallet,
+		Amount: 250,
+		State:  token2022.AccountStateInitialized,
+	})
+
+	gotMint, err := token2022.FetchMint(ctx, client, mint, rpc.CommitmentConfirmed)
+	if err != nil {
+		t.Fatalf("Error fetching mint: %v", err)
+	}
+	if gotMint.Supply != 1000 || gotMint.Decimals != 6 || !gotMint.MintAuthority.Equals(authority) {
+		t.Errorf("Unexpected mint %+v", gotMint)
+	}
+
+	gotAccount, err := token2022.FetchTokenAccount(ctx, client, account, rpc.CommitmentConfirmed)
+	if err != nil {
+		t.Fatalf("Error fetching token account: %v", err)
+	}
+	if !gotAccount.Owner.Equals(wallet) || gotAccount.Amount != 250 {
+		t.Errorf("Unexpected token account %+v", gotAccount)
+	}
+
+	if _, err := token2022.FetchMint(ctx, client, wallet, rpc.CommitmentConfirmed); err == nil {
+		t.Errorf("Expected error fetching a missing mint")
+	}
+
+	_, err = token2022.FetchMintWithOpts(ctx, client, mint, token2022.FetchOptsAtSlot(rpc.CommitmentConfirmed, 101))
+	var rpcErr *jsonrpc.RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != codeMinContextSlot {
+		t.Errorf("Expected min context slot error, got %v", err)
+	}
+
+	balance, err := client.GetBalance(ctx, mint, rpc.CommitmentConfirmed)
+	if err != nil {
+		t.Fatalf("Error getting balance: %v", err)
+	}
+	if balance.Value != RentExemptLamports(len(token2022.EncodeMint(gotMint))) {
+		t.Errorf("Expected rent-exempt balance, got %d", balance.Value)
+	}
+}
+
+func TestServerSend(t *testing.T) {
+
+	var (
+		owner  = solana.NewWallet().PrivateKey
+		mint   = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		server = NewServer(t)
+		client = server.Client()
+	)
+
+	builder := token2022.NewTxBuilder(client).
+		SetFeePayer(owner.PublicKey()).
+		AddInstruction(token2022.NewCreate2022Instruction(owner.PublicKey(), owner.PublicKey(), mint).Build()).
+		AddSigner(token2022.NewPrivateKeySigner(owner))
+
+	result, err := token2022.NewSender(client).
+		SetPollInterval(time.Millisecond).
+		Send(context.Background(), builder)
+	if err != nil {
+		t.Fatalf("Error sending: %v", err)
+	}
+
+	sent := server.Transactions()
+	if len(sent) != 1 {
+		t.Fatalf("Expected 1 transaction, got %d", len(sent))
+	}
+	if result.Signature != sent[0].Signatures[0] {
+		t.Errorf("Expected signature of the sent transaction")
+	}
+	if sent[0].Message.RecentBlockhash != (solana.Hash{1}) {
+		t.Errorf("Expected the served blockhash, got %s", sent[0].Message.RecentBlockhash)
+	}
+	if err := sent[0].VerifySignatures(); err != nil {
+		t.Errorf("Error verifying signatures: %v", err)
+	}
+	if server.Calls("sendTransaction") != 1 {
+		t.Errorf("Expected 1 sendTransaction call, got %d", server.Calls("sendTransaction"))
+	}
+}
+
+func TestServerSendHook(t *testing.T) {
+
+	var (
+		owner  = solana.NewWallet().PrivateKey
+		mint   = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		server = NewServer(t)
+		client = server.Client()
+	)
+
+	server.SetSendHook(func(tx *solana.Transaction) error {
+		return &jsonrpc.RPCError{Code: -32002, Message: "Transaction simulation failed"}
+	})
+
+	tx, err := token2022.NewTxBuilder(client).
+		SetFeePayer(owner.PublicKey()).
+		AddInstruction(token2022.NewCreate2022Instruction(owner.PublicKey(), owner.PublicKey(), mint).Build()).
+		AddSigner(token2022.NewPrivateKeySigner(owner)).
+		BuildAndSign(context.Background())
+	if err != nil {
+		t.Fatalf("Error building transaction: %v", err)
+	}
+
+	_, err = client.SendTransaction(context.Background(), tx)
+	var rpcErr *jsonrpc.RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -32002 {
+		t.Errorf("Expected simulation error, got %v", err)
+	}
+	if len(server.Transactions()) != 0 {
+		t.Errorf("Expected rejected transaction not to be recorded")
+	}
+}
+
+func TestServerUnknownMethod(t *testing.T) {
+
+	var (
+		server = NewServer(t)
+		client = server.Client()
+	)
+
+	_, err := client.GetVersion(context.Background())
+	var rpcErr *jsonrpc.RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != codeMethodNotFound {
+		t.Errorf("Expected method not found error, got %v", err)
+	}
+}
+
+func TestServerEpochInfo(t *testing.T) {
+	server := NewServer(t).SetEpoch(7)
+	info, err := server.Client().GetEpochInfo(context.Background(), rpc.CommitmentConfirmed)
+	if err != nil {
+		t.Fatalf("GetEpochInfo: %v", err)
+	}
+	if info.Epoch != 7 || info.AbsoluteSlot != 100 {
+		t.Errorf("Expected epoch 7 at slot 100, got epoch %d at slot %d", info.Epoch, info.AbsoluteSlot)
+	}
+}
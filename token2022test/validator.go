@@ -0,0 +1,303 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"testing"
+	"time"
+
+	solana "github.com/gagliardetto/solana-go"
+	system "github.com/gagliardetto/solana-go/programs/system"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+
+	token2022 "github.com/dwmfan/token2022"
+)
+
+// ValidatorURLEnv names the environment variable holding the RPC URL of an
+// existing validator. When it is unset, StartValidator runs
+// solana-test-validator from PATH.
+const ValidatorURLEnv = "TOKEN2022_TEST_VALIDATOR_URL"
+
+const validatorStartTimeout = 60 * time.Second
+
+// Validator is a local validator used by integration tests. Its helpers
+// fail the test on error.
+type Validator struct {
+	tb     testing.TB
+	url    string
+	client *rpc.Client
+}
+
+// StartValidator connects to the validator named by ValidatorURLEnv or
+// starts solana-test-validator with a fresh ledger, stopping it when the
+// test ends. The test is skipped when neither is available, and in
+// -short mode.
+func StartValidator(tb testing.TB) *Validator {
+	tb.Helper()
+	if testing.Short() {
+		tb.Skip("skipping validator test in short mode")
+	}
+
+	url := os.Getenv(ValidatorURLEnv)
+	if url == "" {
+		url = runValidator(tb)
+	}
+	v := &Validator{tb: tb, url: url, client: rpc.New(url)}
+	v.waitHealthy()
+	return v
+}
+
+func runValidator(tb testing.TB) string {
+	tb.Helper()
+	path, err := exec.LookPath("solana-test-validator")
+	if err != nil {
+		tb.Skipf("solana-test-validator not found and %s not set", ValidatorURLEnv)
+	}
+
+	rpcPort := freePort(tb)
+	cmd := exec.Command(path,
+		"--reset",
+		"--quiet",
+		"--ledger", tb.TempDir(),
+		"--rpc-port", strconv.Itoa(rpcPort),
+		"--faucet-port", strconv.Itoa(freePort(tb)),
+	)
+	if err := cmd.Start(); err != nil {
+		tb.Fatalf("Error starting solana-test-validator: %v", err)
+	}
+	tb.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+	return fmt.Sprintf("http://127.0.0.1:%d", rpcPort)
+}
+
+func freePort(tb testing.TB) int {
+	tb.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		tb.Fatalf("Error finding a free port: %v", err)
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func (v *Validator) waitHealthy() {
+	v.tb.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), validatorStartTimeout)
+	defer cancel()
+	for {
+		if _, err := v.client.GetHealth(ctx); err == nil {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			v.tb.Fatalf("Validator at %s not healthy after %s", v.url, validatorStartTimeout)
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+// URL returns the RPC endpoint.
+func (v *Validator) URL() string {
+	return v.url
+}
+
+// Client returns an RPC client for the validator.
+func (v *Validator) Client() *rpc.Client {
+	return v.client
+}
+
+// Fund airdrops lamports to an account and waits for confirmation.
+func (v *Validator) Fund(pubkey solana.PublicKey, lamports uint64) {
+	v.tb.Helper()
+	ctx := context.Background()
+	sig, err := v.client.RequestAirdrop(ctx, pubkey, lamports, rpc.CommitmentConfirmed)
+	if err != nil {
+		v.tb.Fatalf("Error requesting airdrop: %v", err)
+	}
+	if _, err := token2022.NewConfirmationTracker(v.client).
+		SetPollInterval(200*time.Millisecond).
+		Wait(ctx, sig, rpc.CommitmentConfirmed); err != nil {
+		v.tb.Fatalf("Error confirming airdrop: %v", err)
+	}
+}
+
+// NewFundedKeypair returns a new keypair holding 10 SOL.
+func (v *Validator) NewFundedKeypair() solana.PrivateKey {
+	v.tb.Helper()
+	key := solana.NewWallet().PrivateKey
+	v.Fund(key.PublicKey(), 10*solana.LAMPORTS_PER_SOL)
+	return key
+}
+
+// Send signs instructions with payer and signers, sends them and waits for
+// confirmation.
+func (v *Validator) Send(payer solana.PrivateKey, instructions []solana.Instruction, signers ...solana.PrivateKey) solana.Signature {
+	v.tb.Helper()
+	builder := token2022.NewTxBuilder(v.client).
+		SetFeePayer(payer.PublicKey()).
+		AddInstruction(instructions...).
+		AddSigner(token2022.PrivateKeySigners(append([]solana.PrivateKey{payer}, signers...)...)...)
+	result, err := token2022.NewSender(v.client).
+		SetPollInterval(200*time.Millisecond).
+		Send(context.Background(), builder)
+	if err != nil {
+		v.tb.Fatalf("Error sending transaction: %v", err)
+	}
+	return result.Signature
+}
+
+// MintExtension is a fixed-size mint extension initialized before
+// InitializeMint.
+type MintExtension struct {
+	Type token2022.ExtensionType
+	// Length is the size of the extension data.
+	Length int
+	// Instruction returns the instruction initializing the extension.
+	Instruction func(mint solana.PublicKey) solana.Instruction
+}
+
+// MintCloseAuthority returns the MintCloseAuthority extension.
+func MintCloseAuthority(authority solana.PublicKey) MintExtension {
+	return MintExtension{
+		Type:   token2022.ExtensionMintCloseAuthority,
+		Length: 32,
+		Instruction: func(mint solana.PublicKey) solana.Instruction {
+			return token2022.NewInitializeMintCloseAuthority2022Instruction(&authority, mint).Build()
+		},
+	}
+}
+
+// TransferFeeConfig returns the TransferFeeConfig extension with authority
+// as both the config and withdraw authority.
+func TransferFeeConfig(authority solana.PublicKey, basisPoints uint16, maximumFee uint64) MintExtension {
+	return MintExtension{
+		Type:   token2022.ExtensionTransferFeeConfig,
+		Length: 108,
+		Instruction: func(mint solana.PublicKey) solana.Instruction {
+			return token2022.NewInitializeTransferFeeConfig2022Instruction(&authority, &authority, basisPoints, maximumFee, mint).Build()
+		},
+	}
+}
+
+// NonTransferable returns the NonTransferable extension.
+func NonTransferable() MintExtension {
+	return MintExtension{
+		Type: token2022.ExtensionNonTransferable,
+		Instruction: func(mint solana.PublicKey) solana.Instruction {
+			return token2022.NewInitializeNonTransferableMint2022Instruction(mint).Build()
+		},
+	}
+}
+
+// PermanentDelegate returns the PermanentDelegate extension.
+func PermanentDelegate(delegate solana.PublicKey) MintExtension {
+	return MintExtension{
+		Type:   token2022.ExtensionPermanentDelegate,
+		Length: 32,
+		Instruction: func(mint solana.PublicKey) solana.Instruction {
+			return token2022.NewInitializePermanentDelegate2022Instruction(delegate, mint).Build()
+		},
+	}
+}
+
+// DefaultAccountState returns the DefaultAccountState extension. A frozen
+// default also needs a freeze authority on the mint.
+func DefaultAccountState(state token2022.AccountState) MintExtension {
+	return MintExtension{
+		Type:   token2022.ExtensionDefaultAccountState,
+		Length: 1,
+		Instruction: func(mint solana.PublicKey) solana.Instruction {
+			return token2022.NewInitializeDefaultAccountState2022Instruction(state, mint).Build()
+		},
+	}
+}
+
+// InterestBearing returns the InterestBearingConfig extension.
+func InterestBearing(authority solana.PublicKey, rate int16) MintExtension {
+	return MintExtension{
+		Type:   token2022.ExtensionInterestBearingConfig,
+		Length: 52,
+		Instruction: func(mint solana.PublicKey) solana.Instruction {
+			return token2022.NewInitializeInterestBearingMint2022Instruction(&authority, rate, mint).Build()
+		},
+	}
+}
+
+// MintSpace returns the account size of a mint with extensions.
+func MintSpace(extensions ...MintExtension) int {
+	if len(extensions) == 0 {
+		return token2022.MintSize
+	}
+	space := token2022.AccountSize + 1
+	for _, extension := range extensions {
+		space += 4 + extension.Length
+	}
+	return space
+}
+
+// CreateMint creates a mint with the given extensions, authority as mint
+// and freeze authority, and payer paying for it. It returns the mint
+// address.
+func (v *Validator) CreateMint(payer solana.PrivateKey, authority solana.PublicKey, decimals uint8, extensions ...MintExtension) solana.PublicKey {
+	v.tb.Helper()
+	mint := solana.NewWallet().PrivateKey
+	space := MintSpace(extensions...)
+	lamports, err := v.client.GetMinimumBalanceForRentExemption(context.Background(), uint64(space), rpc.CommitmentConfirmed)
+	if err != nil {
+		v.tb.Fatalf("Error getting rent exemption: %v", err)
+	}
+
+	instructions := []solana.Instruction{
+		system.NewCreateAccountInstruction(lamports, uint64(space), solana.Token2022ProgramID, payer.PublicKey(), mint.PublicKey()).Build(),
+	}
+	for _, extension := range extensions {
+		instructions = append(instructions, extension.Instruction(mint.PublicKey()))
+	}
+	instructions = append(instructions,
+		token2022.NewInitializeMint2022Instruction(decimals, authority, &authority, mint.PublicKey()).Build())
+
+	v.Send(payer, instructions, mint)
+	return mint.PublicKey()
+}
+
+// CreateTokenAccount creates the associated token account of owner for
+// mint and returns its address.
+func (v *Validator) CreateTokenAccount(payer solana.PrivateKey, owner solana.PublicKey, mint solana.PublicKey) solana.PublicKey {
+	v.tb.Helper()
+	account, _, err := token2022.FindAssociatedTokenAddress2022(owner, mint)
+	if err != nil {
+		v.tb.Fatalf("Error finding associated token address: %v", err)
+	}
+	v.Send(payer, []solana.Instruction{
+		token2022.NewCreate2022Instruction(payer.PublicKey(), owner, mint).Build(),
+	})
+	return account
+}
+
+// MintTo mints amount tokens to a token account.
+func (v *Validator) MintTo(payer solana.PrivateKey, mint solana.PublicKey, destination solana.PublicKey, authority solana.PrivateKey, amount uint64) {
+	v.tb.Helper()
+	v.Send(payer, []solana.Instruction{
+		token2022.NewMintTo2022Instruction(amount, mint, destination, authority.PublicKey()).Build(),
+	}, authority)
+}
@@ -0,0 +1,50 @@
+package token2022test
+
+import (
+	"context"
+	"testing"
+
+	rpc "github.com/gagliardetto/solana-go/rpc"
+
+	token2022 "github.com/dwmfan/token2022"
+)
+
+func TestMintSpace(t *testing.T) {
+	if got := MintSpace(); got != token2022.MintSize {
+		t.Errorf("Expected %d, got %d", token2022.MintSize, got)
+	}
+	// Base account, account type, then 4-byte TLV headers.
+	if got := MintSpace(NonTransferable(), DefaultAccountState(token2022.AccountStateFrozen)); got != 165+1+4+4+1 {
+		t.Errorf("Expected 175, got %d", got)
+	}
+}
+
+func TestValidatorCreateMint(t *testing.T) {
+
+	var (
+		validator = StartValidator(t)
+		payer     = validator.NewFundedKeypair()
+		wallet    = validator.NewFundedKeypair()
+	)
+
+	mint := validator.CreateMint(payer, payer.PublicKey(), 6,
+		TransferFeeConfig(payer.PublicKey(), 50, 5000),
+		MintCloseAuthority(payer.PublicKey()),
+	)
+	account := validator.CreateTokenAccount(payer, wallet.PublicKey(), mint)
+	validator.MintTo(payer, mint, account, payer, 1_000_000)
+
+	got, err := token2022.FetchMint(context.Background(), validator.Client(), mint, rpc.CommitmentConfirmed)
+	if err != nil {
+		t.Fatalf("Error fetching mint: %v", err)
+	}
+	if got.Supply != 1_000_000 || got.Decimals != 6 {
+		t.Errorf("Unexpected mint %+v", got)
+	}
+	if _, ok := got.Extension(token2022.ExtensionTransferFeeConfig); !ok {
+		t.Errorf("Expected TransferFeeConfig extension")
+	}
+	if _, ok := got.Extension(token2022.ExtensionMintCloseAuthority); !ok {
+		t.Errorf("Expected MintCloseAuthority extension")
+	}
+}
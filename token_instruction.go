@@ -0,0 +1,242 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"fmt"
+	"math"
+
+	bin "github.com/gagliardetto/binary"
+	solana "github.com/gagliardetto/solana-go"
+	format "github.com/gagliardetto/solana-go/text/format"
+	treeout "github.com/gagliardetto/treeout"
+)
+
+// TokenProgramName is the name of the Token-2022 program.
+const TokenProgramName = "Token-2022 Program"
+
+// MaxSigners is the maximum number of signers of a multisig account.
+const MaxSigners = 11
+
+// MaxFeeBasisPoints is the largest transfer fee, 100%.
+const MaxFeeBasisPoints = 10_000
+
+// AuthorityType selects the authority changed by SetAuthority2022.
+type AuthorityType uint8
+
+const (
+	AuthorityMintTokens AuthorityType = iota
+	AuthorityFreezeAccount
+	AuthorityAccountOwner
+	AuthorityCloseAccount
+	AuthorityTransferFeeConfig
+	AuthorityWithheldWithdraw
+	AuthorityCloseMint
+	AuthorityInterestRate
+	AuthorityPermanentDelegate
+	AuthorityConfidentialTransferMint
+	AuthorityTransferHookProgramID
+	AuthorityConfidentialTransferFeeConfig
+	AuthorityMetadataPointer
+	AuthorityGroupPointer
+	AuthorityGroupMemberPointer
+	AuthorityScaledUiAmount
+	AuthorityPause
+)
+
+var authorityTypeNames = []string{
+	"MintTokens",
+	"FreezeAccount",
+	"AccountOwner",
+	"CloseAccount",
+	"TransferFeeConfig",
+	"WithheldWithdraw",
+	"CloseMint",
+	"InterestRate",
+	"PermanentDelegate",
+	"ConfidentialTransferMint",
+	"TransferHookProgramId",
+	"ConfidentialTransferFeeConfig",
+	"MetadataPointer",
+	"GroupPointer",
+	"GroupMemberPointer",
+	"ScaledUiAmount",
+	"Pause",
+}
+
+func (t AuthorityType) String() string {
+	if int(t) < len(authorityTypeNames) {
+		return authorityTypeNames[t]
+	}
+	return fmt.Sprintf("AuthorityType(%d)", uint8(t))
+}
+
+func (t AuthorityType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *AuthorityType) UnmarshalText(text []byte) error {
+	n, err := parseEnumName(text, "AuthorityType", uint64(len(authorityTypeNames)), func(i uint64) string { return AuthorityType(i).String() }, 8)
+	*t = AuthorityType(n)
+	return err
+}
+
+// newTokenInstruction wraps a Token-2022 builder into an Instruction.
+func newTokenInstruction(impl interface{}) *Instruction {
+	discriminator, err := discriminatorOf(impl)
+	if err != nil {
+		panic(err)
+	}
+	return &Instruction{
+		BaseVariant: bin.BaseVariant{
+			Impl:   impl,
+			TypeID: discriminator.TypeID(),
+		},
+		programID: solana.Token2022ProgramID,
+	}
+}
+
+// appendAuthority appends the authority of an instruction. A single
+// authority signs; a multisig authority does not and is followed by its
+// signers instead.
+func appendAuthority(keys []*solana.AccountMeta, authority solana.PublicKey, signers []solana.PublicKey) []*solana.AccountMeta {
+	keys = append(keys, &solana.AccountMeta{
+		PublicKey:  authority,
+		IsSigner:   len(signers) == 0,
+		IsWritable: false,
+	})
+	for _, signer := range signers {
+		keys = append(keys, &solana.AccountMeta{
+			PublicKey:  signer,
+			IsSigner:   true,
+			IsWritable: false,
+		})
+	}
+	return keys
+}
+
+func validateMultisigSigners(signers []solana.PublicKey) error {
+	if len(signers) > MaxSigners {
+		return fmt.Errorf("too many multisig signers: %d, maximum is %d", len(signers), MaxSigners)
+	}
+	for _, signer := range signers {
+		if signer.IsZero() {
+			return errNotSet("multisig signer")
+		}
+	}
+	return nil
+}
+
+// splitSigners splits the accounts following an authority into the
+// leading signer accounts and the remaining accounts.
+func splitSigners(accounts []*solana.AccountMeta) ([]solana.PublicKey, []*solana.AccountMeta) {
+	n := 0
+	for n < len(accounts) && accounts[n].IsSigner {
+		n++
+	}
+	return pubkeysOf(accounts[:n]), accounts[n:]
+}
+
+func pubkeysOf(accounts []*solana.AccountMeta) []solana.PublicKey {
+	if len(accounts) == 0 {
+		return nil
+	}
+	keys := make([]solana.PublicKey, len(accounts))
+	for i, account := range accounts {
+		keys[i] = account.PublicKey
+	}
+	return keys
+}
+
+func checkAccountCount(name string, accounts []*solana.AccountMeta, want int) error {
+	if len(accounts) < want {
+		return fmt.Errorf("%w: %s: expected at least %d accounts, got %d", ErrMalformedInstruction, name, want, len(accounts))
+	}
+	return nil
+}
+
+// encodeRemainingAccounts adds the accounts from index on to the tree.
+func encodeRemainingAccounts(branch treeout.Branches, label string, accounts solana.AccountMetaSlice, from int) {
+	for i := from; i < len(accounts); i++ {
+		branch.Child(format.Meta(fmt.Sprintf("%s[%d]", label, i-from), accounts[i]))
+	}
+}
+
+func readPubkey(decoder *bin.Decoder) (solana.PublicKey, error) {
+	data, err := decoder.ReadNBytes(solana.PublicKeyLength)
+	if err != nil {
+		return solana.PublicKey{}, err
+	}
+	return solana.PublicKeyFromBytes(data), nil
+}
+
+// writeOptionalNonZeroPubkey writes an OptionalNonZeroPubkey: always 32
+// bytes, all zero when the key is not set.
+func writeOptionalNonZeroPubkey(encoder *bin.Encoder, key *solana.PublicKey) error {
+	if key == nil {
+		return encoder.WriteBytes(make([]byte, solana.PublicKeyLength), false)
+	}
+	return encoder.WriteBytes(key[:], false)
+}
+
+func readOptionalNonZeroPubkey(decoder *bin.Decoder) (*solana.PublicKey, error) {
+	key, err := readPubkey(decoder)
+	if err != nil {
+		return nil, err
+	}
+	if key.IsZero() {
+		return nil, nil
+	}
+	return &key, nil
+}
+
+// readFiniteFloat64 reads an f64, rejecting NaN and infinities, which the
+// program never accepts and the encoder cannot write.
+func readFiniteFloat64(decoder *bin.Decoder) (float64, error) {
+	value, err := decoder.ReadFloat64(bin.LE)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("invalid float value %v", value)
+	}
+	return value, nil
+}
+
+// writeExtensionTypes writes extension types as consecutive u16 values
+// without a length prefix; they run to the end of the instruction data.
+func writeExtensionTypes(encoder *bin.Encoder, types []ExtensionType) error {
+	for _, t := range types {
+		if err := encoder.WriteUint16(uint16(t), bin.LE); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readExtensionTypes(decoder *bin.Decoder) ([]ExtensionType, error) {
+	if decoder.Remaining()%2 != 0 {
+		return nil, fmt.Errorf("invalid extension type list length %d", decoder.Remaining())
+	}
+	var types []ExtensionType
+	for decoder.Remaining() > 0 {
+		t, err := decoder.ReadUint16(bin.LE)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, ExtensionType(t))
+	}
+	return types, nil
+}
@@ -0,0 +1,280 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+// TokenError is an error code returned by the Token-2022 program as a
+// custom program error. TokenError values are comparable, so
+// errors.Is(err, ErrInsufficientFunds) matches a mapped error.
+type TokenError uint32
+
+// Token-2022 program error codes.
+const (
+	ErrNotRentExempt TokenError = iota
+	ErrInsufficientFunds
+	ErrInvalidMint
+	ErrMintMismatch
+	ErrOwnerMismatch
+	ErrFixedSupply
+	ErrAlreadyInUse
+	ErrInvalidNumberOfProvidedSigners
+	ErrInvalidNumberOfRequiredSigners
+	ErrUninitializedState
+	ErrNativeNotSupported
+	ErrNonNativeHasBalance
+	ErrInvalidInstruction
+	ErrInvalidState
+	ErrOverflow
+	ErrAuthorityTypeNotSupported
+	ErrMintCannotFreeze
+	ErrAccountFrozen
+	ErrMintDecimalsMismatch
+	ErrNonNativeNotSupported
+	ErrExtensionTypeMismatch
+	ErrExtensionBaseMismatch
+	ErrExtensionAlreadyInitialized
+	ErrConfidentialTransferAccountHasBalance
+	ErrConfidentialTransferAccountNotApproved
+	ErrConfidentialTransferDepositsAndTransfersDisabled
+	ErrConfidentialTransferElGamalPubkeyMismatch
+	ErrConfidentialTransferBalanceMismatch
+	ErrMintHasSupply
+	ErrNoAuthorityExists
+	ErrTransferFeeExceedsMaximum
+	ErrMintRequiredForTransfer
+	ErrFeeMismatch
+	ErrFeeParametersMismatch
+	ErrImmutableOwner
+	ErrAccountHasWithheldTransferFees
+	ErrNoMemo
+	ErrNonTransferable
+	ErrNonTransferableNeedsImmutableOwnership
+	ErrMaximumPendingBalanceCreditCounterExceeded
+	ErrMaximumDepositAmountExceeded
+	ErrCpiGuardSettingsLocked
+	ErrCpiGuardTransferBlocked
+	ErrCpiGuardBurnBlocked
+	ErrCpiGuardCloseAccountBlocked
+	ErrCpiGuardApproveBlocked
+	ErrCpiGuardSetAuthorityBlocked
+	ErrCpiGuardOwnerChangeBlocked
+	ErrExtensionNotFound
+	ErrNonConfidentialTransfersDisabled
+	ErrConfidentialTransferFeeAccountHasWithheldFee
+	ErrInvalidExtensionCombination
+	ErrInvalidLengthForAlloc
+	ErrAccountDecryption
+	ErrProofGeneration
+	ErrInvalidProofInstructionOffset
+	ErrHarvestToMintDisabled
+	ErrSplitProofContextStateAccountsNotSupported
+	ErrNotEnoughProofContextStateAccounts
+	ErrMalformedCiphertext
+	ErrCiphertextArithmeticFailed
+	ErrPedersenCommitmentMismatch
+	ErrRangeProofLengthMismatch
+	ErrIllegalBitLength
+	ErrFeeCalculation
+	ErrIllegalMintBurnConversion
+	ErrInvalidScale
+	ErrMintPaused
+	ErrPendingBalanceNonZero
+)
+
+var tokenErrors = []struct {
+	name    string
+	message string
+}{
+	{"NotRentExempt", "Lamport balance below rent-exempt threshold"},
+	{"InsufficientFunds", "Insufficient funds"},
+	{"InvalidMint", "Invalid Mint"},
+	{"MintMismatch", "Account not associated with this Mint"},
+	{"OwnerMismatch", "Owner does not match"},
+	{"FixedSupply", "Fixed supply"},
+	{"AlreadyInUse", "Already in use"},
+	{"InvalidNumberOfProvidedSigners", "Invalid number of provided signers"},
+	{"InvalidNumberOfRequiredSigners", "Invalid number of required signers"},
+	{"UninitializedState", "State is uninitialized"},
+	{"NativeNotSupported", "Instruction does not support native tokens"},
+	{"NonNativeHasBalance", "Non-native account can only be closed if its balance is zero"},
+	{"InvalidInstruction", "Invalid instruction"},
+	{"InvalidState", "State is invalid for requested operation"},
+	{"Overflow", "Operation overflowed"},
+	{"AuthorityTypeNotSupported", "Account does not support specified authority type"},
+	{"MintCannotFreeze", "This token mint cannot freeze accounts"},
+	{"AccountFrozen", "Account is frozen"},
+	{"MintDecimalsMismatch", "The provided decimals value different from the Mint decimals"},
+	{"NonNativeNotSupported", "Instruction does not support non-native tokens"},
+	{"ExtensionTypeMismatch", "Extension type does not match already existing extensions"},
+	{"ExtensionBaseMismatch", "Extension does not match the base type provided"},
+	{"ExtensionAlreadyInitialized", "Extension already initialized on this account"},
+	{"ConfidentialTransferAccountHasBalance", "An account can only be closed if its confidential balance is zero"},
+	{"ConfidentialTransferAccountNotApproved", "Account not approved for confidential transfers"},
+	{"ConfidentialTransferDepositsAndTransfersDisabled", "Account not accepting deposits or transfers"},
+	{"ConfidentialTransferElGamalPubkeyMismatch", "ElGamal public key mismatch"},
+	{"ConfidentialTransferBalanceMismatch", "Balance mismatch"},
+	{"MintHasSupply", "Mint has non-zero supply. Burn all tokens before closing the mint"},
+	{"NoAuthorityExists", "No authority exists to perform the desired operation"},
+	{"TransferFeeExceedsMaximum", "Transfer fee exceeds maximum of 10,000 basis points"},
+	{"MintRequiredForTransfer", "Mint required for this account to transfer tokens, use TransferChecked or TransferCheckedWithFee"},
+	{"FeeMismatch", "Calculated fee does not match expected fee"},
+	{"FeeParametersMismatch", "Fee parameters associated with zero-knowledge proofs do not match fee parameters in mint"},
+	{"ImmutableOwner", "The owner authority cannot be changed"},
+	{"AccountHasWithheldTransferFees", "An account can only be closed if its withheld fee balance is zero, harvest fees to the mint and try again"},
+	{"NoMemo", "No memo in previous instruction; required for recipient to receive a transfer"},
+	{"NonTransferable", "Transfer is disabled for this mint"},
+	{"NonTransferableNeedsImmutableOwnership", "Non-transferable tokens can't be minted to an account without immutable ownership"},
+	{"MaximumPendingBalanceCreditCounterExceeded", "The total number of Deposit and Transfer instructions to an account cannot exceed the associated maximum_pending_balance_credit_counter"},
+	{"MaximumDepositAmountExceeded", "Deposit amount exceeds maximum limit"},
+	{"CpiGuardSettingsLocked", "CPI Guard cannot be enabled or disabled in CPI"},
+	{"CpiGuardTransferBlocked", "CPI Guard is enabled, and a program attempted to transfer user funds via CPI without using a delegate"},
+	{"CpiGuardBurnBlocked", "CPI Guard is enabled, and a program attempted to burn user funds via CPI without using a delegate"},
+	{"CpiGuardCloseAccountBlocked", "CPI Guard is enabled, and a program attempted to close an account via CPI without returning lamports to owner"},
+	{"CpiGuardApproveBlocked", "CPI Guard is enabled, and a program attempted to approve a delegate via CPI"},
+	{"CpiGuardSetAuthorityBlocked", "CPI Guard is enabled, and a program attempted to add or replace an authority via CPI"},
+	{"CpiGuardOwnerChangeBlocked", "Account ownership cannot be changed while CPI Guard is enabled"},
+	{"ExtensionNotFound", "Extension not found in account data"},
+	{"NonConfidentialTransfersDisabled", "Non-confidential transfers disabled"},
+	{"ConfidentialTransferFeeAccountHasWithheldFee", "An account can only be closed if the confidential withheld fee is zero"},
+	{"InvalidExtensionCombination", "A mint or an account is initialized to an invalid combination of extensions"},
+	{"InvalidLengthForAlloc", "Extension allocation with overwrite must use the same length"},
+	{"AccountDecryption", "Failed to decrypt a confidential transfer account"},
+	{"ProofGeneration", "Failed to generate proof"},
+	{"InvalidProofInstructionOffset", "An invalid proof instruction offset was provided"},
+	{"HarvestToMintDisabled", "Harvest of withheld tokens to mint is disabled"},
+	{"SplitProofContextStateAccountsNotSupported", "Split proof context state accounts not supported for instruction"},
+	{"NotEnoughProofContextStateAccounts", "Not enough proof context state accounts provided"},
+	{"MalformedCiphertext", "Ciphertext is malformed"},
+	{"CiphertextArithmeticFailed", "Ciphertext arithmetic failed"},
+	{"PedersenCommitmentMismatch", "Pedersen commitments did not match"},
+	{"RangeProofLengthMismatch", "Range proof length did not match"},
+	{"IllegalBitLength", "Illegal transfer amount bit length"},
+	{"FeeCalculation", "Fee calculation failed"},
+	{"IllegalMintBurnConversion", "Conversions from normal to confidential token balance and vice versa are disabled"},
+	{"InvalidScale", "Invalid scale for scaled ui amount"},
+	{"MintPaused", "Transferring, minting, and burning is paused on this mint"},
+	{"PendingBalanceNonZero", "Pending supply is not zero"},
+}
+
+// Known reports whether the code is a Token-2022 error code.
+func (e TokenError) Known() bool {
+	return int(e) < len(tokenErrors)
+}
+
+// String returns the error name, such as "InsufficientFunds".
+func (e TokenError) String() string {
+	if e.Known() {
+		return tokenErrors[e].name
+	}
+	return fmt.Sprintf("Unknown(%d)", uint32(e))
+}
+
+func (e TokenError) Error() string {
+	if e.Known() {
+		return fmt.Sprintf("token-2022 error 0x%x: %s", uint32(e), tokenErrors[e].message)
+	}
+	return fmt.Sprintf("token-2022 error 0x%x", uint32(e))
+}
+
+// customProgramErrorLog matches the log line of a program failing with a
+// custom error, for example
+// "Program TokenzQdBNbLqP5VEhdkAS6EPFLC1PHnBqCXEpPxuEb failed: custom program error: 0x1".
+var customProgramErrorLog = regexp.MustCompile(`^Program (\w+) failed: custom program error: 0x([0-9a-fA-F]+)$`)
+
+// TokenErrorFromLogs returns the Token-2022 error in transaction logs. Only
+// the first failing program is considered: a program that fails after a
+// Token-2022 CPI failed reports the same code, while a custom error of any
+// other program is not a TokenError.
+func TokenErrorFromLogs(logs []string) (TokenError, bool) {
+	for _, line := range logs {
+		match := customProgramErrorLog.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		if match[1] != solana.Token2022ProgramID.String() {
+			return 0, false
+		}
+		code, err := strconv.ParseUint(match[2], 16, 32)
+		if err != nil {
+			return 0, false
+		}
+		return TokenError(code), true
+	}
+	return 0, false
+}
+
+// AsTokenError finds the Token-2022 error in err. It recognizes a TokenError
+// in the error chain and the logs of a failed preflight simulation
+// returned by sendTransaction or simulateTransaction.
+func AsTokenError(err error) (TokenError, bool) {
+	var tokenErr TokenError
+	if errors.As(err, &tokenErr) {
+		return tokenErr, true
+	}
+	var rpcErr *jsonrpc.RPCError
+	if errors.As(err, &rpcErr) {
+		return TokenErrorFromLogs(simulationLogs(rpcErr.Data))
+	}
+	return 0, false
+}
+
+// MapTokenError wraps err with the Token-2022 error found in err or in the
+// transaction logs, so that errors.Is(mapped, ErrInsufficientFunds) works.
+// err is returned unchanged when no Token-2022 error is found.
+func MapTokenError(err error, logs []string) error {
+	if err == nil {
+		return nil
+	}
+	var tokenErr TokenError
+	if errors.As(err, &tokenErr) {
+		return err
+	}
+	tokenErr, ok := AsTokenError(err)
+	if !ok {
+		tokenErr, ok = TokenErrorFromLogs(logs)
+	}
+	if !ok {
+		return err
+	}
+	return fmt.Errorf("%w: %w", tokenErr, err)
+}
+
+// simulationLogs returns the logs in the data of a simulation failure.
+func simulationLogs(data interface{}) []string {
+	fields, ok := data.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	raw, ok := fields["logs"].([]interface{})
+	if !ok {
+		return nil
+	}
+	logs := make([]string, 0, len(raw))
+	for _, line := range raw {
+		if s, ok := line.(string); ok {
+			logs = append(logs, s)
+		}
+	}
+	return logs
+}
@@ -0,0 +1,101 @@
+package token2022
+
+import (
+	"errors"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/gagliardetto/solana-go/rpc/jsonrpc"
+)
+
+func TestTokenErrorCodes(t *testing.T) {
+	cases := map[TokenError]uint32{
+		ErrNotRentExempt:           0,
+		ErrInsufficientFunds:       1,
+		ErrMintMismatch:            3,
+		ErrAccountFrozen:           17,
+		ErrMintHasSupply:           28,
+		ErrNoMemo:                  36,
+		ErrCpiGuardTransferBlocked: 42,
+		ErrExtensionNotFound:       48,
+		ErrMintPaused:              67,
+		ErrPendingBalanceNonZero:   68,
+	}
+	for tokenErr, code := range cases {
+		if uint32(tokenErr) != code {
+			t.Errorf("%s: expected code %d, got %d", tokenErr, code, uint32(tokenErr))
+		}
+	}
+	if ErrInsufficientFunds.String() != "InsufficientFunds" {
+		t.Errorf("unexpected name %q", ErrInsufficientFunds.String())
+	}
+	if ErrInsufficientFunds.Error() != "token-2022 error 0x1: Insufficient funds" {
+		t.Errorf("unexpected message %q", ErrInsufficientFunds.Error())
+	}
+	if TokenError(1000).Known() || TokenError(1000).String() != "Unknown(1000)" {
+		t.Errorf("unexpected unknown error %s", TokenError(1000))
+	}
+}
+
+func TestTokenErrorFromLogs(t *testing.T) {
+	logs := []string{
+		"Program 11111111111111111111111111111111 invoke [1]",
+		"Program 11111111111111111111111111111111 success",
+		"Program " + solana.Token2022ProgramID.String() + " invoke [1]",
+		"Program log: Instruction: TransferChecked",
+		"Program log: Error: insufficient funds",
+		"Program " + solana.Token2022ProgramID.String() + " consumed 1200 of 200000 compute units",
+		"Program " + solana.Token2022ProgramID.String() + " failed: custom program error: 0x1",
+	}
+	tokenErr, ok := TokenErrorFromLogs(logs)
+	if !ok || tokenErr != ErrInsufficientFunds {
+		t.Errorf("expected InsufficientFunds, got %v %v", tokenErr, ok)
+	}
+
+	// A custom error of another program is not a Token-2022 error.
+	other := []string{"Program MemoSq4gqABAXKb96qnH8TysNcWxMyWCqXgDLGmfcHr failed: custom program error: 0x1"}
+	if _, ok := TokenErrorFromLogs(other); ok {
+		t.Errorf("expected no Token-2022 error")
+	}
+
+	txErr := DecodeTransactionError(solana.Signature{}, map[string]interface{}{
+		"InstructionError": []interface{}{float64(0), map[string]interface{}{"Custom": float64(1)}},
+	})
+	mapped := MapTokenError(txErr, logs)
+	if !errors.Is(mapped, ErrInsufficientFunds) {
+		t.Errorf("expected mapped error to match ErrInsufficientFunds: %v", mapped)
+	}
+	var decoded *TransactionError
+	if !errors.As(mapped, &decoded) || decoded.InstructionIndex != 0 {
+		t.Errorf("expected the transaction error to be kept")
+	}
+	if MapTokenError(txErr, other) != txErr {
+		t.Errorf("expected the error unchanged")
+	}
+}
+
+func TestAsTokenErrorSimulation(t *testing.T) {
+	err := &jsonrpc.RPCError{
+		Code:    -32002,
+		Message: "Transaction simulation failed: Error processing Instruction 0: custom program error: 0x2a",
+		Data: map[string]interface{}{
+			"err": map[string]interface{}{"InstructionError": []interface{}{float64(0), map[string]interface{}{"Custom": float64(42)}}},
+			"logs": []interface{}{
+				"Program " + solana.Token2022ProgramID.String() + " invoke [2]",
+				"Program " + solana.Token2022ProgramID.String() + " failed: custom program error: 0x2a",
+				"Program MemoSq4gqABAXKb96qnH8TysNcWxMyWCqXgDLGmfcHr failed: custom program error: 0x2a",
+			},
+		},
+	}
+	tokenErr, ok := AsTokenError(err)
+	if !ok || tokenErr != ErrCpiGuardTransferBlocked {
+		t.Errorf("expected CpiGuardTransferBlocked, got %v %v", tokenErr, ok)
+	}
+	mapped := MapTokenError(err, nil)
+	if !errors.Is(mapped, ErrCpiGuardTransferBlocked) || !errors.Is(mapped, err) {
+		t.Errorf("unexpected mapped error %v", mapped)
+	}
+	if _, ok := AsTokenError(errors.New("boom")); ok {
+		t.Errorf("expected no Token-2022 error")
+	}
+}
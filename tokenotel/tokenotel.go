@@ -0,0 +1,194 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tokenotel traces token2022 with OpenTelemetry. Tracer adapts an
+// OpenTelemetry tracer for TxBuilder.SetTracer and Sender.SetTracer, and
+// Client wraps an RPC client with a span per request, so slow Token-2022
+// operations can be followed from building to confirmation.
+package tokenotel
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dwmfan/token2022"
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName names the tracer of the spans.
+const InstrumentationName = "github.com/dwmfan/token2022"
+
+// Tracer starts OpenTelemetry spans for token2022.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+var _ token2022.Tracer = (*Tracer)(nil)
+
+// NewTracer creates a Tracer from provider, or from the global provider
+// when provider is nil.
+func NewTracer(provider trace.TracerProvider) *Tracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return &Tracer{tracer: provider.Tracer(InstrumentationName)}
+}
+
+// Start implements token2022.Tracer. attrs alternate string keys and
+// values; values other than strings, booleans, integers and floats are
+// recorded with fmt.Sprint.
+func (t *Tracer) Start(ctx context.Context, name string, attrs ...any) (context.Context, func(error)) {
+	ctx, span := t.tracer.Start(ctx, name, trace.WithAttributes(attributes(attrs)...))
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+func attributes(args []any) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(args)/2)
+	for i := 0; i+1 < len(args); i += 2 {
+		key := attribute.Key(fmt.Sprint(args[i]))
+		switch v := args[i+1].(type) {
+		case string:
+			kvs = append(kvs, key.String(v))
+		case bool:
+			kvs = append(kvs, key.Bool(v))
+		case int:
+			kvs = append(kvs, key.Int(v))
+		case int64:
+			kvs = append(kvs, key.Int64(v))
+		case uint64:
+			kvs = append(kvs, key.Int64(int64(v)))
+		case float64:
+			kvs = append(kvs, key.Float64(v))
+		default:
+			kvs = append(kvs, key.String(fmt.Sprint(v)))
+		}
+	}
+	return kvs
+}
+
+// Client wraps an RPC client with a span per request, named after the
+// JSON-RPC method. Besides token2022.RPCClient it serves getEpochInfo,
+// getMultipleAccounts and simulateTransaction when the wrapped client
+// does, as *rpc.Client does.
+type Client struct {
+	client token2022.RPCClient
+	tracer *Tracer
+}
+
+var (
+	_ token2022.RPCClient              = (*Client)(nil)
+	_ token2022.TransferClient         = (*Client)(nil)
+	_ token2022.MultipleAccountsClient = (*Client)(nil)
+)
+
+// NewClient wraps client, tracing with provider or, when provider is nil,
+// the global provider.
+func NewClient(client token2022.RPCClient, provider trace.TracerProvider) *Client {
+	return &Client{client: client, tracer: NewTracer(provider)}
+}
+
+func (c *Client) start(ctx context.Context, method string, attrs ...any) (context.Context, func(error)) {
+	return c.tracer.Start(ctx, method, append([]any{"rpc.system", "jsonrpc", "rpc.method", method}, attrs...)...)
+}
+
+func (c *Client) GetLatestBlockhash(ctx context.Context, commitment rpc.CommitmentType) (out *rpc.GetLatestBlockhashResult, err error) {
+	ctx, end := c.start(ctx, "getLatestBlockhash")
+	defer func() { end(err) }()
+	return c.client.GetLatestBlockhash(ctx, commitment)
+}
+
+func (c *Client) GetBlockHeight(ctx context.Context, commitment rpc.CommitmentType) (out uint64, err error) {
+	ctx, end := c.start(ctx, "getBlockHeight")
+	defer func() { end(err) }()
+	return c.client.GetBlockHeight(ctx, commitment)
+}
+
+func (c *Client) GetAccountInfoWithOpts(ctx context.Context, account solana.PublicKey, opts *rpc.GetAccountInfoOpts) (out *rpc.GetAccountInfoResult, err error) {
+	ctx, end := c.start(ctx, "getAccountInfo", "solana.account", account.String())
+	defer func() { end(err) }()
+	return c.client.GetAccountInfoWithOpts(ctx, account, opts)
+}
+
+func (c *Client) SendTransactionWithOpts(ctx context.Context, transaction *solana.Transaction, opts rpc.TransactionOpts) (out solana.Signature, err error) {
+	ctx, end := c.start(ctx, "sendTransaction", "solana.signature", signature(transaction))
+	defer func() { end(err) }()
+	return c.client.SendTransactionWithOpts(ctx, transaction, opts)
+}
+
+func (c *Client) GetSignatureStatuses(ctx context.Context, searchTransactionHistory bool, transactionSignatures ...solana.Signature) (out *rpc.GetSignatureStatusesResult, err error) {
+	ctx, end := c.start(ctx, "getSignatureStatuses", "solana.signatures", len(transactionSignatures))
+	defer func() { end(err) }()
+	return c.client.GetSignatureStatuses(ctx, searchTransactionHistory, transactionSignatures...)
+}
+
+func (c *Client) GetEpochInfo(ctx context.Context, commitment rpc.CommitmentType) (out *rpc.GetEpochInfoResult, err error) {
+	ctx, end := c.start(ctx, "getEpochInfo")
+	defer func() { end(err) }()
+	client, ok := c.client.(token2022.EpochClient)
+	if !ok {
+		return nil, unsupported(c.client, "getEpochInfo")
+	}
+	return client.GetEpochInfo(ctx, commitment)
+}
+
+func (c *Client) GetMultipleAccountsWithOpts(ctx context.Context, accounts []solana.PublicKey, opts *rpc.GetMultipleAccountsOpts) (out *rpc.GetMultipleAccountsResult, err error) {
+	ctx, end := c.start(ctx, "getMultipleAccounts", "solana.accounts", len(accounts))
+	defer func() { end(err) }()
+	client, ok := c.client.(token2022.MultipleAccountsClient)
+	if !ok {
+		return nil, unsupported(c.client, "getMultipleAccounts")
+	}
+	return client.GetMultipleAccountsWithOpts(ctx, accounts, opts)
+}
+
+// simulateClient is the simulateTransaction call of *rpc.Client.
+type simulateClient interface {
+	SimulateTransactionWithOpts(ctx context.Context, transaction *solana.Transaction, opts *rpc.SimulateTransactionOpts) (*rpc.SimulateTransactionResponse, error)
+}
+
+func (c *Client) SimulateTransactionWithOpts(ctx context.Context, transaction *solana.Transaction, opts *rpc.SimulateTransactionOpts) (out *rpc.SimulateTransactionResponse, err error) {
+	ctx, end := c.start(ctx, "simulateTransaction", "solana.signature", signature(transaction))
+	defer func() { end(err) }()
+	client, ok := c.client.(simulateClient)
+	if !ok {
+		return nil, unsupported(c.client, "simulateTransaction")
+	}
+	out, err = client.SimulateTransactionWithOpts(ctx, transaction, opts)
+	if err == nil && out != nil && out.Value != nil && out.Value.Err != nil {
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String("solana.simulation_error", fmt.Sprint(out.Value.Err)))
+	}
+	return out, err
+}
+
+func signature(tx *solana.Transaction) string {
+	if len(tx.Signatures) == 0 {
+		return ""
+	}
+	return tx.Signatures[0].String()
+}
+
+func unsupported(client token2022.RPCClient, method string) error {
+	return fmt.Errorf("%T does not support %s", client, method)
+}
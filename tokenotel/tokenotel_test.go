@@ -0,0 +1,89 @@
+package tokenotel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dwmfan/token2022"
+	"github.com/dwmfan/token2022/token2022test"
+	solana "github.com/gagliardetto/solana-go"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSendSpans(t *testing.T) {
+	var (
+		owner    = solana.NewWallet().PrivateKey
+		mint     = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		server   = token2022test.NewServer(t)
+		recorder = tracetest.NewSpanRecorder()
+		provider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	)
+	client := NewClient(server.Client(), provider)
+	tracer := NewTracer(provider)
+
+	builder := token2022.NewTxBuilder(client).
+		SetTracer(tracer).
+		SetFeePayer(owner.PublicKey()).
+		AddInstruction(token2022.NewCreate2022Instruction(owner.PublicKey(), owner.PublicKey(), mint).Build()).
+		AddSigner(token2022.NewPrivateKeySigner(owner))
+	if _, err := token2022.NewSender(client).SetTracer(tracer).SetPollInterval(time.Millisecond).Send(context.Background(), builder); err != nil {
+		t.Fatalf("Error sending: %v", err)
+	}
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		if _, ok := spans[span.Name()]; !ok {
+			spans[span.Name()] = span
+		}
+	}
+	send, ok := spans["token2022.Sender.Send"]
+	if !ok {
+		t.Fatalf("Expected a Send span, got %v", spans)
+	}
+	for _, name := range []string{"token2022.TxBuilder.Build", "token2022.TxBuilder.Sign", "token2022.Sender.Confirm", "sendTransaction"} {
+		span, ok := spans[name]
+		if !ok {
+			t.Errorf("Expected a %s span", name)
+			continue
+		}
+		if span.SpanContext().TraceID() != send.SpanContext().TraceID() {
+			t.Errorf("Expected %s in the trace of Send", name)
+		}
+	}
+	if parent := spans["getLatestBlockhash"].Parent(); parent.SpanID() != spans["token2022.TxBuilder.Build"].SpanContext().SpanID() {
+		t.Errorf("Expected getLatestBlockhash to be a child of Build")
+	}
+}
+
+func TestClientRecordsErrors(t *testing.T) {
+	var (
+		server   = token2022test.NewServer(t)
+		recorder = tracetest.NewSpanRecorder()
+		provider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		account  = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+	)
+	client := NewClient(server.Client(), provider)
+	server.SetEpoch(7)
+
+	info, err := client.GetEpochInfo(context.Background(), "")
+	if err != nil || info.Epoch != 7 {
+		t.Fatalf("Expected epoch 7, got %+v, %v", info, err)
+	}
+	if _, err := token2022.FetchMint(context.Background(), client, account, ""); err == nil {
+		t.Fatalf("Expected a missing mint to fail")
+	}
+
+	ended := recorder.Ended()
+	if len(ended) != 2 || ended[0].Name() != "getEpochInfo" || ended[1].Name() != "getAccountInfo" {
+		t.Fatalf("Expected getEpochInfo and getAccountInfo spans, got %d", len(ended))
+	}
+	attrs := map[string]string{}
+	for _, kv := range ended[1].Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if attrs["rpc.method"] != "getAccountInfo" || attrs["solana.account"] != account.String() {
+		t.Errorf("Unexpected attributes %v", attrs)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tokenprom exports the metrics of token2022 to Prometheus.
+// Metrics implements token2022.Metrics with a counter or histogram per
+// metric name, for operators running cranks, senders and deposit watchers
+// built on token2022.
+package tokenprom
+
+import (
+	"fmt"
+
+	"github.com/dwmfan/token2022"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type metric struct {
+	name   string
+	help   string
+	labels []string
+	// buckets makes the metric a histogram.
+	buckets []float64
+}
+
+var metrics = []metric{
+	{name: token2022.MetricTransactionsSent, help: "Transactions accepted by the RPC node."},
+	{name: token2022.MetricTransactionsConfirmed, help: "Transactions that landed, by result.", labels: []string{"result"}},
+	{name: token2022.MetricConfirmationSeconds, help: "Time from sending a transaction to its confirmation.", buckets: []float64{0.5, 1, 2, 5, 10, 20, 30, 60, 90}},
+	{name: token2022.MetricRetries, help: "Retried operations.", labels: []string{"operation"}},
+	{name: token2022.MetricRPCSeconds, help: "Duration of RPC requests.", labels: []string{"method"}, buckets: prometheus.DefBuckets},
+	{name: token2022.MetricDecodeErrors, help: "Transactions that could not be decoded.", labels: []string{"source"}},
+	{name: token2022.MetricFeeAccountsHarvested, help: "Token accounts whose withheld fees were harvested or withdrawn.", labels: []string{"instruction"}},
+}
+
+// Metrics records token2022 metrics as Prometheus collectors. Metrics
+// with unknown names or the wrong number of labels, and negative counter
+// increments, are ignored.
+//
+// Metrics is safe for concurrent use.
+type Metrics struct {
+	counters   map[string]*prometheus.CounterVec
+	histograms map[string]*prometheus.HistogramVec
+}
+
+var _ token2022.Metrics = (*Metrics)(nil)
+
+// NewMetrics creates the collectors and registers them with registerer, or
+// with prometheus.DefaultRegisterer when registerer is nil.
+func NewMetrics(registerer prometheus.Registerer) (*Metrics, error) {
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+	m := &Metrics{
+		counters:   map[string]*prometheus.CounterVec{},
+		histograms: map[string]*prometheus.HistogramVec{},
+	}
+	for _, metric := range metrics {
+		var collector prometheus.Collector
+		if metric.buckets != nil {
+			vec := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: metric.name, Help: metric.help, Buckets: metric.buckets}, metric.labels)
+			m.histograms[metric.name] = vec
+			collector = vec
+		} else {
+			vec := prometheus.NewCounterVec(prometheus.CounterOpts{Name: metric.name, Help: metric.help}, metric.labels)
+			m.counters[metric.name] = vec
+			collector = vec
+		}
+		if err := registerer.Register(collector); err != nil {
+			return nil, fmt.Errorf("error while registering %s: %w", metric.name, err)
+		}
+	}
+	return m, nil
+}
+
+// Add implements token2022.Metrics.
+func (m *Metrics) Add(name string, delta float64, labels ...string) {
+	vec, ok := m.counters[name]
+	if !ok || delta < 0 {
+		return
+	}
+	if counter, err := vec.GetMetricWithLabelValues(labels...); err == nil {
+		counter.Add(delta)
+	}
+}
+
+// Observe implements token2022.Metrics.
+func (m *Metrics) Observe(name string, value float64, labels ...string) {
+	vec, ok := m.histograms[name]
+	if !ok {
+		return
+	}
+	if histogram, err := vec.GetMetricWithLabelValues(labels...); err == nil {
+		histogram.Observe(value)
+	}
+}
@@ -0,0 +1,79 @@
+package tokenprom
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dwmfan/token2022"
+	"github.com/dwmfan/token2022/token2022test"
+	solana "github.com/gagliardetto/solana-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestSendMetrics(t *testing.T) {
+	var (
+		owner   = solana.NewWallet().PrivateKey
+		mint    = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source  = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		dest    = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		server  = token2022test.NewServer(t)
+		reg     = prometheus.NewRegistry()
+		metrics = mustMetrics(t, reg)
+	)
+	client := token2022.NewThrottledClient(server.Client(), 1000, 10).SetMetrics(metrics)
+	builder := token2022.NewTxBuilder(client).
+		SetFeePayer(owner.PublicKey()).
+		AddInstruction(token2022.NewHarvestWithheldTokensToMint2022Instruction(mint, source, dest).Build()).
+		AddSigner(token2022.NewPrivateKeySigner(owner))
+	sender := token2022.NewSender(client).SetMetrics(metrics).SetPollInterval(time.Millisecond)
+	if _, err := sender.Send(context.Background(), builder); err != nil {
+		t.Fatalf("Error sending: %v", err)
+	}
+
+	if got := testutil.ToFloat64(metrics.counters[token2022.MetricTransactionsSent]); got != 1 {
+		t.Errorf("Expected 1 sent transaction, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.counters[token2022.MetricTransactionsConfirmed].WithLabelValues("confirmed")); got != 1 {
+		t.Errorf("Expected 1 confirmed transaction, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.counters[token2022.MetricFeeAccountsHarvested].WithLabelValues("HarvestWithheldTokensToMint")); got != 2 {
+		t.Errorf("Expected 2 harvested accounts, got %v", got)
+	}
+	if got := testutil.CollectAndCount(metrics.histograms[token2022.MetricRPCSeconds]); got < 3 {
+		t.Errorf("Expected RPC durations for at least 3 methods, got %d", got)
+	}
+	if got := testutil.CollectAndCount(metrics.histograms[token2022.MetricConfirmationSeconds]); got != 1 {
+		t.Errorf("Expected a confirmation time, got %d series", got)
+	}
+}
+
+func TestMetricsIgnoresUnknown(t *testing.T) {
+	metrics := mustMetrics(t, prometheus.NewRegistry())
+	metrics.Add("unknown_total", 1)
+	metrics.Add(token2022.MetricRetries, 1)
+	metrics.Add(token2022.MetricRetries, -1, "request")
+	metrics.Observe(token2022.MetricTransactionsSent, 1)
+	if got := testutil.CollectAndCount(metrics.counters[token2022.MetricRetries]); got != 0 {
+		t.Errorf("Expected no retries, got %d series", got)
+	}
+
+	if _, err := NewMetrics(prometheus.NewRegistry()); err != nil {
+		t.Fatalf("Error creating metrics: %v", err)
+	}
+	reg := prometheus.NewRegistry()
+	mustMetrics(t, reg)
+	if _, err := NewMetrics(reg); err == nil {
+		t.Errorf("Expected registering twice to fail")
+	}
+}
+
+func mustMetrics(t *testing.T, reg prometheus.Registerer) *Metrics {
+	t.Helper()
+	metrics, err := NewMetrics(reg)
+	if err != nil {
+		t.Fatalf("Error creating metrics: %v", err)
+	}
+	return metrics
+}
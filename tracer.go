@@ -0,0 +1,36 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+)
+
+// Tracer starts a span named name with the given attributes, alternating
+// keys and values, and returns the span's context and a function ending
+// the span with the error of the operation, or nil. TxBuilder and Sender
+// trace building, signing, sending and confirmation with it; package
+// tokenotel provides an OpenTelemetry Tracer and traces RPC calls.
+type Tracer interface {
+	Start(ctx context.Context, name string, attrs ...any) (context.Context, func(err error))
+}
+
+// startSpan starts a span with tracer when it is set.
+func startSpan(ctx context.Context, tracer Tracer, name string, attrs ...any) (context.Context, func(error)) {
+	if tracer == nil {
+		return ctx, func(error) {}
+	}
+	return tracer.Start(ctx, name, attrs...)
+}
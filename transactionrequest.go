@@ -0,0 +1,246 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// maxTransactionRequestBody bounds the body of a transaction request,
+// which only carries the customer's account.
+const maxTransactionRequestBody = 4 << 10
+
+// SolanaPayClient is the RPC used to build Solana Pay transactions: the
+// epoch selects the transfer fee of mints with the TransferFeeConfig
+// extension.
+type SolanaPayClient interface {
+	RPCClient
+	EpochClient
+}
+
+var _ SolanaPayClient = (*rpc.Client)(nil)
+
+// Payment is what a Solana Pay transaction request charges a customer.
+type Payment struct {
+	// Recipient is the wallet paid. Its associated token account is
+	// created, at the customer's expense, when it does not exist yet.
+	Recipient solana.PublicKey
+	Mint      solana.PublicKey
+	// Amount is the raw amount transferred from the customer.
+	Amount uint64
+	// FeeOnTop makes the customer also pay the transfer fee of the mint,
+	// so that Recipient receives Amount.
+	FeeOnTop bool
+	// References are added to the transfer as read-only accounts, so that
+	// the payment can be found with getSignaturesForAddress.
+	References []solana.PublicKey
+	// Memo is logged with the Memo program before the transfer.
+	Memo string
+	// Message is shown by the wallet with the transaction.
+	Message string
+	// AllowOwnerOffCurve accepts a Recipient off the ed25519 curve, such
+	// as a program derived address, which BuildTransaction otherwise
+	// rejects with ErrOwnerOffCurve.
+	AllowOwnerOffCurve bool
+}
+
+// PaymentFunc returns the payment of a transaction request made by
+// account, the customer's wallet. An error is reported to the wallet.
+type PaymentFunc func(ctx context.Context, r *http.Request, account solana.PublicKey) (*Payment, error)
+
+// TransactionRequestHandler serves Solana Pay transaction requests: GET
+// returns the merchant's label and icon, and POST returns the unsigned
+// payment transaction for the customer's wallet to sign and send.
+type TransactionRequestHandler struct {
+	client     SolanaPayClient
+	label      string
+	icon       string
+	payment    PaymentFunc
+	commitment rpc.CommitmentType
+}
+
+// NewTransactionRequestHandler creates a handler showing label and the
+// icon at the icon URL, charging the payment returned by payment.
+func NewTransactionRequestHandler(client SolanaPayClient, label, icon string, payment PaymentFunc) *TransactionRequestHandler {
+	return &TransactionRequestHandler{
+		client:     client,
+		label:      label,
+		icon:       icon,
+		payment:    payment,
+		commitment: rpc.CommitmentConfirmed,
+	}
+}
+
+// SetCommitment sets the commitment of the accounts and blockhash read.
+// The default is confirmed.
+func (h *TransactionRequestHandler) SetCommitment(commitment rpc.CommitmentType) *TransactionRequestHandler {
+	h.commitment = commitment
+	return h
+}
+
+type transactionRequestMetadata struct {
+	Label string `json:"label"`
+	Icon  string `json:"icon"`
+}
+
+type transactionRequestBody struct {
+	Account string `json:"account"`
+}
+
+type transactionRequestResponse struct {
+	Transaction string `json:"transaction"`
+	Message     string `json:"message,omitempty"`
+}
+
+type transactionRequestError struct {
+	Error string `json:"error"`
+}
+
+func (h *TransactionRequestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Wallets may call from web views, so requests are allowed from any
+	// origin.
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+
+	switch r.Method {
+	case http.MethodOptions:
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, transactionRequestMetadata{Label: h.label, Icon: h.icon})
+	case http.MethodPost:
+		var body transactionRequestBody
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxTransactionRequestBody)).Decode(&body); err != nil {
+			writeJSON(w, http.StatusBadRequest, transactionRequestError{fmt.Sprintf("invalid request: %v", err)})
+			return
+		}
+		account, err := solana.PublicKeyFromBase58(body.Account)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, transactionRequestError{fmt.Sprintf("invalid account %q", body.Account)})
+			return
+		}
+		payment, err := h.payment(r.Context(), r, account)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, transactionRequestError{err.Error()})
+			return
+		}
+		tx, err := h.BuildTransaction(r.Context(), account, payment)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, transactionRequestError{err.Error()})
+			return
+		}
+		encoded, err := EncodeTransaction(tx)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, transactionRequestError{err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, transactionRequestResponse{Transaction: encoded, Message: payment.Message})
+	default:
+		w.Header().Set("Allow", "GET, POST, OPTIONS")
+		writeJSON(w, http.StatusMethodNotAllowed, transactionRequestError{"method not allowed"})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// BuildTransaction returns the unsigned transaction paying payment from
+// the associated token account of account, which is also the fee payer.
+// It creates the recipient's associated token account if needed, logs
+// the memo and transfers with TransferCheckedWithFee for mints with a
+// transfer fee.
+func (h *TransactionRequestHandler) BuildTransaction(ctx context.Context, account solana.PublicKey, payment *Payment) (*solana.Transaction, error) {
+	if payment == nil {
+		return nil, errNotSet("payment")
+	}
+	if payment.Recipient.IsZero() {
+		return nil, errNotSet("Recipient")
+	}
+	if payment.Mint.IsZero() {
+		return nil, errNotSet("Mint")
+	}
+	if err := ValidateOwner(payment.Recipient, payment.AllowOwnerOffCurve); err != nil {
+		return nil, err
+	}
+	mint, err := FetchMint(ctx, h.client, payment.Mint, h.commitment)
+	if err != nil {
+		return nil, fmt.Errorf("error while fetching mint: %w", err)
+	}
+	source, _, err := FindAssociatedTokenAddress2022(account, payment.Mint)
+	if err != nil {
+		return nil, err
+	}
+	destination, _, err := FindAssociatedTokenAddress2022(payment.Recipient, payment.Mint)
+	if err != nil {
+		return nil, err
+	}
+
+	var instructions []solana.Instruction
+	_, err = h.client.GetAccountInfoWithOpts(ctx, destination, &rpc.GetAccountInfoOpts{Commitment: h.commitment})
+	switch {
+	case errors.Is(err, rpc.ErrNotFound):
+		instructions = append(instructions, NewCreate2022Instruction(account, payment.Recipient, payment.Mint).
+			SetIdempotent(true).
+			SetAllowOwnerOffCurve(payment.AllowOwnerOffCurve).
+			Build())
+	case err != nil:
+		return nil, fmt.Errorf("error while fetching recipient account: %w", err)
+	}
+	if payment.Memo != "" {
+		instructions = append(instructions, NewMemoInstruction(payment.Memo))
+	}
+
+	references := make([]*solana.AccountMeta, len(payment.References))
+	for i, reference := range payment.References {
+		references[i] = solana.Meta(reference)
+	}
+	config, hasFee, err := mint.TransferFeeConfig()
+	if err != nil {
+		return nil, err
+	}
+	if !hasFee {
+		instructions = append(instructions, NewTransferChecked2022Instruction(payment.Amount, mint.Decimals, source, payment.Mint, destination, account).
+			SetAdditionalAccounts(references...).Build())
+	} else {
+		fee, _, err := config.CurrentFee(ctx, h.client, h.commitment)
+		if err != nil {
+			return nil, err
+		}
+		amount := payment.Amount
+		if payment.FeeOnTop {
+			if amount, err = fee.PreFeeAmount(amount); err != nil {
+				return nil, err
+			}
+		}
+		instructions = append(instructions, NewTransferCheckedWithFee2022Instruction(amount, mint.Decimals, fee.Fee(amount), source, payment.Mint, destination, account).
+			SetAdditionalAccounts(references...).Build())
+	}
+
+	return NewTxBuilder(h.client).
+		SetFeePayer(account).
+		SetCommitment(h.commitment).
+		AddInstruction(instructions...).
+		Build(ctx)
+}
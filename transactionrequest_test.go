@@ -0,0 +1,160 @@
+package token2022
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// transferFeeMint encodes a mint whose fee changes from older to newer at
+// epoch 10.
+func transferFeeMint(older, newer TransferFee) []byte {
+	data := make([]byte, transferFeeConfigSize)
+	put := func(offset int, fee TransferFee) {
+		binary.LittleEndian.PutUint64(data[offset:], fee.Epoch)
+		binary.LittleEndian.PutUint64(data[offset+8:], fee.MaximumFee)
+		binary.LittleEndian.PutUint16(data[offset+16:], fee.BasisPoints)
+	}
+	put(72, older)
+	put(90, newer)
+	return EncodeMint(&Mint{Decimals: 6, IsInitialized: true, Extensions: []Extension{{Type: ExtensionTransferFeeConfig, Data: data}}})
+}
+
+func TestTransactionRequestHandler(t *testing.T) {
+	var (
+		customer  = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		merchant  = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		mint      = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		reference = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		client    = newMockRPC()
+	)
+	client.epoch = 12
+	client.setAccount(mint, solana.Token2022ProgramID, transferFeeMint(
+		TransferFee{BasisPoints: 10, MaximumFee: 1_000},
+		TransferFee{Epoch: 10, BasisPoints: 100, MaximumFee: 1_000_000},
+	))
+
+	handler := NewTransactionRequestHandler(client, "Coffee shop", "https://example.com/icon.svg",
+		func(ctx context.Context, r *http.Request, account solana.PublicKey) (*Payment, error) {
+			if account != customer {
+				t.Errorf("Expected account %s, got %s", customer, account)
+			}
+			return &Payment{
+				Recipient:  merchant,
+				Mint:       mint,
+				Amount:     99_000,
+				FeeOnTop:   true,
+				References: []solana.PublicKey{reference},
+				Memo:       "order#42",
+				Message:    "Thanks!",
+			}, nil
+		})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/pay", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"label":"Coffee shop"`) {
+		t.Errorf("Unexpected GET response %d %s", rec.Code, rec.Body)
+	}
+	if rec.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Errorf("Expected CORS header")
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/pay", strings.NewReader(`{"account":"`+customer.String()+`"}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Unexpected POST response %d %s", rec.Code, rec.Body)
+	}
+	var response transactionRequestResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if response.Message != "Thanks!" {
+		t.Errorf("Expected message, got %q", response.Message)
+	}
+	tx, err := DecodeTransaction(response.Transaction)
+	if err != nil {
+		t.Fatalf("DecodeTransaction: %v", err)
+	}
+	if tx.Message.AccountKeys[0] != customer {
+		t.Errorf("Expected the customer to pay fees, got %s", tx.Message.AccountKeys[0])
+	}
+	if len(tx.Message.Instructions) != 3 {
+		t.Fatalf("Expected create, memo and transfer, got %d instructions", len(tx.Message.Instructions))
+	}
+	if program := tx.Message.AccountKeys[tx.Message.Instructions[1].ProgramIDIndex]; program != solana.MemoProgramID ||
+		string(tx.Message.Instructions[1].Data) != "order#42" {
+		t.Errorf("Expected memo, got %s %q", program, tx.Message.Instructions[1].Data)
+	}
+
+	parsed, err := ParseTransaction(tx, nil)
+	if err != nil {
+		t.Fatalf("ParseTransaction: %v", err)
+	}
+	if len(parsed.Instructions) != 2 || parsed.Instructions[0].Name != "CreateIdempotent" {
+		t.Fatalf("Expected idempotent create and transfer, got %d instructions", len(parsed.Instructions))
+	}
+	transfer, ok := parsed.Instructions[1].Instruction.(*TransferCheckedWithFee2022)
+	if !ok {
+		t.Fatalf("Expected *TransferCheckedWithFee2022, got %T", parsed.Instructions[1].Instruction)
+	}
+	// At epoch 12 the fee is 1%, so 100000 are sent for 99000 to arrive.
+	if transfer.Amount != 100_000 || transfer.Fee != 1_000 {
+		t.Errorf("Expected 100000 with fee 1000, got %d with fee %d", transfer.Amount, transfer.Fee)
+	}
+	accounts := parsed.Instructions[1].Accounts
+	if last := accounts[len(accounts)-1]; last.PublicKey != reference || last.IsSigner || last.IsWritable {
+		t.Errorf("Expected read-only reference, got %+v", last)
+	}
+}
+
+func TestTransactionRequestHandlerErrors(t *testing.T) {
+	handler := NewTransactionRequestHandler(newMockRPC(), "Shop", "",
+		func(ctx context.Context, r *http.Request, account solana.PublicKey) (*Payment, error) {
+			return &Payment{Recipient: account, Mint: solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn"), Amount: 1}, nil
+		})
+	for _, tt := range []struct {
+		method, body string
+		status       int
+	}{
+		{http.MethodPost, `{"account":"invalid"}`, http.StatusBadRequest},
+		{http.MethodPost, `not json`, http.StatusBadRequest},
+		// The mint does not exist.
+		{http.MethodPost, `{"account":"nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun"}`, http.StatusInternalServerError},
+		{http.MethodPut, ``, http.StatusMethodNotAllowed},
+		{http.MethodOptions, ``, http.StatusNoContent},
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(tt.method, "/pay", strings.NewReader(tt.body)))
+		if rec.Code != tt.status {
+			t.Errorf("%s %s: expected status %d, got %d", tt.method, tt.body, tt.status, rec.Code)
+		}
+	}
+}
+
+func TestTransactionRequestRecipientOffCurve(t *testing.T) {
+	var (
+		customer = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		merchant = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		mint     = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		client   = newMockRPC()
+	)
+	client.setAccount(mint, solana.Token2022ProgramID, EncodeMint(&Mint{Decimals: 6, IsInitialized: true}))
+	handler := NewTransactionRequestHandler(client, "Shop", "", nil)
+	pda, _, _ := FindAssociatedTokenAddress2022(merchant, mint)
+
+	payment := &Payment{Recipient: pda, Mint: mint, Amount: 100}
+	if _, err := handler.BuildTransaction(context.Background(), customer, payment); !errors.Is(err, ErrOwnerOffCurve) {
+		t.Errorf("Expected ErrOwnerOffCurve for a recipient off the curve, got %v", err)
+	}
+	payment.AllowOwnerOffCurve = true
+	if _, err := handler.BuildTransaction(context.Background(), customer, payment); err != nil {
+		t.Errorf("Expected an allowed off-curve recipient to build, got %v", err)
+	}
+}
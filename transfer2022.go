@@ -0,0 +1,463 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	solana "github.com/gagliardetto/solana-go"
+	format "github.com/gagliardetto/solana-go/text/format"
+	treeout "github.com/gagliardetto/treeout"
+)
+
+// Transfer2022 transfers tokens from one account to another.
+//
+// Deprecated by the program for mints with extensions: use
+// TransferChecked2022, which also verifies the mint and decimals.
+type Transfer2022 struct {
+	// The amount of tokens to transfer.
+	Amount uint64
+
+	Source      solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Destination solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Owner       solana.PublicKey `bin:"-" borsh_skip:"true"`
+	// Signers are the signers of a multisig authority.
+	Signers []solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Source
+	// ··········· The source account
+	//
+	// [1] = [WRITE] Destination
+	// ··········· The destination account
+	//
+	// [2] = [SIGNER] Owner
+	// ··········· The source account's owner or delegate, or a multisig
+	//
+	// [3...] = [SIGNER] Signers
+	// ··········· M signer accounts when the authority is a multisig
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewTransfer2022InstructionBuilder creates a new `Transfer2022` instruction builder.
+func NewTransfer2022InstructionBuilder() *Transfer2022 {
+	nd := &Transfer2022{}
+	return nd
+}
+
+func (inst *Transfer2022) SetAmount(amount uint64) *Transfer2022 {
+	inst.Amount = amount
+	return inst
+}
+
+func (inst *Transfer2022) SetSource(source solana.PublicKey) *Transfer2022 {
+	inst.Source = source
+	return inst
+}
+
+func (inst *Transfer2022) SetDestination(destination solana.PublicKey) *Transfer2022 {
+	inst.Destination = destination
+	return inst
+}
+
+// SetOwner sets the authority. Pass the signers when the authority is a multisig.
+func (inst *Transfer2022) SetOwner(owner solana.PublicKey, multisigSigners ...solana.PublicKey) *Transfer2022 {
+	inst.Owner = owner
+	inst.Signers = multisigSigners
+	return inst
+}
+
+func (inst Transfer2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Source,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+		{
+			PublicKey:  inst.Destination,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+	}
+	keys = appendAuthority(keys, inst.Owner, inst.Signers)
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst Transfer2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *Transfer2022) Validate() error {
+	if inst.Source.IsZero() {
+		return errNotSet("Source")
+	}
+	if inst.Destination.IsZero() {
+		return errNotSet("Destination")
+	}
+	if inst.Owner.IsZero() {
+		return errNotSet("Owner")
+	}
+	if err := validateMultisigSigners(inst.Signers); err != nil {
+		return err
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+// ValidateOnChain validates the instruction and checks its accounts on
+// chain; see the package function ValidateOnChain.
+func (inst *Transfer2022) ValidateOnChain(ctx context.Context, client RPCClient) error {
+	return ValidateOnChain(ctx, client, inst)
+}
+
+// ValidateStrict validates the instruction and checks its values against
+// each other; see the package function ValidateStrict.
+func (inst *Transfer2022) ValidateStrict() error {
+	return ValidateStrict(inst)
+}
+
+func (inst *Transfer2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("Transfer2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=1]").ParentFunc(func(paramsBranch treeout.Branches) {
+						paramsBranch.Child(format.Param("Amount", inst.Amount))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("     source", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(format.Meta("destination", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(format.Meta("      owner", inst.AccountMetaSlice.Get(2)))
+						encodeRemainingAccounts(accountsBranch, "signer", inst.AccountMetaSlice, 3)
+					})
+				})
+		})
+}
+
+func (inst Transfer2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	return encoder.WriteUint64(inst.Amount, bin.LE)
+}
+
+func (inst *Transfer2022) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	if inst.Amount, err = decoder.ReadUint64(bin.LE); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst Transfer2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *Transfer2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("Transfer2022", accounts, 3); err != nil {
+		return err
+	}
+	inst.Source = accounts[0].PublicKey
+	inst.Destination = accounts[1].PublicKey
+	inst.Owner = accounts[2].PublicKey
+	inst.Signers = pubkeysOf(accounts[3:])
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst Transfer2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *Transfer2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewTransfer2022Instruction creates a new `Transfer2022` instruction.
+func NewTransfer2022Instruction(
+	amount uint64,
+	source solana.PublicKey,
+	destination solana.PublicKey,
+	owner solana.PublicKey,
+	multisigSigners ...solana.PublicKey,
+) *Transfer2022 {
+	return NewTransfer2022InstructionBuilder().
+		SetAmount(amount).
+		SetSource(source).
+		SetDestination(destination).
+		SetOwner(owner, multisigSigners...)
+}
+
+// TransferChecked2022 transfers tokens from one account to another, checking the
+// mint and the number of decimals.
+type TransferChecked2022 struct {
+	// The amount of tokens to transfer.
+	Amount uint64
+
+	// Expected number of base 10 digits to the right of the decimal place.
+	Decimals uint8
+
+	Source      solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Mint        solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Destination solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Owner       solana.PublicKey `bin:"-" borsh_skip:"true"`
+	// Signers are the signers of a multisig authority.
+	Signers []solana.PublicKey `bin:"-" borsh_skip:"true"`
+	// AdditionalAccounts are appended after the signers, for example the
+	// accounts required by a transfer hook program.
+	AdditionalAccounts []*solana.AccountMeta `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Source
+	// ··········· The source account
+	//
+	// [1] = [] Mint
+	// ··········· The token mint
+	//
+	// [2] = [WRITE] Destination
+	// ··········· The destination account
+	//
+	// [3] = [SIGNER] Owner
+	// ··········· The source account's owner or delegate, or a multisig
+	//
+	// [4...] = [SIGNER] Signers
+	// ··········· M signer accounts when the authority is a multisig
+	//
+	// [4+M...] = [] AdditionalAccounts
+	// ··········· Accounts required by the transfer hook, if any
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewTransferChecked2022InstructionBuilder creates a new `TransferChecked2022` instruction builder.
+func NewTransferChecked2022InstructionBuilder() *TransferChecked2022 {
+	nd := &TransferChecked2022{}
+	return nd
+}
+
+func (inst *TransferChecked2022) SetAmount(amount uint64) *TransferChecked2022 {
+	inst.Amount = amount
+	return inst
+}
+
+func (inst *TransferChecked2022) SetDecimals(decimals uint8) *TransferChecked2022 {
+	inst.Decimals = decimals
+	return inst
+}
+
+func (inst *TransferChecked2022) SetSource(source solana.PublicKey) *TransferChecked2022 {
+	inst.Source = source
+	return inst
+}
+
+func (inst *TransferChecked2022) SetMint(mint solana.PublicKey) *TransferChecked2022 {
+	inst.Mint = mint
+	return inst
+}
+
+func (inst *TransferChecked2022) SetDestination(destination solana.PublicKey) *TransferChecked2022 {
+	inst.Destination = destination
+	return inst
+}
+
+// SetOwner sets the authority. Pass the signers when the authority is a multisig.
+func (inst *TransferChecked2022) SetOwner(owner solana.PublicKey, multisigSigners ...solana.PublicKey) *TransferChecked2022 {
+	inst.Owner = owner
+	inst.Signers = multisigSigners
+	return inst
+}
+
+func (inst *TransferChecked2022) SetAdditionalAccounts(additionalAccounts ...*solana.AccountMeta) *TransferChecked2022 {
+	inst.AdditionalAccounts = additionalAccounts
+	return inst
+}
+
+func (inst TransferChecked2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Source,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+		{
+			PublicKey:  inst.Mint,
+			IsSigner:   false,
+			IsWritable: false,
+		},
+		{
+			PublicKey:  inst.Destination,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+	}
+	keys = appendAuthority(keys, inst.Owner, inst.Signers)
+	keys = append(keys, inst.AdditionalAccounts...)
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst TransferChecked2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *TransferChecked2022) Validate() error {
+	if inst.Source.IsZero() {
+		return errNotSet("Source")
+	}
+	if inst.Mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	if inst.Destination.IsZero() {
+		return errNotSet("Destination")
+	}
+	if inst.Owner.IsZero() {
+		return errNotSet("Owner")
+	}
+	if err := validateMultisigSigners(inst.Signers); err != nil {
+		return err
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+// ValidateOnChain validates the instruction and checks its accounts on
+// chain; see the package function ValidateOnChain.
+func (inst *TransferChecked2022) ValidateOnChain(ctx context.Context, client RPCClient) error {
+	return ValidateOnChain(ctx, client, inst)
+}
+
+// ValidateStrict validates the instruction and checks its values against
+// each other; see the package function ValidateStrict.
+func (inst *TransferChecked2022) ValidateStrict() error {
+	return ValidateStrict(inst)
+}
+
+func (inst *TransferChecked2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("TransferChecked2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=2]").ParentFunc(func(paramsBranch treeout.Branches) {
+						paramsBranch.Child(format.Param("  Amount", inst.Amount))
+						paramsBranch.Child(format.Param("Decimals", inst.Decimals))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("     source", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(format.Meta("       mint", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(format.Meta("destination", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(format.Meta("      owner", inst.AccountMetaSlice.Get(3)))
+						encodeRemainingAccounts(accountsBranch, "account", inst.AccountMetaSlice, 4)
+					})
+				})
+		})
+}
+
+func (inst TransferChecked2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	if err := encoder.WriteUint64(inst.Amount, bin.LE); err != nil {
+		return err
+	}
+	return encoder.WriteUint8(inst.Decimals)
+}
+
+func (inst *TransferChecked2022) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	if inst.Amount, err = decoder.ReadUint64(bin.LE); err != nil {
+		return err
+	}
+	if inst.Decimals, err = decoder.ReadUint8(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst TransferChecked2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *TransferChecked2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("TransferChecked2022", accounts, 4); err != nil {
+		return err
+	}
+	inst.Source = accounts[0].PublicKey
+	inst.Mint = accounts[1].PublicKey
+	inst.Destination = accounts[2].PublicKey
+	inst.Owner = accounts[3].PublicKey
+	signers, rest := splitSigners(accounts[4:])
+	inst.Signers = signers
+	inst.AdditionalAccounts = rest
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst TransferChecked2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *TransferChecked2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewTransferChecked2022Instruction creates a new `TransferChecked2022` instruction.
+func NewTransferChecked2022Instruction(
+	amount uint64,
+	decimals uint8,
+	source solana.PublicKey,
+	mint solana.PublicKey,
+	destination solana.PublicKey,
+	owner solana.PublicKey,
+	multisigSigners ...solana.PublicKey,
+) *TransferChecked2022 {
+	return NewTransferChecked2022InstructionBuilder().
+		SetAmount(amount).
+		SetDecimals(decimals).
+		SetSource(source).
+		SetMint(mint).
+		SetDestination(destination).
+		SetOwner(owner, multisigSigners...)
+}
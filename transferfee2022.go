@@ -0,0 +1,1148 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"fmt"
+
+	bin "github.com/gagliardetto/binary"
+	solana "github.com/gagliardetto/solana-go"
+	format "github.com/gagliardetto/solana-go/text/format"
+	treeout "github.com/gagliardetto/treeout"
+)
+
+// InitializeTransferFeeConfig2022 initializes the TransferFeeConfig extension of a mint.
+type InitializeTransferFeeConfig2022 struct {
+	// The authority that can update the fee, if any.
+	TransferFeeConfigAuthority *solana.PublicKey
+
+	// The authority that can withdraw withheld fees, if any.
+	WithdrawWithheldAuthority *solana.PublicKey
+
+	// The fee in hundredths of a percent of the transferred amount.
+	TransferFeeBasisPoints uint16
+
+	// The maximum fee per transfer, in base units.
+	MaximumFee uint64
+
+	Mint solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Mint
+	// ··········· The mint to initialize
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewInitializeTransferFeeConfig2022InstructionBuilder creates a new `InitializeTransferFeeConfig2022` instruction builder.
+func NewInitializeTransferFeeConfig2022InstructionBuilder() *InitializeTransferFeeConfig2022 {
+	nd := &InitializeTransferFeeConfig2022{}
+	return nd
+}
+
+func (inst *InitializeTransferFeeConfig2022) SetTransferFeeConfigAuthority(transferFeeConfigAuthority solana.PublicKey) *InitializeTransferFeeConfig2022 {
+	inst.TransferFeeConfigAuthority = &transferFeeConfigAuthority
+	return inst
+}
+
+func (inst *InitializeTransferFeeConfig2022) SetWithdrawWithheldAuthority(withdrawWithheldAuthority solana.PublicKey) *InitializeTransferFeeConfig2022 {
+	inst.WithdrawWithheldAuthority = &withdrawWithheldAuthority
+	return inst
+}
+
+func (inst *InitializeTransferFeeConfig2022) SetTransferFeeBasisPoints(transferFeeBasisPoints uint16) *InitializeTransferFeeConfig2022 {
+	inst.TransferFeeBasisPoints = transferFeeBasisPoints
+	return inst
+}
+
+func (inst *InitializeTransferFeeConfig2022) SetMaximumFee(maximumFee uint64) *InitializeTransferFeeConfig2022 {
+	inst.MaximumFee = maximumFee
+	return inst
+}
+
+func (inst *InitializeTransferFeeConfig2022) SetMint(mint solana.PublicKey) *InitializeTransferFeeConfig2022 {
+	inst.Mint = mint
+	return inst
+}
+
+func (inst InitializeTransferFeeConfig2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Mint,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+	}
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst InitializeTransferFeeConfig2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *InitializeTransferFeeConfig2022) Validate() error {
+	if inst.Mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	if inst.TransferFeeBasisPoints > MaxFeeBasisPoints {
+		return fmt.Errorf("transfer fee of %d basis points exceeds %d", inst.TransferFeeBasisPoints, MaxFeeBasisPoints)
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+// ValidateStrict validates the instruction and checks its values against
+// each other; see the package function ValidateStrict.
+func (inst *InitializeTransferFeeConfig2022) ValidateStrict() error {
+	return ValidateStrict(inst)
+}
+
+func (inst *InitializeTransferFeeConfig2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("InitializeTransferFeeConfig2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=4]").ParentFunc(func(paramsBranch treeout.Branches) {
+						paramsBranch.Child(format.Param("TransferFeeConfigAuthority", inst.TransferFeeConfigAuthority))
+						paramsBranch.Child(format.Param(" WithdrawWithheldAuthority", inst.WithdrawWithheldAuthority))
+						paramsBranch.Child(format.Param("    TransferFeeBasisPoints", inst.TransferFeeBasisPoints))
+						paramsBranch.Child(format.Param("                MaximumFee", inst.MaximumFee))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("mint", inst.AccountMetaSlice.Get(0)))
+					})
+				})
+		})
+}
+
+func (inst InitializeTransferFeeConfig2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	if err := WriteCOptionPubkey(encoder, COptionInstruction, inst.TransferFeeConfigAuthority); err != nil {
+		return err
+	}
+	if err := WriteCOptionPubkey(encoder, COptionInstruction, inst.WithdrawWithheldAuthority); err != nil {
+		return err
+	}
+	if err := encoder.WriteUint16(inst.TransferFeeBasisPoints, bin.LE); err != nil {
+		return err
+	}
+	return encoder.WriteUint64(inst.MaximumFee, bin.LE)
+}
+
+func (inst *InitializeTransferFeeConfig2022) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	if inst.TransferFeeConfigAuthority, err = ReadCOptionPubkey(decoder, COptionInstruction); err != nil {
+		return err
+	}
+	if inst.WithdrawWithheldAuthority, err = ReadCOptionPubkey(decoder, COptionInstruction); err != nil {
+		return err
+	}
+	if inst.TransferFeeBasisPoints, err = decoder.ReadUint16(bin.LE); err != nil {
+		return err
+	}
+	if inst.MaximumFee, err = decoder.ReadUint64(bin.LE); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst InitializeTransferFeeConfig2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *InitializeTransferFeeConfig2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("InitializeTransferFeeConfig2022", accounts, 1); err != nil {
+		return err
+	}
+	inst.Mint = accounts[0].PublicKey
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst InitializeTransferFeeConfig2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *InitializeTransferFeeConfig2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewInitializeTransferFeeConfig2022Instruction creates a new `InitializeTransferFeeConfig2022` instruction.
+func NewInitializeTransferFeeConfig2022Instruction(
+	transferFeeConfigAuthority *solana.PublicKey,
+	withdrawWithheldAuthority *solana.PublicKey,
+	transferFeeBasisPoints uint16,
+	maximumFee uint64,
+	mint solana.PublicKey,
+) *InitializeTransferFeeConfig2022 {
+	inst := NewInitializeTransferFeeConfig2022InstructionBuilder().
+		SetTransferFeeBasisPoints(transferFeeBasisPoints).
+		SetMaximumFee(maximumFee).
+		SetMint(mint)
+	inst.TransferFeeConfigAuthority = transferFeeConfigAuthority
+	inst.WithdrawWithheldAuthority = withdrawWithheldAuthority
+	return inst
+}
+
+// TransferCheckedWithFee2022 transfers tokens like TransferChecked2022 and checks that the
+// fee withheld by the mint equals Fee.
+type TransferCheckedWithFee2022 struct {
+	// The amount of tokens to transfer.
+	Amount uint64
+
+	// Expected number of base 10 digits to the right of the decimal place.
+	Decimals uint8
+
+	// The expected fee, calculated from the mint's transfer fee config.
+	Fee uint64
+
+	Source      solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Mint        solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Destination solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Owner       solana.PublicKey `bin:"-" borsh_skip:"true"`
+	// Signers are the signers of a multisig authority.
+	Signers []solana.PublicKey `bin:"-" borsh_skip:"true"`
+	// AdditionalAccounts are appended after the signers, for example the
+	// accounts required by a transfer hook program.
+	AdditionalAccounts []*solana.AccountMeta `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Source
+	// ··········· The source account
+	//
+	// [1] = [] Mint
+	// ··········· The token mint
+	//
+	// [2] = [WRITE] Destination
+	// ··········· The destination account
+	//
+	// [3] = [SIGNER] Owner
+	// ··········· The source account's owner or delegate, or a multisig
+	//
+	// [4...] = [SIGNER] Signers
+	// ··········· M signer accounts when the authority is a multisig
+	//
+	// [4+M...] = [] AdditionalAccounts
+	// ··········· Accounts required by the transfer hook, if any
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewTransferCheckedWithFee2022InstructionBuilder creates a new `TransferCheckedWithFee2022` instruction builder.
+func NewTransferCheckedWithFee2022InstructionBuilder() *TransferCheckedWithFee2022 {
+	nd := &TransferCheckedWithFee2022{}
+	return nd
+}
+
+func (inst *TransferCheckedWithFee2022) SetAmount(amount uint64) *TransferCheckedWithFee2022 {
+	inst.Amount = amount
+	return inst
+}
+
+func (inst *TransferCheckedWithFee2022) SetDecimals(decimals uint8) *TransferCheckedWithFee2022 {
+	inst.Decimals = decimals
+	return inst
+}
+
+func (inst *TransferCheckedWithFee2022) SetFee(fee uint64) *TransferCheckedWithFee2022 {
+	inst.Fee = fee
+	return inst
+}
+
+func (inst *TransferCheckedWithFee2022) SetSource(source solana.PublicKey) *TransferCheckedWithFee2022 {
+	inst.Source = source
+	return inst
+}
+
+func (inst *TransferCheckedWithFee2022) SetMint(mint solana.PublicKey) *TransferCheckedWithFee2022 {
+	inst.Mint = mint
+	return inst
+}
+
+func (inst *TransferCheckedWithFee2022) SetDestination(destination solana.PublicKey) *TransferCheckedWithFee2022 {
+	inst.Destination = destination
+	return inst
+}
+
+// SetOwner sets the authority. Pass the signers when the authority is a multisig.
+func (inst *TransferCheckedWithFee2022) SetOwner(owner solana.PublicKey, multisigSigners ...solana.PublicKey) *TransferCheckedWithFee2022 {
+	inst.Owner = owner
+	inst.Signers = multisigSigners
+	return inst
+}
+
+func (inst *TransferCheckedWithFee2022) SetAdditionalAccounts(additionalAccounts ...*solana.AccountMeta) *TransferCheckedWithFee2022 {
+	inst.AdditionalAccounts = additionalAccounts
+	return inst
+}
+
+func (inst TransferCheckedWithFee2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Source,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+		{
+			PublicKey:  inst.Mint,
+			IsSigner:   false,
+			IsWritable: false,
+		},
+		{
+			PublicKey:  inst.Destination,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+	}
+	keys = appendAuthority(keys, inst.Owner, inst.Signers)
+	keys = append(keys, inst.AdditionalAccounts...)
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst TransferCheckedWithFee2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *TransferCheckedWithFee2022) Validate() error {
+	if inst.Source.IsZero() {
+		return errNotSet("Source")
+	}
+	if inst.Mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	if inst.Destination.IsZero() {
+		return errNotSet("Destination")
+	}
+	if inst.Owner.IsZero() {
+		return errNotSet("Owner")
+	}
+	if err := validateMultisigSigners(inst.Signers); err != nil {
+		return err
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+// ValidateOnChain validates the instruction and checks its accounts on
+// chain; see the package function ValidateOnChain.
+func (inst *TransferCheckedWithFee2022) ValidateOnChain(ctx context.Context, client RPCClient) error {
+	return ValidateOnChain(ctx, client, inst)
+}
+
+// ValidateStrict validates the instruction and checks its values against
+// each other; see the package function ValidateStrict.
+func (inst *TransferCheckedWithFee2022) ValidateStrict() error {
+	return ValidateStrict(inst)
+}
+
+func (inst *TransferCheckedWithFee2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("TransferCheckedWithFee2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=3]").ParentFunc(func(paramsBranch treeout.Branches) {
+						paramsBranch.Child(format.Param("  Amount", inst.Amount))
+						paramsBranch.Child(format.Param("Decimals", inst.Decimals))
+						paramsBranch.Child(format.Param("     Fee", inst.Fee))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("     source", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(format.Meta("       mint", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(format.Meta("destination", inst.AccountMetaSlice.Get(2)))
+						accountsBranch.Child(format.Meta("      owner", inst.AccountMetaSlice.Get(3)))
+						encodeRemainingAccounts(accountsBranch, "account", inst.AccountMetaSlice, 4)
+					})
+				})
+		})
+}
+
+func (inst TransferCheckedWithFee2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	if err := encoder.WriteUint64(inst.Amount, bin.LE); err != nil {
+		return err
+	}
+	if err := encoder.WriteUint8(inst.Decimals); err != nil {
+		return err
+	}
+	return encoder.WriteUint64(inst.Fee, bin.LE)
+}
+
+func (inst *TransferCheckedWithFee2022) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	if inst.Amount, err = decoder.ReadUint64(bin.LE); err != nil {
+		return err
+	}
+	if inst.Decimals, err = decoder.ReadUint8(); err != nil {
+		return err
+	}
+	if inst.Fee, err = decoder.ReadUint64(bin.LE); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst TransferCheckedWithFee2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *TransferCheckedWithFee2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("TransferCheckedWithFee2022", accounts, 4); err != nil {
+		return err
+	}
+	inst.Source = accounts[0].PublicKey
+	inst.Mint = accounts[1].PublicKey
+	inst.Destination = accounts[2].PublicKey
+	inst.Owner = accounts[3].PublicKey
+	signers, rest := splitSigners(accounts[4:])
+	inst.Signers = signers
+	inst.AdditionalAccounts = rest
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst TransferCheckedWithFee2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *TransferCheckedWithFee2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewTransferCheckedWithFee2022Instruction creates a new `TransferCheckedWithFee2022` instruction.
+func NewTransferCheckedWithFee2022Instruction(
+	amount uint64,
+	decimals uint8,
+	fee uint64,
+	source solana.PublicKey,
+	mint solana.PublicKey,
+	destination solana.PublicKey,
+	owner solana.PublicKey,
+	multisigSigners ...solana.PublicKey,
+) *TransferCheckedWithFee2022 {
+	return NewTransferCheckedWithFee2022InstructionBuilder().
+		SetAmount(amount).
+		SetDecimals(decimals).
+		SetFee(fee).
+		SetSource(source).
+		SetMint(mint).
+		SetDestination(destination).
+		SetOwner(owner, multisigSigners...)
+}
+
+// WithdrawWithheldTokensFromMint2022 withdraws the fees harvested to the mint.
+type WithdrawWithheldTokensFromMint2022 struct {
+	Mint                      solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Destination               solana.PublicKey `bin:"-" borsh_skip:"true"`
+	WithdrawWithheldAuthority solana.PublicKey `bin:"-" borsh_skip:"true"`
+	// Signers are the signers of a multisig authority.
+	Signers []solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Mint
+	// ··········· The token mint
+	//
+	// [1] = [WRITE] Destination
+	// ··········· The fee receiver account
+	//
+	// [2] = [SIGNER] WithdrawWithheldAuthority
+	// ··········· The mint's withdraw withheld authority, or a multisig
+	//
+	// [3...] = [SIGNER] Signers
+	// ··········· M signer accounts when the authority is a multisig
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewWithdrawWithheldTokensFromMint2022InstructionBuilder creates a new `WithdrawWithheldTokensFromMint2022` instruction builder.
+func NewWithdrawWithheldTokensFromMint2022InstructionBuilder() *WithdrawWithheldTokensFromMint2022 {
+	nd := &WithdrawWithheldTokensFromMint2022{}
+	return nd
+}
+
+func (inst *WithdrawWithheldTokensFromMint2022) SetMint(mint solana.PublicKey) *WithdrawWithheldTokensFromMint2022 {
+	inst.Mint = mint
+	return inst
+}
+
+func (inst *WithdrawWithheldTokensFromMint2022) SetDestination(destination solana.PublicKey) *WithdrawWithheldTokensFromMint2022 {
+	inst.Destination = destination
+	return inst
+}
+
+// SetWithdrawWithheldAuthority sets the authority. Pass the signers when the authority is a multisig.
+func (inst *WithdrawWithheldTokensFromMint2022) SetWithdrawWithheldAuthority(withdrawWithheldAuthority solana.PublicKey, multisigSigners ...solana.PublicKey) *WithdrawWithheldTokensFromMint2022 {
+	inst.WithdrawWithheldAuthority = withdrawWithheldAuthority
+	inst.Signers = multisigSigners
+	return inst
+}
+
+func (inst WithdrawWithheldTokensFromMint2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Mint,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+		{
+			PublicKey:  inst.Destination,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+	}
+	keys = appendAuthority(keys, inst.WithdrawWithheldAuthority, inst.Signers)
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst WithdrawWithheldTokensFromMint2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *WithdrawWithheldTokensFromMint2022) Validate() error {
+	if inst.Mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	if inst.Destination.IsZero() {
+		return errNotSet("Destination")
+	}
+	if inst.WithdrawWithheldAuthority.IsZero() {
+		return errNotSet("WithdrawWithheldAuthority")
+	}
+	if err := validateMultisigSigners(inst.Signers); err != nil {
+		return err
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+func (inst *WithdrawWithheldTokensFromMint2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("WithdrawWithheldTokensFromMint2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=0]").ParentFunc(func(paramsBranch treeout.Branches) {})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("                     mint", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(format.Meta("              destination", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(format.Meta("withdrawWithheldAuthority", inst.AccountMetaSlice.Get(2)))
+						encodeRemainingAccounts(accountsBranch, "signer", inst.AccountMetaSlice, 3)
+					})
+				})
+		})
+}
+
+func (inst WithdrawWithheldTokensFromMint2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	return nil
+}
+
+func (inst *WithdrawWithheldTokensFromMint2022) UnmarshalWithDecoder(decoder *bin.Decoder) error {
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst WithdrawWithheldTokensFromMint2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *WithdrawWithheldTokensFromMint2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("WithdrawWithheldTokensFromMint2022", accounts, 3); err != nil {
+		return err
+	}
+	inst.Mint = accounts[0].PublicKey
+	inst.Destination = accounts[1].PublicKey
+	inst.WithdrawWithheldAuthority = accounts[2].PublicKey
+	inst.Signers = pubkeysOf(accounts[3:])
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst WithdrawWithheldTokensFromMint2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *WithdrawWithheldTokensFromMint2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewWithdrawWithheldTokensFromMint2022Instruction creates a new `WithdrawWithheldTokensFromMint2022` instruction.
+func NewWithdrawWithheldTokensFromMint2022Instruction(
+	mint solana.PublicKey,
+	destination solana.PublicKey,
+	withdrawWithheldAuthority solana.PublicKey,
+	multisigSigners ...solana.PublicKey,
+) *WithdrawWithheldTokensFromMint2022 {
+	return NewWithdrawWithheldTokensFromMint2022InstructionBuilder().
+		SetMint(mint).
+		SetDestination(destination).
+		SetWithdrawWithheldAuthority(withdrawWithheldAuthority, multisigSigners...)
+}
+
+// WithdrawWithheldTokensFromAccounts2022 withdraws the fees withheld in token accounts.
+type WithdrawWithheldTokensFromAccounts2022 struct {
+	Mint                      solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Destination               solana.PublicKey `bin:"-" borsh_skip:"true"`
+	WithdrawWithheldAuthority solana.PublicKey `bin:"-" borsh_skip:"true"`
+	// Signers are the signers of a multisig authority.
+	Signers []solana.PublicKey `bin:"-" borsh_skip:"true"`
+	Sources []solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [] Mint
+	// ··········· The token mint
+	//
+	// [1] = [WRITE] Destination
+	// ··········· The fee receiver account
+	//
+	// [2] = [SIGNER] WithdrawWithheldAuthority
+	// ··········· The mint's withdraw withheld authority, or a multisig
+	//
+	// [3...] = [SIGNER] Signers
+	// ··········· M signer accounts when the authority is a multisig
+	//
+	// [3+M...] = [WRITE] Sources
+	// ··········· The accounts to withdraw withheld fees from
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewWithdrawWithheldTokensFromAccounts2022InstructionBuilder creates a new `WithdrawWithheldTokensFromAccounts2022` instruction builder.
+func NewWithdrawWithheldTokensFromAccounts2022InstructionBuilder() *WithdrawWithheldTokensFromAccounts2022 {
+	nd := &WithdrawWithheldTokensFromAccounts2022{}
+	return nd
+}
+
+func (inst *WithdrawWithheldTokensFromAccounts2022) SetMint(mint solana.PublicKey) *WithdrawWithheldTokensFromAccounts2022 {
+	inst.Mint = mint
+	return inst
+}
+
+func (inst *WithdrawWithheldTokensFromAccounts2022) SetDestination(destination solana.PublicKey) *WithdrawWithheldTokensFromAccounts2022 {
+	inst.Destination = destination
+	return inst
+}
+
+// SetWithdrawWithheldAuthority sets the authority. Pass the signers when the authority is a multisig.
+func (inst *WithdrawWithheldTokensFromAccounts2022) SetWithdrawWithheldAuthority(withdrawWithheldAuthority solana.PublicKey, multisigSigners ...solana.PublicKey) *WithdrawWithheldTokensFromAccounts2022 {
+	inst.WithdrawWithheldAuthority = withdrawWithheldAuthority
+	inst.Signers = multisigSigners
+	return inst
+}
+
+func (inst *WithdrawWithheldTokensFromAccounts2022) SetSources(sources ...solana.PublicKey) *WithdrawWithheldTokensFromAccounts2022 {
+	inst.Sources = sources
+	return inst
+}
+
+func (inst WithdrawWithheldTokensFromAccounts2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Mint,
+			IsSigner:   false,
+			IsWritable: false,
+		},
+		{
+			PublicKey:  inst.Destination,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+	}
+	keys = appendAuthority(keys, inst.WithdrawWithheldAuthority, inst.Signers)
+	for _, account := range inst.Sources {
+		keys = append(keys, solana.Meta(account).WRITE())
+	}
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst WithdrawWithheldTokensFromAccounts2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *WithdrawWithheldTokensFromAccounts2022) Validate() error {
+	if inst.Mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	if inst.Destination.IsZero() {
+		return errNotSet("Destination")
+	}
+	if inst.WithdrawWithheldAuthority.IsZero() {
+		return errNotSet("WithdrawWithheldAuthority")
+	}
+	if err := validateMultisigSigners(inst.Signers); err != nil {
+		return err
+	}
+	if len(inst.Sources) == 0 {
+		return errNotSet("Sources")
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+func (inst *WithdrawWithheldTokensFromAccounts2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("WithdrawWithheldTokensFromAccounts2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=0]").ParentFunc(func(paramsBranch treeout.Branches) {})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("                     mint", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(format.Meta("              destination", inst.AccountMetaSlice.Get(1)))
+						accountsBranch.Child(format.Meta("withdrawWithheldAuthority", inst.AccountMetaSlice.Get(2)))
+						encodeRemainingAccounts(accountsBranch, "account", inst.AccountMetaSlice, 3)
+					})
+				})
+		})
+}
+
+func (inst WithdrawWithheldTokensFromAccounts2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	return encoder.WriteUint8(uint8(len(inst.Sources)))
+}
+
+func (inst *WithdrawWithheldTokensFromAccounts2022) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	count, err := decoder.ReadUint8()
+	if err != nil {
+		return err
+	}
+	inst.Sources = make([]solana.PublicKey, count)
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst WithdrawWithheldTokensFromAccounts2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *WithdrawWithheldTokensFromAccounts2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("WithdrawWithheldTokensFromAccounts2022", accounts, 3); err != nil {
+		return err
+	}
+	inst.Mint = accounts[0].PublicKey
+	inst.Destination = accounts[1].PublicKey
+	inst.WithdrawWithheldAuthority = accounts[2].PublicKey
+	rest := accounts[3:]
+	if len(rest) < len(inst.Sources) {
+		return fmt.Errorf("%w: WithdrawWithheldTokensFromAccounts2022: expected %d source accounts, got %d", ErrMalformedInstruction, len(inst.Sources), len(rest))
+	}
+	split := len(rest) - len(inst.Sources)
+	inst.Signers = pubkeysOf(rest[:split])
+	inst.Sources = pubkeysOf(rest[split:])
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst WithdrawWithheldTokensFromAccounts2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *WithdrawWithheldTokensFromAccounts2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewWithdrawWithheldTokensFromAccounts2022Instruction creates a new `WithdrawWithheldTokensFromAccounts2022` instruction.
+func NewWithdrawWithheldTokensFromAccounts2022Instruction(
+	mint solana.PublicKey,
+	destination solana.PublicKey,
+	sources []solana.PublicKey,
+	withdrawWithheldAuthority solana.PublicKey,
+	multisigSigners ...solana.PublicKey,
+) *WithdrawWithheldTokensFromAccounts2022 {
+	return NewWithdrawWithheldTokensFromAccounts2022InstructionBuilder().
+		SetMint(mint).
+		SetDestination(destination).
+		SetSources(sources...).
+		SetWithdrawWithheldAuthority(withdrawWithheldAuthority, multisigSigners...)
+}
+
+// HarvestWithheldTokensToMint2022 moves the fees withheld in token accounts to the mint. Anyone
+// can harvest.
+type HarvestWithheldTokensToMint2022 struct {
+	Mint    solana.PublicKey   `bin:"-" borsh_skip:"true"`
+	Sources []solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Mint
+	// ··········· The token mint
+	//
+	// [1...] = [WRITE] Sources
+	// ··········· The accounts to harvest withheld fees from
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewHarvestWithheldTokensToMint2022InstructionBuilder creates a new `HarvestWithheldTokensToMint2022` instruction builder.
+func NewHarvestWithheldTokensToMint2022InstructionBuilder() *HarvestWithheldTokensToMint2022 {
+	nd := &HarvestWithheldTokensToMint2022{}
+	return nd
+}
+
+func (inst *HarvestWithheldTokensToMint2022) SetMint(mint solana.PublicKey) *HarvestWithheldTokensToMint2022 {
+	inst.Mint = mint
+	return inst
+}
+
+func (inst *HarvestWithheldTokensToMint2022) SetSources(sources ...solana.PublicKey) *HarvestWithheldTokensToMint2022 {
+	inst.Sources = sources
+	return inst
+}
+
+func (inst HarvestWithheldTokensToMint2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Mint,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+	}
+	for _, account := range inst.Sources {
+		keys = append(keys, solana.Meta(account).WRITE())
+	}
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst HarvestWithheldTokensToMint2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *HarvestWithheldTokensToMint2022) Validate() error {
+	if inst.Mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	if len(inst.Sources) == 0 {
+		return errNotSet("Sources")
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+func (inst *HarvestWithheldTokensToMint2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("HarvestWithheldTokensToMint2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=0]").ParentFunc(func(paramsBranch treeout.Branches) {})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("mint", inst.AccountMetaSlice.Get(0)))
+						encodeRemainingAccounts(accountsBranch, "source", inst.AccountMetaSlice, 1)
+					})
+				})
+		})
+}
+
+func (inst HarvestWithheldTokensToMint2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	return nil
+}
+
+func (inst *HarvestWithheldTokensToMint2022) UnmarshalWithDecoder(decoder *bin.Decoder) error {
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst HarvestWithheldTokensToMint2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *HarvestWithheldTokensToMint2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("HarvestWithheldTokensToMint2022", accounts, 1); err != nil {
+		return err
+	}
+	inst.Mint = accounts[0].PublicKey
+	inst.Sources = pubkeysOf(accounts[1:])
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst HarvestWithheldTokensToMint2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *HarvestWithheldTokensToMint2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewHarvestWithheldTokensToMint2022Instruction creates a new `HarvestWithheldTokensToMint2022` instruction.
+func NewHarvestWithheldTokensToMint2022Instruction(
+	mint solana.PublicKey,
+	sources ...solana.PublicKey,
+) *HarvestWithheldTokensToMint2022 {
+	return NewHarvestWithheldTokensToMint2022InstructionBuilder().
+		SetMint(mint).
+		SetSources(sources...)
+}
+
+// SetTransferFee2022 sets a new transfer fee, which takes effect two epochs later.
+type SetTransferFee2022 struct {
+	// The fee in hundredths of a percent of the transferred amount.
+	TransferFeeBasisPoints uint16
+
+	// The maximum fee per transfer, in base units.
+	MaximumFee uint64
+
+	Mint                       solana.PublicKey `bin:"-" borsh_skip:"true"`
+	TransferFeeConfigAuthority solana.PublicKey `bin:"-" borsh_skip:"true"`
+	// Signers are the signers of a multisig authority.
+	Signers []solana.PublicKey `bin:"-" borsh_skip:"true"`
+
+	// [0] = [WRITE] Mint
+	// ··········· The token mint
+	//
+	// [1] = [SIGNER] TransferFeeConfigAuthority
+	// ··········· The mint's transfer fee config authority, or a multisig
+	//
+	// [2...] = [SIGNER] Signers
+	// ··········· M signer accounts when the authority is a multisig
+	solana.AccountMetaSlice `bin:"-" borsh_skip:"true"`
+}
+
+// NewSetTransferFee2022InstructionBuilder creates a new `SetTransferFee2022` instruction builder.
+func NewSetTransferFee2022InstructionBuilder() *SetTransferFee2022 {
+	nd := &SetTransferFee2022{}
+	return nd
+}
+
+func (inst *SetTransferFee2022) SetTransferFeeBasisPoints(transferFeeBasisPoints uint16) *SetTransferFee2022 {
+	inst.TransferFeeBasisPoints = transferFeeBasisPoints
+	return inst
+}
+
+func (inst *SetTransferFee2022) SetMaximumFee(maximumFee uint64) *SetTransferFee2022 {
+	inst.MaximumFee = maximumFee
+	return inst
+}
+
+func (inst *SetTransferFee2022) SetMint(mint solana.PublicKey) *SetTransferFee2022 {
+	inst.Mint = mint
+	return inst
+}
+
+// SetTransferFeeConfigAuthority sets the authority. Pass the signers when the authority is a multisig.
+func (inst *SetTransferFee2022) SetTransferFeeConfigAuthority(transferFeeConfigAuthority solana.PublicKey, multisigSigners ...solana.PublicKey) *SetTransferFee2022 {
+	inst.TransferFeeConfigAuthority = transferFeeConfigAuthority
+	inst.Signers = multisigSigners
+	return inst
+}
+
+func (inst SetTransferFee2022) Build() *Instruction {
+
+	keys := []*solana.AccountMeta{
+		{
+			PublicKey:  inst.Mint,
+			IsSigner:   false,
+			IsWritable: true,
+		},
+	}
+	keys = appendAuthority(keys, inst.TransferFeeConfigAuthority, inst.Signers)
+
+	inst.AccountMetaSlice = keys
+
+	return newTokenInstruction(inst)
+}
+
+// ValidateAndBuild validates the instruction parameters and accounts.
+// If there is a validation error, return the error.
+// Otherwise, build and return the instruction.
+func (inst SetTransferFee2022) ValidateAndBuild() (*Instruction, error) {
+	if err := inst.Validate(); err != nil {
+		return nil, err
+	}
+	return inst.Build(), nil
+}
+
+func (inst *SetTransferFee2022) Validate() error {
+	if inst.Mint.IsZero() {
+		return errNotSet("Mint")
+	}
+	if inst.TransferFeeConfigAuthority.IsZero() {
+		return errNotSet("TransferFeeConfigAuthority")
+	}
+	if err := validateMultisigSigners(inst.Signers); err != nil {
+		return err
+	}
+	if inst.TransferFeeBasisPoints > MaxFeeBasisPoints {
+		return fmt.Errorf("transfer fee of %d basis points exceeds %d", inst.TransferFeeBasisPoints, MaxFeeBasisPoints)
+	}
+	return checkAccounts(inst, inst.AccountMetaSlice)
+}
+
+// ValidateStrict validates the instruction and checks its values against
+// each other; see the package function ValidateStrict.
+func (inst *SetTransferFee2022) ValidateStrict() error {
+	return ValidateStrict(inst)
+}
+
+func (inst *SetTransferFee2022) EncodeToTree(parent treeout.Branches) {
+	parent.Child(format.Program(TokenProgramName, solana.Token2022ProgramID)).
+		//
+		ParentFunc(func(programBranch treeout.Branches) {
+			programBranch.Child(format.Instruction("SetTransferFee2022")).
+				//
+				ParentFunc(func(instructionBranch treeout.Branches) {
+
+					// Parameters of the instruction:
+					instructionBranch.Child("Params[len=2]").ParentFunc(func(paramsBranch treeout.Branches) {
+						paramsBranch.Child(format.Param("TransferFeeBasisPoints", inst.TransferFeeBasisPoints))
+						paramsBranch.Child(format.Param("            MaximumFee", inst.MaximumFee))
+					})
+
+					// Accounts of the instruction:
+					instructionBranch.Child(fmt.Sprintf("Accounts[len=%d]", len(inst.AccountMetaSlice))).ParentFunc(func(accountsBranch treeout.Branches) {
+						accountsBranch.Child(format.Meta("                      mint", inst.AccountMetaSlice.Get(0)))
+						accountsBranch.Child(format.Meta("transferFeeConfigAuthority", inst.AccountMetaSlice.Get(1)))
+						encodeRemainingAccounts(accountsBranch, "signer", inst.AccountMetaSlice, 2)
+					})
+				})
+		})
+}
+
+func (inst SetTransferFee2022) MarshalWithEncoder(encoder *bin.Encoder) error {
+	if err := encoder.WriteUint16(inst.TransferFeeBasisPoints, bin.LE); err != nil {
+		return err
+	}
+	return encoder.WriteUint64(inst.MaximumFee, bin.LE)
+}
+
+func (inst *SetTransferFee2022) UnmarshalWithDecoder(decoder *bin.Decoder) (err error) {
+	if inst.TransferFeeBasisPoints, err = decoder.ReadUint16(bin.LE); err != nil {
+		return err
+	}
+	if inst.MaximumFee, err = decoder.ReadUint64(bin.LE); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetAccounts implements the AccountMetaGettable interface
+func (inst SetTransferFee2022) GetAccounts() []*solana.AccountMeta {
+	return inst.AccountMetaSlice
+}
+
+// SetAccounts sets the accounts from an account list, such as the accounts
+// of a decoded instruction.
+func (inst *SetTransferFee2022) SetAccounts(accounts []*solana.AccountMeta) error {
+	if err := checkAccountCount("SetTransferFee2022", accounts, 2); err != nil {
+		return err
+	}
+	inst.Mint = accounts[0].PublicKey
+	inst.TransferFeeConfigAuthority = accounts[1].PublicKey
+	inst.Signers = pubkeysOf(accounts[2:])
+	inst.AccountMetaSlice = accounts
+	return nil
+}
+
+// MarshalJSON encodes the instruction arguments and accounts as a JSON
+// object with base58 public keys and u64 values as strings.
+func (inst SetTransferFee2022) MarshalJSON() ([]byte, error) {
+	return marshalJSONFields(&inst)
+}
+
+func (inst *SetTransferFee2022) UnmarshalJSON(data []byte) error {
+	return unmarshalJSONFields(data, inst)
+}
+
+// NewSetTransferFee2022Instruction creates a new `SetTransferFee2022` instruction.
+func NewSetTransferFee2022Instruction(
+	transferFeeBasisPoints uint16,
+	maximumFee uint64,
+	mint solana.PublicKey,
+	transferFeeConfigAuthority solana.PublicKey,
+	multisigSigners ...solana.PublicKey,
+) *SetTransferFee2022 {
+	return NewSetTransferFee2022InstructionBuilder().
+		SetTransferFeeBasisPoints(transferFeeBasisPoints).
+		SetMaximumFee(maximumFee).
+		SetMint(mint).
+		SetTransferFeeConfigAuthority(transferFeeConfigAuthority, multisigSigners...)
+}
@@ -0,0 +1,395 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// executeDiscriminator is the discriminator of the Execute instruction of
+// the transfer hook interface, which also tags its entry in the
+// ExtraAccountMetaList account.
+var executeDiscriminator = func() []byte {
+	sum := sha256.Sum256([]byte("spl-transfer-hook-interface:execute"))
+	return sum[:8]
+}()
+
+// ExtraAccountMeta is an entry of a transfer hook's ExtraAccountMetaList:
+// an account passed to the hook, either given literally or derived from the
+// accounts and data of the Execute instruction.
+type ExtraAccountMeta struct {
+	// Discriminator is 0 for a literal key, 1 for an address derived from
+	// the hook program, 2 for a key read from instruction or account data,
+	// and 128+i for an address derived from the program at account index i.
+	Discriminator uint8
+	AddressConfig [32]byte
+	IsSigner      bool
+	IsWritable    bool
+}
+
+// extraAccountMetaSize is the size of an encoded ExtraAccountMeta.
+const extraAccountMetaSize = 35
+
+// FindExtraAccountMetasAddress derives the ExtraAccountMetaList account of
+// mint for a transfer hook program.
+func FindExtraAccountMetasAddress(mint, hookProgram solana.PublicKey) (solana.PublicKey, uint8, error) {
+	return solana.FindProgramAddress([][]byte{[]byte("extra-account-metas"), mint.Bytes()}, hookProgram)
+}
+
+// DecodeExtraAccountMetaList decodes the Execute entry of an
+// ExtraAccountMetaList account.
+func DecodeExtraAccountMetaList(data []byte) ([]ExtraAccountMeta, error) {
+	for offset := 0; offset+12 <= len(data); {
+		discriminator := data[offset : offset+8]
+		length := int(binary.LittleEndian.Uint32(data[offset+8 : offset+12]))
+		value := data[offset+12:]
+		if length > len(value) {
+			return nil, fmt.Errorf("%w: extra account meta list entry of %d bytes overflows the account", ErrInvalidAccountData, length)
+		}
+		value = value[:length]
+		offset += 12 + length
+		if !bytes.Equal(discriminator, executeDiscriminator) {
+			continue
+		}
+		if len(value) < 4 {
+			return nil, fmt.Errorf("%w: extra account meta list entry too short", ErrInvalidAccountData)
+		}
+		count := int(binary.LittleEndian.Uint32(value))
+		if len(value) != 4+count*extraAccountMetaSize {
+			return nil, fmt.Errorf("%w: invalid extra account meta list length: %d bytes for %d entries", ErrInvalidAccountData, len(value), count)
+		}
+		metas := make([]ExtraAccountMeta, count)
+		for i := range metas {
+			entry := value[4+i*extraAccountMetaSize:]
+			metas[i].Discriminator = entry[0]
+			copy(metas[i].AddressConfig[:], entry[1:33])
+			metas[i].IsSigner = entry[33] != 0
+			metas[i].IsWritable = entry[34] != 0
+		}
+		return metas, nil
+	}
+	return nil, fmt.Errorf("%w: extra account meta list has no Execute entry", ErrInvalidAccountData)
+}
+
+// accountDataFunc returns the data of an account read by an extra account
+// seed or key.
+type accountDataFunc func(solana.PublicKey) ([]byte, error)
+
+// transferHookExecution is the Execute instruction a transfer invokes the
+// hook with, whose accounts and data the extra accounts are resolved from.
+type transferHookExecution struct {
+	program solana.PublicKey
+	// accounts starts with the source, mint, destination, authority and
+	// ExtraAccountMetaList accounts; resolved extra accounts are appended.
+	accounts []*solana.AccountMeta
+	data     []byte
+	// transfer holds the accounts of the transfer instruction, which
+	// resolved accounts must not escalate.
+	transfer []*solana.AccountMeta
+}
+
+// newTransferHookExecution returns the Execute instruction invoked by a
+// TransferChecked or TransferCheckedWithFee instruction for hookProgram.
+func newTransferHookExecution(hookProgram solana.PublicKey, transfer TypedInstruction) (*transferHookExecution, error) {
+	var (
+		source, mint, destination, authority solana.PublicKey
+		signers                              []solana.PublicKey
+		amount                               uint64
+	)
+	switch inst := transfer.(type) {
+	case *TransferChecked2022:
+		source, mint, destination, authority, signers, amount = inst.Source, inst.Mint, inst.Destination, inst.Owner, inst.Signers, inst.Amount
+	case *TransferCheckedWithFee2022:
+		source, mint, destination, authority, signers, amount = inst.Source, inst.Mint, inst.Destination, inst.Owner, inst.Signers, inst.Amount
+	default:
+		return nil, fmt.Errorf("transfer hooks need a TransferChecked or TransferCheckedWithFee instruction, got %T", transfer)
+	}
+	list, _, err := FindExtraAccountMetasAddress(mint, hookProgram)
+	if err != nil {
+		return nil, err
+	}
+	execution := &transferHookExecution{
+		program: hookProgram,
+		accounts: []*solana.AccountMeta{
+			solana.Meta(source),
+			solana.Meta(mint),
+			solana.Meta(destination),
+			solana.Meta(authority),
+			solana.Meta(list),
+		},
+		data: binary.LittleEndian.AppendUint64(append([]byte{}, executeDiscriminator...), amount),
+		transfer: []*solana.AccountMeta{
+			solana.Meta(source).WRITE(),
+			solana.Meta(mint),
+			solana.Meta(destination).WRITE(),
+			{PublicKey: authority, IsSigner: len(signers) == 0},
+		},
+	}
+	for _, signer := range signers {
+		execution.transfer = append(execution.transfer, solana.Meta(signer).SIGNER())
+	}
+	return execution, nil
+}
+
+// list returns the ExtraAccountMetaList account.
+func (e *transferHookExecution) list() solana.PublicKey {
+	return e.accounts[4].PublicKey
+}
+
+// resolve resolves metas in order and returns the accounts to append to the
+// transfer: the extra accounts, the hook program and the
+// ExtraAccountMetaList account.
+func (e *transferHookExecution) resolve(metas []ExtraAccountMeta, accountData accountDataFunc) ([]*solana.AccountMeta, error) {
+	extras := make([]*solana.AccountMeta, 0, len(metas)+2)
+	for i, meta := range metas {
+		key, err := e.resolveKey(meta, accountData)
+		if err != nil {
+			return nil, fmt.Errorf("error while resolving extra account %d: %w", i, err)
+		}
+		account := &solana.AccountMeta{PublicKey: key, IsSigner: meta.IsSigner, IsWritable: meta.IsWritable}
+		e.accounts = append(e.accounts, account)
+		extras = append(extras, e.deEscalate(account))
+	}
+	return append(extras, solana.Meta(e.program), solana.Meta(e.list())), nil
+}
+
+// deEscalate returns account without the signer or writable privilege when
+// the transfer already has the account without it, as the program cannot
+// grant more privileges to the hook than the transfer received.
+func (e *transferHookExecution) deEscalate(account *solana.AccountMeta) *solana.AccountMeta {
+	found, signer, writable := false, false, false
+	for _, existing := range e.transfer {
+		if existing.PublicKey == account.PublicKey {
+			found, signer, writable = true, signer || existing.IsSigner, writable || existing.IsWritable
+		}
+	}
+	if !found {
+		return account
+	}
+	return &solana.AccountMeta{
+		PublicKey:  account.PublicKey,
+		IsSigner:   account.IsSigner && signer,
+		IsWritable: account.IsWritable && writable,
+	}
+}
+
+func (e *transferHookExecution) resolveKey(meta ExtraAccountMeta, accountData accountDataFunc) (solana.PublicKey, error) {
+	config := meta.AddressConfig[:]
+	switch {
+	case meta.Discriminator == 0:
+		return solana.PublicKeyFromBytes(config), nil
+	case meta.Discriminator == 1:
+		return e.deriveAddress(config, e.program, accountData)
+	case meta.Discriminator == 2:
+		return e.resolvePubkeyData(config, accountData)
+	case meta.Discriminator >= 128:
+		program, err := e.account(int(meta.Discriminator - 128))
+		if err != nil {
+			return solana.PublicKey{}, err
+		}
+		return e.deriveAddress(config, program, accountData)
+	}
+	return solana.PublicKey{}, fmt.Errorf("unknown extra account discriminator %d", meta.Discriminator)
+}
+
+func (e *transferHookExecution) account(index int) (solana.PublicKey, error) {
+	if index >= len(e.accounts) {
+		return solana.PublicKey{}, fmt.Errorf("account index %d out of range (%d accounts)", index, len(e.accounts))
+	}
+	return e.accounts[index].PublicKey, nil
+}
+
+func (e *transferHookExecution) accountData(index int, accountData accountDataFunc) ([]byte, error) {
+	key, err := e.account(index)
+	if err != nil {
+		return nil, err
+	}
+	return accountData(key)
+}
+
+// deriveAddress unpacks the seeds packed in config and derives the
+// address from program.
+func (e *transferHookExecution) deriveAddress(config []byte, program solana.PublicKey, accountData accountDataFunc) (solana.PublicKey, error) {
+	var seeds [][]byte
+	for i := 0; i < len(config) && config[i] != 0; {
+		var (
+			seed []byte
+			err  error
+		)
+		switch config[i] {
+		case 1: // Literal: length, bytes.
+			if i+2 > len(config) || i+2+int(config[i+1]) > len(config) {
+				return solana.PublicKey{}, errors.New("literal seed overflows the address config")
+			}
+			seed = config[i+2 : i+2+int(config[i+1])]
+			i += 2 + len(seed)
+		case 2: // Instruction data: index, length.
+			if i+3 > len(config) {
+				return solana.PublicKey{}, errors.New("instruction data seed overflows the address config")
+			}
+			seed, err = dataRange(e.data, int(config[i+1]), int(config[i+2]), "instruction data")
+			i += 3
+		case 3: // Account key: index.
+			if i+2 > len(config) {
+				return solana.PublicKey{}, errors.New("account key seed overflows the address config")
+			}
+			var key solana.PublicKey
+			key, err = e.account(int(config[i+1]))
+			seed = key.Bytes()
+			i += 2
+		case 4: // Account data: account index, data index, length.
+			if i+4 > len(config) {
+				return solana.PublicKey{}, errors.New("account data seed overflows the address config")
+			}
+			var data []byte
+			if data, err = e.accountData(int(config[i+1]), accountData); err == nil {
+				seed, err = dataRange(data, int(config[i+2]), int(config[i+3]), "account data")
+			}
+			i += 4
+		default:
+			return solana.PublicKey{}, fmt.Errorf("unknown seed discriminator %d", config[i])
+		}
+		if err != nil {
+			return solana.PublicKey{}, err
+		}
+		seeds = append(seeds, seed)
+	}
+	address, _, err := solana.FindProgramAddress(seeds, program)
+	return address, err
+}
+
+// resolvePubkeyData reads a key from the instruction data or the data of
+// an account, as described by config.
+func (e *transferHookExecution) resolvePubkeyData(config []byte, accountData accountDataFunc) (solana.PublicKey, error) {
+	var (
+		key []byte
+		err error
+	)
+	switch config[0] {
+	case 1: // Instruction data: index.
+		key, err = dataRange(e.data, int(config[1]), solana.PublicKeyLength, "instruction data")
+	case 2: // Account data: account index, data index.
+		var data []byte
+		if data, err = e.accountData(int(config[1]), accountData); err == nil {
+			key, err = dataRange(data, int(config[2]), solana.PublicKeyLength, "account data")
+		}
+	default:
+		return solana.PublicKey{}, fmt.Errorf("unknown key data discriminator %d", config[0])
+	}
+	if err != nil {
+		return solana.PublicKey{}, err
+	}
+	return solana.PublicKeyFromBytes(key), nil
+}
+
+func dataRange(data []byte, index, length int, name string) ([]byte, error) {
+	if index+length > len(data) {
+		return nil, fmt.Errorf("%s range [%d:%d] out of bounds (%d bytes)", name, index, index+length, len(data))
+	}
+	return data[index : index+length], nil
+}
+
+// ResolveTransferHookAccounts returns the accounts a TransferChecked or
+// TransferCheckedWithFee instruction must carry, as AdditionalAccounts, for
+// the transfer hook program of its mint: the extra accounts listed in the
+// program's ExtraAccountMetaList account, then the program and the list
+// account. The list and any account its entries read are fetched from
+// client.
+func ResolveTransferHookAccounts(ctx context.Context, client RPCClient, commitment rpc.CommitmentType, hookProgram solana.PublicKey, transfer TypedInstruction) ([]*solana.AccountMeta, error) {
+	accounts, _, err := resolveTransferHookAccounts(ctx, client, commitment, hookProgram, transfer)
+	return accounts, err
+}
+
+// FetchTransferHookAccountData fetches the data ResolveTransferHookAccountsOffline
+// needs for transfer: the ExtraAccountMetaList account of the hook program
+// and every account its entries read, keyed by address. Run it online and
+// carry the result to the air-gapped signer.
+func FetchTransferHookAccountData(ctx context.Context, client RPCClient, commitment rpc.CommitmentType, hookProgram solana.PublicKey, transfer TypedInstruction) (map[solana.PublicKey][]byte, error) {
+	_, data, err := resolveTransferHookAccounts(ctx, client, commitment, hookProgram, transfer)
+	return data, err
+}
+
+// resolveTransferHookAccounts resolves the hook accounts of transfer,
+// returning the data of every account it fetched.
+func resolveTransferHookAccounts(ctx context.Context, client RPCClient, commitment rpc.CommitmentType, hookProgram solana.PublicKey, transfer TypedInstruction) ([]*solana.AccountMeta, map[solana.PublicKey][]byte, error) {
+	execution, err := newTransferHookExecution(hookProgram, transfer)
+	if err != nil {
+		return nil, nil, err
+	}
+	fetched := map[solana.PublicKey][]byte{}
+	fetch := func(account solana.PublicKey) ([]byte, error) {
+		if data, ok := fetched[account]; ok {
+			return data, nil
+		}
+		out, err := client.GetAccountInfoWithOpts(ctx, account, &rpc.GetAccountInfoOpts{Commitment: commitment})
+		if err != nil {
+			return nil, fmt.Errorf("error while fetching account %s: %w", account, err)
+		}
+		if out == nil || out.Value == nil {
+			return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, account)
+		}
+		fetched[account] = out.Value.Data.GetBinary()
+		return fetched[account], nil
+	}
+	data, err := fetch(execution.list())
+	if err != nil {
+		return nil, nil, fmt.Errorf("error while fetching extra account meta list: %w", err)
+	}
+	metas, err := DecodeExtraAccountMetaList(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	accounts, err := execution.resolve(metas, fetch)
+	if err != nil {
+		return nil, nil, err
+	}
+	return accounts, fetched, nil
+}
+
+// ResolveTransferHookAccountsOffline resolves the hook accounts of
+// transfer like ResolveTransferHookAccounts, without RPC: accountData holds
+// the ExtraAccountMetaList account of the hook program and the accounts its
+// entries read, as returned by FetchTransferHookAccountData. An account
+// missing from accountData wraps ErrAccountNotFound.
+func ResolveTransferHookAccountsOffline(hookProgram solana.PublicKey, transfer TypedInstruction, accountData map[solana.PublicKey][]byte) ([]*solana.AccountMeta, error) {
+	execution, err := newTransferHookExecution(hookProgram, transfer)
+	if err != nil {
+		return nil, err
+	}
+	lookup := func(account solana.PublicKey) ([]byte, error) {
+		data, ok := accountData[account]
+		if !ok {
+			return nil, fmt.Errorf("%w: data of %s not provided", ErrAccountNotFound, account)
+		}
+		return data, nil
+	}
+	data, err := lookup(execution.list())
+	if err != nil {
+		return nil, fmt.Errorf("error while reading extra account meta list: %w", err)
+	}
+	metas, err := DecodeExtraAccountMetaList(data)
+	if err != nil {
+		return nil, err
+	}
+	return execution.resolve(metas, lookup)
+}
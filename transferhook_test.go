@@ -0,0 +1,175 @@
+package token2022
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// encodeExtraAccountMetaList encodes an ExtraAccountMetaList account with
+// an unrelated entry before the Execute entry.
+func encodeExtraAccountMetaList(metas []ExtraAccountMeta) []byte {
+	data := append(make([]byte, 8), 1, 0, 0, 0, 0xff)
+	data[0] = 1
+	data = append(data, executeDiscriminator...)
+	data = binary.LittleEndian.AppendUint32(data, uint32(4+len(metas)*extraAccountMetaSize))
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(metas)))
+	for _, meta := range metas {
+		data = append(data, meta.Discriminator)
+		data = append(data, meta.AddressConfig[:]...)
+		data = append(data, boolByte(meta.IsSigner), boolByte(meta.IsWritable))
+	}
+	return data
+}
+
+func boolByte(b bool) byte {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func addressConfig(b ...byte) (config [32]byte) {
+	copy(config[:], b)
+	return config
+}
+
+func TestExecuteDiscriminator(t *testing.T) {
+	// From the transfer hook interface: ExecuteInstruction's discriminator.
+	expected := []byte{105, 37, 101, 197, 75, 251, 102, 26}
+	if string(executeDiscriminator) != string(expected) {
+		t.Errorf("Expected %v, got %v", expected, executeDiscriminator)
+	}
+}
+
+func TestResolveTransferHookAccounts(t *testing.T) {
+	var (
+		owner       = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		hook        = solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR")
+		external    = solana.MemoProgramID
+		client      = newMockRPC()
+	)
+	client.setAccount(source, solana.Token2022ProgramID, EncodeTokenAccount(&TokenAccount{Mint: mint, Owner: owner, State: AccountStateInitialized}))
+
+	metas := []ExtraAccountMeta{
+		// 5: a literal program, marked writable.
+		{Discriminator: 0, AddressConfig: addressConfig(external.Bytes()...), IsWritable: true},
+		// 6: the hook PDA of "counter" and the source account key.
+		{Discriminator: 1, AddressConfig: addressConfig(1, 7, 'c', 'o', 'u', 'n', 't', 'e', 'r', 3, 0), IsWritable: true},
+		// 7: the source owner, read from the source account data.
+		{Discriminator: 2, AddressConfig: addressConfig(2, 0, 32)},
+		// 8: a PDA of account 5 over the amount and the owner from account data.
+		{Discriminator: 128 + 5, AddressConfig: addressConfig(2, 8, 8, 4, 0, 32, 32)},
+		// 9: the mint, which the transfer only reads.
+		{Discriminator: 0, AddressConfig: addressConfig(mint.Bytes()...), IsSigner: true, IsWritable: true},
+	}
+	list, _, err := FindExtraAccountMetasAddress(mint, hook)
+	if err != nil {
+		t.Fatalf("FindExtraAccountMetasAddress: %v", err)
+	}
+	client.setAccount(list, hook, encodeExtraAccountMetaList(metas))
+
+	decoded, err := DecodeExtraAccountMetaList(encodeExtraAccountMetaList(metas))
+	if err != nil {
+		t.Fatalf("DecodeExtraAccountMetaList: %v", err)
+	}
+	if len(decoded) != len(metas) || decoded[1] != metas[1] {
+		t.Errorf("Expected %v, got %v", metas, decoded)
+	}
+
+	transfer := NewTransferChecked2022Instruction(1_000, 6, source, mint, destination, owner)
+	accounts, err := ResolveTransferHookAccounts(context.Background(), client, "", hook, transfer)
+	if err != nil {
+		t.Fatalf("ResolveTransferHookAccounts: %v", err)
+	}
+
+	counter, _, _ := solana.FindProgramAddress([][]byte{[]byte("counter"), source.Bytes()}, hook)
+	amount := binary.LittleEndian.AppendUint64(nil, 1_000)
+	derived, _, _ := solana.FindProgramAddress([][]byte{amount, owner.Bytes()}, external)
+	expected := []*solana.AccountMeta{
+		solana.Meta(external).WRITE(),
+		solana.Meta(counter).WRITE(),
+		solana.Meta(owner),
+		solana.Meta(derived),
+		solana.Meta(mint),
+		solana.Meta(hook),
+		solana.Meta(list),
+	}
+	if len(accounts) != len(expected) {
+		t.Fatalf("Expected %d accounts, got %d", len(expected), len(accounts))
+	}
+	for i := range expected {
+		if *accounts[i] != *expected[i] {
+			t.Errorf("Account %d: expected %+v, got %+v", i, expected[i], accounts[i])
+		}
+	}
+
+	if _, err := ResolveTransferHookAccounts(context.Background(), client, "", hook, NewTransfer2022Instruction(1, source, destination, owner)); err == nil {
+		t.Error("Expected an error for an unchecked transfer")
+	}
+	bad := []ExtraAccountMeta{{Discriminator: 1, AddressConfig: addressConfig(3, 9)}}
+	client.setAccount(list, hook, encodeExtraAccountMetaList(bad))
+	if _, err := ResolveTransferHookAccounts(context.Background(), client, "", hook, transfer); err == nil {
+		t.Error("Expected an error for an out of range account index")
+	}
+}
+
+func TestResolveTransferHookAccountsOffline(t *testing.T) {
+	var (
+		owner       = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+		destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		hook        = solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR")
+		client      = newMockRPC()
+	)
+	client.setAccount(source, solana.Token2022ProgramID, EncodeTokenAccount(&TokenAccount{Mint: mint, Owner: owner, State: AccountStateInitialized}))
+	metas := []ExtraAccountMeta{
+		// The source owner, read from the source account data, twice.
+		NewExtraAccountMetaFromAccountData(0, 32, false, false),
+		{Discriminator: 1, AddressConfig: addressConfig(4, 0, 32, 32)},
+	}
+	list, _, _ := FindExtraAccountMetasAddress(mint, hook)
+	client.setAccount(list, hook, EncodeExtraAccountMetaList(metas))
+	transfer := NewTransferChecked2022Instruction(1_000, 6, source, mint, destination, owner)
+
+	online, err := ResolveTransferHookAccounts(context.Background(), client, "", hook, transfer)
+	if err != nil {
+		t.Fatalf("ResolveTransferHookAccounts: %v", err)
+	}
+	data, err := FetchTransferHookAccountData(context.Background(), client, "", hook, transfer)
+	if err != nil {
+		t.Fatalf("FetchTransferHookAccountData: %v", err)
+	}
+	if len(data) != 2 {
+		t.Errorf("Expected the list and the source account, got %d accounts", len(data))
+	}
+	if client.calls["getAccountInfo"] != 4 {
+		t.Errorf("Expected each account fetched once per resolution, got %d calls", client.calls["getAccountInfo"])
+	}
+
+	client.err = errors.New("offline")
+	offline, err := ResolveTransferHookAccountsOffline(hook, transfer, data)
+	if err != nil {
+		t.Fatalf("ResolveTransferHookAccountsOffline: %v", err)
+	}
+	if len(offline) != len(online) {
+		t.Fatalf("Expected %d accounts, got %d", len(online), len(offline))
+	}
+	for i := range online {
+		if *offline[i] != *online[i] {
+			t.Errorf("Account %d: expected %+v, got %+v", i, online[i], offline[i])
+		}
+	}
+
+	delete(data, source)
+	if _, err := ResolveTransferHookAccountsOffline(hook, transfer, data); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("Expected ErrAccountNotFound without the source data, got %v", err)
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+// Discriminators of the transfer hook interface instructions that
+// initialize and update an ExtraAccountMetaList account.
+var (
+	initializeExtraAccountMetaListDiscriminator = transferHookDiscriminator("initialize-extra-account-metas")
+	updateExtraAccountMetaListDiscriminator     = transferHookDiscriminator("update-extra-account-metas")
+)
+
+func transferHookDiscriminator(name string) []byte {
+	sum := sha256.Sum256([]byte("spl-transfer-hook-interface:" + name))
+	return sum[:8]
+}
+
+// Seed is a seed of an ExtraAccountMeta derived address, resolved when
+// the transfer runs.
+type Seed struct {
+	packed []byte
+}
+
+// SeedLiteral is a constant seed of up to 32 bytes.
+func SeedLiteral(value []byte) Seed {
+	return Seed{append([]byte{1, byte(len(value))}, value...)}
+}
+
+// SeedInstructionData is length bytes of the Execute instruction data from
+// index; the amount is at index 8, with length 8.
+func SeedInstructionData(index, length uint8) Seed {
+	return Seed{[]byte{2, index, length}}
+}
+
+// SeedAccountKey is the key of the Execute account at index: 0 for the
+// source, 1 the mint, 2 the destination, 3 the authority, 4 the
+// ExtraAccountMetaList and 5 onwards the extra accounts before this one.
+func SeedAccountKey(index uint8) Seed {
+	return Seed{[]byte{3, index}}
+}
+
+// SeedAccountData is length bytes of the data of the Execute account at
+// accountIndex, from dataIndex.
+func SeedAccountData(accountIndex, dataIndex, length uint8) Seed {
+	return Seed{[]byte{4, accountIndex, dataIndex, length}}
+}
+
+// packSeeds packs seeds into an address config, which holds 32 bytes.
+func packSeeds(seeds []Seed) ([32]byte, error) {
+	var config [32]byte
+	offset := 0
+	for _, seed := range seeds {
+		if seed.packed[0] == 1 && len(seed.packed)-2 > 32 {
+			return config, errInvalidField("Seeds", "have a literal of %d bytes, more than 32", len(seed.packed)-2)
+		}
+		if offset+len(seed.packed) > len(config) {
+			return config, errInvalidField("Seeds", "take more than the 32 bytes of an address config")
+		}
+		offset += copy(config[offset:], seed.packed)
+	}
+	return config, nil
+}
+
+// NewExtraAccountMeta returns an ExtraAccountMeta for a fixed key.
+func NewExtraAccountMeta(key solana.PublicKey, isSigner, isWritable bool) ExtraAccountMeta {
+	return ExtraAccountMeta{Discriminator: 0, AddressConfig: key, IsSigner: isSigner, IsWritable: isWritable}
+}
+
+// NewExtraAccountMetaWithSeeds returns an ExtraAccountMeta for an address
+// derived from the hook program with seeds.
+func NewExtraAccountMetaWithSeeds(seeds []Seed, isSigner, isWritable bool) (ExtraAccountMeta, error) {
+	config, err := packSeeds(seeds)
+	if err != nil {
+		return ExtraAccountMeta{}, err
+	}
+	return ExtraAccountMeta{Discriminator: 1, AddressConfig: config, IsSigner: isSigner, IsWritable: isWritable}, nil
+}
+
+// NewExtraAccountMetaExternalPDA returns an ExtraAccountMeta for an
+// address derived with seeds from the program at Execute account
+// programIndex, itself usually an earlier extra account.
+func NewExtraAccountMetaExternalPDA(programIndex uint8, seeds []Seed, isSigner, isWritable bool) (ExtraAccountMeta, error) {
+	if programIndex > 127 {
+		return ExtraAccountMeta{}, errInvalidField("programIndex", "%d exceeds 127", programIndex)
+	}
+	config, err := packSeeds(seeds)
+	if err != nil {
+		return ExtraAccountMeta{}, err
+	}
+	return ExtraAccountMeta{Discriminator: 128 + programIndex, AddressConfig: config, IsSigner: isSigner, IsWritable: isWritable}, nil
+}
+
+// NewExtraAccountMetaFromInstructionData returns an ExtraAccountMeta for a
+// key read from the Execute instruction data at index.
+func NewExtraAccountMetaFromInstructionData(index uint8, isSigner, isWritable bool) ExtraAccountMeta {
+	var config [32]byte
+	config[0], config[1] = 1, index
+	return ExtraAccountMeta{Discriminator: 2, AddressConfig: config, IsSigner: isSigner, IsWritable: isWritable}
+}
+
+// NewExtraAccountMetaFromAccountData returns an ExtraAccountMeta for a key
+// read from the data of the Execute account at accountIndex, from
+// dataIndex, such as the owner at 32 of the source token account at 0.
+func NewExtraAccountMetaFromAccountData(accountIndex, dataIndex uint8, isSigner, isWritable bool) ExtraAccountMeta {
+	var config [32]byte
+	config[0], config[1], config[2] = 2, accountIndex, dataIndex
+	return ExtraAccountMeta{Discriminator: 2, AddressConfig: config, IsSigner: isSigner, IsWritable: isWritable}
+}
+
+// appendExtraAccountMetas appends metas as a length-prefixed vector.
+func appendExtraAccountMetas(data []byte, metas []ExtraAccountMeta) []byte {
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(metas)))
+	for _, meta := range metas {
+		data = append(data, meta.Discriminator)
+		data = append(data, meta.AddressConfig[:]...)
+		data = append(data, boolToByte(meta.IsSigner), boolToByte(meta.IsWritable))
+	}
+	return data
+}
+
+func boolToByte(b bool) byte {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// EncodeExtraAccountMetaList encodes the data of an ExtraAccountMetaList
+// account holding metas, the inverse of DecodeExtraAccountMetaList.
+func EncodeExtraAccountMetaList(metas []ExtraAccountMeta) []byte {
+	data := append([]byte{}, executeDiscriminator...)
+	data = binary.LittleEndian.AppendUint32(data, uint32(4+len(metas)*extraAccountMetaSize))
+	return appendExtraAccountMetas(data, metas)
+}
+
+// ExtraAccountMetaListSize returns the size of an ExtraAccountMetaList
+// account holding count metas, to fund it with RentExemptLamports.
+func ExtraAccountMetaListSize(count int) int {
+	return 8 + 4 + 4 + count*extraAccountMetaSize
+}
+
+// NewInitializeExtraAccountMetaListInstruction returns the transfer hook
+// interface instruction writing metas into the ExtraAccountMetaList
+// account of mint for hookProgram, signed by authority, the mint
+// authority. Hook programs following the reference implementation only
+// allocate and assign the account, so fund it first with
+// RentExemptLamports(ExtraAccountMetaListSize(len(metas))).
+func NewInitializeExtraAccountMetaListInstruction(hookProgram, mint, authority solana.PublicKey, metas []ExtraAccountMeta) (solana.Instruction, error) {
+	return newExtraAccountMetaListInstruction(initializeExtraAccountMetaListDiscriminator, hookProgram, mint, authority, metas, true)
+}
+
+// NewUpdateExtraAccountMetaListInstruction returns the transfer hook
+// interface instruction replacing the metas of the ExtraAccountMetaList
+// account of mint for hookProgram.
+func NewUpdateExtraAccountMetaListInstruction(hookProgram, mint, authority solana.PublicKey, metas []ExtraAccountMeta) (solana.Instruction, error) {
+	return newExtraAccountMetaListInstruction(updateExtraAccountMetaListDiscriminator, hookProgram, mint, authority, metas, false)
+}
+
+func newExtraAccountMetaListInstruction(discriminator []byte, hookProgram, mint, authority solana.PublicKey, metas []ExtraAccountMeta, initialize bool) (solana.Instruction, error) {
+	list, _, err := FindExtraAccountMetasAddress(mint, hookProgram)
+	if err != nil {
+		return nil, err
+	}
+	accounts := solana.AccountMetaSlice{
+		solana.Meta(list).WRITE(),
+		solana.Meta(mint),
+		solana.Meta(authority).SIGNER(),
+	}
+	if initialize {
+		accounts = append(accounts, solana.Meta(solana.SystemProgramID))
+	}
+	data := appendExtraAccountMetas(append([]byte{}, discriminator...), metas)
+	return solana.NewInstruction(hookProgram, accounts, data), nil
+}
+
+// NewExecuteInstruction returns the transfer hook interface Execute
+// instruction Token-2022 invokes hookProgram with for a transfer of
+// amount, such as to test a hook program directly. extras are the
+// resolved extra accounts, as returned by ResolveTransferHookAccounts
+// without its last two.
+func NewExecuteInstruction(hookProgram, source, mint, destination, authority solana.PublicKey, amount uint64, extras ...*solana.AccountMeta) (solana.Instruction, error) {
+	list, _, err := FindExtraAccountMetasAddress(mint, hookProgram)
+	if err != nil {
+		return nil, err
+	}
+	accounts := solana.AccountMetaSlice{
+		solana.Meta(source),
+		solana.Meta(mint),
+		solana.Meta(destination),
+		solana.Meta(authority),
+		solana.Meta(list),
+	}
+	accounts = append(accounts, extras...)
+	data := binary.LittleEndian.AppendUint64(append([]byte{}, executeDiscriminator...), amount)
+	return solana.NewInstruction(hookProgram, accounts, data), nil
+}
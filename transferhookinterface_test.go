@@ -0,0 +1,112 @@
+package token2022
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	solana "github.com/gagliardetto/solana-go"
+)
+
+func TestTransferHookInterfaceDiscriminators(t *testing.T) {
+	// From the transfer hook interface.
+	if expected := []byte{43, 34, 13, 49, 167, 88, 235, 235}; !bytes.Equal(initializeExtraAccountMetaListDiscriminator, expected) {
+		t.Errorf("Expected %v, got %v", expected, initializeExtraAccountMetaListDiscriminator)
+	}
+	if expected := []byte{157, 105, 42, 146, 102, 85, 241, 174}; !bytes.Equal(updateExtraAccountMetaListDiscriminator, expected) {
+		t.Errorf("Expected %v, got %v", expected, updateExtraAccountMetaListDiscriminator)
+	}
+}
+
+func TestExtraAccountMetaConstructors(t *testing.T) {
+	mint := solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	counter, err := NewExtraAccountMetaWithSeeds([]Seed{SeedLiteral([]byte("counter")), SeedAccountKey(0)}, false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	external, err := NewExtraAccountMetaExternalPDA(5, []Seed{SeedInstructionData(8, 8), SeedAccountData(0, 32, 32)}, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The metas of TestResolveTransferHookAccounts, built by hand there.
+	for i, tc := range []struct {
+		got, expected ExtraAccountMeta
+	}{
+		{NewExtraAccountMeta(solana.MemoProgramID, false, true), ExtraAccountMeta{Discriminator: 0, AddressConfig: addressConfig(solana.MemoProgramID.Bytes()...), IsWritable: true}},
+		{counter, ExtraAccountMeta{Discriminator: 1, AddressConfig: addressConfig(1, 7, 'c', 'o', 'u', 'n', 't', 'e', 'r', 3, 0), IsWritable: true}},
+		{NewExtraAccountMetaFromAccountData(0, 32, false, false), ExtraAccountMeta{Discriminator: 2, AddressConfig: addressConfig(2, 0, 32)}},
+		{external, ExtraAccountMeta{Discriminator: 128 + 5, AddressConfig: addressConfig(2, 8, 8, 4, 0, 32, 32)}},
+		{NewExtraAccountMetaFromInstructionData(8, false, false), ExtraAccountMeta{Discriminator: 2, AddressConfig: addressConfig(1, 8)}},
+	} {
+		if tc.got != tc.expected {
+			t.Errorf("Meta %d: expected %+v, got %+v", i, tc.expected, tc.got)
+		}
+	}
+
+	if _, err := NewExtraAccountMetaWithSeeds([]Seed{SeedLiteral(mint[:]), SeedAccountKey(0)}, false, false); !errors.Is(err, ErrInvalidField) {
+		t.Errorf("Expected ErrInvalidField for seeds over 32 bytes, got %v", err)
+	}
+	if _, err := NewExtraAccountMetaExternalPDA(128, nil, false, false); !errors.Is(err, ErrInvalidField) {
+		t.Errorf("Expected ErrInvalidField for a program index over 127, got %v", err)
+	}
+
+	metas := []ExtraAccountMeta{counter, external}
+	data := EncodeExtraAccountMetaList(metas)
+	if len(data) != ExtraAccountMetaListSize(len(metas)) {
+		t.Errorf("Expected %d bytes, got %d", ExtraAccountMetaListSize(len(metas)), len(data))
+	}
+	decoded, err := DecodeExtraAccountMetaList(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 2 || decoded[0] != counter || decoded[1] != external {
+		t.Errorf("Expected %+v, got %+v", metas, decoded)
+	}
+}
+
+func TestTransferHookInterfaceInstructions(t *testing.T) {
+	mint := solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	authority := solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	hook := solana.MustPublicKeyFromBase58("HooKD5NC9QNxk25QuzCssB8ecrEzGt6eXEPBUxWp1LaR")
+	source := solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+	destination := solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+	list, _, _ := FindExtraAccountMetasAddress(mint, hook)
+	metas := []ExtraAccountMeta{NewExtraAccountMeta(solana.MemoProgramID, false, false)}
+
+	initialize, err := NewInitializeExtraAccountMetaListInstruction(hook, mint, authority, metas)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := initialize.Data()
+	if !initialize.ProgramID().Equals(hook) || !bytes.HasPrefix(data, initializeExtraAccountMetaListDiscriminator) {
+		t.Errorf("Expected an initialize instruction for %s, got %s with %v", hook, initialize.ProgramID(), data[:8])
+	}
+	if count := binary.LittleEndian.Uint32(data[8:12]); count != 1 || len(data) != 12+extraAccountMetaSize {
+		t.Errorf("Expected 1 meta in %d bytes, got %d in %d", 12+extraAccountMetaSize, count, len(data))
+	}
+	accounts := initialize.Accounts()
+	if len(accounts) != 4 || !accounts[0].PublicKey.Equals(list) || !accounts[0].IsWritable || !accounts[2].IsSigner || !accounts[3].PublicKey.Equals(solana.SystemProgramID) {
+		t.Errorf("Unexpected initialize accounts: %v", accounts)
+	}
+
+	update, err := NewUpdateExtraAccountMetaListInstruction(hook, mint, authority, metas)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := update.Data(); !bytes.HasPrefix(data, updateExtraAccountMetaListDiscriminator) || len(update.Accounts()) != 3 {
+		t.Errorf("Unexpected update instruction: %v with %d accounts", data[:8], len(update.Accounts()))
+	}
+
+	execute, err := NewExecuteInstruction(hook, source, mint, destination, authority, 1_000, solana.Meta(solana.MemoProgramID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ = execute.Data()
+	if !bytes.Equal(data, binary.LittleEndian.AppendUint64(append([]byte{}, executeDiscriminator...), 1_000)) {
+		t.Errorf("Unexpected execute data: %v", data)
+	}
+	if accounts := execute.Accounts(); len(accounts) != 6 || !accounts[4].PublicKey.Equals(list) || !accounts[5].PublicKey.Equals(solana.MemoProgramID) {
+		t.Errorf("Unexpected execute accounts: %v", accounts)
+	}
+}
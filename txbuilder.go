@@ -0,0 +1,321 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// TxBuilder assembles instructions into a transaction ready to be signed.
+//
+// By default the transaction uses the latest blockhash. In durable nonce
+// mode the builder instead reads the stored nonce from the nonce account,
+// uses it as the recent blockhash and prepends AdvanceNonceAccount, so the
+// transaction stays valid until the nonce is advanced. This is what offline
+// and multisig signing flows need.
+type TxBuilder struct {
+	client       RPCClient
+	feePayer     solana.PublicKey
+	instructions []solana.Instruction
+	signers      []Signer
+	commitment   rpc.CommitmentType
+	minSlot      *uint64
+	blockhashes  BlockhashProvider
+	logger       Logger
+	tracer       Tracer
+	tables       map[solana.PublicKey]solana.PublicKeySlice
+	policies     []Policy
+	onFlag       func(context.Context, *PolicyViolation)
+
+	nonceAccount   solana.PublicKey
+	nonceAuthority solana.PublicKey
+}
+
+// NewTxBuilder creates a new transaction builder backed by the given RPC client.
+func NewTxBuilder(client RPCClient) *TxBuilder {
+	return &TxBuilder{
+		client:     client,
+		commitment: rpc.CommitmentFinalized,
+	}
+}
+
+func (b *TxBuilder) SetFeePayer(feePayer solana.PublicKey) *TxBuilder {
+	b.feePayer = feePayer
+	return b
+}
+
+func (b *TxBuilder) SetCommitment(commitment rpc.CommitmentType) *TxBuilder {
+	b.commitment = commitment
+	return b
+}
+
+// SetMinContextSlot makes the builder read the durable nonce account from
+// a node that has processed at least slot, so a nonce advanced by a
+// just-confirmed transaction is never read stale.
+func (b *TxBuilder) SetMinContextSlot(slot uint64) *TxBuilder {
+	b.minSlot = &slot
+	return b
+}
+
+// SetBlockhashProvider makes the builder take blockhashes from provider,
+// typically a shared BlockhashCache, instead of calling getLatestBlockhash.
+func (b *TxBuilder) SetBlockhashProvider(provider BlockhashProvider) *TxBuilder {
+	b.blockhashes = provider
+	return b
+}
+
+// SetLogger logs every instruction of a built transaction at debug level
+// and the transaction itself.
+func (b *TxBuilder) SetLogger(logger Logger) *TxBuilder {
+	b.logger = logger
+	return b
+}
+
+// SetTracer traces building and signing the transaction.
+func (b *TxBuilder) SetTracer(tracer Tracer) *TxBuilder {
+	b.tracer = tracer
+	return b
+}
+
+func (b *TxBuilder) AddInstruction(instructions ...solana.Instruction) *TxBuilder {
+	b.instructions = append(b.instructions, instructions...)
+	return b
+}
+
+// AddSigner registers signers used by BuildAndSign.
+// Any Signer works, so hardware wallets and remote signing services can be
+// used in place of in-memory private keys.
+func (b *TxBuilder) AddSigner(signers ...Signer) *TxBuilder {
+	b.signers = append(b.signers, signers...)
+	return b
+}
+
+// SetDurableNonce switches the builder to durable nonce mode.
+// The nonce authority must sign the resulting transaction.
+func (b *TxBuilder) SetDurableNonce(nonceAccount solana.PublicKey, nonceAuthority solana.PublicKey) *TxBuilder {
+	b.nonceAccount = nonceAccount
+	b.nonceAuthority = nonceAuthority
+	return b
+}
+
+// SetAddressTables compresses the transaction with address lookup tables,
+// keyed by table address, producing a versioned (v0) transaction. Accounts
+// found in a table are referenced by index instead of by public key.
+func (b *TxBuilder) SetAddressTables(tables map[solana.PublicKey]solana.PublicKeySlice) *TxBuilder {
+	b.tables = tables
+	return b
+}
+
+// SetPolicy checks the Token-2022 instructions against policies before the
+// blockhash is fetched. A rejection fails Build and BuildAndSign with a
+// *PolicyViolation matching ErrPolicyRejected; flagged operations are
+// passed to the handler set with OnPolicyFlag and logged at warn level.
+func (b *TxBuilder) SetPolicy(policies ...Policy) *TxBuilder {
+	b.policies = policies
+	return b
+}
+
+// OnPolicyFlag sets the function called with every operation flagged by
+// a policy, before the transaction is signed.
+func (b *TxBuilder) OnPolicyFlag(handle func(context.Context, *PolicyViolation)) *TxBuilder {
+	b.onFlag = handle
+	return b
+}
+
+// Clone returns a copy of the builder that can be changed, such as by
+// adding instructions, without changing b.
+func (b *TxBuilder) Clone() *TxBuilder {
+	clone := *b
+	clone.instructions = append([]solana.Instruction(nil), b.instructions...)
+	clone.signers = append([]Signer(nil), b.signers...)
+	clone.policies = append([]Policy(nil), b.policies...)
+	return &clone
+}
+
+// UsesDurableNonce reports whether the builder is in durable nonce mode.
+func (b *TxBuilder) UsesDurableNonce() bool {
+	return !b.nonceAccount.IsZero()
+}
+
+func (b *TxBuilder) Validate() error {
+	if b.client == nil {
+		return errNotSet("RPC client")
+	}
+	if b.feePayer.IsZero() {
+		return errNotSet("FeePayer")
+	}
+	if len(b.instructions) == 0 {
+		return errInvalidField("Instructions", "must not be empty")
+	}
+	if b.UsesDurableNonce() && b.nonceAuthority.IsZero() {
+		return errNotSet("NonceAuthority")
+	}
+	return nil
+}
+
+// Build fetches a blockhash (or the stored durable nonce) and returns
+// the unsigned transaction.
+func (b *TxBuilder) Build(ctx context.Context) (*solana.Transaction, error) {
+	tx, _, err := b.build(ctx)
+	return tx, err
+}
+
+// build returns the unsigned transaction and the last block height at
+// which it can land. The height is zero in durable nonce mode, where the
+// transaction does not expire.
+func (b *TxBuilder) build(ctx context.Context) (*solana.Transaction, uint64, error) {
+	ctx, end := startSpan(ctx, b.tracer, "token2022.TxBuilder.Build",
+		"instructions", len(b.instructions),
+		"durable_nonce", b.UsesDurableNonce())
+	tx, lastValidBlockHeight, err := b.assemble(ctx)
+	end(err)
+	return tx, lastValidBlockHeight, err
+}
+
+func (b *TxBuilder) assemble(ctx context.Context) (*solana.Transaction, uint64, error) {
+	if err := b.Validate(); err != nil {
+		return nil, 0, err
+	}
+	if err := b.checkPolicies(ctx); err != nil {
+		return nil, 0, err
+	}
+
+	instructions := b.instructions
+	var blockhash solana.Hash
+	var lastValidBlockHeight uint64
+
+	if b.UsesDurableNonce() {
+		nonce, err := FetchNonceAccountWithOpts(ctx, b.client, b.nonceAccount, &FetchOpts{
+			Commitment:     b.commitment,
+			MinContextSlot: b.minSlot,
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+		if !nonce.AuthorizedPubkey.Equals(b.nonceAuthority) {
+			return nil, 0, fmt.Errorf(
+				"nonce authority mismatch: account %s is authorized by %s, not %s",
+				b.nonceAccount, nonce.AuthorizedPubkey, b.nonceAuthority,
+			)
+		}
+		advance := NewAdvanceNonceInstruction(b.nonceAccount, b.nonceAuthority)
+		instructions = append([]solana.Instruction{advance}, instructions...)
+		blockhash = solana.Hash(nonce.Nonce)
+	} else {
+		recent, err := b.latestBlockhash(ctx)
+		if err != nil {
+			return nil, 0, err
+		}
+		blockhash = recent.Blockhash
+		lastValidBlockHeight = recent.LastValidBlockHeight
+	}
+
+	opts := []solana.TransactionOption{solana.TransactionPayer(b.feePayer)}
+	if len(b.tables) > 0 {
+		opts = append(opts, solana.TransactionAddressTables(b.tables))
+	}
+	tx, err := solana.NewTransaction(instructions, blockhash, opts...)
+	if err != nil {
+		return nil, 0, err
+	}
+	if b.logger != nil {
+		b.logBuilt(ctx, instructions, blockhash)
+	}
+	return tx, lastValidBlockHeight, nil
+}
+
+func (b *TxBuilder) checkPolicies(ctx context.Context) error {
+	if len(b.policies) == 0 {
+		return nil
+	}
+	ops, err := PolicyOperations(b.instructions)
+	if err != nil {
+		return err
+	}
+	flagged, err := CheckPolicies(ctx, ops, b.policies...)
+	if err != nil {
+		var violation *PolicyViolation
+		if errors.As(err, &violation) {
+			logEvent(ctx, b.logger, slog.LevelWarn, "transaction rejected by policy",
+				"index", violation.Operation.Index, "instruction", violation.Operation.Name, "reason", violation.Reason)
+		}
+		return err
+	}
+	for _, violation := range flagged {
+		logEvent(ctx, b.logger, slog.LevelWarn, "instruction flagged by policy",
+			"index", violation.Operation.Index, "instruction", violation.Operation.Name, "reason", violation.Reason)
+		if b.onFlag != nil {
+			b.onFlag(ctx, violation)
+		}
+	}
+	return nil
+}
+
+func (b *TxBuilder) logBuilt(ctx context.Context, instructions []solana.Instruction, blockhash solana.Hash) {
+	for i, inst := range instructions {
+		data, _ := inst.Data()
+		b.logger.Log(ctx, slog.LevelDebug, "instruction built",
+			"index", i,
+			"program", inst.ProgramID().String(),
+			"instruction", instructionLogName(inst, data),
+			"accounts", len(inst.Accounts()))
+	}
+	b.logger.Log(ctx, slog.LevelDebug, "transaction built",
+		"fee_payer", b.feePayer.String(),
+		"blockhash", blockhash.String(),
+		"instructions", len(instructions),
+		"durable_nonce", b.UsesDurableNonce())
+}
+
+func (b *TxBuilder) latestBlockhash(ctx context.Context) (*rpc.LatestBlockhashResult, error) {
+	if b.blockhashes != nil {
+		return b.blockhashes.LatestBlockhash(ctx)
+	}
+	recent, err := b.client.GetLatestBlockhash(ctx, b.commitment)
+	if err != nil {
+		return nil, fmt.Errorf("error while GetLatestBlockhash: %w", err)
+	}
+	return recent.Value, nil
+}
+
+// BuildAndSign builds the transaction and signs it with the registered
+// signers. It fails if a required signature is still missing.
+func (b *TxBuilder) BuildAndSign(ctx context.Context) (*solana.Transaction, error) {
+	tx, _, err := b.buildAndSign(ctx)
+	return tx, err
+}
+
+func (b *TxBuilder) buildAndSign(ctx context.Context) (*solana.Transaction, uint64, error) {
+	tx, lastValidBlockHeight, err := b.build(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	signCtx, end := startSpan(ctx, b.tracer, "token2022.TxBuilder.Sign", "signers", len(b.signers))
+	err = SignTransaction(signCtx, tx, b.signers...)
+	end(err)
+	if err != nil {
+		return nil, 0, err
+	}
+	if missing := MissingSigners(tx); len(missing) > 0 {
+		return nil, 0, fmt.Errorf("missing signatures for %v", missing)
+	}
+	return tx, lastValidBlockHeight, nil
+}
@@ -0,0 +1,126 @@
+package token2022
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	bin "github.com/gagliardetto/binary"
+	solana "github.com/gagliardetto/solana-go"
+	system "github.com/gagliardetto/solana-go/programs/system"
+)
+
+func encodeNonceAccount(t *testing.T, authority solana.PublicKey, nonce solana.PublicKey) []byte {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	err := bin.NewBinEncoder(buf).Encode(system.NonceAccount{
+		Version:          1,
+		State:            NonceStateInitialized,
+		AuthorizedPubkey: authority,
+		Nonce:            nonce,
+		FeeCalculator:    system.FeeCalculator{LamportsPerSignature: 5000},
+	})
+	if err != nil {
+		t.Fatalf("Error encoding nonce account: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestTxBuilderLatestBlockhash(t *testing.T) {
+
+	var (
+		wallet = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint   = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		client = newMockRPC()
+	)
+
+	tx, err := NewTxBuilder(client).
+		SetFeePayer(wallet).
+		AddInstruction(NewCreate2022Instruction(wallet, wallet, mint).Build()).
+		Build(context.Background())
+	if err != nil {
+		t.Fatalf("Error building transaction: %v", err)
+	}
+
+	if tx.Message.RecentBlockhash != client.blockhash {
+		t.Errorf("Expected blockhash %s, got %s", client.blockhash, tx.Message.RecentBlockhash)
+	}
+	if len(tx.Message.Instructions) != 1 {
+		t.Errorf("Expected 1 instruction, got %d", len(tx.Message.Instructions))
+	}
+}
+
+func TestTxBuilderDurableNonce(t *testing.T) {
+
+	var (
+		wallet       = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+		mint         = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		nonceAccount = solana.MustPublicKeyFromBase58("83mctxW8BCh6nPGjxx4jmyaEfbpcMZpLQiv7tXVSAV7a")
+		storedNonce  = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+		client       = newMockRPC()
+	)
+	client.setAccount(nonceAccount, solana.SystemProgramID, encodeNonceAccount(t, wallet, storedNonce))
+
+	tx, err := NewTxBuilder(client).
+		SetFeePayer(wallet).
+		SetDurableNonce(nonceAccount, wallet).
+		AddInstruction(NewCreate2022Instruction(wallet, wallet, mint).Build()).
+		Build(context.Background())
+	if err != nil {
+		t.Fatalf("Error building transaction: %v", err)
+	}
+
+	if tx.Message.RecentBlockhash != solana.Hash(storedNonce) {
+		t.Errorf("Expected stored nonce %s as blockhash, got %s", storedNonce, tx.Message.RecentBlockhash)
+	}
+	if len(tx.Message.Instructions) != 2 {
+		t.Fatalf("Expected 2 instructions, got %d", len(tx.Message.Instructions))
+	}
+	programID, err := tx.ResolveProgramIDIndex(tx.Message.Instructions[0].ProgramIDIndex)
+	if err != nil {
+		t.Fatalf("Error resolving program ID: %v", err)
+	}
+	if programID != solana.SystemProgramID {
+		t.Errorf("Expected AdvanceNonceAccount first, got program %s", programID)
+	}
+
+	_, err = NewTxBuilder(client).
+		SetFeePayer(wallet).
+		SetDurableNonce(nonceAccount, mint).
+		AddInstruction(NewCreate2022Instruction(wallet, wallet, mint).Build()).
+		Build(context.Background())
+	if err == nil {
+		t.Errorf("Expected nonce authority mismatch error")
+	}
+}
+
+func TestTxBuilderBuildAndSign(t *testing.T) {
+
+	var (
+		owner  = solana.NewWallet().PrivateKey
+		other  = solana.NewWallet().PrivateKey
+		mint   = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+		client = newMockRPC()
+	)
+
+	tx, err := NewTxBuilder(client).
+		SetFeePayer(owner.PublicKey()).
+		AddInstruction(NewCreate2022Instruction(owner.PublicKey(), owner.PublicKey(), mint).Build()).
+		AddSigner(NewPrivateKeySigner(owner), NewPrivateKeySigner(other)).
+		BuildAndSign(context.Background())
+	if err != nil {
+		t.Fatalf("Error building transaction: %v", err)
+	}
+	if err := tx.VerifySignatures(); err != nil {
+		t.Errorf("Error verifying signatures: %v", err)
+	}
+
+	_, err = NewTxBuilder(client).
+		SetFeePayer(owner.PublicKey()).
+		AddInstruction(NewCreate2022Instruction(owner.PublicKey(), owner.PublicKey(), mint).Build()).
+		AddSigner(NewPrivateKeySigner(other)).
+		BuildAndSign(context.Background())
+	if err == nil {
+		t.Errorf("Expected missing signature error")
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package token2022
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Constants of the interest calculation of the InterestBearingConfig
+// extension.
+const (
+	secondsPerYear   = 60 * 60 * 24 * 365.24
+	oneInBasisPoints = 10_000
+)
+
+// AmountToUiAmount converts a raw amount to the decimal amount wallets
+// display at unixTimestamp, like the AmountToUiAmount instruction: mints
+// with the InterestBearingConfig or ScaledUiAmount extension apply their
+// interest or multiplier, other mints only shift by their decimals.
+func (m *Mint) AmountToUiAmount(amount uint64, unixTimestamp int64) (string, error) {
+	interest, ok, err := m.InterestBearingConfig()
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		scaled := float64(amount) * interest.totalScale(m.Decimals, unixTimestamp)
+		if math.IsInf(scaled, 0) {
+			return strconv.FormatUint(math.MaxUint64, 10), nil
+		}
+		return trimUiAmount(strconv.FormatFloat(scaled, 'f', int(m.Decimals), 64)), nil
+	}
+	scaled, ok, err := m.ScaledUiAmountConfig()
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		// The scaled amount is truncated to whole base units.
+		raw := math.Floor(float64(amount) * scaled.multiplier(unixTimestamp))
+		if raw >= math.MaxUint64 {
+			return FormatAmount(math.MaxUint64, m.Decimals), nil
+		}
+		return FormatAmount(uint64(raw), m.Decimals), nil
+	}
+	return FormatAmount(amount, m.Decimals), nil
+}
+
+// UiAmountToAmount converts a displayed decimal amount back to a raw
+// amount at unixTimestamp, like the UiAmountToAmount instruction. Amounts
+// of mints without interest or scaling are parsed exactly and may not
+// have more decimal places than the mint.
+func (m *Mint) UiAmountToAmount(uiAmount string, unixTimestamp int64) (uint64, error) {
+	interest, ok, err := m.InterestBearingConfig()
+	if err != nil {
+		return 0, err
+	}
+	if ok {
+		return unscaleUiAmount(uiAmount, interest.totalScale(m.Decimals, unixTimestamp))
+	}
+	scaled, ok, err := m.ScaledUiAmountConfig()
+	if err != nil {
+		return 0, err
+	}
+	if ok {
+		return unscaleUiAmount(uiAmount, scaled.multiplier(unixTimestamp)/math.Pow10(int(m.Decimals)))
+	}
+	return ParseAmount(uiAmount, m.Decimals)
+}
+
+// totalScale is the factor from raw amount to UI amount at unixTimestamp.
+func (c *InterestBearingConfig) totalScale(decimals uint8, unixTimestamp int64) float64 {
+	exp := func(rate int16, timespan int64) float64 {
+		return math.Exp(float64(rate) * float64(timespan) / secondsPerYear / oneInBasisPoints)
+	}
+	return exp(c.PreUpdateAverageRate, c.LastUpdateTimestamp-c.InitializationTimestamp) *
+		exp(c.CurrentRate, unixTimestamp-c.LastUpdateTimestamp) /
+		math.Pow10(int(decimals))
+}
+
+// multiplier returns the multiplier in effect at unixTimestamp.
+func (c *ScaledUiAmountConfig) multiplier(unixTimestamp int64) float64 {
+	if unixTimestamp >= c.NewMultiplierEffectiveTimestamp {
+		return c.NewMultiplier
+	}
+	return c.Multiplier
+}
+
+// unscaleUiAmount divides a UI amount by scale and rounds it to a raw
+// amount.
+func unscaleUiAmount(uiAmount string, scale float64) (uint64, error) {
+	value, err := strconv.ParseFloat(uiAmount, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", uiAmount)
+	}
+	amount := math.Round(value / scale)
+	if math.IsNaN(amount) || amount < 0 || amount >= math.MaxUint64 {
+		return 0, fmt.Errorf("amount %q out of range", uiAmount)
+	}
+	return uint64(amount), nil
+}
+
+// trimUiAmount removes trailing zeros and a trailing decimal point.
+func trimUiAmount(s string) string {
+	if !strings.Contains(s, ".") {
+		return s
+	}
+	return strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+}
@@ -0,0 +1,83 @@
+package token2022
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func interestBearingMint(decimals uint8, rate int16, initialized, lastUpdate int64) *Mint {
+	data := make([]byte, interestBearingConfigSize)
+	binary.LittleEndian.PutUint64(data[32:40], uint64(initialized))
+	binary.LittleEndian.PutUint16(data[40:42], uint16(rate))
+	binary.LittleEndian.PutUint64(data[42:50], uint64(lastUpdate))
+	binary.LittleEndian.PutUint16(data[50:52], uint16(rate))
+	return &Mint{Decimals: decimals, Extensions: []Extension{{Type: ExtensionInterestBearingConfig, Data: data}}}
+}
+
+func scaledUiAmountMint(decimals uint8, multiplier float64, effective int64, newMultiplier float64) *Mint {
+	data := make([]byte, scaledUiAmountConfigSize)
+	binary.LittleEndian.PutUint64(data[32:40], math.Float64bits(multiplier))
+	binary.LittleEndian.PutUint64(data[40:48], uint64(effective))
+	binary.LittleEndian.PutUint64(data[48:56], math.Float64bits(newMultiplier))
+	return &Mint{Decimals: decimals, Extensions: []Extension{{Type: ExtensionScaledUiAmount, Data: data}}}
+}
+
+func TestAmountToUiAmount(t *testing.T) {
+	year := int64(secondsPerYear)
+	tests := []struct {
+		name   string
+		mint   *Mint
+		amount uint64
+		at     int64
+		want   string
+	}{
+		{name: "plain", mint: &Mint{Decimals: 6}, amount: 1_500_000, want: "1.5"},
+		{name: "no decimals", mint: &Mint{}, amount: 42, want: "42"},
+		// 5% a year for a year is a factor of e^0.05.
+		{name: "interest", mint: interestBearingMint(2, 500, 0, 0), amount: 10_000, at: year, want: "105.13"},
+		{name: "interest before start", mint: interestBearingMint(2, 500, 0, 0), amount: 10_000, want: "100"},
+		{name: "scaled", mint: scaledUiAmountMint(2, 1.5, 1000, 3), amount: 101, at: 999, want: "1.51"},
+		{name: "scaled new multiplier", mint: scaledUiAmountMint(2, 1.5, 1000, 3), amount: 101, at: 1000, want: "3.03"},
+	}
+	for _, tt := range tests {
+		got, err := tt.mint.AmountToUiAmount(tt.amount, tt.at)
+		if err != nil {
+			t.Errorf("%s: AmountToUiAmount: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestUiAmountToAmount(t *testing.T) {
+	year := int64(secondsPerYear)
+	tests := []struct {
+		name    string
+		mint    *Mint
+		ui      string
+		at      int64
+		want    uint64
+		wantErr bool
+	}{
+		{name: "plain", mint: &Mint{Decimals: 6}, ui: "1.5", want: 1_500_000},
+		{name: "too many decimals", mint: &Mint{Decimals: 2}, ui: "1.005", wantErr: true},
+		{name: "trailing point", mint: &Mint{Decimals: 2}, ui: "1.", wantErr: true},
+		{name: "overflow", mint: &Mint{}, ui: "18446744073709551616", wantErr: true},
+		{name: "interest", mint: interestBearingMint(2, 500, 0, 0), ui: "105.13", at: year, want: 10_000},
+		{name: "scaled", mint: scaledUiAmountMint(2, 1, 0, 3), ui: "3.03", want: 101},
+		{name: "negative", mint: scaledUiAmountMint(2, 1, 0, 3), ui: "-1", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := tt.mint.UiAmountToAmount(tt.ui, tt.at)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+// Copyright 2025 github.com/dwnfan
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhook receives transaction webhooks, such as those of
+// Helius, and emits the decoded Token-2022 instructions of each
+// transaction. Both enhanced payloads and raw payloads, which carry the
+// transaction as returned by getTransaction, are accepted.
+package webhook
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/dwmfan/token2022"
+	solana "github.com/gagliardetto/solana-go"
+	rpc "github.com/gagliardetto/solana-go/rpc"
+)
+
+// defaultMaxBodySize bounds the payloads read by a Handler.
+const defaultMaxBodySize = 10 << 20
+
+// TransactionEvent is a transaction delivered by a webhook.
+type TransactionEvent struct {
+	Signature solana.Signature
+	Slot      uint64
+	BlockTime *solana.UnixTimeSeconds
+	FeePayer  solana.PublicKey
+	Fee       uint64
+	// Type, Source and Description are the classification of enhanced
+	// payloads, such as "TRANSFER"; they are empty for raw payloads.
+	Type        string
+	Source      string
+	Description string
+	// Transaction and Meta are set for raw payloads only. Enhanced
+	// payloads omit account flags, so their parsed instructions only
+	// carry account public keys.
+	Transaction *solana.Transaction
+	Meta        *rpc.TransactionMeta
+	// Parsed holds the decoded Token-2022 and Associated Token Account
+	// instructions, including inner ones.
+	Parsed *token2022.ParsedTransaction
+}
+
+// Failed reports whether the transaction failed.
+func (e *TransactionEvent) Failed() bool {
+	return e.Parsed.Err != nil
+}
+
+// Handler is an http.Handler for webhook deliveries. A delivery is
+// answered with 200 once every callback returned nil; a callback error is
+// answered with 500 so that the provider retries the delivery, which
+// means callbacks must tolerate seeing a transaction twice.
+type Handler struct {
+	authorization string
+	maxBodySize   int64
+	skipFailed    bool
+	skipOthers    bool
+	onTransaction func(ctx context.Context, event *TransactionEvent) error
+	onInstruction func(ctx context.Context, event *TransactionEvent, inst *token2022.ParsedInstruction) error
+}
+
+// NewHandler creates a handler without callbacks.
+func NewHandler() *Handler {
+	return &Handler{maxBodySize: defaultMaxBodySize, skipOthers: true}
+}
+
+// SetAuthorization requires deliveries to carry this exact Authorization
+// header, the auth header configured on the webhook.
+func (h *Handler) SetAuthorization(value string) *Handler {
+	h.authorization = value
+	return h
+}
+
+// SetMaxBodySize bounds the size of a delivery. The default is 10 MiB.
+func (h *Handler) SetMaxBodySize(size int64) *Handler {
+	h.maxBodySize = size
+	return h
+}
+
+// SetSkipFailed drops failed transactions before any callback.
+func (h *Handler) SetSkipFailed(skip bool) *Handler {
+	h.skipFailed = skip
+	return h
+}
+
+// SetSkipOthers controls whether transactions without Token-2022 or
+// Associated Token Account instructions are dropped, which is the
+// default.
+func (h *Handler) SetSkipOthers(skip bool) *Handler {
+	h.skipOthers = skip
+	return h
+}
+
+// OnTransaction sets the callback invoked for every transaction.
+func (h *Handler) OnTransaction(fn func(ctx context.Context, event *TransactionEvent) error) *Handler {
+	h.onTransaction = fn
+	return h
+}
+
+// OnInstruction sets the callback invoked for every parsed instruction,
+// in execution order, after the transaction callback.
+func (h *Handler) OnInstruction(fn func(ctx context.Context, event *TransactionEvent, inst *token2022.ParsedInstruction) error) *Handler {
+	h.onInstruction = fn
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.authorization != "" &&
+		subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte(h.authorization)) != 1 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var payload []json.RawMessage
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, h.maxBodySize)).Decode(&payload); err != nil {
+		http.Error(w, fmt.Sprintf("invalid payload: %v", err), http.StatusBadRequest)
+		return
+	}
+	events, err := DecodePayload(payload)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.Dispatch(r.Context(), events); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// Dispatch invokes the callbacks for events, stopping at the first error.
+// It is called by ServeHTTP and lets deliveries received another way,
+// such as from a queue, go through the same callbacks.
+func (h *Handler) Dispatch(ctx context.Context, events []*TransactionEvent) error {
+	for _, event := range events {
+		if h.skipFailed && event.Failed() {
+			continue
+		}
+		if h.skipOthers && len(event.Parsed.Instructions) == 0 {
+			continue
+		}
+		if h.onTransaction != nil {
+			if err := h.onTransaction(ctx, event); err != nil {
+				return fmt.Errorf("transaction %s: %w", event.Signature, err)
+			}
+		}
+		if h.onInstruction == nil {
+			continue
+		}
+		for _, inst := range event.Parsed.Instructions {
+			if err := h.onInstruction(ctx, event, inst); err != nil {
+				return fmt.Errorf("transaction %s: %w", event.Signature, err)
+			}
+		}
+	}
+	return nil
+}
+
+// DecodePayload decodes the transactions of a delivery, which is a JSON
+// array of enhanced or raw transactions.
+func DecodePayload(payload []json.RawMessage) ([]*TransactionEvent, error) {
+	events := make([]*TransactionEvent, 0, len(payload))
+	for i, item := range payload {
+		var probe struct {
+			Transaction json.RawMessage `json:"transaction"`
+		}
+		if err := json.Unmarshal(item, &probe); err != nil {
+			return nil, fmt.Errorf("transaction %d: %w", i, err)
+		}
+		var (
+			event *TransactionEvent
+			err   error
+		)
+		if len(probe.Transaction) > 0 {
+			event, err = decodeRaw(item)
+		} else {
+			event, err = decodeEnhanced(item)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("transaction %d: %w", i, err)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func decodeRaw(data []byte) (*TransactionEvent, error) {
+	var result rpc.GetTransactionResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	if result.Transaction == nil {
+		return nil, errors.New("transaction not set")
+	}
+	tx, err := result.Transaction.GetTransaction()
+	if err != nil {
+		return nil, fmt.Errorf("error while decoding transaction: %w", err)
+	}
+	parsed, err := token2022.ParseTransaction(tx, result.Meta)
+	if err != nil {
+		return nil, fmt.Errorf("error while parsing transaction: %w", err)
+	}
+	event := &TransactionEvent{
+		Signature:   parsed.Signature,
+		Slot:        result.Slot,
+		BlockTime:   result.BlockTime,
+		Transaction: tx,
+		Meta:        result.Meta,
+		Parsed:      parsed,
+	}
+	if len(tx.Message.AccountKeys) > 0 {
+		event.FeePayer = tx.Message.AccountKeys[0]
+	}
+	if result.Meta != nil {
+		event.Fee = result.Meta.Fee
+	}
+	return event, nil
+}
+
+// enhancedTransaction is the part of an enhanced transaction that is
+// decoded; token transfers and balance changes are derived from the
+// instructions instead.
+type enhancedTransaction struct {
+	Signature        solana.Signature        `json:"signature"`
+	Slot             uint64                  `json:"slot"`
+	Timestamp        *solana.UnixTimeSeconds `json:"timestamp"`
+	Type             string                  `json:"type"`
+	Source           string                  `json:"source"`
+	Description      string                  `json:"description"`
+	Fee              uint64                  `json:"fee"`
+	FeePayer         solana.PublicKey        `json:"feePayer"`
+	TransactionError interface{}             `json:"transactionError"`
+	Instructions     []enhancedInstruction   `json:"instructions"`
+}
+
+type enhancedInstruction struct {
+	ProgramID         solana.PublicKey      `json:"programId"`
+	Accounts          []solana.PublicKey    `json:"accounts"`
+	Data              solana.Base58         `json:"data"`
+	InnerInstructions []enhancedInstruction `json:"innerInstructions"`
+}
+
+func decodeEnhanced(data []byte) (*TransactionEvent, error) {
+	var tx enhancedTransaction
+	if err := json.Unmarshal(data, &tx); err != nil {
+		return nil, err
+	}
+	parsed := &token2022.ParsedTransaction{Signature: tx.Signature}
+	if tx.TransactionError != nil {
+		parsed.Err = token2022.DecodeTransactionError(tx.Signature, tx.TransactionError)
+	}
+	for i, inst := range tx.Instructions {
+		if p := parseEnhanced(inst, i, -1); p != nil {
+			parsed.Instructions = append(parsed.Instructions, p)
+		}
+		for j, inner := range inst.InnerInstructions {
+			if p := parseEnhanced(inner, i, j); p != nil {
+				parsed.Instructions = append(parsed.Instructions, p)
+			}
+		}
+	}
+	return &TransactionEvent{
+		Signature:   tx.Signature,
+		Slot:        tx.Slot,
+		BlockTime:   tx.Timestamp,
+		FeePayer:    tx.FeePayer,
+		Fee:         tx.Fee,
+		Type:        tx.Type,
+		Source:      tx.Source,
+		Description: tx.Description,
+		Parsed:      parsed,
+	}, nil
+}
+
+// parseEnhanced decodes inst like token2022.ParseTransaction, or returns
+// nil when it does not belong to the Token-2022 or Associated Token
+// Account program.
+func parseEnhanced(inst enhancedInstruction, index, innerIndex int) *token2022.ParsedInstruction {
+	isToken := inst.ProgramID.Equals(solana.Token2022ProgramID)
+	if !isToken && !inst.ProgramID.Equals(solana.SPLAssociatedTokenAccountProgramID) {
+		return nil
+	}
+	accounts := make([]*solana.AccountMeta, len(inst.Accounts))
+	for i, account := range inst.Accounts {
+		accounts[i] = &solana.AccountMeta{PublicKey: account}
+	}
+	parsed := &token2022.ParsedInstruction{
+		Index:      index,
+		InnerIndex: innerIndex,
+		ProgramID:  inst.ProgramID,
+		Accounts:   accounts,
+		Data:       inst.Data,
+	}
+	if isToken {
+		parsed.Name = token2022.InstructionName(inst.Data)
+		parsed.Instruction, parsed.DecodeErr = token2022.DecodeInstruction(accounts, inst.Data)
+	} else {
+		parsed.Name = token2022.AssociatedTokenInstructionName(inst.Data)
+		parsed.Instruction, parsed.DecodeErr = token2022.DecodeAssociatedTokenInstruction(accounts, inst.Data)
+	}
+	return parsed
+}
@@ -0,0 +1,199 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dwmfan/token2022"
+	solana "github.com/gagliardetto/solana-go"
+)
+
+var (
+	wallet      = solana.MustPublicKeyFromBase58("nrw1b6stoyvm3QPsh78iWoJwsjM1b7KfcvxYT3LbFun")
+	mint        = solana.MustPublicKeyFromBase58("D8zFabAK4Jt2Wi1TZJvMnr6EeD9K4qpiGhya1NQpyrZn")
+	source      = solana.MustPublicKeyFromBase58("GHtXQBsoZHVnNFa9YevAzFr17DJjgHXk3ycTKD5xD3Zi")
+	destination = solana.MustPublicKeyFromBase58("9xQeWvG816bUx9EPjHmaT23yvVM2ZWbrrpZb9PusVFin")
+)
+
+func transferChecked() solana.Instruction {
+	return token2022.NewTransferChecked2022Instruction(1_000, 6, source, mint, destination, wallet).Build()
+}
+
+func enhancedPayload(t *testing.T, sig solana.Signature, txErr string) string {
+	inst := transferChecked()
+	data, err := inst.Data()
+	if err != nil {
+		t.Fatalf("Data: %v", err)
+	}
+	var accounts []string
+	for _, account := range inst.Accounts() {
+		accounts = append(accounts, account.PublicKey.String())
+	}
+	tx := map[string]interface{}{
+		"signature":   sig.String(),
+		"slot":        42,
+		"timestamp":   1700000000,
+		"type":        "TRANSFER",
+		"source":      "SOLANA_PROGRAM_LIBRARY",
+		"description": "transfer",
+		"fee":         5000,
+		"feePayer":    wallet.String(),
+		"instructions": []interface{}{
+			map[string]interface{}{
+				"programId": solana.ComputeBudget.String(),
+				"accounts":  []string{},
+				"data":      "3DTZbgwsozUF",
+			},
+			map[string]interface{}{
+				"programId":         solana.Token2022ProgramID.String(),
+				"accounts":          accounts,
+				"data":              solana.Base58(data).String(),
+				"innerInstructions": []interface{}{},
+			},
+		},
+		"tokenTransfers": []interface{}{},
+	}
+	if txErr != "" {
+		tx["transactionError"] = json.RawMessage(txErr)
+	}
+	payload, err := json.Marshal([]interface{}{tx})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	return string(payload)
+}
+
+func rawPayload(t *testing.T, sig solana.Signature) string {
+	tx, err := solana.NewTransaction([]solana.Instruction{transferChecked()}, solana.Hash{1}, solana.TransactionPayer(wallet))
+	if err != nil {
+		t.Fatalf("NewTransaction: %v", err)
+	}
+	tx.Signatures = []solana.Signature{sig}
+	encoded, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	zeros := strings.TrimSuffix(strings.Repeat("0,", len(tx.Message.AccountKeys)), ",")
+	return `[{"slot":43,"blockTime":1700000001,"meta":{"err":null,"fee":5000,"preBalances":[` + zeros +
+		`],"postBalances":[` + zeros + `],"innerInstructions":[]},"transaction":` + string(encoded) + `}]`
+}
+
+func post(handler http.Handler, body, authorization string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandler(t *testing.T) {
+	var (
+		events       []*TransactionEvent
+		instructions []*token2022.ParsedInstruction
+	)
+	handler := NewHandler().
+		SetAuthorization("secret").
+		OnTransaction(func(ctx context.Context, event *TransactionEvent) error {
+			events = append(events, event)
+			return nil
+		}).
+		OnInstruction(func(ctx context.Context, event *TransactionEvent, inst *token2022.ParsedInstruction) error {
+			instructions = append(instructions, inst)
+			return nil
+		})
+
+	for _, tt := range []struct {
+		name    string
+		payload string
+		slot    uint64
+		raw     bool
+		index   int
+	}{
+		// The enhanced payload starts with a compute budget instruction.
+		{name: "enhanced", payload: enhancedPayload(t, solana.Signature{1}, ""), slot: 42, index: 1},
+		{name: "raw", payload: rawPayload(t, solana.Signature{2}), slot: 43, raw: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			events, instructions = nil, nil
+			if rec := post(handler, tt.payload, "secret"); rec.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body)
+			}
+			if len(events) != 1 {
+				t.Fatalf("Expected 1 transaction, got %d", len(events))
+			}
+			event := events[0]
+			if event.Slot != tt.slot || event.FeePayer != wallet || event.Fee != 5000 || event.BlockTime == nil {
+				t.Errorf("Unexpected event %+v", event)
+			}
+			if (event.Transaction != nil) != tt.raw {
+				t.Errorf("Expected transaction set %v, got %v", tt.raw, event.Transaction != nil)
+			}
+			if len(instructions) != 1 {
+				t.Fatalf("Expected 1 instruction, got %d", len(instructions))
+			}
+			transfer, ok := instructions[0].Instruction.(*token2022.TransferChecked2022)
+			if !ok {
+				t.Fatalf("Expected *TransferChecked2022, got %T (%v)", instructions[0].Instruction, instructions[0].DecodeErr)
+			}
+			if transfer.Amount != 1_000 || transfer.Destination != destination {
+				t.Errorf("Unexpected transfer %+v", transfer)
+			}
+			if instructions[0].Index != tt.index {
+				t.Errorf("Expected instruction index %d, got %d", tt.index, instructions[0].Index)
+			}
+		})
+	}
+}
+
+func TestHandlerErrors(t *testing.T) {
+	handler := NewHandler().SetAuthorization("secret").OnTransaction(func(ctx context.Context, event *TransactionEvent) error {
+		return errors.New("database unavailable")
+	})
+	payload := enhancedPayload(t, solana.Signature{1}, "")
+
+	if rec := post(handler, payload, "wrong"); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", rec.Code)
+	}
+	if rec := post(handler, `{"signature":1}`, "secret"); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", rec.Code)
+	}
+	// Callback errors ask the provider to retry.
+	if rec := post(handler, payload, "secret"); rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", rec.Code)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/webhook", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", rec.Code)
+	}
+}
+
+func TestHandlerSkipFailed(t *testing.T) {
+	var count int
+	handler := NewHandler().SetSkipFailed(true).OnTransaction(func(ctx context.Context, event *TransactionEvent) error {
+		count++
+		return nil
+	})
+	payload := enhancedPayload(t, solana.Signature{1}, `{"InstructionError":[1,{"Custom":1}]}`)
+	if rec := post(handler, payload, ""); rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	if count != 0 {
+		t.Errorf("Expected failed transaction to be skipped, got %d callbacks", count)
+	}
+
+	events, err := DecodePayload([]json.RawMessage{json.RawMessage(strings.Trim(payload, "[]"))})
+	if err != nil {
+		t.Fatalf("DecodePayload: %v", err)
+	}
+	if !events[0].Failed() || events[0].Parsed.Err.Custom == nil || *events[0].Parsed.Err.Custom != 1 {
+		t.Errorf("Unexpected error %+v", events[0].Parsed.Err)
+	}
+}